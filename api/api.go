@@ -29,14 +29,17 @@ import (
 	"google.golang.org/grpc/reflection"
 	grpc_status "google.golang.org/grpc/status"
 
+	"github.com/featureform/auth"
 	"github.com/featureform/fferr"
 	"github.com/featureform/health"
 	"github.com/featureform/helpers"
+	"github.com/featureform/helpers/interceptors"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
 	pb "github.com/featureform/metadata/proto"
 	srv "github.com/featureform/proto"
 	"github.com/featureform/provider"
+	pc "github.com/featureform/provider/provider_config"
 	pt "github.com/featureform/provider/provider_type"
 )
 
@@ -541,6 +544,7 @@ func (serv *MetadataServer) GetProviders(stream pb.Api_GetProvidersServer) error
 			loggerWithResource.Errorw("Failed to receive providers from server", "error", err)
 			return err
 		}
+		res.SerializedConfig = pc.RedactSerializedConfig(res.SerializedConfig)
 		sendErr := stream.Send(res)
 		if sendErr != nil {
 			loggerWithResource.Errorw("Failed to send providers to client", "error", sendErr)
@@ -899,6 +903,7 @@ func (serv *MetadataServer) ListProviders(listRequest *pb.ListRequest, stream pb
 		}
 		loggerWithResource := logger.WithResource(logging.Provider, res.Name, logging.NoVariant).WithProvider(res.Type, res.Name)
 		loggerWithResource.Infow("Sending resource on stream")
+		res.SerializedConfig = pc.RedactSerializedConfig(res.SerializedConfig)
 
 		sendErr := stream.Send(res)
 		if sendErr != nil {
@@ -1426,6 +1431,8 @@ func (serv *ApiServer) Serve() error {
 		// grpc.WithUnaryInterceptor(fferr.UnaryClientInterceptor()),
 		// grpc.WithStreamInterceptor(fferr.StreamClientInterceptor()),
 	}
+	opts = append(opts, auth.ServiceDialOptions()...)
+	opts = append(opts, interceptors.TracingDialOptions()...)
 	metaConn, err := grpc.Dial(serv.metadata.address, opts...)
 	if err != nil {
 		logger.Errorw("Failed to dial metadata server", "error", err)
@@ -1505,20 +1512,41 @@ func (serv *ApiServer) ServeOnListener(lis net.Listener) error {
 		Timeout: time.Duration(timeout) * time.Minute, // time after which the connection is closed if no activity
 	}
 
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		interceptors.StreamServerMetricsInterceptor,
+		grpc_logrus.StreamServerInterceptor(logrusEntry, lorgusOpts...),
+	}
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		interceptors.UnaryServerMetricsInterceptor,
+		grpc_logrus.UnaryServerInterceptor(logrusEntry, lorgusOpts...),
+	}
+	authConfig := auth.ConfigFromEnv()
+	authOpts, err := authConfig.ServerOptions()
+	if err != nil {
+		return err
+	}
+	guard, err := auth.NewGuardFromEnv(serv.Logger, metadata.NewAuthResolver(serv.metadata.client))
+	if err != nil {
+		return err
+	}
+	if guard != nil {
+		serv.Logger.Infow("Authentication enabled")
+		streamInterceptors = append(streamInterceptors, guard.StreamServerInterceptor)
+		unaryInterceptors = append(unaryInterceptors, guard.UnaryServerInterceptor)
+	}
+
 	opt := []grpc.ServerOption{
 		grpc.StreamInterceptor(
-			grpc_middleware.ChainStreamServer(
-				grpc_logrus.StreamServerInterceptor(logrusEntry, lorgusOpts...),
-			),
+			grpc_middleware.ChainStreamServer(streamInterceptors...),
 		),
 		grpc.UnaryInterceptor(
-			grpc_middleware.ChainUnaryServer(
-				grpc_logrus.UnaryServerInterceptor(logrusEntry, lorgusOpts...),
-			),
+			grpc_middleware.ChainUnaryServer(unaryInterceptors...),
 		),
 		grpc.KeepaliveEnforcementPolicy(kaep),
 		grpc.KeepaliveParams(kasp),
 	}
+	opt = append(opt, authOpts...)
+	opt = append(opt, interceptors.TracingServerOptions()...)
 	grpcServer := grpc.NewServer(opt...)
 
 	healthServer := grpc_health.NewServer()
@@ -8,6 +8,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/joho/godotenv"
@@ -16,6 +17,8 @@ import (
 	"github.com/featureform/health"
 	help "github.com/featureform/helpers"
 	"github.com/featureform/logging"
+	"github.com/featureform/metrics"
+	"github.com/featureform/tracing"
 )
 
 func main() {
@@ -25,6 +28,18 @@ func main() {
 	}
 
 	logger := logging.NewLogger("api")
+	shutdownTracing, err := tracing.Init(context.Background(), "api")
+	if err != nil {
+		logger.Panicw("Failed to configure tracing", "Err", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Errorw("Failed to flush traces", "Err", err)
+		}
+	}()
+	if err := metrics.EnableInstrumentationFromEnv(); err != nil {
+		logger.Panicw("Failed to register metrics", "Err", err)
+	}
 	apiPort := help.GetEnv("API_PORT", "7878")
 	logger.Infow("Retrieved API port from ENV", "port", apiPort)
 	apiStatusPort := help.GetEnv("API_STATUS_PORT", "8443")
@@ -46,7 +61,11 @@ func main() {
 		servingConn = ""
 	}
 
-	if err := health.StartHttpServer(logger, apiStatusPort); err != nil {
+	checks := []health.Check{health.GRPCPeerCheck("metadata", metadataConn)}
+	if !skipFeatureServing {
+		checks = append(checks, health.GRPCPeerCheck("serving", servingConn))
+	}
+	if err := health.StartHttpServer(logger, apiStatusPort, checks...); err != nil {
 		logger.Errorw("Error starting health check HTTP server", "error", err)
 		panic(fmt.Sprintf("health check HTTP server failed: %+v", err))
 	}
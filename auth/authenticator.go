@@ -0,0 +1,183 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/featureform/fferr"
+)
+
+const (
+	AuthorizationHeader = "authorization"
+	bearerPrefix        = "bearer "
+)
+
+// errNoCredentials is returned by an Authenticator when the request carries
+// no credentials of the kind it understands, so the next one can be tried.
+var errNoCredentials = errors.New("no credentials")
+
+// Authenticator resolves the caller of an incoming gRPC request.
+type Authenticator interface {
+	Authenticate(ctx context.Context) (*Principal, error)
+}
+
+// NewChainAuthenticator tries each authenticator in order and returns the
+// first principal found. If none match, the first real failure is returned.
+func NewChainAuthenticator(authenticators ...Authenticator) Authenticator {
+	return chainAuthenticator(authenticators)
+}
+
+type chainAuthenticator []Authenticator
+
+func (chain chainAuthenticator) Authenticate(ctx context.Context) (*Principal, error) {
+	var firstErr error
+	for _, authenticator := range chain {
+		principal, err := authenticator.Authenticate(ctx)
+		if err == nil {
+			return principal, nil
+		}
+		if !errors.Is(err, errNoCredentials) && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return nil, fferr.NewUnauthenticatedErrorf("missing credentials")
+}
+
+// bearerToken extracts the token from the "authorization: Bearer <token>" header.
+func bearerToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", errNoCredentials
+	}
+	for _, value := range md.Get(AuthorizationHeader) {
+		if len(value) > len(bearerPrefix) && strings.EqualFold(value[:len(bearerPrefix)], bearerPrefix) {
+			return strings.TrimSpace(value[len(bearerPrefix):]), nil
+		}
+	}
+	return "", errNoCredentials
+}
+
+// StaticToken is a single API token entry. Either Token or TokenSHA256 (the
+// hex encoded SHA-256 of the token) must be set; the hash form keeps
+// plaintext tokens out of the config file.
+type StaticToken struct {
+	Subject     string   `json:"subject"`
+	Token       string   `json:"token,omitempty"`
+	TokenSHA256 string   `json:"token_sha256,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+}
+
+type staticTokenFile struct {
+	Tokens []StaticToken `json:"tokens"`
+}
+
+type staticTokenAuthenticator struct {
+	tokens map[[sha256.Size]byte]StaticToken
+}
+
+// NewStaticTokenAuthenticator authenticates bearer tokens against a fixed set of API tokens.
+func NewStaticTokenAuthenticator(tokens []StaticToken) (Authenticator, error) {
+	auth := &staticTokenAuthenticator{tokens: make(map[[sha256.Size]byte]StaticToken, len(tokens))}
+	for _, token := range tokens {
+		if token.Subject == "" {
+			return nil, fferr.NewInvalidConfigf("static token is missing a subject")
+		}
+		var hash [sha256.Size]byte
+		switch {
+		case token.TokenSHA256 != "":
+			decoded, err := hex.DecodeString(token.TokenSHA256)
+			if err != nil || len(decoded) != sha256.Size {
+				return nil, fferr.NewInvalidConfigf("static token for %s has an invalid token_sha256", token.Subject)
+			}
+			copy(hash[:], decoded)
+		case token.Token != "":
+			hash = sha256.Sum256([]byte(token.Token))
+		default:
+			return nil, fferr.NewInvalidConfigf("static token for %s has neither token nor token_sha256", token.Subject)
+		}
+		token.Token = ""
+		auth.tokens[hash] = token
+	}
+	return auth, nil
+}
+
+// NewStaticTokenAuthenticatorFromFile loads tokens from a JSON file of the form
+// {"tokens": [{"subject": "...", "token_sha256": "...", "roles": ["..."]}]}.
+func NewStaticTokenAuthenticatorFromFile(path string) (Authenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fferr.NewInvalidConfigf("could not read static token file %s: %v", path, err)
+	}
+	var file staticTokenFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fferr.NewInvalidConfigf("could not parse static token file %s: %v", path, err)
+	}
+	return NewStaticTokenAuthenticator(file.Tokens)
+}
+
+func (auth *staticTokenAuthenticator) Authenticate(ctx context.Context) (*Principal, error) {
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256([]byte(token))
+	for known, entry := range auth.tokens {
+		if subtle.ConstantTimeCompare(known[:], hash[:]) == 1 {
+			return &Principal{Subject: entry.Subject, Roles: entry.Roles, Method: StaticTokenMethod}, nil
+		}
+	}
+	return nil, fferr.NewUnauthenticatedErrorf("invalid api token")
+}
+
+type mtlsAuthenticator struct{}
+
+// NewMTLSAuthenticator identifies callers by a client certificate that was
+// verified during the TLS handshake. The subject is the certificate's common
+// name, falling back to its first URI or DNS SAN, and the roles are its
+// organizational units.
+func NewMTLSAuthenticator() Authenticator {
+	return mtlsAuthenticator{}
+}
+
+func (mtlsAuthenticator) Authenticate(ctx context.Context) (*Principal, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return nil, errNoCredentials
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, errNoCredentials
+	}
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	subject := cert.Subject.CommonName
+	if subject == "" && len(cert.URIs) > 0 {
+		subject = cert.URIs[0].String()
+	}
+	if subject == "" && len(cert.DNSNames) > 0 {
+		subject = cert.DNSNames[0]
+	}
+	if subject == "" {
+		return nil, fferr.NewUnauthenticatedErrorf("client certificate has no usable subject")
+	}
+	return &Principal{Subject: subject, Roles: cert.Subject.OrganizationalUnit, Method: MTLSMethod}, nil
+}
@@ -0,0 +1,205 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/featureform/fferr"
+)
+
+func bearerContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationHeader, "Bearer "+token))
+}
+
+func isUnauthenticated(err error) bool {
+	var unauthenticated *fferr.UnauthenticatedError
+	return errors.As(err, &unauthenticated)
+}
+
+func TestStaticTokenAuthenticator(t *testing.T) {
+	hash := sha256.Sum256([]byte("hashed-token"))
+	auth, err := NewStaticTokenAuthenticator([]StaticToken{
+		{Subject: "alice", Token: "plain-token", Roles: []string{"admin"}},
+		{Subject: "bob", TokenSHA256: hex.EncodeToString(hash[:])},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create authenticator: %v", err)
+	}
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		subject string
+		noCreds bool
+	}{
+		{"Plain token", bearerContext("plain-token"), "alice", false},
+		{"Hashed token", bearerContext("hashed-token"), "bob", false},
+		{"Unknown token", bearerContext("other"), "", false},
+		{"No header", context.Background(), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := auth.Authenticate(tt.ctx)
+			if tt.noCreds {
+				if !errors.Is(err, errNoCredentials) {
+					t.Fatalf("Expected no credentials, got %v", err)
+				}
+				return
+			}
+			if tt.subject == "" {
+				if !isUnauthenticated(err) {
+					t.Fatalf("Expected unauthenticated error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to authenticate: %v", err)
+			}
+			if principal.Subject != tt.subject || principal.Method != StaticTokenMethod {
+				t.Fatalf("Unexpected principal %+v", principal)
+			}
+		})
+	}
+}
+
+func TestStaticTokenAuthenticatorInvalidConfig(t *testing.T) {
+	invalid := [][]StaticToken{
+		{{Token: "no-subject"}},
+		{{Subject: "alice"}},
+		{{Subject: "alice", TokenSHA256: "not-hex"}},
+	}
+	for _, tokens := range invalid {
+		if _, err := NewStaticTokenAuthenticator(tokens); err == nil {
+			t.Fatalf("Expected error for %+v", tokens)
+		}
+	}
+}
+
+func writeJWKS(t *testing.T, key *rsa.PrivateKey, kid string) string {
+	jwks := jsonWebKeySet{Keys: []jsonWebKey{{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	data, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatalf("Failed to marshal JWKS: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("Failed to write JWKS: %v", err)
+	}
+	return path
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	auth, err := NewJWTAuthenticator(JWTConfig{
+		JWKSPath: writeJWKS(t, key, "key-1"),
+		Issuer:   "https://issuer.example.com",
+		Audience: "featureform",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create authenticator: %v", err)
+	}
+	valid := jwt.MapClaims{
+		"sub":   "alice",
+		"iss":   "https://issuer.example.com",
+		"aud":   "featureform",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"ml-eng", "admin"},
+	}
+	withClaim := func(key string, value interface{}) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		claims[key] = value
+		return claims
+	}
+
+	principal, err := auth.Authenticate(bearerContext(signToken(t, key, "key-1", valid)))
+	if err != nil {
+		t.Fatalf("Failed to authenticate: %v", err)
+	}
+	if principal.Subject != "alice" || !principal.HasRole("ml-eng") || principal.Method != JWTMethod {
+		t.Fatalf("Unexpected principal %+v", principal)
+	}
+
+	rejected := map[string]string{
+		"Expired":        signToken(t, key, "key-1", withClaim("exp", time.Now().Add(-time.Hour).Unix())),
+		"Wrong issuer":   signToken(t, key, "key-1", withClaim("iss", "https://evil.example.com")),
+		"Wrong audience": signToken(t, key, "key-1", withClaim("aud", "other")),
+		"Unknown key":    signToken(t, otherKey, "key-2", valid),
+		"Wrong key":      signToken(t, otherKey, "key-1", valid),
+		"No subject":     signToken(t, key, "key-1", withClaim("sub", "")),
+	}
+	for name, token := range rejected {
+		t.Run(name, func(t *testing.T) {
+			if _, err := auth.Authenticate(bearerContext(token)); !isUnauthenticated(err) {
+				t.Fatalf("Expected unauthenticated error, got %v", err)
+			}
+		})
+	}
+
+	if _, err := auth.Authenticate(bearerContext("opaque-token")); !errors.Is(err, errNoCredentials) {
+		t.Fatalf("Expected opaque tokens to be skipped, got %v", err)
+	}
+}
+
+func TestChainAuthenticator(t *testing.T) {
+	tokens, err := NewStaticTokenAuthenticator([]StaticToken{{Subject: "alice", Token: "token"}})
+	if err != nil {
+		t.Fatalf("Failed to create authenticator: %v", err)
+	}
+	chain := NewChainAuthenticator(NewMTLSAuthenticator(), tokens)
+	principal, err := chain.Authenticate(bearerContext("token"))
+	if err != nil || principal.Subject != "alice" {
+		t.Fatalf("Expected alice, got %+v %v", principal, err)
+	}
+	if _, err := chain.Authenticate(context.Background()); !isUnauthenticated(err) {
+		t.Fatalf("Expected missing credentials to be unauthenticated, got %v", err)
+	}
+	if _, err := chain.Authenticate(bearerContext("wrong")); !isUnauthenticated(err) {
+		t.Fatalf("Expected invalid token to be unauthenticated, got %v", err)
+	}
+}
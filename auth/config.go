@@ -0,0 +1,165 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/helpers"
+)
+
+// Environment variable names for authentication and authorization
+const (
+	EnvTokensFile      = "FEATUREFORM_AUTH_TOKENS_FILE"
+	EnvJWKSFile        = "FEATUREFORM_AUTH_JWKS_FILE"
+	EnvJWTIssuer       = "FEATUREFORM_AUTH_JWT_ISSUER"
+	EnvJWTAudience     = "FEATUREFORM_AUTH_JWT_AUDIENCE"
+	EnvJWTSubjectClaim = "FEATUREFORM_AUTH_JWT_SUBJECT_CLAIM"
+	EnvJWTRolesClaim   = "FEATUREFORM_AUTH_JWT_ROLES_CLAIM"
+	EnvMTLS            = "FEATUREFORM_AUTH_MTLS"
+	EnvTLSCertFile     = "FEATUREFORM_TLS_CERT_FILE"
+	EnvTLSKeyFile      = "FEATUREFORM_TLS_KEY_FILE"
+	EnvTLSClientCAFile = "FEATUREFORM_TLS_CLIENT_CA_FILE"
+	EnvPolicyFile      = "FEATUREFORM_AUTH_POLICY_FILE"
+	EnvServiceToken    = "FEATUREFORM_AUTH_SERVICE_TOKEN"
+)
+
+// Config holds the authentication methods enabled on a server. A zero Config
+// disables authentication, which keeps existing deployments working.
+type Config struct {
+	TokensFile  string
+	JWT         *JWTConfig
+	MTLS        bool
+	TLSCertFile string
+	TLSKeyFile  string
+	ClientCA    string
+	PolicyFile  string
+}
+
+func ConfigFromEnv() Config {
+	config := Config{
+		TokensFile:  helpers.GetEnv(EnvTokensFile, ""),
+		MTLS:        helpers.GetEnvBool(EnvMTLS, false),
+		TLSCertFile: helpers.GetEnv(EnvTLSCertFile, ""),
+		TLSKeyFile:  helpers.GetEnv(EnvTLSKeyFile, ""),
+		ClientCA:    helpers.GetEnv(EnvTLSClientCAFile, ""),
+		PolicyFile:  helpers.GetEnv(EnvPolicyFile, ""),
+	}
+	if jwks := helpers.GetEnv(EnvJWKSFile, ""); jwks != "" {
+		config.JWT = &JWTConfig{
+			JWKSPath:     jwks,
+			Issuer:       helpers.GetEnv(EnvJWTIssuer, ""),
+			Audience:     helpers.GetEnv(EnvJWTAudience, ""),
+			SubjectClaim: helpers.GetEnv(EnvJWTSubjectClaim, ""),
+			RolesClaim:   helpers.GetEnv(EnvJWTRolesClaim, ""),
+		}
+	}
+	return config
+}
+
+func (config Config) Enabled() bool {
+	return config.TokensFile != "" || config.JWT != nil || config.MTLS
+}
+
+// Authenticator builds the chain of configured authenticators. mTLS is tried
+// first since it doesn't depend on request metadata.
+func (config Config) Authenticator() (Authenticator, error) {
+	var authenticators []Authenticator
+	if config.MTLS {
+		authenticators = append(authenticators, NewMTLSAuthenticator())
+	}
+	if config.JWT != nil {
+		jwtAuth, err := NewJWTAuthenticator(*config.JWT)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, jwtAuth)
+	}
+	if config.TokensFile != "" {
+		tokenAuth, err := NewStaticTokenAuthenticatorFromFile(config.TokensFile)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, tokenAuth)
+	}
+	return NewChainAuthenticator(authenticators...), nil
+}
+
+func (config Config) Policy() (Policy, error) {
+	if config.PolicyFile == "" {
+		return DefaultPolicy(), nil
+	}
+	return LoadPolicy(config.PolicyFile)
+}
+
+// ServerOptions returns the transport credentials for the server. TLS is
+// enabled whenever a certificate is configured, and client certificates are
+// required when mTLS authentication is on.
+func (config Config) ServerOptions() ([]grpc.ServerOption, error) {
+	if config.TLSCertFile == "" {
+		if config.MTLS {
+			return nil, fferr.NewInvalidConfigf("mTLS authentication requires %s and %s", EnvTLSCertFile, EnvTLSKeyFile)
+		}
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+	if err != nil {
+		return nil, fferr.NewInvalidConfigf("could not load TLS key pair: %v", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if config.MTLS {
+		if config.ClientCA == "" {
+			return nil, fferr.NewInvalidConfigf("mTLS authentication requires %s", EnvTLSClientCAFile)
+		}
+		pem, err := os.ReadFile(config.ClientCA)
+		if err != nil {
+			return nil, fferr.NewInvalidConfigf("could not read client CA %s: %v", config.ClientCA, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fferr.NewInvalidConfigf("client CA %s contains no certificates", config.ClientCA)
+		}
+		tlsConfig.ClientCAs = pool
+		// Token and JWT callers may still connect without a certificate.
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}, nil
+}
+
+// tokenCredentials attaches a bearer token to every outgoing call.
+type tokenCredentials string
+
+func (token tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{AuthorizationHeader: "Bearer " + string(token)}, nil
+}
+
+// RequireTransportSecurity is false since internal services talk over
+// plaintext connections inside the cluster.
+func (token tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// ServiceDialOptions returns the dial options internal clients use to call
+// other Featureform services when authentication is enabled on them.
+func ServiceDialOptions() []grpc.DialOption {
+	token := helpers.GetEnv(EnvServiceToken, "")
+	if token == "" {
+		return nil
+	}
+	return []grpc.DialOption{grpc.WithPerRPCCredentials(tokenCredentials(token))}
+}
@@ -0,0 +1,292 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/logging"
+	pb "github.com/featureform/metadata/proto"
+	srvpb "github.com/featureform/proto"
+)
+
+// publicMethodPrefixes are never authenticated so that probes and tooling keep working.
+var publicMethodPrefixes = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+// Guard authenticates every call to a gRPC server and authorizes the
+// requests that create, delete or read resources.
+type Guard struct {
+	Authenticator Authenticator
+	Authorizer    Authorizer
+	// Resolver is optional; without it existing resources have no owner or tags.
+	Resolver ResourceResolver
+	Logger   logging.Logger
+}
+
+// NewGuardFromEnv returns nil when no authentication method is configured.
+func NewGuardFromEnv(logger logging.Logger, resolver ResourceResolver) (*Guard, error) {
+	config := ConfigFromEnv()
+	if !config.Enabled() {
+		return nil, nil
+	}
+	authenticator, err := config.Authenticator()
+	if err != nil {
+		return nil, err
+	}
+	policy, err := config.Policy()
+	if err != nil {
+		return nil, err
+	}
+	return &Guard{
+		Authenticator: authenticator,
+		Authorizer:    NewPolicyAuthorizer(policy),
+		Resolver:      resolver,
+		Logger:        logger,
+	}, nil
+}
+
+func isPublicMethod(method string) bool {
+	for _, prefix := range publicMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (guard *Guard) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if isPublicMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	ctx, err := guard.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := guard.authorize(ctx, req); err != nil {
+		return nil, toStatus(err)
+	}
+	return handler(ctx, req)
+}
+
+func (guard *Guard) StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isPublicMethod(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	ctx, err := guard.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return toStatus(err)
+	}
+	return handler(srv, &guardedStream{ServerStream: ss, ctx: ctx, guard: guard})
+}
+
+func (guard *Guard) authenticate(ctx context.Context, method string) (context.Context, error) {
+	principal, err := guard.Authenticator.Authenticate(ctx)
+	if errors.Is(err, errNoCredentials) {
+		err = fferr.NewUnauthenticatedErrorf("missing credentials")
+	}
+	if err != nil {
+		guard.Logger.Warnw("Rejected unauthenticated call", "method", method, "error", err)
+		return nil, err
+	}
+	return WithPrincipal(ctx, principal), nil
+}
+
+func (guard *Guard) authorize(ctx context.Context, req interface{}) error {
+	action, resources, ok := checksForRequest(req)
+	if !ok {
+		return nil
+	}
+	return guard.authorizeResources(ctx, action, resources)
+}
+
+// updatedOnCreate are the resource types that a create replaces when they already exist.
+var updatedOnCreate = map[pb.ResourceType]bool{
+	pb.ResourceType_PROVIDER: true,
+	pb.ResourceType_USER:     true,
+}
+
+func (guard *Guard) authorizeResources(ctx context.Context, action Action, resources []Resource) error {
+	principal, _ := PrincipalFromContext(ctx)
+	for _, resource := range resources {
+		resourceAction := action
+		if guard.Resolver != nil && (action != CreateAction || updatedOnCreate[resource.Type]) {
+			err := guard.Resolver.Resolve(ctx, &resource)
+			switch {
+			case action == CreateAction && err == nil:
+				// Creating a resource that already exists updates it.
+				resourceAction = UpdateAction
+			case action == CreateAction && isNotFound(err):
+				// A new resource is authorized as a create.
+			case err != nil && (action == DeleteAction || !isNotFound(err)):
+				// Serving requests may name a default variant that the resolver
+				// can't look up, let the handler report missing resources.
+				return err
+			}
+		}
+		if err := guard.Authorizer.Authorize(ctx, principal, resourceAction, resource); err != nil {
+			guard.Logger.Warnw("Denied call", "subject", principal.Subject, "action", resourceAction, "resource_type", resource.Type, "resource_name", resource.Name, "resource_variant", resource.Variant)
+			return err
+		}
+	}
+	return nil
+}
+
+// guardedStream carries the principal and authorizes every received message.
+// Client streams such as WriteFeatures send many messages for the same
+// resource, so each action and resource is only authorized once per stream.
+type guardedStream struct {
+	grpc.ServerStream
+	ctx        context.Context
+	guard      *Guard
+	authorized map[string]bool
+}
+
+func (stream *guardedStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *guardedStream) RecvMsg(m interface{}) error {
+	if err := stream.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	action, resources, ok := checksForRequest(m)
+	if !ok {
+		return nil
+	}
+	unchecked := make([]Resource, 0, len(resources))
+	for _, resource := range resources {
+		if !stream.authorized[checkKey(action, resource)] {
+			unchecked = append(unchecked, resource)
+		}
+	}
+	if err := stream.guard.authorizeResources(stream.ctx, action, unchecked); err != nil {
+		return toStatus(err)
+	}
+	if stream.authorized == nil {
+		stream.authorized = make(map[string]bool)
+	}
+	for _, resource := range unchecked {
+		stream.authorized[checkKey(action, resource)] = true
+	}
+	return nil
+}
+
+func checkKey(action Action, resource Resource) string {
+	return fmt.Sprintf("%s/%s/%s/%s", action, resource.Type, resource.Name, resource.Variant)
+}
+
+// checksForRequest maps a request to the action and resources it needs to be authorized for.
+// Requests that only read metadata, such as Get, List and column lookups, aren't authorized.
+func checksForRequest(req interface{}) (Action, []Resource, bool) {
+	switch r := req.(type) {
+	case *pb.UserRequest:
+		return CreateAction, []Resource{{Type: pb.ResourceType_USER, Name: r.GetUser().GetName(), Tags: r.GetUser().GetTags().GetTag()}}, true
+	case *pb.ProviderRequest:
+		return CreateAction, []Resource{{Type: pb.ResourceType_PROVIDER, Name: r.GetProvider().GetName(), Tags: r.GetProvider().GetTags().GetTag()}}, true
+	case *pb.EntityRequest:
+		return CreateAction, []Resource{{Type: pb.ResourceType_ENTITY, Name: r.GetEntity().GetName(), Tags: r.GetEntity().GetTags().GetTag()}}, true
+	case *pb.ModelRequest:
+		return CreateAction, []Resource{{Type: pb.ResourceType_MODEL, Name: r.GetModel().GetName(), Tags: r.GetModel().GetTags().GetTag()}}, true
+	case *pb.SourceVariantRequest:
+		sv := r.GetSourceVariant()
+		return CreateAction, []Resource{{Type: pb.ResourceType_SOURCE_VARIANT, Name: sv.GetName(), Variant: sv.GetVariant(), Tags: sv.GetTags().GetTag()}}, true
+	case *pb.FeatureVariantRequest:
+		fv := r.GetFeatureVariant()
+		return CreateAction, []Resource{{Type: pb.ResourceType_FEATURE_VARIANT, Name: fv.GetName(), Variant: fv.GetVariant(), Tags: fv.GetTags().GetTag()}}, true
+	case *pb.LabelVariantRequest:
+		lv := r.GetLabelVariant()
+		return CreateAction, []Resource{{Type: pb.ResourceType_LABEL_VARIANT, Name: lv.GetName(), Variant: lv.GetVariant(), Tags: lv.GetTags().GetTag()}}, true
+	case *pb.TrainingSetVariantRequest:
+		ts := r.GetTrainingSetVariant()
+		return CreateAction, []Resource{{Type: pb.ResourceType_TRAINING_SET_VARIANT, Name: ts.GetName(), Variant: ts.GetVariant(), Tags: ts.GetTags().GetTag()}}, true
+	case *pb.MarkForDeletionRequest:
+		return DeleteAction, []Resource{idResource(r.GetResourceId())}, true
+	case *pb.PruneResourceRequest:
+		return DeleteAction, []Resource{idResource(r.GetResourceId())}, true
+	case *pb.ScheduleChangeRequest:
+		return WriteAction, []Resource{idResource(r.GetResourceId())}, true
+	case *pb.RunRequest:
+		resources := make([]Resource, len(r.GetVariants()))
+		for i, variant := range r.GetVariants() {
+			resources[i] = variantResource(variant)
+		}
+		return WriteAction, resources, true
+	case *pb.StreamingFeatureVariant:
+		return WriteAction, []Resource{{Type: pb.ResourceType_FEATURE_VARIANT, Name: r.GetName(), Variant: r.GetVariant()}}, true
+	case *pb.StreamingLabelVariant:
+		return WriteAction, []Resource{{Type: pb.ResourceType_LABEL_VARIANT, Name: r.GetName(), Variant: r.GetVariant()}}, true
+	case *srvpb.FeatureServeRequest:
+		return ServeAction, featureResources(r.GetFeatures()), true
+	case *srvpb.BatchFeatureServeRequest:
+		return ServeAction, featureResources(r.GetFeatures()), true
+	case *srvpb.NearestRequest:
+		return ServeAction, featureResources([]*srvpb.FeatureID{r.GetId()}), true
+	case *srvpb.SourceDataRequest:
+		return ServeAction, []Resource{{Type: pb.ResourceType_SOURCE_VARIANT, Name: r.GetId().GetName(), Variant: r.GetId().GetVersion()}}, true
+	case *srvpb.TrainingDataRequest:
+		return TrainAction, []Resource{{Type: pb.ResourceType_TRAINING_SET_VARIANT, Name: r.GetId().GetName(), Variant: r.GetId().GetVersion()}}, true
+	case *srvpb.TrainTestSplitRequest:
+		return TrainAction, []Resource{{Type: pb.ResourceType_TRAINING_SET_VARIANT, Name: r.GetId().GetName(), Variant: r.GetId().GetVersion()}}, true
+	default:
+		return "", nil, false
+	}
+}
+
+func idResource(id *pb.ResourceID) Resource {
+	return Resource{Type: id.GetResourceType(), Name: id.GetResource().GetName(), Variant: id.GetResource().GetVariant()}
+}
+
+func variantResource(variant *pb.ResourceVariant) Resource {
+	switch v := variant.GetResource().(type) {
+	case *pb.ResourceVariant_SourceVariant:
+		return Resource{Type: pb.ResourceType_SOURCE_VARIANT, Name: v.SourceVariant.GetName(), Variant: v.SourceVariant.GetVariant()}
+	case *pb.ResourceVariant_FeatureVariant:
+		return Resource{Type: pb.ResourceType_FEATURE_VARIANT, Name: v.FeatureVariant.GetName(), Variant: v.FeatureVariant.GetVariant()}
+	case *pb.ResourceVariant_LabelVariant:
+		return Resource{Type: pb.ResourceType_LABEL_VARIANT, Name: v.LabelVariant.GetName(), Variant: v.LabelVariant.GetVariant()}
+	case *pb.ResourceVariant_TrainingSetVariant:
+		return Resource{Type: pb.ResourceType_TRAINING_SET_VARIANT, Name: v.TrainingSetVariant.GetName(), Variant: v.TrainingSetVariant.GetVariant()}
+	default:
+		return Resource{}
+	}
+}
+
+func featureResources(ids []*srvpb.FeatureID) []Resource {
+	resources := make([]Resource, len(ids))
+	for i, id := range ids {
+		resources[i] = Resource{Type: pb.ResourceType_FEATURE_VARIANT, Name: id.GetName(), Variant: id.GetVersion()}
+	}
+	return resources
+}
+
+func isNotFound(err error) bool {
+	var grpcErr fferr.Error
+	if errors.As(err, &grpcErr) {
+		return grpcErr.GetCode() == codes.NotFound
+	}
+	return status.Code(err) == codes.NotFound
+}
+
+func toStatus(err error) error {
+	var grpcErr fferr.Error
+	if errors.As(err, &grpcErr) {
+		return grpcErr.ToErr()
+	}
+	return err
+}
@@ -0,0 +1,291 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package auth
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/logging"
+	pb "github.com/featureform/metadata/proto"
+	srvpb "github.com/featureform/proto"
+)
+
+type mapResolver map[string]Resource
+
+func (resolver mapResolver) Resolve(ctx context.Context, resource *Resource) error {
+	known, ok := resolver[resource.Name]
+	if !ok {
+		return fferr.NewKeyNotFoundError(resource.Name, nil)
+	}
+	resource.Owner = known.Owner
+	resource.Tags = known.Tags
+	return nil
+}
+
+func newTestGuard(t *testing.T) *Guard {
+	tokens, err := NewStaticTokenAuthenticator([]StaticToken{
+		{Subject: "alice", Token: "alice-token"},
+		{Subject: "bob", Token: "bob-token"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create authenticator: %v", err)
+	}
+	return &Guard{
+		Authenticator: tokens,
+		Authorizer:    NewPolicyAuthorizer(DefaultPolicy()),
+		Resolver: mapResolver{
+			"feature": {Owner: "alice"},
+		},
+		Logger: logging.NewTestLogger(t),
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	guard := newTestGuard(t)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		principal, ok := PrincipalFromContext(ctx)
+		if !ok {
+			t.Fatalf("Handler called without a principal")
+		}
+		return principal.Subject, nil
+	}
+	deleteFeature := &pb.MarkForDeletionRequest{ResourceId: &pb.ResourceID{
+		Resource:     &pb.NameVariant{Name: "feature", Variant: "v1"},
+		ResourceType: pb.ResourceType_FEATURE_VARIANT,
+	}}
+	deleteMissing := &pb.MarkForDeletionRequest{ResourceId: &pb.ResourceID{
+		Resource:     &pb.NameVariant{Name: "missing", Variant: "v1"},
+		ResourceType: pb.ResourceType_FEATURE_VARIANT,
+	}}
+	serveDefault := &srvpb.FeatureServeRequest{Features: []*srvpb.FeatureID{{Name: "unknown"}}}
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		req    interface{}
+		code   codes.Code
+	}{
+		{"Missing token", "", "/featureform.serving.metadata.proto.Api/MarkForDeletion", deleteFeature, codes.Unauthenticated},
+		{"Invalid token", "wrong", "/featureform.serving.metadata.proto.Api/MarkForDeletion", deleteFeature, codes.Unauthenticated},
+		{"Owner deletes", "alice-token", "/featureform.serving.metadata.proto.Api/MarkForDeletion", deleteFeature, codes.OK},
+		{"Non owner deletes", "bob-token", "/featureform.serving.metadata.proto.Api/MarkForDeletion", deleteFeature, codes.PermissionDenied},
+		{"Delete missing resource", "alice-token", "/featureform.serving.metadata.proto.Api/MarkForDeletion", deleteMissing, codes.NotFound},
+		{"Serve unresolved feature", "bob-token", "/featureform.serving.proto.Feature/FeatureServe", serveDefault, codes.OK},
+		{"Health check", "", "/grpc.health.v1.Health/Check", nil, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = bearerContext(tt.token)
+			}
+			healthHandler := handler
+			if tt.req == nil {
+				healthHandler = func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+			}
+			_, err := guard.UnaryServerInterceptor(ctx, tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, healthHandler)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("Expected %s, got %s: %v", tt.code, code, err)
+			}
+		})
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req *srvpb.TrainingDataRequest
+}
+
+func (stream *fakeServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *fakeServerStream) RecvMsg(m interface{}) error {
+	*m.(*srvpb.TrainingDataRequest) = srvpb.TrainingDataRequest{Id: stream.req.Id}
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	guard := newTestGuard(t)
+	guard.Authorizer = NewPolicyAuthorizer(Policy{
+		Rules: []Rule{{Subjects: []string{"alice"}, Actions: []Action{TrainAction}}},
+	})
+	info := &grpc.StreamServerInfo{FullMethod: "/featureform.serving.proto.Feature/TrainingData"}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		if _, ok := PrincipalFromContext(stream.Context()); !ok {
+			t.Fatalf("Handler called without a principal")
+		}
+		return stream.RecvMsg(&srvpb.TrainingDataRequest{})
+	}
+	req := &srvpb.TrainingDataRequest{Id: &srvpb.TrainingDataID{Name: "feature", Version: "v1"}}
+
+	allowed := &fakeServerStream{ctx: bearerContext("alice-token"), req: req}
+	if err := guard.StreamServerInterceptor(nil, allowed, info, handler); err != nil {
+		t.Fatalf("Expected alice to be allowed, got %v", err)
+	}
+	denied := &fakeServerStream{ctx: bearerContext("bob-token"), req: req}
+	if err := guard.StreamServerInterceptor(nil, denied, info, handler); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Expected bob to be denied, got %v", err)
+	}
+}
+
+// metadataReads are the RPCs that only read metadata, so they aren't authorized.
+var metadataReads = map[string]bool{
+	"GetUsers": true, "GetFeatures": true, "GetFeatureVariants": true, "GetLabels": true,
+	"GetLabelVariants": true, "GetTrainingSets": true, "GetTrainingSetVariants": true,
+	"GetSources": true, "GetSourceVariants": true, "GetProviders": true, "GetEntities": true,
+	"GetModels": true, "GetEquivalent": true, "ListFeatures": true, "ListLabels": true,
+	"ListTrainingSets": true, "ListSources": true, "ListUsers": true, "ListProviders": true,
+	"ListEntities": true, "ListModels": true, "TrainingDataColumns": true, "SourceColumns": true,
+	"GetResourceLocation": true,
+}
+
+func TestChecksForRequestCoversGuardedServices(t *testing.T) {
+	for _, service := range []protoreflect.FullName{"featureform.serving.metadata.proto.Api", "featureform.serving.proto.Feature"} {
+		desc, err := protoregistry.GlobalFiles.FindDescriptorByName(service)
+		if err != nil {
+			t.Fatalf("Failed to find %s: %v", service, err)
+		}
+		methods := desc.(protoreflect.ServiceDescriptor).Methods()
+		for i := 0; i < methods.Len(); i++ {
+			method := methods.Get(i)
+			t.Run(string(method.FullName()), func(t *testing.T) {
+				msgType, err := protoregistry.GlobalTypes.FindMessageByName(method.Input().FullName())
+				if err != nil {
+					t.Fatalf("Failed to find %s: %v", method.Input().FullName(), err)
+				}
+				_, _, ok := checksForRequest(msgType.New().Interface())
+				read := metadataReads[string(method.Name())]
+				if !ok && !read {
+					t.Fatalf("%s isn't authorized", method.Name())
+				}
+				if ok && read {
+					t.Fatalf("%s is authorized but listed as a metadata read", method.Name())
+				}
+			})
+		}
+	}
+}
+
+func TestUnaryServerInterceptorCreateOwner(t *testing.T) {
+	guard := newTestGuard(t)
+	guard.Authorizer = NewPolicyAuthorizer(Policy{OwnerActions: []Action{DeleteAction}})
+	create := &pb.FeatureVariantRequest{FeatureVariant: &pb.FeatureVariant{Name: "new", Variant: "v1", Owner: "bob"}}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/featureform.serving.metadata.proto.Api/CreateFeatureVariant"}
+	if _, err := guard.UnaryServerInterceptor(bearerContext("bob-token"), create, info, handler); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Expected naming yourself owner not to grant creates, got %v", err)
+	}
+}
+
+func TestUnaryServerInterceptorCreateExisting(t *testing.T) {
+	guard := newTestGuard(t)
+	guard.Resolver = mapResolver{"postgres": {}, "alice": {}}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		req    interface{}
+		code   codes.Code
+	}{
+		{"New provider", "bob-token", "CreateProvider", &pb.ProviderRequest{Provider: &pb.Provider{Name: "snowflake"}}, codes.OK},
+		{"Existing provider", "bob-token", "CreateProvider", &pb.ProviderRequest{Provider: &pb.Provider{Name: "postgres"}}, codes.PermissionDenied},
+		{"Existing provider as admin", "admin-token", "CreateProvider", &pb.ProviderRequest{Provider: &pb.Provider{Name: "postgres"}}, codes.OK},
+		{"Existing user", "bob-token", "CreateUser", &pb.UserRequest{User: &pb.User{Name: "alice"}}, codes.PermissionDenied},
+	}
+	tokens, err := NewStaticTokenAuthenticator([]StaticToken{
+		{Subject: "bob", Token: "bob-token"},
+		{Subject: "root", Token: "admin-token", Roles: []string{"admin"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create authenticator: %v", err)
+	}
+	guard.Authenticator = tokens
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &grpc.UnaryServerInfo{FullMethod: "/featureform.serving.metadata.proto.Api/" + tt.method}
+			_, err := guard.UnaryServerInterceptor(bearerContext(tt.token), tt.req, info, handler)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("Expected %s, got %s: %v", tt.code, code, err)
+			}
+		})
+	}
+}
+
+type countingResolver struct {
+	mapResolver
+	calls int
+}
+
+func (resolver *countingResolver) Resolve(ctx context.Context, resource *Resource) error {
+	resolver.calls++
+	return resolver.mapResolver.Resolve(ctx, resource)
+}
+
+type fakeWriteStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	rows []*pb.StreamingFeatureVariant
+}
+
+func (stream *fakeWriteStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *fakeWriteStream) RecvMsg(m interface{}) error {
+	if len(stream.rows) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(*pb.StreamingFeatureVariant), stream.rows[0])
+	stream.rows = stream.rows[1:]
+	return nil
+}
+
+func TestStreamServerInterceptorWrites(t *testing.T) {
+	guard := newTestGuard(t)
+	guard.Authorizer = NewPolicyAuthorizer(Policy{OwnerActions: []Action{WriteAction}})
+	resolver := &countingResolver{mapResolver: mapResolver{"feature": {Owner: "alice"}}}
+	guard.Resolver = resolver
+	info := &grpc.StreamServerInfo{FullMethod: "/featureform.serving.metadata.proto.Api/WriteFeatures"}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		for {
+			if err := stream.RecvMsg(&pb.StreamingFeatureVariant{}); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+	rows := func() []*pb.StreamingFeatureVariant {
+		return []*pb.StreamingFeatureVariant{
+			{Name: "feature", Variant: "v1", Entity: "a", Value: "1"},
+			{Name: "feature", Variant: "v1", Entity: "b", Value: "2"},
+		}
+	}
+	if err := guard.StreamServerInterceptor(nil, &fakeWriteStream{ctx: bearerContext("alice-token"), rows: rows()}, info, handler); err != nil {
+		t.Fatalf("Expected the owner to write, got %v", err)
+	}
+	if resolver.calls != 1 {
+		t.Fatalf("Expected the feature to be resolved once per stream, got %d", resolver.calls)
+	}
+	if err := guard.StreamServerInterceptor(nil, &fakeWriteStream{ctx: bearerContext("bob-token"), rows: rows()}, info, handler); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Expected bob to be denied, got %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/featureform/fferr"
+)
+
+const (
+	defaultSubjectClaim = "sub"
+	defaultRolesClaim   = "roles"
+)
+
+// JWTConfig configures validation of OIDC issued JWTs. Keys are read from a
+// local JWKS file rather than fetched from the issuer so that servers never
+// make outbound calls to authenticate a request.
+type JWTConfig struct {
+	JWKSPath     string
+	Issuer       string
+	Audience     string
+	SubjectClaim string
+	RolesClaim   string
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jwtAuthenticator struct {
+	config JWTConfig
+	keys   map[string]crypto.PublicKey
+	parser *jwt.Parser
+}
+
+func NewJWTAuthenticator(config JWTConfig) (Authenticator, error) {
+	data, err := os.ReadFile(config.JWKSPath)
+	if err != nil {
+		return nil, fferr.NewInvalidConfigf("could not read JWKS file %s: %v", config.JWKSPath, err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	if config.SubjectClaim == "" {
+		config.SubjectClaim = defaultSubjectClaim
+	}
+	if config.RolesClaim == "" {
+		config.RolesClaim = defaultRolesClaim
+	}
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithExpirationRequired(),
+	}
+	if config.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		opts = append(opts, jwt.WithAudience(config.Audience))
+	}
+	return &jwtAuthenticator{
+		config: config,
+		keys:   keys,
+		parser: jwt.NewParser(opts...),
+	}, nil
+}
+
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set jsonWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fferr.NewInvalidConfigf("could not parse JWKS: %v", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fferr.NewInvalidConfigf("invalid JWKS key %q: %v", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fferr.NewInvalidConfigf("JWKS contains no signing keys")
+	}
+	return keys, nil
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("rsa exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %s", jwk.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+func (auth *jwtAuthenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := auth.keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(auth.keys) == 1 {
+		for _, key := range auth.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (auth *jwtAuthenticator) Authenticate(ctx context.Context) (*Principal, error) {
+	raw, err := bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Leave opaque API tokens to the static token authenticator.
+	if strings.Count(raw, ".") != 2 {
+		return nil, errNoCredentials
+	}
+	claims := jwt.MapClaims{}
+	if _, err := auth.parser.ParseWithClaims(raw, claims, auth.keyFunc); err != nil {
+		return nil, fferr.NewUnauthenticatedErrorf("invalid jwt: %v", err)
+	}
+	subject, _ := claims[auth.config.SubjectClaim].(string)
+	if subject == "" {
+		return nil, fferr.NewUnauthenticatedErrorf("jwt is missing the %s claim", auth.config.SubjectClaim)
+	}
+	return &Principal{Subject: subject, Roles: rolesFromClaim(claims[auth.config.RolesClaim]), Method: JWTMethod}, nil
+}
+
+// rolesFromClaim accepts either a JSON array of strings or a single space
+// separated string, which is how most OIDC providers encode groups and scopes.
+func rolesFromClaim(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		roles := make([]string, 0, len(value))
+		for _, role := range value {
+			if str, ok := role.(string); ok {
+				roles = append(roles, str)
+			}
+		}
+		return roles
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,185 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/featureform/fferr"
+	pb "github.com/featureform/metadata/proto"
+)
+
+// Action is the kind of operation being authorized.
+type Action string
+
+const (
+	CreateAction Action = "create"
+	DeleteAction Action = "delete"
+	ServeAction  Action = "serve"
+	TrainAction  Action = "train"
+	// UpdateAction replaces an existing provider or user, which creating them again does.
+	UpdateAction Action = "update"
+	// WriteAction changes an existing resource, e.g. by running it, changing its schedule or
+	// streaming values into it.
+	WriteAction Action = "write"
+)
+
+const wildcard = "*"
+
+// Resource is the target of an authorization check. Tags are filled from
+// the request for creates of new resources, and Owner and Tags by a
+// ResourceResolver otherwise.
+type Resource struct {
+	Type    pb.ResourceType
+	Name    string
+	Variant string
+	Owner   string
+	Tags    []string
+}
+
+func (r Resource) fferrType() fferr.ResourceType {
+	return fferr.ResourceType(r.Type.String())
+}
+
+// ResourceResolver fills in the owner and tags of an existing resource.
+// Resolvers return a NotFound error for resources that don't exist yet.
+type ResourceResolver interface {
+	Resolve(ctx context.Context, resource *Resource) error
+}
+
+// Authorizer decides whether a principal may perform an action on a resource.
+// A denied request returns a PermissionDeniedError.
+type Authorizer interface {
+	Authorize(ctx context.Context, principal *Principal, action Action, resource Resource) error
+}
+
+// Rule grants its Actions to principals matching any of Subjects or Roles.
+// Empty ResourceTypes and Tags match every resource; otherwise the resource
+// must be of one of the types and carry at least one of the tags.
+type Rule struct {
+	Subjects      []string `json:"subjects,omitempty"`
+	Roles         []string `json:"roles,omitempty"`
+	Actions       []Action `json:"actions"`
+	ResourceTypes []string `json:"resource_types,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+func (rule Rule) matchesPrincipal(principal *Principal) bool {
+	if len(rule.Subjects) == 0 && len(rule.Roles) == 0 {
+		return true
+	}
+	if slices.Contains(rule.Subjects, wildcard) || slices.Contains(rule.Subjects, principal.Subject) {
+		return true
+	}
+	for _, role := range rule.Roles {
+		if role == wildcard || principal.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule Rule) matches(principal *Principal, action Action, resource Resource) bool {
+	if !slices.Contains(rule.Actions, action) && !slices.Contains(rule.Actions, Action(wildcard)) {
+		return false
+	}
+	if len(rule.ResourceTypes) > 0 && !slices.Contains(rule.ResourceTypes, resource.Type.String()) {
+		return false
+	}
+	if len(rule.Tags) > 0 && !slices.ContainsFunc(resource.Tags, func(tag string) bool { return slices.Contains(rule.Tags, tag) }) {
+		return false
+	}
+	return rule.matchesPrincipal(principal)
+}
+
+// Policy is the role policy evaluated by the policy authorizer. Principals
+// with an AdminRole may do anything, the owner of a resource may perform
+// OwnerActions on it, and everything else must be granted by a Rule. A
+// resource being created has no owner yet, so creates are only granted by
+// Rules.
+type Policy struct {
+	AdminRoles   []string `json:"admin_roles"`
+	OwnerActions []Action `json:"owner_actions"`
+	Rules        []Rule   `json:"rules"`
+}
+
+// DefaultPolicy lets any authenticated caller create and run resources, serve
+// features and read training sets, while deletes and updates are limited to owners
+// and admins.
+func DefaultPolicy() Policy {
+	return Policy{
+		AdminRoles:   []string{"admin"},
+		OwnerActions: []Action{DeleteAction, ServeAction, TrainAction, WriteAction, UpdateAction},
+		Rules: []Rule{
+			{Actions: []Action{CreateAction, ServeAction, TrainAction, WriteAction}},
+		},
+	}
+}
+
+func LoadPolicy(path string) (Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, fferr.NewInvalidConfigf("could not read auth policy %s: %v", path, err)
+	}
+	var policy Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return Policy{}, fferr.NewInvalidConfigf("could not parse auth policy %s: %v", path, err)
+	}
+	if slices.Contains(policy.OwnerActions, CreateAction) {
+		return Policy{}, fferr.NewInvalidConfigf("auth policy owner_actions can't include %s, creates are only granted by rules", CreateAction)
+	}
+	for i, rule := range policy.Rules {
+		if len(rule.Actions) == 0 {
+			return Policy{}, fferr.NewInvalidConfigf("auth policy rule %d has no actions", i)
+		}
+		for _, resourceType := range rule.ResourceTypes {
+			if _, ok := pb.ResourceType_value[resourceType]; !ok {
+				return Policy{}, fferr.NewInvalidConfigf("auth policy rule %d has unknown resource type %s", i, resourceType)
+			}
+		}
+	}
+	return policy, nil
+}
+
+type policyAuthorizer struct {
+	policy Policy
+}
+
+func NewPolicyAuthorizer(policy Policy) Authorizer {
+	return &policyAuthorizer{policy: policy}
+}
+
+func (auth *policyAuthorizer) Authorize(ctx context.Context, principal *Principal, action Action, resource Resource) error {
+	if principal == nil {
+		return fferr.NewUnauthenticatedErrorf("missing credentials")
+	}
+	if slices.ContainsFunc(auth.policy.AdminRoles, principal.HasRole) {
+		return nil
+	}
+	// The owner of a resource being created comes from the request, so it can't grant the create.
+	if action != CreateAction && resource.Owner != "" && resource.Owner == principal.Subject && slices.Contains(auth.policy.OwnerActions, action) {
+		return nil
+	}
+	for _, rule := range auth.policy.Rules {
+		if rule.matches(principal, action, resource) {
+			return nil
+		}
+	}
+	return fferr.NewPermissionDeniedError(
+		principal.Subject,
+		string(action),
+		resource.Name,
+		resource.Variant,
+		resource.fferrType(),
+		fmt.Errorf("%s may not %s %s %s", principal.Subject, action, resource.Type, resource.Name),
+	)
+}
@@ -0,0 +1,99 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/featureform/fferr"
+	pb "github.com/featureform/metadata/proto"
+)
+
+func TestPolicyAuthorizer(t *testing.T) {
+	policy := Policy{
+		AdminRoles:   []string{"admin"},
+		OwnerActions: []Action{DeleteAction, CreateAction},
+		Rules: []Rule{
+			{Roles: []string{"ml-eng"}, Actions: []Action{CreateAction, ServeAction}},
+			{Subjects: []string{"trainer"}, Actions: []Action{TrainAction}, ResourceTypes: []string{"TRAINING_SET_VARIANT"}},
+			{Roles: []string{"fraud"}, Actions: []Action{DeleteAction}, Tags: []string{"fraud"}},
+		},
+	}
+	authorizer := NewPolicyAuthorizer(policy)
+	feature := Resource{Type: pb.ResourceType_FEATURE_VARIANT, Name: "f", Variant: "v", Owner: "alice"}
+	fraudFeature := Resource{Type: pb.ResourceType_FEATURE_VARIANT, Name: "f", Variant: "v", Owner: "alice", Tags: []string{"fraud"}}
+	trainingSet := Resource{Type: pb.ResourceType_TRAINING_SET_VARIANT, Name: "ts", Variant: "v"}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		action    Action
+		resource  Resource
+		allowed   bool
+	}{
+		{"Admin may delete", &Principal{Subject: "root", Roles: []string{"admin"}}, DeleteAction, feature, true},
+		{"Owner may delete", &Principal{Subject: "alice"}, DeleteAction, feature, true},
+		{"Non owner may not delete", &Principal{Subject: "bob", Roles: []string{"ml-eng"}}, DeleteAction, feature, false},
+		{"Role may create", &Principal{Subject: "bob", Roles: []string{"ml-eng"}}, CreateAction, feature, true},
+		{"Owner named in request may not create", &Principal{Subject: "alice"}, CreateAction, feature, false},
+		{"Missing role may not serve", &Principal{Subject: "carol"}, ServeAction, feature, false},
+		{"Subject may train", &Principal{Subject: "trainer"}, TrainAction, trainingSet, true},
+		{"Resource type is enforced", &Principal{Subject: "trainer"}, TrainAction, feature, false},
+		{"Tag grants delete", &Principal{Subject: "dave", Roles: []string{"fraud"}}, DeleteAction, fraudFeature, true},
+		{"Tag is required", &Principal{Subject: "dave", Roles: []string{"fraud"}}, DeleteAction, feature, false},
+		{"Create rule doesn't grant updates", &Principal{Subject: "bob", Roles: []string{"ml-eng"}}, UpdateAction, feature, false},
+		{"Admin may update", &Principal{Subject: "root", Roles: []string{"admin"}}, UpdateAction, feature, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.Authorize(context.Background(), tt.principal, tt.action, tt.resource)
+			if tt.allowed && err != nil {
+				t.Fatalf("Expected allowed, got %v", err)
+			}
+			var denied *fferr.PermissionDeniedError
+			if !tt.allowed && !errors.As(err, &denied) {
+				t.Fatalf("Expected permission denied, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	if err := os.WriteFile(valid, []byte(`{"admin_roles": ["admin"], "rules": [{"roles": ["*"], "actions": ["serve"], "resource_types": ["FEATURE_VARIANT"]}]}`), 0600); err != nil {
+		t.Fatalf("Failed to write policy: %v", err)
+	}
+	policy, err := LoadPolicy(valid)
+	if err != nil {
+		t.Fatalf("Failed to load policy: %v", err)
+	}
+	if len(policy.Rules) != 1 || policy.Rules[0].Actions[0] != ServeAction {
+		t.Fatalf("Unexpected policy %+v", policy)
+	}
+
+	invalid := map[string]string{
+		"no_actions.json":   `{"rules": [{"roles": ["admin"]}]}`,
+		"unknown_type.json": `{"rules": [{"actions": ["serve"], "resource_types": ["WIDGET"]}]}`,
+		"not_json.json":     `rules`,
+		"owner_create.json": `{"owner_actions": ["create"], "rules": []}`,
+	}
+	for name, contents := range invalid {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatalf("Failed to write policy: %v", err)
+		}
+		if _, err := LoadPolicy(path); err == nil {
+			t.Fatalf("Expected %s to fail", name)
+		}
+	}
+}
@@ -184,6 +184,48 @@ def head(host, cert, insecure, limit, name, variant):
     print(df)
 
 
+@cli.command()
+@click.option(
+    "--host",
+    "host",
+    required=False,
+    help="The host address of the API server to connect to",
+)
+@click.option(
+    "--cert", "cert", required=False, help="Path to self-signed TLS certificate"
+)
+@click.option("--insecure", is_flag=True, help="Disables TLS verification")
+@click.option(
+    "--start",
+    "start",
+    required=True,
+    type=click.DateTime(),
+    help="The start of the range to rebuild, inclusive",
+)
+@click.option(
+    "--end",
+    "end",
+    required=True,
+    type=click.DateTime(),
+    help="The end of the range to rebuild, exclusive",
+)
+@click.option(
+    "--partitions-per-run",
+    "partitions_per_run",
+    required=False,
+    default=1,
+    type=click.IntRange(min=1),
+    help="The number of daily partitions rebuilt by each task run",
+)
+@click.argument("name", required=True)
+@click.argument("variant", required=True)
+def backfill(host, cert, insecure, start, end, partitions_per_run, name, variant):
+    """Rebuilds a time range of an incremental or daily partitioned SQL transformation."""
+    client = Client(host=host, insecure=insecure, cert_path=cert)
+    client.backfill((name, variant), start, end, partitions_per_run)
+    print(f"Backfilling {name} ({variant}) from {start} to {end}")
+
+
 @cli.command()
 @click.argument("files", required=True, nargs=-1)
 @click.option(
@@ -6,6 +6,7 @@
 #
 
 import json
+import uuid
 from datetime import datetime
 from typing import List, Optional, Union
 import os
@@ -413,6 +414,52 @@ class Client(ResourceClient, ServingClient):
         labels = iter([label])
         self._stub.WriteLabels(labels)
 
+    @typechecked
+    def backfill(
+        self,
+        name_variant: tuple,
+        start: datetime,
+        end: datetime,
+        partitions_per_run: int = 1,
+    ):
+        """Rebuilds the rows of an incremental or daily partitioned SQL transformation with timestamps in [start, end).
+
+        A daily partitioned transformation is rebuilt in whole days, `partitions_per_run` days per task run,
+        overwriting only those partitions. Its features are then re-materialized for the entities with values
+        in the range.
+
+        **Examples:**
+        ``` py
+        client.backfill(("avg_transactions", "v1"), datetime(2024, 1, 1), datetime(2024, 2, 1))
+        ```
+
+        Args:
+            name_variant (tuple): The name and variant of the transformation.
+            start (datetime): The start of the range, inclusive.
+            end (datetime): The end of the range, exclusive.
+            partitions_per_run (int): The number of daily partitions rebuilt by each task run.
+        """
+        if start >= end:
+            raise ValueError("backfill start must be before its end")
+        if partitions_per_run < 1:
+            raise ValueError("partitions_per_run must be at least 1")
+        name, variant = name_variant
+        start_ts, end_ts = Timestamp(), Timestamp()
+        start_ts.FromDatetime(start)
+        end_ts.FromDatetime(end)
+        req = pb.RunRequest(
+            request_id=str(uuid.uuid4()),
+            variants=[
+                pb.ResourceVariant(
+                    source_variant=pb.SourceVariant(name=name, variant=variant)
+                )
+            ],
+            backfill=pb.BackfillRange(
+                start=start_ts, end=end_ts, partitions_per_run=partitions_per_run
+            ),
+        )
+        self._stub.Run(req)
+
     def location(
         self,
         source: Union[
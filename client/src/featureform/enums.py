@@ -362,6 +362,33 @@ class Initialize(Enum):
             raise ValueError(f"Initialize value required: received {value}")
 
 
+class SnowflakeRefreshStrategy(Enum):
+    """
+    How Snowflake keeps a resource's table up to date. By default, Featureform re-runs the
+    resource's query on its schedule.
+
+    DYNAMIC_TABLE: Snowflake refreshes a dynamic table within its target lag.
+    STREAM: Snowflake streams the new rows of an incremental transformation's sources, and a
+        task merges them into its table every target lag.
+    """
+
+    DYNAMIC_TABLE = pb.SnowflakeRefreshStrategy.SNOWFLAKE_REFRESH_STRATEGY_DYNAMIC_TABLE
+    STREAM = pb.SnowflakeRefreshStrategy.SNOWFLAKE_REFRESH_STRATEGY_STREAM
+
+    @classmethod
+    def from_proto(cls, proto_value):
+        try:
+            return cls(proto_value)
+        except ValueError:
+            return None
+
+    def to_proto(self):
+        return self.value
+
+    def to_string(self):
+        return self.name
+
+
 class SnowflakeSessionParamKey(Enum):
     ABORT_DETACHED_QUERY = "abort_detached_query"
     AUTOCOMMIT = "autocommit"
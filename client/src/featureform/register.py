@@ -947,6 +947,7 @@ class ColumnMapping(dict):
     properties: dict
     variant: str = ""
     resource_snowflake_config: Optional[ResourceSnowflakeConfig] = None
+    expectations: Optional[List[Expectation]] = None
 
 
 class SubscriptableTransformation:
@@ -1499,6 +1500,7 @@ class ColumnResource(ABC):
         name: str = "",
         variant: str = "",
         resource_snowflake_config: Optional[ResourceSnowflakeConfig] = None,
+        expectations: Optional[List[Expectation]] = None,
     ):
         registrar, source_name_variant, columns = transformation_args
         self.type = type if isinstance(type, str) else type.value
@@ -1524,6 +1526,7 @@ class ColumnResource(ABC):
         self.properties = properties
         self.variant = variant
         self.resource_snowflake_config = resource_snowflake_config
+        self.expectations = expectations
 
     def register(self):
         features, labels = self.get_resources_by_type(self.resource_type)
@@ -1554,6 +1557,7 @@ class ColumnResource(ABC):
                 "tags": self.tags,
                 "properties": self.properties,
                 "resource_snowflake_config": self.resource_snowflake_config,
+                "expectations": self.expectations,
             }
         ]
 
@@ -1615,6 +1619,7 @@ class FeatureColumnResource(ColumnResource):
         tags: Optional[List[str]] = None,
         properties: Optional[Dict[str, str]] = None,
         resource_snowflake_config: Optional[ResourceSnowflakeConfig] = None,
+        expectations: Optional[List[Expectation]] = None,
     ):
         """
         Feature registration object.
@@ -1637,6 +1642,7 @@ class FeatureColumnResource(ColumnResource):
             variant (str): An optional variant name for the feature.
             type (Union[ScalarType, str]): The type of the value in for the feature.
             inference_store (Union[str, OnlineProvider, FileStoreProvider]): Where to store for online serving.
+            expectations (List[Expectation]): Data quality checks on the value, entity and timestamp columns; a failing blocking check stops materialization.
         """
         super().__init__(
             transformation_args=transformation_args,
@@ -1653,6 +1659,7 @@ class FeatureColumnResource(ColumnResource):
             tags=tags,
             properties=properties,
             resource_snowflake_config=resource_snowflake_config,
+            expectations=expectations,
         )
 
 
@@ -3865,6 +3872,7 @@ class Registrar:
         spark_params: Optional[Dict[str, str]] = None,
         write_options: Optional[Dict[str, str]] = None,
         table_properties: Optional[Dict[str, str]] = None,
+        table_format: str = "",
     ):
         """Register a Spark on Executor provider.
 
@@ -3888,6 +3896,7 @@ class Registrar:
             team (str): (Mutable) Name of team
             tags (List[str]): (Mutable) Optional grouping mechanism for resources
             properties (dict): (Mutable) Optional grouping mechanism for resources
+            table_format (str): (Immutable) Write resources to the filestore as "delta" or "iceberg" tables instead of Parquet files; ignored with a catalog
 
         Returns:
             spark (OfflineSparkProvider): Provider
@@ -3899,6 +3908,7 @@ class Registrar:
             store_type=filestore.store_type(),
             store_config=filestore.config(),
             catalog=catalog.config() if catalog is not None else None,
+            table_format=table_format,
         )
 
         provider = Provider(
@@ -3923,6 +3933,7 @@ class Registrar:
         docker_image: str = "",
         tags: List[str] = [],
         properties: dict = {},
+        table_format: str = "",
     ):
         """
         Register an offline store provider to run on Featureform's own k8s deployment.
@@ -3943,6 +3954,7 @@ class Registrar:
             team (str): (Mutable) A string parameter describing the team that owns the provider
             tags (List[str]): (Mutable) Optional grouping mechanism for resources
             properties (dict): (Mutable) Optional grouping mechanism for resources
+            table_format (str): (Immutable) Write resources to the filestore as "delta" tables instead of Parquet files
         """
 
         tags, properties = set_tags_properties(tags, properties)
@@ -3950,6 +3962,7 @@ class Registrar:
             store_type=store.store_type(),
             store_config=store.config(),
             docker_image=docker_image,
+            table_format=table_format,
         )
 
         provider = Provider(
@@ -3975,6 +3988,8 @@ class Registrar:
         timestamp_column: str = "",
         owner: Union[str, UserRegistrar] = "",
         description: str = "",
+        allow_added_columns: bool = False,
+        expectations: Optional[List[Expectation]] = None,
     ):
         """Register a primary data source.
 
@@ -3984,6 +3999,8 @@ class Registrar:
             location (Location): Location of primary data
             provider (Union[str, OfflineProvider]): Provider
             timestamp_column (str): Optionally include timestamp column for append-only tables.
+            allow_added_columns (bool): Keep running tasks when columns are added to the table after registration. Removed or retyped columns always fail.
+            expectations (List[Expectation]): Data quality checks run on the source after it's registered
             owner (Union[str, UserRegistrar]): Owner
             description (str): Description of primary data to be registered
 
@@ -4003,13 +4020,16 @@ class Registrar:
             name=name,
             variant=variant,
             definition=PrimaryData(
-                location=location, timestamp_column=timestamp_column
+                location=location,
+                timestamp_column=timestamp_column,
+                allow_added_columns=allow_added_columns,
             ),
             owner=owner,
             provider=provider,
             description=description,
             tags=tags,
             properties=properties,
+            expectations=expectations or [],
         )
         self.__resources.append(source)
         column_source_registrar = ColumnSourceRegistrar(self, source)
@@ -4029,6 +4049,7 @@ class Registrar:
         inputs: Union[List[NameVariant], List[str], List[ColumnSourceRegistrar]] = None,
         tags: List[str] = [],
         properties: dict = {},
+        expectations: Optional[List[Expectation]] = None,
     ):
         """Register a SQL transformation source.
 
@@ -4043,6 +4064,7 @@ class Registrar:
             args (K8sArgs): Additional transformation arguments
             tags (List[str]): Optional grouping mechanism for resources
             properties (dict): Optional grouping mechanism for resources
+            expectations (List[Expectation]): Data quality checks run on the transformation after each run
 
         Returns:
             source (ColumnSourceRegistrar): Source
@@ -4066,6 +4088,7 @@ class Registrar:
             description=description,
             tags=tags,
             properties=properties,
+            expectations=expectations or [],
         )
         self.__resources.append(source)
         return ColumnSourceRegistrar(self, source)
@@ -4549,6 +4572,7 @@ class Registrar:
                 properties=feature_properties,
                 additional_parameters=additional_Parameters,
                 resource_snowflake_config=feature.get("resource_snowflake_config"),
+                expectations=feature.get("expectations") or [],
             )
             self.__resources.append(resource)
             self.map_client_object_to_resource(client_object, resource)
@@ -4749,6 +4773,8 @@ class Registrar:
         provider: str = "",
         resource_snowflake_config: Optional[ResourceSnowflakeConfig] = None,
         type: TrainingSetType = TrainingSetType.DYNAMIC,
+        expectations: Optional[List[Expectation]] = None,
+        snapshot_policy: Optional[SnapshotPolicy] = None,
     ):
         """Register a training set.
 
@@ -4772,6 +4798,8 @@ class Registrar:
             schedule (str): Kubernetes CronJob schedule string ("* * * * *")
             tags (List[str]): Optional grouping mechanism for resources
             properties (dict): Optional grouping mechanism for resources
+            expectations (List[Expectation]): Data quality checks run on the training set after it's created. Columns are named feature__<name>__<variant> and label__<name>__<variant>.
+            snapshot_policy (SnapshotPolicy): Pin the data each run reads and writes, so the training set can be re-created and served exactly as it was
 
         Returns:
             resource (ResourceRegistrar): resource
@@ -4847,6 +4875,8 @@ class Registrar:
             provider=provider,
             resource_snowflake_config=resource_snowflake_config,
             type=type,
+            expectations=expectations or [],
+            snapshot_policy=snapshot_policy,
         )
         self.map_client_object_to_resource(resource, resource)
         self.__resources.append(resource)
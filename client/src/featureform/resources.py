@@ -1068,6 +1068,7 @@ class SnowflakeDynamicTableConfig:
 class ResourceSnowflakeConfig:
     dynamic_table_config: Optional[SnowflakeDynamicTableConfig] = None
     warehouse: Optional[str] = None
+    refresh_strategy: Optional[SnowflakeRefreshStrategy] = None
 
     def config(self) -> dict:
         return {
@@ -1077,6 +1078,9 @@ class ResourceSnowflakeConfig:
                 else None
             ),
             "Warehouse": self.warehouse,
+            "RefreshStrategy": (
+                self.refresh_strategy.to_string() if self.refresh_strategy else ""
+            ),
         }
 
     def to_proto(self):
@@ -1087,6 +1091,9 @@ class ResourceSnowflakeConfig:
                 else None
             ),
             warehouse=self.warehouse,
+            refresh_strategy=(
+                self.refresh_strategy.to_proto() if self.refresh_strategy else None
+            ),
         )
 
     @classmethod
@@ -1112,6 +1119,9 @@ class ResourceSnowflakeConfig:
                 else None
             ),
             warehouse=config.warehouse,
+            refresh_strategy=SnowflakeRefreshStrategy.from_proto(
+                config.refresh_strategy
+            ),
         )
 
 
@@ -1323,6 +1333,8 @@ class SparkConfig:
     store_type: str
     store_config: dict
     catalog: Optional[dict] = None
+    # "delta" or "iceberg" writes resources to the filestore as tables; ignored with a catalog.
+    table_format: str = ""
 
     def software(self) -> str:
         return "spark"
@@ -1341,6 +1353,9 @@ class SparkConfig:
         if self.catalog is not None:
             config["GlueConfig"] = self.catalog  # change to catalog later
 
+        if self.table_format:
+            config["FileStoreTableFormat"] = self.table_format
+
         return bytes(json.dumps(config), "utf-8")
 
     @classmethod
@@ -1354,6 +1369,7 @@ class SparkConfig:
                 store_type=deserialized_config["StoreType"],
                 store_config=deserialized_config["StoreConfig"],
                 catalog=deserialized_config.get("GlueConfig"),
+                table_format=deserialized_config.get("FileStoreTableFormat", ""),
             )
         except KeyError as e:
             raise ValueError(f"Missing expected config key: {e}")
@@ -1393,6 +1409,8 @@ class K8sConfig:
     store_type: str
     store_config: dict
     docker_image: str = ""
+    # "delta" writes resources to the filestore as Delta tables.
+    table_format: str = ""
 
     def software(self) -> str:
         return "k8s"
@@ -1407,6 +1425,8 @@ class K8sConfig:
             "StoreType": self.store_type,
             "StoreConfig": self.store_config,
         }
+        if self.table_format:
+            config["FileStoreTableFormat"] = self.table_format
         return bytes(json.dumps(config), "utf-8")
 
 
@@ -1737,11 +1757,16 @@ class ResourceVariant(HasNameVariant, Protocol):
 class PrimaryData:
     location: Location
     timestamp_column: str = ""
+    allow_added_columns: bool = False
 
     def kwargs(self) -> Dict[str, Any]:
         primary_data_kwargs = {
             "timestamp_column": self.timestamp_column,
         }
+        if self.allow_added_columns:
+            primary_data_kwargs["schema_drift_policy"] = (
+                pb.SchemaDriftPolicy.SCHEMA_DRIFT_ALLOW_ADDED_COLUMNS
+            )
 
         if isinstance(self.location, SQLTable):
             primary_data_kwargs["table"] = pb.SQLTable(
@@ -1781,7 +1806,164 @@ class PrimaryData:
         else:
             raise Exception(f"Invalid primary data type {source_primary_data}")
 
-        return PrimaryData(location, source_primary_data.timestamp_column)
+        return PrimaryData(
+            location,
+            source_primary_data.timestamp_column,
+            allow_added_columns=source_primary_data.schema_drift_policy
+            == pb.SchemaDriftPolicy.SCHEMA_DRIFT_ALLOW_ADDED_COLUMNS,
+        )
+
+
+@typechecked
+@dataclass
+class Expectation:
+    """A data quality check run after each run of a source, after a training set is created and
+    before a feature is materialized. A failing blocking expectation fails the run; other failures
+    are only recorded on it.
+
+    Use the constructors rather than creating one directly:
+
+    ``` py
+    expectations = [
+        ff.Expectation.null_fraction("few_missing_amounts", 0.01, column="amount", blocking=True),
+        ff.Expectation.range("positive_amounts", min=0, column="amount"),
+        ff.Expectation.unique("one_row_per_transaction", columns=["customer", "ts"]),
+        ff.Expectation.row_count_change("stable_size", 0.2),
+    ]
+    ```
+
+    Columns default to the value column, or the entity and timestamp columns, for features.
+    """
+
+    name: str
+    type: int
+    blocking: bool = False
+    column: str = ""
+    columns: List[str] = field(default_factory=list)
+    min: float = float("-inf")
+    max: float = float("inf")
+    max_null_fraction: float = 0.0
+    max_row_count_change: float = 0.0
+
+    @staticmethod
+    def null_fraction(
+        name: str, max_fraction: float, column: str = "", blocking: bool = False
+    ) -> "Expectation":
+        return Expectation(
+            name=name,
+            type=pb.ExpectationType.EXPECT_NULL_FRACTION,
+            blocking=blocking,
+            column=column,
+            max_null_fraction=max_fraction,
+        )
+
+    @staticmethod
+    def range(
+        name: str,
+        min: float = float("-inf"),
+        max: float = float("inf"),
+        column: str = "",
+        blocking: bool = False,
+    ) -> "Expectation":
+        return Expectation(
+            name=name,
+            type=pb.ExpectationType.EXPECT_RANGE,
+            blocking=blocking,
+            column=column,
+            min=min,
+            max=max,
+        )
+
+    @staticmethod
+    def unique(
+        name: str, columns: Optional[List[str]] = None, blocking: bool = False
+    ) -> "Expectation":
+        return Expectation(
+            name=name,
+            type=pb.ExpectationType.EXPECT_UNIQUE,
+            blocking=blocking,
+            columns=columns or [],
+        )
+
+    @staticmethod
+    def row_count_change(
+        name: str, max_change: float, blocking: bool = False
+    ) -> "Expectation":
+        return Expectation(
+            name=name,
+            type=pb.ExpectationType.EXPECT_ROW_COUNT_CHANGE,
+            blocking=blocking,
+            max_row_count_change=max_change,
+        )
+
+    def to_proto(self) -> pb.Expectation:
+        return pb.Expectation(
+            name=self.name,
+            type=self.type,
+            blocking=self.blocking,
+            column=self.column,
+            columns=self.columns,
+            min=self.min,
+            max=self.max,
+            max_null_fraction=self.max_null_fraction,
+            max_row_count_change=self.max_row_count_change,
+        )
+
+    @staticmethod
+    def from_proto(expectation: pb.Expectation) -> "Expectation":
+        return Expectation(
+            name=expectation.name,
+            type=expectation.type,
+            blocking=expectation.blocking,
+            column=expectation.column,
+            columns=list(expectation.columns),
+            min=expectation.min,
+            max=expectation.max,
+            max_null_fraction=expectation.max_null_fraction,
+            max_row_count_change=expectation.max_row_count_change,
+        )
+
+
+@typechecked
+@dataclass
+class SnapshotPolicy:
+    """Pins the data each run of a training set reads and writes, so the training set can be
+    re-created and served exactly as it was, e.g. to retrain a model on the data it was trained on.
+
+    Inputs are pinned with Snowflake Time Travel, BigQuery time travel, the Delta Lake version or
+    Iceberg snapshot of file store tables, or a copy of plain files.
+
+    ``` py
+    policy = ff.SnapshotPolicy(retention=timedelta(days=30), keep_last=3)
+    ```
+
+    A run's snapshots are released once they're older than retention, unless the run is one of the
+    keep_last newest runs. Without a retention, snapshots are kept until keep_last releases them.
+    Snowflake keeps at most 90 days of Time Travel and BigQuery at most 7.
+    """
+
+    retention: Optional[timedelta] = None
+    keep_last: int = 0
+
+    def to_proto(self) -> pb.SnapshotPolicy:
+        retention = None
+        if self.retention is not None:
+            retention = Duration()
+            retention.FromTimedelta(self.retention)
+        return pb.SnapshotPolicy(
+            enabled=True, retention=retention, keep_last=self.keep_last
+        )
+
+    @staticmethod
+    def from_proto(policy: pb.SnapshotPolicy) -> Optional["SnapshotPolicy"]:
+        if not policy.enabled:
+            return None
+        return SnapshotPolicy(
+            retention=(
+                policy.retention.ToTimedelta() if policy.HasField("retention") else None
+            ),
+            keep_last=policy.keep_last,
+        )
 
 
 class Transformation(ABC):
@@ -1985,6 +2167,7 @@ class SourceVariant(ResourceVariant):
     error: Optional[str] = None
     server_status: Optional[ServerStatus] = None
     max_job_duration: timedelta = timedelta(hours=48)
+    expectations: List[Expectation] = field(default_factory=list)
 
     def update_schedule(self, schedule) -> None:
         self.schedule_obj = Schedule(
@@ -2031,6 +2214,7 @@ class SourceVariant(ResourceVariant):
             error=source.status.error_message,
             server_status=ServerStatus.from_proto(source.status),
             max_job_duration=source.max_job_duration.ToTimedelta(),
+            expectations=[Expectation.from_proto(e) for e in source.expectations],
         )
 
     @staticmethod
@@ -2061,6 +2245,7 @@ class SourceVariant(ResourceVariant):
                 properties=Properties(self.properties).serialized,
                 status=pb.ResourceStatus(status=pb.ResourceStatus.NO_STATUS),
                 max_job_duration=duration,
+                expectations=[e.to_proto() for e in self.expectations],
                 **defArgs,
             ),
             request_id="",
@@ -2216,6 +2401,7 @@ class FeatureVariant(ResourceVariant):
     additional_parameters: Optional[Additional_Parameters] = None
     server_status: Optional[ServerStatus] = None
     resource_snowflake_config: Optional[ResourceSnowflakeConfig] = None
+    expectations: List[Expectation] = field(default_factory=list)
 
     def __post_init__(self):
         if isinstance(self.value_type, str):
@@ -2268,6 +2454,7 @@ class FeatureVariant(ResourceVariant):
             error=feature.status.error_message,
             server_status=ServerStatus.from_proto(feature.status),
             additional_parameters=None,
+            expectations=[Expectation.from_proto(e) for e in feature.expectations],
         )
 
     def _get_and_set_equivalent_variant(self, req_id, stub):
@@ -2298,6 +2485,7 @@ class FeatureVariant(ResourceVariant):
                 if self.resource_snowflake_config
                 else None
             ),
+            expectations=[e.to_proto() for e in self.expectations],
         )
 
         # Initialize the FeatureVariantRequest message with the FeatureVariant message
@@ -2912,6 +3100,8 @@ class TrainingSetVariant(ResourceVariant):
     server_status: Optional[ServerStatus] = None
     resource_snowflake_config: Optional[ResourceSnowflakeConfig] = None
     type: TrainingSetType = field(default=TrainingSetType.DYNAMIC)
+    expectations: List[Expectation] = field(default_factory=list)
+    snapshot_policy: Optional[SnapshotPolicy] = None
 
     def update_schedule(self, schedule) -> None:
         self.schedule_obj = Schedule(
@@ -2977,6 +3167,7 @@ class TrainingSetVariant(ResourceVariant):
                 ts.resource_snowflake_config
             ),
             type=TrainingSetType.from_proto(ts.type),
+            snapshot_policy=SnapshotPolicy.from_proto(ts.snapshot_policy),
         )
 
     def _get_and_set_equivalent_variant(self, req_id, stub):
@@ -3022,6 +3213,10 @@ class TrainingSetVariant(ResourceVariant):
                     else None
                 ),
                 type=self.type.to_proto(),
+                expectations=[e.to_proto() for e in self.expectations],
+                snapshot_policy=(
+                    self.snapshot_policy.to_proto() if self.snapshot_policy else None
+                ),
             ),
             request_id="",
         )
@@ -95,6 +95,7 @@ class ServingClient:
         variant="",
         include_label_timestamp=False,
         model: Union[str, Model] = None,
+        run_id: int = 0,
     ) -> "Dataset":
         """Return an iterator that iterates through the specified training set.
 
@@ -110,6 +111,7 @@ class ServingClient:
         Args:
             name (str): Name of training set to be retrieved
             variant (str): Variant of training set to be retrieved
+            run_id (int): For training sets with a snapshot policy, the run whose pinned snapshot to read; defaults to the latest
 
         Returns:
             training_set (Dataset): A training set iterator
@@ -117,7 +119,9 @@ class ServingClient:
         if isinstance(name, TrainingSetVariant):
             variant = name.variant
             name = name.name
-        return self.impl.training_set(name, variant, include_label_timestamp, model)
+        return self.impl.training_set(
+            name, variant, include_label_timestamp, model, run_id
+        )
 
     def features(
         self, features, entities, model: Union[str, Model] = None, params: list = None
@@ -192,9 +196,16 @@ class HostedClientImpl:
             return secure_channel(host, cert_path)
 
     def training_set(
-        self, name, variation, include_label_timestamp, model: Union[str, Model] = None
+        self,
+        name,
+        variation,
+        include_label_timestamp,
+        model: Union[str, Model] = None,
+        run_id: int = 0,
     ):
-        training_set_stream = TrainingSetStream(self._stub, name, variation, model)
+        training_set_stream = TrainingSetStream(
+            self._stub, name, variation, model, run_id
+        )
         return Dataset(training_set_stream)
 
     def features(
@@ -308,10 +319,13 @@ class HostedClientImpl:
 
 
 class TrainingSetStream(Iterator):
-    def __init__(self, stub, name, version, model: Union[str, Model] = None):
+    def __init__(
+        self, stub, name, version, model: Union[str, Model] = None, run_id: int = 0
+    ):
         req = serving_pb2.TrainingDataRequest()
         req.id.name = name
         req.id.version = version
+        req.run_id = run_id
         if model is not None:
             req.model.name = model if isinstance(model, str) else model.name
         self.name = name
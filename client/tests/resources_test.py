@@ -8,18 +8,21 @@
 import os.path
 import sys
 import unittest
+from datetime import timedelta
 
 sys.path.insert(0, "client/src/")
 import pytest
 from featureform.resources import (
     DailyPartition,
     DatabricksCredentials,
+    Expectation,
     FileStore,
     GlueCatalogTable,
     KafkaTopic,
     Location,
     ResourceRedefinedError,
     ResourceState,
+    SnapshotPolicy,
     RedisConfig,
     CassandraConfig,
     FirestoreConfig,
@@ -1385,3 +1388,43 @@ def test_df_source_equivalence(spark_provider):
     return df"""
 
     assert my_function.transformation.canonical_func_text == expected
+
+
+@pytest.mark.parametrize(
+    "expectation,field,value",
+    [
+        (
+            Expectation.null_fraction("few_nulls", 0.1, column="amount"),
+            "max_null_fraction",
+            0.1,
+        ),
+        (Expectation.range("positive", min=0, column="amount"), "min", 0),
+        (
+            Expectation.unique("no_duplicates", columns=["entity", "ts"]),
+            "columns",
+            ["entity", "ts"],
+        ),
+        (Expectation.row_count_change("stable", 0.5), "max_row_count_change", 0.5),
+    ],
+)
+def test_expectation_to_proto(expectation, field, value):
+    serialized = expectation.to_proto()
+    assert serialized.name == expectation.name
+    assert serialized.type == expectation.type
+    assert getattr(serialized, field) == value
+    assert Expectation.from_proto(serialized) == expectation
+
+
+@pytest.mark.parametrize(
+    "policy",
+    [
+        SnapshotPolicy(),
+        SnapshotPolicy(retention=timedelta(days=30)),
+        SnapshotPolicy(retention=timedelta(hours=12), keep_last=3),
+    ],
+)
+def test_snapshot_policy_to_proto(policy):
+    serialized = policy.to_proto()
+    assert serialized.enabled
+    assert serialized.keep_last == policy.keep_last
+    assert SnapshotPolicy.from_proto(serialized) == policy
@@ -11,7 +11,7 @@ import pytest
 from click.testing import CliRunner
 
 sys.path.insert(0, "client/src/")
-from featureform.cli import apply, version
+from featureform.cli import apply, backfill, version
 
 
 class TestApply:
@@ -92,3 +92,26 @@ class TestApply:
         assert result.exit_code == 0
         assert "Client Version:" in result.output
         assert "Cluster Version:" in result.output
+
+
+class TestBackfill:
+    def test_missing_range(self):
+        runner = CliRunner()
+        result = runner.invoke(backfill, "avg_transactions v1".split())
+        assert result.exit_code == 2
+
+    def test_invalid_start(self):
+        runner = CliRunner()
+        result = runner.invoke(
+            backfill,
+            "avg_transactions v1 --start yesterday --end 2024-02-01".split(),
+        )
+        assert result.exit_code == 2
+
+    def test_invalid_partitions_per_run(self):
+        runner = CliRunner()
+        result = runner.invoke(
+            backfill,
+            "avg_transactions v1 --start 2024-01-01 --end 2024-02-01 --partitions-per-run 0".split(),
+        )
+        assert result.exit_code == 2
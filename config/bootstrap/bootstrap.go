@@ -13,20 +13,25 @@ import (
 	"sync"
 
 	"github.com/featureform/config"
+	"github.com/featureform/db"
 	"github.com/featureform/fferr"
 	"github.com/featureform/helpers/postgres"
+	"github.com/featureform/helpers/sqlite"
 	"github.com/featureform/logging"
 	"github.com/featureform/scheduling"
 )
 
 type Initializer struct {
-	config *config.FeatureformApp
-	pg     *postgres.Pool
-	pgErr  error
-	initPg sync.Once
-	tm     scheduling.TaskMetadataManager
-	tmErr  error
-	initTm sync.Once
+	config   *config.FeatureformApp
+	pg       *postgres.Pool
+	pgErr    error
+	initPg   sync.Once
+	lite     *sqlite.DB
+	liteErr  error
+	initLite sync.Once
+	tm       scheduling.TaskMetadataManager
+	tmErr    error
+	initTm   sync.Once
 }
 
 // TODO(simba) I want to make the loger to be initialized by this object later
@@ -61,6 +66,31 @@ func (i *Initializer) GetOrCreatePostgresPool(ctx context.Context) (*postgres.Po
 	return i.pg, i.pgErr
 }
 
+// GetOrCreateSQLiteDB will open the SQLite file defined by config and bring its schema up to date.
+// Unlike Postgres, the migrations are embedded and always run since there's no separate setup step.
+func (i *Initializer) GetOrCreateSQLiteDB(ctx context.Context) (*sqlite.DB, error) {
+	logger := logging.GetLoggerFromContext(ctx)
+	i.initLite.Do(func() {
+		logger.Info("Initializing sqlite from app config")
+		if i.config.SQLite == nil {
+			i.liteErr = fferr.NewInvalidConfigf("SQLite config not set but state provider is sqlite")
+			logger.Errorw("Failed to open sqlite database", "err", i.liteErr)
+			return
+		}
+		i.lite, i.liteErr = sqlite.NewDB(ctx, *i.config.SQLite)
+		if i.liteErr != nil {
+			logger.Errorw("Failed to initialize sqlite from app config", "err", i.liteErr)
+			return
+		}
+		if i.liteErr = db.RunSQLiteMigrations(ctx, i.lite); i.liteErr != nil {
+			logger.Errorw("Failed to run sqlite migrations", "err", i.liteErr)
+			return
+		}
+		logger.Info("Successfully initialized sqlite from app config")
+	})
+	return i.lite, i.liteErr
+}
+
 // GetOrCreateTaskMetadataManager creates a TaskMetadataManager if one doesn't exist. It'll
 // re-use or initialize state providers like Postgres based on the config.
 func (i *Initializer) GetOrCreateTaskMetadataManager(ctx context.Context) (scheduling.TaskMetadataManager, error) {
@@ -81,6 +111,16 @@ func (i *Initializer) GetOrCreateTaskMetadataManager(ctx context.Context) (sched
 			}
 			logger.Debug("Got postgres connection pool")
 			i.tm, i.tmErr = scheduling.NewPSQLTaskMetadataManager(ctx, pool)
+		case config.SQLiteStateProvider:
+			logger.Debug("Initializing sqlite task manager")
+			liteDB, dbErr := i.GetOrCreateSQLiteDB(ctx)
+			if dbErr != nil {
+				logger.Errorw("Failed to get SQLite database", "err", dbErr)
+				i.tmErr = dbErr
+				return
+			}
+			logger.Debug("Got sqlite database")
+			i.tm, i.tmErr = scheduling.NewSQLiteTaskMetadataManager(ctx, liteDB)
 		default:
 			errMsg := fmt.Sprintf(
 				"Unable to initialize task manager, unknown state provider type: %s",
@@ -104,5 +144,8 @@ func (i *Initializer) Close() error {
 	if i.pg != nil {
 		i.pg.Close()
 	}
+	if i.lite != nil {
+		i.lite.Close()
+	}
 	return nil
 }
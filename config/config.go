@@ -18,6 +18,7 @@ import (
 	"github.com/featureform/fferr"
 	"github.com/featureform/helpers"
 	"github.com/featureform/helpers/postgres"
+	"github.com/featureform/helpers/sqlite"
 	"github.com/featureform/logging"
 )
 
@@ -52,6 +53,8 @@ const (
 	EnvMaterializeWithTimestampQueryPath = "MATERIALIZE_WITH_TIMESTAMP_QUERY_PATH"
 	EnvFFStateProvider                   = "FF_STATE_PROVIDER"
 	EnvSlackChannelId                    = "SLACK_CHANNEL_ID"
+	EnvNotificationsConfigPath           = "NOTIFICATIONS_CONFIG_PATH"
+	EnvProfileDatasets                   = "PROFILE_DATASETS"
 	EnvFFInitTimeout                     = "FF_INIT_TIMEOUT"
 )
 
@@ -160,6 +163,18 @@ func GetSlackChannelId() string {
 	return helpers.GetEnv("SLACK_CHANNEL_ID", "") //no meaningful fallback ID
 }
 
+// GetNotificationsConfigPath returns the JSON file that configures task run notification sinks and
+// rules. Task run notifications are disabled when it's empty.
+func GetNotificationsConfigPath() string {
+	return helpers.GetEnv(EnvNotificationsConfigPath, "")
+}
+
+// ShouldProfileDatasets reports whether feature materializations and training sets are profiled
+// after each run. Profiling scans the data, so large deployments may want to turn it off.
+func ShouldProfileDatasets() bool {
+	return helpers.GetEnvBool(EnvProfileDatasets, true)
+}
+
 func GetIcebergProxyHost() string {
 	return helpers.GetEnv("ICEBERG_PROXY_HOST", "localhost")
 }
@@ -174,10 +189,11 @@ const (
 	StateProviderNIL      StateProviderType = ""
 	NoStateProvider       StateProviderType = "memory"
 	PostgresStateProvider StateProviderType = "psql"
+	SQLiteStateProvider   StateProviderType = "sqlite"
 )
 
 var AllStateProviderTypes = []StateProviderType{
-	NoStateProvider, PostgresStateProvider,
+	NoStateProvider, PostgresStateProvider, SQLiteStateProvider,
 }
 
 var cached *FeatureformApp
@@ -263,6 +279,9 @@ func parseStateProvider(logger logging.Logger, cfg *FeatureformApp) fferr.Error
 			return err
 		}
 		cfg.Postgres = psqlCfg
+	case SQLiteStateProvider:
+		logger.Debug("Parsing SQLite config from env")
+		cfg.SQLite = parseSQLite(stateLogger)
 	default:
 		stateLogger.Errorw("Invalid state provider")
 		return fferr.NewInvalidConfigEnv(
@@ -306,6 +325,15 @@ func parsePostgres(logger logging.Logger) (*postgres.Config, fferr.Error) {
 	return &cfg, nil
 }
 
+func parseSQLite(logger logging.Logger) *sqlite.Config {
+	logger.Debugw("Parsing SQLite config from env.")
+	cfg := sqlite.Config{
+		Path: getEnvWithDefault(logger, "SQLITE_PATH", "featureform.db"),
+	}
+	logger.Infow("SQLite config parsed from env", "config", cfg.Redacted())
+	return &cfg
+}
+
 func parseSchedulerConfig(logger logging.Logger, cfg *FeatureformApp) fferr.Error {
 	defaultEnvs := map[string]string{
 		"FF_TASK_POLL_INTERVAL":        "1s",
@@ -368,6 +396,8 @@ type FeatureformApp struct {
 	StateProviderType StateProviderType
 	// This will only be set when StateProviderType is PostgresStateProvider
 	Postgres *postgres.Config
+	// This will only be set when StateProviderType is SQLiteStateProvider
+	SQLite *sqlite.Config
 	// Scheduler Configuration
 	SchedulerTaskPollInterval         time.Duration
 	SchedulerTaskStatusSyncInterval   time.Duration
@@ -21,8 +21,11 @@ import (
 	"github.com/featureform/fferr"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
+	"github.com/featureform/metrics"
 	"github.com/featureform/scheduling"
+	"github.com/featureform/tracing"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type ExecutorConfig struct {
@@ -40,6 +43,17 @@ type Executor struct {
 // We should only need to pass the runID here, but the way the data is stored doesn't allow that atm
 // without searching through all tasks
 func (e *Executor) RunTask(ctx context.Context, sid ct.SchedulerID, tid scheduling.TaskID, rid scheduling.TaskRunID) error {
+	ctx, span := tracing.Start(ctx, "task_run",
+		tracing.SchedulerIDKey.String(string(sid)),
+		tracing.TaskIDKey.String(tid.String()),
+		tracing.RunIDKey.String(rid.String()),
+	)
+	err := e.runTask(ctx, sid, tid, rid)
+	tracing.End(span, err)
+	return err
+}
+
+func (e *Executor) runTask(ctx context.Context, sid ct.SchedulerID, tid scheduling.TaskID, rid scheduling.TaskRunID) error {
 	logger := e.logger.With("execution_id", uuid.NewString(), "scheduler_id", sid, "task_id", tid, "run_id", rid)
 	logger.Debug("Checking if task is lockable")
 	unlockTask, err := e.locker.LockTask(tid, false)
@@ -84,6 +98,10 @@ func (e *Executor) RunTask(ctx context.Context, sid ct.SchedulerID, tid scheduli
 	}
 
 	logger = logger.With("target", run.Target)
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(tracing.TargetKey.String(fmt.Sprint(run.Target)))
+	// The run executes outside the request that created it, link back to that request's trace.
+	tracing.LinkTo(span, run.TraceParent)
 	logger.Debugw("Task run started", "run", run)
 
 	// Stop attempting to run the run
@@ -108,9 +126,6 @@ func (e *Executor) RunTask(ctx context.Context, sid ct.SchedulerID, tid scheduli
 		return err
 	}
 
-	//logger.Debug("Checking for cancel signal")
-	//cancel, waitErr := e.metadata.Tasks.WatchForCancel(tid, rid)
-
 	var lastSuccessfulRun scheduling.TaskRunMetadata
 
 	isUpdate := false
@@ -125,7 +140,11 @@ func (e *Executor) RunTask(ctx context.Context, sid ct.SchedulerID, tid scheduli
 		isUpdate = true
 	}
 
-	task, err := e.getTaskRunner(run, lastSuccessfulRun, isUpdate, logger)
+	// The task and the cancel watch run under runCtx, so both stop when the run is cancelled or
+	// finishes.
+	runCtx, stopRun := context.WithCancel(ctx)
+	defer stopRun()
+	task, err := e.getTaskRunner(runCtx, run, lastSuccessfulRun, isUpdate, logger)
 	if err != nil {
 		return err
 	}
@@ -158,32 +177,77 @@ func (e *Executor) RunTask(ctx context.Context, sid ct.SchedulerID, tid scheduli
 	logger.Info("Set run status to running")
 
 	logger.Info("Starting Run")
+	runStart := time.Now()
 	runErrChan := e.Run(task)
 
-	// Disabling the cancel for now since we don't currently support it all the way and was running into panics
-	select {
-	//case <-cancel:
-	//	logger.Info("Run Cancelled")
-	//	return e.handleRunStatus(tid, rid, scheduling.CANCELLED, nil)
-	//
-	//case err := <-waitErr:
-	//	logger.Errorf("Recieved error while watching for cancel: %s", err.Error())
-	//	return err
-
-	case err := <-runErrChan:
-		if err != nil {
-			logger.Errorf("Run Failed: %s", err.Error())
-			if err := e.handleRunStatus(tid, rid, scheduling.FAILED, err); err != nil {
+	logger.Debug("Watching for cancel signal")
+	cancel, waitErr := e.metadata.Tasks.WatchForCancel(runCtx, tid, rid)
+	for {
+		select {
+		case <-cancel:
+			logger.Info("Run Cancelled")
+			metrics.Instrument().ObserveTaskRun(taskRunType(run), scheduling.CANCELLED.String(), time.Since(runStart))
+			return e.cancelRun(task, stopRun, runErrChan, tid, rid, logger)
+
+		case err := <-waitErr:
+			// The run can still finish, it just can't be cancelled anymore.
+			logger.Warnw("Stopped watching for cancel signal", "error", err)
+			cancel, waitErr = nil, nil
+
+		case err := <-runErrChan:
+			metrics.Instrument().ObserveTaskRun(taskRunType(run), runStatus(err).String(), time.Since(runStart))
+			if err != nil {
+				logger.Errorf("Run Failed: %s", err.Error())
+				if err := e.handleRunStatus(tid, rid, scheduling.FAILED, err); err != nil {
+					logger.Error(err.Error())
+				}
+				return fferr.NewTaskRunFailedError(tid.String(), rid.String(), err)
+			}
+			logger.Info("Run Ready")
+			if err := e.handleRunStatus(tid, rid, scheduling.READY, err); err != nil {
 				logger.Error(err.Error())
 			}
-			return fferr.NewTaskRunFailedError(tid.String(), rid.String(), err)
+			return nil
 		}
-		logger.Info("Run Ready")
-		if err := e.handleRunStatus(tid, rid, scheduling.READY, err); err != nil {
-			logger.Error(err.Error())
+	}
+}
+
+// cancelStopTimeout is how long cancelRun waits for a cancelled run's task to stop.
+var cancelStopTimeout = time.Minute
+
+// cancelRun stops a cancelled run's task, along with the work it started if the task supports
+// it, then ends the run. The task is stopped first so that it can't write its results after the
+// run ends. The run's status is already CANCELLED, so it isn't set again.
+func (e *Executor) cancelRun(task tasks.Task, stopRun context.CancelFunc, runErrChan chan error, tid scheduling.TaskID, rid scheduling.TaskRunID, logger logging.Logger) error {
+	stopRun()
+	if cancellable, ok := task.(tasks.Cancellable); ok {
+		if err := cancellable.Cancel(); err != nil {
+			logger.Errorw("Failed to cancel task", "error", err)
 		}
-		return nil
 	}
+	select {
+	case err := <-runErrChan:
+		logger.Infow("Cancelled task stopped", "error", err)
+	case <-time.After(cancelStopTimeout):
+		logger.Warnw("Cancelled task didn't stop in time", "timeout", cancelStopTimeout)
+	}
+	return e.metadata.Tasks.EndRun(tid, rid)
+}
+
+// taskRunType is the metrics label for a run: the resource type for resource runs and the
+// target type otherwise.
+func taskRunType(run scheduling.TaskRunMetadata) string {
+	if nv, ok := run.Target.(scheduling.NameVariant); ok {
+		return nv.ResourceType
+	}
+	return run.TargetType.String()
+}
+
+func runStatus(err error) scheduling.Status {
+	if err != nil {
+		return scheduling.FAILED
+	}
+	return scheduling.READY
 }
 
 func (e *Executor) handleRunStatus(tid scheduling.TaskID, rid scheduling.TaskRunID, status scheduling.Status, err error) error {
@@ -213,12 +277,12 @@ func (e *Executor) Run(task tasks.Task) chan error {
 	return errChan
 }
 
-func (e *Executor) getTaskRunner(runMetadata scheduling.TaskRunMetadata, lastSuccessfulRun scheduling.TaskRunMetadata, isUpdate bool, logger logging.Logger) (tasks.Task, error) {
+func (e *Executor) getTaskRunner(ctx context.Context, runMetadata scheduling.TaskRunMetadata, lastSuccessfulRun scheduling.TaskRunMetadata, isUpdate bool, logger logging.Logger) (tasks.Task, error) {
 	logger.Infow("getTaskRunner", "last task", lastSuccessfulRun)
 	taskConfig := tasks.TaskConfig{
 		DependencyPollInterval: e.config.DependencyPollInterval,
 	}
-	baseTask := tasks.NewBaseTask(e.metadata, runMetadata, lastSuccessfulRun, isUpdate, runMetadata.IsDelete, e.spawner, logger, taskConfig)
+	baseTask := tasks.NewBaseTask(e.metadata, runMetadata, lastSuccessfulRun, isUpdate, runMetadata.IsDelete, e.spawner, logger, taskConfig).WithContext(ctx)
 	e.logger.Infow("Base task created", "task", baseTask.Redacted())
 	return tasks.Get(runMetadata.TargetType, baseTask)
 }
@@ -21,6 +21,7 @@ import (
 	ct "github.com/featureform/coordinator/types"
 	"github.com/featureform/fferr"
 	"github.com/featureform/ffsync"
+	fftypes "github.com/featureform/fftypes"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
 	"github.com/featureform/provider"
@@ -65,48 +66,51 @@ func (l *MyMockedLocker) Close() {
 
 type MyMockedTaskClient struct {
 	mock.Mock
+	// watchCtx is the context of the latest WatchForCancel call.
+	watchCtx context.Context
 }
 
-func (m MyMockedTaskClient) CreateRun(name string, id s.TaskID, trigger s.Trigger) (s.TaskRunID, error) {
+func (m *MyMockedTaskClient) CreateRun(name string, id s.TaskID, trigger s.Trigger) (s.TaskRunID, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m MyMockedTaskClient) SyncUnfinishedRuns() error {
+func (m *MyMockedTaskClient) SyncUnfinishedRuns() error {
 	return nil
 }
 
-func (m MyMockedTaskClient) GetUnfinishedRuns() (s.TaskRunList, error) {
+func (m *MyMockedTaskClient) GetUnfinishedRuns() (s.TaskRunList, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m MyMockedTaskClient) GetTaskByID(id s.TaskID) (s.TaskMetadata, error) {
+func (m *MyMockedTaskClient) GetTaskByID(id s.TaskID) (s.TaskMetadata, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m MyMockedTaskClient) WatchForCancel(tid s.TaskID, id s.TaskRunID) (chan s.Status, chan error) {
+func (m *MyMockedTaskClient) WatchForCancel(ctx context.Context, tid s.TaskID, id s.TaskRunID) (chan s.Status, chan error) {
+	m.watchCtx = ctx
 	args := m.Called(tid, id)
 	return args.Get(0).(chan s.Status), args.Get(1).(chan error)
 }
 
-func (m MyMockedTaskClient) GetAllRuns() (s.TaskRunList, error) {
+func (m *MyMockedTaskClient) GetAllRuns() (s.TaskRunList, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m MyMockedTaskClient) GetRuns(id s.TaskID) (s.TaskRunList, error) {
+func (m *MyMockedTaskClient) GetRuns(id s.TaskID) (s.TaskRunList, error) {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m MyMockedTaskClient) GetRun(tid s.TaskID, id s.TaskRunID) (s.TaskRunMetadata, error) {
+func (m *MyMockedTaskClient) GetRun(tid s.TaskID, id s.TaskRunID) (s.TaskRunMetadata, error) {
 	args := m.Called(tid, id)
 	return args.Get(0).(s.TaskRunMetadata), args.Error(1)
 }
 
-func (m MyMockedTaskClient) GetLatestRun(id s.TaskID) (s.TaskRunMetadata, error) {
+func (m *MyMockedTaskClient) GetLatestRun(id s.TaskID) (s.TaskRunMetadata, error) {
 	//TODO implement me
 	panic("implement me")
 }
@@ -116,17 +120,42 @@ func (m *MyMockedTaskClient) SetRunStatus(tid s.TaskID, rid s.TaskRunID, status
 	return args.Error(0)
 }
 
-func (m MyMockedTaskClient) AddRunLog(taskID s.TaskID, runID s.TaskRunID, msg string) error {
+func (m *MyMockedTaskClient) AddRunLog(taskID s.TaskID, runID s.TaskRunID, msg string) error {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m MyMockedTaskClient) SetRunResumeID(taskID s.TaskID, runID s.TaskRunID, resumeID ptypes.ResumeID) error {
+func (m *MyMockedTaskClient) SetRunResumeID(taskID s.TaskID, runID s.TaskRunID, resumeID ptypes.ResumeID) error {
 	//TODO implement me
 	panic("implement me")
 }
 
-func (m MyMockedTaskClient) EndRun(tid s.TaskID, rid s.TaskRunID) error {
+func (m *MyMockedTaskClient) SetRunWatermarks(taskID s.TaskID, runID s.TaskRunID, watermarks map[string]time.Time) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MyMockedTaskClient) SetRunSchemaContract(taskID s.TaskID, runID s.TaskRunID, contract *s.SchemaContract) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MyMockedTaskClient) SetRunExpectationResults(taskID s.TaskID, runID s.TaskRunID, results []s.ExpectationResult) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MyMockedTaskClient) SetRunProfile(taskID s.TaskID, runID s.TaskRunID, profile *fftypes.DatasetProfile) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MyMockedTaskClient) SetRunSnapshots(taskID s.TaskID, runID s.TaskRunID, snapshots []fftypes.DataSnapshot) error {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (m *MyMockedTaskClient) EndRun(tid s.TaskID, rid s.TaskRunID) error {
 	args := m.Called(tid, rid)
 	return args.Error(0)
 }
@@ -457,45 +486,69 @@ func setDependenciesToReady(t *testing.T, taskClient *MyMockedTaskClient) {
 	).Return(nil)
 }
 
+// blockingTargetType is registered with a factory that returns a blockingTask.
+const blockingTargetType s.TargetType = 100
+
+// blockingTask runs until it's cancelled.
+type blockingTask struct {
+	cancelled chan struct{}
+	stopped   chan struct{}
+}
+
+func (b *blockingTask) Run() error {
+	defer close(b.stopped)
+	<-b.cancelled
+	return fmt.Errorf("cancelled")
+}
+
+func (b *blockingTask) Cancel() error {
+	close(b.cancelled)
+	return nil
+}
+
 // TestExecutorCancelTask tests behavior when a task is cancelled.
 func TestExecutorCancelTask(t *testing.T) {
-	t.Skip("Need to resolve a nil pointer error with the channel")
 	locker := new(MyMockedLocker)
 	taskClient := new(MyMockedTaskClient)
 	ctx, logger := logging.NewTestContextAndLogger(t)
 
+	task := &blockingTask{cancelled: make(chan struct{}), stopped: make(chan struct{})}
+	if err := tasks.RegisterFactory(blockingTargetType, func(tasks.BaseTask) (tasks.Task, error) { return task, nil }); err != nil {
+		t.Fatalf("Failed to register factory: %v", err)
+	}
+
 	locker.On("Lock", "/tasklock/1", false).Return(nil)
 	locker.On("Lock", "/runlock/1", false).Return(nil)
 
-	setDependenciesToReady(t, taskClient)
-
-	statusChan := make(chan s.Status)
-	errorChan := make(chan error)
-	// Set status of dependent run to READY
-	taskClient.On(
-		"WatchForCancel",
-		s.TaskID(uintID(1)),
-		s.TaskRunID(uintID(1)),
-	).Return(statusChan, errorChan).Once()
+	taskDag := createDag(t)
+	taskClient.On("GetRun", s.TaskID(uintID(1)), s.TaskRunID(uintID(1))).Return(s.TaskRunMetadata{
+		ID:         s.TaskRunID(uintID(1)),
+		TaskId:     s.TaskID(uintID(1)),
+		Dag:        taskDag,
+		Status:     s.PENDING,
+		TargetType: blockingTargetType,
+	}, nil)
+	taskClient.On("GetRun", s.TaskID(uintID(1)), s.TaskRunID(uintID(2))).Return(s.TaskRunMetadata{
+		ID:     s.TaskID(uintID(2)),
+		Status: s.READY,
+	}, nil)
+	taskClient.On("SetRunStatus", s.TaskID(uintID(1)), s.TaskRunID(uintID(1)), s.RUNNING).Return(nil)
 
-	// Expect status change to be CANCELLED
-	taskClient.On(
-		"SetRunStatus",
-		s.TaskID(uintID(1)),
-		s.TaskRunID(uintID(1)),
-		s.CANCELLED,
-	).Return(nil)
+	statusChan := make(chan s.Status, 1)
+	errorChan := make(chan error, 1)
+	taskClient.On("WatchForCancel", s.TaskID(uintID(1)), s.TaskRunID(uintID(1))).Return(statusChan, errorChan).Once()
 
-	taskClient.On(
-		"EndRun",
-		s.TaskID(uintID(1)),
-		s.TaskRunID(uintID(1)),
-	).Return(nil)
+	// The run is already CANCELLED, so the executor only ends it, once the task has stopped.
+	taskClient.On("EndRun", s.TaskID(uintID(1)), s.TaskRunID(uintID(1))).Return(nil).Once().Run(func(mock.Arguments) {
+		select {
+		case <-task.stopped:
+		default:
+			t.Errorf("Expected the task to stop before the run ends")
+		}
+	})
 
 	// Send a Cancel signal
-	go func() {
-		statusChan <- s.CANCELLED
-	}()
+	statusChan <- s.CANCELLED
 
 	client := metadata.Client{
 		Logger: logger,
@@ -507,6 +560,13 @@ func TestExecutorCancelTask(t *testing.T) {
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
+	select {
+	case <-task.cancelled:
+	default:
+		t.Errorf("Expected task to be cancelled")
+	}
+	taskClient.AssertNotCalled(t, "SetRunStatus", s.TaskID(uintID(1)), s.TaskRunID(uintID(1)), s.CANCELLED)
+	taskClient.AssertExpectations(t)
 }
 
 // TestExecutorSucceedTask tests behavior when a task is successfully executed.
@@ -549,6 +609,9 @@ func TestExecutorSucceedTask(t *testing.T) {
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
+	if taskClient.watchCtx.Err() == nil {
+		t.Errorf("Expected watching for cancel to stop when the run finishes")
+	}
 }
 
 func startServ(ctx context.Context, t *testing.T) (*metadata.MetadataServer, string) {
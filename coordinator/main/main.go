@@ -25,6 +25,8 @@ import (
 	help "github.com/featureform/helpers"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
+	"github.com/featureform/metrics"
+	"github.com/featureform/tracing"
 )
 
 func main() {
@@ -34,6 +36,18 @@ func main() {
 	useK8sRunner := help.GetEnv("K8S_RUNNER_ENABLE", "false")
 	logger := logging.NewLogger("coordinator")
 	defer logger.Sync()
+	shutdownTracing, err := tracing.Init(context.Background(), "coordinator")
+	if err != nil {
+		logger.Panicw("Failed to configure tracing", "Err", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Errorw("Failed to flush traces", "Err", err)
+		}
+	}()
+	if err := metrics.EnableInstrumentationFromEnv(); err != nil {
+		logger.Panicw("Failed to register metrics", "Err", err)
+	}
 	logger.Info("Parsing Featureform App Config")
 	appConfig, err := config.Get(logger)
 	if err != nil {
@@ -78,7 +92,13 @@ func main() {
 
 	apiStatusPort := help.GetEnv("API_STATUS_PORT", "8443")
 	logger.Infow("Retrieved API status port from ENV", "port", apiStatusPort)
-	if err = health.StartHttpServer(logger, apiStatusPort); err != nil {
+	checks := []health.Check{
+		health.MetadataStorageCheck(manager.Storage),
+		health.LockerCheck(manager.Storage.Locker),
+		health.TaskManagerCheck(manager),
+		health.GRPCPeerCheck("metadata", metadataUrl),
+	}
+	if err = health.StartHttpServer(logger, apiStatusPort, checks...); err != nil {
 		logger.Errorw("Failed to start health check", "err", err)
 		panic(err)
 	}
@@ -96,6 +116,8 @@ func main() {
 		TaskDistributionInterval: appConfig.SchedulerTaskDistributionInterval,
 	}
 
+	health.NewProviderMonitor(client, logger).Start(context.Background())
+
 	logger.Info("Dependencies created. Starting Scheduler...")
 	hostname, err := os.Hostname()
 	if err != nil {
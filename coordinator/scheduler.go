@@ -18,6 +18,7 @@ import (
 	"github.com/featureform/ffsync"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
+	"github.com/featureform/metrics"
 	"github.com/featureform/scheduling"
 )
 
@@ -75,6 +76,8 @@ func (c *Scheduler) Start(ctx context.Context) error {
 		c.Logger.Debugf("Fetched all unfinished runs: %v", runs)
 		if err != nil {
 			c.Logger.Error(err.Error())
+		} else {
+			metrics.Instrument().SetUnfinishedRuns(len(runs))
 		}
 
 		for _, run := range stochasticFilter.filterRuns(runs) {
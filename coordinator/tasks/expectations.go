@@ -0,0 +1,171 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package tasks
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/logging"
+	"github.com/featureform/metadata"
+	pb "github.com/featureform/metadata/proto"
+	"github.com/featureform/provider"
+	"github.com/featureform/provider/dataset"
+	"github.com/featureform/scheduling"
+)
+
+// expectationDefaults fill in the columns an expectation leaves empty, e.g. a feature's value,
+// entity and timestamp columns.
+type expectationDefaults struct {
+	column  string
+	columns []string
+}
+
+func toProviderExpectations(expectations []*pb.Expectation, defaults expectationDefaults) ([]provider.Expectation, error) {
+	converted := make([]provider.Expectation, len(expectations))
+	for i, e := range expectations {
+		exp := provider.Expectation{
+			Name:    e.GetName(),
+			Column:  e.GetColumn(),
+			Columns: e.GetColumns(),
+			Min:     e.GetMin(),
+			Max:     e.GetMax(),
+		}
+		if exp.Column == "" {
+			exp.Column = defaults.column
+		}
+		if len(exp.Columns) == 0 {
+			exp.Columns = defaults.columns
+		}
+		switch e.GetType() {
+		case pb.ExpectationType_EXPECT_NULL_FRACTION:
+			exp.Type = provider.NullFractionExpectation
+		case pb.ExpectationType_EXPECT_RANGE:
+			exp.Type = provider.RangeExpectation
+		case pb.ExpectationType_EXPECT_UNIQUE:
+			exp.Type = provider.UniqueExpectation
+		case pb.ExpectationType_EXPECT_ROW_COUNT_CHANGE:
+			exp.Type = provider.RowCountExpectation
+		default:
+			return nil, fferr.NewInvalidArgumentErrorf("expectation %s has unknown type %s", e.GetName(), e.GetType())
+		}
+		converted[i] = exp
+	}
+	return converted, nil
+}
+
+// evaluateExpectations decides whether each measurement passes its expectation. Row count changes
+// are relative to the same expectation's row count on the previous successful run; the first run
+// always passes.
+func evaluateExpectations(expectations []*pb.Expectation, measurements []provider.ExpectationMeasurement, previous []scheduling.ExpectationResult) []scheduling.ExpectationResult {
+	previousRows := make(map[string]int64, len(previous))
+	for _, result := range previous {
+		previousRows[result.Name] = result.RowCount
+	}
+	results := make([]scheduling.ExpectationResult, len(expectations))
+	for i, e := range expectations {
+		m := measurements[i]
+		result := scheduling.ExpectationResult{
+			Name:     e.GetName(),
+			Type:     e.GetType().String(),
+			Blocking: e.GetBlocking(),
+			RowCount: m.Rows,
+		}
+		switch e.GetType() {
+		case pb.ExpectationType_EXPECT_NULL_FRACTION:
+			if m.Rows > 0 {
+				result.Observed = float64(m.Violations) / float64(m.Rows)
+			}
+			result.Passed = result.Observed <= e.GetMaxNullFraction()
+			result.Message = fmt.Sprintf("%.4g of %d rows are null, expected at most %.4g", result.Observed, m.Rows, e.GetMaxNullFraction())
+		case pb.ExpectationType_EXPECT_RANGE:
+			result.Observed = float64(m.Violations)
+			result.Passed = m.Violations == 0
+			result.Message = fmt.Sprintf("%d of %d rows are outside of [%g, %g]", m.Violations, m.Rows, e.GetMin(), e.GetMax())
+		case pb.ExpectationType_EXPECT_UNIQUE:
+			result.Observed = float64(m.Violations)
+			result.Passed = m.Violations == 0
+			result.Message = fmt.Sprintf("%d of %d rows are duplicates", m.Violations, m.Rows)
+		case pb.ExpectationType_EXPECT_ROW_COUNT_CHANGE:
+			prev, has := previousRows[e.GetName()]
+			if !has || prev == 0 {
+				result.Passed = true
+				result.Message = fmt.Sprintf("%d rows, no previous run to compare to", m.Rows)
+				break
+			}
+			result.Observed = math.Abs(float64(m.Rows-prev)) / float64(prev)
+			result.Passed = result.Observed <= e.GetMaxRowCountChange()
+			result.Message = fmt.Sprintf("row count changed by %.4g from %d to %d, expected at most %.4g", result.Observed, prev, m.Rows, e.GetMaxRowCountChange())
+		}
+		results[i] = result
+	}
+	return results
+}
+
+// expectationsError returns an error listing the failed blocking expectations, or nil if all of
+// them passed.
+func expectationsError(name, variant string, resourceType fferr.ResourceType, results []scheduling.ExpectationResult) error {
+	var failures []string
+	for _, result := range results {
+		if result.Blocking && !result.Passed {
+			failures = append(failures, fmt.Sprintf("%s: %s", result.Name, result.Message))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return fferr.NewExpectationFailedError(name, variant, resourceType, failures)
+}
+
+// checkExpectations measures expectations, records the results on the run and returns an error if
+// a blocking expectation failed. measure is only called if there are expectations.
+func (bt *BaseTask) checkExpectations(
+	name, variant string,
+	resourceType fferr.ResourceType,
+	expectations []*pb.Expectation,
+	defaults expectationDefaults,
+	measure func([]provider.Expectation) ([]provider.ExpectationMeasurement, error),
+	logger logging.Logger,
+) error {
+	if len(expectations) == 0 {
+		return nil
+	}
+	if err := bt.metadata.Tasks.AddRunLog(bt.taskDef.TaskId, bt.taskDef.ID, "Checking expectations..."); err != nil {
+		logger.Warnw("Failed to add run log; continuing", "error", err)
+	}
+	converted, err := toProviderExpectations(expectations, defaults)
+	if err != nil {
+		return err
+	}
+	measurements, err := measure(converted)
+	if err != nil {
+		logger.Errorw("Failed to measure expectations", "error", err)
+		return err
+	}
+	results := evaluateExpectations(expectations, measurements, bt.lastSuccessfulTask.ExpectationResults)
+	if err := bt.metadata.Tasks.SetRunExpectationResults(bt.taskDef.TaskId, bt.taskDef.ID, results); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if !result.Passed {
+			logger.Warnw("Expectation failed", "expectation", result.Name, "blocking", result.Blocking, "message", result.Message)
+		}
+	}
+	return expectationsError(name, variant, resourceType, results)
+}
+
+// sourceDataset returns the table a source's rows are read from.
+func sourceDataset(store provider.OfflineStore, source *metadata.SourceVariant) (dataset.Dataset, error) {
+	if source.IsPrimaryData() {
+		id := provider.ResourceID{Name: source.Name(), Variant: source.Variant(), Type: provider.Primary}
+		return store.GetPrimaryTable(id, *source)
+	}
+	id := provider.ResourceID{Name: source.Name(), Variant: source.Variant(), Type: provider.Transformation}
+	return store.GetTransformationTable(id)
+}
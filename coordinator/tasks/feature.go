@@ -17,6 +17,7 @@ import (
 	"github.com/featureform/provider/provider_schema"
 
 	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
 	"github.com/featureform/filestore"
 	"github.com/featureform/helpers"
 	"github.com/featureform/metadata"
@@ -33,7 +34,7 @@ type FeatureTask struct {
 }
 
 func (t *FeatureTask) Run() error {
-	_, ctx, logger := t.logger.InitializeRequestID(context.TODO())
+	_, ctx, logger := t.logger.InitializeRequestID(t.context())
 	logger.Infow("Running Feature Task")
 	nv, ok := t.taskDef.Target.(scheduling.NameVariant)
 	if !ok {
@@ -85,6 +86,9 @@ func (t *FeatureTask) Run() error {
 	if err != nil {
 		return err
 	}
+	if err := t.checkSchemaContract(source, sourceStore, logger); err != nil {
+		return err
+	}
 
 	var inferenceStore *metadata.Provider
 	if feature.Provider() != "" {
@@ -143,6 +147,24 @@ func (t *FeatureTask) Run() error {
 	}
 	logger.Debugw("Resource Table Created")
 
+	// Blocking expectations are checked on the feature's source columns so that a failure stops
+	// the values from reaching the online store.
+	expectationColumns := []string{tmpSchema.Entity}
+	if tmpSchema.TS != "" {
+		expectationColumns = append(expectationColumns, tmpSchema.TS)
+	}
+	measure := func(expectations []provider.Expectation) ([]provider.ExpectationMeasurement, error) {
+		ds, err := sourceDataset(sourceStore, source)
+		if err != nil {
+			return nil, err
+		}
+		return provider.MeasureExpectations(ctx, sourceStore, ds, expectations)
+	}
+	defaults := expectationDefaults{column: tmpSchema.Value, columns: expectationColumns}
+	if err := t.checkExpectations(nv.Name, nv.Variant, fferr.FEATURE_VARIANT, feature.Expectations(), defaults, measure, logger); err != nil {
+		return err
+	}
+
 	maxJobDurationEnv := helpers.GetEnv("MAX_JOB_DURATION", "48h")
 	maxJobDuration, err := time.ParseDuration(maxJobDurationEnv)
 
@@ -178,6 +200,11 @@ func (t *FeatureTask) Run() error {
 		},
 	}
 
+	if backfill, isBackfill := t.taskDef.Trigger.(scheduling.BackfillTrigger); isBackfill && t.isUpdate {
+		logger.Infow("Backfilling entities with values in range", "start", backfill.Start, "end", backfill.End)
+		materializedRunnerConfig.Backfill = &runner.BackfillWindow{Start: backfill.Start, End: backfill.End}
+	}
+
 	if inferenceStore != nil {
 		materializedRunnerConfig.OnlineType = pt.Type(inferenceStore.Type())
 		materializedRunnerConfig.OnlineConfig = inferenceStore.SerializedConfig()
@@ -235,6 +262,21 @@ func (t *FeatureTask) Run() error {
 	if materializationErr != nil {
 		return materializationErr
 	}
+	t.recordProfile(func() (*fftypes.DatasetProfile, error) {
+		matID, err := provider.NewMaterializationID(providerResID)
+		if err != nil {
+			return nil, err
+		}
+		mat, err := sourceStore.GetMaterialization(matID)
+		if err != nil {
+			return nil, err
+		}
+		valueType, err := fftypes.ValueTypeFromProto(vType.ToProto())
+		if err != nil {
+			return nil, err
+		}
+		return provider.ProfileDataset(ctx, sourceStore, mat, provider.MaterializationColumns(valueType))
+	}, logger)
 
 	logger.Debugw("Setting status to ready")
 	if err := t.metadata.Tasks.AddRunLog(t.taskDef.TaskId, t.taskDef.ID, "Materialization Complete..."); err != nil {
@@ -26,7 +26,7 @@ type LabelTask struct {
 }
 
 func (t *LabelTask) Run() error {
-	_, ctx, logger := t.logger.InitializeRequestID(context.TODO())
+	_, ctx, logger := t.logger.InitializeRequestID(t.context())
 	nv, ok := t.taskDef.Target.(scheduling.NameVariant)
 	if !ok {
 		return fferr.NewInternalErrorf("cannot create a label from target type: %s", t.taskDef.TargetType)
@@ -87,6 +87,9 @@ func (t *LabelTask) Run() error {
 		}
 		logger.Debug("Closed offline store")
 	}(sourceStore, logger)
+	if err := t.checkSchemaContract(source, sourceStore, logger); err != nil {
+		return err
+	}
 	var sourceLocation pl.Location
 	var sourceLocationErr error
 	if source.IsSQLTransformation() || source.IsDFTransformation() {
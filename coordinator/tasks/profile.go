@@ -0,0 +1,35 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package tasks
+
+import (
+	"github.com/featureform/config"
+	fftypes "github.com/featureform/fftypes"
+	"github.com/featureform/logging"
+)
+
+// recordProfile profiles the data a run produced and saves the profile on the run, unless profiling
+// is turned off. Profiles are informational, so failing to compute or save one is logged rather
+// than failing the run.
+func (bt *BaseTask) recordProfile(profile func() (*fftypes.DatasetProfile, error), logger logging.Logger) {
+	if !config.ShouldProfileDatasets() {
+		logger.Debugw("Dataset profiling is disabled; skipping profile")
+		return
+	}
+	if err := bt.metadata.Tasks.AddRunLog(bt.taskDef.TaskId, bt.taskDef.ID, "Profiling data..."); err != nil {
+		logger.Warnw("Failed to add run log; continuing", "error", err)
+	}
+	p, err := profile()
+	if err != nil {
+		logger.Warnw("Failed to profile data; continuing", "error", err)
+		return
+	}
+	if err := bt.metadata.Tasks.SetRunProfile(bt.taskDef.TaskId, bt.taskDef.ID, p); err != nil {
+		logger.Warnw("Failed to save profile; continuing", "error", err)
+	}
+}
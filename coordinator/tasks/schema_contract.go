@@ -0,0 +1,157 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package tasks
+
+import (
+	"context"
+
+	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
+	"github.com/featureform/logging"
+	"github.com/featureform/metadata"
+	pb "github.com/featureform/metadata/proto"
+	"github.com/featureform/provider"
+	"github.com/featureform/provider/dataset"
+	pt "github.com/featureform/provider/provider_type"
+	"github.com/featureform/scheduling"
+)
+
+// recordSchemaContract saves the schema of a newly registered primary table on the current run so
+// that later tasks can detect when the table changes underneath them.
+func (t *SourceTask) recordSchemaContract(ds dataset.Dataset, logger logging.Logger) error {
+	if ds == nil {
+		return nil
+	}
+	schema, err := provider.ReadSchema(ds)
+	if err != nil {
+		logger.Warnw("Failed to read primary table schema; skipping schema contract", "error", err)
+		return nil
+	}
+	if len(schema.Fields) == 0 {
+		return nil
+	}
+	return t.metadata.Tasks.SetRunSchemaContract(t.taskDef.TaskId, t.taskDef.ID, scheduling.NewSchemaContract(schema))
+}
+
+// checkSchemaContracts verifies that every primary source in sources still matches the schema
+// it was registered with.
+func (t *SourceTask) checkSchemaContracts(sources []metadata.NameVariant, logger logging.Logger) error {
+	sourceVariants, err := t.metadata.GetSourceVariants(t.ctx, sources)
+	if err != nil {
+		return err
+	}
+	for _, source := range sourceVariants {
+		if !source.IsPrimaryData() {
+			continue
+		}
+		if err := t.checkSourceSchemaContract(t.ctx, source, logger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bt *BaseTask) checkSourceSchemaContract(ctx context.Context, source *metadata.SourceVariant, logger logging.Logger) error {
+	sourceProvider, err := source.FetchProvider(bt.metadata, ctx)
+	if err != nil {
+		return err
+	}
+	p, err := provider.Get(pt.Type(sourceProvider.Type()), sourceProvider.SerializedConfig())
+	if err != nil {
+		return err
+	}
+	store, err := p.AsOfflineStore()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logger.Errorw("Failed to close offline store", "error", err)
+		}
+	}()
+	return bt.checkSchemaContract(source, store, logger)
+}
+
+// checkSchemaContract compares a primary source's live schema against the contract recorded when
+// it was registered. Sources registered before contracts existed are not checked.
+func (bt *BaseTask) checkSchemaContract(source *metadata.SourceVariant, store provider.OfflineStore, logger logging.Logger) error {
+	if !source.IsPrimaryData() {
+		return nil
+	}
+	contract, err := bt.latestSchemaContract(source)
+	if err != nil {
+		return err
+	}
+	if contract == nil {
+		logger.Debugw("No schema contract recorded for source; skipping drift check", "source", source.Name(), "variant", source.Variant())
+		return nil
+	}
+	expected, err := contract.Schema()
+	if err != nil {
+		return err
+	}
+	id := provider.ResourceID{Name: source.Name(), Variant: source.Variant(), Type: provider.Primary}
+	ds, err := store.GetPrimaryTable(id, *source)
+	if err != nil {
+		return err
+	}
+	live, err := provider.ReadSchema(ds)
+	if err != nil {
+		return err
+	}
+	drift := expected.Drift(live)
+	if err := schemaDriftError(source.Name(), source.Variant(), drift, source.PrimaryDataSchemaDriftPolicy()); err != nil {
+		logger.Errorw("Source schema drifted from its contract", "source", source.Name(), "variant", source.Variant(), "drift", drift.String())
+		return err
+	}
+	if !drift.IsEmpty() {
+		logger.Infow("Source gained columns since registration", "source", source.Name(), "variant", source.Variant(), "drift", drift.String())
+	}
+	return nil
+}
+
+// latestSchemaContract returns the contract from the source's newest run that recorded one, or
+// nil if none did.
+func (bt *BaseTask) latestSchemaContract(source *metadata.SourceVariant) (*scheduling.SchemaContract, error) {
+	taskIDs, err := source.TaskIDs()
+	if err != nil {
+		return nil, err
+	}
+	var latest *scheduling.TaskRunMetadata
+	for _, taskID := range taskIDs {
+		runs, err := bt.metadata.Tasks.GetRuns(taskID)
+		if err != nil {
+			return nil, err
+		}
+		for i, run := range runs {
+			if run.SchemaContract == nil {
+				continue
+			}
+			if latest == nil || run.StartTime.After(latest.StartTime) {
+				latest = &runs[i]
+			}
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	return latest.SchemaContract, nil
+}
+
+// schemaDriftError decides whether drift breaks a source under its policy. Sources are strict by
+// default; ALLOW_ADDED_COLUMNS tolerates new columns but not removed or retyped ones.
+func schemaDriftError(name, variant string, drift fftypes.SchemaDrift, policy pb.SchemaDriftPolicy) error {
+	if drift.IsEmpty() {
+		return nil
+	}
+	if drift.IsAdditive() && policy == pb.SchemaDriftPolicy_SCHEMA_DRIFT_ALLOW_ADDED_COLUMNS {
+		return nil
+	}
+	added, removed, retyped := drift.Describe()
+	return fferr.NewSchemaDriftError(name, variant, added, removed, retyped)
+}
@@ -0,0 +1,181 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	fftypes "github.com/featureform/fftypes"
+	"github.com/featureform/logging"
+	pb "github.com/featureform/metadata/proto"
+	"github.com/featureform/provider"
+	"github.com/featureform/scheduling"
+)
+
+// toProviderSnapshotPolicy converts a training set's snapshot policy. ok is false when the
+// training set doesn't pin snapshots.
+func toProviderSnapshotPolicy(policy *pb.SnapshotPolicy) (provider.SnapshotPolicy, bool) {
+	if !policy.GetEnabled() {
+		return provider.SnapshotPolicy{}, false
+	}
+	return provider.SnapshotPolicy{
+		Enabled:   true,
+		Retention: policy.GetRetention().AsDuration(),
+		KeepLast:  int(policy.GetKeepLast()),
+	}, true
+}
+
+// pinTrainingSetInputs sets def.Snapshots to snapshots of the data the training set reads. A run
+// that re-creates a training set reads the inputs pinned by the latest run that pinned them, so
+// it's re-created from the same data. Updates, and runs with no pinned inputs to reuse, pin the
+// inputs as they are now.
+func (t *TrainingSetTask) pinTrainingSetInputs(def *provider.TrainingSetDef, store provider.SnapshotOfflineStore, policy provider.SnapshotPolicy, logger logging.Logger) error {
+	if !t.isUpdate {
+		runs, err := t.metadata.Tasks.GetRuns(t.taskDef.TaskId)
+		if err != nil {
+			logger.Errorw("Failed to get runs with pinned inputs", "error", err)
+			return err
+		}
+		if run, inputs := latestPinnedInputs(runs, t.taskDef.ID); inputs != nil {
+			logger.Infow("Reading inputs pinned by an earlier run", "run_id", run.String())
+			def.Snapshots = inputs
+			return nil
+		}
+	}
+	if err := t.metadata.Tasks.AddRunLog(t.taskDef.TaskId, t.taskDef.ID, "Pinning training set inputs..."); err != nil {
+		logger.Warnw("Failed to add run log; continuing", "error", err)
+	}
+	snapshots, err := store.PinTrainingSetInputs(*def, policy)
+	if err != nil {
+		logger.Errorw("Failed to pin training set inputs", "error", err)
+		return err
+	}
+	def.Snapshots = snapshots
+	return nil
+}
+
+// latestPinnedInputs returns the input snapshots of the latest run, other than current, that
+// pinned its inputs, if none of them have been released.
+func latestPinnedInputs(runs scheduling.TaskRunList, current scheduling.TaskRunID) (scheduling.TaskRunID, []fftypes.DataSnapshot) {
+	var latest *scheduling.TaskRunMetadata
+	for i, run := range runs {
+		if run.ID.String() == current.String() || len(inputSnapshots(run.Snapshots)) == 0 {
+			continue
+		}
+		if latest == nil || run.StartTime.After(latest.StartTime) {
+			latest = &runs[i]
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	inputs := inputSnapshots(latest.Snapshots)
+	for _, snapshot := range inputs {
+		if snapshot.Released {
+			return nil, nil
+		}
+	}
+	return latest.ID, inputs
+}
+
+func inputSnapshots(snapshots []fftypes.DataSnapshot) []fftypes.DataSnapshot {
+	inputs := make([]fftypes.DataSnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if !snapshot.Output {
+			inputs = append(inputs, snapshot)
+		}
+	}
+	return inputs
+}
+
+// pinTrainingSet pins the training set a run created, saves the run's snapshots and releases the
+// snapshots of older runs that the policy no longer keeps.
+func (t *TrainingSetTask) pinTrainingSet(def provider.TrainingSetDef, store provider.SnapshotOfflineStore, policy provider.SnapshotPolicy, logger logging.Logger) error {
+	output, err := store.PinTrainingSet(def, policy)
+	if err != nil {
+		logger.Errorw("Failed to pin training set", "error", err)
+		return err
+	}
+	snapshots := append(append(make([]fftypes.DataSnapshot, 0, len(def.Snapshots)+1), def.Snapshots...), output)
+	if err := t.metadata.Tasks.SetRunSnapshots(t.taskDef.TaskId, t.taskDef.ID, snapshots); err != nil {
+		logger.Errorw("Failed to save snapshots", "error", err)
+		return err
+	}
+	logger.Infow("Pinned training set", "snapshots", len(snapshots))
+	t.releaseExpiredSnapshots(store, policy, logger)
+	return nil
+}
+
+// releaseExpiredSnapshots releases the snapshots of runs that the policy no longer keeps and marks
+// them released on the runs. Snapshots that a kept run shares, such as inputs a re-created
+// training set reused, aren't released. Releasing only reclaims storage, so failures are logged
+// rather than failing the run.
+func (t *TrainingSetTask) releaseExpiredSnapshots(store provider.SnapshotOfflineStore, policy provider.SnapshotPolicy, logger logging.Logger) {
+	runs, err := t.metadata.Tasks.GetRuns(t.taskDef.TaskId)
+	if err != nil {
+		logger.Warnw("Failed to get runs to release snapshots of; continuing", "error", err)
+		return
+	}
+	pinned := make(scheduling.TaskRunList, 0, len(runs))
+	for _, run := range runs {
+		if hasUnreleasedSnapshots(run.Snapshots) {
+			pinned = append(pinned, run)
+		}
+	}
+	sort.Slice(pinned, func(i, j int) bool { return pinned[i].StartTime.After(pinned[j].StartTime) })
+	now := time.Now()
+	kept := make(map[string]bool)
+	expired := make(scheduling.TaskRunList, 0)
+	for i, run := range pinned {
+		if run.ID.String() == t.taskDef.ID.String() || !policy.Expired(run.StartTime, i, now) {
+			for _, snapshot := range run.Snapshots {
+				kept[snapshotKey(snapshot)] = true
+			}
+			continue
+		}
+		expired = append(expired, run)
+	}
+	for _, run := range expired {
+		release := make([]fftypes.DataSnapshot, 0, len(run.Snapshots))
+		for _, snapshot := range run.Snapshots {
+			if !snapshot.Released && !kept[snapshotKey(snapshot)] {
+				release = append(release, snapshot)
+			}
+		}
+		if err := store.ReleaseSnapshots(release); err != nil {
+			logger.Warnw("Failed to release snapshots; continuing", "run_id", run.ID.String(), "error", err)
+			continue
+		}
+		released := make([]fftypes.DataSnapshot, len(run.Snapshots))
+		for i, snapshot := range run.Snapshots {
+			snapshot.Released = true
+			released[i] = snapshot
+		}
+		if err := t.metadata.Tasks.SetRunSnapshots(t.taskDef.TaskId, run.ID, released); err != nil {
+			logger.Warnw("Failed to mark snapshots released; continuing", "run_id", run.ID.String(), "error", err)
+			continue
+		}
+		logger.Infow("Released snapshots", "run_id", run.ID.String(), "snapshots", len(release))
+	}
+}
+
+func hasUnreleasedSnapshots(snapshots []fftypes.DataSnapshot) bool {
+	for _, snapshot := range snapshots {
+		if !snapshot.Released {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotKey identifies the data a snapshot pins, so runs that share a snapshot can be found.
+func snapshotKey(snapshot fftypes.DataSnapshot) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%d", snapshot.Kind, snapshot.Location, snapshot.Version, snapshot.TableFormat, snapshot.Timestamp.UnixNano())
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/featureform/fferr"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
+	"github.com/featureform/metrics"
 	"github.com/featureform/provider"
 	pl "github.com/featureform/provider/location"
 	pc "github.com/featureform/provider/provider_config"
@@ -26,6 +27,7 @@ import (
 	ptypes "github.com/featureform/provider/types"
 	"github.com/featureform/runner"
 	"github.com/featureform/scheduling"
+	"github.com/featureform/tracing"
 )
 
 type SourceTask struct {
@@ -43,7 +45,7 @@ type tableMapping struct {
 }
 
 func (t *SourceTask) Run() error {
-	_, ctx, logger := t.logger.InitializeRequestID(context.TODO())
+	_, ctx, logger := t.logger.InitializeRequestID(t.context())
 	t.ctx = ctx
 	logger.Infow("Running source task")
 	nv, ok := t.taskDef.Target.(scheduling.NameVariant)
@@ -90,20 +92,76 @@ func (t *SourceTask) Run() error {
 		"definition", source.Definition(),
 	)
 	logger.Debug("Selecting source job type")
+	var jobErr error
 	switch {
 	case source.IsSQLTransformation():
 		logger.Info("Running SQL transformation job")
-		return t.runSQLTransformationJob(source, resID, sourceStore, logger)
+		jobErr = t.runSQLTransformationJob(source, resID, sourceStore, logger)
 	case source.IsDFTransformation():
 		logger.Info("Running DF transformation job")
-		return t.runDFTransformationJob(source, resID, sourceStore, logger)
+		jobErr = t.runDFTransformationJob(source, resID, sourceStore, logger)
 	case source.IsPrimaryData():
 		logger.Info("Running primary table job")
-		return t.runPrimaryTableJob(source, resID, sourceStore, logger)
+		jobErr = t.runPrimaryTableJob(source, resID, sourceStore, logger)
 	default:
 		logger.Error("Unknown source type")
 		return fferr.NewInternalErrorf("source type not implemented")
 	}
+	if jobErr != nil {
+		return jobErr
+	}
+	measure := func(expectations []provider.Expectation) ([]provider.ExpectationMeasurement, error) {
+		ds, err := sourceDataset(sourceStore, source)
+		if err != nil {
+			return nil, err
+		}
+		return provider.MeasureExpectations(ctx, sourceStore, ds, expectations)
+	}
+	return t.checkExpectations(source.Name(), source.Variant(), fferr.SOURCE_VARIANT, source.Expectations(), expectationDefaults{}, measure, logger)
+}
+
+// Cancel stops the transformation started by the run, if it started one and its offline store
+// can cancel transformations.
+func (t *SourceTask) Cancel() error {
+	ctx := t.context()
+	logger := t.logger.With("task_id", t.taskDef.TaskId, "task_run_id", t.taskDef.ID)
+	nv, ok := t.taskDef.Target.(scheduling.NameVariant)
+	if !ok {
+		return fferr.NewInternalErrorf("cannot cancel a source from target type: %s", t.taskDef.TargetType)
+	}
+	// The resume ID is set once the transformation starts, so it's fetched rather than read
+	// from the task definition.
+	run, err := t.metadata.Tasks.GetRun(t.taskDef.TaskId, t.taskDef.ID)
+	if err != nil {
+		logger.Errorw("Failed to get run", "error", err)
+		return err
+	}
+	if run.ResumeID == ptypes.NilResumeID {
+		logger.Info("Run has no running transformation to cancel")
+		return nil
+	}
+	source, err := t.metadata.GetSourceVariant(ctx, metadata.NameVariant{Name: nv.Name, Variant: nv.Variant})
+	if err != nil {
+		logger.Errorw("Failed to get source variant", "error", err)
+		return err
+	}
+	store, err := getOfflineStore(ctx, t.BaseTask, t.metadata, source, logger)
+	if err != nil {
+		logger.Errorw("Failed to get store", "error", err)
+		return err
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logger.Errorf("could not close offline store: %v", err)
+		}
+	}()
+	canceller, ok := store.(provider.TransformationCanceller)
+	if !ok {
+		logger.Infow("Offline store can't cancel transformations", "store", fmt.Sprintf("%T", store))
+		return nil
+	}
+	logger.Infow("Cancelling transformation", "resume_id", run.ResumeID)
+	return canceller.CancelTransformation(run.ResumeID)
 }
 
 func (t *SourceTask) handleDeletion(ctx context.Context, resID metadata.ResourceID, logger logging.Logger) error {
@@ -186,6 +244,10 @@ func (t *SourceTask) runSQLTransformationJob(
 		return err
 	}
 
+	if err := t.checkSchemaContracts(sources, logger); err != nil {
+		return err
+	}
+
 	if err := t.metadata.Tasks.AddRunLog(
 		t.taskDef.TaskId,
 		t.taskDef.ID,
@@ -200,6 +262,38 @@ func (t *SourceTask) runSQLTransformationJob(
 		return err
 	}
 
+	resourceSnowflakeConfig := &metadata.ResourceSnowflakeConfig{}
+	if offlineStore.Type() == pt.SnowflakeOffline {
+		tempConfig, err := transformSource.ResourceSnowflakeConfig()
+		if err != nil {
+			return err
+		}
+		resourceSnowflakeConfig = tempConfig
+	}
+	// Tables that Snowflake refreshes are always created from the full query.
+	refreshedBySnowflake := resourceSnowflakeConfig.RefreshedBySnowflake()
+
+	var plan *incrementalPlan
+	if !refreshedBySnowflake {
+		if plan, err = t.planIncrementalRun(transformSource, sourceTableMapping, offlineStore, logger); err != nil {
+			return err
+		}
+	}
+	if plan != nil {
+		if templateString, err = incrementalTemplate(templateString, plan.windows); err != nil {
+			return err
+		}
+	}
+	var partitionConfig *provider.IncrementalConfig
+	if plan == nil && !refreshedBySnowflake {
+		if partitionConfig, err = t.planPartitionBackfill(transformSource, offlineStore, logger); err != nil {
+			return err
+		}
+	}
+	if partitionConfig != nil {
+		templateString = partitionTemplate(templateString, *partitionConfig, offlineStore.Type())
+	}
+
 	sourceMapping, err := getSourceMapping(templateString, sourceTableMapping)
 	logger.Debugw("Source Mapping", "mapping", sourceMapping)
 	if err != nil {
@@ -215,15 +309,6 @@ func (t *SourceTask) runSQLTransformationJob(
 	// Replaces unique Featureform variables in the query; i.e. FF_LAST_RUN_TIMESTAMP will be replaced with the current epoch time
 	query = sqlVariableReplace(query, t)
 
-	resourceSnowflakeConfig := &metadata.ResourceSnowflakeConfig{}
-	if offlineStore.Type() == pt.SnowflakeOffline {
-		tempConfig, err := transformSource.ResourceSnowflakeConfig()
-		if err != nil {
-			return err
-		}
-		resourceSnowflakeConfig = tempConfig
-	}
-
 	logger.Debugw("Created SQL transformation query", "query", query)
 	providerResourceID := provider.ResourceID{Name: resID.Name, Variant: resID.Variant, Type: provider.Transformation}
 	transformationConfig := provider.TransformationConfig{
@@ -240,14 +325,349 @@ func (t *SourceTask) runSQLTransformationJob(
 		SparkFlags:              transformSource.SparkFlags(),
 		ResourceSnowflakeConfig: resourceSnowflakeConfig,
 	}
+	if plan != nil {
+		transformationConfig.Incremental = &plan.config
+	} else if partitionConfig != nil {
+		transformationConfig.Incremental = partitionConfig
+	} else if resourceSnowflakeConfig.RefreshStrategy == metadata.StreamRefresh {
+		if transformationConfig.Incremental, err = t.planStreamRefresh(transformSource, templateString, sourceTableMapping, offlineStore, logger); err != nil {
+			return err
+		}
+	}
+	if refreshedBySnowflake && t.isUpdate {
+		if err := t.metadata.Tasks.AddRunLog(t.taskDef.TaskId, t.taskDef.ID, "Checking Snowflake's latest refresh instead of re-running the query..."); err != nil {
+			return err
+		}
+	}
 	logger.Debugw("Transformation Config", "config", transformationConfig)
 	if err := t.runTransformationJob(transformationConfig, offlineStore, logger); err != nil {
 		return err
 	}
 
+	if plan != nil {
+		logger.Debugw("Setting source watermarks", "watermarks", plan.watermarks)
+		if err := t.metadata.Tasks.SetRunWatermarks(t.taskDef.TaskId, t.taskDef.ID, plan.watermarks); err != nil {
+			logger.Errorw("Failed to set source watermarks", "error", err)
+			return err
+		}
+	}
+	if backfill, isBackfill := t.taskDef.Trigger.(scheduling.BackfillTrigger); isBackfill {
+		return t.backfillFeatures(transformSource, backfill, logger)
+	}
+	return nil
+}
+
+// planPartitionBackfill returns the config that overwrites the daily partitions in a backfill's
+// range, or nil if the run isn't a backfill of a daily partitioned transformation, in which case
+// the full query is run. Backfills are split into a run per batch of partitions, so stores that
+// can't overwrite partitions reject them instead of rebuilding the whole table in every run.
+func (t *SourceTask) planPartitionBackfill(
+	transformSource *metadata.SourceVariant,
+	offlineStore provider.OfflineStore,
+	logger logging.Logger,
+) (*provider.IncrementalConfig, error) {
+	backfill, isBackfill := t.taskDef.Trigger.(scheduling.BackfillTrigger)
+	column := transformSource.DailyPartitionColumn()
+	if !isBackfill || column == "" {
+		return nil, nil
+	}
+	if !pt.SupportsIncrementalTransformation(offlineStore.Type()) {
+		logger.Errorw("Offline store can't overwrite partitions", "store", offlineStore.Type())
+		return nil, fferr.NewInvalidArgumentErrorf("%s can't overwrite the partitions of %s (%s), so it can't be backfilled", offlineStore.Type(), transformSource.Name(), transformSource.Variant())
+	}
+	if !t.isUpdate {
+		logger.Infow("Transformation hasn't been built yet; running the full query instead of the backfill")
+		return nil, nil
+	}
+	logger.Debugw("Planned partition backfill", "column", column, "start", backfill.Start, "end", backfill.End)
+	return &provider.IncrementalConfig{
+		TimestampColumn: column,
+		BackfillStart:   backfill.Start,
+		BackfillEnd:     backfill.End,
+	}, nil
+}
+
+// partitionTemplate limits the query template to the rows in the config's backfill range, which
+// are the ones the store deletes before writing the result. Only the result is filtered, on the
+// partition column, since how a source's own timestamps map to partitions depends on the query.
+func partitionTemplate(template string, config provider.IncrementalConfig, storeType pt.Type) string {
+	partition := incrementalWindow{
+		timestampColumn: config.TimestampColumn,
+		storeType:       storeType,
+		low:             config.BackfillStart,
+		high:            config.BackfillEnd,
+		isBackfill:      true,
+	}
+	return fmt.Sprintf("SELECT * FROM ( %s ) ff_partition WHERE %s", strings.TrimSpace(template), partition.condition())
+}
+
+// backfillFeatures re-materializes the features on a backfilled source, limited to the entities
+// with values in the backfilled range.
+func (t *SourceTask) backfillFeatures(transformSource *metadata.SourceVariant, backfill scheduling.BackfillTrigger, logger logging.Logger) error {
+	features := transformSource.Features()
+	if len(features) == 0 {
+		return nil
+	}
+	ids := make([]metadata.ResourceID, len(features))
+	for i, feature := range features {
+		ids[i] = metadata.ResourceID{Name: feature.Name, Variant: feature.Variant, Type: metadata.FEATURE_VARIANT}
+	}
+	logger.Infow("Backfilling downstream features", "features", ids, "start", backfill.Start, "end", backfill.End)
+	if err := t.metadata.Backfill(t.ctx, ids, backfill.Start, backfill.End, 0); err != nil {
+		logger.Errorw("Failed to backfill downstream features", "error", err)
+		return err
+	}
 	return nil
 }
 
+// planStreamRefresh returns the config of a transformation whose table is kept up to date by a
+// Snowflake task merging the streamed rows of its incremental sources, or nil if it isn't
+// incremental.
+func (t *SourceTask) planStreamRefresh(
+	transformSource *metadata.SourceVariant,
+	template string,
+	sourceTableMapping map[string]tableMapping,
+	offlineStore provider.OfflineStore,
+	logger logging.Logger,
+) (*provider.IncrementalConfig, error) {
+	if !transformSource.IsIncrementalSQLTransformation() {
+		return nil, nil
+	}
+	config := &provider.IncrementalConfig{
+		MergeKeys:       transformSource.SQLTransformationMergeKeys(),
+		TimestampColumn: transformSource.SQLTransformationIncrementalTimestampColumn(),
+	}
+	streamed := make(map[string]bool)
+	for _, nv := range transformSource.SQLTransformationIncrementalSources() {
+		key := nv.ClientString()
+		mapping, has := sourceTableMapping[key]
+		if !has {
+			return nil, fferr.NewInternalErrorf("incremental source %s not in source map", key)
+		}
+		table, err := getReplacementString(offlineStore, mapping, logger)
+		if err != nil {
+			return nil, err
+		}
+		config.StreamSources = append(config.StreamSources, provider.StreamSource{Key: key, Table: table})
+		streamed[key] = true
+	}
+	streamTemplate, err := streamTemplateReplace(template, sourceTableMapping, streamed, offlineStore, logger)
+	if err != nil {
+		return nil, err
+	}
+	config.StreamTemplate = sqlVariableReplace(streamTemplate, t)
+	logger.Debugw("Planned stream refresh", "sources", config.StreamSources, "template", config.StreamTemplate)
+	return config, nil
+}
+
+// streamTemplateReplace replaces a query template's placeholders like templateReplace, except for
+// those of the streamed sources, which the offline store fills in with either the sources' tables
+// or their streams.
+func streamTemplateReplace(template string, replacements map[string]tableMapping, streamed map[string]bool, offlineStore provider.OfflineStore, logger logging.Logger) (string, error) {
+	formattedString := ""
+	numEscapes := strings.Count(template, "{{")
+	for i := 0; i < numEscapes; i++ {
+		split := strings.SplitN(template, "{{", 2)
+		afterSplit := strings.SplitN(split[1], "}}", 2)
+		if len(afterSplit) != 2 {
+			return "", fferr.NewInvalidArgumentErrorf("unterminated {{ in query template")
+		}
+		key := strings.TrimSpace(afterSplit[0])
+		if streamed[key] {
+			formattedString += fmt.Sprintf("%s{{ %s }}", split[0], key)
+			template = afterSplit[1]
+			continue
+		}
+		tableMapping, has := replacements[key]
+		if !has {
+			return "", fferr.NewInvalidArgumentError(fmt.Errorf("value %s not found in replacements: %v", key, replacements))
+		}
+		replacement, err := getReplacementString(offlineStore, tableMapping, logger)
+		if err != nil {
+			return "", err
+		}
+		formattedString += fmt.Sprintf("%s%s", split[0], replacement)
+		template = afterSplit[1]
+	}
+	formattedString += template
+	return formattedString, nil
+}
+
+// incrementalWindow is the range of an incremental source's rows that a run reads.
+type incrementalWindow struct {
+	// source is the source's name.variant, as written in the query template.
+	source          string
+	timestampColumn string
+	// storeType decides how timestampColumn is quoted.
+	storeType pt.Type
+	// low is exclusive and high inclusive, so consecutive runs don't overlap. low is zero on the
+	// first run, which reads everything up to high.
+	low, high time.Time
+	// isBackfill makes the window [low, high), the same as the range a backfill deletes.
+	isBackfill bool
+}
+
+func (w incrementalWindow) condition() string {
+	format := func(ts time.Time) string {
+		return fmt.Sprintf("'%s'", ts.UTC().Format(provider.IncrementalTimestampFormat))
+	}
+	column := provider.SanitizeIncrementalColumn(w.storeType, w.timestampColumn)
+	if w.isBackfill {
+		return fmt.Sprintf("%s >= %s AND %s < %s", column, format(w.low), column, format(w.high))
+	}
+	if w.low.IsZero() {
+		return fmt.Sprintf("%s <= %s", column, format(w.high))
+	}
+	return fmt.Sprintf("%s > %s AND %s <= %s", column, format(w.low), column, format(w.high))
+}
+
+// incrementalPlan is what an incremental run reads and where it leaves each source's watermark.
+type incrementalPlan struct {
+	windows    []incrementalWindow
+	watermarks map[string]time.Time
+	config     provider.IncrementalConfig
+}
+
+// planIncrementalRun returns nil if the transformation isn't incremental, or its store can't merge
+// into the existing table, in which case the full query is run.
+func (t *SourceTask) planIncrementalRun(
+	transformSource *metadata.SourceVariant,
+	sourceTableMapping map[string]tableMapping,
+	offlineStore provider.OfflineStore,
+	logger logging.Logger,
+) (*incrementalPlan, error) {
+	if !transformSource.IsIncrementalSQLTransformation() {
+		return nil, nil
+	}
+	if !pt.SupportsIncrementalTransformation(offlineStore.Type()) {
+		logger.Warnw("Offline store doesn't support incremental transformations; running the full query", "store", offlineStore.Type())
+		return nil, nil
+	}
+	backfill, isBackfill := t.taskDef.Trigger.(scheduling.BackfillTrigger)
+	previous := map[string]time.Time{}
+	if t.isUpdate && t.lastSuccessfulTask.Watermarks != nil {
+		previous = t.lastSuccessfulTask.Watermarks
+	} else if t.isUpdate && !isBackfill {
+		// The last run didn't record watermarks, e.g. it ran before they were kept. Reading
+		// every row again would append them to the table a second time, so pick up from where
+		// the last run ended, or rebuild the table if that isn't known either.
+		lastEnd := t.lastSuccessfulTask.EndTime
+		if lastEnd.IsZero() {
+			logger.Warnw("Last run has no watermarks or end time; rebuilding the table from the full query")
+			return nil, nil
+		}
+		logger.Infow("Last run has no watermarks; reading rows newer than its end time", "end_time", lastEnd)
+		for _, nv := range transformSource.SQLTransformationIncrementalSources() {
+			previous[nv.ClientString()] = lastEnd.UTC()
+		}
+	}
+	plan := &incrementalPlan{
+		windows:    make([]incrementalWindow, 0),
+		watermarks: make(map[string]time.Time),
+		config: provider.IncrementalConfig{
+			MergeKeys:       transformSource.SQLTransformationMergeKeys(),
+			TimestampColumn: transformSource.SQLTransformationIncrementalTimestampColumn(),
+		},
+	}
+	if isBackfill {
+		plan.config.BackfillStart = backfill.Start
+		plan.config.BackfillEnd = backfill.End
+	}
+	runStart := time.Now().UTC()
+	watermarker, canWatermark := offlineStore.(provider.SourceWatermarker)
+	for _, nv := range transformSource.SQLTransformationIncrementalSources() {
+		key := nv.ClientString()
+		mapping, has := sourceTableMapping[key]
+		if !has {
+			return nil, fferr.NewInternalErrorf("incremental source %s not in source map", key)
+		}
+		timestampColumn := mapping.timestampColumnName
+		if timestampColumn == "" {
+			timestampColumn = transformSource.SQLTransformationIncrementalTimestampColumn()
+		}
+		if timestampColumn == "" {
+			return nil, fferr.NewInvalidArgumentErrorf("incremental source %s has no timestamp column", key)
+		}
+		if isBackfill {
+			// Backfills rebuild a past range, so they leave the watermarks where they were.
+			if watermark, ok := previous[key]; ok {
+				plan.watermarks[key] = watermark
+			}
+			plan.windows = append(plan.windows, incrementalWindow{
+				source: key, timestampColumn: timestampColumn, storeType: offlineStore.Type(), low: backfill.Start, high: backfill.End, isBackfill: true,
+			})
+			continue
+		}
+		high := runStart
+		if canWatermark {
+			table, err := getReplacementString(offlineStore, mapping, logger)
+			if err != nil {
+				return nil, err
+			}
+			if high, err = watermarker.SourceWatermark(table, timestampColumn); err != nil {
+				return nil, err
+			}
+			if high.IsZero() {
+				// The source is empty, so there's nothing new to read.
+				high = previous[key]
+			}
+		}
+		plan.watermarks[key] = high
+		plan.windows = append(plan.windows, incrementalWindow{
+			source: key, timestampColumn: timestampColumn, storeType: offlineStore.Type(), low: previous[key], high: high,
+		})
+	}
+	logger.Debugw("Planned incremental run", "windows", plan.windows, "backfill", isBackfill)
+	return plan, nil
+}
+
+// incrementalTemplate limits the query template to the windows of its incremental sources. Each
+// source is read through a CTE that filters it, so the template's own {{ name.variant }} references,
+// which every store substitutes differently, are left to templateReplace.
+func incrementalTemplate(template string, windows []incrementalWindow) (string, error) {
+	aliases := make(map[string]string, len(windows))
+	ctes := make([]string, 0, len(windows))
+	for i, window := range windows {
+		alias := fmt.Sprintf("ff_incremental_%d", i)
+		aliases[window.source] = alias
+		ctes = append(ctes, fmt.Sprintf("%s AS (SELECT * FROM {{ %s }} WHERE %s)", alias, window.source, window.condition()))
+	}
+	rewritten := ""
+	numEscapes := strings.Count(template, "{{")
+	for i := 0; i < numEscapes; i++ {
+		split := strings.SplitN(template, "{{", 2)
+		afterSplit := strings.SplitN(split[1], "}}", 2)
+		if len(afterSplit) != 2 {
+			return "", fferr.NewInvalidArgumentErrorf("unterminated {{ in query template")
+		}
+		key := strings.TrimSpace(afterSplit[0])
+		if alias, has := aliases[key]; has {
+			rewritten += split[0] + alias
+		} else {
+			rewritten += fmt.Sprintf("%s{{ %s }}", split[0], key)
+		}
+		template = afterSplit[1]
+	}
+	rewritten += template
+	if len(ctes) == 0 {
+		return rewritten, nil
+	}
+
+	with := "WITH " + strings.Join(ctes, ", ")
+	trimmed := strings.TrimSpace(rewritten)
+	words := strings.Fields(trimmed)
+	switch {
+	case len(words) > 1 && strings.EqualFold(words[0], "WITH") && strings.EqualFold(words[1], "RECURSIVE"):
+		rest := strings.TrimSpace(trimmed[len("WITH"):])
+		rest = strings.TrimSpace(rest[len("RECURSIVE"):])
+		return fmt.Sprintf("WITH RECURSIVE %s, %s", strings.Join(ctes, ", "), rest), nil
+	case len(words) > 0 && strings.EqualFold(words[0], "WITH"):
+		return fmt.Sprintf("%s, %s", with, strings.TrimSpace(trimmed[len("WITH"):])), nil
+	default:
+		return fmt.Sprintf("%s %s", with, trimmed), nil
+	}
+}
+
 func (t *SourceTask) runDFTransformationJob(
 	transformSource *metadata.SourceVariant,
 	resID metadata.ResourceID,
@@ -273,6 +693,10 @@ func (t *SourceTask) runDFTransformationJob(
 		return err
 	}
 
+	if err := t.checkSchemaContracts(sources, logger); err != nil {
+		return err
+	}
+
 	err = t.metadata.Tasks.AddRunLog(t.taskDef.TaskId, t.taskDef.ID, "Mapping Name Variants to Tables...")
 	if err != nil {
 		return err
@@ -311,7 +735,21 @@ func (t *SourceTask) runDFTransformationJob(
 	return nil
 }
 
-func (t *SourceTask) runTransformationJob(transformationConfig provider.TransformationConfig, offlineStore provider.OfflineStore, logger logging.Logger) error {
+func (t *SourceTask) runTransformationJob(transformationConfig provider.TransformationConfig, offlineStore provider.OfflineStore, logger logging.Logger) (err error) {
+	spanName := "provider.CreateTransformation"
+	if t.isUpdate {
+		spanName = "provider.UpdateTransformation"
+	}
+	_, span := tracing.Start(t.ctx, spanName,
+		tracing.ProviderTypeKey.String(offlineStore.Type().String()),
+		tracing.ResourceNameKey.String(transformationConfig.TargetTableID.Name),
+		tracing.ResourceVariantKey.String(transformationConfig.TargetTableID.Variant),
+	)
+	start := time.Now()
+	defer func() {
+		metrics.Instrument().ObserveProviderOperation(offlineStore.Type().String(), strings.TrimPrefix(spanName, "provider."), time.Since(start), err)
+		tracing.End(span, err)
+	}()
 	logger.Debugw("Starting transformation")
 	if err := t.metadata.Tasks.AddRunLog(t.taskDef.TaskId, t.taskDef.ID, "Starting Transformation..."); err != nil {
 		logger.Errorw("Unable to add run log", "error", err)
@@ -349,14 +787,35 @@ func (t *SourceTask) runTransformationJob(transformationConfig provider.Transfor
 	} else {
 		asyncOpt = provider.RunAsyncWithResume(maxWait)
 	}
+	var tfOpts []provider.TransformationOption
+	supportsRunLogOpt, err := offlineStore.SupportsTransformationOption(provider.RunLogTransformation)
+	if err != nil {
+		logger.Errorw("Unable to verify if offline store supports run log options", "error", err)
+		return err
+	}
+	if supportsRunLogOpt {
+		tfOpts = append(tfOpts, provider.WithRunLogs(func(msg string) error {
+			return t.metadata.Tasks.AddRunLog(t.taskDef.TaskId, t.taskDef.ID, msg)
+		}))
+	}
 	if !supportsAsyncOpt && isResuming {
 		// This is only possible if the provider used to support resumes and doesn't anymore
 		logger.DPanicw("Unable to resume, re-running task", "resume_id", lastResumeID)
 	}
 	if supportsAsyncOpt {
 		logger.Debugw("Running transformation with async option")
-		if err := transformFn(transformationConfig, asyncOpt); err != nil {
+		if err := transformFn(transformationConfig, append(tfOpts, asyncOpt)...); err != nil {
 			logger.Errorw("Transform failed with asyncOpt set", "error", err)
+			return err
+		}
+		// Persist the resume ID so that if the coordinator restarts mid-job, the next attempt
+		// re-attaches to the running job rather than submitting it again.
+		if !isResuming && asyncOpt.IsResumeIDSet() {
+			resumeID := asyncOpt.ResumeID()
+			if err := t.metadata.Tasks.SetRunResumeID(t.taskDef.TaskId, t.taskDef.ID, resumeID); err != nil {
+				logger.Errorw("Unable to persist resume ID, transformation won't be resumable", "resume_id", resumeID, "error", err)
+				// We can continue without the resume ID
+			}
 		}
 		waiter = asyncOpt
 	} else {
@@ -366,7 +825,7 @@ func (t *SourceTask) runTransformationJob(transformationConfig provider.Transfor
 			DoneChannel: make(chan interface{}),
 		}
 		go func() {
-			if err := transformFn(transformationConfig); err != nil {
+			if err := transformFn(transformationConfig, tfOpts...); err != nil {
 				logger.Errorw("Transform failed, ending watch", "error", err)
 				transformationWatcher.EndWatch(err)
 				return
@@ -416,7 +875,11 @@ func (t *SourceTask) runPrimaryTableJob(
 		return err
 	}
 	logger.Debugw("Registering primary table", "location", location, "provider_resource_id", providerResourceID)
-	if _, err := offlineStore.RegisterPrimaryFromSourceTable(providerResourceID, location); err != nil {
+	ds, err := offlineStore.RegisterPrimaryFromSourceTable(providerResourceID, location)
+	if err != nil {
+		return err
+	}
+	if err := t.recordSchemaContract(ds, logger); err != nil {
 		return err
 	}
 	err = t.metadata.Tasks.AddRunLog(t.taskDef.TaskId, t.taskDef.ID, "Registration Complete.")
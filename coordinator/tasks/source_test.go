@@ -9,12 +9,22 @@ package tasks
 
 import (
 	"context"
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/featureform/coordinator/spawner"
+	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
+	pb "github.com/featureform/metadata/proto"
+	"github.com/featureform/provider"
+	pl "github.com/featureform/provider/location"
 	pt "github.com/featureform/provider/provider_type"
+	"github.com/featureform/scheduling"
 )
 
 func TestSourceTaskRun(t *testing.T) {
@@ -132,3 +142,332 @@ func TestSqlVariableReplace(t *testing.T) {
 		})
 	}
 }
+
+func TestIncrementalTemplate(t *testing.T) {
+	low := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	high := time.Date(2024, 1, 2, 12, 30, 0, 500000000, time.UTC)
+	window := incrementalWindow{source: "events.v1", timestampColumn: "ts", low: low, high: high}
+	testCases := []struct {
+		name     string
+		template string
+		windows  []incrementalWindow
+		expected string
+	}{
+		{
+			name:     "no incremental sources",
+			template: "SELECT * FROM {{events.v1}}",
+			windows:  nil,
+			expected: "SELECT * FROM {{ events.v1 }}",
+		},
+		{
+			name:     "first run",
+			template: "SELECT * FROM {{ events.v1 }}",
+			windows:  []incrementalWindow{{source: "events.v1", timestampColumn: "ts", high: high}},
+			expected: "WITH ff_incremental_0 AS (SELECT * FROM {{ events.v1 }} WHERE \"ts\" <= '2024-01-02 12:30:00.5') SELECT * FROM ff_incremental_0",
+		},
+		{
+			name:     "joined with a full source",
+			template: "SELECT e.id, u.name FROM {{ events.v1 }} e JOIN {{ users.v1 }} u ON e.id = u.id",
+			windows:  []incrementalWindow{window},
+			expected: "WITH ff_incremental_0 AS (SELECT * FROM {{ events.v1 }} WHERE \"ts\" > '2024-01-01 00:00:00' AND \"ts\" <= '2024-01-02 12:30:00.5') " +
+				"SELECT e.id, u.name FROM ff_incremental_0 e JOIN {{ users.v1 }} u ON e.id = u.id",
+		},
+		{
+			name:     "existing CTE",
+			template: "with totals AS (SELECT id, COUNT(*) c FROM {{ events.v1 }} GROUP BY id) SELECT * FROM totals",
+			windows:  []incrementalWindow{window},
+			expected: "WITH ff_incremental_0 AS (SELECT * FROM {{ events.v1 }} WHERE \"ts\" > '2024-01-01 00:00:00' AND \"ts\" <= '2024-01-02 12:30:00.5'), " +
+				"totals AS (SELECT id, COUNT(*) c FROM ff_incremental_0 GROUP BY id) SELECT * FROM totals",
+		},
+		{
+			name:     "recursive CTE",
+			template: "WITH RECURSIVE r AS (SELECT * FROM {{ events.v1 }}) SELECT * FROM r",
+			windows:  []incrementalWindow{window},
+			expected: "WITH RECURSIVE ff_incremental_0 AS (SELECT * FROM {{ events.v1 }} WHERE \"ts\" > '2024-01-01 00:00:00' AND \"ts\" <= '2024-01-02 12:30:00.5'), " +
+				"r AS (SELECT * FROM ff_incremental_0) SELECT * FROM r",
+		},
+		{
+			name:     "backticked column",
+			template: "SELECT * FROM {{ events.v1 }}",
+			windows:  []incrementalWindow{{source: "events.v1", timestampColumn: "event time", storeType: pt.BigQueryOffline, high: high}},
+			expected: "WITH ff_incremental_0 AS (SELECT * FROM {{ events.v1 }} WHERE `event time` <= '2024-01-02 12:30:00.5') SELECT * FROM ff_incremental_0",
+		},
+		{
+			name:     "backfill",
+			template: "SELECT * FROM {{ events.v1 }}",
+			windows:  []incrementalWindow{{source: "events.v1", timestampColumn: "ts", low: low, high: high, isBackfill: true}},
+			expected: "WITH ff_incremental_0 AS (SELECT * FROM {{ events.v1 }} WHERE \"ts\" >= '2024-01-01 00:00:00' AND \"ts\" < '2024-01-02 12:30:00.5') SELECT * FROM ff_incremental_0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rewritten, err := incrementalTemplate(tc.template, tc.windows)
+			if err != nil {
+				t.Fatalf("Failed to rewrite template: %v", err)
+			}
+			if rewritten != tc.expected {
+				t.Fatalf("Expected: %s, got: %s", tc.expected, rewritten)
+			}
+		})
+	}
+}
+
+func TestPartitionTemplate(t *testing.T) {
+	config := provider.IncrementalConfig{
+		TimestampColumn: "date",
+		BackfillStart:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		BackfillEnd:     time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+	}
+	// Sources are read in full, only the result is limited to the partitions in the range.
+	template := " SELECT DATE(e.event_ts) AS date, u.region, SUM(e.amount) FROM {{ events.v1 }} e JOIN {{ users.v1 }} u ON e.user_id = u.id GROUP BY 1, 2\n"
+	expected := "SELECT * FROM ( SELECT DATE(e.event_ts) AS date, u.region, SUM(e.amount) FROM {{ events.v1 }} e JOIN {{ users.v1 }} u ON e.user_id = u.id GROUP BY 1, 2 ) ff_partition " +
+		"WHERE \"date\" >= '2024-01-01 00:00:00' AND \"date\" < '2024-01-03 00:00:00'"
+	query := partitionTemplate(template, config, pt.PostgresOffline)
+	if query != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, query)
+	}
+}
+
+func TestStreamTemplateReplace(t *testing.T) {
+	mapping := map[string]tableMapping{
+		"events.v1": {name: "events", location: pl.NewSQLLocation("events")},
+		"users.v1":  {name: "users", location: pl.NewSQLLocation("users")},
+	}
+	// Only placeholders are filled in, so text that matches a source's name is left alone.
+	template := "SELECT e.id, u.name FROM {{ events.v1 }} e JOIN {{users.v1}} u ON e.id = u.id UNION ALL SELECT id, 'events' FROM {{ events.v1 }}"
+	expected := "SELECT e.id, u.name FROM {{ events.v1 }} e JOIN \"users\" u ON e.id = u.id UNION ALL SELECT id, 'events' FROM {{ events.v1 }}"
+	query, err := streamTemplateReplace(template, mapping, map[string]bool{"events.v1": true}, watermarkedStore{}, logging.NewTestLogger(t))
+	if err != nil {
+		t.Fatalf("Failed to replace template: %v", err)
+	}
+	if query != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, query)
+	}
+}
+
+// watermarkedStore is an offline store whose sources' newest timestamp is fixed.
+type watermarkedStore struct {
+	provider.OfflineStore
+	watermark time.Time
+}
+
+func (store watermarkedStore) Type() pt.Type {
+	return pt.PostgresOffline
+}
+
+func (store watermarkedStore) SourceWatermark(table, timestampColumn string) (time.Time, error) {
+	return store.watermark, nil
+}
+
+func TestPlanIncrementalRun(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	source := metadata.WrapProtoSourceVariant(&pb.SourceVariant{
+		Name:    "totals",
+		Variant: "v1",
+		Definition: &pb.SourceVariant_Transformation{Transformation: &pb.Transformation{
+			Type: &pb.Transformation_SQLTransformation{SQLTransformation: &pb.SQLTransformation{
+				Query:                      "SELECT * FROM {{ events.v1 }}",
+				Source:                     []*pb.NameVariant{{Name: "events", Variant: "v1"}},
+				IsIncremental:              true,
+				IncrementalSource:          []*pb.NameVariant{{Name: "events", Variant: "v1"}},
+				IncrementalTimestampColumn: "ts",
+			}},
+		}},
+	})
+	mapping := map[string]tableMapping{"events.v1": {name: "events", location: pl.NewSQLLocation("events")}}
+	watermark := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	store := watermarkedStore{watermark: watermark}
+	lastWatermark := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	lastEnd := time.Date(2024, 1, 2, 6, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		isUpdate bool
+		last     scheduling.TaskRunMetadata
+		// rebuild is set when the full query should be run instead.
+		rebuild bool
+		low     time.Time
+	}
+	tests := map[string]testCase{
+		"FirstRun":            {isUpdate: false, last: scheduling.TaskRunMetadata{}, low: time.Time{}},
+		"FromWatermark":       {isUpdate: true, last: scheduling.TaskRunMetadata{EndTime: lastEnd, Watermarks: map[string]time.Time{"events.v1": lastWatermark}}, low: lastWatermark},
+		"NoWatermarksEndTime": {isUpdate: true, last: scheduling.TaskRunMetadata{EndTime: lastEnd}, low: lastEnd},
+		"NoWatermarksNoEnd":   {isUpdate: true, last: scheduling.TaskRunMetadata{}, rebuild: true},
+		"NewTableIgnoresLast": {isUpdate: false, last: scheduling.TaskRunMetadata{EndTime: lastEnd}, low: time.Time{}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			task := SourceTask{BaseTask: BaseTask{
+				taskDef:            scheduling.TaskRunMetadata{Trigger: scheduling.OnApplyTrigger{TriggerName: "apply"}},
+				lastSuccessfulTask: test.last,
+				isUpdate:           test.isUpdate,
+			}}
+			plan, err := task.planIncrementalRun(source, mapping, store, logger)
+			if err != nil {
+				t.Fatalf("Failed to plan run: %v", err)
+			}
+			if test.rebuild {
+				if plan != nil {
+					t.Fatalf("Expected the table to be rebuilt, got %+v", plan)
+				}
+				return
+			}
+			if plan == nil || len(plan.windows) != 1 {
+				t.Fatalf("Expected one incremental window, got %+v", plan)
+			}
+			window := plan.windows[0]
+			if !window.low.Equal(test.low) || !window.high.Equal(watermark) {
+				t.Fatalf("Expected window (%s, %s], got (%s, %s]", test.low, watermark, window.low, window.high)
+			}
+			if !plan.watermarks["events.v1"].Equal(watermark) {
+				t.Fatalf("Expected the watermark to move to %s, got %s", watermark, plan.watermarks["events.v1"])
+			}
+		})
+	}
+}
+
+func TestSchemaDriftError(t *testing.T) {
+	contract := fftypes.Schema{
+		Fields: []fftypes.ColumnSchema{
+			{Name: "entity", Type: fftypes.String},
+			{Name: "value", Type: fftypes.Int64},
+			{Name: "ts"},
+		},
+	}
+	added := fftypes.Schema{Fields: append(append([]fftypes.ColumnSchema{}, contract.Fields...), fftypes.ColumnSchema{Name: "region", Type: fftypes.String})}
+	retyped := fftypes.Schema{
+		Fields: []fftypes.ColumnSchema{
+			{Name: "entity", Type: fftypes.String},
+			{Name: "value", Type: fftypes.Float64},
+		},
+	}
+	// Columns with unknown types are only checked for being present.
+	untyped := fftypes.Schema{
+		Fields: []fftypes.ColumnSchema{
+			{Name: "entity"},
+			{Name: "value"},
+			{Name: "ts", Type: fftypes.Timestamp},
+		},
+	}
+
+	type testCase struct {
+		live     fftypes.Schema
+		policy   pb.SchemaDriftPolicy
+		fail     bool
+		contains []string
+	}
+	tests := map[string]testCase{
+		"Unchanged":           {contract, pb.SchemaDriftPolicy_SCHEMA_DRIFT_STRICT, false, nil},
+		"UntypedUnchanged":    {untyped, pb.SchemaDriftPolicy_SCHEMA_DRIFT_STRICT, false, nil},
+		"AddedStrict":         {added, pb.SchemaDriftPolicy_SCHEMA_DRIFT_STRICT, true, []string{"added: region (string)"}},
+		"AddedAllowed":        {added, pb.SchemaDriftPolicy_SCHEMA_DRIFT_ALLOW_ADDED_COLUMNS, false, nil},
+		"RetypedAndRemoved":   {retyped, pb.SchemaDriftPolicy_SCHEMA_DRIFT_ALLOW_ADDED_COLUMNS, true, []string{"removed: ts (unknown type)", "retyped: value (int64 -> float64)"}},
+		"RetypedStrictPolicy": {retyped, pb.SchemaDriftPolicy_SCHEMA_DRIFT_STRICT, true, []string{"retyped: value"}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := schemaDriftError("transactions", "v1", contract.Drift(test.live), test.policy)
+			if !test.fail {
+				if err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			var driftErr *fferr.SchemaDriftError
+			if !errors.As(err, &driftErr) {
+				t.Fatalf("Expected a SchemaDriftError, got: %#v", err)
+			}
+			for _, substr := range test.contains {
+				if !strings.Contains(err.Error(), substr) {
+					t.Fatalf("Expected %q in error: %s", substr, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateExpectations(t *testing.T) {
+	expectations := []*pb.Expectation{
+		{Name: "nulls", Type: pb.ExpectationType_EXPECT_NULL_FRACTION, MaxNullFraction: 0.1, Blocking: true},
+		{Name: "range", Type: pb.ExpectationType_EXPECT_RANGE, Min: 0, Max: 10},
+		{Name: "unique", Type: pb.ExpectationType_EXPECT_UNIQUE, Blocking: true},
+		{Name: "rows", Type: pb.ExpectationType_EXPECT_ROW_COUNT_CHANGE, MaxRowCountChange: 0.5, Blocking: true},
+	}
+	measurements := []provider.ExpectationMeasurement{
+		{Rows: 100, Violations: 5},
+		{Rows: 100, Violations: 2},
+		{Rows: 100, Violations: 0},
+		{Rows: 100, Violations: 0},
+	}
+
+	results := evaluateExpectations(expectations, measurements, nil)
+	passed := []bool{true, false, true, true}
+	for i, result := range results {
+		if result.Passed != passed[i] {
+			t.Fatalf("Expected %s to pass: %v, got: %#v", result.Name, passed[i], result)
+		}
+		if result.RowCount != 100 {
+			t.Fatalf("Expected the row count to be recorded, got: %#v", result)
+		}
+	}
+	if results[0].Observed != 0.05 {
+		t.Fatalf("Expected a null fraction of 0.05, got: %v", results[0].Observed)
+	}
+	// The failing range expectation isn't blocking.
+	if err := expectationsError("transactions", "v1", fferr.SOURCE_VARIANT, results); err != nil {
+		t.Fatalf("Expected no error for non-blocking failures, got: %v", err)
+	}
+
+	previous := []scheduling.ExpectationResult{{Name: "rows", RowCount: 40}}
+	results = evaluateExpectations(expectations, measurements, previous)
+	if results[3].Passed || results[3].Observed != 1.5 {
+		t.Fatalf("Expected a failing row count change of 1.5, got: %#v", results[3])
+	}
+	err := expectationsError("transactions", "v1", fferr.SOURCE_VARIANT, results)
+	var expectationErr *fferr.ExpectationFailedError
+	if !errors.As(err, &expectationErr) {
+		t.Fatalf("Expected an ExpectationFailedError, got: %#v", err)
+	}
+	if !strings.Contains(err.Error(), "rows: row count changed by 1.5 from 40 to 100") {
+		t.Fatalf("Expected the failed expectation in the error, got: %s", err.Error())
+	}
+}
+
+func TestToProviderExpectationsDefaults(t *testing.T) {
+	expectations := []*pb.Expectation{
+		{Name: "nulls", Type: pb.ExpectationType_EXPECT_NULL_FRACTION},
+		{Name: "unique", Type: pb.ExpectationType_EXPECT_UNIQUE},
+		{Name: "explicit", Type: pb.ExpectationType_EXPECT_RANGE, Column: "other"},
+	}
+	defaults := expectationDefaults{column: "value", columns: []string{"entity", "ts"}}
+	converted, err := toProviderExpectations(expectations, defaults)
+	if err != nil {
+		t.Fatalf("Failed to convert expectations: %v", err)
+	}
+	if converted[0].Column != "value" || converted[0].Type != provider.NullFractionExpectation {
+		t.Fatalf("Expected the default value column, got: %#v", converted[0])
+	}
+	if len(converted[1].Columns) != 2 || converted[1].Type != provider.UniqueExpectation {
+		t.Fatalf("Expected the default entity and timestamp columns, got: %#v", converted[1])
+	}
+	if converted[2].Column != "other" {
+		t.Fatalf("Expected the explicit column to be kept, got: %#v", converted[2])
+	}
+}
+
+func TestTrainingSetColumns(t *testing.T) {
+	features := []provider.ResourceID{{Name: "avg_amount", Variant: "v1"}, {Name: "tx_count", Variant: "v2"}}
+	lags := []provider.LagFeatureDef{{FeatureName: "avg_amount", FeatureVariant: "v1", LagName: "avg_amount_lag_1d"}}
+	columns := trainingSetColumns(features, lags, provider.ResourceID{Name: "fraud", Variant: "v1"})
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = string(col.Name)
+	}
+	expected := []string{"feature__avg_amount__v1", "feature__tx_count__v2", "avg_amount_lag_1d", "label__fraud__v1"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Wrong column names\nexpected: %v\n     got: %v", expected, names)
+	}
+	if withoutLabel := trainingSetColumns(features, nil, provider.ResourceID{}); len(withoutLabel) != 2 {
+		t.Fatalf("Expected only feature columns without a label, got %v", withoutLabel)
+	}
+}
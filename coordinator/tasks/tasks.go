@@ -46,6 +46,12 @@ type Task interface {
 	Run() error
 }
 
+// Cancellable is implemented by tasks that can stop the work they started when their run is
+// cancelled.
+type Cancellable interface {
+	Cancel() error
+}
+
 func init() {
 	unregisteredFactories := map[scheduling.TargetType]Factory{
 		scheduling.NameVariantTarget: NewResourceCreationFactory,
@@ -90,6 +96,8 @@ type BaseTask struct {
 	spawner            spawner.JobSpawner
 	logger             logging.Logger
 	config             TaskConfig
+	// parentCtx carries the task run's trace so provider spans are nested under it.
+	parentCtx context.Context
 }
 
 func NewBaseTask(
@@ -114,6 +122,19 @@ func NewBaseTask(
 	}
 }
 
+// WithContext sets the context the task runs under.
+func (bt BaseTask) WithContext(ctx context.Context) BaseTask {
+	bt.parentCtx = ctx
+	return bt
+}
+
+func (bt *BaseTask) context() context.Context {
+	if bt.parentCtx == nil {
+		return context.TODO()
+	}
+	return bt.parentCtx
+}
+
 func (bt *BaseTask) Redacted() map[string]any {
 	return map[string]any{
 		"task-def":        bt.taskDef,
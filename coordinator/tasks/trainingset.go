@@ -11,13 +11,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/featureform/logging"
 	"github.com/featureform/provider/clickhouse"
 
 	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
 	"github.com/featureform/metadata"
+	"github.com/featureform/metrics"
 	"github.com/featureform/provider"
 	pl "github.com/featureform/provider/location"
 	pc "github.com/featureform/provider/provider_config"
@@ -25,6 +28,7 @@ import (
 	pt "github.com/featureform/provider/provider_type"
 	"github.com/featureform/runner"
 	"github.com/featureform/scheduling"
+	"github.com/featureform/tracing"
 )
 
 type TrainingSetTask struct {
@@ -32,7 +36,7 @@ type TrainingSetTask struct {
 }
 
 func (t *TrainingSetTask) Run() error {
-	_, ctx, logger := t.logger.InitializeRequestID(context.TODO())
+	_, ctx, logger := t.logger.InitializeRequestID(t.context())
 	logger = logger.With("%#v\n", t.taskDef.Target)
 	nv, ok := t.taskDef.Target.(scheduling.NameVariant)
 	if !ok {
@@ -128,26 +132,52 @@ func (t *TrainingSetTask) Run() error {
 		}
 	}
 
-	label, err := ts.FetchLabel(t.metadata, ctx)
-	if err != nil {
-		logger.Errorw("Failed to fetch label", "error", err)
-		return err
-	}
-	labelSourceNameVariant := label.Source()
-	_, err = t.awaitPendingSource(ctx, labelSourceNameVariant)
-	if err != nil {
-		logger.Errorw("Failed to wait on pending label source", "error", err)
-		return err
-	}
-	label, err = t.AwaitPendingLabel(ctx, metadata.NameVariant{Name: label.Name(), Variant: label.Variant()})
-	if err != nil {
-		logger.Errorw("Failed to wait on pending label variant", "error", err)
-		return err
-	}
-	labelSourceMapping, err := t.getLabelSourceMapping(ctx, label)
-	if err != nil {
-		logger.Errorw("Failed to get label source mapping", "error", err)
-		return err
+	var labelID provider.ResourceID
+	var spineID *provider.ResourceID
+	var labelSourceMapping provider.SourceMapping
+	if ts.HasSpine() {
+		id, mapping, err := t.getSpineSourceMapping(ctx, ts.Spine())
+		if err != nil {
+			logger.Errorw("Failed to get spine source mapping", "error", err)
+			return err
+		}
+		spineID = &id
+		labelSourceMapping = mapping
+	} else {
+		label, err := ts.FetchLabel(t.metadata, ctx)
+		if err != nil {
+			logger.Errorw("Failed to fetch label", "error", err)
+			return err
+		}
+		labelSourceNameVariant := label.Source()
+		_, err = t.awaitPendingSource(ctx, labelSourceNameVariant)
+		if err != nil {
+			logger.Errorw("Failed to wait on pending label source", "error", err)
+			return err
+		}
+		label, err = t.AwaitPendingLabel(ctx, metadata.NameVariant{Name: label.Name(), Variant: label.Variant()})
+		if err != nil {
+			logger.Errorw("Failed to wait on pending label variant", "error", err)
+			return err
+		}
+		labelSourceMapping, err = t.getLabelSourceMapping(ctx, label)
+		if err != nil {
+			logger.Errorw("Failed to get label source mapping", "error", err)
+			return err
+		}
+		labelID = provider.ResourceID{Name: label.Name(), Variant: label.Variant(), Type: provider.Label}
+	}
+	additionalLabels := ts.AdditionalLabels()
+	additionalLabelList := make([]provider.ResourceID, len(additionalLabels))
+	additionalLabelSourceMappings := make([]provider.SourceMapping, len(additionalLabels))
+	for i, nv := range additionalLabels {
+		additionalLabelList[i] = provider.ResourceID{Name: nv.Name, Variant: nv.Variant, Type: provider.Label}
+		mapping, err := t.getAdditionalLabelSourceMapping(ctx, nv)
+		if err != nil {
+			logger.Errorw("Failed to get additional label source mapping", "label", nv, "error", err)
+			return err
+		}
+		additionalLabelSourceMappings[i] = mapping
 	}
 	resourceSnowflakeConfig := &metadata.ResourceSnowflakeConfig{}
 	if store.Type() == pt.SnowflakeOffline {
@@ -160,17 +190,71 @@ func (t *TrainingSetTask) Run() error {
 	}
 
 	trainingSetDef := provider.TrainingSetDef{
-		ID:                      providerResID,
-		Label:                   provider.ResourceID{Name: label.Name(), Variant: label.Variant(), Type: provider.Label},
-		LabelSourceMapping:      labelSourceMapping,
-		Features:                featureList,
-		FeatureSourceMappings:   featureSourceMappings,
-		LagFeatures:             lagFeaturesList,
-		ResourceSnowflakeConfig: resourceSnowflakeConfig,
-		Type:                    ts.TrainingSetType(),
+		ID:                            providerResID,
+		Label:                         labelID,
+		Spine:                         spineID,
+		LabelSourceMapping:            labelSourceMapping,
+		AdditionalLabels:              additionalLabelList,
+		AdditionalLabelSourceMappings: additionalLabelSourceMappings,
+		Features:                      featureList,
+		FeatureSourceMappings:         featureSourceMappings,
+		LagFeatures:                   lagFeaturesList,
+		ResourceSnowflakeConfig:       resourceSnowflakeConfig,
+		Type:                          ts.TrainingSetType(),
 	}
 	logger.Debugw("Successfully created training set def", "def", trainingSetDef)
-	return t.runTrainingSetJob(trainingSetDef, store)
+	snapshotStore, canPin := store.(provider.SnapshotOfflineStore)
+	policy, pinSnapshots := toProviderSnapshotPolicy(ts.SnapshotPolicy())
+	if pinSnapshots && !canPin {
+		logger.Errorw("Offline store can't pin snapshots", "store_type", store.Type())
+		return fferr.NewInvalidArgumentErrorf("%s can't pin training set snapshots", store.Type())
+	}
+	if pinSnapshots {
+		if err := t.pinTrainingSetInputs(&trainingSetDef, snapshotStore, policy, logger); err != nil {
+			return err
+		}
+	}
+	if err := t.runTrainingSetJob(ctx, trainingSetDef, store); err != nil {
+		return err
+	}
+	if pinSnapshots {
+		if err := t.pinTrainingSet(trainingSetDef, snapshotStore, policy, logger); err != nil {
+			return err
+		}
+	}
+	measure := func(expectations []provider.Expectation) ([]provider.ExpectationMeasurement, error) {
+		iter, err := store.GetTrainingSet(providerResID)
+		if err != nil {
+			return nil, err
+		}
+		defer iter.Close()
+		columns := trainingSetColumns(featureList, lagFeaturesList, labelID)
+		return provider.MeasureTrainingSetExpectations(iter, columns, labelID.Name != "", expectations)
+	}
+	if err := t.checkExpectations(nv.Name, nv.Variant, fferr.TRAINING_SET_VARIANT, ts.Expectations(), expectationDefaults{}, measure, logger); err != nil {
+		return err
+	}
+	t.recordProfile(func() (*fftypes.DatasetProfile, error) {
+		columns := trainingSetColumns(featureList, lagFeaturesList, labelID)
+		return provider.ProfileStoredTrainingSet(ctx, store, providerResID, columns, labelID.Name != "")
+	}, logger)
+	return nil
+}
+
+// trainingSetColumns names a training set's columns the way its tables do: features,
+// then lag features, then the label.
+func trainingSetColumns(features []provider.ResourceID, lagFeatures []provider.LagFeatureDef, label provider.ResourceID) []fftypes.ColumnSchema {
+	columns := make([]fftypes.ColumnSchema, 0, len(features)+len(lagFeatures)+1)
+	for _, feature := range features {
+		columns = append(columns, fftypes.ColumnSchema{Name: fftypes.ColumnName(fmt.Sprintf("feature__%s__%s", feature.Name, feature.Variant))})
+	}
+	for _, lag := range lagFeatures {
+		columns = append(columns, fftypes.ColumnSchema{Name: fftypes.ColumnName(lag.LagName)})
+	}
+	if label.Name != "" {
+		columns = append(columns, fftypes.ColumnSchema{Name: fftypes.ColumnName(fmt.Sprintf("label__%s__%s", label.Name, label.Variant))})
+	}
+	return columns
 }
 
 func (t *TrainingSetTask) handleDeletion(ctx context.Context, tsId metadata.ResourceID, logger logging.Logger) error {
@@ -308,38 +392,117 @@ func (t *TrainingSetTask) getLabelSourceMappingFromSource(label *metadata.LabelV
 		logger.Errorw("could not fetch source", "error", err)
 		return provider.SourceMapping{}, err
 	}
-	var location pl.Location
+	location, err := t.getSourceLocation(source, logger)
+	if err != nil {
+		return provider.SourceMapping{}, err
+	}
+	lblEntityMappings, err := label.Location()
+	if err != nil {
+		logger.Errorw("could not get label location", "label", label.Name(), "variant", label.Variant(), "error", err)
+		return provider.SourceMapping{}, err
+	}
+	logger.Debugw("Successfully got label source mapping from source", "entity_mappings", lblEntityMappings, "loc", location)
+	return provider.SourceMapping{
+		ProviderType:   pt.Type(labelProvider.Type()),
+		ProviderConfig: labelProvider.SerializedConfig(),
+		Location:       location,
+		EntityMappings: &lblEntityMappings,
+	}, nil
+}
+
+// getAdditionalLabelSourceMapping waits for an additional label to be ready and returns its source mapping
+// in the same shape as a feature's, since additional labels are joined to the label or spine like features.
+func (t *TrainingSetTask) getAdditionalLabelSourceMapping(ctx context.Context, nv metadata.NameVariant) (provider.SourceMapping, error) {
+	logger := t.logger.With("additional_label", nv)
+	label, err := t.metadata.GetLabelVariant(ctx, nv)
+	if err != nil {
+		logger.Errorw("Failed to get label variant", "error", err)
+		return provider.SourceMapping{}, err
+	}
+	if _, err := t.awaitPendingSource(ctx, label.Source()); err != nil {
+		logger.Errorw("Failed to wait on pending label source", "error", err)
+		return provider.SourceMapping{}, err
+	}
+	label, err = t.AwaitPendingLabel(ctx, nv)
+	if err != nil {
+		logger.Errorw("Failed to wait on pending label variant", "error", err)
+		return provider.SourceMapping{}, err
+	}
+	srcMapping, err := t.getLabelSourceMapping(ctx, label)
+	if err != nil {
+		return provider.SourceMapping{}, err
+	}
+	mappings := srcMapping.EntityMappings
+	if mappings == nil || len(mappings.Mappings) != 1 {
+		logger.Errorw("Additional label must be keyed on exactly one entity", "entity_mappings", mappings)
+		return provider.SourceMapping{}, fferr.NewInvalidArgumentErrorf("additional label %s (%s) must be keyed on exactly one entity", nv.Name, nv.Variant)
+	}
+	srcMapping.Columns = &metadata.ResourceVariantColumns{
+		Entity: mappings.Mappings[0].EntityColumn,
+		Value:  mappings.ValueColumn,
+		TS:     mappings.TimestampColumn,
+	}
+	srcMapping.EntityMappings = &metadata.EntityMappings{Mappings: mappings.Mappings}
+	return srcMapping, nil
+}
+
+// getSpineSourceMapping waits for the spine's source to be ready and returns the source mapping used in
+// place of a label's. Spines are always read straight from their source, so the entity mappings refer
+// to the source's columns.
+func (t *TrainingSetTask) getSpineSourceMapping(ctx context.Context, spine *metadata.TrainingSetSpine) (provider.ResourceID, provider.SourceMapping, error) {
+	logger := t.logger.With("spine_source", spine.Source)
+	logger.Debugw("Getting spine source mapping ...")
+	source, err := t.awaitPendingSource(ctx, spine.Source)
+	if err != nil {
+		logger.Errorw("Failed to wait on pending spine source", "error", err)
+		return provider.ResourceID{}, provider.SourceMapping{}, err
+	}
+	spineProvider, err := source.FetchProvider(t.metadata, ctx)
+	if err != nil {
+		logger.Errorw("could not fetch spine provider", "error", err)
+		return provider.ResourceID{}, provider.SourceMapping{}, err
+	}
+	location, err := t.getSourceLocation(source, logger)
+	if err != nil {
+		return provider.ResourceID{}, provider.SourceMapping{}, err
+	}
+	spineID := provider.ResourceID{Name: source.Name(), Variant: source.Variant(), Type: provider.Primary}
+	if source.IsTransformation() {
+		spineID.Type = provider.Transformation
+	}
+	entityMappings := spine.EntityMappings
+	logger.Debugw("Successfully got spine source mapping", "entity_mappings", entityMappings, "loc", location)
+	return spineID, provider.SourceMapping{
+		Source:         location.Location(),
+		ProviderType:   pt.Type(spineProvider.Type()),
+		ProviderConfig: spineProvider.SerializedConfig(),
+		Location:       location,
+		EntityMappings: &entityMappings,
+	}, nil
+}
+
+func (t *TrainingSetTask) getSourceLocation(source *metadata.SourceVariant, logger logging.Logger) (pl.Location, error) {
 	switch {
 	case source.IsPrimaryData():
 		logger.Debugw("Getting primary location ...")
-		location, err = source.GetPrimaryLocation()
+		location, err := source.GetPrimaryLocation()
 		if err != nil {
 			logger.Errorw("could not get primary location", "source", source.Definition(), "error", err)
-			return provider.SourceMapping{}, err
+			return nil, err
 		}
+		return location, nil
 	case source.IsTransformation():
 		logger.Debugw("Getting transformation location ...")
-		location, err = source.GetTransformationLocation()
+		location, err := source.GetTransformationLocation()
 		if err != nil {
 			logger.Errorw("could not get transformation location", "source", source.Definition(), "error", err)
-			return provider.SourceMapping{}, err
+			return nil, err
 		}
+		return location, nil
 	default:
 		logger.Errorw("source is neither primary data nor transformation", "definition", source.Definition())
-		return provider.SourceMapping{}, fferr.NewInternalErrorf("unsupported source type: %T", source.Definition())
+		return nil, fferr.NewInternalErrorf("unsupported source type: %T", source.Definition())
 	}
-	lblEntityMappings, err := label.Location()
-	if err != nil {
-		logger.Errorw("could not get label location", "label", label.Name(), "variant", label.Variant(), "error", err)
-		return provider.SourceMapping{}, err
-	}
-	logger.Debugw("Successfully got label source mapping from source", "entity_mappings", lblEntityMappings, "loc", location)
-	return provider.SourceMapping{
-		ProviderType:   pt.Type(labelProvider.Type()),
-		ProviderConfig: labelProvider.SerializedConfig(),
-		Location:       location,
-		EntityMappings: &lblEntityMappings,
-	}, nil
 }
 
 // **NOTE**: Given a feature's provider will always be an online store, we actually need its source's provider to grab the data for the training set.
@@ -503,7 +666,21 @@ func (t *TrainingSetTask) getSourceTableNameForNonMaterializedProviders(ctx cont
 	}
 }
 
-func (t *TrainingSetTask) runTrainingSetJob(def provider.TrainingSetDef, offlineStore provider.OfflineStore) error {
+func (t *TrainingSetTask) runTrainingSetJob(ctx context.Context, def provider.TrainingSetDef, offlineStore provider.OfflineStore) (err error) {
+	spanName := "provider.CreateTrainingSet"
+	if t.isUpdate {
+		spanName = "provider.UpdateTrainingSet"
+	}
+	_, span := tracing.Start(ctx, spanName,
+		tracing.ProviderTypeKey.String(offlineStore.Type().String()),
+		tracing.ResourceNameKey.String(def.ID.Name),
+		tracing.ResourceVariantKey.String(def.ID.Variant),
+	)
+	start := time.Now()
+	defer func() {
+		metrics.Instrument().ObserveProviderOperation(offlineStore.Type().String(), strings.TrimPrefix(spanName, "provider."), time.Since(start), err)
+		tracing.End(span, err)
+	}()
 	t.logger.Debugw("Running training set job", "id", def.ID)
 	if err := t.metadata.Tasks.AddRunLog(t.taskDef.TaskId, t.taskDef.ID, "Starting Training Set Creation..."); err != nil {
 		t.logger.Errorw("Unable to add run log", "error", err)
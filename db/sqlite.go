@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+
+	"github.com/pressly/goose/v3"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/helpers/sqlite"
+	"github.com/featureform/logging"
+)
+
+// The SQLite schema ships inside the binary since the SQLite state provider is
+// meant to run without any external setup.
+//
+//go:embed sqlite_migrations/*.sql
+var sqliteMigrations embed.FS
+
+func RunSQLiteMigrations(ctx context.Context, db *sqlite.DB) error {
+	logger := logging.GetLoggerFromContext(ctx)
+	if db == nil {
+		logger.Info("No sqlite database provided")
+		return nil
+	}
+
+	migrations, err := fs.Sub(sqliteMigrations, "sqlite_migrations")
+	if err != nil {
+		logger.Errorw("error loading embedded sqlite migrations", "err", err)
+		return fferr.NewInternalErrorf("error loading embedded sqlite migrations: %v", err)
+	}
+
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db.DB, migrations)
+	if err != nil {
+		logger.Errorw("error creating sqlite migration provider", "err", err)
+		return fferr.NewInternalErrorf("error creating sqlite migration provider: %v", err)
+	}
+
+	logger.Info("starting sqlite migrations")
+	results, err := provider.Up(ctx)
+	if err != nil {
+		logger.Errorw("error running sqlite migrations", "err", err)
+		return fferr.NewInternalErrorf("error running sqlite migrations: %v", err)
+	}
+	logger.Infow("sqlite migrations completed successfully", "applied", len(results))
+	return nil
+}
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS ff_ordered_id (
+    namespace TEXT PRIMARY KEY,
+    current_id INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS ff_task_metadata (
+    key TEXT PRIMARY KEY,
+    value TEXT,
+    marked_for_deletion_at TIMESTAMP DEFAULT NULL
+);
+
+-- Expiration is stored as unix nanoseconds so that it compares numerically.
+CREATE TABLE IF NOT EXISTS ff_locks (
+    owner TEXT,
+    key TEXT NOT NULL PRIMARY KEY,
+    expiration INTEGER NOT NULL
+);
+
+-- Full-text index over resource names, types, variants and tags. Rows are kept in
+-- sync with ff_task_metadata by the storage implementation.
+CREATE VIRTUAL TABLE IF NOT EXISTS search_resources USING fts4(
+    id,
+    name,
+    type,
+    variant,
+    tags,
+    notindexed=id
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS search_resources;
+DROP TABLE IF EXISTS ff_locks;
+DROP TABLE IF EXISTS ff_task_metadata;
+DROP TABLE IF EXISTS ff_ordered_id;
+-- +goose StatementEnd
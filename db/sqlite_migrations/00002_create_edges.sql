@@ -0,0 +1,135 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS edges (
+    from_resource_proto_type INTEGER NOT NULL,
+    from_resource_name TEXT NOT NULL,
+    from_resource_variant TEXT NOT NULL,
+    to_resource_proto_type INTEGER NOT NULL,
+    to_resource_name TEXT NOT NULL,
+    to_resource_variant TEXT NOT NULL,
+    PRIMARY KEY (
+        from_resource_proto_type,
+        from_resource_name,
+        from_resource_variant,
+        to_resource_proto_type,
+        to_resource_name,
+        to_resource_variant
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_from_resource ON edges (
+    from_resource_proto_type,
+    from_resource_name,
+    from_resource_variant
+);
+
+CREATE INDEX IF NOT EXISTS idx_to_resource ON edges (
+    to_resource_proto_type,
+    to_resource_name,
+    to_resource_variant
+);
+
+-- SQLite has no stored procedures, so the edges the Postgres process_*_variant functions
+-- derive from a resource's Message are expressed as a view. Both the trigger and the
+-- backfill below read from it. Resource types match the proto enum:
+-- 4 FEATURE_VARIANT, 5 LABEL_VARIANT, 6 TRAINING_SET_VARIANT, 7 SOURCE_VARIANT, 8 PROVIDER.
+CREATE VIEW IF NOT EXISTS resource_edges AS
+WITH resources AS (
+    SELECT
+        key,
+        CASE
+            WHEN key GLOB 'FEATURE_VARIANT__*' THEN 4
+            WHEN key GLOB 'LABEL_VARIANT__*' THEN 5
+            WHEN key GLOB 'TRAINING_SET_VARIANT__*' THEN 6
+            WHEN key GLOB 'SOURCE_VARIANT__*' THEN 7
+        END AS type,
+        CASE
+            WHEN json_valid(value) AND json_valid(json_extract(value, '$.Message'))
+                THEN json_extract(value, '$.Message')
+        END AS message
+    FROM ff_task_metadata
+),
+variants AS (
+    SELECT
+        key,
+        type,
+        message,
+        json_extract(message, '$.name') AS name,
+        COALESCE(json_extract(message, '$.variant'), '') AS variant
+    FROM resources
+    WHERE type IS NOT NULL AND message IS NOT NULL
+)
+SELECT key, 8 AS from_resource_proto_type, json_extract(message, '$.provider') AS from_resource_name,
+    '' AS from_resource_variant, type AS to_resource_proto_type, name AS to_resource_name,
+    variant AS to_resource_variant
+FROM variants
+WHERE json_extract(message, '$.provider') IS NOT NULL
+UNION ALL
+SELECT key, 7, json_extract(message, '$.source.name'), COALESCE(json_extract(message, '$.source.variant'), ''),
+    type, name, variant
+FROM variants
+WHERE type IN (4, 5) AND json_extract(message, '$.source') IS NOT NULL
+UNION ALL
+SELECT v.key, 7, json_extract(s.value, '$.name'), COALESCE(json_extract(s.value, '$.variant'), ''),
+    v.type, v.name, v.variant
+FROM variants v, json_each(v.message, '$.transformation.SQLTransformation.source') s
+WHERE v.type = 7
+UNION ALL
+SELECT v.key, 7, json_extract(i.value, '$.name'), COALESCE(json_extract(i.value, '$.variant'), ''),
+    v.type, v.name, v.variant
+FROM variants v, json_each(v.message, '$.transformation.DFTransformation.inputs') i
+WHERE v.type = 7
+UNION ALL
+SELECT key, 5, json_extract(message, '$.label.name'), COALESCE(json_extract(message, '$.label.variant'), ''),
+    type, name, variant
+FROM variants
+WHERE type = 6 AND json_extract(message, '$.label') IS NOT NULL
+UNION ALL
+SELECT v.key, 4, json_extract(f.value, '$.name'), COALESCE(json_extract(f.value, '$.variant'), ''),
+    v.type, v.name, v.variant
+FROM variants v, json_each(v.message, '$.features') f
+WHERE v.type = 6;
+
+INSERT OR IGNORE INTO edges (
+    from_resource_proto_type, from_resource_name, from_resource_variant,
+    to_resource_proto_type, to_resource_name, to_resource_variant
+)
+SELECT
+    from_resource_proto_type, from_resource_name, from_resource_variant,
+    to_resource_proto_type, to_resource_name, to_resource_variant
+FROM resource_edges;
+
+CREATE TRIGGER IF NOT EXISTS after_insert_ff_task_metadata
+    AFTER INSERT ON ff_task_metadata
+    FOR EACH ROW
+    WHEN NEW.key GLOB '*_VARIANT__*'
+BEGIN
+    -- Mirrors add_edge: resources can't be created on top of a resource that is being deleted.
+    SELECT RAISE(ABORT, 'Cannot insert edge because a dependency is marked as deleted in ff_task_metadata')
+    FROM resource_edges e
+    JOIN ff_task_metadata d ON d.key = CASE e.from_resource_proto_type
+        WHEN 4 THEN 'FEATURE_VARIANT'
+        WHEN 5 THEN 'LABEL_VARIANT'
+        WHEN 7 THEN 'SOURCE_VARIANT'
+        WHEN 8 THEN 'PROVIDER'
+    END || '__' || e.from_resource_name || '__' || e.from_resource_variant
+    WHERE e.key = NEW.key AND d.marked_for_deletion_at IS NOT NULL;
+
+    INSERT OR IGNORE INTO edges (
+        from_resource_proto_type, from_resource_name, from_resource_variant,
+        to_resource_proto_type, to_resource_name, to_resource_variant
+    )
+    SELECT
+        from_resource_proto_type, from_resource_name, from_resource_variant,
+        to_resource_proto_type, to_resource_name, to_resource_variant
+    FROM resource_edges
+    WHERE key = NEW.key;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS after_insert_ff_task_metadata;
+DROP VIEW IF EXISTS resource_edges;
+DROP TABLE IF EXISTS edges;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package encryption
+
+import (
+	"github.com/featureform/fferr"
+	"github.com/featureform/helpers"
+	"github.com/featureform/provider/retriever"
+)
+
+// Environment variable names for encryption at rest
+const (
+	EnvKeyFile           = "FEATUREFORM_ENCRYPTION_KEY_FILE"
+	EnvVaultTransitKey   = "FEATUREFORM_ENCRYPTION_VAULT_TRANSIT_KEY"
+	EnvVaultTransitMount = "FEATUREFORM_ENCRYPTION_VAULT_TRANSIT_MOUNT"
+	EnvVaultAddress      = "VAULT_ADDR"
+)
+
+// NewEncryptorFromEnv returns the Encryptor configured through the environment, or nil if
+// encryption at rest isn't enabled. A key file takes precedence over Vault Transit.
+func NewEncryptorFromEnv() (*Encryptor, error) {
+	if path := helpers.GetEnv(EnvKeyFile, ""); path != "" {
+		keys, err := LoadKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return NewEncryptor(keys), nil
+	}
+	if key := helpers.GetEnv(EnvVaultTransitKey, ""); key != "" {
+		address := helpers.GetEnv(EnvVaultAddress, "")
+		if address == "" {
+			return nil, fferr.NewInvalidArgumentErrorf("%s must be set to use vault transit encryption", EnvVaultAddress)
+		}
+		mount := helpers.GetEnv(EnvVaultTransitMount, "transit")
+		return NewEncryptor(NewVaultTransitKeyProvider(address, mount, key, retriever.VaultToken)), nil
+	}
+	return nil, nil
+}
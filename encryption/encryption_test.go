@@ -0,0 +1,170 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKey(seed byte) []byte {
+	return bytes.Repeat([]byte{seed}, dataKeySize)
+}
+
+func writeKeyFile(t *testing.T, primary string, keys map[string][]byte) string {
+	file := keyFile{Primary: primary, Keys: make(map[string]string)}
+	for id, key := range keys {
+		file.Keys[id] = base64.StdEncoding.EncodeToString(key)
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		t.Fatalf("Failed to marshal key file: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+	return path
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	keys, err := LoadKeyFile(writeKeyFile(t, "k1", map[string][]byte{"k1": testKey(1)}))
+	if err != nil {
+		t.Fatalf("Failed to load key file: %v", err)
+	}
+	encryptor := NewEncryptor(keys)
+	plaintext := []byte(`{"Password": "hunter2"}`)
+
+	encrypted, err := encryptor.Encrypt(ctx, plaintext)
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	if !IsEncrypted(encrypted) || bytes.Contains(encrypted, []byte("hunter2")) {
+		t.Fatalf("Expected ciphertext, got %s", encrypted)
+	}
+	if encryptor.NeedsRotation(encrypted) {
+		t.Fatalf("Value wrapped with the primary key shouldn't need rotation")
+	}
+	// A fresh encryptor can't use the data key cache.
+	decrypted, err := NewEncryptor(keys).Decrypt(ctx, encrypted)
+	if err != nil {
+		t.Fatalf("Failed to decrypt: %v", err)
+	}
+	if !bytes.Equal(plaintext, decrypted) {
+		t.Fatalf("Expected %s, got %s", plaintext, decrypted)
+	}
+
+	legacy, err := encryptor.Decrypt(ctx, plaintext)
+	if err != nil || !bytes.Equal(plaintext, legacy) {
+		t.Fatalf("Expected plaintext to be returned as is, got %s, %v", legacy, err)
+	}
+	if !encryptor.NeedsRotation(plaintext) {
+		t.Fatalf("Plaintext should need rotation")
+	}
+
+	tampered := bytes.Replace(encrypted, []byte(`"ct":"`), []byte(`"ct":"AA`), 1)
+	if _, err := NewEncryptor(keys).Decrypt(ctx, tampered); err == nil {
+		t.Fatalf("Expected tampered value to fail to decrypt")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	oldKeys, err := NewLocalKeyProvider("k1", map[string][]byte{"k1": testKey(1)})
+	if err != nil {
+		t.Fatalf("Failed to create keys: %v", err)
+	}
+	encrypted, err := NewEncryptor(oldKeys).Encrypt(ctx, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+
+	rotatedKeys, err := NewLocalKeyProvider("k2", map[string][]byte{"k1": testKey(1), "k2": testKey(2)})
+	if err != nil {
+		t.Fatalf("Failed to create keys: %v", err)
+	}
+	rotated := NewEncryptor(rotatedKeys)
+	if !rotated.NeedsRotation(encrypted) {
+		t.Fatalf("Value wrapped with a retired key should need rotation")
+	}
+	decrypted, err := rotated.Decrypt(ctx, encrypted)
+	if err != nil || string(decrypted) != "secret" {
+		t.Fatalf("Expected retired key to still decrypt, got %s, %v", decrypted, err)
+	}
+
+	removedKeys, err := NewLocalKeyProvider("k2", map[string][]byte{"k2": testKey(2)})
+	if err != nil {
+		t.Fatalf("Failed to create keys: %v", err)
+	}
+	if _, err := NewEncryptor(removedKeys).Decrypt(ctx, encrypted); err == nil {
+		t.Fatalf("Expected decryption with a removed key to fail")
+	}
+}
+
+func TestLocalKeyProviderValidation(t *testing.T) {
+	if _, err := NewLocalKeyProvider("missing", map[string][]byte{"k1": testKey(1)}); err == nil {
+		t.Fatalf("Expected missing primary key to fail")
+	}
+	if _, err := NewLocalKeyProvider("k1", map[string][]byte{"k1": []byte("short")}); err == nil {
+		t.Fatalf("Expected short key to fail")
+	}
+	if _, err := LoadKeyFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatalf("Expected missing key file to fail")
+	}
+}
+
+func TestVaultTransitKeyProvider(t *testing.T) {
+	// The stand-in "encrypts" by prefixing the plaintext, which is enough to check the protocol.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/featureform":
+			fmt.Fprintf(w, `{"data": {"ciphertext": "vault:v1:%s"}}`, body["plaintext"])
+		case "/v1/transit/decrypt/featureform":
+			fmt.Fprintf(w, `{"data": {"plaintext": %q}}`, strings.TrimPrefix(body["ciphertext"], "vault:v1:"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	token := func() (string, error) { return "root", nil }
+	keys := NewVaultTransitKeyProvider(server.URL, "", "featureform", token)
+	encrypted, err := NewEncryptor(keys).Encrypt(ctx, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	decrypted, err := NewEncryptor(keys).Decrypt(ctx, encrypted)
+	if err != nil || string(decrypted) != "secret" {
+		t.Fatalf("Expected secret, got %s, %v", decrypted, err)
+	}
+
+	badToken := func() (string, error) { return "wrong", nil }
+	if _, err := NewEncryptor(NewVaultTransitKeyProvider(server.URL, "", "featureform", badToken)).Decrypt(ctx, encrypted); err == nil {
+		t.Fatalf("Expected unauthorized unwrap to fail")
+	}
+}
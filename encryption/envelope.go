@@ -0,0 +1,174 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+// Package encryption implements envelope encryption for secrets stored in the metadata
+// storage. Every value is encrypted with its own random data key, and the data key is wrapped by
+// a KeyProvider's key encryption key, so rotating the key encryption key only requires
+// re-wrapping data keys.
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/featureform/fferr"
+)
+
+// envelopePrefix marks encrypted values so plaintext written before encryption was enabled can
+// still be read.
+var envelopePrefix = []byte("ffenc:v1:")
+
+const dataKeySize = 32
+
+type envelope struct {
+	KeyID      string `json:"kid"`
+	WrappedKey []byte `json:"dek"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ct"`
+}
+
+// Encryptor encrypts and decrypts values with data keys wrapped by a KeyProvider.
+type Encryptor struct {
+	keys KeyProvider
+
+	mtx sync.Mutex
+	// dataKeys caches unwrapped data keys so remote key providers aren't called on every read.
+	dataKeys map[string][]byte
+}
+
+func NewEncryptor(keys KeyProvider) *Encryptor {
+	return &Encryptor{keys: keys, dataKeys: make(map[string][]byte)}
+}
+
+// IsEncrypted reports whether data was produced by Encrypt.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, envelopePrefix)
+}
+
+func parseEnvelope(data []byte) (envelope, error) {
+	var env envelope
+	if err := json.Unmarshal(bytes.TrimPrefix(data, envelopePrefix), &env); err != nil {
+		return envelope{}, fferr.NewInternalErrorf("failed to parse encrypted value: %w", err)
+	}
+	return env, nil
+}
+
+// Encrypt returns the envelope for plaintext, wrapped with the key provider's primary key.
+func (e *Encryptor) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, fferr.NewInternalError(err)
+	}
+	keyID, wrapped, err := e.keys.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce, ciphertext, err := seal(dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	serialized, err := json.Marshal(envelope{KeyID: keyID, WrappedKey: wrapped, Nonce: nonce, Ciphertext: ciphertext})
+	if err != nil {
+		return nil, fferr.NewInternalError(err)
+	}
+	e.cacheDataKey(wrapped, dataKey)
+	return append(append([]byte{}, envelopePrefix...), serialized...), nil
+}
+
+// Decrypt returns the plaintext of an envelope. Values that aren't encrypted are returned as is.
+func (e *Encryptor) Decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	env, err := parseEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := e.dataKey(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	return open(dataKey, env.Nonce, env.Ciphertext)
+}
+
+// NeedsRotation reports whether data is plaintext or wrapped with a key other than the primary.
+func (e *Encryptor) NeedsRotation(data []byte) bool {
+	if !IsEncrypted(data) {
+		return true
+	}
+	env, err := parseEnvelope(data)
+	if err != nil {
+		return false
+	}
+	return env.KeyID != e.keys.PrimaryKeyID()
+}
+
+func (e *Encryptor) dataKey(ctx context.Context, env envelope) ([]byte, error) {
+	e.mtx.Lock()
+	cached, has := e.dataKeys[string(env.WrappedKey)]
+	e.mtx.Unlock()
+	if has {
+		return cached, nil
+	}
+	dataKey, err := e.keys.UnwrapKey(ctx, env.KeyID, env.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	e.cacheDataKey(env.WrappedKey, dataKey)
+	return dataKey, nil
+}
+
+func (e *Encryptor) cacheDataKey(wrapped, dataKey []byte) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.dataKeys[string(wrapped)] = dataKey
+}
+
+func seal(key, plaintext []byte) ([]byte, []byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, fferr.NewInternalError(err)
+	}
+	return nonce, gcm.Seal(nil, nonce, plaintext, nil), nil
+}
+
+func open(key, nonce, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fferr.NewInternalErrorf("invalid nonce length %d", len(nonce))
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fferr.NewInternalErrorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fferr.NewInternalErrorf("invalid encryption key: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fferr.NewInternalError(err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,202 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/featureform/fferr"
+)
+
+// KeyProvider wraps and unwraps data keys with a key encryption key that never leaves it.
+type KeyProvider interface {
+	// PrimaryKeyID identifies the key new data keys are wrapped with.
+	PrimaryKeyID() string
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// keyFile is the format of a local key file. Keys are base64 encoded 32 byte AES keys. To rotate,
+// add a new key, make it the primary and restart the metadata server. Old keys must stay in the
+// file until every value wrapped with them has been re-encrypted.
+//
+//	{"primary": "2025-02", "keys": {"2025-01": "...", "2025-02": "..."}}
+type keyFile struct {
+	Primary string            `json:"primary"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// LocalKeyProvider wraps data keys with AES-GCM keys loaded from a file, such as a mounted
+// Kubernetes secret.
+type LocalKeyProvider struct {
+	primary string
+	keys    map[string][]byte
+}
+
+func NewLocalKeyProvider(primary string, keys map[string][]byte) (*LocalKeyProvider, error) {
+	if _, has := keys[primary]; !has {
+		return nil, fferr.NewInvalidArgumentErrorf("primary encryption key %q not found", primary)
+	}
+	for id, key := range keys {
+		if len(key) != dataKeySize {
+			return nil, fferr.NewInvalidArgumentErrorf("encryption key %q must be %d bytes, got %d", id, dataKeySize, len(key))
+		}
+	}
+	return &LocalKeyProvider{primary: primary, keys: keys}, nil
+}
+
+func LoadKeyFile(path string) (*LocalKeyProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fferr.NewInternalErrorf("failed to read encryption key file: %w", err)
+	}
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fferr.NewInvalidArgumentErrorf("failed to parse encryption key file: %v", err)
+	}
+	keys := make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fferr.NewInvalidArgumentErrorf("encryption key %q is not valid base64: %v", id, err)
+		}
+		keys[id] = key
+	}
+	return NewLocalKeyProvider(file.Primary, keys)
+}
+
+func (p *LocalKeyProvider) PrimaryKeyID() string {
+	return p.primary
+}
+
+func (p *LocalKeyProvider) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	nonce, ciphertext, err := seal(p.keys[p.primary], dataKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return p.primary, append(nonce, ciphertext...), nil
+}
+
+func (p *LocalKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key, has := p.keys[keyID]
+	if !has {
+		return nil, fferr.NewInternalErrorf("encryption key %q not found, it may have been removed before re-encryption", keyID)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, fferr.NewInternalErrorf("wrapped data key is too short")
+	}
+	return open(key, wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():])
+}
+
+// VaultTransitKeyProvider wraps data keys with a Vault Transit key, so the key encryption key
+// never leaves Vault. Vault rotates transit keys itself and the key version is embedded in the
+// wrapped key.
+type VaultTransitKeyProvider struct {
+	Address string
+	Mount   string
+	Key     string
+	// Token returns the Vault token for each request, so renewed tokens are picked up.
+	Token func() (string, error)
+
+	client *http.Client
+}
+
+func NewVaultTransitKeyProvider(address, mount, key string, token func() (string, error)) *VaultTransitKeyProvider {
+	if mount == "" {
+		mount = "transit"
+	}
+	return &VaultTransitKeyProvider{
+		Address: strings.TrimRight(address, "/"),
+		Mount:   strings.Trim(mount, "/"),
+		Key:     key,
+		Token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *VaultTransitKeyProvider) PrimaryKeyID() string {
+	return fmt.Sprintf("vault-transit:%s/%s", p.Mount, p.Key)
+}
+
+func (p *VaultTransitKeyProvider) WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	req := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)}
+	if err := p.call(ctx, "encrypt", req, &resp); err != nil {
+		return "", nil, err
+	}
+	return p.PrimaryKeyID(), []byte(resp.Data.Ciphertext), nil
+}
+
+func (p *VaultTransitKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != p.PrimaryKeyID() {
+		return nil, fferr.NewInternalErrorf("data key was wrapped with %s, not %s", keyID, p.PrimaryKeyID())
+	}
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := p.call(ctx, "decrypt", map[string]string{"ciphertext": string(wrapped)}, &resp); err != nil {
+		return nil, err
+	}
+	dataKey, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return nil, fferr.NewInternalErrorf("failed to decode data key from vault: %w", err)
+	}
+	return dataKey, nil
+}
+
+func (p *VaultTransitKeyProvider) call(ctx context.Context, op string, body, out interface{}) error {
+	token, err := p.Token()
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fferr.NewInternalError(err)
+	}
+	url := fmt.Sprintf("%s/v1/%s/%s/%s", p.Address, p.Mount, op, p.Key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fferr.NewInternalError(err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fferr.NewInternalErrorf("failed to reach vault: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fferr.NewInternalError(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fferr.NewInternalErrorf("vault transit %s returned %d: %s", op, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fferr.NewInternalErrorf("failed to parse vault response: %w", err)
+	}
+	return nil
+}
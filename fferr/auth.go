@@ -0,0 +1,53 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package fferr
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+)
+
+func NewUnauthenticatedError(err error) *UnauthenticatedError {
+	if err == nil {
+		err = fmt.Errorf("unauthenticated")
+	}
+	baseError := newBaseError(err, UNAUTHENTICATED, codes.Unauthenticated)
+
+	return &UnauthenticatedError{
+		baseError,
+	}
+}
+
+func NewUnauthenticatedErrorf(format string, a ...any) *UnauthenticatedError {
+	return NewUnauthenticatedError(fmt.Errorf(format, a...))
+}
+
+type UnauthenticatedError struct {
+	baseError
+}
+
+func NewPermissionDeniedError(subject, action, resourceName, resourceVariant string, resourceType ResourceType, err error) *PermissionDeniedError {
+	if err == nil {
+		err = fmt.Errorf("permission denied")
+	}
+	baseError := newBaseError(err, PERMISSION_DENIED, codes.PermissionDenied)
+	baseError.AddDetail("subject", subject)
+	baseError.AddDetail("action", action)
+	baseError.AddDetail("resource_name", resourceName)
+	baseError.AddDetail("resource_variant", resourceVariant)
+	baseError.AddDetail("resource_type", string(resourceType))
+
+	return &PermissionDeniedError{
+		baseError,
+	}
+}
+
+type PermissionDeniedError struct {
+	baseError
+}
@@ -9,6 +9,7 @@ package fferr
 
 import (
 	"fmt"
+	"strings"
 
 	"google.golang.org/grpc/codes"
 )
@@ -255,3 +256,52 @@ func NewResourceChangedError(resourceName, resourceVariant string, resourceType
 type ResourceChangedError struct {
 	baseError
 }
+
+// NewSchemaDriftError is returned when a source's live schema no longer matches the schema it
+// was registered with. Each column is listed along with its type.
+func NewSchemaDriftError(resourceName, resourceVariant string, added, removed, retyped []string) *SchemaDriftError {
+	changes := make([]string, 0, 3)
+	if len(added) > 0 {
+		changes = append(changes, fmt.Sprintf("added: %s", strings.Join(added, ", ")))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("removed: %s", strings.Join(removed, ", ")))
+	}
+	if len(retyped) > 0 {
+		changes = append(changes, fmt.Sprintf("retyped: %s", strings.Join(retyped, ", ")))
+	}
+	err := fmt.Errorf("the schema of %s (%s) has changed since it was registered; %s", resourceName, resourceVariant, strings.Join(changes, "; "))
+	baseError := newBaseError(err, SCHEMA_DRIFT, codes.FailedPrecondition)
+	baseError.AddDetail("resource_name", resourceName)
+	baseError.AddDetail("resource_variant", resourceVariant)
+	baseError.AddDetail("added_columns", strings.Join(added, ", "))
+	baseError.AddDetail("removed_columns", strings.Join(removed, ", "))
+	baseError.AddDetail("retyped_columns", strings.Join(retyped, ", "))
+
+	return &SchemaDriftError{
+		baseError,
+	}
+}
+
+type SchemaDriftError struct {
+	baseError
+}
+
+// NewExpectationFailedError is returned when a blocking data quality expectation fails. Each
+// failure describes one expectation.
+func NewExpectationFailedError(resourceName, resourceVariant string, resourceType ResourceType, failures []string) *ExpectationFailedError {
+	err := fmt.Errorf("%s %s (%s) failed blocking expectations: %s", resourceType, resourceName, resourceVariant, strings.Join(failures, "; "))
+	baseError := newBaseError(err, EXPECTATION_FAILED, codes.FailedPrecondition)
+	baseError.AddDetail("resource_name", resourceName)
+	baseError.AddDetail("resource_variant", resourceVariant)
+	baseError.AddDetail("resource_type", string(resourceType))
+	baseError.AddDetail("failed_expectations", strings.Join(failures, "; "))
+
+	return &ExpectationFailedError{
+		baseError,
+	}
+}
+
+type ExpectationFailedError struct {
+	baseError
+}
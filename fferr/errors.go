@@ -46,6 +46,8 @@ const (
 	INVALID_FILE_TYPE             = "Invalid File Type"
 	RESOURCE_CHANGED              = "Resource Changed"
 	TYPE_ERROR                    = "Type Error"
+	SCHEMA_DRIFT                  = "Schema Drift"
+	EXPECTATION_FAILED            = "Expectation Failed"
 
 	// MISCELLANEOUS:
 	INTERNAL_ERROR      = "Internal Error"
@@ -67,6 +69,10 @@ const (
 	// ETCD
 	KEY_NOT_FOUND = "Key Not Found"
 
+	// AUTH
+	UNAUTHENTICATED   = "Unauthenticated"
+	PERMISSION_DENIED = "Permission Denied"
+
 	// LOCKING
 	KEY_ALREADY_LOCKED = "Key Already Locked"
 	KEY_NOT_LOCKED     = "Key Not Locked"
@@ -73,6 +73,12 @@ func setErrorType(err baseError, errorType string) error {
 	case KEY_NOT_FOUND:
 		return &KeyNotFoundError{err}
 
+	// AUTH
+	case UNAUTHENTICATED:
+		return &UnauthenticatedError{err}
+	case PERMISSION_DENIED:
+		return &PermissionDeniedError{err}
+
 	}
 	return nil
 }
@@ -112,6 +118,8 @@ func TestNewError(t *testing.T) {
 		{"NewInvalidResourceVariantNameError", NewInvalidResourceVariantNameError("name", "variant", FEATURE_VARIANT, fmt.Errorf("test error")), fmt.Errorf("test error"), INVALID_RESOURCE_TYPE, codes.InvalidArgument, []map[string]string{{"resource_name": "name"}, {"resource_variant": "variant"}, {"resource_type": string(FEATURE_VARIANT)}}},
 		{"NewResourceExecutionError", NewResourceExecutionError("provider", "name", "variant", FEATURE_VARIANT, fmt.Errorf("test error")), fmt.Errorf("test error"), EXECUTION_ERROR, codes.FailedPrecondition, []map[string]string{{"provider": "provider"}, {"resource_name": "name"}, {"resource_variant": "variant"}, {"resource_type": string(FEATURE_VARIANT)}}},
 		{"NewProviderConfigError", NewProviderConfigError("provider", fmt.Errorf("test error")), fmt.Errorf("test error"), EXECUTION_ERROR, codes.InvalidArgument, []map[string]string{{"provider": "provider"}}},
+		{"Unauthenticated Error", NewUnauthenticatedError(fmt.Errorf("test error")), fmt.Errorf("test error"), UNAUTHENTICATED, codes.Unauthenticated, []map[string]string{}},
+		{"Permission Denied Error", NewPermissionDeniedError("alice", "delete", "name", "variant", FEATURE_VARIANT, fmt.Errorf("test error")), fmt.Errorf("test error"), PERMISSION_DENIED, codes.PermissionDenied, []map[string]string{{"subject": "alice"}, {"action": "delete"}, {"resource_name": "name"}, {"resource_variant": "variant"}, {"resource_type": string(FEATURE_VARIANT)}}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -164,6 +172,8 @@ func TestNewErrorEmptyInner(t *testing.T) {
 		{"NewInvalidResourceVariantNameError", NewInvalidResourceVariantNameError("name", "variant", FEATURE_VARIANT, nil), fmt.Errorf("invalid resource variant name or variant"), INVALID_RESOURCE_TYPE, codes.InvalidArgument, []map[string]string{{"resource_name": "name"}, {"resource_variant": "variant"}, {"resource_type": string(FEATURE_VARIANT)}}},
 		{"NewResourceExecutionError", NewResourceExecutionError("provider", "name", "variant", FEATURE_VARIANT, nil), fmt.Errorf("execution failed on resource"), EXECUTION_ERROR, codes.FailedPrecondition, []map[string]string{{"provider": "provider"}, {"resource_name": "name"}, {"resource_variant": "variant"}, {"resource_type": string(FEATURE_VARIANT)}}},
 		{"NewProviderConfigError", NewProviderConfigError("provider", nil), fmt.Errorf("provider config"), EXECUTION_ERROR, codes.InvalidArgument, []map[string]string{{"provider": "provider"}}},
+		{"Unauthenticated Error", NewUnauthenticatedError(nil), fmt.Errorf("unauthenticated"), UNAUTHENTICATED, codes.Unauthenticated, []map[string]string{}},
+		{"Permission Denied Error", NewPermissionDeniedError("alice", "delete", "name", "variant", FEATURE_VARIANT, nil), fmt.Errorf("permission denied"), PERMISSION_DENIED, codes.PermissionDenied, []map[string]string{{"subject": "alice"}, {"action": "delete"}, {"resource_name": "name"}, {"resource_variant": "variant"}, {"resource_type": string(FEATURE_VARIANT)}}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,33 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package ffsync
+
+import (
+	"context"
+	"time"
+
+	"github.com/featureform/metrics"
+)
+
+// instrumentedLocker records how long callers wait to acquire locks.
+type instrumentedLocker struct {
+	Locker
+	name string
+}
+
+// NewInstrumentedLocker wraps locker so lock wait times are recorded under name.
+func NewInstrumentedLocker(name string, locker Locker) Locker {
+	return &instrumentedLocker{Locker: locker, name: name}
+}
+
+func (l *instrumentedLocker) Lock(ctx context.Context, lock string, wait bool) (Key, error) {
+	start := time.Now()
+	key, err := l.Locker.Lock(ctx, lock, wait)
+	metrics.Instrument().ObserveLockWait(l.name, time.Since(start), err)
+	return key, err
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/featureform/fferr"
 	"github.com/featureform/helpers/postgres"
+	"github.com/featureform/helpers/sqlite"
 	"github.com/featureform/logging"
 
 	_ "github.com/lib/pq"
@@ -157,6 +158,59 @@ func (pg *pgIdGenerator) upsertIdQuery() string {
 	return fmt.Sprintf("INSERT INTO %s (namespace, current_id) VALUES ($1, $2) ON CONFLICT (namespace) DO UPDATE SET current_id = %s.current_id + 1 RETURNING current_id", sanitizedTableName, sanitizedTableName)
 }
 
+func NewSQLiteOrderedIdGenerator(
+	ctx context.Context, db *sqlite.DB,
+) (OrderedIdGenerator, error) {
+	const tableName = "ff_ordered_id"
+	logger := logging.GetLoggerFromContext(ctx).WithValues(map[string]any{
+		"ordered-id-table-name": tableName,
+	})
+
+	logger.Infow("Successfully created OrderedIDGenerator for sqlite")
+	return &sqliteIdGenerator{
+		db:        db,
+		tableName: tableName,
+		logger:    logger,
+	}, nil
+}
+
+type sqliteIdGenerator struct {
+	db        *sqlite.DB
+	tableName string
+	logger    logging.Logger
+}
+
+func (lite *sqliteIdGenerator) NextId(ctx context.Context, namespace string) (OrderedId, error) {
+	logger := lite.logger.WithRequestIDFromContext(ctx).With("ordered-id-namespace", namespace)
+	logger.Debug("Getting next ordered ID from sqlite")
+	if namespace == "" {
+		errMsg := "cannot generate ID for empty namespace"
+		logger.Error(errMsg)
+		return nil, fferr.NewInternalErrorf(errMsg)
+	}
+
+	var nextId int64
+	upsertIdQuery := lite.upsertIdQuery()
+	logger.Debugw("Running next ID sqlite query", "query", upsertIdQuery)
+	if err := lite.db.QueryRowContext(ctx, upsertIdQuery, namespace, 1).Scan(&nextId); err != nil {
+		errMsg := "failed to get next ID in namespace"
+		logger.Errorw(errMsg, "err", err)
+		return nil, fferr.NewInternalErrorf("%s %s: %w", errMsg, namespace, err)
+	}
+	logger.Debugw("Got next ID", "next-id", nextId)
+	return Uint64OrderedId(nextId), nil
+}
+
+func (lite *sqliteIdGenerator) Close() {
+	// No-op
+}
+
+// SQL Queries
+func (lite *sqliteIdGenerator) upsertIdQuery() string {
+	sanitizedTableName := sqlite.Sanitize(lite.tableName)
+	return fmt.Sprintf("INSERT INTO %s (namespace, current_id) VALUES (?1, ?2) ON CONFLICT (namespace) DO UPDATE SET current_id = %s.current_id + 1 RETURNING current_id", sanitizedTableName, sanitizedTableName)
+}
+
 func createLockKey(prefix, namespace string) string {
 	return fmt.Sprintf("%s/%s", strings.TrimSuffix(prefix, "/"), strings.TrimPrefix(namespace, "/"))
 }
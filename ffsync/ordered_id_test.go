@@ -63,6 +63,12 @@ func TestOrderedIdGenerator(t *testing.T) {
 			createGen: createMemoryIdGenerator,
 			deferFunc: func(generator OrderedIdGenerator, t *testing.T) {},
 		},
+		{
+			name:      "SQLite",
+			shortTest: true,
+			createGen: createSQLiteIdGenerator,
+			deferFunc: func(generator OrderedIdGenerator, t *testing.T) {},
+		},
 		{
 			name:      "Postgres",
 			shortTest: false,
@@ -134,3 +140,7 @@ func createPSQLIdGenerator(t *testing.T, ctx context.Context) (OrderedIdGenerato
 	}
 	return NewPSQLOrderedIdGenerator(ctx, pool)
 }
+
+func createSQLiteIdGenerator(t *testing.T, ctx context.Context) (OrderedIdGenerator, error) {
+	return NewSQLiteOrderedIdGenerator(ctx, createTestSQLiteDB(ctx, t))
+}
@@ -0,0 +1,235 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package ffsync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/helpers/sqlite"
+	"github.com/featureform/logging"
+
+	"github.com/google/uuid"
+	"github.com/jonboulle/clockwork"
+)
+
+type sqliteKey struct {
+	owner string
+	key   string
+	done  chan error
+}
+
+func (k sqliteKey) Owner() string {
+	return k.owner
+}
+
+func (k sqliteKey) Key() string {
+	return k.key
+}
+
+// NewSQLiteLocker creates a Locker that stores its leases in the ff_locks table of an
+// embedded SQLite file. It follows the same lease and keep-alive protocol as the
+// PSQL locker, so a lock held by a crashed process expires on its own.
+func NewSQLiteLocker(ctx context.Context, db *sqlite.DB) (Locker, error) {
+	const tableName = "ff_locks"
+
+	logger := logging.GetLoggerFromContext(ctx).With(
+		"lock-table-name", tableName,
+	)
+
+	return &sqliteLocker{
+		db:        db,
+		tableName: tableName,
+		logger:    logger,
+		clock:     clockwork.NewRealClock(),
+	}, nil
+}
+
+type sqliteLocker struct {
+	db        *sqlite.DB
+	tableName string
+	logger    logging.Logger
+	clock     clockwork.Clock
+}
+
+func (l *sqliteLocker) runLockQuery(
+	ctx context.Context, key string, owner string, logger logging.Logger,
+) error {
+	lockQuery := l.lockQuery()
+	nowTime := l.clock.Now()
+	validTime := nowTime.Add(validTimePeriod.Duration())
+	logger.Debugw("Running lock query", "lock-query", lockQuery, "lock-now-time", nowTime, "lock-valid-time", validTime)
+	r, err := l.db.ExecContext(ctx, lockQuery, owner, key, validTime.UnixNano(), nowTime.UnixNano())
+	if err != nil {
+		logger.Errorw("Failed to lock key", "err", err)
+		return fferr.NewInternalErrorf("failed to lock key %s: %v", key, err)
+	}
+	if affected, err := r.RowsAffected(); err != nil {
+		logger.Errorw("Failed to get lock result", "err", err)
+		return fferr.NewInternalErrorf("failed to lock key %s: %v", key, err)
+	} else if affected == 0 {
+		logger.Debug("Key already lock")
+		return fferr.NewKeyAlreadyLockedError(key, owner, nil)
+	}
+	logger.Debug("Acquired lock")
+	return nil
+}
+
+func (l *sqliteLocker) attemptLock(
+	ctx context.Context, key string, owner string, shouldWait bool, logger logging.Logger,
+) error {
+	startTime := l.clock.Now()
+	logger.Debugw("Attempting to lock key", "start-lock-time", startTime)
+	for {
+		if hasExceededWaitTime(startTime) {
+			logger.Error("Exceeded time waiting for lock")
+			return fferr.NewExceededWaitTimeError("sqlite", key)
+		}
+		if err := l.runLockQuery(ctx, key, owner, logger); err == nil {
+			return nil
+		} else if fferr.IsKeyAlreadyLockedError(err) && shouldWait {
+			sleepTime := 100 * time.Millisecond
+			logger.Debugw("Key is locked. Waiting...", "time", sleepTime)
+			l.clock.Sleep(sleepTime)
+		} else {
+			return err
+		}
+	}
+}
+
+func (l *sqliteLocker) Lock(ctx context.Context, key string, shouldWait bool) (Key, error) {
+	owner := uuid.New().String()
+	logger := l.logger.WithRequestIDFromContext(ctx).With(
+		"lock-key", key, "should-wait-for-lock", shouldWait, "lock-owner", owner,
+	)
+	logger.Debug("Locking key")
+	if key == "" {
+		errMsg := "cannot lock an empty key"
+		logger.Error(errMsg)
+		return nil, fferr.NewInternalErrorf(errMsg)
+	} else if len(key) > maxKeyLength {
+		errMsg := fmt.Sprintf("key is too long: %d, max length: %d", len(key), maxKeyLength)
+		logger.Error(errMsg)
+		return nil, fferr.NewInternalErrorf(errMsg)
+	}
+	if err := l.attemptLock(ctx, key, owner, shouldWait, logger); err != nil {
+		if !fferr.IsKeyAlreadyLockedError(err) {
+			logger.Errorw("Failed to lock key", "err", err)
+		} else {
+			logger.Debugw("Key was already locked and didnt wait", "err", err)
+		}
+		return nil, err
+	}
+
+	lockKey := &sqliteKey{
+		owner: owner,
+		key:   key,
+		done:  make(chan error),
+	}
+
+	logger.Debug("Starting lock expiration update")
+	go l.updateLockTime(lockKey, logger)
+
+	logger.Debug("Successfully locked key")
+	return lockKey, nil
+}
+
+func (l *sqliteLocker) updateLockTime(key *sqliteKey, logger logging.Logger) {
+	ticker := l.clock.NewTicker(updateSleepTime.Duration())
+	defer ticker.Stop()
+	logger.Debug("Lock time extender thread started")
+
+	// Keep track of failed updates and will return if it exceeds the limit
+	failedUpdatesInARow := 0
+
+	for {
+		select {
+		case <-key.done:
+			logger.Debug("Lock time extender received signal to stop")
+			return
+		case <-ticker.Chan():
+			validUntil := l.clock.Now().Add(validTimePeriod.Duration())
+			r, err := l.db.Exec(l.updateLockExpirationQuery(), key.owner, key.key, validUntil.UnixNano())
+			var affected int64
+			if err == nil {
+				affected, err = r.RowsAffected()
+			}
+			if err != nil {
+				failedUpdatesInARow++
+				errLogger := logger.With("error", err, "lock-failed-updates-in-row", failedUpdatesInARow)
+				if failedUpdatesInARow >= tickerFailedUpdateLimit {
+					errLogger.Error("Failed to revalidate lock, not trying again")
+					return
+				}
+				errLogger.Warn("Failed to revalidate lock, trying again")
+				continue
+			}
+
+			// Key no longer exists, stop updating
+			if affected == 0 {
+				logger.Debug("Lock no longer exists, exiting lock extender thread")
+				return
+			}
+
+			failedUpdatesInARow = 0
+		}
+	}
+}
+
+func (l *sqliteLocker) Unlock(ctx context.Context, key Key) error {
+	if key == nil {
+		errMsg := "Cannot unlock a nil key"
+		l.logger.Error(errMsg)
+		return fferr.NewInternalErrorf(errMsg)
+	}
+	logger := l.logger.WithRequestIDFromContext(ctx).With(
+		"unlock-key-owner", key.Owner(),
+		"unlock-key-key", key.Key(),
+	)
+	logger.Debug("Unlocking key")
+	if key.Key() == "" {
+		errMsg := "Cannot unlock an empty key"
+		logger.Error(errMsg)
+		return fferr.NewInternalErrorf(errMsg)
+	}
+
+	liteKey, ok := key.(*sqliteKey)
+	if !ok {
+		errMsg := fmt.Sprintf("Trying to unlock key %#v as a sqliteKey, wrong type.", key)
+		logger.Error(errMsg)
+		return fferr.NewInternalErrorf(errMsg)
+	}
+
+	if _, err := l.db.ExecContext(ctx, l.unlockQuery(), key.Owner(), key.Key()); err != nil {
+		logger.Errorw("Failed to unlock key", "error", err)
+		return fferr.NewInternalErrorf("failed to unlock key %s: %v", key.Key(), err)
+	}
+	close(liteKey.done)
+	logger.Debug("Successfully unlocked key")
+	return nil
+}
+
+func (l *sqliteLocker) Close() {
+	// No-op
+}
+
+// SQL Queries
+func (l *sqliteLocker) lockQuery() string {
+	tableName := sqlite.Sanitize(l.tableName)
+	return fmt.Sprintf("INSERT INTO %s (owner, key, expiration) VALUES (?1, ?2, ?3) ON CONFLICT (key) DO UPDATE SET owner = excluded.owner, expiration = excluded.expiration WHERE %s.expiration < ?4", tableName, tableName)
+}
+
+func (l *sqliteLocker) unlockQuery() string {
+	return fmt.Sprintf("DELETE FROM %s WHERE owner = ?1 AND key = ?2", sqlite.Sanitize(l.tableName))
+}
+
+func (l *sqliteLocker) updateLockExpirationQuery() string {
+	return fmt.Sprintf("UPDATE %s SET expiration = ?3 WHERE owner = ?1 AND key = ?2", sqlite.Sanitize(l.tableName))
+}
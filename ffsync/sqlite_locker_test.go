@@ -0,0 +1,78 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package ffsync
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/featureform/db"
+	"github.com/featureform/helpers/sqlite"
+	"github.com/featureform/logging"
+	"github.com/jonboulle/clockwork"
+)
+
+func createTestSQLiteDB(ctx context.Context, t *testing.T) *sqlite.DB {
+	t.Helper()
+	liteDB, err := sqlite.NewDB(ctx, sqlite.Config{Path: filepath.Join(t.TempDir(), "featureform.db")})
+	if err != nil {
+		t.Fatalf("Failed to open sqlite database: %v", err)
+	}
+	t.Cleanup(func() { liteDB.Close() })
+	if err := db.RunSQLiteMigrations(ctx, liteDB); err != nil {
+		t.Fatalf("Failed to run sqlite migrations: %v", err)
+	}
+	return liteDB
+}
+
+func TestSQLiteLocker(t *testing.T) {
+	ctx := logging.NewTestContext(t)
+	locker, err := NewSQLiteLocker(ctx, createTestSQLiteDB(ctx, t))
+	if err != nil {
+		t.Fatalf("Failed to create SQLite locker: %v", err)
+	}
+	defer locker.Close()
+
+	clock := clockwork.NewFakeClock()
+	locker.(*sqliteLocker).clock = clock
+
+	test := LockerTest{
+		t:          t,
+		locker:     locker,
+		lockerType: "sqlite",
+	}
+	test.Run(clock)
+}
+
+func TestSQLiteLockerExpiredLease(t *testing.T) {
+	ctx := logging.NewTestContext(t)
+	locker, err := NewSQLiteLocker(ctx, createTestSQLiteDB(ctx, t))
+	if err != nil {
+		t.Fatalf("Failed to create SQLite locker: %v", err)
+	}
+	defer locker.Close()
+	clock := clockwork.NewFakeClock()
+	liteLocker := locker.(*sqliteLocker)
+	liteLocker.clock = clock
+
+	key := "/tasks/metadata/task_id=4"
+	lock, err := locker.Lock(ctx, key, false)
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	// Simulate the owner crashing by stopping its keep-alive.
+	close(lock.(*sqliteKey).done)
+	if _, err := locker.Lock(ctx, key, false); err == nil {
+		t.Fatalf("Locking a held key should fail")
+	}
+	clock.Advance(validTimePeriod.Duration() * 2)
+	if _, err := locker.Lock(ctx, key, false); err != nil {
+		t.Fatalf("Expected expired lease to be taken over: %v", err)
+	}
+}
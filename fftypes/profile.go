@@ -0,0 +1,69 @@
+package types
+
+// DatasetProfile summarizes what the data of a materialization or training set looks like.
+type DatasetProfile struct {
+	RowCount int64           `json:"rowCount"`
+	Columns  []ColumnProfile `json:"columns"`
+}
+
+// ColumnProfile holds the statistics of one column. Min, Max, Mean, StdDev and Histogram are only
+// set for numeric columns and TopValues only for string columns.
+type ColumnProfile struct {
+	Name string `json:"name"`
+	// Type is the column's value type, or empty if it's unknown.
+	Type string `json:"type,omitempty"`
+	// Count is the number of non-null values.
+	Count     int64 `json:"count"`
+	NullCount int64 `json:"nullCount"`
+	// DistinctCount is exact for small columns and an estimate for large ones.
+	DistinctCount int64        `json:"distinctCount"`
+	Numeric       bool         `json:"numeric"`
+	Min           float64      `json:"min"`
+	Max           float64      `json:"max"`
+	Mean          float64      `json:"mean"`
+	StdDev        float64      `json:"stddev"`
+	TopValues     []ValueCount `json:"topValues,omitempty"`
+	// Histogram splits [Min, Max] into equal-width buckets.
+	Histogram []HistogramBucket `json:"histogram,omitempty"`
+}
+
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// HistogramBucket counts the values in [Lower, Upper). The last bucket of a histogram also
+// includes Upper.
+type HistogramBucket struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int64   `json:"count"`
+}
+
+// EqualWidthBuckets splits [min, max] into n empty buckets, or a single one if min equals max.
+func EqualWidthBuckets(min, max float64, n int) []HistogramBucket {
+	if max <= min || n < 1 {
+		return []HistogramBucket{{Lower: min, Upper: max}}
+	}
+	width := (max - min) / float64(n)
+	buckets := make([]HistogramBucket, n)
+	for i := range buckets {
+		buckets[i] = HistogramBucket{Lower: min + float64(i)*width, Upper: min + float64(i+1)*width}
+	}
+	buckets[n-1].Upper = max
+	return buckets
+}
+
+// HistogramBucket returns the index of the bucket value falls in. Values outside of the
+// histogram's range go in the first or last bucket. It returns -1 if there's no histogram.
+func (c ColumnProfile) HistogramBucket(value float64) int {
+	if len(c.Histogram) == 0 {
+		return -1
+	}
+	for i, bucket := range c.Histogram[:len(c.Histogram)-1] {
+		if value < bucket.Upper {
+			return i
+		}
+	}
+	return len(c.Histogram) - 1
+}
@@ -0,0 +1,111 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchemaDrift is how a table's live schema differs from the schema it was registered with.
+type SchemaDrift struct {
+	Added   []ColumnSchema
+	Removed []ColumnSchema
+	Retyped []RetypedColumn
+}
+
+type RetypedColumn struct {
+	Name     ColumnName
+	Expected ColumnSchema
+	Actual   ColumnSchema
+}
+
+// Drift compares live to s, matching columns by name. Columns whose types are unknown on either
+// side are only checked for being present.
+func (s *Schema) Drift(live Schema) SchemaDrift {
+	expected := make(map[ColumnName]ColumnSchema, len(s.Fields))
+	for _, field := range s.Fields {
+		expected[field.Name] = field
+	}
+	drift := SchemaDrift{}
+	seen := make(map[ColumnName]bool, len(live.Fields))
+	for _, field := range live.Fields {
+		seen[field.Name] = true
+		contract, has := expected[field.Name]
+		if !has {
+			drift.Added = append(drift.Added, field)
+			continue
+		}
+		if columnTypeChanged(contract, field) {
+			drift.Retyped = append(drift.Retyped, RetypedColumn{Name: field.Name, Expected: contract, Actual: field})
+		}
+	}
+	for _, field := range s.Fields {
+		if !seen[field.Name] {
+			drift.Removed = append(drift.Removed, field)
+		}
+	}
+	return drift
+}
+
+func columnTypeChanged(expected, actual ColumnSchema) bool {
+	if expected.Type != nil && actual.Type != nil {
+		return SerializeType(expected.Type) != SerializeType(actual.Type)
+	}
+	if expected.NativeType != nil && actual.NativeType != nil {
+		return !strings.EqualFold(expected.NativeType.TypeName(), actual.NativeType.TypeName())
+	}
+	return false
+}
+
+func (d SchemaDrift) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Retyped) == 0
+}
+
+// IsAdditive reports whether the only change is added columns, which don't break existing readers.
+func (d SchemaDrift) IsAdditive() bool {
+	return len(d.Removed) == 0 && len(d.Retyped) == 0
+}
+
+// Describe lists the added, removed and retyped columns along with their types.
+func (d SchemaDrift) Describe() (added, removed, retyped []string) {
+	added = describeColumns(d.Added)
+	removed = describeColumns(d.Removed)
+	retyped = make([]string, len(d.Retyped))
+	for i, col := range d.Retyped {
+		retyped[i] = fmt.Sprintf("%s (%s -> %s)", col.Name, describeType(col.Expected), describeType(col.Actual))
+	}
+	return added, removed, retyped
+}
+
+func (d SchemaDrift) String() string {
+	added, removed, retyped := d.Describe()
+	changes := make([]string, 0, 3)
+	if len(added) > 0 {
+		changes = append(changes, "added: "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, "removed: "+strings.Join(removed, ", "))
+	}
+	if len(retyped) > 0 {
+		changes = append(changes, "retyped: "+strings.Join(retyped, ", "))
+	}
+	return strings.Join(changes, "; ")
+}
+
+func describeColumns(columns []ColumnSchema) []string {
+	described := make([]string, len(columns))
+	for i, col := range columns {
+		described[i] = fmt.Sprintf("%s (%s)", col.Name, describeType(col))
+	}
+	return described
+}
+
+func describeType(col ColumnSchema) string {
+	switch {
+	case col.Type != nil:
+		return col.Type.String()
+	case col.NativeType != nil:
+		return col.NativeType.TypeName()
+	default:
+		return "unknown type"
+	}
+}
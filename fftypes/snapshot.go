@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+// SnapshotKind is how an offline store pins the version of a table.
+type SnapshotKind string
+
+const (
+	// TimeTravelSnapshot reads a table as of Timestamp, e.g. with Snowflake's AT(TIMESTAMP => ...)
+	// or Spark's TIMESTAMP AS OF.
+	TimeTravelSnapshot SnapshotKind = "time_travel"
+	// SnapshotDecorator reads a BigQuery table as of Timestamp with FOR SYSTEM_TIME AS OF.
+	SnapshotDecorator SnapshotKind = "snapshot_decorator"
+	// TableVersionSnapshot reads a Delta Lake version or an Iceberg snapshot ID of a file store table.
+	TableVersionSnapshot SnapshotKind = "table_version"
+	// FileCopySnapshot reads a copy of plain files that was made when they were pinned.
+	FileCopySnapshot SnapshotKind = "file_copy"
+	// PinnedQuerySnapshot is a view whose query already reads pinned snapshots of its inputs, so
+	// it's read as-is.
+	PinnedQuerySnapshot SnapshotKind = "pinned_query"
+)
+
+// DataSnapshot pins the data one input of a training set run read, or the data the run wrote.
+type DataSnapshot struct {
+	// Resource names what was pinned, e.g. "label churn (v1)", for display.
+	Resource string `json:"resource"`
+	// Location is the table or path that was pinned, written the way the store's locations are.
+	Location string       `json:"location"`
+	Kind     SnapshotKind `json:"kind"`
+	// Version is the table version for TableVersionSnapshot or the path of the copy for
+	// FileCopySnapshot.
+	Version string `json:"version,omitempty"`
+	// TableFormat is the format of a file store table, e.g. "delta" or "iceberg".
+	TableFormat string `json:"tableFormat,omitempty"`
+	// Timestamp is when the snapshot was taken, in the store's clock.
+	Timestamp time.Time `json:"timestamp"`
+	// Output is true for the snapshot of the training set itself.
+	Output bool `json:"output,omitempty"`
+	// Released is true once the store may have reclaimed the snapshot's data.
+	Released bool `json:"released,omitempty"`
+}
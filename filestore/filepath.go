@@ -40,6 +40,11 @@ const (
 	CSV         FileType = "csv"
 	JSON        FileType = "json"
 	DB          FileType = "db"
+	Avro        FileType = "avro"
+	// Delta and Iceberg are table formats rather than file types: a table is a directory of
+	// Parquet data files and a log or metadata that says which of them belong to each version.
+	Delta   FileType = "delta"
+	Iceberg FileType = "iceberg"
 )
 
 const (
@@ -61,6 +66,11 @@ func (ft FileType) Matches(file string) bool {
 	return FileType(ext) == ft
 }
 
+// IsTableFormat reports whether the file type is a table format stored as a directory.
+func (ft FileType) IsTableFormat() bool {
+	return ft == Delta || ft == Iceberg
+}
+
 func IsValidFileType(file string) bool {
 	for _, fileType := range []FileType{Parquet, CSV, DB} {
 		if fileType.Matches(file) {
@@ -9,10 +9,12 @@ package health
 
 import (
 	"context"
+	"io"
 
 	"github.com/featureform/fferr"
 	"github.com/featureform/metadata"
 	"github.com/featureform/provider"
+	pc "github.com/featureform/provider/provider_config"
 	pt "github.com/featureform/provider/provider_type"
 )
 
@@ -31,12 +33,20 @@ func (h *Health) CheckProvider(ctx context.Context, name string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	p, err := provider.Get(pt.Type(rec.Type()), rec.SerializedConfig())
+	return h.checkProviderConfig(ctx, pt.Type(rec.Type()), rec.SerializedConfig())
+}
+
+func (h *Health) checkProviderConfig(ctx context.Context, t pt.Type, config pc.SerializedConfig) (bool, error) {
+	p, err := provider.Get(t, config)
 	if err != nil {
 		h.handleError(err)
 		return false, err
 	}
-	isHealthy, err := p.CheckHealth()
+	// Every check opens its own connections to the provider.
+	if closer, ok := p.(io.Closer); ok {
+		defer closer.Close()
+	}
+	isHealthy, err := provider.CheckHealth(ctx, p)
 	if err != nil {
 		h.handleError(err)
 		return false, err
@@ -49,12 +59,17 @@ func (h *Health) IsSupportedProvider(t pt.Type) bool {
 	case
 		pt.RedisOnline,
 		pt.DynamoDBOnline,
+		pt.CassandraOnline,
+		pt.MongoDBOnline,
+		pt.PineconeOnline,
+		pt.FirestoreOnline,
 		pt.PostgresOffline,
+		pt.MySqlOffline,
 		pt.SnowflakeOffline,
 		pt.ClickHouseOffline,
 		pt.SparkOffline,
 		pt.RedshiftOffline,
-		pt.FirestoreOnline:
+		pt.BigQueryOffline:
 		return true
 	default:
 		return false
@@ -0,0 +1,184 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	grpc_status "google.golang.org/grpc/status"
+
+	help "github.com/featureform/helpers"
+	"github.com/featureform/logging"
+	"github.com/featureform/metadata"
+	pb "github.com/featureform/metadata/proto"
+	pt "github.com/featureform/provider/provider_type"
+)
+
+const (
+	// ProviderHealthCheckIntervalEnv sets how often every registered provider is checked. A
+	// value of 0 disables the background checks.
+	ProviderHealthCheckIntervalEnv = "PROVIDER_HEALTH_CHECK_INTERVAL"
+	// ProviderHealthCheckTimeoutEnv bounds how long a single provider check may take.
+	ProviderHealthCheckTimeoutEnv = "PROVIDER_HEALTH_CHECK_TIMEOUT"
+
+	defaultProviderHealthCheckInterval = 5 * time.Minute
+	defaultProviderHealthCheckTimeout  = time.Minute
+)
+
+var (
+	providerHealthy = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "featureform_provider_healthy",
+			Help: "Whether the last background health check of a provider passed (1) or failed (0)",
+		},
+		[]string{"provider", "type"},
+	)
+	providerLastChecked = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "featureform_provider_health_last_checked_seconds",
+			Help: "Unix time of the last background health check of a provider",
+		},
+		[]string{"provider", "type"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(providerHealthy)
+	prometheus.MustRegister(providerLastChecked)
+}
+
+// ProviderMonitor periodically checks the health of every registered provider, stores the
+// result as the provider's status in metadata and exposes it as Prometheus gauges.
+type ProviderMonitor struct {
+	client   *metadata.Client
+	health   *Health
+	logger   logging.Logger
+	interval time.Duration
+	timeout  time.Duration
+}
+
+func NewProviderMonitor(client *metadata.Client, logger logging.Logger) *ProviderMonitor {
+	return &ProviderMonitor{
+		client:   client,
+		health:   NewHealth(client),
+		logger:   logger,
+		interval: envDuration(logger, ProviderHealthCheckIntervalEnv, defaultProviderHealthCheckInterval),
+		timeout:  envDuration(logger, ProviderHealthCheckTimeoutEnv, defaultProviderHealthCheckTimeout),
+	}
+}
+
+func envDuration(logger logging.Logger, key string, fallback time.Duration) time.Duration {
+	duration, err := help.LookupEnvDuration(key)
+	if _, notFound := err.(*help.EnvNotFound); notFound {
+		return fallback
+	} else if err != nil {
+		logger.Warnw("Invalid duration; using default", "env", key, "default", fallback, "error", err)
+		return fallback
+	}
+	return duration
+}
+
+// Start checks all providers every interval until ctx is cancelled. It returns immediately.
+func (m *ProviderMonitor) Start(ctx context.Context) {
+	if m.interval <= 0 {
+		m.logger.Infow("Background provider health checks disabled")
+		return
+	}
+	m.logger.Infow("Starting background provider health checks", "interval", m.interval)
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			m.CheckAll(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// CheckAll checks every registered provider whose type supports health checks.
+func (m *ProviderMonitor) CheckAll(ctx context.Context) {
+	providers, err := m.client.ListProviders(ctx)
+	if err != nil {
+		m.logger.Errorw("Failed to list providers for health check", "error", err)
+		return
+	}
+	for _, provider := range providers {
+		t := pt.Type(provider.Type())
+		if !m.health.IsSupportedProvider(t) {
+			continue
+		}
+		err := m.check(ctx, t, provider.SerializedConfig())
+		m.record(ctx, provider.Name(), t, err)
+	}
+}
+
+// check runs a provider's health check with a timeout. Checks of providers that support it are
+// cancelled when the timeout passes, the others are left to finish in the background.
+func (m *ProviderMonitor) check(ctx context.Context, t pt.Type, config []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		healthy, err := m.health.checkProviderConfig(ctx, t, config)
+		if err == nil && !healthy {
+			err = fmt.Errorf("provider reported itself as unhealthy")
+		}
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("health check timed out after %s", m.timeout)
+	}
+}
+
+func (m *ProviderMonitor) record(ctx context.Context, name string, t pt.Type, checkErr error) {
+	logger := m.logger.With("provider", name, "type", t)
+	healthy := 1.0
+	if checkErr != nil {
+		logger.Warnw("Provider health check failed", "error", checkErr)
+		healthy = 0
+	}
+	providerHealthy.WithLabelValues(name, t.String()).Set(healthy)
+	providerLastChecked.WithLabelValues(name, t.String()).SetToCurrentTime()
+
+	statusReq := &pb.SetStatusRequest{
+		ResourceId: &pb.ResourceID{
+			Resource:     &pb.NameVariant{Name: name},
+			ResourceType: pb.ResourceType_PROVIDER,
+		},
+		Status: providerStatus(checkErr),
+	}
+	if _, err := m.client.GrpcConn.SetResourceStatus(ctx, statusReq); err != nil {
+		logger.Errorw("Failed to set provider status", "error", err)
+	}
+}
+
+// providerStatus converts the result of a provider health check into a resource status.
+func providerStatus(err error) *pb.ResourceStatus {
+	if err == nil {
+		return &pb.ResourceStatus{Status: pb.ResourceStatus_READY}
+	}
+	status := &pb.ResourceStatus{
+		Status:       pb.ResourceStatus_FAILED,
+		ErrorMessage: err.Error(),
+	}
+	if errorStatus, ok := grpc_status.FromError(err); ok {
+		errorProto := errorStatus.Proto()
+		status.ErrorStatus = &pb.ErrorStatus{Code: errorProto.Code, Message: errorProto.Message, Details: errorProto.Details}
+	}
+	return status
+}
@@ -0,0 +1,174 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/featureform/auth"
+	"github.com/featureform/ffsync"
+	"github.com/featureform/scheduling"
+	"github.com/featureform/storage"
+)
+
+// readinessTimeout bounds how long /readyz waits on all of a service's checks.
+const readinessTimeout = 5 * time.Second
+
+// healthKeyPrefix namespaces the keys used to probe storage and locks so they never collide
+// with real metadata.
+const healthKeyPrefix = "/health/"
+
+// Check is a readiness check of one of a service's dependencies.
+type Check struct {
+	Name string
+	Fn   func(ctx context.Context) error
+}
+
+type checkResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type readinessResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks"`
+}
+
+// runChecks runs all checks concurrently and reports whether all of them passed.
+func runChecks(ctx context.Context, checks []Check) (bool, map[string]checkResult) {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+	results := make(map[string]checkResult, len(checks))
+	ready := true
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			err := check.Fn(ctx)
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				ready = false
+				results[check.Name] = checkResult{Status: "FAILED", Error: err.Error()}
+				return
+			}
+			results[check.Name] = checkResult{Status: "OK"}
+		}(check)
+	}
+	wg.Wait()
+	return ready, results
+}
+
+func readinessHandler(checks []Check) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ready, results := runChecks(r.Context(), checks)
+		resp := readinessResponse{Status: "OK", Checks: results}
+		code := http.StatusOK
+		if !ready {
+			resp.Status = "FAILED"
+			code = http.StatusServiceUnavailable
+		}
+		jsonResponse, err := json.Marshal(resp)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		w.Write(jsonResponse)
+	}
+}
+
+// handleLive reports that the process is up. It deliberately doesn't check dependencies so an
+// outage of a shared dependency doesn't restart every service.
+func handleLive(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"OK"}`))
+}
+
+// MetadataStorageCheck checks that the metadata storage backend can be queried.
+func MetadataStorageCheck(conn storage.MetadataStorage) Check {
+	return Check{
+		Name: "metadata_storage",
+		Fn: func(ctx context.Context) error {
+			_, err := conn.Count(healthKeyPrefix)
+			return err
+		},
+	}
+}
+
+// LockerCheck checks that a lock can be acquired and released.
+func LockerCheck(locker ffsync.Locker) Check {
+	return Check{
+		Name: "locker",
+		Fn: func(ctx context.Context) error {
+			key, err := locker.Lock(ctx, healthKeyPrefix+uuid.NewString(), false)
+			if err != nil {
+				return err
+			}
+			return locker.Unlock(ctx, key)
+		},
+	}
+}
+
+// TaskManagerCheck checks that the task manager can read task runs.
+func TaskManagerCheck(manager scheduling.TaskMetadataManager) Check {
+	return Check{
+		Name: "task_manager",
+		Fn: func(ctx context.Context) error {
+			return manager.CheckHealth()
+		},
+	}
+}
+
+// GRPCPeerCheck checks that the gRPC service at address reports itself as serving through the
+// standard gRPC health service. The connection is created once and reused across checks.
+func GRPCPeerCheck(name, address string) Check {
+	var once sync.Once
+	var client grpc_health_v1.HealthClient
+	var dialErr error
+	return Check{
+		Name: name,
+		Fn: func(ctx context.Context) error {
+			once.Do(func() {
+				opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+				opts = append(opts, auth.ServiceDialOptions()...)
+				conn, err := grpc.Dial(address, opts...)
+				if err != nil {
+					dialErr = err
+					return
+				}
+				client = grpc_health_v1.NewHealthClient(conn)
+			})
+			if dialErr != nil {
+				return dialErr
+			}
+			resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+			if err != nil {
+				return err
+			}
+			if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+				return fmt.Errorf("%s is %s", address, resp.Status)
+			}
+			return nil
+		},
+	}
+}
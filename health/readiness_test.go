@@ -0,0 +1,136 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/logging"
+	pb "github.com/featureform/metadata/proto"
+	"github.com/featureform/provider"
+	pc "github.com/featureform/provider/provider_config"
+	pt "github.com/featureform/provider/provider_type"
+	"github.com/featureform/scheduling"
+)
+
+func TestReadiness(t *testing.T) {
+	ctx, _ := logging.NewTestContextAndLogger(t)
+	manager, err := scheduling.NewMemoryTaskMetadataManager(ctx)
+	if err != nil {
+		t.Fatalf("failed to create task manager: %v", err)
+	}
+	healthy := []Check{
+		MetadataStorageCheck(manager.Storage),
+		LockerCheck(manager.Storage.Locker),
+		TaskManagerCheck(manager),
+	}
+	failing := Check{Name: "downstream", Fn: func(ctx context.Context) error { return errors.New("connection refused") }}
+
+	tests := []struct {
+		name         string
+		checks       []Check
+		expectedCode int
+	}{
+		{"Healthy", healthy, http.StatusOK},
+		{"Failing dependency", append(healthy, failing), http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/readyz", nil)
+			rr := httptest.NewRecorder()
+			readinessHandler(tt.checks).ServeHTTP(rr, req)
+			if rr.Code != tt.expectedCode {
+				t.Fatalf("handler returned wrong status code: got %v want %v: %s", rr.Code, tt.expectedCode, rr.Body.String())
+			}
+			var resp readinessResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response body: %v", err)
+			}
+			if len(resp.Checks) != len(tt.checks) {
+				t.Fatalf("expected %d check results, got %v", len(tt.checks), resp.Checks)
+			}
+			for _, check := range healthy {
+				if resp.Checks[check.Name].Status != "OK" {
+					t.Errorf("expected %s to pass, got %v", check.Name, resp.Checks[check.Name])
+				}
+			}
+			if result, ok := resp.Checks[failing.Name]; ok && result.Error != "connection refused" {
+				t.Errorf("expected failing check to report its error, got %v", result)
+			}
+		})
+	}
+}
+
+func TestLiveness(t *testing.T) {
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(handleLive).ServeHTTP(rr, httptest.NewRequest("GET", "/livez", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+}
+
+func TestProviderStatus(t *testing.T) {
+	if status := providerStatus(nil); status.Status != pb.ResourceStatus_READY {
+		t.Fatalf("expected healthy provider to be READY, got %v", status)
+	}
+	status := providerStatus(fferr.NewConnectionError("POSTGRES_OFFLINE", errors.New("connection refused")))
+	if status.Status != pb.ResourceStatus_FAILED || status.ErrorStatus == nil {
+		t.Fatalf("expected failed provider to have an error status, got %v", status)
+	}
+	status = providerStatus(errors.New("health check timed out"))
+	if status.Status != pb.ResourceStatus_FAILED || status.ErrorMessage != "health check timed out" {
+		t.Fatalf("expected failed provider to keep its error message, got %v", status)
+	}
+}
+
+// hangingProvider's health check only returns once its context is done.
+type hangingProvider struct {
+	provider.BaseProvider
+	cancelled chan struct{}
+	closed    chan struct{}
+}
+
+func (p *hangingProvider) CheckHealthContext(ctx context.Context) (bool, error) {
+	<-ctx.Done()
+	close(p.cancelled)
+	return false, ctx.Err()
+}
+
+func (p *hangingProvider) Close() error {
+	close(p.closed)
+	return nil
+}
+
+func TestProviderMonitorCheckTimeout(t *testing.T) {
+	hanging := &hangingProvider{cancelled: make(chan struct{}), closed: make(chan struct{})}
+	hangingType := pt.Type("HANGING_TEST")
+	err := provider.RegisterFactory(hangingType, func(pc.SerializedConfig) (provider.Provider, error) {
+		return hanging, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	monitor := &ProviderMonitor{health: NewHealth(nil), logger: logging.NewTestLogger(t), timeout: 10 * time.Millisecond}
+	if err := monitor.check(context.Background(), hangingType, nil); err == nil {
+		t.Fatalf("Expected the hanging check to time out")
+	}
+	for name, done := range map[string]chan struct{}{"cancelled": hanging.cancelled, "closed": hanging.closed} {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected the timed out check to be %s", name)
+		}
+	}
+}
@@ -16,6 +16,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
 	help "github.com/featureform/helpers"
 	"github.com/featureform/logging"
 )
@@ -77,7 +79,9 @@ func validatePort(port string) error {
 	return nil
 }
 
-func StartHttpServer(logger logging.Logger, port string) error {
+// StartHttpServer serves the status, liveness and readiness endpoints and Prometheus metrics.
+// /readyz runs the given checks of the service's dependencies on every request.
+func StartHttpServer(logger logging.Logger, port string, checks ...Check) error {
 	err := validatePort(port)
 	if err != nil {
 		return err
@@ -92,6 +96,9 @@ func StartHttpServer(logger logging.Logger, port string) error {
 	// e.g. /_ah/live, /_ah/ready and /_ah/lb
 	// Create separate routes for specific health requests as needed.
 	mux.HandleFunc("/_ah/", handleHealthCheck)
+	mux.HandleFunc("/livez", handleLive)
+	mux.HandleFunc("/readyz", readinessHandler(checks))
+	mux.Handle("/metrics", promhttp.Handler())
 	mux.HandleFunc("/", handleIndex)
 	// Add more routes as needed.
 
@@ -0,0 +1,29 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package interceptors
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+// Tracing is done with otelgrpc stats handlers rather than interceptors. gRPC reports when a
+// call actually finishes to stats handlers, so client stream spans end on the response of
+// unary-response streams, and on EOF, an error or context cancellation for server streams,
+// even when the caller never drains the stream.
+
+// TracingServerOptions returns the server options that continue the caller's trace, or start a
+// new one, for each call and stream.
+func TracingServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{grpc.StatsHandler(otelgrpc.NewServerHandler())}
+}
+
+// TracingDialOptions returns the dial options that propagate traces to other services.
+func TracingDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{grpc.WithStatsHandler(otelgrpc.NewClientHandler())}
+}
@@ -0,0 +1,122 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package interceptors
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// tracedHealthClient serves the health service in process with tracing on both ends.
+func tracedHealthClient(t *testing.T) (grpc_health_v1.HealthClient, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(TracingServerOptions()...)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	opts := append(TracingDialOptions(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+	)
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return grpc_health_v1.NewHealthClient(conn), exporter
+}
+
+// waitForSpan returns the span with name and kind once it has ended.
+func waitForSpan(t *testing.T, exporter *tracetest.InMemoryExporter, name string, kind trace.SpanKind) tracetest.SpanStub {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, span := range exporter.GetSpans() {
+			if span.Name == name && span.SpanKind == kind {
+				return span
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Expected %s span %s to end", kind, name)
+	return tracetest.SpanStub{}
+}
+
+func hasAttribute(span tracetest.SpanStub, want attribute.KeyValue) bool {
+	for _, attr := range span.Attributes {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTracingPropagation(t *testing.T) {
+	client, exporter := tracedHealthClient(t)
+	if _, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown"}); err == nil {
+		t.Fatalf("Expected the server error to be returned")
+	}
+
+	const method = "grpc.health.v1.Health/Check"
+	clientSpan := waitForSpan(t, exporter, method, trace.SpanKindClient)
+	serverSpan := waitForSpan(t, exporter, method, trace.SpanKindServer)
+	if serverSpan.SpanContext.TraceID() != clientSpan.SpanContext.TraceID() || serverSpan.Parent.SpanID() != clientSpan.SpanContext.SpanID() {
+		t.Fatalf("Expected the server span to continue the client's trace")
+	}
+	// Following the semantic conventions, NotFound is only an error on the client.
+	if clientSpan.Status.Code != codes.Error {
+		t.Fatalf("Expected the client span to record the error, got %v", clientSpan.Status)
+	}
+	if !hasAttribute(serverSpan, semconv.RPCGRPCStatusCodeKey.Int(int(grpccodes.NotFound))) {
+		t.Fatalf("Expected the server span to record the status code, got %v", serverSpan.Attributes)
+	}
+}
+
+func TestTracingEndsCancelledStreams(t *testing.T) {
+	client, exporter := tracedHealthClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Failed to watch: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Failed to receive: %v", err)
+	}
+	// Watch never ends on its own, the span has to end when the caller gives up on the stream
+	// without reading it to the end.
+	cancel()
+	waitForSpan(t, exporter, "grpc.health.v1.Health/Watch", trace.SpanKindClient)
+}
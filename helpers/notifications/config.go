@@ -0,0 +1,144 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package notifications
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/logging"
+)
+
+const (
+	WebhookSinkType = "webhook"
+	SlackSinkType   = "slack"
+	EmailSinkType   = "email"
+)
+
+// SinkConfig configures one sink. Secrets can be read from environment variables with the
+// *Env fields instead of being written into the file.
+type SinkConfig struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	SecretEnv   string   `json:"secretEnv"`
+	SMTPHost    string   `json:"smtpHost"`
+	SMTPPort    int      `json:"smtpPort"`
+	Username    string   `json:"username"`
+	Password    string   `json:"password"`
+	PasswordEnv string   `json:"passwordEnv"`
+	From        string   `json:"from"`
+	To          []string `json:"to"`
+}
+
+// Config is the contents of the notifications config file.
+type Config struct {
+	Sinks        []SinkConfig `json:"sinks"`
+	Rules        []Rule       `json:"rules"`
+	MaxAttempts  int          `json:"maxAttempts"`
+	RetryBackoff string       `json:"retryBackoff"`
+	DedupWindow  string       `json:"dedupWindow"`
+}
+
+func valueOrEnv(value, env string) string {
+	if env != "" {
+		return os.Getenv(env)
+	}
+	return value
+}
+
+func (c SinkConfig) sink() (Sink, error) {
+	if c.Name == "" {
+		return nil, fferr.NewInvalidArgumentErrorf("notification sink of type %s has no name", c.Type)
+	}
+	switch c.Type {
+	case WebhookSinkType:
+		if c.URL == "" {
+			return nil, fferr.NewInvalidArgumentErrorf("webhook sink %s has no url", c.Name)
+		}
+		return NewWebhookSink(c.Name, c.URL, valueOrEnv(c.Secret, c.SecretEnv)), nil
+	case SlackSinkType:
+		if c.URL == "" {
+			return nil, fferr.NewInvalidArgumentErrorf("slack sink %s has no url", c.Name)
+		}
+		return NewSlackWebhookSink(c.Name, c.URL), nil
+	case EmailSinkType:
+		if c.SMTPHost == "" || c.From == "" || len(c.To) == 0 {
+			return nil, fferr.NewInvalidArgumentErrorf("email sink %s requires smtpHost, from and to", c.Name)
+		}
+		port := c.SMTPPort
+		if port == 0 {
+			port = 587
+		}
+		return NewEmailSink(c.Name, c.SMTPHost, port, c.Username, valueOrEnv(c.Password, c.PasswordEnv), c.From, c.To), nil
+	default:
+		return nil, fferr.NewInvalidArgumentErrorf("unknown notification sink type %q for sink %s", c.Type, c.Name)
+	}
+}
+
+func parseDuration(field, value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fferr.NewInvalidArgumentErrorf("invalid notifications %s %q: %v", field, value, err)
+	}
+	return duration, nil
+}
+
+// NewDispatcherFromConfig builds the sinks and rules described by cfg.
+func NewDispatcherFromConfig(logger logging.Logger, cfg Config) (*Dispatcher, error) {
+	sinks := make([]Sink, len(cfg.Sinks))
+	for i, sinkCfg := range cfg.Sinks {
+		sink, err := sinkCfg.sink()
+		if err != nil {
+			return nil, err
+		}
+		sinks[i] = sink
+	}
+	var opts []DispatcherOption
+	if cfg.MaxAttempts > 0 || cfg.RetryBackoff != "" {
+		policy := RetryPolicy{MaxAttempts: defaultMaxAttempts, Backoff: defaultRetryBackoff}
+		if cfg.MaxAttempts > 0 {
+			policy.MaxAttempts = cfg.MaxAttempts
+		}
+		if cfg.RetryBackoff != "" {
+			backoff, err := parseDuration("retryBackoff", cfg.RetryBackoff)
+			if err != nil {
+				return nil, err
+			}
+			policy.Backoff = backoff
+		}
+		opts = append(opts, WithRetryPolicy(policy))
+	}
+	if cfg.DedupWindow != "" {
+		window, err := parseDuration("dedupWindow", cfg.DedupWindow)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithDedupWindow(window))
+	}
+	return NewDispatcher(logger, sinks, cfg.Rules, opts...)
+}
+
+// NewDispatcherFromFile reads a JSON Config from path. It returns nil, and no error, if path is empty.
+func NewDispatcherFromFile(logger logging.Logger, path string) (*Dispatcher, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fferr.NewInternalErrorf("failed to read notifications config %s: %w", path, err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fferr.NewInvalidArgumentErrorf("failed to parse notifications config %s: %v", path, err)
+	}
+	return NewDispatcherFromConfig(logger, cfg)
+}
@@ -0,0 +1,266 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/logging"
+)
+
+const (
+	defaultMaxAttempts  = 3
+	defaultRetryBackoff = time.Second
+	defaultDedupWindow  = 10 * time.Minute
+)
+
+// RunEvent describes a task run's status transition.
+type RunEvent struct {
+	TaskID         string    `json:"taskId"`
+	RunID          string    `json:"runId"`
+	RunName        string    `json:"runName"`
+	ResourceType   string    `json:"resourceType"`
+	Name           string    `json:"name"`
+	Variant        string    `json:"variant"`
+	Owner          string    `json:"owner"`
+	PreviousStatus string    `json:"previousStatus"`
+	Status         string    `json:"status"`
+	Error          string    `json:"error"`
+	Time           time.Time `json:"time"`
+}
+
+// Key identifies the transition, so redelivering the same transition can be detected.
+func (e RunEvent) Key() string {
+	return fmt.Sprintf("%s/%s/%s", e.TaskID, e.RunID, e.Status)
+}
+
+// RunNotifier is told about every task run status transition.
+type RunNotifier interface {
+	NotifyRun(ctx context.Context, event RunEvent) error
+}
+
+// OwnerResolver looks up the owner of a resource so rules can match on RunEvent.Owner.
+type OwnerResolver func(ctx context.Context, resourceType, name, variant string) (string, error)
+
+// Sink delivers a RunEvent to an external system.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, event RunEvent) error
+}
+
+// permanentError marks a delivery failure that retrying won't fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Rule subscribes sinks to the events it matches. An empty field matches everything.
+type Rule struct {
+	ResourceTypes []string `json:"resourceTypes"`
+	Resources     []string `json:"resources"`
+	Variants      []string `json:"variants"`
+	Owners        []string `json:"owners"`
+	Statuses      []string `json:"statuses"`
+	Sinks         []string `json:"sinks"`
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches returns true if the event satisfies every filter in the rule.
+func (r Rule) Matches(event RunEvent) bool {
+	return matchesAny(r.ResourceTypes, event.ResourceType) &&
+		matchesAny(r.Resources, event.Name) &&
+		matchesAny(r.Variants, event.Variant) &&
+		matchesAny(r.Owners, event.Owner) &&
+		matchesAny(r.Statuses, event.Status)
+}
+
+// RetryPolicy controls how failed deliveries are retried. The backoff doubles after each attempt.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// dedupCache remembers which deliveries have been made, or are in flight, within a window.
+type dedupCache struct {
+	mtx    sync.Mutex
+	window time.Duration
+	seen   map[string]time.Time
+	now    func() time.Time
+}
+
+func newDedupCache(window time.Duration) *dedupCache {
+	return &dedupCache{window: window, seen: make(map[string]time.Time), now: time.Now}
+}
+
+// reserve returns false if the key was delivered within the window or is being delivered now.
+func (c *dedupCache) reserve(key string) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	now := c.now()
+	for k, t := range c.seen {
+		if now.Sub(t) > c.window {
+			delete(c.seen, k)
+		}
+	}
+	if _, ok := c.seen[key]; ok {
+		return false
+	}
+	c.seen[key] = now
+	return true
+}
+
+// release forgets a key whose delivery failed so it can be tried again.
+func (c *dedupCache) release(key string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.seen, key)
+}
+
+// Dispatcher routes RunEvents to the sinks subscribed by its rules, retrying failed deliveries
+// and dropping duplicates.
+type Dispatcher struct {
+	sinks  map[string]Sink
+	rules  []Rule
+	retry  RetryPolicy
+	dedup  *dedupCache
+	logger logging.Logger
+}
+
+// DispatcherOption configures a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// WithRetryPolicy overrides the default of three attempts starting with a one second backoff.
+func WithRetryPolicy(policy RetryPolicy) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.retry = policy
+	}
+}
+
+// WithDedupWindow overrides how long a delivered transition is remembered. Defaults to 10 minutes.
+func WithDedupWindow(window time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.dedup = newDedupCache(window)
+	}
+}
+
+// NewDispatcher creates a Dispatcher. Every sink named in a rule must be in sinks.
+func NewDispatcher(logger logging.Logger, sinks []Sink, rules []Rule, opts ...DispatcherOption) (*Dispatcher, error) {
+	sinksByName := make(map[string]Sink, len(sinks))
+	for _, sink := range sinks {
+		if _, ok := sinksByName[sink.Name()]; ok {
+			return nil, fferr.NewInvalidArgumentErrorf("duplicate notification sink %s", sink.Name())
+		}
+		sinksByName[sink.Name()] = sink
+	}
+	for i, rule := range rules {
+		if len(rule.Sinks) == 0 {
+			return nil, fferr.NewInvalidArgumentErrorf("notification rule %d has no sinks", i)
+		}
+		for _, name := range rule.Sinks {
+			if _, ok := sinksByName[name]; !ok {
+				return nil, fferr.NewInvalidArgumentErrorf("notification rule %d references unknown sink %s", i, name)
+			}
+		}
+	}
+	d := &Dispatcher{
+		sinks:  sinksByName,
+		rules:  rules,
+		retry:  RetryPolicy{MaxAttempts: defaultMaxAttempts, Backoff: defaultRetryBackoff},
+		dedup:  newDedupCache(defaultDedupWindow),
+		logger: logger,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.retry.MaxAttempts < 1 {
+		d.retry.MaxAttempts = 1
+	}
+	return d, nil
+}
+
+// NotifyRun delivers the event to every sink subscribed to it. Each sink gets the event at most
+// once, even when several rules match.
+func (d *Dispatcher) NotifyRun(ctx context.Context, event RunEvent) error {
+	var errs []error
+	for _, name := range d.matchingSinks(event) {
+		if err := d.deliver(ctx, d.sinks[name], event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d *Dispatcher) matchingSinks(event RunEvent) []string {
+	added := make(map[string]bool)
+	names := make([]string, 0)
+	for _, rule := range d.rules {
+		if !rule.Matches(event) {
+			continue
+		}
+		for _, name := range rule.Sinks {
+			if !added[name] {
+				added[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, sink Sink, event RunEvent) error {
+	key := fmt.Sprintf("%s/%s", sink.Name(), event.Key())
+	if !d.dedup.reserve(key) {
+		d.logger.Debugw("Skipping duplicate notification", "sink", sink.Name(), "event", event.Key())
+		return nil
+	}
+	backoff := d.retry.Backoff
+	var err error
+	for attempt := 1; attempt <= d.retry.MaxAttempts; attempt++ {
+		if err = sink.Send(ctx, event); err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt == d.retry.MaxAttempts {
+			break
+		}
+		d.logger.Warnw("Notification failed, retrying", "sink", sink.Name(), "event", event.Key(), "attempt", attempt, "err", err)
+		select {
+		case <-ctx.Done():
+			d.dedup.release(key)
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	d.dedup.release(key)
+	d.logger.Errorw("Failed to deliver notification", "sink", sink.Name(), "event", event.Key(), "err", err)
+	return fferr.NewInternalErrorf("notification sink %s failed: %w", sink.Name(), err)
+}
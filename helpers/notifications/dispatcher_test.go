@@ -0,0 +1,334 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/featureform/logging"
+)
+
+func testEvent() RunEvent {
+	return RunEvent{
+		TaskID:         "1",
+		RunID:          "2",
+		ResourceType:   "SOURCE_VARIANT",
+		Name:           "transactions",
+		Variant:        "v1",
+		Owner:          "alice",
+		PreviousStatus: "RUNNING",
+		Status:         "FAILED",
+		Error:          "transformation failed",
+		Time:           time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func testLogger(t *testing.T) logging.Logger {
+	return logging.WrapZapLogger(zaptest.NewLogger(t).Sugar())
+}
+
+var fastRetries = WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+
+// webhookServer responds with the given status codes in order, then with 200.
+type webhookServer struct {
+	*httptest.Server
+	mtx      sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newWebhookServer(t *testing.T, statuses ...int) *webhookServer {
+	s := &webhookServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read body: %v", err)
+		}
+		s.mtx.Lock()
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, body)
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		s.mtx.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *webhookServer) count() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return len(s.requests)
+}
+
+func TestWebhookSinkSignsPayload(t *testing.T) {
+	server := newWebhookServer(t)
+	event := testEvent()
+	if err := NewWebhookSink("hook", server.URL, "s3cret").Send(context.Background(), event); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if server.count() != 1 {
+		t.Fatalf("Expected one request, got %d", server.count())
+	}
+	req, body := server.requests[0], server.bodies[0]
+	assert.Equal(t, Sign("s3cret", body), req.Header.Get(SignatureHeader))
+	assert.Equal(t, event.Key(), req.Header.Get(DeliveryHeader))
+	var received RunEvent
+	if err := json.Unmarshal(body, &received); err != nil {
+		t.Fatalf("Failed to parse payload: %v", err)
+	}
+	assert.Equal(t, event, received)
+}
+
+func TestSlackWebhookSink(t *testing.T) {
+	server := newWebhookServer(t)
+	if err := NewSlackWebhookSink("slack", server.URL).Send(context.Background(), testEvent()); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	var msg map[string]string
+	if err := json.Unmarshal(server.bodies[0], &msg); err != nil {
+		t.Fatalf("Failed to parse payload: %v", err)
+	}
+	assert.Contains(t, msg["text"], "Resource: transactions (v1)")
+	assert.Contains(t, msg["text"], "Status: RUNNING -> FAILED")
+	assert.Contains(t, msg["text"], "Error Message: transformation failed")
+}
+
+func TestEmailSink(t *testing.T) {
+	sink := NewEmailSink("email", "smtp.example.com", 25, "", "", "ff@example.com", []string{"oncall@example.com"})
+	var addr string
+	var msg []byte
+	sink.sendMail = func(a string, _ smtp.Auth, from string, to []string, m []byte) error {
+		addr, msg = a, m
+		return nil
+	}
+	if err := sink.Send(context.Background(), testEvent()); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	assert.Equal(t, "smtp.example.com:25", addr)
+	assert.Contains(t, string(msg), "Subject: [Featureform] SOURCE_VARIANT transactions (v1) is FAILED")
+	assert.Contains(t, string(msg), "To: oncall@example.com")
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	server := newWebhookServer(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+	rules := []Rule{{Sinks: []string{"hook"}}}
+	dispatcher, err := NewDispatcher(testLogger(t), []Sink{NewWebhookSink("hook", server.URL, "")}, rules, fastRetries)
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+	if err := dispatcher.NotifyRun(context.Background(), testEvent()); err != nil {
+		t.Fatalf("NotifyRun failed: %v", err)
+	}
+	assert.Equal(t, 3, server.count())
+}
+
+func TestDispatcherGivesUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		requests int
+	}{
+		{"Server Errors", []int{500, 500, 500, 500}, 3},
+		{"Client Error", []int{http.StatusBadRequest}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newWebhookServer(t, tt.statuses...)
+			rules := []Rule{{Sinks: []string{"hook"}}}
+			dispatcher, err := NewDispatcher(testLogger(t), []Sink{NewWebhookSink("hook", server.URL, "")}, rules, fastRetries)
+			if err != nil {
+				t.Fatalf("Failed to create dispatcher: %v", err)
+			}
+			if err := dispatcher.NotifyRun(context.Background(), testEvent()); err == nil {
+				t.Fatalf("Expected NotifyRun to fail")
+			}
+			assert.Equal(t, tt.requests, server.count())
+		})
+	}
+}
+
+func TestDispatcherDeduplicates(t *testing.T) {
+	server := newWebhookServer(t)
+	// Both rules match, but the sink should only be sent the event once.
+	rules := []Rule{
+		{Statuses: []string{"FAILED"}, Sinks: []string{"hook"}},
+		{Owners: []string{"alice"}, Sinks: []string{"hook"}},
+	}
+	dispatcher, err := NewDispatcher(testLogger(t), []Sink{NewWebhookSink("hook", server.URL, "")}, rules, fastRetries)
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := dispatcher.NotifyRun(context.Background(), testEvent()); err != nil {
+				t.Errorf("NotifyRun failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, server.count())
+
+	// A different transition of the same run is a new event.
+	succeeded := testEvent()
+	succeeded.Status = "READY"
+	if err := dispatcher.NotifyRun(context.Background(), succeeded); err != nil {
+		t.Fatalf("NotifyRun failed: %v", err)
+	}
+	assert.Equal(t, 2, server.count())
+}
+
+func TestDispatcherRedeliversAfterFailure(t *testing.T) {
+	server := newWebhookServer(t, http.StatusBadRequest)
+	rules := []Rule{{Sinks: []string{"hook"}}}
+	dispatcher, err := NewDispatcher(testLogger(t), []Sink{NewWebhookSink("hook", server.URL, "")}, rules, fastRetries)
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+	if err := dispatcher.NotifyRun(context.Background(), testEvent()); err == nil {
+		t.Fatalf("Expected first delivery to fail")
+	}
+	if err := dispatcher.NotifyRun(context.Background(), testEvent()); err != nil {
+		t.Fatalf("Expected redelivery to succeed: %v", err)
+	}
+	assert.Equal(t, 2, server.count())
+}
+
+func TestDedupWindowExpires(t *testing.T) {
+	cache := newDedupCache(time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	assert.True(t, cache.reserve("key"))
+	assert.False(t, cache.reserve("key"))
+	now = now.Add(2 * time.Minute)
+	assert.True(t, cache.reserve("key"))
+}
+
+type countingSink struct {
+	name  string
+	count atomic.Int32
+}
+
+func (s *countingSink) Name() string {
+	return s.name
+}
+
+func (s *countingSink) Send(ctx context.Context, event RunEvent) error {
+	s.count.Add(1)
+	return nil
+}
+
+func TestDispatcherRules(t *testing.T) {
+	failures := &countingSink{name: "failures"}
+	alice := &countingSink{name: "alice"}
+	transactions := &countingSink{name: "transactions"}
+	rules := []Rule{
+		{Statuses: []string{"FAILED"}, Sinks: []string{"failures"}},
+		{Owners: []string{"alice"}, Sinks: []string{"alice"}},
+		{ResourceTypes: []string{"SOURCE_VARIANT"}, Resources: []string{"transactions"}, Sinks: []string{"transactions"}},
+	}
+	dispatcher, err := NewDispatcher(testLogger(t), []Sink{failures, alice, transactions}, rules)
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+	events := []RunEvent{
+		testEvent(),
+		{TaskID: "3", RunID: "1", ResourceType: "FEATURE_VARIANT", Name: "avg", Owner: "bob", Status: "FAILED"},
+		{TaskID: "4", RunID: "1", ResourceType: "SOURCE_VARIANT", Name: "transactions", Owner: "bob", Status: "READY"},
+		{TaskID: "5", RunID: "1", ResourceType: "LABEL_VARIANT", Name: "fraud", Owner: "bob", Status: "READY"},
+	}
+	for _, event := range events {
+		if err := dispatcher.NotifyRun(context.Background(), event); err != nil {
+			t.Fatalf("NotifyRun failed: %v", err)
+		}
+	}
+	assert.Equal(t, int32(2), failures.count.Load())
+	assert.Equal(t, int32(1), alice.count.Load())
+	assert.Equal(t, int32(2), transactions.count.Load())
+}
+
+func TestNewDispatcherValidation(t *testing.T) {
+	sink := &countingSink{name: "hook"}
+	tests := []struct {
+		name  string
+		sinks []Sink
+		rules []Rule
+	}{
+		{"Unknown Sink", []Sink{sink}, []Rule{{Sinks: []string{"missing"}}}},
+		{"No Sinks", []Sink{sink}, []Rule{{Statuses: []string{"FAILED"}}}},
+		{"Duplicate Sink", []Sink{sink, sink}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDispatcher(testLogger(t), tt.sinks, tt.rules); err == nil {
+				t.Fatalf("Expected an error")
+			}
+		})
+	}
+}
+
+func TestNewDispatcherFromFile(t *testing.T) {
+	dispatcher, err := NewDispatcherFromFile(testLogger(t), "")
+	if err != nil || dispatcher != nil {
+		t.Fatalf("Expected no dispatcher without a config file, got %v, %v", dispatcher, err)
+	}
+
+	t.Setenv("TEST_WEBHOOK_SECRET", "from-env")
+	path := filepath.Join(t.TempDir(), "notifications.json")
+	config := `{
+		"sinks": [
+			{"name": "hook", "type": "webhook", "url": "http://localhost/hook", "secretEnv": "TEST_WEBHOOK_SECRET"},
+			{"name": "slack", "type": "slack", "url": "http://localhost/slack"},
+			{"name": "email", "type": "email", "smtpHost": "smtp.example.com", "from": "ff@example.com", "to": ["oncall@example.com"]}
+		],
+		"rules": [{"statuses": ["FAILED"], "sinks": ["hook", "slack", "email"]}],
+		"maxAttempts": 5,
+		"retryBackoff": "2s",
+		"dedupWindow": "1h"
+	}`
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	dispatcher, err = NewDispatcherFromFile(testLogger(t), path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	assert.Equal(t, RetryPolicy{MaxAttempts: 5, Backoff: 2 * time.Second}, dispatcher.retry)
+	assert.Equal(t, time.Hour, dispatcher.dedup.window)
+	assert.Equal(t, "from-env", dispatcher.sinks["hook"].(*WebhookSink).secret)
+	assert.Equal(t, "smtp.example.com:587", dispatcher.sinks["email"].(*EmailSink).addr)
+
+	if err := os.WriteFile(path, []byte(strings.Replace(config, `"webhook"`, `"pager"`, 1)), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := NewDispatcherFromFile(testLogger(t), path); err == nil {
+		t.Fatalf("Expected an unknown sink type to fail")
+	}
+}
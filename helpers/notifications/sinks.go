@@ -0,0 +1,188 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	help "github.com/featureform/helpers"
+)
+
+const (
+	// SignatureHeader carries the hex encoded HMAC-SHA256 of the webhook body, prefixed with "sha256=".
+	SignatureHeader = "X-Featureform-Signature"
+	// DeliveryHeader carries RunEvent.Key so receivers can drop redeliveries.
+	DeliveryHeader = "X-Featureform-Delivery"
+
+	defaultSinkTimeout = 10 * time.Second
+)
+
+// Sign returns the value of SignatureHeader for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("%s responded with %d: %s", url, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	// Client errors other than throttling won't succeed on retry.
+	if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return &permanentError{err}
+	}
+	return err
+}
+
+// WebhookSink POSTs the RunEvent as JSON, signed with a shared secret if one is set.
+type WebhookSink struct {
+	name   string
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookSink(name, url, secret string) *WebhookSink {
+	return &WebhookSink{
+		name:   name,
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: defaultSinkTimeout},
+	}
+}
+
+func (s *WebhookSink) Name() string {
+	return s.name
+}
+
+func (s *WebhookSink) Send(ctx context.Context, event RunEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return &permanentError{err}
+	}
+	headers := map[string]string{DeliveryHeader: event.Key()}
+	if s.secret != "" {
+		headers[SignatureHeader] = Sign(s.secret, body)
+	}
+	return postJSON(ctx, s.client, s.url, body, headers)
+}
+
+// SlackWebhookSink posts a message to a Slack incoming webhook.
+type SlackWebhookSink struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+func NewSlackWebhookSink(name, url string) *SlackWebhookSink {
+	return &SlackWebhookSink{
+		name:   name,
+		url:    url,
+		client: &http.Client{Timeout: defaultSinkTimeout},
+	}
+}
+
+func (s *SlackWebhookSink) Name() string {
+	return s.name
+}
+
+func (s *SlackWebhookSink) Send(ctx context.Context, event RunEvent) error {
+	body, err := json.Marshal(map[string]string{"text": formatRunEvent(event)})
+	if err != nil {
+		return &permanentError{err}
+	}
+	return postJSON(ctx, s.client, s.url, body, nil)
+}
+
+// EmailSink sends a plain text email through an SMTP server.
+type EmailSink struct {
+	name     string
+	addr     string
+	auth     smtp.Auth
+	from     string
+	to       []string
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailSink creates an EmailSink. Authentication is skipped when username is empty.
+func NewEmailSink(name, host string, port int, username, password, from string, to []string) *EmailSink {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &EmailSink{
+		name:     name,
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		auth:     auth,
+		from:     from,
+		to:       to,
+		sendMail: smtp.SendMail,
+	}
+}
+
+func (s *EmailSink) Name() string {
+	return s.name
+}
+
+func (s *EmailSink) Send(ctx context.Context, event RunEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	subject := fmt.Sprintf("[Featureform] %s %s (%s) is %s", event.ResourceType, event.Name, event.Variant, event.Status)
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.from, strings.Join(s.to, ", "), subject, formatRunEvent(event))
+	return s.sendMail(s.addr, s.auth, s.from, s.to, []byte(msg))
+}
+
+func formatRunEvent(event RunEvent) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Resource Type: %s\n", event.ResourceType)
+	fmt.Fprintf(&sb, "Resource: %s (%s)\n", event.Name, event.Variant)
+	fmt.Fprintf(&sb, "Status: %s -> %s\n", event.PreviousStatus, event.Status)
+	if event.Owner != "" {
+		fmt.Fprintf(&sb, "Owner: %s\n", event.Owner)
+	}
+	fmt.Fprintf(&sb, "Task: %s, Run: %s\n", event.TaskID, event.RunID)
+	if event.Error != "" {
+		fmt.Fprintf(&sb, "Error Message: %s\n", event.Error)
+	}
+	dashboardUrl, err := help.BuildDashboardUrl(help.GetEnv("FEATUREFORM_HOST", "localhost"), event.ResourceType, event.Name, event.Variant)
+	if err == nil {
+		fmt.Fprintf(&sb, "DashboardUrl: %s", dashboardUrl)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,95 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/logging"
+)
+
+const driverName = "sqlite3"
+
+// DB wraps a database/sql handle to an embedded SQLite file.
+type DB struct {
+	*sql.DB
+}
+
+type Config struct {
+	// Path is the location of the database file, it's created if it doesn't exist.
+	Path string
+}
+
+func (c Config) Redacted() map[string]any {
+	return map[string]any{
+		"Path": c.Path,
+	}
+}
+
+func (c Config) ConnectionString() string {
+	params := url.Values{
+		// WAL lets readers continue while the single writer is active.
+		"_journal_mode": []string{"WAL"},
+		"_busy_timeout": []string{"5000"},
+		"_foreign_keys": []string{"on"},
+		// Postgres' LIKE is case-sensitive, we match it so prefix queries behave the same.
+		"_cslike": []string{"true"},
+		// Take the write lock when a transaction begins to avoid lock upgrade deadlocks.
+		"_txlock": []string{"immediate"},
+	}
+	return fmt.Sprintf("file:%s?%s", c.Path, params.Encode())
+}
+
+func NewDB(ctx context.Context, config Config) (*DB, error) {
+	logger := logging.GetLoggerFromContext(ctx).With("sqlite-config", config.Redacted())
+	logger.Info("Opening sqlite database")
+	if config.Path == "" {
+		logger.Error("SQLite path not set")
+		return nil, fferr.NewInvalidConfigf("SQLite path not set")
+	}
+	if dir := filepath.Dir(config.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			logger.Errorw("Failed to create sqlite directory", "dir", dir, "err", err)
+			return nil, fferr.NewInternalErrorf("failed to create directory for sqlite database %s: %w", config.Path, err)
+		}
+	}
+	db, err := sql.Open(driverName, config.ConnectionString())
+	if err != nil {
+		logger.Errorw("Failed to open sqlite database", "err", err)
+		return nil, fferr.NewConnectionError(config.Path, err)
+	}
+	// SQLite only allows a single writer at a time. Funneling everything through one
+	// connection serializes writes in process rather than relying on busy retries.
+	db.SetMaxOpenConns(1)
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+	}
+	if err := db.PingContext(ctx); err != nil {
+		logger.Errorw("Failed to ping sqlite database", "err", err)
+		db.Close()
+		return nil, fferr.NewConnectionError(config.Path, err)
+	}
+	logger.Info("Opened sqlite database")
+	return &DB{db}, nil
+}
+
+func Sanitize(ident string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(ident, `"`, `""`))
+}
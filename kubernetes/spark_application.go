@@ -0,0 +1,220 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package kubernetes
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/featureform/fferr"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	kubernetes "k8s.io/client-go/kubernetes"
+	rest "k8s.io/client-go/rest"
+)
+
+// SparkApplicationGVR identifies the SparkApplication custom resource installed by the
+// Kubernetes Spark Operator.
+var SparkApplicationGVR = schema.GroupVersionResource{
+	Group:    "sparkoperator.k8s.io",
+	Version:  "v1beta2",
+	Resource: "sparkapplications",
+}
+
+const sparkApplicationKind = "SparkApplication"
+
+// SparkApplicationState is the applicationState.state reported by the Spark Operator.
+type SparkApplicationState string
+
+const (
+	SparkApplicationNew              SparkApplicationState = ""
+	SparkApplicationSubmitted        SparkApplicationState = "SUBMITTED"
+	SparkApplicationRunning          SparkApplicationState = "RUNNING"
+	SparkApplicationCompleted        SparkApplicationState = "COMPLETED"
+	SparkApplicationFailed           SparkApplicationState = "FAILED"
+	SparkApplicationSubmissionFailed SparkApplicationState = "SUBMISSION_FAILED"
+	SparkApplicationPendingRerun     SparkApplicationState = "PENDING_RERUN"
+	SparkApplicationInvalidating     SparkApplicationState = "INVALIDATING"
+	SparkApplicationSucceeding       SparkApplicationState = "SUCCEEDING"
+	SparkApplicationFailing          SparkApplicationState = "FAILING"
+	SparkApplicationUnknown          SparkApplicationState = "UNKNOWN"
+)
+
+// IsTerminal returns true once the operator will no longer change the application's state.
+func (s SparkApplicationState) IsTerminal() bool {
+	switch s {
+	case SparkApplicationCompleted, SparkApplicationFailed, SparkApplicationSubmissionFailed:
+		return true
+	}
+	return false
+}
+
+// SparkApplicationStatus is the subset of a SparkApplication's status that we act on.
+type SparkApplicationStatus struct {
+	State         SparkApplicationState
+	ErrorMessage  string
+	DriverPodName string
+	// ExecutorState maps executor pod names to their state, e.g. RUNNING or FAILED.
+	ExecutorState map[string]string
+}
+
+// FailedExecutors returns the names of the executor pods the operator reported as failed.
+func (s SparkApplicationStatus) FailedExecutors() []string {
+	failed := make([]string, 0)
+	for pod, state := range s.ExecutorState {
+		if state == "FAILED" {
+			failed = append(failed, pod)
+		}
+	}
+	return failed
+}
+
+// SparkApplicationClient creates and watches SparkApplication resources in a single namespace.
+type SparkApplicationClient struct {
+	Dynamic   dynamic.Interface
+	Clientset kubernetes.Interface
+	Namespace string
+}
+
+// NewSparkApplicationClient returns a client using the in-cluster config. If namespace is
+// empty, the namespace Featureform is running in is used.
+func NewSparkApplicationClient(namespace string) (*SparkApplicationClient, error) {
+	if namespace == "" {
+		current, err := GetCurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		namespace = current
+	}
+	kubeConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fferr.NewInternalError(err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, fferr.NewInternalError(err)
+	}
+	clientset, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, fferr.NewInternalError(err)
+	}
+	return &SparkApplicationClient{Dynamic: dynamicClient, Clientset: clientset, Namespace: namespace}, nil
+}
+
+var invalidSparkApplicationNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// CreateSparkApplicationName turns jobName into a unique, valid SparkApplication name. The
+// operator derives driver pod and service names from it, so it's kept below MaxJobNameLength.
+func CreateSparkApplicationName(jobName string) string {
+	cleaned := invalidSparkApplicationNameChars.ReplaceAllString(strings.ToLower(jobName), "-")
+	cleaned = strings.Trim(cleaned, "-")
+	// leave room for a 10 character uuid and a 1 character separator
+	if len(cleaned) > MaxJobNameLength-11 {
+		cleaned = strings.TrimRight(cleaned[:MaxJobNameLength-11], "-")
+	}
+	suffix := uuid.New().String()[:10]
+	if cleaned == "" {
+		return fmt.Sprintf("featureform-%s", suffix)
+	}
+	return fmt.Sprintf("%s-%s", cleaned, suffix)
+}
+
+func (c *SparkApplicationClient) resource() dynamic.ResourceInterface {
+	return c.Dynamic.Resource(SparkApplicationGVR).Namespace(c.Namespace)
+}
+
+// Create submits a SparkApplication with the given spec. The spec must follow the
+// sparkoperator.k8s.io/v1beta2 SparkApplicationSpec schema.
+func (c *SparkApplicationClient) Create(ctx context.Context, name string, spec map[string]interface{}) error {
+	app := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": SparkApplicationGVR.GroupVersion().String(),
+			"kind":       sparkApplicationKind,
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": c.Namespace,
+				"labels": map[string]interface{}{
+					"app.kubernetes.io/managed-by": "featureform",
+				},
+			},
+			"spec": spec,
+		},
+	}
+	if _, err := c.resource().Create(ctx, app, metav1.CreateOptions{}); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return fferr.NewJobAlreadyExistsError(name, err)
+		}
+		return fferr.NewInternalError(err)
+	}
+	return nil
+}
+
+// GetStatus returns the current status of the SparkApplication.
+func (c *SparkApplicationClient) GetStatus(ctx context.Context, name string) (SparkApplicationStatus, error) {
+	app, err := c.resource().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return SparkApplicationStatus{}, fferr.NewJobDoesNotExistError(name, err)
+		}
+		return SparkApplicationStatus{}, fferr.NewInternalError(err)
+	}
+	return parseSparkApplicationStatus(app), nil
+}
+
+func parseSparkApplicationStatus(app *unstructured.Unstructured) SparkApplicationStatus {
+	state, _, _ := unstructured.NestedString(app.Object, "status", "applicationState", "state")
+	errMsg, _, _ := unstructured.NestedString(app.Object, "status", "applicationState", "errorMessage")
+	driverPod, _, _ := unstructured.NestedString(app.Object, "status", "driverInfo", "podName")
+	executors, _, _ := unstructured.NestedStringMap(app.Object, "status", "executorState")
+	return SparkApplicationStatus{
+		State:         SparkApplicationState(state),
+		ErrorMessage:  errMsg,
+		DriverPodName: driverPod,
+		ExecutorState: executors,
+	}
+}
+
+// Delete removes the SparkApplication, which makes the operator kill its driver and executors.
+// Deleting an application that doesn't exist is not an error.
+func (c *SparkApplicationClient) Delete(ctx context.Context, name string) error {
+	propagation := metav1.DeletePropagationBackground
+	err := c.resource().Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fferr.NewInternalError(err)
+	}
+	return nil
+}
+
+// StreamPodLogs follows the logs of the pod and calls handleLine for every line until the pod
+// terminates or ctx is cancelled. If since is set, only lines logged after it are returned.
+func (c *SparkApplicationClient) StreamPodLogs(ctx context.Context, podName string, since *metav1.Time, handleLine func(string)) error {
+	opts := &corev1.PodLogOptions{Follow: true, SinceTime: since}
+	stream, err := c.Clientset.CoreV1().Pods(c.Namespace).GetLogs(podName, opts).Stream(ctx)
+	if err != nil {
+		return fferr.NewInternalError(err)
+	}
+	defer stream.Close()
+	scanner := bufio.NewScanner(stream)
+	// Spark can log very long lines, like query plans.
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		handleLine(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fferr.NewInternalError(err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package kubernetes
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCreateSparkApplicationName(t *testing.T) {
+	validName := regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	tests := map[string]string{
+		"featureform-sql-transformation--name--variant": "featureform-sql-transformation--name--var-",
+		"Materialize_Feature/My.Variant":                "materialize-feature-my-variant-",
+		"__":                                            "featureform-",
+		"":                                              "featureform-",
+	}
+	for jobName, expectedPrefix := range tests {
+		t.Run(jobName, func(t *testing.T) {
+			name := CreateSparkApplicationName(jobName)
+			if !strings.HasPrefix(name, expectedPrefix) {
+				t.Fatalf("Expected %s to start with %s", name, expectedPrefix)
+			}
+			if len(name) > MaxJobNameLength || !validName.MatchString(name) {
+				t.Fatalf("Invalid spark application name %s", name)
+			}
+		})
+	}
+	if CreateSparkApplicationName("job") == CreateSparkApplicationName("job") {
+		t.Fatalf("Expected application names to be unique")
+	}
+}
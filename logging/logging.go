@@ -18,6 +18,8 @@ import (
 	"github.com/featureform/metadata/proto"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest"
@@ -43,6 +45,9 @@ const (
 	LoggerKey    = contextKey("logger")
 )
 
+// RequestIDSpanAttribute is the span attribute that links a trace to a request ID.
+const RequestIDSpanAttribute = "featureform.request_id"
+
 const (
 	Provider           ResourceType = "provider"
 	User               ResourceType = "user"
@@ -254,6 +259,7 @@ func (logger Logger) InitializeRequestID(ctx context.Context) (RequestID, contex
 		requestID = NewRequestID()
 		ctx = context.WithValue(ctx, RequestIDKey, requestID)
 	}
+	annotateSpan(ctx, requestID.(RequestID))
 	ctxLogger := ctx.Value(LoggerKey)
 	if ctxLogger == nil {
 		logger.Debugw("Adding logger to context")
@@ -263,6 +269,11 @@ func (logger Logger) InitializeRequestID(ctx context.Context) (RequestID, contex
 	return requestID.(RequestID), ctx, ctxLogger.(Logger)
 }
 
+// annotateSpan links the current span, if any, to the request ID.
+func annotateSpan(ctx context.Context, id RequestID) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String(RequestIDSpanAttribute, id.String()))
+}
+
 func (logger Logger) AttachToContext(ctx context.Context) context.Context {
 	return addLoggerToContext(ctx, logger)
 }
@@ -302,6 +313,7 @@ func AttachRequestID(id RequestID, ctx context.Context, logger Logger) context.C
 		logger.Infow("Request ID already set in context. Overwriting request ID", "old request-id", contextID, "new request-id", id)
 	}
 	ctx = context.WithValue(ctx, RequestIDKey, RequestID(id))
+	annotateSpan(ctx, id)
 	logger = logger.WithRequestID(RequestID(id))
 	ctx = context.WithValue(ctx, LoggerKey, logger)
 	return ctx
@@ -53,7 +53,7 @@ func main() {
 		log.Fatalf("err %v", err)
 	}
 	if _, set := os.LookupEnv("FF_STATE_PROVIDER"); !set {
-		log.Println("FF_STATE_PROVIDER not set, defaulting to memory")
+		log.Println("FF_STATE_PROVIDER not set, defaulting to memory. Set it to sqlite to keep state across restarts")
 		if err := os.Setenv("FF_STATE_PROVIDER", "memory"); err != nil {
 			log.Fatalf("err %v", err)
 		}
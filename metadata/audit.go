@@ -0,0 +1,198 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/featureform/logging"
+	"github.com/featureform/metadata/common"
+	pb "github.com/featureform/metadata/proto"
+	"github.com/featureform/storage"
+)
+
+const redactedValue = "<redacted>"
+
+// sensitiveFields are lower cased substrings of field names whose values never
+// make it into the audit log. A change to them is still recorded.
+var sensitiveFields = []string{
+	"serializedconfig",
+	"password",
+	"secret",
+	"token",
+	"credential",
+	"apikey",
+	"privatekey",
+}
+
+func isSensitivePath(path string) bool {
+	lower := strings.ToLower(path)
+	for _, field := range sensitiveFields {
+		if strings.Contains(lower, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceAuditDiff is the storage.AuditDiffer for resource keys written by
+// MetadataStorageResourceLookup. Jobs, tasks and other keys aren't audited.
+func resourceAuditDiff(key, oldValue, newValue string) (storage.AuditResource, []storage.AuditChange, bool) {
+	parts := strings.SplitN(key, "__", 3)
+	if len(parts) != 3 {
+		return storage.AuditResource{}, nil, false
+	}
+	if _, ok := pb.ResourceType_value[parts[0]]; !ok {
+		return storage.AuditResource{}, nil, false
+	}
+	resource := storage.AuditResource{Type: parts[0], Name: parts[1], Variant: parts[2]}
+	oldFields := flattenStoredResource(oldValue)
+	newFields := flattenStoredResource(newValue)
+	return resource, diffFields(oldFields, newFields), true
+}
+
+// flattenStoredResource maps the dotted path of every leaf in the stored proto to its JSON value.
+func flattenStoredResource(value string) map[string]string {
+	fields := make(map[string]string)
+	if value == "" {
+		return fields
+	}
+	var row StoredRowTemp
+	if err := json.Unmarshal([]byte(value), &row); err != nil {
+		return fields
+	}
+	var msg interface{}
+	if err := json.Unmarshal([]byte(row.Message), &msg); err != nil {
+		return fields
+	}
+	flattenJSON("", msg, fields)
+	return fields
+}
+
+func flattenJSON(path string, value interface{}, fields map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flattenJSON(joinPath(path, key), child, fields)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenJSON(joinPath(path, fmt.Sprint(i)), child, fields)
+		}
+	default:
+		serialized, _ := json.Marshal(v)
+		fields[path] = string(serialized)
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func diffFields(oldFields, newFields map[string]string) []storage.AuditChange {
+	paths := make(map[string]struct{}, len(oldFields)+len(newFields))
+	for path := range oldFields {
+		paths[path] = struct{}{}
+	}
+	for path := range newFields {
+		paths[path] = struct{}{}
+	}
+	changes := make([]storage.AuditChange, 0)
+	for path := range paths {
+		oldValue, newValue := oldFields[path], newFields[path]
+		if oldValue == newValue {
+			continue
+		}
+		if isSensitivePath(path) {
+			if oldValue != "" {
+				oldValue = redactedValue
+			}
+			if newValue != "" {
+				newValue = redactedValue
+			}
+		}
+		changes = append(changes, storage.AuditChange{Path: path, OldValue: oldValue, NewValue: newValue})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// newResourceAuditLog audits the resource writes made through conn.
+func newResourceAuditLog(conn storage.MetadataStorage) *storage.AuditLog {
+	return storage.NewAuditLog(conn.Storage, resourceAuditDiff)
+}
+
+// auditLogFor returns the audit log of lookups backed by the state provider.
+func auditLogFor(lookup ResourceLookup) *storage.AuditLog {
+	if storageLookup, ok := lookup.(*MetadataStorageResourceLookup); ok {
+		return storageLookup.Connection.Audit
+	}
+	return nil
+}
+
+// recordResourceAudit records a mutation that doesn't go through MetadataStorage.Create.
+func recordResourceAudit(ctx context.Context, logger logging.Logger, lookup ResourceLookup, op storage.AuditOperation, id common.ResourceID) {
+	auditLog := auditLogFor(lookup)
+	if auditLog == nil {
+		return
+	}
+	entry := storage.AuditEntry{
+		Operation: op,
+		Key:       id.ToKey(),
+		Resource:  storage.AuditResource{Type: id.Type.String(), Name: id.Name, Variant: id.Variant},
+	}
+	if err := auditLog.Append(ctx, entry); err != nil {
+		logger.Errorw("Failed to record audit entry", "resource", id, "operation", op, "error", err)
+	}
+}
+
+func auditEntryProto(entry storage.AuditEntry) *pb.AuditEntry {
+	changes := make([]*pb.AuditChange, len(entry.Changes))
+	for i, change := range entry.Changes {
+		changes[i] = &pb.AuditChange{Path: change.Path, OldValue: change.OldValue, NewValue: change.NewValue}
+	}
+	return &pb.AuditEntry{
+		Id:        entry.ID,
+		Timestamp: timestamppb.New(entry.Timestamp),
+		Actor:     entry.Actor,
+		RequestId: entry.RequestID,
+		Operation: string(entry.Operation),
+		ResourceId: &pb.ResourceID{
+			Resource:     &pb.NameVariant{Name: entry.Resource.Name, Variant: entry.Resource.Variant},
+			ResourceType: pb.ResourceType(pb.ResourceType_value[entry.Resource.Type]),
+		},
+		Changes: changes,
+	}
+}
+
+func auditFilterFromProto(req *pb.ListAuditLogRequest) storage.AuditFilter {
+	filter := storage.AuditFilter{
+		Actor: req.GetActor(),
+		Limit: int(req.GetLimit()),
+	}
+	if req.GetSince() != nil {
+		filter.Since = req.GetSince().AsTime()
+	}
+	if id := req.GetResourceId(); id != nil {
+		filter.Resource = &storage.AuditResource{
+			Type:    id.GetResourceType().String(),
+			Name:    id.GetResource().GetName(),
+			Variant: id.GetResource().GetVariant(),
+		}
+	}
+	return filter
+}
@@ -0,0 +1,83 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package metadata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/featureform/metadata/proto"
+)
+
+func storedRow(t *testing.T, resType ResourceType, msg proto.Message) string {
+	serialized, err := protojson.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Failed to marshal proto: %v", err)
+	}
+	row, err := json.Marshal(StoredRowTemp{ResourceType: resType, Message: string(serialized), StorageType: RESOURCE, SerializedVersion: 1})
+	if err != nil {
+		t.Fatalf("Failed to marshal row: %v", err)
+	}
+	return string(row)
+}
+
+func TestResourceAuditDiff(t *testing.T) {
+	oldProvider := storedRow(t, PROVIDER, &pb.Provider{
+		Name:             "postgres",
+		Description:      "old",
+		SerializedConfig: []byte(`{"Password": "hunter2"}`),
+		Tags:             &pb.Tags{Tag: []string{"a"}},
+	})
+	newProvider := storedRow(t, PROVIDER, &pb.Provider{
+		Name:             "postgres",
+		Description:      "new",
+		SerializedConfig: []byte(`{"Password": "hunter3"}`),
+		Tags:             &pb.Tags{Tag: []string{"a", "b"}},
+	})
+
+	resource, changes, ok := resourceAuditDiff("PROVIDER__postgres__", oldProvider, newProvider)
+	if !ok {
+		t.Fatalf("Expected provider key to be audited")
+	}
+	if resource.Type != "PROVIDER" || resource.Name != "postgres" || resource.Variant != "" {
+		t.Fatalf("Unexpected resource %+v", resource)
+	}
+	expected := map[string][2]string{
+		"description":      {`"old"`, `"new"`},
+		"serializedConfig": {redactedValue, redactedValue},
+		"tags.tag.1":       {"", `"b"`},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), changes)
+	}
+	for _, change := range changes {
+		values, ok := expected[change.Path]
+		if !ok {
+			t.Fatalf("Unexpected change %+v", change)
+		}
+		if change.OldValue != values[0] || change.NewValue != values[1] {
+			t.Fatalf("Unexpected values for %s: %+v", change.Path, change)
+		}
+	}
+
+	_, created, _ := resourceAuditDiff("PROVIDER__postgres__", "", newProvider)
+	for _, change := range created {
+		if change.OldValue != "" {
+			t.Fatalf("Expected no old values on create, got %+v", change)
+		}
+	}
+
+	for _, key := range []string{"/tasks/metadata/task_id=1", "JOB__FEATURE_VARIANT__f__v", "not-a-resource"} {
+		if _, _, ok := resourceAuditDiff(key, "", ""); ok {
+			t.Fatalf("Expected %s not to be audited", key)
+		}
+	}
+}
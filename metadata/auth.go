@@ -0,0 +1,109 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package metadata
+
+import (
+	"context"
+
+	"github.com/featureform/auth"
+	pb "github.com/featureform/metadata/proto"
+)
+
+type ownedProto interface {
+	GetOwner() string
+}
+
+type taggedProto interface {
+	GetTags() *pb.Tags
+}
+
+// lookupResolver resolves owners and tags from the metadata server's own storage.
+type lookupResolver struct {
+	lookup ResourceLookup
+}
+
+func (resolver lookupResolver) Resolve(ctx context.Context, resource *auth.Resource) error {
+	id := ResourceID{Name: resource.Name, Variant: resource.Variant, Type: ResourceType(resource.Type)}
+	res, err := resolver.lookup.Lookup(ctx, id)
+	if err != nil {
+		return err
+	}
+	msg := res.Proto()
+	if owned, ok := msg.(ownedProto); ok {
+		resource.Owner = owned.GetOwner()
+	}
+	if tagged, ok := msg.(taggedProto); ok {
+		resource.Tags = tagged.GetTags().GetTag()
+	}
+	return nil
+}
+
+// clientResolver resolves owners and tags for services that reach metadata over gRPC.
+type clientResolver struct {
+	client *Client
+}
+
+// NewAuthResolver returns an auth.ResourceResolver backed by the metadata client.
+func NewAuthResolver(client *Client) auth.ResourceResolver {
+	return clientResolver{client: client}
+}
+
+func (resolver clientResolver) Resolve(ctx context.Context, resource *auth.Resource) error {
+	nv := NameVariant{Name: resource.Name, Variant: resource.Variant}
+	switch ResourceType(resource.Type) {
+	case FEATURE_VARIANT:
+		variant, err := resolver.client.GetFeatureVariant(ctx, nv)
+		if err != nil {
+			return err
+		}
+		resource.Owner, resource.Tags = variant.Owner(), variant.Tags()
+	case LABEL_VARIANT:
+		variant, err := resolver.client.GetLabelVariant(ctx, nv)
+		if err != nil {
+			return err
+		}
+		resource.Owner, resource.Tags = variant.Owner(), variant.Tags()
+	case SOURCE_VARIANT:
+		variant, err := resolver.client.GetSourceVariant(ctx, nv)
+		if err != nil {
+			return err
+		}
+		resource.Owner, resource.Tags = variant.Owner(), variant.Tags()
+	case TRAINING_SET_VARIANT:
+		variant, err := resolver.client.GetTrainingSetVariant(ctx, nv)
+		if err != nil {
+			return err
+		}
+		resource.Owner, resource.Tags = variant.Owner(), variant.Tags()
+	case PROVIDER:
+		provider, err := resolver.client.GetProvider(ctx, resource.Name)
+		if err != nil {
+			return err
+		}
+		resource.Tags = provider.Tags()
+	case ENTITY:
+		entity, err := resolver.client.GetEntity(ctx, resource.Name)
+		if err != nil {
+			return err
+		}
+		resource.Tags = entity.Tags()
+	case MODEL:
+		model, err := resolver.client.GetModel(ctx, resource.Name)
+		if err != nil {
+			return err
+		}
+		resource.Tags = model.Tags()
+	case USER:
+		user, err := resolver.client.GetUser(ctx, resource.Name)
+		if err != nil {
+			return err
+		}
+		resource.Tags = user.Tags()
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/featureform/auth"
 	"github.com/featureform/config"
 	"github.com/featureform/filestore"
 	pc "github.com/featureform/provider/provider_config"
@@ -35,6 +36,7 @@ import (
 	tspb "google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/featureform/fferr"
+	"github.com/featureform/helpers/interceptors"
 	"github.com/featureform/logging"
 	pb "github.com/featureform/metadata/proto"
 	pl "github.com/featureform/provider/location"
@@ -59,8 +61,8 @@ func (variant NameVariant) ClientString() string {
 
 func parseNameVariant(serialized *pb.NameVariant) NameVariant {
 	return NameVariant{
-		Name:    serialized.Name,
-		Variant: serialized.Variant,
+		Name:    serialized.GetName(),
+		Variant: serialized.GetVariant(),
 	}
 }
 
@@ -137,6 +139,76 @@ func (client *Client) RequestScheduleChange(ctx context.Context, resID ResourceI
 	return err
 }
 
+// ListAuditLog returns the audit log entries matching req, oldest first.
+func (client *Client) ListAuditLog(ctx context.Context, req *pb.ListAuditLogRequest) ([]*pb.AuditEntry, error) {
+	logger := logging.GetLoggerFromContext(ctx)
+	resp, err := client.GrpcConn.ListAuditLog(ctx, req)
+	if err != nil {
+		logger.Errorw("Failed to list audit log", "error", err)
+		return nil, err
+	}
+	return resp.GetEntries(), nil
+}
+
+// GetLineage returns the resources up to depth edges upstream and/or downstream of id. A depth of
+// zero returns the whole graph.
+func (client *Client) GetLineage(ctx context.Context, id ResourceID, direction pb.LineageDirection, depth int) (*LineageGraph, error) {
+	logger := logging.GetLoggerFromContext(ctx)
+	resp, err := client.GrpcConn.GetLineage(ctx, &pb.LineageRequest{ResourceId: id.Proto(), Direction: direction, Depth: int32(depth)})
+	if err != nil {
+		logger.Errorw("Failed to get lineage", "resource_id", id.String(), "error", err)
+		return nil, err
+	}
+	return LineageGraphFromProto(resp), nil
+}
+
+// Backfill rebuilds the transformations and features in ids over [start, end). Daily partitioned
+// transformations are rebuilt partitionsPerRun days at a time, one task run per batch.
+func (client *Client) Backfill(ctx context.Context, ids []ResourceID, start, end time.Time, partitionsPerRun int) error {
+	logger := logging.GetLoggerFromContext(ctx)
+	variants := make([]*pb.ResourceVariant, len(ids))
+	for i, id := range ids {
+		switch id.Type {
+		case SOURCE_VARIANT:
+			variants[i] = &pb.ResourceVariant{Resource: &pb.ResourceVariant_SourceVariant{
+				SourceVariant: &pb.SourceVariant{Name: id.Name, Variant: id.Variant},
+			}}
+		case FEATURE_VARIANT:
+			variants[i] = &pb.ResourceVariant{Resource: &pb.ResourceVariant_FeatureVariant{
+				FeatureVariant: &pb.FeatureVariant{Name: id.Name, Variant: id.Variant},
+			}}
+		default:
+			return fferr.NewInvalidArgumentErrorf("%s can't be backfilled", id.String())
+		}
+	}
+	req := &pb.RunRequest{
+		RequestId: logging.GetRequestIDFromContext(ctx).String(),
+		Variants:  variants,
+		Backfill: &pb.BackfillRange{
+			Start:            tspb.New(start),
+			End:              tspb.New(end),
+			PartitionsPerRun: int32(partitionsPerRun),
+		},
+	}
+	if _, err := client.GrpcConn.Run(ctx, req); err != nil {
+		logger.Errorw("Failed to backfill resources", "resources", ids, "start", start, "end", end, "error", err)
+		return err
+	}
+	return nil
+}
+
+// GetProfile returns the data profile recorded by run runID of a feature or training set variant,
+// or by its latest profiled run if runID is empty.
+func (client *Client) GetProfile(ctx context.Context, id ResourceID, runID string) (*ResourceProfile, error) {
+	logger := logging.GetLoggerFromContext(ctx)
+	resp, err := client.GrpcConn.GetProfile(ctx, &pb.ProfileRequest{ResourceId: id.Proto(), RunId: runID})
+	if err != nil {
+		logger.Errorw("Failed to get profile", "resource_id", id.String(), "run_id", runID, "error", err)
+		return nil, err
+	}
+	return ResourceProfileFromProto(resp), nil
+}
+
 func (client *Client) CreateAll(ctx context.Context, defs []ResourceDef) error {
 	for _, def := range defs {
 		if err := client.Create(ctx, def); err != nil {
@@ -273,6 +345,36 @@ type EntityMappings struct {
 	TimestampColumn string
 }
 
+func (m EntityMappings) Serialize() *pb.EntityMappings {
+	mappings := make([]*pb.EntityMapping, len(m.Mappings))
+	for i, mapping := range m.Mappings {
+		mappings[i] = &pb.EntityMapping{
+			Name:         mapping.Name,
+			EntityColumn: mapping.EntityColumn,
+		}
+	}
+	return &pb.EntityMappings{
+		Mappings:        mappings,
+		ValueColumn:     m.ValueColumn,
+		TimestampColumn: m.TimestampColumn,
+	}
+}
+
+func parseEntityMappings(serialized *pb.EntityMappings) EntityMappings {
+	mappings := make([]EntityMapping, 0)
+	for _, mapping := range serialized.GetMappings() {
+		mappings = append(mappings, EntityMapping{
+			Name:         mapping.Name,
+			EntityColumn: mapping.EntityColumn,
+		})
+	}
+	return EntityMappings{
+		Mappings:        mappings,
+		ValueColumn:     serialized.GetValueColumn(),
+		TimestampColumn: serialized.GetTimestampColumn(),
+	}
+}
+
 func (c ResourceVariantColumns) SerializeFeatureColumns() *pb.FeatureVariant_Columns {
 	return &pb.FeatureVariant_Columns{
 		Columns: &pb.Columns{
@@ -639,10 +741,41 @@ type TrainingSetDef struct {
 	Provider    string
 	Schedule    string
 	Label       NameVariant
-	Features    NameVariants
-	Tags        Tags
-	Properties  Properties
-	Type        TrainingSetType
+	// Spine is set instead of Label for training sets that only contain features.
+	Spine *TrainingSetSpine
+	// AdditionalLabels are joined alongside Label (or the Spine) for multi-task training sets.
+	AdditionalLabels NameVariants
+	Features         NameVariants
+	Tags             Tags
+	Properties       Properties
+	Type             TrainingSetType
+}
+
+// TrainingSetSpine is a source whose rows are the entities, and optionally timestamps,
+// that a training set's features are looked up for.
+type TrainingSetSpine struct {
+	Source         NameVariant
+	EntityMappings EntityMappings
+}
+
+func (spine *TrainingSetSpine) Serialize() *pb.TrainingSetSpine {
+	if spine == nil {
+		return nil
+	}
+	return &pb.TrainingSetSpine{
+		Source:         spine.Source.Serialize(),
+		EntityMappings: spine.EntityMappings.Serialize(),
+	}
+}
+
+func parseTrainingSetSpine(serialized *pb.TrainingSetSpine) *TrainingSetSpine {
+	if serialized == nil {
+		return nil
+	}
+	return &TrainingSetSpine{
+		Source:         parseNameVariant(serialized.GetSource()),
+		EntityMappings: parseEntityMappings(serialized.GetEntityMappings()),
+	}
 }
 
 func (def TrainingSetDef) ResourceType() ResourceType {
@@ -660,24 +793,40 @@ func (def TrainingSetDef) ResourceID() ResourceID {
 func (def TrainingSetDef) Serialize(requestID logging.RequestID) *pb.TrainingSetVariantRequest {
 	return &pb.TrainingSetVariantRequest{
 		TrainingSetVariant: &pb.TrainingSetVariant{
-			Name:        def.Name,
-			Variant:     def.Variant,
-			Description: def.Description,
-			Owner:       def.Owner,
-			Provider:    def.Provider,
-			Status:      &pb.ResourceStatus{Status: pb.ResourceStatus_CREATED},
-			Label:       def.Label.Serialize(),
-			Features:    def.Features.Serialize(),
-			Schedule:    def.Schedule,
-			Tags:        &pb.Tags{Tag: def.Tags},
-			Properties:  def.Properties.Serialize(),
-			Type:        TrainingSetTypeToProto(def.Type),
+			Name:             def.Name,
+			Variant:          def.Variant,
+			Description:      def.Description,
+			Owner:            def.Owner,
+			Provider:         def.Provider,
+			Status:           &pb.ResourceStatus{Status: pb.ResourceStatus_CREATED},
+			Label:            def.serializeLabel(),
+			Spine:            def.Spine.Serialize(),
+			AdditionalLabels: def.serializeAdditionalLabels(),
+			Features:         def.Features.Serialize(),
+			Schedule:         def.Schedule,
+			Tags:             &pb.Tags{Tag: def.Tags},
+			Properties:       def.Properties.Serialize(),
+			Type:             TrainingSetTypeToProto(def.Type),
 		},
 		RequestId: requestID.String(),
 	}
 
 }
 
+func (def TrainingSetDef) serializeLabel() *pb.NameVariant {
+	if def.Spine != nil {
+		return nil
+	}
+	return def.Label.Serialize()
+}
+
+func (def TrainingSetDef) serializeAdditionalLabels() []*pb.NameVariant {
+	if len(def.AdditionalLabels) == 0 {
+		return nil
+	}
+	return def.AdditionalLabels.Serialize()
+}
+
 func (client *Client) CreateTrainingSetVariant(ctx context.Context, def TrainingSetDef) error {
 	requestID := logging.GetRequestIDFromContext(ctx)
 	serialized := def.Serialize(requestID)
@@ -826,8 +975,9 @@ type SQLTransformationType struct {
 }
 
 type PrimaryDataSource struct {
-	Location        PrimaryDataLocationType
-	TimestampColumn string
+	Location          PrimaryDataLocationType
+	TimestampColumn   string
+	SchemaDriftPolicy pb.SchemaDriftPolicy
 }
 
 type PrimaryDataLocationType interface {
@@ -878,7 +1028,8 @@ func (t PrimaryDataSource) Serialize() (*pb.SourceVariant_PrimaryData, error) {
 					Name: t.Location.(SQLTable).Name,
 				},
 			},
-			TimestampColumn: t.TimestampColumn,
+			TimestampColumn:   t.TimestampColumn,
+			SchemaDriftPolicy: t.SchemaDriftPolicy,
 		}
 	case nil:
 		return nil, fferr.NewInvalidArgumentError(fmt.Errorf("PrimaryDataSource Type not set"))
@@ -1979,6 +2130,11 @@ func (variant *FeatureVariant) Location() interface{} {
 	return variant.serialized.GetLocation()
 }
 
+// Expectations are the data quality checks run on the feature before it's materialized.
+func (variant *FeatureVariant) Expectations() []*pb.Expectation {
+	return variant.serialized.GetExpectations()
+}
+
 func (variant *FeatureVariant) Definition() string {
 	def := ""
 	if variant.IsOnDemand() {
@@ -2191,7 +2347,7 @@ func (provider *Provider) ToShallowMap() ProviderResource {
 		ProviderType:     provider.Type(),
 		Software:         provider.Software(),
 		Team:             provider.Team(),
-		SerializedConfig: provider.SerializedConfig(),
+		SerializedConfig: pc.RedactSerializedConfig(provider.SerializedConfig()),
 		Status:           provider.Status().String(),
 		Error:            provider.Error(),
 		Tags:             provider.Tags(),
@@ -2449,18 +2605,7 @@ func (variant *LabelVariant) Location() (EntityMappings, error) {
 		}, nil
 	case *pb.LabelVariant_EntityMappings:
 		logger.Debugw("Using entity mappings location type", "location", loc)
-		mappings := make([]EntityMapping, 0)
-		for _, mapping := range loc.EntityMappings.Mappings {
-			mappings = append(mappings, EntityMapping{
-				Name:         mapping.Name,
-				EntityColumn: mapping.EntityColumn,
-			})
-		}
-		return EntityMappings{
-			Mappings:        mappings,
-			ValueColumn:     loc.EntityMappings.ValueColumn,
-			TimestampColumn: loc.EntityMappings.TimestampColumn,
-		}, nil
+		return parseEntityMappings(loc.EntityMappings), nil
 	default:
 		logger.Errorw("Unknown or unsupported location type", "location", loc)
 		return EntityMappings{}, fferr.NewInternalErrorf("Unknown or unsupported location type %T", loc)
@@ -2585,6 +2730,39 @@ func (variant *TrainingSetVariant) Label() NameVariant {
 	return parseNameVariant(variant.serialized.GetLabel())
 }
 
+// Spine returns the training set's spine, or nil if the training set has a label.
+func (variant *TrainingSetVariant) Spine() *TrainingSetSpine {
+	return parseTrainingSetSpine(variant.serialized.GetSpine())
+}
+
+func (variant *TrainingSetVariant) HasSpine() bool {
+	return variant.serialized.GetSpine() != nil
+}
+
+// Expectations are the data quality checks run on the training set after it's created.
+func (variant *TrainingSetVariant) Expectations() []*pb.Expectation {
+	return variant.serialized.GetExpectations()
+}
+
+// SnapshotPolicy controls whether the training set's runs pin snapshots of the data they read and
+// write. It's nil when snapshots aren't pinned.
+func (variant *TrainingSetVariant) SnapshotPolicy() *pb.SnapshotPolicy {
+	return variant.serialized.GetSnapshotPolicy()
+}
+
+func (variant *TrainingSetVariant) TaskIDs() ([]scheduling.TaskID, error) {
+	// Check if using a deprecated taskID singleton
+	if variant.serialized.TaskId != "" {
+		return parseResourceTasks([]string{variant.serialized.TaskId})
+	}
+	return parseResourceTasks(variant.serialized.TaskIdList)
+}
+
+// AdditionalLabels returns the labels joined in addition to Label or the Spine.
+func (variant *TrainingSetVariant) AdditionalLabels() NameVariants {
+	return parseNameVariants(variant.serialized.GetAdditionalLabels())
+}
+
 func (variant *TrainingSetVariant) LagFeatures() []*pb.FeatureLag {
 	return variant.serialized.GetFeatureLags()
 }
@@ -2762,6 +2940,32 @@ func InitializeFromString(initialize string) (Initialize, error) {
 	}
 }
 
+// SnowflakeRefreshStrategy is how a Snowflake resource's table is kept up to date.
+type SnowflakeRefreshStrategy string
+
+const (
+	// ScheduledRefresh re-runs the resource's query on its schedule.
+	ScheduledRefresh SnowflakeRefreshStrategy = "" // Default
+	// DynamicTableRefresh creates a dynamic table that Snowflake refreshes within its target lag.
+	DynamicTableRefresh SnowflakeRefreshStrategy = "DYNAMIC_TABLE"
+	// StreamRefresh streams the changes to an incremental transformation's sources, and a
+	// Snowflake task merges them into its table.
+	StreamRefresh SnowflakeRefreshStrategy = "STREAM"
+)
+
+func SnowflakeRefreshStrategyFromProto(proto pb.SnowflakeRefreshStrategy) (SnowflakeRefreshStrategy, error) {
+	switch proto {
+	case pb.SnowflakeRefreshStrategy_SNOWFLAKE_REFRESH_STRATEGY_UNSPECIFIED:
+		return ScheduledRefresh, nil
+	case pb.SnowflakeRefreshStrategy_SNOWFLAKE_REFRESH_STRATEGY_DYNAMIC_TABLE:
+		return DynamicTableRefresh, nil
+	case pb.SnowflakeRefreshStrategy_SNOWFLAKE_REFRESH_STRATEGY_STREAM:
+		return StreamRefresh, nil
+	default:
+		return "", fferr.NewInternalErrorf("Unknown Snowflake refresh strategy %v", proto)
+	}
+}
+
 func TrainingSetTypeFromProto(proto pb.TrainingSetType) (TrainingSetType, error) {
 	logger := logging.GlobalLogger.Named("TrainingSetTypeFromProto")
 	var trainingSetType TrainingSetType
@@ -2813,6 +3017,64 @@ func TrainingSetTypeToProto(trainingSetType TrainingSetType) pb.TrainingSetType
 type ResourceSnowflakeConfig struct {
 	DynamicTableConfig *SnowflakeDynamicTableConfig
 	Warehouse          string
+	RefreshStrategy    SnowflakeRefreshStrategy
+}
+
+// RefreshedBySnowflake reports whether Snowflake, rather than Featureform's scheduler, keeps the
+// resource's table up to date.
+func (config *ResourceSnowflakeConfig) RefreshedBySnowflake() bool {
+	return config != nil && config.RefreshStrategy != ScheduledRefresh
+}
+
+// MergeRefreshed fills in the settings of a resource refreshed by Snowflake from its provider.
+// Unlike Merge, it doesn't require a catalog, as the tables it describes aren't Iceberg tables.
+func (config *ResourceSnowflakeConfig) MergeRefreshed(c *pc.SnowflakeConfig) error {
+	if config.DynamicTableConfig == nil {
+		config.DynamicTableConfig = &SnowflakeDynamicTableConfig{}
+	}
+	if c.Catalog != nil {
+		if config.DynamicTableConfig.TargetLag == "" {
+			config.DynamicTableConfig.TargetLag = c.Catalog.TableConfig.TargetLag
+		}
+		if config.DynamicTableConfig.RefreshMode == "" && c.Catalog.TableConfig.RefreshMode != "" {
+			refreshMode, err := RefreshModeFromString(c.Catalog.TableConfig.RefreshMode)
+			if err != nil {
+				return err
+			}
+			config.DynamicTableConfig.RefreshMode = refreshMode
+		}
+		if config.DynamicTableConfig.Initialize == "" && c.Catalog.TableConfig.Initialize != "" {
+			initialize, err := InitializeFromString(c.Catalog.TableConfig.Initialize)
+			if err != nil {
+				return err
+			}
+			config.DynamicTableConfig.Initialize = initialize
+		}
+	}
+	if config.DynamicTableConfig.RefreshMode == "" {
+		config.DynamicTableConfig.RefreshMode = AutoRefresh
+	}
+	if config.DynamicTableConfig.Initialize == "" {
+		config.DynamicTableConfig.Initialize = InitializeOnCreate
+	}
+	if config.Warehouse == "" {
+		config.Warehouse = c.Warehouse
+	}
+	return config.ValidateRefreshed()
+}
+
+// ValidateRefreshed checks the config of a resource refreshed by Snowflake.
+func (config ResourceSnowflakeConfig) ValidateRefreshed() error {
+	if config.Warehouse == "" {
+		return fferr.NewInvalidArgumentErrorf("Snowflake configuration requires a warehouse")
+	}
+	if err := config.DynamicTableConfig.ValidateTargetLag(); err != nil {
+		return err
+	}
+	if config.RefreshStrategy == StreamRefresh && strings.EqualFold(strings.TrimSpace(config.DynamicTableConfig.TargetLag), "DOWNSTREAM") {
+		return fferr.NewInvalidArgumentErrorf("Snowflake stream refreshes run on a schedule, so their target lag can't be DOWNSTREAM")
+	}
+	return nil
 }
 
 func (config *ResourceSnowflakeConfig) Merge(c *pc.SnowflakeConfig) error {
@@ -3077,6 +3339,11 @@ func (variant *SourceVariant) Schedule() string {
 	return variant.serialized.GetSchedule()
 }
 
+// Expectations are the data quality checks run on the source after each run.
+func (variant *SourceVariant) Expectations() []*pb.Expectation {
+	return variant.serialized.GetExpectations()
+}
+
 func (variant *SourceVariant) Variant() string {
 	return variant.serialized.GetVariant()
 }
@@ -3158,6 +3425,50 @@ func (variant *SourceVariant) SQLTransformationSources() []NameVariant {
 	return variants
 }
 
+func (variant *SourceVariant) IsIncrementalSQLTransformation() bool {
+	if !variant.IsSQLTransformation() {
+		return false
+	}
+	return variant.serialized.GetTransformation().GetSQLTransformation().GetIsIncremental()
+}
+
+// SQLTransformationIncrementalSources are the sources, also in SQLTransformationSources, that
+// each run reads only the new rows of.
+func (variant *SourceVariant) SQLTransformationIncrementalSources() []NameVariant {
+	if !variant.IsSQLTransformation() {
+		return nil
+	}
+	nameVariants := variant.serialized.GetTransformation().GetSQLTransformation().GetIncrementalSource()
+	var variants []NameVariant
+	for _, nv := range nameVariants {
+		variants = append(variants, NameVariant{Name: nv.Name, Variant: nv.Variant})
+	}
+	return variants
+}
+
+func (variant *SourceVariant) SQLTransformationMergeKeys() []string {
+	if !variant.IsSQLTransformation() {
+		return nil
+	}
+	return variant.serialized.GetTransformation().GetSQLTransformation().GetMergeKeys()
+}
+
+func (variant *SourceVariant) SQLTransformationIncrementalTimestampColumn() string {
+	if !variant.IsSQLTransformation() {
+		return ""
+	}
+	return variant.serialized.GetTransformation().GetSQLTransformation().GetIncrementalTimestampColumn()
+}
+
+// DailyPartitionColumn is the column a transformation is partitioned by day on, or an empty
+// string if it isn't daily partitioned.
+func (variant *SourceVariant) DailyPartitionColumn() string {
+	if !variant.IsTransformation() {
+		return ""
+	}
+	return variant.serialized.GetTransformation().GetDailyPartition().GetColumn()
+}
+
 func (variant *SourceVariant) IsDFTransformation() bool {
 	if !variant.IsTransformation() {
 		return false
@@ -3225,6 +3536,15 @@ func (variant *SourceVariant) PrimaryDataTimestampColumn() string {
 	return variant.serialized.GetPrimaryData().GetTimestampColumn()
 }
 
+// PrimaryDataSchemaDriftPolicy is what changes to the source's schema since it was registered are
+// tolerated by the tasks that read it.
+func (variant *SourceVariant) PrimaryDataSchemaDriftPolicy() pb.SchemaDriftPolicy {
+	if !variant.IsPrimaryData() {
+		return pb.SchemaDriftPolicy_SCHEMA_DRIFT_STRICT
+	}
+	return variant.serialized.GetPrimaryData().GetSchemaDriftPolicy()
+}
+
 func (variant *SourceVariant) GetPrimaryLocation() (pl.Location, error) {
 	if !variant.isPrimaryData() {
 		fmt.Println("Variant is not primary data, returning returning nil values")
@@ -3242,6 +3562,9 @@ func (variant *SourceVariant) GetPrimaryLocation() (pl.Location, error) {
 		if err := fp.ParseFilePath(pt.Filestore.GetPath()); err != nil {
 			return nil, err
 		}
+		if tableFormat := pt.Filestore.GetTableFormat(); tableFormat != "" {
+			return pl.NewFileTableLocation(&fp, tableFormat), nil
+		}
 		return pl.NewFileLocation(&fp), nil
 	case *pb.PrimaryData_Catalog:
 		return pl.NewCatalogLocation(pt.Catalog.GetDatabase(), pt.Catalog.GetTable(), pt.Catalog.GetTableFormat()), nil
@@ -3265,6 +3588,9 @@ func (variant *SourceVariant) GetTransformationLocation() (pl.Location, error) {
 		if err := fp.ParseDirPath(pt.Filestore.GetPath()); err != nil {
 			return nil, err
 		}
+		if tableFormat := pt.Filestore.GetTableFormat(); tableFormat != "" {
+			return pl.NewFileTableLocation(&fp, tableFormat), nil
+		}
 		return pl.NewFileLocation(&fp), nil
 	case *pb.Transformation_Catalog:
 		return pl.NewCatalogLocation(pt.Catalog.GetDatabase(), pt.Catalog.GetTable(), pt.Catalog.GetTableFormat()), nil
@@ -3407,6 +3733,8 @@ func NewClient(host string, logger logging.Logger) (*Client, error) {
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 	}
+	opts = append(opts, auth.ServiceDialOptions()...)
+	opts = append(opts, interceptors.TracingDialOptions()...)
 	conn, err := grpc.Dial(host, opts...)
 	if err != nil {
 		return nil, fferr.NewInternalError(err)
@@ -3476,5 +3804,11 @@ func getResourceSnowflakeConfig(getter resourceSnowflakeConfigGetter) (*Resource
 		resConfig.Warehouse = config.GetWarehouse()
 	}
 
+	refreshStrategy, err := SnowflakeRefreshStrategyFromProto(config.GetRefreshStrategy())
+	if err != nil {
+		return nil, err
+	}
+	resConfig.RefreshStrategy = refreshStrategy
+
 	return resConfig, nil
 }
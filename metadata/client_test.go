@@ -15,6 +15,7 @@ import (
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/featureform/logging"
 	pb "github.com/featureform/metadata/proto"
 	pc "github.com/featureform/provider/provider_config"
 	"github.com/featureform/provider/types"
@@ -906,6 +907,105 @@ func TestSnowflakeDynamicTableConfigMerge(t *testing.T) {
 	}
 }
 
+func TestSnowflakeRefreshedConfigMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		resConfig *ResourceSnowflakeConfig
+		config    *pc.SnowflakeConfig
+		want      *ResourceSnowflakeConfig
+		expectErr bool
+	}{
+		{
+			"Dynamic Table Without Catalog",
+			&ResourceSnowflakeConfig{
+				DynamicTableConfig: &SnowflakeDynamicTableConfig{TargetLag: "1 hours"},
+				RefreshStrategy:    DynamicTableRefresh,
+			},
+			&pc.SnowflakeConfig{Warehouse: "warehouse"},
+			&ResourceSnowflakeConfig{
+				DynamicTableConfig: &SnowflakeDynamicTableConfig{
+					TargetLag:   "1 hours",
+					RefreshMode: AutoRefresh,
+					Initialize:  InitializeOnCreate,
+				},
+				Warehouse:       "warehouse",
+				RefreshStrategy: DynamicTableRefresh,
+			},
+			false,
+		},
+		{
+			"Stream With Catalog Defaults",
+			&ResourceSnowflakeConfig{RefreshStrategy: StreamRefresh},
+			&pc.SnowflakeConfig{
+				Catalog: &pc.SnowflakeCatalogConfig{
+					TableConfig: pc.SnowflakeTableConfig{
+						TargetLag:   "10 minutes",
+						RefreshMode: "INCREMENTAL",
+					},
+				},
+				Warehouse: "warehouse",
+			},
+			&ResourceSnowflakeConfig{
+				DynamicTableConfig: &SnowflakeDynamicTableConfig{
+					TargetLag:   "10 minutes",
+					RefreshMode: IncrementalRefresh,
+					Initialize:  InitializeOnCreate,
+				},
+				Warehouse:       "warehouse",
+				RefreshStrategy: StreamRefresh,
+			},
+			false,
+		},
+		{
+			"Stream With Downstream Target Lag",
+			&ResourceSnowflakeConfig{
+				DynamicTableConfig: &SnowflakeDynamicTableConfig{TargetLag: "DOWNSTREAM"},
+				RefreshStrategy:    StreamRefresh,
+			},
+			&pc.SnowflakeConfig{Warehouse: "warehouse"},
+			&ResourceSnowflakeConfig{
+				DynamicTableConfig: &SnowflakeDynamicTableConfig{
+					TargetLag:   "DOWNSTREAM",
+					RefreshMode: AutoRefresh,
+					Initialize:  InitializeOnCreate,
+				},
+				Warehouse:       "warehouse",
+				RefreshStrategy: StreamRefresh,
+			},
+			true,
+		},
+		{
+			"Missing Warehouse",
+			&ResourceSnowflakeConfig{
+				DynamicTableConfig: &SnowflakeDynamicTableConfig{TargetLag: "1 hours"},
+				RefreshStrategy:    DynamicTableRefresh,
+			},
+			&pc.SnowflakeConfig{},
+			&ResourceSnowflakeConfig{
+				DynamicTableConfig: &SnowflakeDynamicTableConfig{
+					TargetLag:   "1 hours",
+					RefreshMode: AutoRefresh,
+					Initialize:  InitializeOnCreate,
+				},
+				RefreshStrategy: DynamicTableRefresh,
+			},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.resConfig.MergeRefreshed(tt.config)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("MergeRefreshed() error = %v", err)
+			}
+			if !reflect.DeepEqual(tt.resConfig, tt.want) {
+				t.Errorf("MergeRefreshed() = %v, want %v", tt.resConfig, tt.want)
+			}
+		})
+	}
+}
+
 func TestResourceSnowflakeConfigValidation(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -980,3 +1080,53 @@ func TestResourceSnowflakeConfigValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestTrainingSetDefSpineSerialize(t *testing.T) {
+	spine := &TrainingSetSpine{
+		Source: NameVariant{Name: "users_to_score", Variant: "v1"},
+		EntityMappings: EntityMappings{
+			Mappings:        []EntityMapping{{Name: "user", EntityColumn: "user_id"}},
+			TimestampColumn: "scored_at",
+		},
+	}
+	def := TrainingSetDef{
+		Name:     "scoring",
+		Variant:  "v1",
+		Label:    NameVariant{Name: "ignored", Variant: "v1"},
+		Spine:    spine,
+		Features: NameVariants{{Name: "avg_spend", Variant: "v1"}},
+	}
+	serialized := def.Serialize(logging.NewRequestID()).TrainingSetVariant
+	if serialized.GetLabel() != nil {
+		t.Errorf("Expected training set with a spine to not have a label, got %v", serialized.GetLabel())
+	}
+	variant := WrapProtoTrainingSetVariant(serialized)
+	if !variant.HasSpine() {
+		t.Fatalf("Expected training set to have a spine")
+	}
+	if !reflect.DeepEqual(spine, variant.Spine()) {
+		t.Errorf("Expected spine %#v, got %#v", spine, variant.Spine())
+	}
+	if label := variant.Label(); label != (NameVariant{}) {
+		t.Errorf("Expected empty label, got %v", label)
+	}
+}
+
+func TestTrainingSetDefAdditionalLabelsSerialize(t *testing.T) {
+	def := TrainingSetDef{
+		Name:             "fraud",
+		Variant:          "v1",
+		Label:            NameVariant{Name: "is_fraud", Variant: "v1"},
+		AdditionalLabels: NameVariants{{Name: "disputed", Variant: "v1"}},
+		Features:         NameVariants{{Name: "avg_spend", Variant: "v1"}},
+	}
+	variant := WrapProtoTrainingSetVariant(def.Serialize(logging.NewRequestID()).TrainingSetVariant)
+	if !reflect.DeepEqual(def.AdditionalLabels, variant.AdditionalLabels()) {
+		t.Errorf("Expected additional labels %v, got %v", def.AdditionalLabels, variant.AdditionalLabels())
+	}
+
+	def.AdditionalLabels = nil
+	if serialized := def.Serialize(logging.NewRequestID()).TrainingSetVariant; serialized.AdditionalLabels != nil {
+		t.Errorf("Expected no additional labels, got %v", serialized.AdditionalLabels)
+	}
+}
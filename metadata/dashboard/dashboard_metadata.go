@@ -19,6 +19,7 @@ import (
 
 	"github.com/featureform/config"
 	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
 	help "github.com/featureform/helpers"
 	"github.com/featureform/helpers/postgres"
 	"github.com/featureform/logging"
@@ -38,6 +39,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
@@ -2920,6 +2922,253 @@ func (m *MetadataServer) GetTaskRunDetails(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+type AuditChange struct {
+	Path     string `json:"path"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+type AuditLogEntry struct {
+	ID        string        `json:"id"`
+	Timestamp time.Time     `json:"timestamp"`
+	Actor     string        `json:"actor"`
+	RequestID string        `json:"requestId"`
+	Operation string        `json:"operation"`
+	Type      string        `json:"type"`
+	Name      string        `json:"name"`
+	Variant   string        `json:"variant"`
+	Changes   []AuditChange `json:"changes"`
+}
+
+// GetAuditLog lists metadata mutations. All query parameters are optional:
+// type (a resource type such as FEATURE_VARIANT) and name filter by resource,
+// variant narrows it further, actor filters by caller, since is an RFC 3339
+// timestamp and limit keeps only the most recent entries.
+func (m *MetadataServer) GetAuditLog(c *gin.Context) {
+	req := &pb.ListAuditLogRequest{Actor: c.Query("actor")}
+	if resType := c.Query("type"); resType != "" {
+		typeValue, ok := pb.ResourceType_value[resType]
+		if !ok {
+			fetchError := &FetchError{StatusCode: http.StatusBadRequest, Type: fmt.Sprintf("GetAuditLog - Unknown resource type: %s", resType)}
+			m.logger.Errorw(fetchError.Error(), "Metadata error")
+			c.JSON(fetchError.StatusCode, fetchError.Error())
+			return
+		}
+		req.ResourceId = &pb.ResourceID{
+			Resource:     &pb.NameVariant{Name: c.Query("name"), Variant: c.Query("variant")},
+			ResourceType: pb.ResourceType(typeValue),
+		}
+	}
+	if since := c.Query("since"); since != "" {
+		sinceTime, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			fetchError := m.GetRequestError(http.StatusBadRequest, err, c, "GetAuditLog - Invalid since parameter")
+			c.JSON(fetchError.StatusCode, fetchError.Error())
+			return
+		}
+		req.Since = timestamppb.New(sinceTime)
+	}
+	if limit := c.Query("limit"); limit != "" {
+		limitValue, err := strconv.Atoi(limit)
+		if err != nil || limitValue < 0 {
+			fetchError := &FetchError{StatusCode: http.StatusBadRequest, Type: fmt.Sprintf("GetAuditLog - Invalid limit value: %s", limit)}
+			m.logger.Errorw(fetchError.Error(), "Metadata error")
+			c.JSON(fetchError.StatusCode, fetchError.Error())
+			return
+		}
+		req.Limit = int32(limitValue)
+	}
+
+	entries, err := m.client.ListAuditLog(c.Request.Context(), req)
+	if err != nil {
+		fetchError := m.GetRequestError(http.StatusInternalServerError, err, c, "GetAuditLog - Failed to fetch audit log")
+		c.JSON(fetchError.StatusCode, fetchError.Error())
+		return
+	}
+
+	resp := make([]AuditLogEntry, len(entries))
+	for i, entry := range entries {
+		changes := make([]AuditChange, len(entry.GetChanges()))
+		for j, change := range entry.GetChanges() {
+			changes[j] = AuditChange{Path: change.GetPath(), OldValue: change.GetOldValue(), NewValue: change.GetNewValue()}
+		}
+		resp[i] = AuditLogEntry{
+			ID:        entry.GetId(),
+			Timestamp: entry.GetTimestamp().AsTime(),
+			Actor:     entry.GetActor(),
+			RequestID: entry.GetRequestId(),
+			Operation: entry.GetOperation(),
+			Type:      entry.GetResourceId().GetResourceType().String(),
+			Name:      entry.GetResourceId().GetResource().GetName(),
+			Variant:   entry.GetResourceId().GetResource().GetVariant(),
+			Changes:   changes,
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+type LineageResource struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Variant string `json:"variant"`
+}
+
+type LineageNode struct {
+	LineageResource
+	Provider string `json:"provider"`
+	Location string `json:"location"`
+	Status   string `json:"status"`
+	Owner    string `json:"owner"`
+	Distance int    `json:"distance"`
+}
+
+type LineageColumn struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type LineageEdge struct {
+	From    LineageResource `json:"from"`
+	To      LineageResource `json:"to"`
+	Columns []LineageColumn `json:"columns"`
+}
+
+type LineageGraph struct {
+	Root  LineageResource `json:"root"`
+	Nodes []LineageNode   `json:"nodes"`
+	Edges []LineageEdge   `json:"edges"`
+}
+
+func lineageResource(id metadata.ResourceID) LineageResource {
+	return LineageResource{Type: id.Type.String(), Name: id.Name, Variant: id.Variant}
+}
+
+func lineageGraphResponse(graph *metadata.LineageGraph) LineageGraph {
+	resp := LineageGraph{
+		Root:  lineageResource(graph.Root),
+		Nodes: make([]LineageNode, len(graph.Nodes)),
+		Edges: make([]LineageEdge, len(graph.Edges)),
+	}
+	for i, node := range graph.Nodes {
+		resp.Nodes[i] = LineageNode{
+			LineageResource: lineageResource(node.ID),
+			Provider:        node.Provider,
+			Location:        node.Location,
+			Status:          node.Status,
+			Owner:           node.Owner,
+			Distance:        node.Distance,
+		}
+	}
+	for i, edge := range graph.Edges {
+		columns := make([]LineageColumn, len(edge.Columns))
+		for j, col := range edge.Columns {
+			columns[j] = LineageColumn{From: col.From, To: col.To}
+		}
+		resp.Edges[i] = LineageEdge{From: lineageResource(edge.From), To: lineageResource(edge.To), Columns: columns}
+	}
+	return resp
+}
+
+// GetLineage returns the lineage graph of a resource variant. type (such as FEATURE_VARIANT),
+// name and variant identify the resource. direction is upstream, downstream or both (the
+// default), depth limits how many edges are followed (0, the default, follows all of them) and
+// format is json (the default), dot or mermaid.
+func (m *MetadataServer) GetLineage(c *gin.Context) {
+	typeValue, ok := pb.ResourceType_value[c.Query("type")]
+	if !ok || c.Query("name") == "" {
+		fetchError := &FetchError{StatusCode: http.StatusBadRequest, Type: fmt.Sprintf("GetLineage - Invalid resource type %q or name %q", c.Query("type"), c.Query("name"))}
+		m.logger.Errorw(fetchError.Error(), "Metadata error")
+		c.JSON(fetchError.StatusCode, fetchError.Error())
+		return
+	}
+	id := metadata.ResourceID{Name: c.Query("name"), Variant: c.Query("variant"), Type: metadata.ResourceType(typeValue)}
+
+	directions := map[string]pb.LineageDirection{
+		"":           pb.LineageDirection_LINEAGE_BOTH,
+		"both":       pb.LineageDirection_LINEAGE_BOTH,
+		"upstream":   pb.LineageDirection_LINEAGE_UPSTREAM,
+		"downstream": pb.LineageDirection_LINEAGE_DOWNSTREAM,
+	}
+	direction, ok := directions[c.Query("direction")]
+	if !ok {
+		fetchError := &FetchError{StatusCode: http.StatusBadRequest, Type: fmt.Sprintf("GetLineage - Invalid direction: %s", c.Query("direction"))}
+		m.logger.Errorw(fetchError.Error(), "Metadata error")
+		c.JSON(fetchError.StatusCode, fetchError.Error())
+		return
+	}
+	depth := 0
+	if depthParam := c.Query("depth"); depthParam != "" {
+		var err error
+		depth, err = strconv.Atoi(depthParam)
+		if err != nil || depth < 0 {
+			fetchError := &FetchError{StatusCode: http.StatusBadRequest, Type: fmt.Sprintf("GetLineage - Invalid depth value: %s", depthParam)}
+			m.logger.Errorw(fetchError.Error(), "Metadata error")
+			c.JSON(fetchError.StatusCode, fetchError.Error())
+			return
+		}
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "dot" && format != "mermaid" {
+		fetchError := &FetchError{StatusCode: http.StatusBadRequest, Type: fmt.Sprintf("GetLineage - Invalid format: %s", format)}
+		m.logger.Errorw(fetchError.Error(), "Metadata error")
+		c.JSON(fetchError.StatusCode, fetchError.Error())
+		return
+	}
+
+	graph, err := m.client.GetLineage(c.Request.Context(), id, direction, depth)
+	if err != nil {
+		fetchError := m.GetRequestError(http.StatusInternalServerError, err, c, "GetLineage - Failed to fetch lineage")
+		c.JSON(fetchError.StatusCode, fetchError.Error())
+		return
+	}
+	switch format {
+	case "dot":
+		c.String(http.StatusOK, graph.DOT())
+	case "mermaid":
+		c.String(http.StatusOK, graph.Mermaid())
+	default:
+		c.JSON(http.StatusOK, lineageGraphResponse(graph))
+	}
+}
+
+type ResourceProfile struct {
+	Type    string                  `json:"type"`
+	Name    string                  `json:"name"`
+	Variant string                  `json:"variant"`
+	RunID   string                  `json:"runId"`
+	RunTime time.Time               `json:"runTime"`
+	Profile *fftypes.DatasetProfile `json:"profile"`
+}
+
+// GetProfile returns the data profile of a feature or training set variant. type
+// (FEATURE_VARIANT or TRAINING_SET_VARIANT), name and variant identify the resource and run_id
+// picks a run, defaulting to the latest one that recorded a profile.
+func (m *MetadataServer) GetProfile(c *gin.Context) {
+	typeValue, ok := pb.ResourceType_value[c.Query("type")]
+	if !ok || c.Query("name") == "" {
+		fetchError := &FetchError{StatusCode: http.StatusBadRequest, Type: fmt.Sprintf("GetProfile - Invalid resource type %q or name %q", c.Query("type"), c.Query("name"))}
+		m.logger.Errorw(fetchError.Error(), "Metadata error")
+		c.JSON(fetchError.StatusCode, fetchError.Error())
+		return
+	}
+	id := metadata.ResourceID{Name: c.Query("name"), Variant: c.Query("variant"), Type: metadata.ResourceType(typeValue)}
+
+	profile, err := m.client.GetProfile(c.Request.Context(), id, c.Query("run_id"))
+	if err != nil {
+		fetchError := m.GetRequestError(http.StatusInternalServerError, err, c, "GetProfile - Failed to fetch profile")
+		c.JSON(fetchError.StatusCode, fetchError.Error())
+		return
+	}
+	c.JSON(http.StatusOK, ResourceProfile{
+		Type:    profile.ID.Type.String(),
+		Name:    profile.ID.Name,
+		Variant: profile.ID.Variant,
+		RunID:   profile.RunID,
+		RunTime: profile.RunTime,
+		Profile: profile.Profile,
+	})
+}
+
 func (m *MetadataServer) GetIcebergData(c *gin.Context) {
 	source := c.Query("name")
 	variant := c.Query("variant")
@@ -3075,6 +3324,9 @@ func (m *MetadataServer) Start(port string, local bool) error {
 	router.POST("/data/models", m.GetModelResources)
 	router.GET("/data/:type/prop/owners", m.GetTypeOwners)
 	router.GET("/data/stream", m.GetIcebergData)
+	router.GET("/data/audit", m.GetAuditLog)
+	router.GET("/data/lineage", m.GetLineage)
+	router.GET("/data/profile", m.GetProfile)
 
 	return router.Run(port)
 }
@@ -21,8 +21,8 @@ type nameVariant struct {
 
 func nameVariantFromProto(proto *pb.NameVariant) nameVariant {
 	return nameVariant{
-		Name:    proto.Name,
-		Variant: proto.Variant,
+		Name:    proto.GetName(),
+		Variant: proto.GetVariant(),
 	}
 }
 
@@ -45,6 +45,7 @@ func (n nameVariant) IsEquivalent(other Equivalencer) bool {
 type resourceSnowflakeConfig struct {
 	DynamicTableConfig snowflakeDynamicTableConfig
 	Warehouse          string
+	RefreshStrategy    string
 }
 
 type snowflakeDynamicTableConfig struct {
@@ -58,10 +59,16 @@ func resourceSnowflakeConfigFromProto(proto *pb.ResourceSnowflakeConfig) resourc
 		return resourceSnowflakeConfig{}
 	}
 
+	var refreshStrategy string
+	if proto.RefreshStrategy != pb.SnowflakeRefreshStrategy_SNOWFLAKE_REFRESH_STRATEGY_UNSPECIFIED {
+		refreshStrategy = proto.RefreshStrategy.String()
+	}
+
 	dynamicTableConfig := proto.DynamicTableConfig
 	if dynamicTableConfig == nil {
 		return resourceSnowflakeConfig{
-			Warehouse: proto.Warehouse,
+			Warehouse:       proto.Warehouse,
+			RefreshStrategy: refreshStrategy,
 		}
 	}
 
@@ -71,7 +78,8 @@ func resourceSnowflakeConfigFromProto(proto *pb.ResourceSnowflakeConfig) resourc
 			RefreshMode: dynamicTableConfig.RefreshMode.String(),
 			Initialize:  dynamicTableConfig.Initialize.String(),
 		},
-		Warehouse: proto.Warehouse,
+		Warehouse:       proto.Warehouse,
+		RefreshStrategy: refreshStrategy,
 	}
 }
 
@@ -45,6 +45,17 @@ func TestResourceSnowflakeConfigFromProto(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "refresh strategy",
+			input: &pb.ResourceSnowflakeConfig{
+				Warehouse:       "test_warehouse",
+				RefreshStrategy: pb.SnowflakeRefreshStrategy_SNOWFLAKE_REFRESH_STRATEGY_STREAM,
+			},
+			expected: resourceSnowflakeConfig{
+				Warehouse:       "test_warehouse",
+				RefreshStrategy: "SNOWFLAKE_REFRESH_STRATEGY_STREAM",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -88,7 +88,8 @@ func primaryDataFromProto(proto *pb.PrimaryData) primaryData {
 		}
 	case *pb.PrimaryData_Filestore:
 		location = &fileStoreTable{
-			Path: l.Filestore.Path,
+			Path:        l.Filestore.Path,
+			TableFormat: l.Filestore.TableFormat,
 		}
 	case *pb.PrimaryData_Catalog:
 		location = &catalogTable{
@@ -182,7 +183,8 @@ func (s *sqlTable) IsEquivalent(other Equivalencer) bool {
 }
 
 type fileStoreTable struct {
-	Path string
+	Path        string
+	TableFormat string
 }
 
 func (f *fileStoreTable) IsLocationType() {}
@@ -192,7 +194,8 @@ func (f *fileStoreTable) IsEquivalent(other Equivalencer) bool {
 	if !ok {
 		return false
 	}
-	return f.Path == otherLoc.Path
+	return f.Path == otherLoc.Path &&
+		f.TableFormat == otherLoc.TableFormat
 }
 
 type catalogTable struct {
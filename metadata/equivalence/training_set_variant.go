@@ -50,6 +50,8 @@ type trainingSetVariant struct {
 	Name                    string
 	Features                []nameVariant
 	Label                   nameVariant
+	Spine                   *trainingSetSpine
+	AdditionalLabels        []nameVariant
 	LagFeatures             []featureLag
 	ResourceSnowflakeConfig resourceSnowflakeConfig
 	Type                    trainingSetType
@@ -64,6 +66,8 @@ func TrainingSetVariantFromProto(proto *pb.TrainingSetVariant) (trainingSetVaria
 		Name:                    proto.Name,
 		Features:                nameVariantsFromProto(proto.Features),
 		Label:                   nameVariantFromProto(proto.Label),
+		Spine:                   trainingSetSpineFromProto(proto.GetSpine()),
+		AdditionalLabels:        nameVariantsFromProto(proto.AdditionalLabels),
 		LagFeatures:             featureLagsFromProto(proto.FeatureLags),
 		ResourceSnowflakeConfig: resourceSnowflakeConfigFromProto(proto.ResourceSnowflakeConfig),
 		Type:                    trainingSetType,
@@ -82,6 +86,8 @@ func (t trainingSetVariant) IsEquivalent(other Equivalencer) bool {
 				reflect.DeepEqual(t1.Features, t2.Features) &&
 				reflect.DeepEqual(t1.LagFeatures, t2.LagFeatures) &&
 				t1.Label.IsEquivalent(t2.Label) &&
+				reflect.DeepEqual(t1.Spine, t2.Spine) &&
+				reflect.DeepEqual(t1.AdditionalLabels, t2.AdditionalLabels) &&
 				reflect.DeepEqual(t1.ResourceSnowflakeConfig, t2.ResourceSnowflakeConfig) &&
 				t1.Type == t2.Type
 		}),
@@ -97,6 +103,21 @@ func (t trainingSetVariant) IsEquivalent(other Equivalencer) bool {
 	return isEqual
 }
 
+type trainingSetSpine struct {
+	Source         nameVariant
+	EntityMappings entityMappings
+}
+
+func trainingSetSpineFromProto(proto *pb.TrainingSetSpine) *trainingSetSpine {
+	if proto == nil {
+		return nil
+	}
+	return &trainingSetSpine{
+		Source:         nameVariantFromProto(proto.GetSource()),
+		EntityMappings: entityMappingsFromProto(proto.GetEntityMappings()),
+	}
+}
+
 type featureLag struct {
 	Feature string
 	Name    string
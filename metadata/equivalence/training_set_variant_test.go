@@ -166,6 +166,58 @@ func TestTrainingSetVariantIsEquivalent(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "Different Spines",
+			ts1: trainingSetVariant{
+				Name:     "set1",
+				Features: []nameVariant{{Name: "feature1", Variant: "v1"}},
+				Spine: &trainingSetSpine{
+					Source:         nameVariant{Name: "users_to_score", Variant: "v1"},
+					EntityMappings: entityMappings{Mappings: []entityMapping{{Name: "user", EntityColumn: "user_id"}}},
+				},
+			},
+			ts2: trainingSetVariant{
+				Name:     "set1",
+				Features: []nameVariant{{Name: "feature1", Variant: "v1"}},
+				Spine: &trainingSetSpine{
+					Source:         nameVariant{Name: "users_to_score", Variant: "v2"},
+					EntityMappings: entityMappings{Mappings: []entityMapping{{Name: "user", EntityColumn: "user_id"}}},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "Spine and Label",
+			ts1: trainingSetVariant{
+				Name:     "set1",
+				Features: []nameVariant{{Name: "feature1", Variant: "v1"}},
+				Spine: &trainingSetSpine{
+					Source:         nameVariant{Name: "users_to_score", Variant: "v1"},
+					EntityMappings: entityMappings{Mappings: []entityMapping{{Name: "user", EntityColumn: "user_id"}}},
+				},
+			},
+			ts2: trainingSetVariant{
+				Name:     "set1",
+				Features: []nameVariant{{Name: "feature1", Variant: "v1"}},
+				Label:    nameVariant{Name: "label1", Variant: "v1"},
+			},
+			expected: false,
+		},
+		{
+			name: "Different Additional Labels",
+			ts1: trainingSetVariant{
+				Name:             "set1",
+				Features:         []nameVariant{{Name: "feature1", Variant: "v1"}},
+				Label:            nameVariant{Name: "label1", Variant: "v1"},
+				AdditionalLabels: []nameVariant{{Name: "label2", Variant: "v1"}},
+			},
+			ts2: trainingSetVariant{
+				Name:     "set1",
+				Features: []nameVariant{{Name: "feature1", Variant: "v1"}},
+				Label:    nameVariant{Name: "label1", Variant: "v1"},
+			},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,487 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/featureform/fferr"
+	pb "github.com/featureform/metadata/proto"
+)
+
+// ColumnLineage is a column of an upstream resource that's read into a column of a downstream one.
+// Feature and label columns are named after their role: the entity name, "value" or "timestamp".
+type ColumnLineage struct {
+	From string
+	To   string
+}
+
+// LineageEdge is data flowing from one resource variant into another.
+type LineageEdge struct {
+	From    ResourceID
+	To      ResourceID
+	Columns []ColumnLineage
+}
+
+// LineageNode is a resource variant in a LineageGraph. Distance is the number of edges from the
+// root, negative for upstream resources. Nodes for resources that no longer exist only have an ID.
+type LineageNode struct {
+	ID       ResourceID
+	Provider string
+	Location string
+	Status   string
+	Owner    string
+	Distance int
+}
+
+// LineageGraph is the upstream and downstream data lineage of a resource variant.
+type LineageGraph struct {
+	Root  ResourceID
+	Nodes []LineageNode
+	Edges []LineageEdge
+}
+
+func isLineageType(t ResourceType) bool {
+	switch t {
+	case SOURCE_VARIANT, FEATURE_VARIANT, LABEL_VARIANT, TRAINING_SET_VARIANT:
+		return true
+	default:
+		return false
+	}
+}
+
+type lineageStep struct {
+	id       ResourceID
+	distance int
+}
+
+type lineageBuilder struct {
+	lookup    ResourceLookup
+	resources map[ResourceID]Resource
+	nodes     map[ResourceID]int
+	edges     map[[2]ResourceID]bool
+	graph     *LineageGraph
+	// Sources read by each transformation, indexed by the source read. Built on first use since
+	// sources don't record the transformations that read them.
+	transformations map[ResourceID][]ResourceID
+}
+
+// BuildLineage returns the lineage of root up to depth edges away in the given direction. A depth
+// of zero follows the whole graph.
+func BuildLineage(ctx context.Context, lookup ResourceLookup, root ResourceID, direction pb.LineageDirection, depth int) (*LineageGraph, error) {
+	if !isLineageType(root.Type) {
+		return nil, fferr.NewInvalidArgumentErrorf("lineage is not supported for %s", root.Type)
+	}
+	if depth < 0 {
+		return nil, fferr.NewInvalidArgumentErrorf("lineage depth must not be negative: %d", depth)
+	}
+	b := &lineageBuilder{
+		lookup:    lookup,
+		resources: make(map[ResourceID]Resource),
+		nodes:     make(map[ResourceID]int),
+		edges:     make(map[[2]ResourceID]bool),
+		graph:     &LineageGraph{Root: root, Nodes: []LineageNode{}, Edges: []LineageEdge{}},
+	}
+	res, err := b.get(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fferr.NewKeyNotFoundError(root.String(), nil)
+	}
+	if err := b.addNode(ctx, root, 0); err != nil {
+		return nil, err
+	}
+	if direction != pb.LineageDirection_LINEAGE_DOWNSTREAM {
+		if err := b.walk(ctx, root, depth, -1, b.upstream); err != nil {
+			return nil, err
+		}
+	}
+	if direction != pb.LineageDirection_LINEAGE_UPSTREAM {
+		if err := b.walk(ctx, root, depth, 1, b.downstream); err != nil {
+			return nil, err
+		}
+	}
+	return b.graph, nil
+}
+
+// walk does a breadth first search from root, adding every edge that next returns along the way.
+// sign is -1 when walking upstream, where the neighbor is the edge's From, and 1 downstream.
+func (b *lineageBuilder) walk(ctx context.Context, root ResourceID, depth, sign int, next func(context.Context, ResourceID) ([]LineageEdge, error)) error {
+	visited := map[ResourceID]bool{root: true}
+	queue := []lineageStep{{id: root}}
+	for len(queue) > 0 {
+		step := queue[0]
+		queue = queue[1:]
+		if depth != 0 && step.distance >= depth {
+			continue
+		}
+		edges, err := next(ctx, step.id)
+		if err != nil {
+			return err
+		}
+		for _, edge := range edges {
+			neighbor := edge.To
+			if sign < 0 {
+				neighbor = edge.From
+			}
+			if err := b.addNode(ctx, neighbor, sign*(step.distance+1)); err != nil {
+				return err
+			}
+			b.addEdge(edge)
+			if !visited[neighbor] {
+				visited[neighbor] = true
+				queue = append(queue, lineageStep{id: neighbor, distance: step.distance + 1})
+			}
+		}
+	}
+	return nil
+}
+
+// get returns the resource with id, or nil if it doesn't exist.
+func (b *lineageBuilder) get(ctx context.Context, id ResourceID) (Resource, error) {
+	if res, ok := b.resources[id]; ok {
+		return res, nil
+	}
+	res, err := b.lookup.Lookup(ctx, id)
+	var notFound *fferr.KeyNotFoundError
+	if errors.As(err, &notFound) {
+		res, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.resources[id] = res
+	return res, nil
+}
+
+func (b *lineageBuilder) addNode(ctx context.Context, id ResourceID, distance int) error {
+	if _, ok := b.nodes[id]; ok {
+		return nil
+	}
+	res, err := b.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	node := LineageNode{ID: id, Distance: distance}
+	if res != nil {
+		node.Status = res.GetStatus().GetStatus().String()
+		msg := res.Proto()
+		if p, ok := msg.(interface{ GetProvider() string }); ok {
+			node.Provider = p.GetProvider()
+		}
+		if o, ok := msg.(interface{ GetOwner() string }); ok {
+			node.Owner = o.GetOwner()
+		}
+		if sv, ok := msg.(*pb.SourceVariant); ok {
+			location, err := WrapProtoSourceVariant(sv).GetLocation(ctx)
+			if err == nil && location != nil {
+				node.Location = location.Location()
+			}
+		}
+	}
+	b.nodes[id] = len(b.graph.Nodes)
+	b.graph.Nodes = append(b.graph.Nodes, node)
+	return nil
+}
+
+func (b *lineageBuilder) addEdge(edge LineageEdge) {
+	key := [2]ResourceID{edge.From, edge.To}
+	if b.edges[key] {
+		return
+	}
+	b.edges[key] = true
+	b.graph.Edges = append(b.graph.Edges, edge)
+}
+
+func (b *lineageBuilder) upstream(ctx context.Context, id ResourceID) ([]LineageEdge, error) {
+	res, err := b.get(ctx, id)
+	if err != nil || res == nil {
+		return nil, err
+	}
+	return upstreamLineage(res), nil
+}
+
+func (b *lineageBuilder) downstream(ctx context.Context, id ResourceID) ([]LineageEdge, error) {
+	res, err := b.get(ctx, id)
+	if err != nil || res == nil {
+		return nil, err
+	}
+	var candidates []ResourceID
+	switch serialized := res.Proto().(type) {
+	case *pb.SourceVariant:
+		candidates = append(candidates, nameVariantIDs(FEATURE_VARIANT, serialized.GetFeatures())...)
+		candidates = append(candidates, nameVariantIDs(LABEL_VARIANT, serialized.GetLabels())...)
+		candidates = append(candidates, nameVariantIDs(TRAINING_SET_VARIANT, serialized.GetTrainingsets())...)
+		transformations, err := b.transformationsOf(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, transformations...)
+	case *pb.FeatureVariant:
+		candidates = nameVariantIDs(TRAINING_SET_VARIANT, serialized.GetTrainingsets())
+	case *pb.LabelVariant:
+		candidates = nameVariantIDs(TRAINING_SET_VARIANT, serialized.GetTrainingsets())
+	}
+	// The downstream resource knows which columns it reads, so take the edge from its side.
+	var edges []LineageEdge
+	for _, candidate := range candidates {
+		child, err := b.get(ctx, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if child == nil {
+			continue
+		}
+		for _, edge := range upstreamLineage(child) {
+			if edge.From == id {
+				edges = append(edges, edge)
+			}
+		}
+	}
+	return edges, nil
+}
+
+func (b *lineageBuilder) transformationsOf(ctx context.Context, id ResourceID) ([]ResourceID, error) {
+	if b.transformations == nil {
+		sources, err := b.lookup.ListForType(ctx, SOURCE_VARIANT)
+		if err != nil {
+			return nil, err
+		}
+		b.transformations = make(map[ResourceID][]ResourceID)
+		for _, source := range sources {
+			for _, edge := range upstreamLineage(source) {
+				b.transformations[edge.From] = append(b.transformations[edge.From], edge.To)
+			}
+		}
+	}
+	return b.transformations[id], nil
+}
+
+func nameVariantIDs(t ResourceType, nvs []*pb.NameVariant) []ResourceID {
+	ids := make([]ResourceID, 0, len(nvs))
+	for _, nv := range nvs {
+		if nv.GetName() != "" {
+			ids = append(ids, ResourceID{Name: nv.GetName(), Variant: nv.GetVariant(), Type: t})
+		}
+	}
+	return ids
+}
+
+func roleColumns(mappings []EntityMapping, value, ts string) []ColumnLineage {
+	var columns []ColumnLineage
+	for _, mapping := range mappings {
+		if mapping.EntityColumn != "" {
+			columns = append(columns, ColumnLineage{From: mapping.EntityColumn, To: mapping.Name})
+		}
+	}
+	if value != "" {
+		columns = append(columns, ColumnLineage{From: value, To: "value"})
+	}
+	if ts != "" {
+		columns = append(columns, ColumnLineage{From: ts, To: "timestamp"})
+	}
+	return columns
+}
+
+// upstreamLineage returns the edges into res from the resources its data is read from.
+func upstreamLineage(res Resource) []LineageEdge {
+	to := res.ID()
+	edgesFrom := func(ids []ResourceID) []LineageEdge {
+		edges := make([]LineageEdge, len(ids))
+		for i, id := range ids {
+			edges[i] = LineageEdge{From: id, To: to}
+		}
+		return edges
+	}
+	switch serialized := res.Proto().(type) {
+	case *pb.SourceVariant:
+		variant := WrapProtoSourceVariant(serialized)
+		var ids []ResourceID
+		for _, nv := range append(variant.SQLTransformationSources(), variant.DFTransformationSources()...) {
+			ids = append(ids, ResourceID{Name: nv.Name, Variant: nv.Variant, Type: SOURCE_VARIANT})
+		}
+		return edgesFrom(ids)
+	case *pb.FeatureVariant:
+		if serialized.GetSource().GetName() == "" {
+			return nil
+		}
+		edge := LineageEdge{From: nameVariantIDs(SOURCE_VARIANT, []*pb.NameVariant{serialized.GetSource()})[0], To: to}
+		if cols := serialized.GetColumns(); cols != nil {
+			edge.Columns = roleColumns([]EntityMapping{{Name: serialized.GetEntity(), EntityColumn: cols.GetEntity()}}, cols.GetValue(), cols.GetTs())
+		}
+		return []LineageEdge{edge}
+	case *pb.LabelVariant:
+		if serialized.GetSource().GetName() == "" {
+			return nil
+		}
+		edge := LineageEdge{From: nameVariantIDs(SOURCE_VARIANT, []*pb.NameVariant{serialized.GetSource()})[0], To: to}
+		hasColumns := serialized.GetColumns() != nil || serialized.GetEntityMappings() != nil
+		if mappings, err := WrapProtoLabelVariant(serialized).Location(); hasColumns && err == nil {
+			edge.Columns = roleColumns(mappings.Mappings, mappings.ValueColumn, mappings.TimestampColumn)
+		}
+		return []LineageEdge{edge}
+	case *pb.TrainingSetVariant:
+		ids := nameVariantIDs(FEATURE_VARIANT, serialized.GetFeatures())
+		ids = append(ids, nameVariantIDs(LABEL_VARIANT, []*pb.NameVariant{serialized.GetLabel()})...)
+		ids = append(ids, nameVariantIDs(LABEL_VARIANT, serialized.GetAdditionalLabels())...)
+		edges := edgesFrom(ids)
+		if spine := WrapProtoTrainingSetVariant(serialized).Spine(); spine != nil {
+			mappings := spine.EntityMappings
+			edges = append(edges, LineageEdge{
+				From:    ResourceID{Name: spine.Source.Name, Variant: spine.Source.Variant, Type: SOURCE_VARIANT},
+				To:      to,
+				Columns: roleColumns(mappings.Mappings, mappings.ValueColumn, mappings.TimestampColumn),
+			})
+		}
+		return edges
+	default:
+		return nil
+	}
+}
+
+func lineageIDFromProto(id *pb.ResourceID) ResourceID {
+	return ResourceID{
+		Name:    id.GetResource().GetName(),
+		Variant: id.GetResource().GetVariant(),
+		Type:    ResourceType(id.GetResourceType()),
+	}
+}
+
+func (g *LineageGraph) Proto() *pb.LineageGraph {
+	msg := &pb.LineageGraph{
+		Root:  g.Root.Proto(),
+		Nodes: make([]*pb.LineageNode, len(g.Nodes)),
+		Edges: make([]*pb.LineageEdge, len(g.Edges)),
+	}
+	for i, node := range g.Nodes {
+		msg.Nodes[i] = &pb.LineageNode{
+			ResourceId: node.ID.Proto(),
+			Provider:   node.Provider,
+			Location:   node.Location,
+			Status:     node.Status,
+			Owner:      node.Owner,
+			Distance:   int32(node.Distance),
+		}
+	}
+	for i, edge := range g.Edges {
+		columns := make([]*pb.ColumnLineage, len(edge.Columns))
+		for j, col := range edge.Columns {
+			columns[j] = &pb.ColumnLineage{FromColumn: col.From, ToColumn: col.To}
+		}
+		msg.Edges[i] = &pb.LineageEdge{From: edge.From.Proto(), To: edge.To.Proto(), Columns: columns}
+	}
+	return msg
+}
+
+func LineageGraphFromProto(msg *pb.LineageGraph) *LineageGraph {
+	g := &LineageGraph{
+		Root:  lineageIDFromProto(msg.GetRoot()),
+		Nodes: make([]LineageNode, len(msg.GetNodes())),
+		Edges: make([]LineageEdge, len(msg.GetEdges())),
+	}
+	for i, node := range msg.GetNodes() {
+		g.Nodes[i] = LineageNode{
+			ID:       lineageIDFromProto(node.GetResourceId()),
+			Provider: node.GetProvider(),
+			Location: node.GetLocation(),
+			Status:   node.GetStatus(),
+			Owner:    node.GetOwner(),
+			Distance: int(node.GetDistance()),
+		}
+	}
+	for i, edge := range msg.GetEdges() {
+		var columns []ColumnLineage
+		for _, col := range edge.GetColumns() {
+			columns = append(columns, ColumnLineage{From: col.GetFromColumn(), To: col.GetToColumn()})
+		}
+		g.Edges[i] = LineageEdge{From: lineageIDFromProto(edge.GetFrom()), To: lineageIDFromProto(edge.GetTo()), Columns: columns}
+	}
+	return g
+}
+
+func lineageLabel(node LineageNode) []string {
+	lines := []string{fmt.Sprintf("%s (%s)", node.ID.Name, node.ID.Variant), node.ID.Type.String()}
+	if node.Provider != "" {
+		lines = append(lines, node.Provider)
+	}
+	if node.Location != "" {
+		lines = append(lines, node.Location)
+	}
+	return lines
+}
+
+func columnsLabel(columns []ColumnLineage) string {
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = fmt.Sprintf("%s → %s", col.From, col.To)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// DOT renders the graph in Graphviz's DOT language.
+func (g *LineageGraph) DOT() string {
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+	}
+	var sb strings.Builder
+	sb.WriteString("digraph lineage {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, node := range g.Nodes {
+		lines := lineageLabel(node)
+		for i := range lines {
+			lines[i] = strings.ReplaceAll(strings.ReplaceAll(lines[i], `\`, `\\`), `"`, `\"`)
+		}
+		attrs := fmt.Sprintf(`label="%s"`, strings.Join(lines, `\n`))
+		if node.ID == g.Root {
+			attrs += ", style=bold"
+		}
+		fmt.Fprintf(&sb, "  %s [%s];\n", quote(node.ID.String()), attrs)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&sb, "  %s -> %s", quote(edge.From.String()), quote(edge.To.String()))
+		if len(edge.Columns) > 0 {
+			fmt.Fprintf(&sb, " [label=%s]", quote(columnsLabel(edge.Columns)))
+		}
+		sb.WriteString(";\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *LineageGraph) Mermaid() string {
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, `"`, "#quot;")
+	}
+	ids := make(map[ResourceID]string, len(g.Nodes))
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+		lines := lineageLabel(node)
+		for j := range lines {
+			lines[j] = escape(lines[j])
+		}
+		fmt.Fprintf(&sb, "  %s[\"%s\"]\n", ids[node.ID], strings.Join(lines, "<br/>"))
+	}
+	for _, edge := range g.Edges {
+		from, to := ids[edge.From], ids[edge.To]
+		if len(edge.Columns) > 0 {
+			fmt.Fprintf(&sb, "  %s -->|\"%s\"| %s\n", from, escape(columnsLabel(edge.Columns)), to)
+		} else {
+			fmt.Fprintf(&sb, "  %s --> %s\n", from, to)
+		}
+	}
+	if root, ok := ids[g.Root]; ok {
+		fmt.Fprintf(&sb, "  style %s stroke-width:3px\n", root)
+	}
+	return sb.String()
+}
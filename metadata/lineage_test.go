@@ -0,0 +1,210 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package metadata
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/featureform/fferr"
+	pb "github.com/featureform/metadata/proto"
+)
+
+var (
+	transactionsID = ResourceID{Name: "transactions", Variant: "v1", Type: SOURCE_VARIANT}
+	avgTxID        = ResourceID{Name: "avg_tx", Variant: "v1", Type: SOURCE_VARIANT}
+	avgAmountID    = ResourceID{Name: "avg_amount", Variant: "v1", Type: FEATURE_VARIANT}
+	fraudID        = ResourceID{Name: "fraud", Variant: "v1", Type: LABEL_VARIANT}
+	fraudTrainID   = ResourceID{Name: "fraud_training", Variant: "v1", Type: TRAINING_SET_VARIANT}
+)
+
+// lineageTestLookup builds:
+//
+//	transactions -> avg_tx -> avg_amount -> fraud_training
+//	transactions -> fraud ----------------> fraud_training
+func lineageTestLookup() LocalResourceLookup {
+	nv := func(id ResourceID) *pb.NameVariant {
+		return id.NameVariantProto()
+	}
+	return LocalResourceLookup{
+		transactionsID: &sourceVariantResource{&pb.SourceVariant{
+			Name:     transactionsID.Name,
+			Variant:  transactionsID.Variant,
+			Owner:    "alice",
+			Provider: "postgres",
+			Status:   &pb.ResourceStatus{Status: pb.ResourceStatus_READY},
+			Definition: &pb.SourceVariant_PrimaryData{PrimaryData: &pb.PrimaryData{
+				Location: &pb.PrimaryData_Table{Table: &pb.SQLTable{Name: "tx"}},
+			}},
+			Labels: []*pb.NameVariant{nv(fraudID)},
+		}},
+		avgTxID: &sourceVariantResource{&pb.SourceVariant{
+			Name:     avgTxID.Name,
+			Variant:  avgTxID.Variant,
+			Owner:    "bob",
+			Provider: "postgres",
+			Definition: &pb.SourceVariant_Transformation{Transformation: &pb.Transformation{
+				Type: &pb.Transformation_SQLTransformation{SQLTransformation: &pb.SQLTransformation{
+					Query:  "SELECT user_id, avg(amount) AS avg, max(ts) AS ts FROM {{transactions.v1}} GROUP BY user_id",
+					Source: []*pb.NameVariant{nv(transactionsID)},
+				}},
+			}},
+			Features: []*pb.NameVariant{nv(avgAmountID)},
+		}},
+		avgAmountID: &featureVariantResource{&pb.FeatureVariant{
+			Name:         avgAmountID.Name,
+			Variant:      avgAmountID.Variant,
+			Source:       nv(avgTxID),
+			Entity:       "user",
+			Provider:     "redis",
+			Location:     &pb.FeatureVariant_Columns{Columns: &pb.Columns{Entity: "user_id", Value: "avg", Ts: "ts"}},
+			Trainingsets: []*pb.NameVariant{nv(fraudTrainID)},
+		}},
+		fraudID: &labelVariantResource{&pb.LabelVariant{
+			Name:     fraudID.Name,
+			Variant:  fraudID.Variant,
+			Source:   nv(transactionsID),
+			Provider: "postgres",
+			Location: &pb.LabelVariant_EntityMappings{EntityMappings: &pb.EntityMappings{
+				Mappings:    []*pb.EntityMapping{{Name: "user", EntityColumn: "user_id"}},
+				ValueColumn: "is_fraud",
+			}},
+			Trainingsets: []*pb.NameVariant{nv(fraudTrainID)},
+		}},
+		fraudTrainID: &trainingSetVariantResource{&pb.TrainingSetVariant{
+			Name:     fraudTrainID.Name,
+			Variant:  fraudTrainID.Variant,
+			Provider: "postgres",
+			Features: []*pb.NameVariant{nv(avgAmountID)},
+			Label:    nv(fraudID),
+		}},
+	}
+}
+
+func lineageNode(t *testing.T, graph *LineageGraph, id ResourceID) LineageNode {
+	for _, node := range graph.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+	t.Fatalf("%s is not in the graph", id)
+	return LineageNode{}
+}
+
+func lineageEdge(t *testing.T, graph *LineageGraph, from, to ResourceID) LineageEdge {
+	for _, edge := range graph.Edges {
+		if edge.From == from && edge.To == to {
+			return edge
+		}
+	}
+	t.Fatalf("%s -> %s is not in the graph", from, to)
+	return LineageEdge{}
+}
+
+func TestLineageUpstream(t *testing.T) {
+	graph, err := BuildLineage(context.Background(), lineageTestLookup(), fraudTrainID, pb.LineageDirection_LINEAGE_UPSTREAM, 0)
+	if err != nil {
+		t.Fatalf("Failed to build lineage: %v", err)
+	}
+	assert.Len(t, graph.Nodes, 5)
+	assert.Len(t, graph.Edges, 5)
+	assert.Equal(t, 0, lineageNode(t, graph, fraudTrainID).Distance)
+	assert.Equal(t, -1, lineageNode(t, graph, avgAmountID).Distance)
+	assert.Equal(t, -1, lineageNode(t, graph, fraudID).Distance)
+	assert.Equal(t, -2, lineageNode(t, graph, avgTxID).Distance)
+	assert.Equal(t, -2, lineageNode(t, graph, transactionsID).Distance)
+
+	transactions := lineageNode(t, graph, transactionsID)
+	assert.Equal(t, "postgres", transactions.Provider)
+	assert.Equal(t, "alice", transactions.Owner)
+	assert.Equal(t, pb.ResourceStatus_READY.String(), transactions.Status)
+	assert.Contains(t, transactions.Location, "tx")
+
+	assert.Equal(t, []ColumnLineage{{"user_id", "user"}, {"avg", "value"}, {"ts", "timestamp"}}, lineageEdge(t, graph, avgTxID, avgAmountID).Columns)
+	assert.Equal(t, []ColumnLineage{{"user_id", "user"}, {"is_fraud", "value"}}, lineageEdge(t, graph, transactionsID, fraudID).Columns)
+	assert.Empty(t, lineageEdge(t, graph, transactionsID, avgTxID).Columns)
+}
+
+func TestLineageDownstream(t *testing.T) {
+	graph, err := BuildLineage(context.Background(), lineageTestLookup(), transactionsID, pb.LineageDirection_LINEAGE_DOWNSTREAM, 1)
+	if err != nil {
+		t.Fatalf("Failed to build lineage: %v", err)
+	}
+	// The transformation reading transactions is found even though transactions doesn't list it.
+	assert.Len(t, graph.Nodes, 3)
+	assert.Len(t, graph.Edges, 2)
+	assert.Equal(t, 1, lineageNode(t, graph, avgTxID).Distance)
+	assert.Equal(t, 1, lineageNode(t, graph, fraudID).Distance)
+	assert.Equal(t, []ColumnLineage{{"user_id", "user"}, {"is_fraud", "value"}}, lineageEdge(t, graph, transactionsID, fraudID).Columns)
+
+	graph, err = BuildLineage(context.Background(), lineageTestLookup(), transactionsID, pb.LineageDirection_LINEAGE_DOWNSTREAM, 0)
+	if err != nil {
+		t.Fatalf("Failed to build lineage: %v", err)
+	}
+	assert.Len(t, graph.Nodes, 5)
+	assert.Len(t, graph.Edges, 5)
+	assert.Equal(t, 2, lineageNode(t, graph, fraudTrainID).Distance)
+}
+
+func TestLineageBothDirections(t *testing.T) {
+	graph, err := BuildLineage(context.Background(), lineageTestLookup(), avgAmountID, pb.LineageDirection_LINEAGE_BOTH, 1)
+	if err != nil {
+		t.Fatalf("Failed to build lineage: %v", err)
+	}
+	assert.Len(t, graph.Nodes, 3)
+	assert.Equal(t, -1, lineageNode(t, graph, avgTxID).Distance)
+	assert.Equal(t, 1, lineageNode(t, graph, fraudTrainID).Distance)
+	lineageEdge(t, graph, avgTxID, avgAmountID)
+	lineageEdge(t, graph, avgAmountID, fraudTrainID)
+}
+
+func TestLineageMissingResources(t *testing.T) {
+	lookup := lineageTestLookup()
+	delete(lookup, avgTxID)
+	graph, err := BuildLineage(context.Background(), lookup, avgAmountID, pb.LineageDirection_LINEAGE_UPSTREAM, 0)
+	if err != nil {
+		t.Fatalf("Failed to build lineage: %v", err)
+	}
+	// The deleted source is still shown, but nothing past it.
+	assert.Len(t, graph.Nodes, 2)
+	assert.Equal(t, LineageNode{ID: avgTxID, Distance: -1}, lineageNode(t, graph, avgTxID))
+
+	_, err = BuildLineage(context.Background(), lookup, avgTxID, pb.LineageDirection_LINEAGE_BOTH, 0)
+	var notFound *fferr.KeyNotFoundError
+	assert.True(t, errors.As(err, &notFound), "expected KeyNotFoundError, got %v", err)
+
+	_, err = BuildLineage(context.Background(), lookup, ResourceID{Name: "postgres", Type: PROVIDER}, pb.LineageDirection_LINEAGE_BOTH, 0)
+	var invalidArg *fferr.InvalidArgumentError
+	assert.True(t, errors.As(err, &invalidArg), "expected InvalidArgumentError, got %v", err)
+}
+
+func TestLineageExport(t *testing.T) {
+	graph, err := BuildLineage(context.Background(), lineageTestLookup(), avgAmountID, pb.LineageDirection_LINEAGE_BOTH, 1)
+	if err != nil {
+		t.Fatalf("Failed to build lineage: %v", err)
+	}
+
+	assert.Equal(t, graph, LineageGraphFromProto(graph.Proto()))
+
+	dot := graph.DOT()
+	assert.True(t, strings.HasPrefix(dot, "digraph lineage {"))
+	assert.Contains(t, dot, `"SOURCE_VARIANT avg_tx (v1)" -> "FEATURE_VARIANT avg_amount (v1)" [label="user_id → user, avg → value, ts → timestamp"];`)
+	assert.Contains(t, dot, `"FEATURE_VARIANT avg_amount (v1)" -> "TRAINING_SET_VARIANT fraud_training (v1)";`)
+	assert.Contains(t, dot, `"FEATURE_VARIANT avg_amount (v1)" [label="avg_amount (v1)\nFEATURE_VARIANT\nredis", style=bold];`)
+
+	mermaid := graph.Mermaid()
+	assert.True(t, strings.HasPrefix(mermaid, "flowchart LR\n"))
+	assert.Contains(t, mermaid, `n0["avg_amount (v1)<br/>FEATURE_VARIANT<br/>redis"]`)
+	assert.Contains(t, mermaid, `n1 -->|"user_id → user, avg → value, ts → timestamp"| n0`)
+	assert.Contains(t, mermaid, "n0 --> n2")
+	assert.Contains(t, mermaid, "style n0 stroke-width:3px")
+}
@@ -29,7 +29,9 @@ import (
 	"google.golang.org/protobuf/proto"
 	tspb "google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/featureform/auth"
 	ct "github.com/featureform/coordinator/types"
+	"github.com/featureform/encryption"
 	"github.com/featureform/fferr"
 	"github.com/featureform/filestore"
 	"github.com/featureform/helpers/interceptors"
@@ -194,6 +196,79 @@ func (serv *MetadataServer) needsJob(res Resource) bool {
 	return false
 }
 
+func isIncrementalSQLTransformation(res Resource) bool {
+	sv, ok := res.(*sourceVariantResource)
+	if !ok {
+		return false
+	}
+	return sv.serialized.GetTransformation().GetSQLTransformation().GetIsIncremental()
+}
+
+func dailyPartitionColumn(res Resource) string {
+	sv, ok := res.(*sourceVariantResource)
+	if !ok {
+		return ""
+	}
+	return sv.serialized.GetTransformation().GetDailyPartition().GetColumn()
+}
+
+// backfillTriggers splits a backfill of res into the triggers of its task runs. A daily partitioned
+// SQL transformation is rebuilt a batch of whole days at a time, so each run overwrites only its
+// own partitions, which stores of storeType must support. Incremental transformations and
+// features are rebuilt in a single run.
+func backfillTriggers(res Resource, storeType pt.Type, backfill scheduling.BackfillTrigger, partitionsPerRun int) ([]scheduling.Trigger, error) {
+	id := res.ID()
+	isPartitioned := dailyPartitionColumn(res) != ""
+	isSQL := false
+	if sv, ok := res.(*sourceVariantResource); ok {
+		isSQL = sv.serialized.GetTransformation().GetSQLTransformation() != nil
+	}
+	switch {
+	case isSQL && isPartitioned && !pt.SupportsIncrementalTransformation(storeType):
+		return nil, fferr.NewInvalidArgumentErrorf(
+			"%s (%s) is stored in %s, which can't overwrite its partitions, and can't be backfilled",
+			id.Name, id.Variant, storeType,
+		)
+	case isSQL && isPartitioned:
+		return dailyBackfillTriggers(backfill, partitionsPerRun), nil
+	case isIncrementalSQLTransformation(res), id.Type == FEATURE_VARIANT:
+		return []scheduling.Trigger{backfill}, nil
+	default:
+		return nil, fferr.NewInvalidArgumentErrorf(
+			"%s (%s) is not an incremental or daily partitioned SQL transformation, or a feature, and can't be backfilled",
+			id.Name, id.Variant,
+		)
+	}
+}
+
+// dailyBackfillTriggers widens the backfill to whole days and splits it into ranges of
+// partitionsPerRun days.
+func dailyBackfillTriggers(backfill scheduling.BackfillTrigger, partitionsPerRun int) []scheduling.Trigger {
+	const day = 24 * time.Hour
+	if partitionsPerRun < 1 {
+		partitionsPerRun = 1
+	}
+	start := backfill.Start.UTC().Truncate(day)
+	end := backfill.End.UTC().Truncate(day)
+	if end.Before(backfill.End) {
+		end = end.Add(day)
+	}
+	triggers := make([]scheduling.Trigger, 0)
+	for batchStart := start; batchStart.Before(end); {
+		batchEnd := batchStart.Add(time.Duration(partitionsPerRun) * day)
+		if batchEnd.After(end) {
+			batchEnd = end
+		}
+		triggers = append(triggers, scheduling.BackfillTrigger{
+			TriggerName: backfill.TriggerName,
+			Start:       batchStart,
+			End:         batchEnd,
+		})
+		batchStart = batchEnd
+	}
+	return triggers
+}
+
 func (serv *MetadataServer) needsRun(ctx context.Context, res Resource) bool {
 	logger := logging.GetLoggerFromContext(ctx)
 	switch res.ID().Type {
@@ -1431,16 +1506,31 @@ func (resource *trainingSetVariantResource) Dependencies(ctx context.Context, lo
 			Name: serialized.Provider,
 			Type: PROVIDER,
 		},
-		{
-			Name:    serialized.Label.Name,
-			Variant: serialized.Label.Variant,
-			Type:    LABEL_VARIANT,
-		},
 		{
 			Name: serialized.Name,
 			Type: TRAINING_SET,
 		},
 	}
+	if spine := serialized.GetSpine(); spine != nil {
+		depIds = append(depIds, ResourceID{
+			Name:    spine.Source.Name,
+			Variant: spine.Source.Variant,
+			Type:    SOURCE_VARIANT,
+		})
+	} else {
+		depIds = append(depIds, ResourceID{
+			Name:    serialized.Label.Name,
+			Variant: serialized.Label.Variant,
+			Type:    LABEL_VARIANT,
+		})
+	}
+	for _, label := range serialized.AdditionalLabels {
+		depIds = append(depIds, ResourceID{
+			Name:    label.Name,
+			Variant: label.Variant,
+			Type:    LABEL_VARIANT,
+		})
+	}
 	for _, feature := range serialized.Features {
 		depIds = append(depIds, ResourceID{
 			Name:    feature.Name,
@@ -1534,15 +1624,53 @@ func (resource *trainingSetVariantResource) Owner() string {
 }
 
 func (resource *trainingSetVariantResource) Validate(ctx context.Context, lookup ResourceLookup) error {
+	var entityMap map[string]struct{}
+	var err error
+	if spine := resource.serialized.GetSpine(); spine != nil {
+		entityMap, err = resource.spineEntities(ctx, lookup, spine)
+	} else {
+		entityMap, err = resource.labelEntities(ctx, lookup)
+	}
+	if err != nil {
+		return err
+	}
+	if err := resource.validateAdditionalLabels(ctx, lookup, entityMap); err != nil {
+		return err
+	}
+	for _, feature := range resource.serialized.Features {
+		fvResId := ResourceID{Name: feature.Name, Variant: feature.Variant, Type: FEATURE_VARIANT}
+		featureResource, err := lookup.Lookup(ctx, fvResId)
+		if err != nil {
+			return err
+		}
+		featureVariant, isFeatureVariant := featureResource.(*featureVariantResource)
+		if !isFeatureVariant {
+			return fferr.NewDatasetNotFoundError(feature.Name, feature.Variant, fmt.Errorf("feature variant not found"))
+		}
+		switch featureVariant.serialized.Mode {
+		case pb.ComputationMode_PRECOMPUTED:
+			if _, exists := entityMap[featureVariant.serialized.Entity]; !exists {
+				return fferr.NewInvalidArgumentErrorf("feature %s entity %s does not match any label or spine entity", feature.Name, featureVariant.serialized.Entity)
+			}
+		case pb.ComputationMode_CLIENT_COMPUTED:
+			return fferr.NewInvalidArgumentErrorf("feature %s has unsupported computation mode %s", feature.Name, featureVariant.serialized.Mode)
+		default:
+			return fferr.NewInternalErrorf("feature %s has unknown computation mode %s", feature.Name, featureVariant.serialized.Mode)
+		}
+	}
+	return nil
+}
+
+func (resource *trainingSetVariantResource) labelEntities(ctx context.Context, lookup ResourceLookup) (map[string]struct{}, error) {
 	logger := logging.GetLoggerFromContext(ctx)
 	resId := ResourceID{Name: resource.serialized.Label.Name, Variant: resource.serialized.Label.Variant, Type: LABEL_VARIANT}
 	label, err := lookup.Lookup(ctx, resId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	labelVariant, isLabelVariant := label.(*labelVariantResource)
 	if !isLabelVariant {
-		return fferr.NewDatasetNotFoundError(resource.ID().Name, resource.ID().Variant, fmt.Errorf("label variant not found"))
+		return nil, fferr.NewDatasetNotFoundError(resource.ID().Name, resource.ID().Variant, fmt.Errorf("label variant not found"))
 	}
 	entityMap := make(map[string]struct{})
 	loc := labelVariant.serialized.GetLocation()
@@ -1557,32 +1685,75 @@ func (resource *trainingSetVariantResource) Validate(ctx context.Context, lookup
 		// There's nothing to be done here; however, we don't want the match on stream to result in an error.
 		logger.Debugw("stream location type detected for training set variant resource", "location", loc)
 	default:
-		return fferr.NewInternalErrorf("unknown location type %T", loc)
+		return nil, fferr.NewInternalErrorf("unknown location type %T", loc)
 	}
-	for _, feature := range resource.serialized.Features {
-		fvResId := ResourceID{Name: feature.Name, Variant: feature.Variant, Type: FEATURE_VARIANT}
-		featureResource, err := lookup.Lookup(ctx, fvResId)
+	return entityMap, nil
+}
+
+// validateAdditionalLabels checks that every additional label is keyed on a single entity
+// that the label or spine maps a column to, since that column is what it's joined on.
+func (resource *trainingSetVariantResource) validateAdditionalLabels(ctx context.Context, lookup ResourceLookup, entityMap map[string]struct{}) error {
+	seen := make(map[NameVariant]struct{})
+	if label := resource.serialized.GetLabel(); label != nil {
+		seen[parseNameVariant(label)] = struct{}{}
+	}
+	for _, label := range resource.serialized.AdditionalLabels {
+		if _, exists := seen[parseNameVariant(label)]; exists {
+			return fferr.NewInvalidArgumentErrorf("label %s (%s) is included in the training set more than once", label.Name, label.Variant)
+		}
+		seen[parseNameVariant(label)] = struct{}{}
+		resId := ResourceID{Name: label.Name, Variant: label.Variant, Type: LABEL_VARIANT}
+		labelResource, err := lookup.Lookup(ctx, resId)
 		if err != nil {
 			return err
 		}
-		featureVariant, isFeatureVariant := featureResource.(*featureVariantResource)
-		if !isFeatureVariant {
-			return fferr.NewDatasetNotFoundError(feature.Name, feature.Variant, fmt.Errorf("feature variant not found"))
+		labelVariant, isLabelVariant := labelResource.(*labelVariantResource)
+		if !isLabelVariant {
+			return fferr.NewDatasetNotFoundError(label.Name, label.Variant, fmt.Errorf("label variant not found"))
 		}
-		switch featureVariant.serialized.Mode {
-		case pb.ComputationMode_PRECOMPUTED:
-			if _, exists := entityMap[featureVariant.serialized.Entity]; !exists {
-				return fferr.NewInvalidArgumentErrorf("feature %s entity %s does not match label entity %s", feature.Name, featureVariant.serialized.Entity, labelVariant.serialized.Entity)
+		var entity string
+		switch loc := labelVariant.serialized.GetLocation().(type) {
+		case *pb.LabelVariant_Columns:
+			entity = labelVariant.serialized.Entity
+		case *pb.LabelVariant_EntityMappings:
+			if len(loc.EntityMappings.Mappings) != 1 {
+				return fferr.NewInvalidArgumentErrorf("additional label %s (%s) must be keyed on exactly one entity", label.Name, label.Variant)
 			}
-		case pb.ComputationMode_CLIENT_COMPUTED:
-			return fferr.NewInvalidArgumentErrorf("feature %s has unsupported computation mode %s", feature.Name, featureVariant.serialized.Mode)
+			entity = loc.EntityMappings.Mappings[0].Name
 		default:
-			return fferr.NewInternalErrorf("feature %s has unknown computation mode %s", feature.Name, featureVariant.serialized.Mode)
+			return fferr.NewInvalidArgumentErrorf("additional label %s (%s) has unsupported location type %T", label.Name, label.Variant, loc)
+		}
+		if _, exists := entityMap[entity]; !exists {
+			return fferr.NewInvalidArgumentErrorf("label %s entity %s does not match any label or spine entity", label.Name, entity)
 		}
 	}
 	return nil
 }
 
+// spineEntities checks that the spine's source exists and returns the entities it maps columns to.
+func (resource *trainingSetVariantResource) spineEntities(ctx context.Context, lookup ResourceLookup, spine *pb.TrainingSetSpine) (map[string]struct{}, error) {
+	resId := ResourceID{Name: spine.GetSource().GetName(), Variant: spine.GetSource().GetVariant(), Type: SOURCE_VARIANT}
+	source, err := lookup.Lookup(ctx, resId)
+	if err != nil {
+		return nil, err
+	}
+	if _, isSourceVariant := source.(*sourceVariantResource); !isSourceVariant {
+		return nil, fferr.NewDatasetNotFoundError(resource.ID().Name, resource.ID().Variant, fmt.Errorf("spine source variant not found"))
+	}
+	mappings := spine.GetEntityMappings().GetMappings()
+	if len(mappings) == 0 {
+		return nil, fferr.NewInvalidArgumentErrorf("training set spine must map at least one entity")
+	}
+	entityMap := make(map[string]struct{})
+	for _, mapping := range mappings {
+		if mapping.Name == "" || mapping.EntityColumn == "" {
+			return nil, fferr.NewInvalidArgumentErrorf("training set spine entity mappings must have a name and column: %v", mapping)
+		}
+		entityMap[mapping.Name] = struct{}{}
+	}
+	return entityMap, nil
+}
+
 func (resource *trainingSetVariantResource) ToDashboardDoc() ResourceDashboardDoc {
 	return ResourceDashboardDoc{
 		Name:    resource.serialized.Name,
@@ -2055,7 +2226,19 @@ func NewMetadataServer(ctx context.Context, config *Config) (*MetadataServer, er
 
 	logger.Infow("Creating new metadata server", "address", config.Address)
 
-	baseLookup := MetadataStorageResourceLookup{config.TaskManager.Storage}
+	conn := config.TaskManager.Storage
+	conn.Audit = newResourceAuditLog(conn)
+	encryptor, err := encryption.NewEncryptorFromEnv()
+	if err != nil {
+		logger.Errorw("Failed to configure provider config encryption", "error", err)
+		return nil, err
+	}
+	if encryptor != nil {
+		codec := newProviderConfigCodec(encryptor)
+		conn.Codec = codec
+		reencryptProviderConfigs(logger, conn, codec)
+	}
+	baseLookup := MetadataStorageResourceLookup{conn}
 
 	resourcesRepo, err := NewResourcesRepositoryFromLookup(&baseLookup)
 	if err != nil {
@@ -2068,6 +2251,7 @@ func NewMetadataServer(ctx context.Context, config *Config) (*MetadataServer, er
 		return nil, fferr.NewInternalErrorf("resources repository is nil")
 	}
 
+	config.TaskManager.SetOwnerResolver(resourceOwnerResolver(&baseLookup))
 	return &MetadataServer{
 		lookup:              &baseLookup,
 		address:             config.Address,
@@ -2078,6 +2262,26 @@ func NewMetadataServer(ctx context.Context, config *Config) (*MetadataServer, er
 	}, nil
 }
 
+// resourceOwnerResolver looks up the owner of a task run's resource so run notifications can be
+// routed by owner. Resources without an owner resolve to an empty string.
+func resourceOwnerResolver(lookup ResourceLookup) notifications.OwnerResolver {
+	return func(ctx context.Context, resourceType, name, variant string) (string, error) {
+		resType, err := ResourceTypeFromString(resourceType)
+		if err != nil {
+			return "", err
+		}
+		res, err := lookup.Lookup(ctx, ResourceID{Name: name, Variant: variant, Type: resType})
+		if err != nil {
+			return "", err
+		}
+		owned, ok := res.(interface{ Owner() string })
+		if !ok {
+			return "", nil
+		}
+		return owned.Owner(), nil
+	}
+}
+
 func (serv *MetadataServer) GetTaskByID(ctx context.Context, taskID *schproto.TaskID) (*schproto.TaskMetadata, error) {
 	_, _, logger := serv.Logger.InitializeRequestID(ctx)
 	tid, err := scheduling.ParseTaskID(taskID.GetId())
@@ -2250,6 +2454,136 @@ func (serv *MetadataServer) AddRunLog(ctx context.Context, log *schproto.Log) (*
 	return &schproto.Empty{}, nil
 }
 
+func (serv *MetadataServer) SetRunWatermarks(ctx context.Context, update *schproto.WatermarksUpdate) (*schproto.Empty, error) {
+	_, _, logger := serv.Logger.InitializeRequestID(ctx)
+	taskID, runID := update.GetTaskID().GetId(), update.GetRunID().GetId()
+	logger = logger.WithValues(map[string]interface{}{
+		"task_id": taskID,
+		"run_id":  runID,
+	})
+	logger.Info("Setting Watermarks")
+	tid, err := scheduling.ParseTaskID(taskID)
+	if err != nil {
+		logger.Errorw("failed to parse task id", "error", err)
+		return nil, err
+	}
+	rid, err := scheduling.ParseTaskRunID(runID)
+	if err != nil {
+		logger.Errorw("failed to parse run id", "error", err)
+		return nil, err
+	}
+	err = serv.taskManager.SetRunWatermarks(rid, tid, scheduling.WatermarksFromProto(update.GetWatermarks()))
+	if err != nil {
+		logger.Errorw("failed to set watermarks", "error", err)
+		return nil, err
+	}
+	return &schproto.Empty{}, nil
+}
+
+func (serv *MetadataServer) SetRunSchemaContract(ctx context.Context, update *schproto.SchemaContractUpdate) (*schproto.Empty, error) {
+	_, _, logger := serv.Logger.InitializeRequestID(ctx)
+	taskID, runID := update.GetTaskID().GetId(), update.GetRunID().GetId()
+	logger = logger.WithValues(map[string]interface{}{
+		"task_id": taskID,
+		"run_id":  runID,
+	})
+	logger.Info("Setting Schema Contract")
+	tid, err := scheduling.ParseTaskID(taskID)
+	if err != nil {
+		logger.Errorw("failed to parse task id", "error", err)
+		return nil, err
+	}
+	rid, err := scheduling.ParseTaskRunID(runID)
+	if err != nil {
+		logger.Errorw("failed to parse run id", "error", err)
+		return nil, err
+	}
+	err = serv.taskManager.SetRunSchemaContract(rid, tid, scheduling.SchemaContractFromProto(update.GetContract()))
+	if err != nil {
+		logger.Errorw("failed to set schema contract", "error", err)
+		return nil, err
+	}
+	return &schproto.Empty{}, nil
+}
+
+func (serv *MetadataServer) SetRunExpectationResults(ctx context.Context, update *schproto.ExpectationResultsUpdate) (*schproto.Empty, error) {
+	_, _, logger := serv.Logger.InitializeRequestID(ctx)
+	taskID, runID := update.GetTaskID().GetId(), update.GetRunID().GetId()
+	logger = logger.WithValues(map[string]interface{}{
+		"task_id": taskID,
+		"run_id":  runID,
+	})
+	logger.Info("Setting Expectation Results")
+	tid, err := scheduling.ParseTaskID(taskID)
+	if err != nil {
+		logger.Errorw("failed to parse task id", "error", err)
+		return nil, err
+	}
+	rid, err := scheduling.ParseTaskRunID(runID)
+	if err != nil {
+		logger.Errorw("failed to parse run id", "error", err)
+		return nil, err
+	}
+	err = serv.taskManager.SetRunExpectationResults(rid, tid, scheduling.ExpectationResultsFromProto(update.GetResults()))
+	if err != nil {
+		logger.Errorw("failed to set expectation results", "error", err)
+		return nil, err
+	}
+	return &schproto.Empty{}, nil
+}
+
+func (serv *MetadataServer) SetRunProfile(ctx context.Context, update *schproto.ProfileUpdate) (*schproto.Empty, error) {
+	_, _, logger := serv.Logger.InitializeRequestID(ctx)
+	taskID, runID := update.GetTaskID().GetId(), update.GetRunID().GetId()
+	logger = logger.WithValues(map[string]interface{}{
+		"task_id": taskID,
+		"run_id":  runID,
+	})
+	logger.Info("Setting Profile")
+	tid, err := scheduling.ParseTaskID(taskID)
+	if err != nil {
+		logger.Errorw("failed to parse task id", "error", err)
+		return nil, err
+	}
+	rid, err := scheduling.ParseTaskRunID(runID)
+	if err != nil {
+		logger.Errorw("failed to parse run id", "error", err)
+		return nil, err
+	}
+	err = serv.taskManager.SetRunProfile(rid, tid, scheduling.DatasetProfileFromProto(update.GetProfile()))
+	if err != nil {
+		logger.Errorw("failed to set profile", "error", err)
+		return nil, err
+	}
+	return &schproto.Empty{}, nil
+}
+
+func (serv *MetadataServer) SetRunSnapshots(ctx context.Context, update *schproto.SnapshotsUpdate) (*schproto.Empty, error) {
+	_, _, logger := serv.Logger.InitializeRequestID(ctx)
+	taskID, runID := update.GetTaskID().GetId(), update.GetRunID().GetId()
+	logger = logger.WithValues(map[string]interface{}{
+		"task_id": taskID,
+		"run_id":  runID,
+	})
+	logger.Info("Setting Snapshots")
+	tid, err := scheduling.ParseTaskID(taskID)
+	if err != nil {
+		logger.Errorw("failed to parse task id", "error", err)
+		return nil, err
+	}
+	rid, err := scheduling.ParseTaskRunID(runID)
+	if err != nil {
+		logger.Errorw("failed to parse run id", "error", err)
+		return nil, err
+	}
+	err = serv.taskManager.SetRunSnapshots(rid, tid, scheduling.DataSnapshotsFromProto(update.GetSnapshots()))
+	if err != nil {
+		logger.Errorw("failed to set snapshots", "error", err)
+		return nil, err
+	}
+	return &schproto.Empty{}, nil
+}
+
 func (serv *MetadataServer) SetRunResumeID(ctx context.Context, update *schproto.ResumeIDUpdate) (*schproto.Empty, error) {
 	_, _, logger := serv.Logger.InitializeRequestID(ctx)
 	taskID, runID, resumeID := update.GetTaskID().GetId(), update.GetRunID().GetId(), update.GetResumeID().GetId()
@@ -2289,7 +2623,7 @@ func (serv *MetadataServer) WatchForCancel(ctx context.Context, id *schproto.Tas
 		logger.Errorw("failed to parse run id", "run id", id.RunID.GetId(), "error", err)
 		return nil, err
 	}
-	err = serv.taskManager.WatchForCancel(tid, rid)
+	err = serv.taskManager.WatchForCancel(ctx, rid, tid)
 	if err != nil {
 		return nil, err
 	}
@@ -2365,7 +2699,12 @@ func (serv *MetadataServer) serveOnListener(lis net.Listener) error {
 		return fferr.NewInternalErrorf("Can't serve metadata server on a NIL port/listerner")
 	}
 	serv.listener = lis
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(interceptors.UnaryServerErrorInterceptor), grpc.StreamInterceptor(interceptors.StreamServerErrorInterceptor))
+	opts, err := serv.serverOptions()
+	if err != nil {
+		serv.Logger.Errorw("Failed to configure server authentication", "error", err)
+		return err
+	}
+	grpcServer := grpc.NewServer(opts...)
 
 	healthServer := grpc_health.NewServer()
 	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
@@ -2378,6 +2717,27 @@ func (serv *MetadataServer) serveOnListener(lis net.Listener) error {
 	return grpcServer.Serve(lis)
 }
 
+func (serv *MetadataServer) serverOptions() ([]grpc.ServerOption, error) {
+	unary := []grpc.UnaryServerInterceptor{interceptors.UnaryServerMetricsInterceptor, interceptors.UnaryServerErrorInterceptor}
+	stream := []grpc.StreamServerInterceptor{interceptors.StreamServerMetricsInterceptor, interceptors.StreamServerErrorInterceptor}
+	authConfig := auth.ConfigFromEnv()
+	opts, err := authConfig.ServerOptions()
+	if err != nil {
+		return nil, err
+	}
+	guard, err := auth.NewGuardFromEnv(serv.Logger, lookupResolver{lookup: serv.lookup})
+	if err != nil {
+		return nil, err
+	}
+	if guard != nil {
+		serv.Logger.Infow("Authentication enabled")
+		unary = append(unary, guard.UnaryServerInterceptor)
+		stream = append(stream, guard.StreamServerInterceptor)
+	}
+	opts = append(opts, interceptors.TracingServerOptions()...)
+	return append(opts, grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...)), nil
+}
+
 func (serv *MetadataServer) GracefulStop() error {
 	if serv.grpcServer == nil {
 		return fferr.NewInternalErrorf("server not running")
@@ -2424,6 +2784,49 @@ func (serv *MetadataServer) RequestScheduleChange(ctx context.Context, req *pb.S
 	return &pb.Empty{}, err
 }
 
+func (serv *MetadataServer) ListAuditLog(ctx context.Context, req *pb.ListAuditLogRequest) (*pb.ListAuditLogResponse, error) {
+	_, _, logger := serv.Logger.InitializeRequestID(ctx)
+	auditLog := auditLogFor(serv.lookup)
+	if auditLog == nil {
+		logger.Errorw("Audit log not supported by resource lookup", "lookup", fmt.Sprintf("%T", serv.lookup))
+		return nil, fferr.NewInternalErrorf("audit log not supported by %T", serv.lookup)
+	}
+	entries, err := auditLog.List(auditFilterFromProto(req))
+	if err != nil {
+		logger.Errorw("Failed to list audit log", "error", err)
+		return nil, err
+	}
+	resp := &pb.ListAuditLogResponse{Entries: make([]*pb.AuditEntry, len(entries))}
+	for i, entry := range entries {
+		resp.Entries[i] = auditEntryProto(entry)
+	}
+	return resp, nil
+}
+
+func (serv *MetadataServer) GetLineage(ctx context.Context, req *pb.LineageRequest) (*pb.LineageGraph, error) {
+	_, ctx, logger := serv.Logger.InitializeRequestID(ctx)
+	root := lineageIDFromProto(req.GetResourceId())
+	logger.Debugw("Building lineage", "resource_id", root.String(), "direction", req.GetDirection(), "depth", req.GetDepth())
+	graph, err := BuildLineage(ctx, serv.lookup, root, req.GetDirection(), int(req.GetDepth()))
+	if err != nil {
+		logger.Errorw("Failed to build lineage", "resource_id", root.String(), "error", err)
+		return nil, err
+	}
+	return graph.Proto(), nil
+}
+
+func (serv *MetadataServer) GetProfile(ctx context.Context, req *pb.ProfileRequest) (*pb.ResourceProfile, error) {
+	_, ctx, logger := serv.Logger.InitializeRequestID(ctx)
+	id := lineageIDFromProto(req.GetResourceId())
+	logger.Debugw("Getting profile", "resource_id", id.String(), "run_id", req.GetRunId())
+	profile, err := GetResourceProfile(ctx, serv.lookup, serv.taskManager, id, req.GetRunId())
+	if err != nil {
+		logger.Errorw("Failed to get profile", "resource_id", id.String(), "error", err)
+		return nil, err
+	}
+	return profile.Proto(), nil
+}
+
 func (serv *MetadataServer) SetResourceStatus(ctx context.Context, req *pb.SetStatusRequest) (*pb.Empty, error) {
 	_, ctx, logger := serv.Logger.InitializeRequestID(ctx)
 	logger.Infow("Setting resource status", "resource_id", req.ResourceId, "status", req.Status.Status)
@@ -3131,7 +3534,8 @@ func (serv *MetadataServer) addTransformationLocation(
 		logger.Debugw("Added filestore location to transformation", "path", lt.Filepath().ToURI())
 		transformation.Location = &pb.Transformation_Filestore{
 			Filestore: &pb.FileStoreTable{
-				Path: lt.Filepath().ToURI(),
+				Path:        lt.Filepath().ToURI(),
+				TableFormat: lt.TableFormat(),
 			},
 		}
 	default:
@@ -3380,6 +3784,16 @@ func (serv *MetadataServer) Run(ctx context.Context, req *pb.RunRequest) (*pb.Em
 	ctx = logging.AttachRequestID(logging.RequestID(req.RequestId), ctx, serv.Logger)
 	baseLogger := logging.GetLoggerFromContext(ctx)
 	baseLogger.Infow("Running resource variants", "num", len(req.Variants))
+	var trigger scheduling.Trigger = scheduling.OnApplyTrigger{TriggerName: "Run"}
+	if backfill := req.GetBackfill(); backfill != nil {
+		start, end := backfill.GetStart().AsTime(), backfill.GetEnd().AsTime()
+		if backfill.GetStart() == nil || backfill.GetEnd() == nil || !start.Before(end) {
+			err := fferr.NewInvalidArgumentErrorf("backfill range must have a start before its end")
+			baseLogger.Errorw("Invalid backfill range", "start", start, "end", end, "error", err)
+			return nil, err
+		}
+		trigger = scheduling.BackfillTrigger{TriggerName: "Backfill", Start: start, End: end}
+	}
 	for _, variant := range req.Variants {
 		resVar, _, err := serv.extractResourceVariant(variant)
 		if err != nil {
@@ -3395,10 +3809,25 @@ func (serv *MetadataServer) Run(ctx context.Context, req *pb.RunRequest) (*pb.Em
 			logger.Errorw("Failed to lookup resource", "error", err)
 			return nil, err
 		}
-		if taskImpl, hasTasks := res.(resourceTaskImplementation); serv.needsRun(ctx, res) && hasTasks {
-			if err := serv.createTaskRuns(ctx, id, taskImpl, logger); err != nil {
+		triggers := []scheduling.Trigger{trigger}
+		if backfill, isBackfill := trigger.(scheduling.BackfillTrigger); isBackfill {
+			storeType, err := serv.sourceStoreType(ctx, res)
+			if err != nil {
+				logger.Errorw("Failed to look up the resource's offline store", "error", err)
+				return nil, err
+			}
+			triggers, err = backfillTriggers(res, storeType, backfill, int(req.GetBackfill().GetPartitionsPerRun()))
+			if err != nil {
+				logger.Errorw("Can't backfill resource", "error", err)
 				return nil, err
 			}
+		}
+		if taskImpl, hasTasks := res.(resourceTaskImplementation); serv.needsRun(ctx, res) && hasTasks {
+			for _, trigger := range triggers {
+				if err := serv.createTaskRuns(ctx, id, taskImpl, trigger, logger); err != nil {
+					return nil, err
+				}
+			}
 		} else {
 			logger.Infow("Resource doesn't need run", "res", res.ID())
 		}
@@ -3406,7 +3835,25 @@ func (serv *MetadataServer) Run(ctx context.Context, req *pb.RunRequest) (*pb.Em
 	return &pb.Empty{}, nil
 }
 
-func (serv *MetadataServer) createTaskRuns(ctx context.Context, id ResourceID, taskImpl resourceTaskImplementation, logger logging.Logger) error {
+// sourceStoreType returns the type of the provider that stores res, or an empty type if res isn't
+// a source.
+func (serv *MetadataServer) sourceStoreType(ctx context.Context, res Resource) (pt.Type, error) {
+	sv, ok := res.(*sourceVariantResource)
+	if !ok {
+		return "", nil
+	}
+	providerResource, err := serv.lookup.Lookup(ctx, ResourceID{Name: sv.serialized.GetProvider(), Type: PROVIDER})
+	if err != nil {
+		return "", err
+	}
+	providerProto, ok := providerResource.Proto().(*pb.Provider)
+	if !ok {
+		return "", fferr.NewInternalErrorf("provider resource is not a provider proto")
+	}
+	return pt.Type(providerProto.GetType()), nil
+}
+
+func (serv *MetadataServer) createTaskRuns(ctx context.Context, id ResourceID, taskImpl resourceTaskImplementation, trigger scheduling.Trigger, logger logging.Logger) error {
 	logger.Infow("Creating TaskRun for resource")
 	taskIDs, err := taskImpl.TaskIDs()
 	if err != nil {
@@ -3414,12 +3861,11 @@ func (serv *MetadataServer) createTaskRuns(ctx context.Context, id ResourceID, t
 		return err
 	}
 	for _, taskId := range taskIDs {
-		trigger := scheduling.OnApplyTrigger{TriggerName: "Run"}
 		taskName := fmt.Sprintf("Create Resource %s (%s)", id.Name, id.Variant)
 		// This creates task runs to be picked up by the coordinator.
 		taskRun, err := serv.taskManager.CreateTaskRun(ctx, taskName, taskId, trigger)
 		if err != nil {
-			logger.Errorw("unable to create task run", "task name", taskName, "task ID", taskId, "trigger", trigger.TriggerName, "error", err)
+			logger.Errorw("unable to create task run", "task name", taskName, "task ID", taskId, "trigger", trigger.Name(), "error", err)
 			return err
 		}
 		logger.Infow("Successfully Created Task", "task ID", taskRun.TaskId, "taskrun ID", taskRun.ID)
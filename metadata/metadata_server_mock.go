@@ -359,3 +359,15 @@ func (m MetadataServerMock) FinalizeDeletion(ctx context.Context, in *pb.Finaliz
 func (m MetadataServerMock) PruneResource(ctx context.Context, in *pb.PruneResourceRequest, opts ...grpc.CallOption) (*pb.PruneResourceResponse, error) {
 	return &pb.PruneResourceResponse{}, nil
 }
+
+func (m MetadataServerMock) ListAuditLog(ctx context.Context, in *pb.ListAuditLogRequest, opts ...grpc.CallOption) (*pb.ListAuditLogResponse, error) {
+	return &pb.ListAuditLogResponse{}, nil
+}
+
+func (m MetadataServerMock) GetProfile(ctx context.Context, in *pb.ProfileRequest, opts ...grpc.CallOption) (*pb.ResourceProfile, error) {
+	return &pb.ResourceProfile{}, nil
+}
+
+func (m MetadataServerMock) GetLineage(ctx context.Context, in *pb.LineageRequest, opts ...grpc.CallOption) (*pb.LineageGraph, error) {
+	return &pb.LineageGraph{}, nil
+}
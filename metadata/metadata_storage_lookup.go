@@ -359,7 +359,7 @@ func (lookup MetadataStorageResourceLookup) Search(ctx context.Context, q string
 	logger := logging.GetLoggerFromContext(ctx)
 	logger.Infow("Searching for resources", "searchquery", q)
 
-	searchResults, err := lookup.Connection.Storage.Search(ctx, q)
+	searchResults, err := lookup.Connection.Search(ctx, q)
 	if err != nil {
 		logger.Errorw("failed to execute search", "query", q, "err", err)
 		return nil, fferr.NewExecutionError("Postgres", fmt.Errorf("failed to execute search: %v", err))
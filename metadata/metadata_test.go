@@ -2888,3 +2888,101 @@ func TestInvalidCommonEntity(t *testing.T) {
 	}
 	defer ctx.Destroy()
 }
+
+func TestBackfillTriggers(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	sqlTransformation := func(partition string, incremental bool) *sourceVariantResource {
+		transformation := &pb.Transformation{
+			Type: &pb.Transformation_SQLTransformation{
+				SQLTransformation: &pb.SQLTransformation{Query: "SELECT * FROM {{ events.v1 }}", IsIncremental: incremental},
+			},
+		}
+		if partition != "" {
+			transformation.Partition = &pb.Transformation_DailyPartition{DailyPartition: &pb.DailyPartition{Column: partition}}
+		}
+		return &sourceVariantResource{serialized: &pb.SourceVariant{
+			Name:       "transactions",
+			Variant:    "v1",
+			Definition: &pb.SourceVariant_Transformation{Transformation: transformation},
+		}}
+	}
+	backfill := scheduling.BackfillTrigger{TriggerName: "Backfill", Start: day(1).Add(6 * time.Hour), End: day(4).Add(time.Hour)}
+	testCases := []struct {
+		name             string
+		res              Resource
+		storeType        pt.Type
+		partitionsPerRun int
+		expected         []scheduling.Trigger
+		isValid          bool
+	}{
+		{
+			name:             "daily partitions",
+			res:              sqlTransformation("date", false),
+			storeType:        pt.SnowflakeOffline,
+			partitionsPerRun: 1,
+			expected: []scheduling.Trigger{
+				scheduling.BackfillTrigger{TriggerName: "Backfill", Start: day(1), End: day(2)},
+				scheduling.BackfillTrigger{TriggerName: "Backfill", Start: day(2), End: day(3)},
+				scheduling.BackfillTrigger{TriggerName: "Backfill", Start: day(3), End: day(4)},
+				scheduling.BackfillTrigger{TriggerName: "Backfill", Start: day(4), End: day(5)},
+			},
+			isValid: true,
+		},
+		{
+			name:             "batched daily partitions",
+			res:              sqlTransformation("date", true),
+			storeType:        pt.SnowflakeOffline,
+			partitionsPerRun: 3,
+			expected: []scheduling.Trigger{
+				scheduling.BackfillTrigger{TriggerName: "Backfill", Start: day(1), End: day(4)},
+				scheduling.BackfillTrigger{TriggerName: "Backfill", Start: day(4), End: day(5)},
+			},
+			isValid: true,
+		},
+		{
+			name:     "incremental",
+			res:      sqlTransformation("", true),
+			expected: []scheduling.Trigger{backfill},
+			isValid:  true,
+		},
+		{
+			name: "feature",
+			res: &featureVariantResource{serialized: &pb.FeatureVariant{
+				Name:    "avg_transactions",
+				Variant: "v1",
+			}},
+			expected: []scheduling.Trigger{backfill},
+			isValid:  true,
+		},
+		{
+			name:    "unpartitioned",
+			res:     sqlTransformation("", false),
+			isValid: false,
+		},
+		{
+			name:      "store can't overwrite partitions",
+			res:       sqlTransformation("date", false),
+			storeType: pt.MySqlOffline,
+			isValid:   false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			triggers, err := backfillTriggers(tc.res, tc.storeType, backfill, tc.partitionsPerRun)
+			if !tc.isValid {
+				if err == nil {
+					t.Fatalf("Expected %s to fail", tc.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to split backfill: %v", err)
+			}
+			if !reflect.DeepEqual(triggers, tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, triggers)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
+	pb "github.com/featureform/metadata/proto"
+	"github.com/featureform/scheduling"
+	tspb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ResourceProfile is the data profile a run of a feature or training set variant recorded.
+type ResourceProfile struct {
+	ID      ResourceID
+	RunID   string
+	RunTime time.Time
+	Profile *fftypes.DatasetProfile
+}
+
+func (p *ResourceProfile) Proto() *pb.ResourceProfile {
+	return &pb.ResourceProfile{
+		ResourceId: p.ID.Proto(),
+		RunId:      p.RunID,
+		RunTime:    tspb.New(p.RunTime),
+		Profile:    scheduling.DatasetProfileToProto(p.Profile),
+	}
+}
+
+func ResourceProfileFromProto(proto *pb.ResourceProfile) *ResourceProfile {
+	return &ResourceProfile{
+		ID:      lineageIDFromProto(proto.GetResourceId()),
+		RunID:   proto.GetRunId(),
+		RunTime: proto.GetRunTime().AsTime(),
+		Profile: scheduling.DatasetProfileFromProto(proto.GetProfile()),
+	}
+}
+
+// GetResourceProfile returns the profile recorded by run runID of a feature or training set
+// variant, or by its latest profiled run if runID is empty.
+func GetResourceProfile(ctx context.Context, lookup ResourceLookup, tasks *scheduling.TaskMetadataManager, id ResourceID, runID string) (*ResourceProfile, error) {
+	if id.Type != FEATURE_VARIANT && id.Type != TRAINING_SET_VARIANT {
+		return nil, fferr.NewInvalidArgumentErrorf("profiles are only recorded for features and training sets, not %s", id.Type)
+	}
+	resource, err := lookup.Lookup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	taskIDs, err := resource.(resourceTaskImplementation).TaskIDs()
+	if err != nil {
+		return nil, err
+	}
+	var runs []scheduling.TaskRunMetadata
+	for _, taskID := range taskIDs {
+		taskRuns, err := tasks.GetTaskRunMetadata(taskID)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, taskRuns...)
+	}
+	run, err := profiledRun(runs, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, fferr.NewDatasetNotFoundError(id.Name, id.Variant, fmt.Errorf("no profile has been recorded"))
+	}
+	return &ResourceProfile{ID: id, RunID: run.ID.String(), RunTime: run.StartTime, Profile: run.Profile}, nil
+}
+
+// profiledRun picks run runID, or the newest run with a profile if runID is empty. It returns nil
+// if there's no such run.
+func profiledRun(runs []scheduling.TaskRunMetadata, runID string) (*scheduling.TaskRunMetadata, error) {
+	var latest *scheduling.TaskRunMetadata
+	for i, run := range runs {
+		if runID != "" {
+			if run.ID.String() != runID {
+				continue
+			}
+			if run.Profile == nil {
+				return nil, fferr.NewInvalidArgumentErrorf("run %s didn't record a profile", runID)
+			}
+			return &runs[i], nil
+		}
+		if run.Profile == nil {
+			continue
+		}
+		if latest == nil || run.StartTime.After(latest.StartTime) {
+			latest = &runs[i]
+		}
+	}
+	return latest, nil
+}
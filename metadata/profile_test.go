@@ -0,0 +1,56 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package metadata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/featureform/ffsync"
+	fftypes "github.com/featureform/fftypes"
+	"github.com/featureform/scheduling"
+)
+
+func TestProfiledRun(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	run := func(id uint64, hoursLater int, rows int64) scheduling.TaskRunMetadata {
+		r := scheduling.TaskRunMetadata{ID: scheduling.TaskRunID(ffsync.Uint64OrderedId(id)), StartTime: start.Add(time.Duration(hoursLater) * time.Hour)}
+		if rows > 0 {
+			r.Profile = &fftypes.DatasetProfile{RowCount: rows}
+		}
+		return r
+	}
+	runs := []scheduling.TaskRunMetadata{run(1, 0, 10), run(2, 2, 20), run(3, 3, 0), run(4, 1, 40)}
+
+	latest, err := profiledRun(runs, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), latest.Profile.RowCount, "the newest run with a profile should be picked")
+
+	byID, err := profiledRun(runs, "4")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(40), byID.Profile.RowCount)
+
+	_, err = profiledRun(runs, "3")
+	assert.Error(t, err, "a run without a profile should be an error")
+
+	missing, err := profiledRun(runs, "5")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	none, err := profiledRun(nil, "")
+	assert.NoError(t, err)
+	assert.Nil(t, none)
+}
+
+func TestGetResourceProfileRejectsOtherTypes(t *testing.T) {
+	_, err := GetResourceProfile(context.Background(), LocalResourceLookup{}, nil, transactionsID, "")
+	assert.Error(t, err)
+}
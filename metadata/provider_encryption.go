@@ -0,0 +1,131 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/featureform/encryption"
+	"github.com/featureform/fferr"
+	"github.com/featureform/logging"
+	pb "github.com/featureform/metadata/proto"
+	"github.com/featureform/storage"
+)
+
+// providerConfigCodec is the storage.ValueCodec that encrypts the serialized config of provider
+// rows. Only the config is encrypted so the rest of the row stays queryable.
+type providerConfigCodec struct {
+	encryptor *encryption.Encryptor
+}
+
+func newProviderConfigCodec(encryptor *encryption.Encryptor) *providerConfigCodec {
+	return &providerConfigCodec{encryptor: encryptor}
+}
+
+func isProviderKey(key string) bool {
+	return strings.HasPrefix(key, PROVIDER.String()+"__")
+}
+
+func (codec *providerConfigCodec) Encode(ctx context.Context, key, value string) (string, error) {
+	return codec.transform(key, value, func(config []byte) ([]byte, error) {
+		if encryption.IsEncrypted(config) {
+			return config, nil
+		}
+		return codec.encryptor.Encrypt(ctx, config)
+	})
+}
+
+func (codec *providerConfigCodec) Decode(ctx context.Context, key, value string) (string, error) {
+	return codec.transform(key, value, func(config []byte) ([]byte, error) {
+		return codec.encryptor.Decrypt(ctx, config)
+	})
+}
+
+// transform applies fn to the serialized config of provider rows and leaves other values alone.
+func (codec *providerConfigCodec) transform(key, value string, fn func([]byte) ([]byte, error)) (string, error) {
+	row, provider, ok, err := parseStoredProvider(key, value)
+	if err != nil || !ok {
+		return value, err
+	}
+	config, err := fn(provider.SerializedConfig)
+	if err != nil {
+		return "", err
+	}
+	provider.SerializedConfig = config
+	message, err := protojson.Marshal(provider)
+	if err != nil {
+		return "", fferr.NewInternalError(err)
+	}
+	row.Message = string(message)
+	serialized, err := json.Marshal(row)
+	if err != nil {
+		return "", fferr.NewInternalError(err)
+	}
+	return string(serialized), nil
+}
+
+// needsRotation reports whether a stored provider row has a config that's plaintext or wrapped
+// with a retired key.
+func (codec *providerConfigCodec) needsRotation(key, value string) bool {
+	_, provider, ok, err := parseStoredProvider(key, value)
+	if err != nil || !ok {
+		return false
+	}
+	return codec.encryptor.NeedsRotation(provider.SerializedConfig)
+}
+
+func parseStoredProvider(key, value string) (StoredRowTemp, *pb.Provider, bool, error) {
+	if !isProviderKey(key) || value == "" {
+		return StoredRowTemp{}, nil, false, nil
+	}
+	var row StoredRowTemp
+	if err := json.Unmarshal([]byte(value), &row); err != nil {
+		return StoredRowTemp{}, nil, false, fferr.NewInternalErrorf("failed to parse provider %s: %w", key, err)
+	}
+	if row.StorageType != RESOURCE {
+		return StoredRowTemp{}, nil, false, nil
+	}
+	provider := &pb.Provider{}
+	if err := protojson.Unmarshal([]byte(row.Message), provider); err != nil {
+		return StoredRowTemp{}, nil, false, fferr.NewInternalErrorf("failed to parse provider %s: %w", key, err)
+	}
+	if len(provider.SerializedConfig) == 0 {
+		return StoredRowTemp{}, nil, false, nil
+	}
+	return row, provider, true, nil
+}
+
+// reencryptProviderConfigs rewrites provider configs that are plaintext or wrapped with a
+// retired key, which encrypts configs stored before encryption was enabled and completes a key
+// rotation. Failures are logged and retried on the next start.
+func reencryptProviderConfigs(logger logging.Logger, conn storage.MetadataStorage, codec *providerConfigCodec) {
+	rows, err := conn.Storage.List(PROVIDER.String())
+	if err != nil {
+		logger.Errorw("Failed to list providers for re-encryption", "error", err)
+		return
+	}
+	rotated := 0
+	for key, value := range rows {
+		if !codec.needsRotation(key, value) {
+			continue
+		}
+		unchanged := func(value string) (string, error) { return value, nil }
+		if err := conn.Update(key, unchanged); err != nil {
+			logger.Errorw("Failed to re-encrypt provider config", "key", key, "error", err)
+			continue
+		}
+		rotated++
+	}
+	if rotated > 0 {
+		logger.Infow("Re-encrypted provider configs", "count", rotated)
+	}
+}
@@ -0,0 +1,97 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package metadata
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/featureform/encryption"
+	"github.com/featureform/logging"
+	pb "github.com/featureform/metadata/proto"
+	"github.com/featureform/scheduling"
+)
+
+func testEncryptor(t *testing.T, primary string, ids ...string) *encryption.Encryptor {
+	keys := make(map[string][]byte)
+	for i, id := range ids {
+		keys[id] = bytes.Repeat([]byte{byte(i + 1)}, 32)
+	}
+	provider, err := encryption.NewLocalKeyProvider(primary, keys)
+	if err != nil {
+		t.Fatalf("Failed to create key provider: %v", err)
+	}
+	return encryption.NewEncryptor(provider)
+}
+
+func TestProviderConfigEncryption(t *testing.T) {
+	ctx, logger := logging.NewTestContextAndLogger(t)
+	manager, err := scheduling.NewMemoryTaskMetadataManager(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create task manager: %v", err)
+	}
+	conn := manager.Storage
+	codec := newProviderConfigCodec(testEncryptor(t, "k1", "k1"))
+	conn.Codec = codec
+	lookup := MetadataStorageResourceLookup{Connection: conn}
+
+	config := []byte(`{"Host": "localhost", "Password": "hunter2"}`)
+	res := &providerResource{serialized: &pb.Provider{Name: "postgres", Type: "POSTGRES_OFFLINE", SerializedConfig: config}}
+	if err := lookup.Set(ctx, res.ID(), res); err != nil {
+		t.Fatalf("Failed to set provider: %v", err)
+	}
+
+	key := createKey(res.ID())
+	raw, err := conn.Storage.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to read raw provider: %v", err)
+	}
+	if strings.Contains(raw, "hunter2") || !strings.Contains(raw, `"name\":\"postgres\"`) {
+		t.Fatalf("Expected only the config to be encrypted, got %s", raw)
+	}
+
+	found, err := lookup.Lookup(ctx, res.ID())
+	if err != nil {
+		t.Fatalf("Failed to lookup provider: %v", err)
+	}
+	if actual := found.Proto().(*pb.Provider).SerializedConfig; !bytes.Equal(config, actual) {
+		t.Fatalf("Expected %s, got %s", config, actual)
+	}
+	listed, err := lookup.ListForType(ctx, PROVIDER)
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("Failed to list providers: %v, %v", listed, err)
+	}
+	if actual := listed[0].Proto().(*pb.Provider).SerializedConfig; !bytes.Equal(config, actual) {
+		t.Fatalf("Expected listed config %s, got %s", config, actual)
+	}
+
+	// Rotating to k2 re-wraps the config on the next start, and k1 can then be removed.
+	rotated := newProviderConfigCodec(testEncryptor(t, "k2", "k1", "k2"))
+	if !rotated.needsRotation(key, raw) {
+		t.Fatalf("Expected config wrapped with k1 to need rotation")
+	}
+	conn.Codec = rotated
+	reencryptProviderConfigs(logger, conn, rotated)
+	raw, err = conn.Storage.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to read raw provider: %v", err)
+	}
+	if rotated.needsRotation(key, raw) {
+		t.Fatalf("Expected config to be wrapped with k2 after re-encryption")
+	}
+	conn.Codec = newProviderConfigCodec(testEncryptor(t, "k2", "k0", "k2"))
+	lookup = MetadataStorageResourceLookup{Connection: conn}
+	found, err = lookup.Lookup(ctx, res.ID())
+	if err != nil {
+		t.Fatalf("Failed to lookup provider after rotation: %v", err)
+	}
+	if actual := found.Proto().(*pb.Provider).SerializedConfig; !bytes.Equal(config, actual) {
+		t.Fatalf("Expected %s after rotation, got %s", config, actual)
+	}
+}
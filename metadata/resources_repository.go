@@ -26,6 +26,7 @@ type ResourcesRepositoryType string
 const (
 	ResourcesRepositoryTypeMemory ResourcesRepositoryType = "memory"
 	ResourcesRepositoryTypePsql   ResourcesRepositoryType = "psql"
+	ResourcesRepositoryTypeSQLite ResourcesRepositoryType = "sqlite"
 )
 
 const (
@@ -80,8 +81,89 @@ const (
 			AND name = $2
 			AND type = $3
 			AND COALESCE(variant, '') = $4;`
+
+	sqlTaskStatus = `
+			WITH task_info AS (
+		-- Step 1: Extract the first task ID from taskIdList
+		SELECT (
+				   SELECT (jsonb_array_elements_text((ff.value::jsonb ->> 'Message')::jsonb -> 'taskIdList'))::INTEGER
+				   LIMIT 1
+			   ) AS task_id
+		FROM ff_task_metadata ff
+		WHERE ff.key = $1
+		LIMIT 1
+	),
+	
+	task_runs AS (
+		-- Step 2: Get all task runs for the extracted task_id
+		SELECT tr.value::jsonb AS runs_data, ti.task_id
+		FROM task_info ti
+		JOIN ff_task_metadata tr
+			 ON tr.key = '/tasks/runs/task_id=' || ti.task_id
+		WHERE ti.task_id IS NOT NULL
+	),
+	
+	latest_run AS (
+		-- Step 3: Get the latest run based on dateCreated
+		SELECT run_obj->>'runID' AS run_id,
+			   run_obj->>'dateCreated' AS date_created,
+			   tr.task_id
+		FROM task_runs tr,
+			 jsonb_array_elements(tr.runs_data -> 'runs') AS run_obj
+		ORDER BY run_obj->>'dateCreated' DESC
+		LIMIT 1
+	)
+	
+	-- Step 4: Get the status directly from the run metadata
+	SELECT (run_metadata.value::jsonb ->> 'status')::INTEGER AS status
+	FROM latest_run lr
+	JOIN ff_task_metadata run_metadata
+		 ON run_metadata.key = '/tasks/runs/metadata/' ||
+							   to_char((lr.date_created)::timestamptz, 'YYYY/MM/DD/HH24/MI') ||
+							   '/task_id=' || lr.task_id ||
+							   '/run_id=' || lr.run_id
+	`
+
+	sqlDirectStatus = `
+		SELECT ((ff.value::jsonb ->> 'Message')::jsonb -> 'status' ->> 'status')
+		FROM ff_task_metadata ff
+		WHERE ff.key = $1
+		LIMIT 1;
+	`
+
+	sqlLookupResource = `-- name: LookupResource :one
+		SELECT value
+		FROM ff_task_metadata
+		WHERE key = $1;`
 )
 
+// resourcesQueries holds the statements sqlResourcesRepository runs, one set per SQL dialect.
+type resourcesQueries struct {
+	countDirectDependencies string
+	markAsDeleted           string
+	deleteFromParent        string
+	deleteFromEdges         string
+	getDependencies         string
+	archive                 string
+	deleteFromSearch        string
+	taskStatus              string
+	directStatus            string
+	lookupResource          string
+}
+
+var postgresResourcesQueries = resourcesQueries{
+	countDirectDependencies: sqlCountDirectDependencies,
+	markAsDeleted:           sqlMarkAsDeleted,
+	deleteFromParent:        deleteFromParent,
+	deleteFromEdges:         sqlDeleteFromEdges,
+	getDependencies:         getDependencies,
+	archive:                 archiveSql,
+	deleteFromSearch:        sqlDeleteFromSearch,
+	taskStatus:              sqlTaskStatus,
+	directStatus:            sqlDirectStatus,
+	lookupResource:          sqlLookupResource,
+}
+
 type AsyncDeletionHandler func(ctx context.Context, resId ResourceID, logger logging.Logger) error
 
 type ResourcesRepository interface {
@@ -125,6 +207,10 @@ func NewResourcesRepositoryFromLookup(resourceLookup ResourceLookup) (ResourcesR
 		case storage.MemoryMetadataStorage:
 			return NewInMemoryResourcesRepository(lookup), nil
 
+		case storage.SQLiteMetadataStorage:
+			liteStorage := lookup.Connection.Storage.(*storage.SQLiteStorageImplementation)
+			return NewSQLiteResourcesRepository(liteStorage.Db, lookup, DefaultResourcesRepoConfig()), nil
+
 		case storage.PSQLMetadataStorage:
 			psqlStorage := lookup.Connection.Storage.(*storage.PSQLStorageImplementation)
 			return NewSqlResourcesRepository(psqlStorage.Db, lookup, DefaultResourcesRepoConfig()), nil
@@ -138,19 +224,112 @@ func NewResourcesRepositoryFromLookup(resourceLookup ResourceLookup) (ResourcesR
 }
 
 type sqlResourcesRepository struct {
-	db     *postgres.Pool
-	config SqlRepositoryConfig
+	db       resourcesDB
+	queries  resourcesQueries
+	repoType ResourcesRepositoryType
+	// deferHandlers runs the async deletion handlers after the transaction commits
+	// instead of inside it, for databases where the handler can't get a connection
+	// while the transaction holds one.
+	deferHandlers bool
+	config        SqlRepositoryConfig
 	ResourceLookup
 }
 
 func NewSqlResourcesRepository(db *postgres.Pool, lookup ResourceLookup, config SqlRepositoryConfig) ResourcesRepository {
 	return &sqlResourcesRepository{
-		db:             db,
+		db:             pgResourcesDB{db},
+		queries:        postgresResourcesQueries,
+		repoType:       ResourcesRepositoryTypePsql,
 		config:         config,
 		ResourceLookup: lookup,
 	}
 }
 
+// resourcesDB starts the transactions sqlResourcesRepository runs its queries in.
+type resourcesDB interface {
+	BeginTx(ctx context.Context) (resourcesTx, error)
+	IsRetryable(err error) bool
+}
+
+// resourcesTx is the part of a transaction the repository uses. Rows that aren't
+// found are reported as sql.ErrNoRows regardless of the driver.
+type resourcesTx interface {
+	Exec(ctx context.Context, query string, args ...any) error
+	QueryRow(ctx context.Context, query string, args ...any) resourcesRow
+	Query(ctx context.Context, query string, args ...any) (resourcesRows, error)
+	Commit(ctx context.Context) error
+	Rollback(ctx context.Context) error
+}
+
+type resourcesRow interface {
+	Scan(dest ...any) error
+}
+
+type resourcesRows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close()
+}
+
+type pgResourcesDB struct {
+	pool *postgres.Pool
+}
+
+func (db pgResourcesDB) BeginTx(ctx context.Context) (resourcesTx, error) {
+	tx, err := db.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return nil, err
+	}
+	return pgResourcesTx{tx}, nil
+}
+
+func (db pgResourcesDB) IsRetryable(err error) bool {
+	var retryablePgErrors = []string{
+		"40001", // Serialization failure (error while trying to run everything in a single transaction)
+	}
+
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && slices.Contains(retryablePgErrors, pgErr.Code)
+}
+
+type pgResourcesTx struct {
+	tx pgx.Tx
+}
+
+func (tx pgResourcesTx) Exec(ctx context.Context, query string, args ...any) error {
+	_, err := tx.tx.Exec(ctx, query, args...)
+	return err
+}
+
+func (tx pgResourcesTx) QueryRow(ctx context.Context, query string, args ...any) resourcesRow {
+	return pgResourcesRow{tx.tx.QueryRow(ctx, query, args...)}
+}
+
+func (tx pgResourcesTx) Query(ctx context.Context, query string, args ...any) (resourcesRows, error) {
+	return tx.tx.Query(ctx, query, args...)
+}
+
+func (tx pgResourcesTx) Commit(ctx context.Context) error {
+	return tx.tx.Commit(ctx)
+}
+
+func (tx pgResourcesTx) Rollback(ctx context.Context) error {
+	return tx.tx.Rollback(ctx)
+}
+
+type pgResourcesRow struct {
+	row pgx.Row
+}
+
+func (row pgResourcesRow) Scan(dest ...any) error {
+	err := row.row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sql.ErrNoRows
+	}
+	return err
+}
+
 // transactionError to combine transaction error and rollback error
 type transactionError struct {
 	txErr       error
@@ -164,11 +343,11 @@ func (e *transactionError) Error() string {
 	return fmt.Sprintf("transaction error: %v", e.txErr)
 }
 
-func (r *sqlResourcesRepository) withTx(ctx context.Context, logger logging.Logger, fn func(pgx.Tx) error) error {
+func (r *sqlResourcesRepository) withTx(ctx context.Context, logger logging.Logger, fn func(resourcesTx) error) error {
 	ctx, cancel := context.WithTimeout(ctx, r.config.TransactionTimeout)
 	defer cancel()
 
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	tx, err := r.db.BeginTx(ctx)
 	if err != nil {
 		logger.Errorw("error starting transaction", "error", err)
 		return err
@@ -193,17 +372,8 @@ func (r *sqlResourcesRepository) withTx(ctx context.Context, logger logging.Logg
 	return nil
 }
 
-func isRetryableError(err error) bool {
-	var retryablePgErrors = []string{
-		"40001", // Serialization failure (error while trying to run everything in a single transaction)
-	}
-
-	var pgErr *pgconn.PgError
-	return errors.As(err, &pgErr) && slices.Contains(retryablePgErrors, pgErr.Code)
-}
-
 func (r *sqlResourcesRepository) Type() ResourcesRepositoryType {
-	return ResourcesRepositoryTypePsql
+	return r.repoType
 }
 
 func (r *sqlResourcesRepository) Archive(ctx context.Context, resourceID common.ResourceID) error {
@@ -212,8 +382,8 @@ func (r *sqlResourcesRepository) Archive(ctx context.Context, resourceID common.
 		With("function", "archive")
 
 	err := r.withRetry(ctx, logger, func() error {
-		return r.withTx(ctx, logger, func(tx pgx.Tx) error {
-			if _, err := tx.Exec(ctx, archiveSql, resourceID.ToKey()); err != nil {
+		return r.withTx(ctx, logger, func(tx resourcesTx) error {
+			if err := tx.Exec(ctx, r.queries.archive, resourceID.ToKey()); err != nil {
 				logger.Errorw("error archiving resource", "error", err)
 				return fferr.NewInternalErrorf("error archiving resource %s: %v", resourceID.ToKey(), err)
 			}
@@ -235,7 +405,7 @@ func (r *sqlResourcesRepository) GetDependencies(ctx context.Context, resourceID
 
 	var dependencies []common.ResourceID
 	err := r.withRetry(ctx, logger, func() error {
-		return r.withTx(ctx, logger, func(tx pgx.Tx) error {
+		return r.withTx(ctx, logger, func(tx resourcesTx) error {
 			var getDepsErr error
 			dependencies, getDepsErr = r.getDependencies(ctx, tx, resourceID, logger)
 			return getDepsErr
@@ -253,8 +423,10 @@ func (r *sqlResourcesRepository) MarkForDeletion(ctx context.Context, resourceID
 		WithResource(resourceID.Type.ToLoggingResourceType(), resourceID.Name, resourceID.Variant).
 		With("function", "MarkForDeletion")
 
-	return r.withRetry(ctx, logger, func() error {
-		return r.withTx(ctx, logger, func(tx pgx.Tx) error {
+	var deferred []ResourceID
+	err := r.withRetry(ctx, logger, func() error {
+		deferred = nil
+		return r.withTx(ctx, logger, func(tx resourcesTx) error {
 			if err := r.checkDependencies(ctx, tx, resourceID, logger); err != nil {
 				logger.Errorw("error checking dependencies", "error", err)
 				return err
@@ -294,15 +466,14 @@ func (r *sqlResourcesRepository) MarkForDeletion(ctx context.Context, resourceID
 			}
 
 			resId := ResourceID{Name: resourceID.Name, Variant: resourceID.Variant, Type: ResourceType(resourceID.Type)}
-			resource, err := r.Lookup(ctx, resId)
+			resource, err := r.lookup(ctx, tx, resId)
 			if err != nil {
 				logger.Errorw("error looking up resource", "error", err)
 				return err
 			}
 
 			if needsJob(resource) {
-				if err := deletionHandler(ctx, resId, logger); err != nil {
-					logger.Errorw("error executing deletion handler", "error", err)
+				if err := r.startDeletion(ctx, deletionHandler, resId, logger, &deferred); err != nil {
 					return err
 				}
 			} else {
@@ -315,6 +486,14 @@ func (r *sqlResourcesRepository) MarkForDeletion(ctx context.Context, resourceID
 			return nil
 		})
 	})
+	if err != nil {
+		return err
+	}
+	if err := r.runDeferredDeletions(ctx, deletionHandler, deferred, logger); err != nil {
+		return err
+	}
+	recordResourceAudit(ctx, logger, r.ResourceLookup, storage.AuditMarkForDeletion, resourceID)
+	return nil
 }
 
 func (r *sqlResourcesRepository) PruneResource(
@@ -327,10 +506,12 @@ func (r *sqlResourcesRepository) PruneResource(
 	logger = logger.WithResource(resourceID.Type.ToLoggingResourceType(), resourceID.Name, resourceID.Variant).
 		With("function", "prune")
 
+	var deferred []ResourceID
 	err := r.withRetry(ctx, logger, func() error {
-		return r.withTx(ctx, logger, func(tx pgx.Tx) error {
+		deferred = nil
+		return r.withTx(ctx, logger, func(tx resourcesTx) error {
 			resId := ResourceID{Name: resourceID.Name, Variant: resourceID.Variant, Type: ResourceType(resourceID.Type)}
-			_, err := r.Lookup(ctx, resId)
+			_, err := r.lookup(ctx, tx, resId)
 			if err != nil {
 				logger.Errorw("error looking up resource", "error", err)
 				return err
@@ -408,15 +589,14 @@ func (r *sqlResourcesRepository) PruneResource(
 			for _, dep := range deps {
 				resId := ResourceID{Name: dep.Name, Variant: dep.Variant, Type: ResourceType(dep.Type)}
 
-				resource, err := r.Lookup(ctx, resId)
+				resource, err := r.lookup(ctx, tx, resId)
 				if err != nil {
 					logger.Errorw("error looking up resource", "error", err)
 					return err
 				}
 
 				if needsJob(resource) {
-					if err := asyncDeletionHandler(ctx, resId, logger); err != nil {
-						logger.Errorw("error executing deletion handler", "error", err)
+					if err := r.startDeletion(ctx, asyncDeletionHandler, resId, logger, &deferred); err != nil {
 						return err
 					}
 				} else {
@@ -435,6 +615,12 @@ func (r *sqlResourcesRepository) PruneResource(
 	if err != nil {
 		return nil, err
 	}
+	if err := r.runDeferredDeletions(ctx, asyncDeletionHandler, deferred, logger); err != nil {
+		return nil, err
+	}
+	for _, deleted := range deletedResources {
+		recordResourceAudit(ctx, logger, r.ResourceLookup, storage.AuditPrune, deleted)
+	}
 	return deletedResources, nil
 }
 
@@ -445,7 +631,7 @@ func (r *sqlResourcesRepository) withRetry(ctx context.Context, logger logging.L
 		retry.Delay(r.config.InitialBackoff), // Initial delay
 		retry.MaxDelay(r.config.MaxDelay),    // Max total time
 		retry.DelayType(retry.BackOffDelay),  // Exponential backoff
-		retry.RetryIf(isRetryableError),      // Only retry on specific errors
+		retry.RetryIf(r.db.IsRetryable),      // Only retry on specific errors
 		retry.OnRetry(func(n uint, err error) {
 			logger.Debugw("retrying after error",
 				"attempt", n+1,
@@ -457,6 +643,52 @@ func (r *sqlResourcesRepository) withRetry(ctx context.Context, logger logging.L
 	)
 }
 
+// startDeletion runs the async deletion handler for a resource inside the transaction, or
+// queues it for runDeferredDeletions when the repository defers handlers.
+func (r *sqlResourcesRepository) startDeletion(ctx context.Context, handler AsyncDeletionHandler, resId ResourceID, logger logging.Logger, deferred *[]ResourceID) error {
+	if r.deferHandlers {
+		*deferred = append(*deferred, resId)
+		return nil
+	}
+	if err := handler(ctx, resId, logger); err != nil {
+		logger.Errorw("error executing deletion handler", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *sqlResourcesRepository) runDeferredDeletions(ctx context.Context, handler AsyncDeletionHandler, resIds []ResourceID, logger logging.Logger) error {
+	for _, resId := range resIds {
+		if err := handler(ctx, resId, logger); err != nil {
+			logger.Errorw("error executing deletion handler", "resource", resId, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup reads a resource through the transaction rather than the ResourceLookup's own
+// connection, which a single connection database would still be holding for the transaction.
+func (r *sqlResourcesRepository) lookup(ctx context.Context, tx resourcesTx, id ResourceID) (Resource, error) {
+	key := createKey(id)
+	var value string
+	if err := tx.QueryRow(ctx, r.queries.lookupResource, key).Scan(&value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fferr.NewKeyNotFoundError(key, err)
+		}
+		return nil, fferr.NewInternalErrorf("error looking up resource %s: %v", key, err)
+	}
+	msg, err := MetadataStorageResourceLookup{}.deserialize([]byte(value))
+	if err != nil {
+		return nil, err
+	}
+	resType, err := CreateEmptyResource(msg.ResourceType)
+	if err != nil {
+		return nil, err
+	}
+	return ParseResource(msg, resType)
+}
+
 func needsJob(res Resource) bool {
 	if res.ID().Type == TRAINING_SET_VARIANT ||
 		res.ID().Type == SOURCE_VARIANT ||
@@ -473,9 +705,9 @@ func needsJob(res Resource) bool {
 	return false
 }
 
-func (r *sqlResourcesRepository) getDependencies(ctx context.Context, tx pgx.Tx, resourceID common.ResourceID, logger logging.Logger) ([]common.ResourceID, error) {
+func (r *sqlResourcesRepository) getDependencies(ctx context.Context, tx resourcesTx, resourceID common.ResourceID, logger logging.Logger) ([]common.ResourceID, error) {
 	var dependencies []common.ResourceID
-	rows, err := tx.Query(ctx, getDependencies, resourceID.Type, resourceID.Name, resourceID.Variant)
+	rows, err := tx.Query(ctx, r.queries.getDependencies, resourceID.Type, resourceID.Name, resourceID.Variant)
 	if err != nil {
 		logger.Errorw("error getting dependencies", "error", err)
 		return nil, err
@@ -510,9 +742,9 @@ func (r *sqlResourcesRepository) getDependencies(ctx context.Context, tx pgx.Tx,
 	return dependencies, nil
 }
 
-func (r *sqlResourcesRepository) checkDependencies(ctx context.Context, tx pgx.Tx, resourceID common.ResourceID, logger logging.Logger) error {
+func (r *sqlResourcesRepository) checkDependencies(ctx context.Context, tx resourcesTx, resourceID common.ResourceID, logger logging.Logger) error {
 	var dependencyCount int
-	if err := tx.QueryRow(ctx, sqlCountDirectDependencies,
+	if err := tx.QueryRow(ctx, r.queries.countDirectDependencies,
 		resourceID.Type, resourceID.Name, resourceID.Variant).Scan(&dependencyCount); err != nil {
 		logger.Errorw("error counting direct dependencies", "error", err)
 		return fferr.NewInternalErrorf("error counting direct dependencies: %v", err)
@@ -527,63 +759,21 @@ func (r *sqlResourcesRepository) checkDependencies(ctx context.Context, tx pgx.T
 	return nil
 }
 
-func (r *sqlResourcesRepository) archive(ctx context.Context, tx pgx.Tx, resourceID common.ResourceID, logger logging.Logger) error {
-	if _, err := tx.Exec(ctx, archiveSql, resourceID.ToKey()); err != nil {
+func (r *sqlResourcesRepository) archive(ctx context.Context, tx resourcesTx, resourceID common.ResourceID, logger logging.Logger) error {
+	if err := tx.Exec(ctx, r.queries.archive, resourceID.ToKey()); err != nil {
 		logger.Errorw("error deleting resource", "error", err)
 		return fferr.NewInternalErrorf("error deleting resource %s: %v", resourceID.ToKey(), err)
 	}
 	return nil
 }
 
-func (r *sqlResourcesRepository) getStatus(ctx context.Context, tx pgx.Tx, resourceID common.ResourceID, logger logging.Logger) (scheduling.Status, error) {
+func (r *sqlResourcesRepository) getStatus(ctx context.Context, tx resourcesTx, resourceID common.ResourceID, logger logging.Logger) (scheduling.Status, error) {
 	// 1. Attempt to get the latest task status first
-	const taskStatusQuery = `
-			WITH task_info AS (
-		-- Step 1: Extract the first task ID from taskIdList
-		SELECT (
-				   SELECT (jsonb_array_elements_text((ff.value::jsonb ->> 'Message')::jsonb -> 'taskIdList'))::INTEGER
-				   LIMIT 1
-			   ) AS task_id
-		FROM ff_task_metadata ff
-		WHERE ff.key = $1
-		LIMIT 1
-	),
-	
-	task_runs AS (
-		-- Step 2: Get all task runs for the extracted task_id
-		SELECT tr.value::jsonb AS runs_data, ti.task_id
-		FROM task_info ti
-		JOIN ff_task_metadata tr
-			 ON tr.key = '/tasks/runs/task_id=' || ti.task_id
-		WHERE ti.task_id IS NOT NULL
-	),
-	
-	latest_run AS (
-		-- Step 3: Get the latest run based on dateCreated
-		SELECT run_obj->>'runID' AS run_id,
-			   run_obj->>'dateCreated' AS date_created,
-			   tr.task_id
-		FROM task_runs tr,
-			 jsonb_array_elements(tr.runs_data -> 'runs') AS run_obj
-		ORDER BY run_obj->>'dateCreated' DESC
-		LIMIT 1
-	)
-	
-	-- Step 4: Get the status directly from the run metadata
-	SELECT (run_metadata.value::jsonb ->> 'status')::INTEGER AS status
-	FROM latest_run lr
-	JOIN ff_task_metadata run_metadata
-		 ON run_metadata.key = '/tasks/runs/metadata/' ||
-							   to_char((lr.date_created)::timestamptz, 'YYYY/MM/DD/HH24/MI') ||
-							   '/task_id=' || lr.task_id ||
-							   '/run_id=' || lr.run_id
-	`
-
 	var taskStatus sql.NullInt32
 
 	// 2. Try to get the task status
-	err := tx.QueryRow(ctx, taskStatusQuery, resourceID.ToKey()).Scan(&taskStatus)
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+	err := tx.QueryRow(ctx, r.queries.taskStatus, resourceID.ToKey()).Scan(&taskStatus)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		logger.Errorw("error getting task status", "error", err)
 		return scheduling.NO_STATUS, fferr.NewInternalErrorf("failed to get task status for resource %s: %v", resourceID.ToKey(), err)
 	}
@@ -594,18 +784,11 @@ func (r *sqlResourcesRepository) getStatus(ctx context.Context, tx pgx.Tx, resou
 	}
 
 	// 4. Fallback: Get direct status from the resource
-	const directStatusQuery = `
-		SELECT ((ff.value::jsonb ->> 'Message')::jsonb -> 'status' ->> 'status')
-		FROM ff_task_metadata ff
-		WHERE ff.key = $1
-		LIMIT 1;
-	`
-
 	var directStatus sql.NullString
 
-	err = tx.QueryRow(ctx, directStatusQuery, resourceID.ToKey()).Scan(&directStatus)
+	err = tx.QueryRow(ctx, r.queries.directStatus, resourceID.ToKey()).Scan(&directStatus)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, sql.ErrNoRows) {
 			return scheduling.NO_STATUS, nil
 		}
 		logger.Errorw("error getting direct status", "error", err)
@@ -619,10 +802,10 @@ func (r *sqlResourcesRepository) getStatus(ctx context.Context, tx pgx.Tx, resou
 	return scheduling.NO_STATUS, nil
 }
 
-func (r *sqlResourcesRepository) markDeleted(ctx context.Context, tx pgx.Tx, resourceID common.ResourceID, logger logging.Logger) error {
+func (r *sqlResourcesRepository) markDeleted(ctx context.Context, tx resourcesTx, resourceID common.ResourceID, logger logging.Logger) error {
 	var deletedKey string
-	if err := tx.QueryRow(ctx, sqlMarkAsDeleted, resourceID.ToKey()).Scan(&deletedKey); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+	if err := tx.QueryRow(ctx, r.queries.markAsDeleted, resourceID.ToKey()).Scan(&deletedKey); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
 			return retry.Unrecoverable(fferr.NewInternalErrorf(
 				"resource with key %s does not exist in ff_task_metadata",
 				resourceID.ToKey(),
@@ -634,8 +817,8 @@ func (r *sqlResourcesRepository) markDeleted(ctx context.Context, tx pgx.Tx, res
 	return nil
 }
 
-func (r *sqlResourcesRepository) deleteEdges(ctx context.Context, tx pgx.Tx, resourceID common.ResourceID, logger logging.Logger) error {
-	if _, err := tx.Exec(ctx, sqlDeleteFromEdges, resourceID.Type, resourceID.Name, resourceID.Variant); err != nil {
+func (r *sqlResourcesRepository) deleteEdges(ctx context.Context, tx resourcesTx, resourceID common.ResourceID, logger logging.Logger) error {
+	if err := tx.Exec(ctx, r.queries.deleteFromEdges, resourceID.Type, resourceID.Name, resourceID.Variant); err != nil {
 		logger.Errorw("error deleting edges", "error", err)
 		return fferr.NewInternalErrorf("error deleting edges for resource %s: %v", resourceID, err)
 	}
@@ -643,10 +826,10 @@ func (r *sqlResourcesRepository) deleteEdges(ctx context.Context, tx pgx.Tx, res
 }
 
 // add a delete from parent
-func (r *sqlResourcesRepository) deleteFromParent(ctx context.Context, tx pgx.Tx, resourceID common.ResourceID, logger logging.Logger) error {
+func (r *sqlResourcesRepository) deleteFromParent(ctx context.Context, tx resourcesTx, resourceID common.ResourceID, logger logging.Logger) error {
 	parent, hasParent := resourceID.Parent()
 	if hasParent {
-		if _, err := tx.Exec(ctx, deleteFromParent, parent.ToKey(), resourceID.Variant); err != nil {
+		if err := tx.Exec(ctx, r.queries.deleteFromParent, parent.ToKey(), resourceID.Variant); err != nil {
 			logger.Errorw("error deleting from parent", "parent", parent, "resource", resourceID, "error", err)
 			return fferr.NewInternalErrorf("could not delete from parent %s: %v", parent, err)
 		}
@@ -654,9 +837,9 @@ func (r *sqlResourcesRepository) deleteFromParent(ctx context.Context, tx pgx.Tx
 	return nil
 }
 
-func (r *sqlResourcesRepository) deleteFromSearch(ctx context.Context, tx pgx.Tx, resID common.ResourceID) error {
+func (r *sqlResourcesRepository) deleteFromSearch(ctx context.Context, tx resourcesTx, resID common.ResourceID) error {
 	logger := logging.GetLoggerFromContext(ctx)
-	if _, err := tx.Exec(ctx, sqlDeleteFromSearch, resID.ToKey(), resID.Name, resID.Type.String(), resID.Variant); err != nil {
+	if err := tx.Exec(ctx, r.queries.deleteFromSearch, resID.ToKey(), resID.Name, resID.Type.String(), resID.Variant); err != nil {
 		logger.Errorw("error deleting from search", "error", err)
 		return fferr.NewInternalErrorf("error deleting from search for key %s: %v", resID.ToKey(), err)
 	}
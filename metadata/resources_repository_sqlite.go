@@ -0,0 +1,216 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package metadata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/featureform/helpers/sqlite"
+)
+
+// The SQLite statements mirror the Postgres ones. The edges table is kept up to date by
+// the trigger in db/sqlite_migrations and get_dependencies is inlined as a recursive CTE.
+const (
+	sqliteCountDirectDependencies = `
+		SELECT COUNT(*)
+		FROM edges
+		WHERE from_resource_proto_type = ?1
+			AND from_resource_name = ?2
+			AND from_resource_variant = ?3;`
+
+	sqliteMarkAsDeleted = `
+		UPDATE ff_task_metadata
+		SET marked_for_deletion_at = CURRENT_TIMESTAMP
+		WHERE key = ?1
+		RETURNING key;`
+
+	// Message is stored as a JSON string, concatenating with '' keeps json_set from
+	// embedding the rebuilt message as an object.
+	sqliteDeleteFromParent = `
+		UPDATE ff_task_metadata
+		SET value = json_set(value, '$.Message', json_object(
+			'name', json_extract(json_extract(value, '$.Message'), '$.name'),
+			'defaultVariant', '',
+			'variants', (
+				SELECT json_group_array(v.value)
+				FROM json_each(json_extract(value, '$.Message'), '$.variants') v
+				WHERE v.value != ?2
+			)
+		) || '')
+		WHERE key = ?1;`
+
+	sqliteDeleteFromEdges = `
+		DELETE FROM edges
+		WHERE to_resource_proto_type = ?1
+			AND to_resource_name = ?2
+			AND to_resource_variant = ?3;`
+
+	sqliteGetDependencies = `
+		WITH RECURSIVE dependency_chain AS (
+			SELECT to_resource_proto_type, to_resource_name, to_resource_variant, 1 AS depth
+			FROM edges
+			WHERE from_resource_proto_type = ?1
+				AND from_resource_name = ?2
+				AND from_resource_variant = ?3
+
+			UNION ALL
+
+			SELECT e.to_resource_proto_type, e.to_resource_name, e.to_resource_variant, dc.depth + 1
+			FROM edges e
+			INNER JOIN dependency_chain dc
+				ON e.from_resource_proto_type = dc.to_resource_proto_type
+				AND e.from_resource_name = dc.to_resource_name
+				AND e.from_resource_variant = dc.to_resource_variant
+			WHERE dc.depth < 500
+		)
+		SELECT DISTINCT to_resource_proto_type, to_resource_name, to_resource_variant
+		FROM dependency_chain;`
+
+	sqliteArchive = `
+		UPDATE ff_task_metadata
+		SET key = 'DELETED__' || key || strftime('%Y%m%dT%H%M%S', 'now')
+		WHERE key = ?1;`
+
+	// The SQLite search index uses the key as the id as is.
+	sqliteDeleteFromSearch = `
+		DELETE FROM search_resources
+		WHERE id = ?1
+			AND name = ?2
+			AND type = ?3
+			AND COALESCE(variant, '') = ?4;`
+
+	sqliteTaskStatus = `
+		WITH task_info AS (
+			SELECT (
+				SELECT t.value
+				FROM json_each(json_extract(ff.value, '$.Message'), '$.taskIdList') t
+				LIMIT 1
+			) AS task_id
+			FROM ff_task_metadata ff
+			WHERE ff.key = ?1
+			LIMIT 1
+		),
+
+		latest_run AS (
+			SELECT json_extract(run.value, '$.runID') AS run_id,
+				json_extract(run.value, '$.dateCreated') AS date_created,
+				ti.task_id
+			FROM task_info ti
+			JOIN ff_task_metadata tr
+				ON tr.key = '/tasks/runs/task_id=' || ti.task_id
+			JOIN json_each(tr.value, '$.runs') run
+			WHERE ti.task_id IS NOT NULL
+			ORDER BY date_created DESC
+			LIMIT 1
+		)
+
+		SELECT json_extract(run_metadata.value, '$.status') AS status
+		FROM latest_run lr
+		JOIN ff_task_metadata run_metadata
+			ON run_metadata.key = '/tasks/runs/metadata/' ||
+				strftime('%Y/%m/%d/%H/%M', lr.date_created) ||
+				'/task_id=' || lr.task_id ||
+				'/run_id=' || lr.run_id`
+
+	sqliteDirectStatus = `
+		SELECT json_extract(json_extract(ff.value, '$.Message'), '$.status.status')
+		FROM ff_task_metadata ff
+		WHERE ff.key = ?1
+		LIMIT 1;`
+
+	sqliteLookupResource = `
+		SELECT value
+		FROM ff_task_metadata
+		WHERE key = ?1;`
+)
+
+var sqliteResourcesQueries = resourcesQueries{
+	countDirectDependencies: sqliteCountDirectDependencies,
+	markAsDeleted:           sqliteMarkAsDeleted,
+	deleteFromParent:        sqliteDeleteFromParent,
+	deleteFromEdges:         sqliteDeleteFromEdges,
+	getDependencies:         sqliteGetDependencies,
+	archive:                 sqliteArchive,
+	deleteFromSearch:        sqliteDeleteFromSearch,
+	taskStatus:              sqliteTaskStatus,
+	directStatus:            sqliteDirectStatus,
+	lookupResource:          sqliteLookupResource,
+}
+
+// NewSQLiteResourcesRepository builds the SQL resources repository on an embedded SQLite
+// database. The database must already have the SQLite migrations applied.
+func NewSQLiteResourcesRepository(db *sqlite.DB, lookup ResourceLookup, config SqlRepositoryConfig) ResourcesRepository {
+	return &sqlResourcesRepository{
+		db:       sqliteResourcesDB{db},
+		queries:  sqliteResourcesQueries,
+		repoType: ResourcesRepositoryTypeSQLite,
+		// The database is limited to a single connection, the deletion handlers create
+		// tasks through storage and would wait on the transaction forever.
+		deferHandlers:  true,
+		config:         config,
+		ResourceLookup: lookup,
+	}
+}
+
+type sqliteResourcesDB struct {
+	db *sqlite.DB
+}
+
+func (db sqliteResourcesDB) BeginTx(ctx context.Context) (resourcesTx, error) {
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return sqliteResourcesTx{tx}, nil
+}
+
+func (db sqliteResourcesDB) IsRetryable(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked)
+}
+
+type sqliteResourcesTx struct {
+	tx *sql.Tx
+}
+
+func (tx sqliteResourcesTx) Exec(ctx context.Context, query string, args ...any) error {
+	_, err := tx.tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (tx sqliteResourcesTx) QueryRow(ctx context.Context, query string, args ...any) resourcesRow {
+	return tx.tx.QueryRowContext(ctx, query, args...)
+}
+
+func (tx sqliteResourcesTx) Query(ctx context.Context, query string, args ...any) (resourcesRows, error) {
+	rows, err := tx.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return sqliteResourcesRows{rows}, nil
+}
+
+func (tx sqliteResourcesTx) Commit(ctx context.Context) error {
+	return tx.tx.Commit()
+}
+
+func (tx sqliteResourcesTx) Rollback(ctx context.Context) error {
+	return tx.tx.Rollback()
+}
+
+type sqliteResourcesRows struct {
+	*sql.Rows
+}
+
+func (rows sqliteResourcesRows) Close() {
+	rows.Rows.Close()
+}
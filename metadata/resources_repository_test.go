@@ -3,18 +3,23 @@ package metadata
 import (
 	"context"
 	"fmt"
+	"net"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/featureform/db"
 	"github.com/featureform/fferr"
 	"github.com/featureform/helpers/postgres"
+	"github.com/featureform/helpers/sqlite"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata/common"
 	pb "github.com/featureform/metadata/proto"
 	pt "github.com/featureform/provider/provider_type"
 	"github.com/featureform/provider/types"
+	"github.com/featureform/scheduling"
 )
 
 type TestResourcesRepository struct {
@@ -82,7 +87,7 @@ func newTestMetadataServer(t *testing.T) *TestMetadataServer {
 	sqlRepo, ok := serv.resourcesRepository.(*sqlResourcesRepository)
 	assert.True(t, ok, "resourcesRepository should be of type *sqlResourcesRepository")
 
-	testRepo := NewTestResourcesRepository(sqlRepo, sqlRepo.db)
+	testRepo := NewTestResourcesRepository(sqlRepo, sqlRepo.db.(pgResourcesDB).pool)
 
 	ctx, logger := logging.NewTestContextAndLogger(t)
 	cli := client(t, ctx, logger, addr)
@@ -587,11 +592,9 @@ func noOpAsyncHandler(ctx context.Context, resId ResourceID, logger logging.Logg
 	return nil
 }
 
-func TestPrune(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping test in short mode.")
-	}
-	resources := []ResourceDef{
+// pruneTestResources is a source DAG with a training set at the bottom.
+func pruneTestResources() []ResourceDef {
+	return []ResourceDef{
 		UserDef{
 			Name:       "Featureform",
 			Tags:       Tags{},
@@ -711,6 +714,13 @@ func TestPrune(t *testing.T) {
 			Properties: Properties{},
 		},
 	}
+}
+
+func TestPrune(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	resources := pruneTestResources()
 
 	ctx := context.Background()
 	testServer := newTestMetadataServer(t)
@@ -817,3 +827,90 @@ func TestPrune(t *testing.T) {
 		require.Nil(t, markedForDeletion)
 	})
 }
+
+func newSQLiteTestMetadataServer(t *testing.T) *TestMetadataServer {
+	t.Helper()
+	ctx, logger := logging.NewTestContextAndLogger(t)
+	liteDB, err := sqlite.NewDB(ctx, sqlite.Config{Path: filepath.Join(t.TempDir(), "featureform.db")})
+	require.NoError(t, err)
+	require.NoError(t, db.RunSQLiteMigrations(ctx, liteDB))
+	manager, err := scheduling.NewSQLiteTaskMetadataManager(ctx, liteDB)
+	require.NoError(t, err)
+
+	serv, err := NewMetadataServer(ctx, &Config{Logger: logger, TaskManager: manager})
+	require.NoError(t, err)
+	lis, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	go serv.ServeOnListener(lis)
+	t.Cleanup(func() {
+		serv.Stop()
+		liteDB.Close()
+	})
+
+	sqlRepo, ok := serv.resourcesRepository.(*sqlResourcesRepository)
+	require.True(t, ok, "resourcesRepository should be of type *sqlResourcesRepository")
+	require.Equal(t, ResourcesRepositoryTypeSQLite, sqlRepo.Type())
+	return &TestMetadataServer{
+		server:                  serv,
+		client:                  client(t, ctx, logger, lis.Addr().String()),
+		TestResourcesRepository: TestResourcesRepository{repo: sqlRepo},
+		t:                       t,
+		ctx:                     ctx,
+		logger:                  logger,
+	}
+}
+
+func TestSQLiteResourcesRepository(t *testing.T) {
+	testServer := newSQLiteTestMetadataServer(t)
+	ctx := testServer.ctx
+	resources := pruneTestResources()
+	testServer.SeedResources(ctx, resources)
+	resourceIDs := make([]ResourceID, 0, len(resources))
+	for _, res := range resources {
+		resourceIDs = append(resourceIDs, res.ResourceID())
+	}
+	testServer.SetResourcesReady(ctx, resourceIDs)
+
+	source := common.ResourceID{Name: "mockSource", Variant: "var", Type: common.SOURCE_VARIANT}
+	feature := common.ResourceID{Name: "feature", Variant: "variant", Type: common.FEATURE_VARIANT}
+	label := common.ResourceID{Name: "label", Variant: "variant", Type: common.LABEL_VARIANT}
+	trainingSet := common.ResourceID{Name: "training-set", Variant: "variant", Type: common.TRAINING_SET_VARIANT}
+
+	deps, err := testServer.repo.GetDependencies(ctx, source)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []common.ResourceID{feature, label, trainingSet}, deps)
+
+	err = testServer.repo.MarkForDeletion(ctx, source, noOpAsyncHandler)
+	require.Error(t, err, "a source with dependencies can't be deleted")
+
+	// The handlers run against the same single connection database, they'd block if
+	// they were called while the transaction still held it.
+	var handled []ResourceID
+	handler := func(ctx context.Context, resId ResourceID, logger logging.Logger) error {
+		if _, err := testServer.server.lookup.Lookup(ctx, resId, DeleteLookupOption{DeletedOnly}); err != nil {
+			return err
+		}
+		handled = append(handled, resId)
+		return nil
+	}
+	deleted, err := testServer.repo.PruneResource(ctx, trainingSet, handler)
+	require.NoError(t, err)
+	require.Equal(t, []common.ResourceID{trainingSet}, deleted)
+	require.Equal(t, []ResourceID{{Name: "training-set", Variant: "variant", Type: TRAINING_SET_VARIANT}}, handled)
+	_, err = testServer.repo.Lookup(ctx, ResourceID{Name: "training-set", Variant: "variant", Type: TRAINING_SET_VARIANT})
+	require.Error(t, err)
+
+	require.NoError(t, testServer.repo.MarkForDeletion(ctx, label, handler))
+	res, err := testServer.repo.Lookup(ctx, ResourceID{Name: "label", Type: LABEL})
+	require.NoError(t, err)
+	require.Empty(t, res.(*labelResource).serialized.Variants, "the deleted variant should be removed from its parent")
+	results, err := testServer.repo.Search(ctx, "label")
+	require.NoError(t, err)
+	for _, result := range results {
+		require.NotEqual(t, LABEL_VARIANT, result.ID().Type, "the deleted variant should be removed from search")
+	}
+
+	deps, err = testServer.repo.GetDependencies(ctx, source)
+	require.NoError(t, err)
+	require.Equal(t, []common.ResourceID{feature}, deps)
+}
@@ -14,9 +14,12 @@ import (
 	"github.com/featureform/config"
 	"github.com/featureform/config/bootstrap"
 	"github.com/featureform/db"
+	"github.com/featureform/health"
 	"github.com/featureform/helpers"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
+	"github.com/featureform/metrics"
+	"github.com/featureform/tracing"
 )
 
 func main() {
@@ -24,6 +27,18 @@ func main() {
 
 	logger := logging.NewLogger("metadata")
 	defer logger.Sync()
+	shutdownTracing, err := tracing.Init(context.Background(), "metadata")
+	if err != nil {
+		logger.Panicw("Failed to configure tracing", "Err", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Errorw("Failed to flush traces", "Err", err)
+		}
+	}()
+	if err := metrics.EnableInstrumentationFromEnv(); err != nil {
+		logger.Panicw("Failed to register metrics", "Err", err)
+	}
 	logger.Info("Parsing Featureform App Config")
 	appConfig, err := config.Get(logger)
 	if err != nil {
@@ -62,6 +77,17 @@ func main() {
 		panic(err.Error())
 	}
 
+	statusPort := helpers.GetEnv("API_STATUS_PORT", "8443")
+	checks := []health.Check{
+		health.MetadataStorageCheck(manager.Storage),
+		health.LockerCheck(manager.Storage.Locker),
+		health.TaskManagerCheck(manager),
+	}
+	if err := health.StartHttpServer(logger, statusPort, checks...); err != nil {
+		logger.Errorw("Failed to start health check", "err", err)
+		panic(err)
+	}
+
 	config := &metadata.Config{
 		Logger:      logger,
 		Address:     fmt.Sprintf(":%s", addr),
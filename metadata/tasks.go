@@ -10,8 +10,10 @@ package metadata
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/featureform/ffsync"
+	fftypes "github.com/featureform/fftypes"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata/proto"
 	ptypes "github.com/featureform/provider/types"
@@ -28,7 +30,7 @@ type TaskService interface {
 	CreateRun(name string, id s.TaskID, trigger s.Trigger) (s.TaskRunID, error)
 	SyncUnfinishedRuns() error
 	GetTaskByID(id s.TaskID) (s.TaskMetadata, error)
-	WatchForCancel(tid s.TaskID, id s.TaskRunID) (chan s.Status, chan error)
+	WatchForCancel(ctx context.Context, tid s.TaskID, id s.TaskRunID) (chan s.Status, chan error)
 	GetAllRuns() (s.TaskRunList, error)
 	GetUnfinishedRuns() (s.TaskRunList, error)
 	GetRuns(tid s.TaskID) (s.TaskRunList, error)
@@ -36,6 +38,11 @@ type TaskService interface {
 	GetLatestRun(id s.TaskID) (s.TaskRunMetadata, error)
 	SetRunStatus(tid s.TaskID, runID s.TaskRunID, status s.Status, errMsg error) error
 	SetRunResumeID(tid s.TaskID, runID s.TaskRunID, resumeID ptypes.ResumeID) error
+	SetRunWatermarks(tid s.TaskID, runID s.TaskRunID, watermarks map[string]time.Time) error
+	SetRunSchemaContract(tid s.TaskID, runID s.TaskRunID, contract *s.SchemaContract) error
+	SetRunExpectationResults(tid s.TaskID, runID s.TaskRunID, results []s.ExpectationResult) error
+	SetRunProfile(tid s.TaskID, runID s.TaskRunID, profile *fftypes.DatasetProfile) error
+	SetRunSnapshots(tid s.TaskID, runID s.TaskRunID, snapshots []fftypes.DataSnapshot) error
 	AddRunLog(taskID s.TaskID, runID s.TaskRunID, msg string) error
 	EndRun(tid s.TaskID, runID s.TaskRunID) error
 	SetRunSchedulerID(ctx context.Context, tid s.TaskID, runID s.TaskRunID, schedulerID string, runIteration string) error
@@ -87,20 +94,22 @@ func (t *Tasks) GetTaskByID(id s.TaskID) (s.TaskMetadata, error) {
 	return metadata, nil
 }
 
-func (t *Tasks) WatchForCancel(tid s.TaskID, rid s.TaskRunID) (chan s.Status, chan error) {
+// WatchForCancel sends the run's status once it's cancelled. Watching stops when ctx is done.
+func (t *Tasks) WatchForCancel(ctx context.Context, tid s.TaskID, rid s.TaskRunID) (chan s.Status, chan error) {
 	t.logger.Debugw("Watching for cancel", "task_id", tid.String(), "run_id", rid.String())
 	statusChannel := make(chan s.Status, 1)
 	waitErr := make(chan error, 1)
 	go func() {
 		t.logger.Debugw("Starting cancel watch request", "task_id", tid.String(), "run_id", rid.String())
 		status, err := t.GrpcConn.WatchForCancel(
-			context.Background(),
+			ctx,
 			&schproto.TaskRunID{
 				RunID:  &schproto.RunID{Id: rid.String()},
 				TaskID: &schproto.TaskID{Id: tid.String()},
 			})
 		if err != nil {
 			waitErr <- err
+			return
 		}
 		statusChannel <- s.Status(status.Status)
 	}()
@@ -255,6 +264,102 @@ func (t *Tasks) SetRunResumeID(tid s.TaskID, runID s.TaskRunID, resumeID ptypes.
 	return nil
 }
 
+func (t *Tasks) SetRunWatermarks(tid s.TaskID, runID s.TaskRunID, watermarks map[string]time.Time) error {
+	logger := t.logger.WithValues(map[string]any{
+		"task_id":    tid.String(),
+		"run_id":     runID.String(),
+		"watermarks": watermarks,
+	})
+	logger.Debugw("Setting watermarks")
+	update := &schproto.WatermarksUpdate{
+		RunID:      &schproto.RunID{Id: runID.String()},
+		TaskID:     &schproto.TaskID{Id: tid.String()},
+		Watermarks: s.WatermarksToProto(watermarks),
+	}
+	_, err := t.GrpcConn.SetRunWatermarks(context.Background(), update)
+	if err != nil {
+		logger.Errorw("Failed to set watermarks", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (t *Tasks) SetRunSchemaContract(tid s.TaskID, runID s.TaskRunID, contract *s.SchemaContract) error {
+	logger := t.logger.WithValues(map[string]any{
+		"task_id": tid.String(),
+		"run_id":  runID.String(),
+	})
+	logger.Debugw("Setting schema contract", "contract", contract)
+	update := &schproto.SchemaContractUpdate{
+		RunID:    &schproto.RunID{Id: runID.String()},
+		TaskID:   &schproto.TaskID{Id: tid.String()},
+		Contract: contract.ToProto(),
+	}
+	_, err := t.GrpcConn.SetRunSchemaContract(context.Background(), update)
+	if err != nil {
+		logger.Errorw("Failed to set schema contract", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (t *Tasks) SetRunExpectationResults(tid s.TaskID, runID s.TaskRunID, results []s.ExpectationResult) error {
+	logger := t.logger.WithValues(map[string]any{
+		"task_id": tid.String(),
+		"run_id":  runID.String(),
+	})
+	logger.Debugw("Setting expectation results", "results", results)
+	update := &schproto.ExpectationResultsUpdate{
+		RunID:   &schproto.RunID{Id: runID.String()},
+		TaskID:  &schproto.TaskID{Id: tid.String()},
+		Results: s.ExpectationResultsToProto(results),
+	}
+	_, err := t.GrpcConn.SetRunExpectationResults(context.Background(), update)
+	if err != nil {
+		logger.Errorw("Failed to set expectation results", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (t *Tasks) SetRunProfile(tid s.TaskID, runID s.TaskRunID, profile *fftypes.DatasetProfile) error {
+	logger := t.logger.WithValues(map[string]any{
+		"task_id": tid.String(),
+		"run_id":  runID.String(),
+	})
+	logger.Debugw("Setting profile", "columns", len(profile.Columns))
+	update := &schproto.ProfileUpdate{
+		RunID:   &schproto.RunID{Id: runID.String()},
+		TaskID:  &schproto.TaskID{Id: tid.String()},
+		Profile: s.DatasetProfileToProto(profile),
+	}
+	_, err := t.GrpcConn.SetRunProfile(context.Background(), update)
+	if err != nil {
+		logger.Errorw("Failed to set profile", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (t *Tasks) SetRunSnapshots(tid s.TaskID, runID s.TaskRunID, snapshots []fftypes.DataSnapshot) error {
+	logger := t.logger.WithValues(map[string]any{
+		"task_id": tid.String(),
+		"run_id":  runID.String(),
+	})
+	logger.Debugw("Setting snapshots", "snapshots", len(snapshots))
+	update := &schproto.SnapshotsUpdate{
+		RunID:     &schproto.RunID{Id: runID.String()},
+		TaskID:    &schproto.TaskID{Id: tid.String()},
+		Snapshots: s.DataSnapshotsToProto(snapshots),
+	}
+	_, err := t.GrpcConn.SetRunSnapshots(context.Background(), update)
+	if err != nil {
+		logger.Errorw("Failed to set snapshots", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (t *Tasks) AddRunLog(tid s.TaskID, runID s.TaskRunID, msg string) error {
 	t.logger.Debugw("Adding run log", "task_id", tid.String(), "run_id", runID.String(), "msg", msg)
 	log := &schproto.Log{RunID: &schproto.RunID{Id: runID.String()}, TaskID: &schproto.TaskID{Id: tid.String()}, Log: msg}
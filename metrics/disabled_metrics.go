@@ -7,6 +7,8 @@
 
 package metrics
 
+import "time"
+
 type NoOpMetricsHandler struct{}
 
 func (nop *NoOpMetricsHandler) BeginObservingOnlineServe(feature string, key string) FeatureObserver {
@@ -23,3 +25,13 @@ type NoOpFeatureObserver struct{}
 func (nop *NoOpFeatureObserver) SetError() {}
 func (nop *NoOpFeatureObserver) ServeRow() {}
 func (nop *NoOpFeatureObserver) Finish()   {}
+
+// NoOpInstrumentation is the Instrumentation used when operational metrics are disabled.
+type NoOpInstrumentation struct{}
+
+func (nop *NoOpInstrumentation) ObserveTaskRun(string, string, time.Duration)                  {}
+func (nop *NoOpInstrumentation) SetUnfinishedRuns(int)                                         {}
+func (nop *NoOpInstrumentation) ObserveLockWait(string, time.Duration, error)                  {}
+func (nop *NoOpInstrumentation) ObserveMaterializedChunk(string, int, time.Duration)           {}
+func (nop *NoOpInstrumentation) ObserveProviderOperation(string, string, time.Duration, error) {}
+func (nop *NoOpInstrumentation) ObserveRPC(string, string, time.Duration)                      {}
@@ -0,0 +1,211 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/featureform/helpers"
+)
+
+// InstrumentationEnabledEnv turns the operational metrics on or off. They're on by default in
+// the services that call EnableInstrumentationFromEnv and a no-op everywhere else.
+const InstrumentationEnabledEnv = "FEATUREFORM_METRICS_ENABLED"
+
+const (
+	statusSuccess = "success"
+	statusError   = "error"
+)
+
+// Instrumentation records operational metrics for the scheduler, task runs, locks,
+// materializations, provider calls and gRPC calls.
+type Instrumentation interface {
+	ObserveTaskRun(taskType, status string, duration time.Duration)
+	SetUnfinishedRuns(count int)
+	ObserveLockWait(locker string, duration time.Duration, err error)
+	ObserveMaterializedChunk(providerType string, rows int, duration time.Duration)
+	ObserveProviderOperation(providerType, operation string, duration time.Duration, err error)
+	ObserveRPC(method, code string, duration time.Duration)
+}
+
+// PromInstrumentation is the Instrumentation backed by a Prometheus registry.
+type PromInstrumentation struct {
+	taskRuns                  *prometheus.CounterVec
+	taskRunDuration           *prometheus.HistogramVec
+	unfinishedRuns            prometheus.Gauge
+	lockWait                  *prometheus.HistogramVec
+	materializedRows          *prometheus.CounterVec
+	materializationThroughput *prometheus.HistogramVec
+	providerOperationDuration *prometheus.HistogramVec
+	providerOperationErrors   *prometheus.CounterVec
+	rpcDuration               *prometheus.HistogramVec
+}
+
+// NewPromInstrumentation creates the metrics and registers them with registerer.
+func NewPromInstrumentation(registerer prometheus.Registerer) (*PromInstrumentation, error) {
+	instr := &PromInstrumentation{
+		taskRuns: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "featureform_task_runs_total",
+				Help: "Completed task runs, labeled by task type and final status",
+			},
+			[]string{"type", "status"},
+		),
+		taskRunDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "featureform_task_run_duration_seconds",
+				Help:    "Duration of task runs, labeled by task type and final status",
+				Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+			},
+			[]string{"type", "status"},
+		),
+		unfinishedRuns: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "featureform_unfinished_task_runs",
+				Help: "Task runs waiting to be picked up or still running, as seen by the scheduler's last poll",
+			},
+		),
+		lockWait: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "featureform_lock_wait_seconds",
+				Help:    "Time spent acquiring locks, labeled by locker and whether the lock was acquired",
+				Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+			},
+			[]string{"locker", "status"},
+		),
+		materializedRows: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "featureform_materialized_rows_total",
+				Help: "Rows written to online stores by materializations, labeled by provider type",
+			},
+			[]string{"provider"},
+		),
+		materializationThroughput: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "featureform_materialization_rows_per_second",
+				Help:    "Write throughput of materialization chunks, labeled by provider type",
+				Buckets: prometheus.ExponentialBuckets(10, 4, 10),
+			},
+			[]string{"provider"},
+		),
+		providerOperationDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "featureform_provider_operation_duration_seconds",
+				Help:    "Latency of provider operations, labeled by provider type, operation and status",
+				Buckets: prometheus.ExponentialBuckets(0.001, 4, 12),
+			},
+			[]string{"provider", "operation", "status"},
+		),
+		providerOperationErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "featureform_provider_operation_errors_total",
+				Help: "Failed provider operations, labeled by provider type and operation",
+			},
+			[]string{"provider", "operation"},
+		),
+		rpcDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "featureform_grpc_server_duration_seconds",
+				Help:    "Latency of gRPC calls handled by the service, labeled by method and status code",
+				Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+			},
+			[]string{"method", "code"},
+		),
+	}
+	collectors := []prometheus.Collector{
+		instr.taskRuns,
+		instr.taskRunDuration,
+		instr.unfinishedRuns,
+		instr.lockWait,
+		instr.materializedRows,
+		instr.materializationThroughput,
+		instr.providerOperationDuration,
+		instr.providerOperationErrors,
+		instr.rpcDuration,
+	}
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return instr, nil
+}
+
+func errStatus(err error) string {
+	if err != nil {
+		return statusError
+	}
+	return statusSuccess
+}
+
+func (p *PromInstrumentation) ObserveTaskRun(taskType, status string, duration time.Duration) {
+	p.taskRuns.WithLabelValues(taskType, status).Inc()
+	p.taskRunDuration.WithLabelValues(taskType, status).Observe(duration.Seconds())
+}
+
+func (p *PromInstrumentation) SetUnfinishedRuns(count int) {
+	p.unfinishedRuns.Set(float64(count))
+}
+
+func (p *PromInstrumentation) ObserveLockWait(locker string, duration time.Duration, err error) {
+	p.lockWait.WithLabelValues(locker, errStatus(err)).Observe(duration.Seconds())
+}
+
+func (p *PromInstrumentation) ObserveMaterializedChunk(providerType string, rows int, duration time.Duration) {
+	p.materializedRows.WithLabelValues(providerType).Add(float64(rows))
+	if seconds := duration.Seconds(); seconds > 0 && rows > 0 {
+		p.materializationThroughput.WithLabelValues(providerType).Observe(float64(rows) / seconds)
+	}
+}
+
+func (p *PromInstrumentation) ObserveProviderOperation(providerType, operation string, duration time.Duration, err error) {
+	p.providerOperationDuration.WithLabelValues(providerType, operation, errStatus(err)).Observe(duration.Seconds())
+	if err != nil {
+		p.providerOperationErrors.WithLabelValues(providerType, operation).Inc()
+	}
+}
+
+func (p *PromInstrumentation) ObserveRPC(method, code string, duration time.Duration) {
+	p.rpcDuration.WithLabelValues(method, code).Observe(duration.Seconds())
+}
+
+var (
+	instrumentationMtx sync.RWMutex
+	instrumentation    Instrumentation = &NoOpInstrumentation{}
+)
+
+// Instrument returns the process-wide Instrumentation, which is a no-op until it's enabled.
+func Instrument() Instrumentation {
+	instrumentationMtx.RLock()
+	defer instrumentationMtx.RUnlock()
+	return instrumentation
+}
+
+// SetInstrumentation replaces the process-wide Instrumentation.
+func SetInstrumentation(instr Instrumentation) {
+	instrumentationMtx.Lock()
+	defer instrumentationMtx.Unlock()
+	instrumentation = instr
+}
+
+// EnableInstrumentationFromEnv registers the operational metrics with the default Prometheus
+// registry, which the health server exposes on /metrics, unless they're disabled.
+func EnableInstrumentationFromEnv() error {
+	if !helpers.GetEnvBool(InstrumentationEnabledEnv, true) {
+		return nil
+	}
+	instr, err := NewPromInstrumentation(prometheus.DefaultRegisterer)
+	if err != nil {
+		return err
+	}
+	SetInstrumentation(instr)
+	return nil
+}
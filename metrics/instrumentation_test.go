@@ -0,0 +1,105 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	prometheus "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromInstrumentation(t *testing.T) {
+	instr, err := NewPromInstrumentation(prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("Failed to create instrumentation: %v", err)
+	}
+
+	instr.ObserveTaskRun("FEATURE_VARIANT", "READY", time.Second)
+	instr.ObserveTaskRun("FEATURE_VARIANT", "READY", time.Second)
+	instr.ObserveTaskRun("FEATURE_VARIANT", "FAILED", time.Second)
+	instr.SetUnfinishedRuns(7)
+	instr.ObserveLockWait("memory", time.Millisecond, nil)
+	instr.ObserveMaterializedChunk("REDIS_ONLINE", 100, time.Second)
+	instr.ObserveMaterializedChunk("REDIS_ONLINE", 50, 0)
+	instr.ObserveProviderOperation("REDIS_ONLINE", "Set", time.Millisecond, nil)
+	instr.ObserveProviderOperation("REDIS_ONLINE", "Set", time.Millisecond, errors.New("failed"))
+	instr.ObserveRPC("/metadata.Metadata/ListFeatures", "OK", time.Millisecond)
+
+	readyRuns, err := GetCounterValue(instr.taskRuns, "FEATURE_VARIANT", "READY")
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, readyRuns)
+	failedRuns, err := GetHistogramValue(instr.taskRunDuration, "FEATURE_VARIANT", "FAILED")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), failedRuns)
+
+	gauge := &dto.Metric{}
+	assert.NoError(t, instr.unfinishedRuns.Write(gauge))
+	assert.Equal(t, 7.0, gauge.GetGauge().GetValue())
+
+	lockWaits, err := GetHistogramValue(instr.lockWait, "memory", statusSuccess)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), lockWaits)
+
+	rows, err := GetCounterValue(instr.materializedRows, "REDIS_ONLINE")
+	assert.NoError(t, err)
+	assert.Equal(t, 150.0, rows)
+	// Chunks without a measurable duration don't contribute to throughput.
+	throughput, err := GetHistogramValue(instr.materializationThroughput, "REDIS_ONLINE")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), throughput)
+
+	succeeded, err := GetHistogramValue(instr.providerOperationDuration, "REDIS_ONLINE", "Set", statusSuccess)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), succeeded)
+	failed, err := GetCounterValue(instr.providerOperationErrors, "REDIS_ONLINE", "Set")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, failed)
+
+	rpcs, err := GetHistogramValue(instr.rpcDuration, "/metadata.Metadata/ListFeatures", "OK")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), rpcs)
+}
+
+func TestPromInstrumentationDuplicateRegistration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := NewPromInstrumentation(registry); err != nil {
+		t.Fatalf("Failed to create instrumentation: %v", err)
+	}
+	if _, err := NewPromInstrumentation(registry); err == nil {
+		t.Fatalf("Expected registering the metrics twice to fail")
+	}
+}
+
+func TestInstrumentationDefaultsToNoOp(t *testing.T) {
+	if _, ok := Instrument().(*NoOpInstrumentation); !ok {
+		t.Fatalf("Expected no-op instrumentation by default, got %T", Instrument())
+	}
+	// The no-op must be safe to call from any code path.
+	Instrument().ObserveTaskRun("FEATURE_VARIANT", "READY", time.Second)
+	Instrument().ObserveProviderOperation("REDIS_ONLINE", "Get", time.Millisecond, errors.New("failed"))
+
+	instr, err := NewPromInstrumentation(prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("Failed to create instrumentation: %v", err)
+	}
+	SetInstrumentation(instr)
+	defer SetInstrumentation(&NoOpInstrumentation{})
+	assert.Equal(t, instr, Instrument())
+}
+
+func TestEnableInstrumentationFromEnvDisabled(t *testing.T) {
+	t.Setenv(InstrumentationEnabledEnv, "false")
+	assert.NoError(t, EnableInstrumentationFromEnv())
+	if _, ok := Instrument().(*NoOpInstrumentation); !ok {
+		t.Fatalf("Expected no-op instrumentation when disabled, got %T", Instrument())
+	}
+}
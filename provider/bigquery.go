@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
 	_ "github.com/lib/pq"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
@@ -80,6 +81,15 @@ func (pt *bqPrimaryTable) GetLocation() pl.Location {
 	return pl.NewSQLLocationFromParts(pt.table.ProjectID, pt.table.DatasetID, pt.table.TableID)
 }
 
+func (pt *bqPrimaryTable) readSchema() (fftypes.Schema, error) {
+	converter, err := p_type.GetConverter(p_type.BigQueryOffline)
+	if err != nil {
+		return fftypes.Schema{}, err
+	}
+	location := pl.NewSQLLocationFromParts(pt.table.ProjectID, pt.table.DatasetID, pt.table.TableID)
+	return pt.query.getSchema(pt.client, converter, *location)
+}
+
 func (pt *bqPrimaryTable) IterateSegment(n int64) (GenericTableIterator, error) {
 	tableName := pt.query.getTableName(pt.name)
 	var query string
@@ -508,6 +518,12 @@ func (q defaultBQQueries) transformationCreate(location pl.SQLLocation, query st
 	return qry
 }
 
+// incrementalTransformationCreate creates a table, rather than a view, since incremental runs
+// merge into the rows that are already there.
+func (q defaultBQQueries) incrementalTransformationCreate(location pl.SQLLocation, query string) string {
+	return fmt.Sprintf("CREATE TABLE `%s` AS %s", q.getTableNameFromLocation(location), query)
+}
+
 func (q defaultBQQueries) getColumns(client *bigquery.Client, name string) ([]TableColumn, error) {
 	qry := fmt.Sprintf("SELECT column_name FROM `%s.INFORMATION_SCHEMA.COLUMNS` WHERE table_name=\"%s\" ORDER BY ordinal_position", q.getTablePrefix(), name)
 
@@ -617,6 +633,54 @@ func (q defaultBQQueries) transformationUpdate(client *bigquery.Client, tableNam
 	return nil
 }
 
+func (q defaultBQQueries) transformationIncrementalUpdate(client *bigquery.Client, tableName string, query string, config IncrementalConfig) error {
+	var columns []TableColumn
+	if len(config.MergeKeys) > 0 {
+		var err error
+		if columns, err = q.getColumns(client, tableName); err != nil {
+			return err
+		}
+	}
+	bdQ := client.Query(q.incrementalUpdateScript(tableName, query, columns, config))
+	job, err := bdQ.Run(q.getContext())
+	if err != nil {
+		q.logger.Errorw("Failed to update incremental transformation", "table", tableName, "err", err)
+		wrapped := fferr.NewExecutionError(p_type.BigQueryOffline.String(), err)
+		wrapped.AddDetail("table_name", tableName)
+		return wrapped
+	}
+	return q.monitorJob(job)
+}
+
+func (q defaultBQQueries) incrementalUpdateScript(tableName string, query string, columns []TableColumn, config IncrementalConfig) string {
+	bqTableName := q.getTableName(tableName)
+	statements := make([]string, 0)
+	if config.IsBackfill() {
+		ts := config.TimestampColumn
+		statements = append(statements, fmt.Sprintf(
+			"DELETE FROM `%s` WHERE `%s` >= %s AND `%s` < %s",
+			bqTableName, ts, incrementalTimestamp(config.BackfillStart), ts, incrementalTimestamp(config.BackfillEnd),
+		))
+	}
+	if len(config.MergeKeys) == 0 {
+		statements = append(statements, fmt.Sprintf("INSERT INTO `%s` %s", bqTableName, query))
+	} else {
+		on := make([]string, len(config.MergeKeys))
+		for i, key := range config.MergeKeys {
+			on[i] = fmt.Sprintf("target.`%s` = incremental.`%s`", key, key)
+		}
+		updates := make([]string, len(columns))
+		for i, column := range columns {
+			updates[i] = fmt.Sprintf("`%s` = incremental.`%s`", column.Name, column.Name)
+		}
+		statements = append(statements, fmt.Sprintf(
+			"MERGE `%s` target USING ( %s ) incremental ON %s WHEN MATCHED THEN UPDATE SET %s WHEN NOT MATCHED THEN INSERT ROW",
+			bqTableName, query, strings.Join(on, " AND "), strings.Join(updates, ", "),
+		))
+	}
+	return strings.Join(statements, ";") + ";"
+}
+
 func (q defaultBQQueries) atomicUpdate(client *bigquery.Client, tableName string, tempName string, query string) error {
 	bqTableName := q.getTableName(tableName)
 	bqTempTableName := q.getTableName(tempName)
@@ -702,6 +766,11 @@ func (q defaultBQQueries) trainingRowSelect(columns string, trainingSetName stri
 	return fmt.Sprintf("SELECT %s FROM `%s`", columns, q.getTableName(trainingSetName))
 }
 
+// snapshotTable reads a table as of a time with BigQuery's time travel.
+func (q defaultBQQueries) snapshotTable(tableName string, at time.Time) string {
+	return fmt.Sprintf("`%s` FOR SYSTEM_TIME AS OF TIMESTAMP_MILLIS(%d)", strings.Trim(tableName, "`"), at.UnixMilli())
+}
+
 func (q defaultBQQueries) getTableName(tableName string) string {
 	location := pl.FullyQualifiedObject{
 		Database: q.ProjectId,
@@ -1067,6 +1136,9 @@ func (store *bqOfflineStore) CreateTransformation(config TransformationConfig, o
 
 	location := pl.NewSQLLocation(name)
 	query := store.query.transformationCreate(*location, config.Query)
+	if config.Incremental != nil {
+		query = store.query.incrementalTransformationCreate(*location, config.Query)
+	}
 
 	bqQ := store.client.Query(query)
 	job, err := bqQ.Run(store.query.getContext())
@@ -1130,7 +1202,14 @@ func (store *bqOfflineStore) UpdateTransformation(config TransformationConfig, o
 		logger.Errorw("Error getting table name", "error", err)
 		return err
 	}
-	err = store.query.transformationUpdate(store.client, name, config.Query)
+	if config.Incremental != nil {
+		if err := config.Incremental.check(); err != nil {
+			return err
+		}
+		err = store.query.transformationIncrementalUpdate(store.client, name, config.Query, *config.Incremental)
+	} else {
+		err = store.query.transformationUpdate(store.client, name, config.Query)
+	}
 	if err != nil {
 		logger.Errorw("Error updating transformation", "error", err)
 		return err
@@ -1141,6 +1220,35 @@ func (store *bqOfflineStore) UpdateTransformation(config TransformationConfig, o
 	return nil
 }
 
+// SourceWatermark satisfies SourceWatermarker.
+func (store *bqOfflineStore) SourceWatermark(table, timestampColumn string) (time.Time, error) {
+	it, err := store.client.Query(fmt.Sprintf("SELECT MAX(`%s`) FROM %s", timestampColumn, table)).Read(store.query.getContext())
+	if err != nil {
+		wrapped := fferr.NewExecutionError(store.Type().String(), err)
+		wrapped.AddDetail("table_name", table)
+		return time.Time{}, wrapped
+	}
+	var row []bigquery.Value
+	if err := it.Next(&row); err != nil {
+		wrapped := fferr.NewExecutionError(store.Type().String(), err)
+		wrapped.AddDetail("table_name", table)
+		return time.Time{}, wrapped
+	}
+	if len(row) == 0 || row[0] == nil {
+		return time.Time{}, nil
+	}
+	switch watermark := row[0].(type) {
+	case time.Time:
+		return watermark.UTC(), nil
+	case civil.DateTime:
+		return watermark.In(time.UTC), nil
+	case civil.Date:
+		return watermark.In(time.UTC), nil
+	default:
+		return time.Time{}, fferr.NewDataTypeNotFoundErrorf(watermark, "source watermark of %s must be a timestamp", table)
+	}
+}
+
 func (store *bqOfflineStore) CreatePrimaryTable(id ResourceID, schema TableSchema) (dataset.Dataset, error) {
 	logger := store.logger.With("resourceId", id)
 
@@ -1624,6 +1732,10 @@ func (store *bqOfflineStore) UpdateTrainingSet(def TrainingSetDef) error {
 		logger.Errorw("Error validating training set", "error", err)
 		return err
 	}
+	if def.Spine != nil {
+		// Training sets are created with CREATE OR REPLACE, so recreating one updates it.
+		return store.CreateTrainingSet(def)
+	}
 	label, err := store.getbqResourceTable(def.Label)
 	if err != nil {
 		logger.Errorw("Error getting table", "error", err)
@@ -1645,6 +1757,12 @@ func (store *bqOfflineStore) UpdateTrainingSet(def TrainingSetDef) error {
 }
 
 func (store *bqOfflineStore) GetTrainingSet(id ResourceID) (dataset.TrainingSetIterator, error) {
+	return store.getTrainingSet(id, store.query.trainingRowSelect)
+}
+
+// getTrainingSet serves a training set's rows using selectQuery, which selects the columns of the
+// training set's table.
+func (store *bqOfflineStore) getTrainingSet(id ResourceID, selectQuery func(columns string, trainingSetName string) string) (dataset.TrainingSetIterator, error) {
 	logger := store.logger.With("resourceId", id)
 
 	logger.Debug("Getting training set")
@@ -1675,7 +1793,7 @@ func (store *bqOfflineStore) GetTrainingSet(id ResourceID) (dataset.TrainingSetI
 		features = append(features, name.Name)
 	}
 	columns := strings.Join(features[:], ", ")
-	trainingSetQry := store.query.trainingRowSelect(columns, trainingSetName)
+	trainingSetQry := selectQuery(columns, trainingSetName)
 
 	bqQ := store.client.Query(trainingSetQry)
 	iter, err := bqQ.Read(store.query.getContext())
@@ -1696,7 +1814,24 @@ func (store *bqOfflineStore) GetTrainTestSplit(def TrainTestSplitDef) (dataset.T
 }
 
 func (store *bqOfflineStore) CheckHealth() (bool, error) {
-	return false, fferr.NewInternalError(fmt.Errorf("provider health check not implemented"))
+	return store.CheckHealthContext(context.Background())
+}
+
+func (store *bqOfflineStore) CheckHealthContext(ctx context.Context) (bool, error) {
+	job, err := store.client.Query("SELECT 1").Run(ctx)
+	if err == nil {
+		var status *bigquery.JobStatus
+		status, err = job.Wait(ctx)
+		if err == nil {
+			err = status.Err()
+		}
+	}
+	if err != nil {
+		wrapped := fferr.NewConnectionError(pt.BigQueryOffline.String(), err)
+		wrapped.AddDetail("action", "query")
+		return false, wrapped
+	}
+	return true, nil
 }
 
 func (store *bqOfflineStore) ResourceLocation(id ResourceID, resource any) (pl.Location, error) {
@@ -1761,7 +1896,14 @@ func (it *bqTrainingRowsIterator) Next() bool {
 	}
 
 	var label interface{}
-	numFeatures := len(it.iter.Schema) - 1
+	columnNames := make([]string, len(it.iter.Schema))
+	for i, field := range it.iter.Schema {
+		columnNames[i] = field.Name
+	}
+	numFeatures := len(columnNames)
+	if trainingSetHasLabel(columnNames) {
+		numFeatures--
+	}
 	featureVals := make([]interface{}, numFeatures)
 	for i, value := range rowValues {
 		if value == nil {
@@ -1919,5 +2061,91 @@ func (bq *bqOfflineStore) adaptTsDefToBuilderParams(def TrainingSetDef) (tsq.Bui
 		return bq.query.getTableNameFromLocation(*lblLoc), nil
 	}
 
-	return def.ToBuilderParams(bq.logger, sanitizeTableNameFn)
+	params, err := def.ToBuilderParams(bq.logger, sanitizeTableNameFn)
+	if err != nil {
+		return tsq.BuilderParams{}, err
+	}
+	def.setSnapshotTables(&params, func(table string, snapshot fftypes.DataSnapshot) string {
+		return fmt.Sprintf("(SELECT * FROM %s)", bq.query.snapshotTable(table, snapshot.Timestamp))
+	})
+	return params, nil
+}
+
+// bqMaxTimeTravel is the longest time travel window a BigQuery dataset can have.
+const bqMaxTimeTravel = 7 * 24 * time.Hour
+
+// PinTrainingSetInputs pins the tables a training set reads as of the current time, which the
+// training set's query reads with FOR SYSTEM_TIME AS OF. BigQuery keeps at most 7 days of time
+// travel, set per dataset, so the policy's retention can't be longer.
+func (bq *bqOfflineStore) PinTrainingSetInputs(def TrainingSetDef, policy SnapshotPolicy) ([]fftypes.DataSnapshot, error) {
+	if policy.Retention > bqMaxTimeTravel {
+		return nil, fferr.NewInvalidArgumentErrorf("BigQuery keeps at most %s of time travel, but the snapshot retention is %s", bqMaxTimeTravel, policy.Retention)
+	}
+	at, err := bq.currentTimestamp()
+	if err != nil {
+		return nil, err
+	}
+	inputs := def.inputs()
+	snapshots := make([]fftypes.DataSnapshot, 0, len(inputs))
+	for _, input := range inputs {
+		snapshots = append(snapshots, fftypes.DataSnapshot{
+			Resource:  input.resource,
+			Location:  snapshotLocation(input.location),
+			Kind:      fftypes.SnapshotDecorator,
+			Timestamp: at,
+		})
+	}
+	return snapshots, nil
+}
+
+func (bq *bqOfflineStore) PinTrainingSet(def TrainingSetDef, policy SnapshotPolicy) (fftypes.DataSnapshot, error) {
+	tableName, err := bq.getTrainingSetName(def.ID)
+	if err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	at, err := bq.currentTimestamp()
+	if err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	return fftypes.DataSnapshot{
+		Resource:  snapshotResourceName(def.ID),
+		Location:  bq.query.getTableName(tableName),
+		Kind:      fftypes.SnapshotDecorator,
+		Timestamp: at,
+		Output:    true,
+	}, nil
+}
+
+func (bq *bqOfflineStore) GetTrainingSetSnapshot(id ResourceID, snapshot fftypes.DataSnapshot) (dataset.TrainingSetIterator, error) {
+	if err := checkSnapshotNotReleased(id, snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Kind != fftypes.SnapshotDecorator {
+		return nil, fferr.NewInvalidArgumentErrorf("BigQuery can't serve %s snapshots", snapshot.Kind)
+	}
+	return bq.getTrainingSet(id, func(columns string, trainingSetName string) string {
+		return fmt.Sprintf("SELECT %s FROM %s", columns, bq.query.snapshotTable(bq.query.getTableName(trainingSetName), snapshot.Timestamp))
+	})
+}
+
+// ReleaseSnapshots doesn't need to do anything, since BigQuery drops old table versions once
+// they're past the dataset's time travel window.
+func (bq *bqOfflineStore) ReleaseSnapshots(snapshots []fftypes.DataSnapshot) error {
+	return nil
+}
+
+func (bq *bqOfflineStore) currentTimestamp() (time.Time, error) {
+	it, err := bq.client.Query("SELECT CURRENT_TIMESTAMP()").Read(bq.query.getContext())
+	if err != nil {
+		return time.Time{}, fferr.NewExecutionError(bq.Type().String(), err)
+	}
+	var row []bigquery.Value
+	if err := it.Next(&row); err != nil {
+		return time.Time{}, fferr.NewExecutionError(bq.Type().String(), err)
+	}
+	at, ok := row[0].(time.Time)
+	if !ok {
+		return time.Time{}, fferr.NewInternalErrorf("BigQuery returned %T for CURRENT_TIMESTAMP()", row[0])
+	}
+	return at.UTC(), nil
 }
@@ -202,7 +202,16 @@ func (store *cassandraOnlineStore) DeleteTable(feature, variant string) error {
 }
 
 func (store *cassandraOnlineStore) CheckHealth() (bool, error) {
-	return false, fferr.NewInternalError(fmt.Errorf("not implemented"))
+	return store.CheckHealthContext(context.Background())
+}
+
+func (store *cassandraOnlineStore) CheckHealthContext(ctx context.Context) (bool, error) {
+	if err := store.session.Query("SELECT release_version FROM system.local").WithContext(ctx).Exec(); err != nil {
+		wrapped := fferr.NewConnectionError(pt.CassandraOnline.String(), err)
+		wrapped.AddDetail("action", "query system.local")
+		return false, wrapped
+	}
+	return true, nil
 }
 
 func (store cassandraOnlineStore) Delete(location pl.Location) error {
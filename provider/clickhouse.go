@@ -8,6 +8,7 @@
 package provider
 
 import (
+	"context"
 	"crypto/tls"
 	"database/sql"
 	"fmt"
@@ -509,6 +510,14 @@ func (table *clickhousePrimaryTable) GetLocation() pl.Location {
 	return clickhouse.NewLocationFromTableName(table.name)
 }
 
+func (table *clickhousePrimaryTable) readSchema() (fftypes.Schema, error) {
+	converter, err := pt.GetConverter(pt.ClickHouseOffline)
+	if err != nil {
+		return fftypes.Schema{}, err
+	}
+	return table.query.getSchema(table.db, converter, *clickhouse.NewLocationFromTableName(table.name).SQLLocation)
+}
+
 func (table *clickhousePrimaryTable) IterateSegment(n int64) (GenericTableIterator, error) {
 	columns, err := table.query.getColumns(table.db, table.name)
 	if err != nil {
@@ -735,7 +744,11 @@ func (store *clickHouseOfflineStore) AsOfflineStore() (OfflineStore, error) {
 }
 
 func (store *clickHouseOfflineStore) CheckHealth() (bool, error) {
-	err := store.db.Ping()
+	return store.CheckHealthContext(context.Background())
+}
+
+func (store *clickHouseOfflineStore) CheckHealthContext(ctx context.Context) (bool, error) {
+	err := store.db.PingContext(ctx)
 	if err != nil {
 		wrapped := fferr.NewConnectionError(pt.ClickHouseOffline.String(), err)
 		wrapped.AddDetail("action", "ping")
@@ -845,6 +858,12 @@ func (store *clickHouseOfflineStore) UpdateTransformation(config TransformationC
 	if err != nil {
 		return err
 	}
+	if config.Incremental != nil {
+		if err := config.Incremental.check(); err != nil {
+			return err
+		}
+		return store.query.transformationIncrementalUpdate(store.db, name, config.Query, *config.Incremental)
+	}
 	err = store.query.transformationUpdate(store.db, name, config.Query)
 	if err != nil {
 		return err
@@ -852,6 +871,12 @@ func (store *clickHouseOfflineStore) UpdateTransformation(config TransformationC
 	return nil
 }
 
+// SourceWatermark satisfies SourceWatermarker.
+func (store *clickHouseOfflineStore) SourceWatermark(table, timestampColumn string) (time.Time, error) {
+	query := fmt.Sprintf("SELECT MAX(%s) FROM %s", SanitizeClickHouseIdentifier(timestampColumn), table)
+	return scanSourceWatermark(store.db.QueryRow(query), pt.ClickHouseOffline.String(), table)
+}
+
 func (store *clickHouseOfflineStore) CreatePrimaryTable(id ResourceID, schema TableSchema) (dataset.Dataset, error) {
 	if err := id.check(Primary); err != nil {
 		return nil, err
@@ -1206,6 +1231,10 @@ func (store *clickHouseOfflineStore) UpdateTrainingSet(def TrainingSetDef) error
 	if err := def.check(); err != nil {
 		return err
 	}
+	if def.Spine != nil {
+		// Training sets are created with CREATE OR REPLACE, so recreating one updates it.
+		return store.CreateTrainingSet(def)
+	}
 	label, err := store.getsqlResourceTable(def.Label)
 	if err != nil {
 		return err
@@ -1276,6 +1305,12 @@ func (store *clickHouseOfflineStore) GetTrainingSet(id ResourceID) (dataset.Trai
 }
 
 func (store *clickHouseOfflineStore) CreateTrainTestSplit(def TrainTestSplitDef) (func() error, error) {
+	if err := def.check(); err != nil {
+		return nil, err
+	}
+	if def.Type != RandomSplit || def.Folds > 1 {
+		return nil, fferr.NewInvalidArgumentErrorf("%s only supports random train test splits", store.Type())
+	}
 	prep, err := store.prepareTrainingSetQuery(ResourceID{Name: def.TrainingSetName, Variant: def.TrainingSetVariant})
 	if err != nil {
 		return nil, err
@@ -1334,6 +1369,9 @@ func (store *clickHouseOfflineStore) getTrainTestSplitTableName(trainingSetTable
 	// Generate unique suffix for the view names
 	tableNameSuffix := fmt.Sprintf("%s_%d_%t_%d", trainingSetTable, int(def.TestSize*100), def.Shuffle, def.RandomState)
 	trainTestSplitViewName := fmt.Sprintf("%s_split", tableNameSuffix)
+	if def.RequestID != "" {
+		trainTestSplitViewName = fmt.Sprintf("%s_%s", trainTestSplitViewName, strings.ReplaceAll(def.RequestID, "-", ""))
+	}
 	return trainTestSplitViewName
 }
 
@@ -1790,6 +1828,47 @@ func (q clickhouseSQLQueries) transformationUpdate(db *sql.DB, tableName string,
 	return nil
 }
 
+// transformationIncrementalUpdate deletes with synchronous mutations, since ClickHouse has no
+// transactions or MERGE. The new rows are staged in a temp table so the query only runs once.
+func (q clickhouseSQLQueries) transformationIncrementalUpdate(db *sql.DB, tableName string, query string, config IncrementalConfig) error {
+	for _, statement := range q.incrementalUpdateStatements(tableName, query, config) {
+		if _, err := db.Exec(statement); err != nil {
+			wrapped := fferr.NewExecutionError(pt.ClickHouseOffline.String(), err)
+			wrapped.AddDetail("table_name", tableName)
+			return wrapped
+		}
+	}
+	return nil
+}
+
+func (q clickhouseSQLQueries) incrementalUpdateStatements(tableName string, query string, config IncrementalConfig) []string {
+	table := SanitizeClickHouseIdentifier(tableName)
+	statements := make([]string, 0)
+	if config.IsBackfill() {
+		ts := SanitizeClickHouseIdentifier(config.TimestampColumn)
+		statements = append(statements, fmt.Sprintf(
+			"ALTER TABLE %s DELETE WHERE %s >= %s AND %s < %s SETTINGS mutations_sync = 2",
+			table, ts, incrementalTimestamp(config.BackfillStart), ts, incrementalTimestamp(config.BackfillEnd),
+		))
+	}
+	if len(config.MergeKeys) == 0 {
+		return append(statements, fmt.Sprintf("INSERT INTO %s %s", table, query))
+	}
+	tempName := SanitizeClickHouseIdentifier(fmt.Sprintf("tmp_%s", tableName))
+	keys := make([]string, len(config.MergeKeys))
+	for i, key := range config.MergeKeys {
+		keys[i] = SanitizeClickHouseIdentifier(key)
+	}
+	keyStr := strings.Join(keys, ", ")
+	return append(statements,
+		fmt.Sprintf("CREATE TABLE %s ENGINE = MergeTree ORDER BY tuple() EMPTY AS %s", tempName, query),
+		fmt.Sprintf("INSERT INTO %s %s", tempName, query),
+		fmt.Sprintf("ALTER TABLE %s DELETE WHERE (%s) IN (SELECT %s FROM %s) SETTINGS mutations_sync = 2", table, keyStr, keyStr, tempName),
+		fmt.Sprintf("INSERT INTO %s SELECT * FROM %s", table, tempName),
+		fmt.Sprintf("DROP TABLE %s", tempName),
+	)
+}
+
 func (q clickhouseSQLQueries) transformationExists() string {
 	return q.tableExists()
 }
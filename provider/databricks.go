@@ -10,6 +10,7 @@ package provider
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -24,13 +25,13 @@ import (
 	pc "github.com/featureform/provider/provider_config"
 	pt "github.com/featureform/provider/provider_type"
 	"github.com/featureform/provider/spark"
+	"github.com/featureform/provider/types"
 
 	re "github.com/avast/retry-go/v4"
 	"github.com/databricks/databricks-sdk-go"
 	"github.com/databricks/databricks-sdk-go/apierr"
 	dbClient "github.com/databricks/databricks-sdk-go/client"
 	dbConfig "github.com/databricks/databricks-sdk-go/config"
-	"github.com/databricks/databricks-sdk-go/retries"
 	"github.com/databricks/databricks-sdk-go/service/compute"
 	dbfs "github.com/databricks/databricks-sdk-go/service/files"
 	"github.com/databricks/databricks-sdk-go/service/jobs"
@@ -119,49 +120,115 @@ type DatabricksExecutor struct {
 }
 
 func (db *DatabricksExecutor) SupportsTransformationOption(opt TransformationOptionType) (bool, error) {
+	if opt == ResumableTransformation {
+		return true, nil
+	}
 	return false, nil
 }
 
 func (db *DatabricksExecutor) RunSparkJob(cmd *spark.Command, store SparkFileStoreV2, opts SparkJobOptions, tfopts TransformationOptions) error {
 	safeScript, safeArgs := cmd.Redacted().CompileScriptOnly()
+	logger := db.logger.With("script", safeScript, "args", safeArgs, "store", store.Type(), "job_name", opts.JobName, "cluster_id", db.cluster)
+	resumeOpt, hasResumeOpt := tfopts.GetResumeOption(logger)
+	logger = logger.With("resume_opt_set", hasResumeOpt)
+
+	runID, err := db.runOrResumeJob(cmd, store, opts, resumeOpt, logger)
+	if err != nil {
+		return err
+	}
+	logger = logger.With("run_id", runID.RunID, "job_id", runID.JobID)
+
+	if !hasResumeOpt {
+		return db.waitForRun(runID, store, opts.MaxJobDuration, logger)
+	}
+	if !resumeOpt.IsResumeIDSet() {
+		resumeID, err := runID.Marshal()
+		if err != nil {
+			return err
+		}
+		if err := resumeOpt.setResumeID(resumeID); err != nil {
+			return err
+		}
+	}
+	go func() {
+		// Finish ResumeOption after the run finishes.
+		var runErr error = fferr.NewInternalErrorf("Waiter panicked")
+		defer func() {
+			if err := resumeOpt.finishWithError(runErr); err != nil {
+				logger.Errorw("Unable to set error in resume option", "error", err)
+			}
+		}()
+		runErr = db.waitForRun(runID, store, opts.MaxJobDuration, logger)
+		logger.Debugw("Resume option finished", "run_err", runErr)
+	}()
+	return nil
+}
+
+// runOrResumeJob either re-attaches to the run in resumeOpt or creates and
+// starts a new job, returning the IDs needed to wait for it.
+func (db *DatabricksExecutor) runOrResumeJob(cmd *spark.Command, store SparkFileStoreV2, opts SparkJobOptions, resumeOpt *ResumeOption, logger logging.Logger) (*databricksResumeID, error) {
+	if resumeOpt != nil && resumeOpt.IsResumeIDSet() {
+		runID, err := deserializeDatabricksResumeID(resumeOpt.ResumeID())
+		if err != nil {
+			logger.Errorw("Failed to deserialize resume ID", "error", err)
+			return nil, err
+		}
+		logger.Infow("Resuming Transformation on Databricks", "run_id", runID.RunID, "job_id", runID.JobID)
+		return runID, nil
+	}
+
 	ctx := context.Background()
 	id := uuid.New().String()
+	jobName := fmt.Sprintf("%s-%s", opts.JobName, id)
 	task := cmd.CompileDatabricks()
-	logger := db.logger.With("script", safeScript, "args", safeArgs, "store", store.Type(), "job_name", opts.JobName, "cluster_id", db.cluster, "id", id)
+	logger = logger.With("id", id)
 	task.TaskKey = fmt.Sprintf("featureform-task-%s", id)
 	logger.Info("Running Spark job")
 	task.ExistingClusterId = db.cluster
 	jobToRun, err := db.client.Jobs.Create(ctx, jobs.CreateJob{
-		Name:  fmt.Sprintf("%s-%s", opts.JobName, id),
+		Name:  jobName,
 		Tasks: []jobs.Task{task},
 	})
 	if err != nil {
 		logger.Errorw("could not create job", "error", err)
 		wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), err)
-		wrapped.AddDetails("job_name", fmt.Sprintf("%s-%s", opts.JobName, id), "job_id", fmt.Sprint(jobToRun.JobId), "executor_type", "Databricks", "store_type", store.Type())
+		wrapped.AddDetails("job_name", jobName, "executor_type", "Databricks", "store_type", store.Type())
 		wrapped.AddFixSuggestion("Check the cluster logs for more information")
-		return wrapped
+		return nil, wrapped
 	}
-
-	weekTimeout := retries.Timeout[jobs.Run](opts.MaxJobDuration)
-	_, err = db.client.Jobs.RunNowAndWait(ctx, jobs.RunNow{
+	run, err := db.client.Jobs.RunNow(ctx, jobs.RunNow{
 		JobId: jobToRun.JobId,
-	}, weekTimeout)
+	})
+	if err != nil {
+		logger.Errorw("could not start job", "error", err)
+		wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), err)
+		wrapped.AddDetails("job_name", jobName, "job_id", fmt.Sprint(jobToRun.JobId), "executor_type", "Databricks", "store_type", store.Type())
+		wrapped.AddFixSuggestion("Check the cluster logs for more information")
+		return nil, wrapped
+	}
+	return &databricksResumeID{JobID: jobToRun.JobId, RunID: run.RunId}, nil
+}
+
+func (db *DatabricksExecutor) waitForRun(runID *databricksResumeID, store SparkFileStoreV2, maxWait time.Duration, logger logging.Logger) error {
+	logger.Infow("Waiting for Databricks run to complete", "wait_duration", maxWait.String())
+	run, err := db.client.Jobs.WaitGetRunJobTerminatedOrSkipped(context.Background(), runID.RunID, maxWait, nil)
+	if err == nil && run.State != nil && run.State.ResultState != "" && run.State.ResultState != jobs.RunResultStateSuccess {
+		err = fmt.Errorf("run finished with result state %s: %s", run.State.ResultState, run.State.StateMessage)
+	}
 	if err != nil {
 		logger.Errorw("job failed", "error", err)
 		errorMessage := err
 		if db.errorMessageClient != nil {
-			errorMessage, err = db.getErrorMessage(jobToRun.JobId)
+			errorMessage, err = db.getErrorMessage(runID.JobID)
 			if err != nil {
-				logger.Errorf("the '%v' job failed, could not get error message: %v\n", jobToRun.JobId, err)
+				logger.Errorf("the '%v' job failed, could not get error message: %v\n", runID.JobID, err)
 			}
 		}
 		wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("job failed: %v", errorMessage))
-		wrapped.AddDetails("job_name", fmt.Sprintf("%s-%s", opts.JobName, id), "job_id", fmt.Sprint(jobToRun.JobId), "executor_type", "Databricks", "store_type", store.Type())
+		wrapped.AddDetails("job_id", fmt.Sprint(runID.JobID), "run_id", fmt.Sprint(runID.RunID), "executor_type", "Databricks", "store_type", store.Type())
 		wrapped.AddFixSuggestion("Check the cluster logs for more information")
 		return wrapped
 	}
-
 	return nil
 }
 
@@ -290,3 +357,65 @@ func (db *DatabricksExecutor) readAndUploadFileDBFS(
 	logger.Infow("Copied local file to remote filestore")
 	return nil
 }
+
+// databricksResumeID serializes into a ResumeID to be used via ResumeOption (a type of TransformationOption)
+type databricksResumeID struct {
+	JobID int64
+	RunID int64
+}
+
+// databricksResumeIDRecordV0 becomes the actual JSON format of the ResumeID in the database.
+type databricksResumeIDRecordV0 struct {
+	// SchemaVersion will make it easier to retain backwards compatibility in the future and do schema
+	// migration.
+	SchemaVersion int
+	JobID         int64
+	RunID         int64
+}
+
+func (rec databricksResumeIDRecordV0) ToDatabricksResumeID() *databricksResumeID {
+	return &databricksResumeID{
+		JobID: rec.JobID,
+		RunID: rec.RunID,
+	}
+}
+
+func (resID *databricksResumeID) Validate() error {
+	if resID.JobID == 0 {
+		return fferr.NewInternalErrorf("Databricks Resume ID must have JobID set: %v", resID)
+	}
+	if resID.RunID == 0 {
+		return fferr.NewInternalErrorf("Databricks Resume ID must have RunID set: %v", resID)
+	}
+	return nil
+}
+
+func (resID *databricksResumeID) Marshal() (types.ResumeID, error) {
+	if err := resID.Validate(); err != nil {
+		return types.NilResumeID, err
+	}
+	record := databricksResumeIDRecordV0{
+		// If you're changing the schema of the record, you should change the schema version and handle it in
+		// the deserialize method.
+		SchemaVersion: 0,
+		JobID:         resID.JobID,
+		RunID:         resID.RunID,
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fferr.NewInternalErrorf("Unable to serialize Databricks resume ID: %s", err)
+	}
+	return types.ResumeID(data), nil
+}
+
+func deserializeDatabricksResumeID(id types.ResumeID) (*databricksResumeID, error) {
+	var record databricksResumeIDRecordV0
+	if err := json.Unmarshal([]byte(id), &record); err != nil {
+		return nil, fferr.NewInternalErrorf("Unable to deserialize Databricks resume ID: %s", err)
+	}
+	resID := record.ToDatabricksResumeID()
+	if err := resID.Validate(); err != nil {
+		return nil, err
+	}
+	return resID, nil
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -87,14 +88,20 @@ func (e *DataprocServerlessExecutor) RunSparkJob(
 	)
 	logger.Debug("Starting RunSparkJob")
 	resumeOpt, hasResumeOpt := tfOpts.GetResumeOption(logger)
+	var op *dataproclib.CreateBatchOperation
 	var jobName string
 	if hasResumeOpt && resumeOpt.IsResumeIDSet() {
-		jobName = resumeOpt.ResumeID().String()
+		resumeID := deserializeDataprocResumeID(resumeOpt.ResumeID())
+		jobName = resumeID.RequestID
 		logger = logger.With(
 			"resuming-spark-job", "true",
 			"spark-job-name", jobName,
+			"operation-name", resumeID.OperationName,
 		)
 		logger.Info("Resuming spark job")
+		if resumeID.OperationName != "" {
+			op = e.client.CreateBatchOperation(resumeID.OperationName)
+		}
 	} else {
 		// GCloud wants job names to be lower cased
 		id := uuid.New().String()
@@ -102,35 +109,46 @@ func (e *DataprocServerlessExecutor) RunSparkJob(
 		logger = logger.With(
 			"spark-job-name", jobName,
 		)
-		if hasResumeOpt {
-			logger.Debug("Setting resume id")
-			resumeOpt.setResumeID(types.ResumeID(jobName))
-		}
 		logger.Info("Starting Spark Job")
 	}
-	req := cmd.CompileDataprocServerless(e.projectID, e.region)
-	req.RequestId = jobName
-	logger.Infow("request", "req", req)
-	op, err := e.client.CreateBatch(ctx, req)
-	if err != nil {
-		wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), err)
-		st, ok := status.FromError(err)
-		if ok && st.Code() == codes.InvalidArgument {
-			logger = logger.With("err-details", st.Details())
-			wrapped.AddDetails("err-details", st.Details())
+	// Resume IDs written before operation names were recorded only have the request ID. Re-submitting
+	// with the same request ID is idempotent, so it re-attaches to the existing batch.
+	if op == nil {
+		req := cmd.CompileDataprocServerless(e.projectID, e.region)
+		req.RequestId = jobName
+		logger.Infow("request", "req", req)
+		var err error
+		op, err = e.client.CreateBatch(ctx, req)
+		if err != nil {
+			wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), err)
+			st, ok := status.FromError(err)
+			if ok && st.Code() == codes.InvalidArgument {
+				logger = logger.With("err-details", st.Details())
+				wrapped.AddDetails("err-details", st.Details())
+			}
+			logger.Errorw("Error submitting job", "error", err)
+			wrapped.AddDetails(
+				"jobName", jobName,
+				"executor_type", "DataProc Serverless",
+				"store_type", store.Type(),
+			)
+			wrapped.AddFixSuggestion("Check the cluster logs for more information")
+			return err
+		}
+	}
+	if hasResumeOpt && !resumeOpt.IsResumeIDSet() {
+		logger.Debug("Setting resume id")
+		resumeID, err := (&dataprocResumeID{RequestID: jobName, OperationName: op.Name()}).Marshal()
+		if err != nil {
+			return err
+		}
+		if err := resumeOpt.setResumeID(resumeID); err != nil {
+			return err
 		}
-		logger.Errorw("Error submitting job", "error", err)
-		wrapped.AddDetails(
-			"jobName", jobName,
-			"executor_type", "DataProc Serverless",
-			"store_type", store.Type(),
-		)
-		wrapped.AddFixSuggestion("Check the cluster logs for more information")
-		return err
 	}
 	waitFn := func() error {
 		logger.Info("Waiting for job to finish")
-		_, err = op.Wait(ctx)
+		_, err := op.Wait(ctx)
 		if err != nil {
 			logger.Errorw("Job Failed", "error", err)
 			wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), err)
@@ -175,3 +193,65 @@ func (e *DataprocServerlessExecutor) SupportsTransformationOption(
 	logger.Debugw("Dataproc doesn't support transformation option")
 	return false, nil
 }
+
+// dataprocResumeID serializes into a ResumeID to be used via ResumeOption (a type of TransformationOption)
+type dataprocResumeID struct {
+	// RequestID is the idempotency key the batch was created with.
+	RequestID string
+	// OperationName is the long-running operation that tracks the batch.
+	OperationName string
+}
+
+// dataprocResumeIDRecordV0 becomes the actual JSON format of the ResumeID in the database.
+type dataprocResumeIDRecordV0 struct {
+	// SchemaVersion will make it easier to retain backwards compatibility in the future and do schema
+	// migration.
+	SchemaVersion int
+	RequestID     string
+	OperationName string
+}
+
+func (rec dataprocResumeIDRecordV0) ToDataprocResumeID() *dataprocResumeID {
+	return &dataprocResumeID{
+		RequestID:     rec.RequestID,
+		OperationName: rec.OperationName,
+	}
+}
+
+func (resID *dataprocResumeID) Validate() error {
+	if resID.RequestID == "" {
+		return fferr.NewInternalErrorf("Dataproc Resume ID must have RequestID set: %v", resID)
+	}
+	if resID.OperationName == "" {
+		return fferr.NewInternalErrorf("Dataproc Resume ID must have OperationName set: %v", resID)
+	}
+	return nil
+}
+
+func (resID *dataprocResumeID) Marshal() (types.ResumeID, error) {
+	if err := resID.Validate(); err != nil {
+		return types.NilResumeID, err
+	}
+	record := dataprocResumeIDRecordV0{
+		// If you're changing the schema of the record, you should change the schema version and handle it in
+		// the deserialize method.
+		SchemaVersion: 0,
+		RequestID:     resID.RequestID,
+		OperationName: resID.OperationName,
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fferr.NewInternalErrorf("Unable to serialize Dataproc resume ID: %s", err)
+	}
+	return types.ResumeID(data), nil
+}
+
+// deserializeDataprocResumeID parses a resume ID. Resume IDs that predate the versioned record
+// were the bare request ID, those are returned without an operation name.
+func deserializeDataprocResumeID(id types.ResumeID) *dataprocResumeID {
+	var record dataprocResumeIDRecordV0
+	if err := json.Unmarshal([]byte(id), &record); err != nil || record.RequestID == "" {
+		return &dataprocResumeID{RequestID: id.String()}
+	}
+	return record.ToDataprocResumeID()
+}
@@ -37,6 +37,7 @@ import (
 	pt "github.com/featureform/provider/provider_type"
 	se "github.com/featureform/provider/serialization"
 	vt "github.com/featureform/provider/types"
+	"github.com/featureform/provider/writebudget"
 )
 
 func init() {
@@ -81,6 +82,7 @@ type dynamodbOnlineStore struct {
 	region             string
 	stronglyConsistent bool
 	tags               []types.Tag
+	writeBudget        pc.WriteBudget
 }
 
 type dynamodbOnlineTable struct {
@@ -192,6 +194,7 @@ func NewDynamodbOnlineStore(options *pc.DynamodbConfig) (*dynamodbOnlineStore, e
 		options.Region,
 		options.StronglyConsistent,
 		tags,
+		options.WriteBudget,
 	}, nil
 }
 
@@ -199,6 +202,10 @@ func (store *dynamodbOnlineStore) AsOnlineStore() (OnlineStore, error) {
 	return store, nil
 }
 
+func (store *dynamodbOnlineStore) WriteBudget() pc.WriteBudget {
+	return store.writeBudget
+}
+
 func (store *dynamodbOnlineStore) Close() error {
 	// dynamoDB client does not implement an equivalent to Close
 	return nil
@@ -499,8 +506,12 @@ func (store *dynamodbOnlineStore) DeleteTable(feature, variant string) error {
 }
 
 func (store *dynamodbOnlineStore) CheckHealth() (bool, error) {
+	return store.CheckHealthContext(context.Background())
+}
+
+func (store *dynamodbOnlineStore) CheckHealthContext(ctx context.Context) (bool, error) {
 	store.logger.Info("Checking health of DynamoDB connnection ...")
-	_, err := store.client.ListTables(context.TODO(), &dynamodb.ListTablesInput{Limit: aws.Int32(1)})
+	_, err := store.client.ListTables(ctx, &dynamodb.ListTablesInput{Limit: aws.Int32(1)})
 	if err != nil {
 		store.logger.Errorw("DynamoDB health check failed", "err", err)
 		return false, fferr.NewExecutionError(pt.DynamoDBOnline.String(), err)
@@ -553,9 +564,16 @@ func (table dynamodbOnlineTable) BatchSet(ctx context.Context, items []SetItem)
 		output, err := table.client.BatchWriteItem(ctx, batchInput)
 		table.logBatchWriteItemError(logger, err)
 		if err != nil {
+			if writebudget.IsThrottle(err) {
+				writebudget.ReportThrottle(ctx)
+			}
 			continue
 		}
 		*unprocessedItems = table.handleUnprocessedItem(logger, output)
+		// DynamoDB returns unprocessed items when the table is over its provisioned throughput.
+		if len(*unprocessedItems) > 0 {
+			writebudget.ReportThrottle(ctx)
+		}
 	}
 	logger.Debugw("Successfully wrote items to dynamo", "item_count", len(*unprocessedItems))
 	return nil
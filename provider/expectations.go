@@ -0,0 +1,305 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
+	"github.com/featureform/provider/dataset"
+	pl "github.com/featureform/provider/location"
+)
+
+type ExpectationType int
+
+const (
+	// NullFractionExpectation counts null values in Column.
+	NullFractionExpectation ExpectationType = iota
+	// RangeExpectation counts non-null values of Column outside of [Min, Max].
+	RangeExpectation
+	// UniqueExpectation counts rows that repeat the values of Columns of an earlier row.
+	UniqueExpectation
+	// RowCountExpectation only counts rows.
+	RowCountExpectation
+)
+
+func (t ExpectationType) String() string {
+	switch t {
+	case NullFractionExpectation:
+		return "NullFraction"
+	case RangeExpectation:
+		return "Range"
+	case UniqueExpectation:
+		return "Unique"
+	case RowCountExpectation:
+		return "RowCount"
+	default:
+		return fmt.Sprintf("ExpectationType(%d)", int(t))
+	}
+}
+
+// Expectation is a data quality check to measure against a dataset. Deciding whether the
+// measurement passes is left to the caller.
+type Expectation struct {
+	Name    string
+	Type    ExpectationType
+	Column  string
+	Columns []string
+	// Min and Max bound RangeExpectation; an infinite bound isn't checked.
+	Min float64
+	Max float64
+}
+
+func (e Expectation) check() error {
+	switch e.Type {
+	case NullFractionExpectation, RangeExpectation:
+		if e.Column == "" {
+			return fferr.NewInvalidArgumentErrorf("expectation %s requires a column", e.Name)
+		}
+	case UniqueExpectation:
+		if len(e.Columns) == 0 {
+			return fferr.NewInvalidArgumentErrorf("expectation %s requires at least one column", e.Name)
+		}
+	case RowCountExpectation:
+	default:
+		return fferr.NewInvalidArgumentErrorf("expectation %s has unknown type %s", e.Name, e.Type)
+	}
+	return nil
+}
+
+// ExpectationMeasurement is what measuring an Expectation found.
+type ExpectationMeasurement struct {
+	Rows int64
+	// Violations are null values, out of range values or duplicate rows, depending on the
+	// expectation's type.
+	Violations int64
+}
+
+// ExpectationMeasurer is implemented by offline stores that can push expectations down to the
+// database rather than reading the whole dataset.
+type ExpectationMeasurer interface {
+	MeasureExpectations(ctx context.Context, ds dataset.Dataset, expectations []Expectation) ([]ExpectationMeasurement, error)
+}
+
+// MeasureExpectations measures each expectation against ds, in order. Stores that implement
+// ExpectationMeasurer do the work; otherwise ds is read once, row by row.
+func MeasureExpectations(ctx context.Context, store OfflineStore, ds dataset.Dataset, expectations []Expectation) ([]ExpectationMeasurement, error) {
+	for _, e := range expectations {
+		if err := e.check(); err != nil {
+			return nil, err
+		}
+	}
+	if measurer, ok := store.(ExpectationMeasurer); ok {
+		return measurer.MeasureExpectations(ctx, ds, expectations)
+	}
+	return measureExpectationsFromIterator(ctx, ds, expectations)
+}
+
+func measureExpectationsFromIterator(ctx context.Context, ds dataset.Dataset, expectations []Expectation) ([]ExpectationMeasurement, error) {
+	iter, err := ds.Iterator(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	return measureIterator(iter, expectations)
+}
+
+// MeasureIteratorExpectations measures each expectation by reading the rest of iter. It doesn't
+// close iter.
+func MeasureIteratorExpectations(iter dataset.Iterator, expectations []Expectation) ([]ExpectationMeasurement, error) {
+	for _, e := range expectations {
+		if err := e.check(); err != nil {
+			return nil, err
+		}
+	}
+	return measureIterator(iter, expectations)
+}
+
+// MeasureTrainingSetExpectations measures each expectation against the features and, if hasLabel
+// is set, the label of a training set by reading the rest of iter. columns names the features in
+// order, followed by the label. It doesn't close iter.
+func MeasureTrainingSetExpectations(iter dataset.TrainingSetIterator, columns []fftypes.ColumnSchema, hasLabel bool, expectations []Expectation) ([]ExpectationMeasurement, error) {
+	for _, e := range expectations {
+		if err := e.check(); err != nil {
+			return nil, err
+		}
+	}
+	return measureRows(iter, trainingSetValues(iter, hasLabel), columns, expectations)
+}
+
+// trainingSetValues returns the current row of iter as its features followed by its label.
+// Training set iterators don't all implement Values.
+func trainingSetValues(iter dataset.TrainingSetIterator, hasLabel bool) func() fftypes.Row {
+	return func() fftypes.Row {
+		row := append(fftypes.Row{}, iter.Features().Row...)
+		if hasLabel {
+			row = append(row, iter.Label())
+		}
+		return row
+	}
+}
+
+type rowSource interface {
+	Next() bool
+	Err() error
+}
+
+func measureIterator(iter dataset.Iterator, expectations []Expectation) ([]ExpectationMeasurement, error) {
+	return measureRows(iter, iter.Values, iter.Schema().Fields, expectations)
+}
+
+func measureRows(src rowSource, values func() fftypes.Row, fields []fftypes.ColumnSchema, expectations []Expectation) ([]ExpectationMeasurement, error) {
+	columns := make(map[string]int)
+	for i, field := range fields {
+		columns[string(field.Name)] = i
+	}
+	indices := make([][]int, len(expectations))
+	for i, e := range expectations {
+		names := e.Columns
+		if e.Type == NullFractionExpectation || e.Type == RangeExpectation {
+			names = []string{e.Column}
+		}
+		if e.Type == RowCountExpectation {
+			names = nil
+		}
+		for _, name := range names {
+			idx, has := columns[name]
+			if !has {
+				return nil, fferr.NewInvalidArgumentErrorf("expectation %s: column %s not found in dataset", e.Name, name)
+			}
+			indices[i] = append(indices[i], idx)
+		}
+	}
+
+	measurements := make([]ExpectationMeasurement, len(expectations))
+	seen := make([]map[string]struct{}, len(expectations))
+	for i, e := range expectations {
+		if e.Type == UniqueExpectation {
+			seen[i] = make(map[string]struct{})
+		}
+	}
+	var rows int64
+	for src.Next() {
+		row := values()
+		rows++
+		for i, e := range expectations {
+			switch e.Type {
+			case NullFractionExpectation:
+				if row[indices[i][0]].Value == nil {
+					measurements[i].Violations++
+				}
+			case RangeExpectation:
+				outside, err := outsideRange(row[indices[i][0]], e.Min, e.Max)
+				if err != nil {
+					return nil, fferr.NewInvalidArgumentErrorf("expectation %s: column %s: %v", e.Name, e.Column, err)
+				}
+				if outside {
+					measurements[i].Violations++
+				}
+			case UniqueExpectation:
+				key := uniqueKey(row, indices[i])
+				if _, has := seen[i][key]; has {
+					measurements[i].Violations++
+				} else {
+					seen[i][key] = struct{}{}
+				}
+			}
+		}
+	}
+	if err := src.Err(); err != nil {
+		return nil, err
+	}
+	for i := range measurements {
+		measurements[i].Rows = rows
+	}
+	return measurements, nil
+}
+
+func outsideRange(val fftypes.Value, min, max float64) (bool, error) {
+	if val.Value == nil {
+		return false, nil
+	}
+	f, err := fftypes.ConvertNumberToFloat64(val.Value)
+	if err != nil {
+		return false, err
+	}
+	return (!math.IsInf(min, -1) && f < min) || (!math.IsInf(max, 1) && f > max), nil
+}
+
+func uniqueKey(row fftypes.Row, indices []int) string {
+	parts := make([]string, len(indices))
+	for i, idx := range indices {
+		if row[idx].Value == nil {
+			parts[i] = "\x01"
+			continue
+		}
+		parts[i] = fmt.Sprintf("%v", row[idx].Value)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+func (store *sqlOfflineStore) MeasureExpectations(ctx context.Context, ds dataset.Dataset, expectations []Expectation) ([]ExpectationMeasurement, error) {
+	sqlLocation, ok := ds.Location().(*pl.SQLLocation)
+	if !ok {
+		return measureExpectationsFromIterator(ctx, ds, expectations)
+	}
+	dbConn, err := store.getDb(sqlLocation.GetDatabase(), sqlLocation.GetSchema())
+	if err != nil {
+		return nil, fferr.NewConnectionError(store.Type().String(), err)
+	}
+	table := pl.SanitizeFullyQualifiedObject(sqlLocation.TableLocation())
+	measurements := make([]ExpectationMeasurement, len(expectations))
+	for i, e := range expectations {
+		query := expectationQuery(e, table)
+		store.logger.Debugw("Measuring expectation", "expectation", e.Name, "query", query)
+		if err := dbConn.QueryRowContext(ctx, query).Scan(&measurements[i].Rows, &measurements[i].Violations); err != nil {
+			wrapped := fferr.NewExecutionError(store.Type().String(), err)
+			wrapped.AddDetail("expectation", e.Name)
+			wrapped.AddDetail("table_name", sqlLocation.Location())
+			return nil, wrapped
+		}
+	}
+	return measurements, nil
+}
+
+// expectationQuery selects the row count and the violation count of e in table.
+func expectationQuery(e Expectation, table string) string {
+	switch e.Type {
+	case NullFractionExpectation:
+		return fmt.Sprintf("SELECT COUNT(*), COUNT(*) - COUNT(%s) FROM %s", sanitize(e.Column), table)
+	case RangeExpectation:
+		column := sanitize(e.Column)
+		bounds := make([]string, 0, 2)
+		if !math.IsInf(e.Min, -1) {
+			bounds = append(bounds, fmt.Sprintf("%s < %s", column, strconv.FormatFloat(e.Min, 'g', -1, 64)))
+		}
+		if !math.IsInf(e.Max, 1) {
+			bounds = append(bounds, fmt.Sprintf("%s > %s", column, strconv.FormatFloat(e.Max, 'g', -1, 64)))
+		}
+		if len(bounds) == 0 {
+			return fmt.Sprintf("SELECT COUNT(*), 0 FROM %s", table)
+		}
+		return fmt.Sprintf("SELECT COUNT(*), COUNT(CASE WHEN %s THEN 1 END) FROM %s", strings.Join(bounds, " OR "), table)
+	case UniqueExpectation:
+		sanitized := make([]string, len(e.Columns))
+		for i, c := range e.Columns {
+			sanitized[i] = sanitize(c)
+		}
+		return fmt.Sprintf(
+			"SELECT COUNT(*), COUNT(*) - (SELECT COUNT(*) FROM (SELECT DISTINCT %s FROM %s) distinct_rows) FROM %s",
+			strings.Join(sanitized, ", "), table, table,
+		)
+	default:
+		return fmt.Sprintf("SELECT COUNT(*), 0 FROM %s", table)
+	}
+}
@@ -0,0 +1,160 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	fftypes "github.com/featureform/fftypes"
+	"github.com/featureform/provider/dataset"
+	pl "github.com/featureform/provider/location"
+)
+
+func expectationTestDataset() dataset.Dataset {
+	schema := fftypes.Schema{
+		Fields: []fftypes.ColumnSchema{
+			{Name: "entity", Type: fftypes.String},
+			{Name: "amount", Type: fftypes.Float64},
+			{Name: "ts", Type: fftypes.Int64},
+		},
+	}
+	row := func(entity string, amount any, ts int64) fftypes.Row {
+		return fftypes.Row{
+			{Type: fftypes.String, Value: entity},
+			{Type: fftypes.Float64, Value: amount},
+			{Type: fftypes.Int64, Value: ts},
+		}
+	}
+	rows := []fftypes.Row{
+		row("a", 1.5, 1),
+		row("a", -2.0, 2),
+		row("b", nil, 1),
+		row("b", 30.0, 1),
+		row("c", 4.0, 1),
+	}
+	return dataset.NewInMemoryDataset(rows, schema, pl.NewSQLLocation("transactions"))
+}
+
+func TestMeasureExpectations(t *testing.T) {
+	expectations := []Expectation{
+		{Name: "nulls", Type: NullFractionExpectation, Column: "amount"},
+		{Name: "range", Type: RangeExpectation, Column: "amount", Min: 0, Max: 10},
+		{Name: "open_range", Type: RangeExpectation, Column: "amount", Min: math.Inf(-1), Max: 10},
+		{Name: "unique", Type: UniqueExpectation, Columns: []string{"entity", "ts"}},
+		{Name: "rows", Type: RowCountExpectation},
+	}
+	measurements, err := MeasureExpectations(context.Background(), NewMemoryOfflineStore(), expectationTestDataset(), expectations)
+	if err != nil {
+		t.Fatalf("Failed to measure expectations: %v", err)
+	}
+	expected := []ExpectationMeasurement{
+		{Rows: 5, Violations: 1},
+		{Rows: 5, Violations: 2},
+		{Rows: 5, Violations: 1},
+		{Rows: 5, Violations: 1},
+		{Rows: 5, Violations: 0},
+	}
+	if !reflect.DeepEqual(measurements, expected) {
+		t.Fatalf("Wrong measurements\nexpected: %v\n     got: %v", expected, measurements)
+	}
+
+	missing := []Expectation{{Name: "missing", Type: NullFractionExpectation, Column: "price"}}
+	if _, err := MeasureExpectations(context.Background(), NewMemoryOfflineStore(), expectationTestDataset(), missing); err == nil {
+		t.Fatalf("Expected an error for a column that doesn't exist")
+	}
+	noColumns := []Expectation{{Name: "no_columns", Type: UniqueExpectation}}
+	if _, err := MeasureExpectations(context.Background(), NewMemoryOfflineStore(), expectationTestDataset(), noColumns); err == nil {
+		t.Fatalf("Expected an error for a unique expectation without columns")
+	}
+}
+
+func TestExpectationQuery(t *testing.T) {
+	table := `"transactions"`
+	tests := map[string]struct {
+		expectation Expectation
+		expected    string
+	}{
+		"NullFraction": {
+			Expectation{Type: NullFractionExpectation, Column: "amount"},
+			`SELECT COUNT(*), COUNT(*) - COUNT("amount") FROM "transactions"`,
+		},
+		"Range": {
+			Expectation{Type: RangeExpectation, Column: "amount", Min: 0, Max: 10.5},
+			`SELECT COUNT(*), COUNT(CASE WHEN "amount" < 0 OR "amount" > 10.5 THEN 1 END) FROM "transactions"`,
+		},
+		"RangeWithoutMax": {
+			Expectation{Type: RangeExpectation, Column: "amount", Min: 0, Max: math.Inf(1)},
+			`SELECT COUNT(*), COUNT(CASE WHEN "amount" < 0 THEN 1 END) FROM "transactions"`,
+		},
+		"Unique": {
+			Expectation{Type: UniqueExpectation, Columns: []string{"entity", "ts"}},
+			`SELECT COUNT(*), COUNT(*) - (SELECT COUNT(*) FROM (SELECT DISTINCT "entity", "ts" FROM "transactions") distinct_rows) FROM "transactions"`,
+		},
+		"RowCount": {
+			Expectation{Type: RowCountExpectation},
+			`SELECT COUNT(*), 0 FROM "transactions"`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if query := expectationQuery(test.expectation, table); query != test.expected {
+				t.Fatalf("Unexpected query\nexpected: %s\n     got: %s", test.expected, query)
+			}
+		})
+	}
+}
+
+type legacyTrainingSetRows struct {
+	rows [][]interface{}
+	idx  int
+}
+
+func (it *legacyTrainingSetRows) Next() bool {
+	it.idx++
+	return it.idx <= len(it.rows)
+}
+
+func (it *legacyTrainingSetRows) Features() []interface{} {
+	row := it.rows[it.idx-1]
+	return row[:len(row)-1]
+}
+
+func (it *legacyTrainingSetRows) Label() interface{} {
+	row := it.rows[it.idx-1]
+	return row[len(row)-1]
+}
+
+func (it *legacyTrainingSetRows) Err() error {
+	return nil
+}
+
+func TestMeasureTrainingSetExpectations(t *testing.T) {
+	// Legacy training set iterators only expose rows through Features and Label.
+	iter := NewLegacyTrainingSetIteratorAdapter(&legacyTrainingSetRows{rows: [][]interface{}{
+		{1.0, "a", true},
+		{nil, "b", false},
+		{3.0, "b", nil},
+	}})
+	columns := []fftypes.ColumnSchema{{Name: "feature__amount__v1"}, {Name: "feature__city__v1"}, {Name: "label__fraud__v1"}}
+	expectations := []Expectation{
+		{Name: "amount_nulls", Type: NullFractionExpectation, Column: "feature__amount__v1"},
+		{Name: "label_nulls", Type: NullFractionExpectation, Column: "label__fraud__v1"},
+		{Name: "unique_city", Type: UniqueExpectation, Columns: []string{"feature__city__v1"}},
+	}
+	measurements, err := MeasureTrainingSetExpectations(iter, columns, true, expectations)
+	if err != nil {
+		t.Fatalf("Failed to measure expectations: %v", err)
+	}
+	expected := []ExpectationMeasurement{{Rows: 3, Violations: 1}, {Rows: 3, Violations: 1}, {Rows: 3, Violations: 1}}
+	if !reflect.DeepEqual(measurements, expected) {
+		t.Fatalf("Wrong measurements\nexpected: %v\n     got: %v", expected, measurements)
+	}
+}
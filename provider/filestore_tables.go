@@ -0,0 +1,634 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/filestore"
+	pl "github.com/featureform/provider/location"
+)
+
+// LatestTableVersion selects the newest version of a Delta table or the current snapshot of an
+// Iceberg table.
+const LatestTableVersion int64 = -1
+
+// FileStoreTableReader reads the table log of a Delta Lake or Iceberg table stored in a FileStore
+// so that its data files can be served without Spark.
+type FileStoreTableReader interface {
+	Format() filestore.FileType
+	// LatestVersion returns the newest Delta version or the current Iceberg snapshot ID.
+	LatestVersion() (int64, error)
+	// DataFiles returns the Parquet files that make up the table at a version (a Delta version
+	// or an Iceberg snapshot ID), or at the latest version when given LatestTableVersion.
+	DataFiles(version int64) ([]filestore.Filepath, error)
+}
+
+// NewFileStoreTableReader returns a reader for the table of the given format at dir.
+func NewFileStoreTableReader(store FileStore, dir filestore.Filepath, format filestore.FileType) (FileStoreTableReader, error) {
+	switch format {
+	case filestore.Delta:
+		return &deltaTableReader{store: store, dir: dir}, nil
+	case filestore.Iceberg:
+		return &icebergTableReader{store: store, dir: dir}, nil
+	default:
+		return nil, fferr.NewInvalidArgumentErrorf("unsupported file store table format %q", format)
+	}
+}
+
+// DetectFileStoreTableFormat returns the table format of the table at dir, or an empty FileType
+// if dir holds plain files. Delta tables have a _delta_log directory and Iceberg tables have
+// metadata/*.metadata.json files.
+func DetectFileStoreTableFormat(store FileStore, dir filestore.Filepath) (filestore.FileType, error) {
+	deltaLog, err := tableChildPath(store, dir, deltaLogDir, true)
+	if err != nil {
+		return "", err
+	}
+	commits, err := store.List(deltaLog, filestore.JSON)
+	if err != nil {
+		return "", err
+	}
+	if len(commits) > 0 {
+		return filestore.Delta, nil
+	}
+	metadataDir, err := tableChildPath(store, dir, icebergMetadataDir, true)
+	if err != nil {
+		return "", err
+	}
+	metadataFiles, err := store.List(metadataDir, filestore.JSON)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range metadataFiles {
+		if strings.HasSuffix(f.Key(), icebergMetadataSuffix) {
+			return filestore.Iceberg, nil
+		}
+	}
+	return "", nil
+}
+
+// ServeFileStoreTable returns an iterator over the table at dir as of version.
+func ServeFileStoreTable(store FileStore, dir filestore.Filepath, format filestore.FileType, version int64) (Iterator, error) {
+	reader, err := NewFileStoreTableReader(store, dir, format)
+	if err != nil {
+		return nil, err
+	}
+	files, err := reader.DataFiles(version)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		wrapped := fferr.NewInternalErrorf("%s table has no data files at version %d", format, version)
+		wrapped.AddDetail("uri", dir.ToURI())
+		return nil, wrapped
+	}
+	return store.Serve(files)
+}
+
+// fileStoreNewestFiles returns the Parquet files holding the current contents of a resource
+// directory: the live data files of a Delta or Iceberg table, or otherwise the files in the newest
+// date-time directory written by a run.
+func fileStoreNewestFiles(store FileStore, dir filestore.Filepath) ([]filestore.Filepath, error) {
+	format, err := DetectFileStoreTableFormat(store, dir)
+	if err != nil {
+		return nil, err
+	}
+	if format != "" {
+		reader, err := NewFileStoreTableReader(store, dir, format)
+		if err != nil {
+			return nil, err
+		}
+		return reader.DataFiles(LatestTableVersion)
+	}
+	files, err := store.List(dir, filestore.Parquet)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := filestore.NewFilePathGroup(files, filestore.DateTimeDirectoryGrouping)
+	if err != nil {
+		return nil, err
+	}
+	return groups.GetFirst()
+}
+
+// fileStoreResourceLocation returns the location of a resource's current contents: the table
+// directory for Delta and Iceberg tables, or the newest date-time directory otherwise.
+func fileStoreResourceLocation(store FileStore, id ResourceID) (pl.Location, error) {
+	dir, err := store.CreateFilePath(id.ToFilestorePath(), true)
+	if err != nil {
+		return nil, err
+	}
+	format, err := DetectFileStoreTableFormat(store, dir)
+	if err != nil {
+		return nil, err
+	}
+	if format != "" {
+		return pl.NewFileTableLocation(dir, string(format)), nil
+	}
+	newestFile, err := store.NewestFileOfType(dir, filestore.Parquet)
+	if err != nil {
+		return nil, err
+	}
+	newestDir, err := store.CreateFilePath(newestFile.KeyPrefix(), true)
+	if err != nil {
+		return nil, err
+	}
+	return pl.NewFileLocation(newestDir), nil
+}
+
+// tableChildPath returns the path of rel inside the table directory dir.
+func tableChildPath(store FileStore, dir filestore.Filepath, rel string, isDir bool) (filestore.Filepath, error) {
+	key := path.Join(dir.Key(), rel)
+	if isDir {
+		// A trailing slash keeps a prefix listing from matching sibling directories.
+		key += "/"
+	}
+	return store.CreateFilePath(key, isDir)
+}
+
+// tableFilePath resolves a path recorded in a table log. Relative paths are relative to the table
+// directory; absolute URIs are rebased onto dir when they start with the table's recorded root, so
+// that tables read correctly even if the writer used a different scheme (e.g. s3a:// vs s3://).
+func tableFilePath(store FileStore, dir filestore.Filepath, root, file string) (filestore.Filepath, error) {
+	if !strings.Contains(file, "://") {
+		return tableChildPath(store, dir, file, false)
+	}
+	root = strings.TrimSuffix(root, "/")
+	if root != "" && strings.HasPrefix(file, root+"/") {
+		return tableChildPath(store, dir, strings.TrimPrefix(file, root+"/"), false)
+	}
+	return store.ParseFilePath(file)
+}
+
+const deltaLogDir = "_delta_log"
+
+type deltaTableReader struct {
+	store FileStore
+	dir   filestore.Filepath
+}
+
+func (r *deltaTableReader) Format() filestore.FileType {
+	return filestore.Delta
+}
+
+// deltaLogFiles maps the versions in _delta_log to their commit files and checkpoint parts.
+type deltaLogFiles struct {
+	commits     map[int64]filestore.Filepath
+	checkpoints map[int64][]filestore.Filepath
+}
+
+func (r *deltaTableReader) listLog() (deltaLogFiles, error) {
+	logDir, err := tableChildPath(r.store, r.dir, deltaLogDir, true)
+	if err != nil {
+		return deltaLogFiles{}, err
+	}
+	log := deltaLogFiles{
+		commits:     map[int64]filestore.Filepath{},
+		checkpoints: map[int64][]filestore.Filepath{},
+	}
+	commits, err := r.store.List(logDir, filestore.JSON)
+	if err != nil {
+		return deltaLogFiles{}, err
+	}
+	for _, f := range commits {
+		// Commits are named <20-digit version>.json.
+		name := strings.TrimSuffix(path.Base(f.Key()), ".json")
+		if version, err := strconv.ParseInt(name, 10, 64); err == nil {
+			log.commits[version] = f
+		}
+	}
+	checkpoints, err := r.store.List(logDir, filestore.Parquet)
+	if err != nil {
+		return deltaLogFiles{}, err
+	}
+	for _, f := range checkpoints {
+		// Checkpoints are named <version>.checkpoint.parquet or, when split into parts,
+		// <version>.checkpoint.<part>.<parts>.parquet.
+		parts := strings.Split(path.Base(f.Key()), ".")
+		if len(parts) < 3 || parts[1] != "checkpoint" {
+			continue
+		}
+		if version, err := strconv.ParseInt(parts[0], 10, 64); err == nil {
+			log.checkpoints[version] = append(log.checkpoints[version], f)
+		}
+	}
+	return log, nil
+}
+
+func (r *deltaTableReader) LatestVersion() (int64, error) {
+	log, err := r.listLog()
+	if err != nil {
+		return 0, err
+	}
+	return r.latestVersion(log)
+}
+
+func (r *deltaTableReader) latestVersion(log deltaLogFiles) (int64, error) {
+	latest := int64(-1)
+	for version := range log.commits {
+		if version > latest {
+			latest = version
+		}
+	}
+	for version := range log.checkpoints {
+		if version > latest {
+			latest = version
+		}
+	}
+	if latest < 0 {
+		wrapped := fferr.NewInternalErrorf("delta table has no commits")
+		wrapped.AddDetail("uri", r.dir.ToURI())
+		return 0, wrapped
+	}
+	return latest, nil
+}
+
+// deltaAction is one line of a Delta commit file or one row of a checkpoint. Only the actions and
+// fields needed to work out a version's data files are decoded.
+type deltaAction struct {
+	Add      *deltaAddAction    `json:"add,omitempty" parquet:"add,optional"`
+	Remove   *deltaRemoveAction `json:"remove,omitempty" parquet:"remove,optional"`
+	MetaData *deltaMetadata     `json:"metaData,omitempty" parquet:"metaData,optional"`
+}
+
+type deltaAddAction struct {
+	Path           string                   `json:"path" parquet:"path"`
+	DeletionVector *deltaDeletionDescriptor `json:"deletionVector,omitempty" parquet:"deletionVector,optional"`
+}
+
+type deltaDeletionDescriptor struct {
+	StorageType string `json:"storageType" parquet:"storageType"`
+}
+
+type deltaRemoveAction struct {
+	Path string `json:"path" parquet:"path"`
+}
+
+type deltaMetadata struct {
+	PartitionColumns []string `json:"partitionColumns" parquet:"partitionColumns,list"`
+}
+
+func (r *deltaTableReader) DataFiles(version int64) ([]filestore.Filepath, error) {
+	log, err := r.listLog()
+	if err != nil {
+		return nil, err
+	}
+	if version == LatestTableVersion {
+		if version, err = r.latestVersion(log); err != nil {
+			return nil, err
+		}
+	}
+	// Start from the newest checkpoint at or before the version, then replay the commits after it.
+	start := int64(-1)
+	for v := range log.checkpoints {
+		if v <= version && v > start {
+			start = v
+		}
+	}
+	live := map[string]bool{}
+	var partitionColumns []string
+	apply := func(action deltaAction) error {
+		switch {
+		case action.Add != nil:
+			if action.Add.DeletionVector != nil {
+				wrapped := fferr.NewInternalErrorf("delta tables with deletion vectors are not supported")
+				wrapped.AddDetail("uri", r.dir.ToURI())
+				return wrapped
+			}
+			live[action.Add.Path] = true
+		case action.Remove != nil:
+			delete(live, action.Remove.Path)
+		case action.MetaData != nil:
+			partitionColumns = action.MetaData.PartitionColumns
+		}
+		return nil
+	}
+	if start >= 0 {
+		for _, part := range log.checkpoints[start] {
+			actions, err := r.readCheckpoint(part)
+			if err != nil {
+				return nil, err
+			}
+			for _, action := range actions {
+				if err := apply(action); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for v := start + 1; v <= version; v++ {
+		commit, ok := log.commits[v]
+		if !ok {
+			wrapped := fferr.NewInternalErrorf("delta table is missing commit %d", v)
+			wrapped.AddDetail("uri", r.dir.ToURI())
+			return nil, wrapped
+		}
+		actions, err := r.readCommit(commit)
+		if err != nil {
+			return nil, err
+		}
+		for _, action := range actions {
+			if err := apply(action); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(partitionColumns) > 0 {
+		// Partition values live in directory names rather than the data files, so serving the
+		// files alone would drop those columns.
+		wrapped := fferr.NewInternalErrorf("partitioned delta tables are not supported")
+		wrapped.AddDetail("uri", r.dir.ToURI())
+		wrapped.AddDetail("partition_columns", strings.Join(partitionColumns, ","))
+		return nil, wrapped
+	}
+	paths := make([]string, 0, len(live))
+	for p := range live {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	files := make([]filestore.Filepath, 0, len(paths))
+	for _, p := range paths {
+		// Paths in the log are URL-encoded.
+		decoded, err := url.PathUnescape(p)
+		if err != nil {
+			return nil, fferr.NewParsingError(err)
+		}
+		file, err := tableFilePath(r.store, r.dir, r.dir.ToURI(), decoded)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func (r *deltaTableReader) readCommit(commit filestore.Filepath) ([]deltaAction, error) {
+	data, err := r.store.Read(commit)
+	if err != nil {
+		return nil, err
+	}
+	actions := []deltaAction{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var action deltaAction
+		if err := json.Unmarshal(line, &action); err != nil {
+			wrapped := fferr.NewParsingError(err)
+			wrapped.AddDetail("uri", commit.ToURI())
+			return nil, wrapped
+		}
+		actions = append(actions, action)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fferr.NewParsingError(err)
+	}
+	return actions, nil
+}
+
+func (r *deltaTableReader) readCheckpoint(checkpoint filestore.Filepath) ([]deltaAction, error) {
+	data, err := r.store.Read(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	reader := parquet.NewGenericReader[deltaAction](bytes.NewReader(data))
+	defer reader.Close()
+	actions := make([]deltaAction, reader.NumRows())
+	n, err := reader.Read(actions)
+	if err != nil && err != io.EOF {
+		wrapped := fferr.NewParsingError(err)
+		wrapped.AddDetail("uri", checkpoint.ToURI())
+		return nil, wrapped
+	}
+	return actions[:n], nil
+}
+
+const (
+	icebergMetadataDir    = "metadata"
+	icebergMetadataSuffix = ".metadata.json"
+	icebergVersionHint    = "version-hint.text"
+
+	// Manifest entry statuses and data file content types from the Iceberg spec.
+	icebergEntryDeleted  = 2
+	icebergContentData   = 0
+	icebergManifestData  = 0
+	icebergParquetFormat = "PARQUET"
+)
+
+type icebergTableReader struct {
+	store FileStore
+	dir   filestore.Filepath
+}
+
+func (r *icebergTableReader) Format() filestore.FileType {
+	return filestore.Iceberg
+}
+
+type icebergTableMetadata struct {
+	Location          string            `json:"location"`
+	CurrentSnapshotID *int64            `json:"current-snapshot-id"`
+	Snapshots         []icebergSnapshot `json:"snapshots"`
+}
+
+type icebergSnapshot struct {
+	SnapshotID   int64  `json:"snapshot-id"`
+	ManifestList string `json:"manifest-list"`
+	TimestampMs  int64  `json:"timestamp-ms"`
+}
+
+// currentMetadata reads the newest metadata file, found through version-hint.text when the
+// writer keeps one (Hadoop catalogs) or else by the highest version in the file names, which are
+// v<N>.metadata.json or <N>-<uuid>.metadata.json.
+func (r *icebergTableReader) currentMetadata() (icebergTableMetadata, error) {
+	metadataDir, err := tableChildPath(r.store, r.dir, icebergMetadataDir, true)
+	if err != nil {
+		return icebergTableMetadata{}, err
+	}
+	files, err := r.store.List(metadataDir, filestore.JSON)
+	if err != nil {
+		return icebergTableMetadata{}, err
+	}
+	hint := ""
+	hintPath, err := tableChildPath(r.store, r.dir, path.Join(icebergMetadataDir, icebergVersionHint), false)
+	if err != nil {
+		return icebergTableMetadata{}, err
+	}
+	if data, err := r.store.Read(hintPath); err == nil {
+		hint = "v" + strings.TrimSpace(string(data)) + icebergMetadataSuffix
+	}
+	var newest filestore.Filepath
+	newestVersion := int64(-1)
+	for _, f := range files {
+		name := path.Base(f.Key())
+		if !strings.HasSuffix(name, icebergMetadataSuffix) {
+			continue
+		}
+		if name == hint {
+			newest = f
+			break
+		}
+		prefix := strings.TrimPrefix(strings.SplitN(name, "-", 2)[0], "v")
+		prefix = strings.TrimSuffix(prefix, icebergMetadataSuffix)
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		if version > newestVersion {
+			newestVersion = version
+			newest = f
+		}
+	}
+	if newest == nil {
+		wrapped := fferr.NewInternalErrorf("iceberg table has no metadata files")
+		wrapped.AddDetail("uri", r.dir.ToURI())
+		return icebergTableMetadata{}, wrapped
+	}
+	data, err := r.store.Read(newest)
+	if err != nil {
+		return icebergTableMetadata{}, err
+	}
+	var metadata icebergTableMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		wrapped := fferr.NewParsingError(err)
+		wrapped.AddDetail("uri", newest.ToURI())
+		return icebergTableMetadata{}, wrapped
+	}
+	return metadata, nil
+}
+
+func (r *icebergTableReader) LatestVersion() (int64, error) {
+	metadata, err := r.currentMetadata()
+	if err != nil {
+		return 0, err
+	}
+	// Spec v1 tables use -1 for "no snapshot".
+	if metadata.CurrentSnapshotID == nil || *metadata.CurrentSnapshotID == -1 {
+		wrapped := fferr.NewInternalErrorf("iceberg table has no snapshots")
+		wrapped.AddDetail("uri", r.dir.ToURI())
+		return 0, wrapped
+	}
+	return *metadata.CurrentSnapshotID, nil
+}
+
+func (r *icebergTableReader) DataFiles(snapshotID int64) ([]filestore.Filepath, error) {
+	metadata, err := r.currentMetadata()
+	if err != nil {
+		return nil, err
+	}
+	if snapshotID == LatestTableVersion {
+		if metadata.CurrentSnapshotID == nil || *metadata.CurrentSnapshotID == -1 {
+			return []filestore.Filepath{}, nil
+		}
+		snapshotID = *metadata.CurrentSnapshotID
+	}
+	var snapshot *icebergSnapshot
+	for i := range metadata.Snapshots {
+		if metadata.Snapshots[i].SnapshotID == snapshotID {
+			snapshot = &metadata.Snapshots[i]
+		}
+	}
+	if snapshot == nil {
+		wrapped := fferr.NewInternalErrorf("iceberg snapshot %d not found; it may have been expired", snapshotID)
+		wrapped.AddDetail("uri", r.dir.ToURI())
+		return nil, wrapped
+	}
+	manifestList, err := r.readAvro(metadata.Location, snapshot.ManifestList)
+	if err != nil {
+		return nil, err
+	}
+	files := []filestore.Filepath{}
+	for _, record := range manifestList {
+		manifest, _ := record.(map[string]interface{})
+		// Spec v1 manifest lists have no content field; every manifest holds data files.
+		if content, ok := manifest["content"].(int32); ok && content != icebergManifestData {
+			wrapped := fferr.NewInternalErrorf("iceberg tables with delete files are not supported")
+			wrapped.AddDetail("uri", r.dir.ToURI())
+			return nil, wrapped
+		}
+		manifestPath, _ := manifest["manifest_path"].(string)
+		entries, err := r.readAvro(metadata.Location, manifestPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			entry, _ := e.(map[string]interface{})
+			if status, _ := entry["status"].(int32); status == icebergEntryDeleted {
+				continue
+			}
+			dataFile, _ := entry["data_file"].(map[string]interface{})
+			if content, ok := dataFile["content"].(int32); ok && content != icebergContentData {
+				wrapped := fferr.NewInternalErrorf("iceberg tables with delete files are not supported")
+				wrapped.AddDetail("uri", r.dir.ToURI())
+				return nil, wrapped
+			}
+			if format, _ := dataFile["file_format"].(string); !strings.EqualFold(format, icebergParquetFormat) {
+				wrapped := fferr.NewInternalErrorf("iceberg data file format %q is not supported", format)
+				wrapped.AddDetail("uri", r.dir.ToURI())
+				return nil, wrapped
+			}
+			filePath, _ := dataFile["file_path"].(string)
+			file, err := tableFilePath(r.store, r.dir, metadata.Location, filePath)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// readAvro returns the records of an Avro object container file, such as a manifest list or
+// manifest. Records are decoded to map[string]interface{}, and non-null union values to
+// map[string]interface{}{branch: value}.
+func (r *icebergTableReader) readAvro(root, file string) ([]interface{}, error) {
+	fp, err := tableFilePath(r.store, r.dir, root, file)
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.store.Read(fp)
+	if err != nil {
+		return nil, err
+	}
+	wrapErr := func(err error) error {
+		wrapped := fferr.NewParsingError(fmt.Errorf("could not read iceberg avro file: %w", err))
+		wrapped.AddDetail("uri", fp.ToURI())
+		return wrapped
+	}
+	ocf, err := goavro.NewOCFReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	records := []interface{}{}
+	for ocf.Scan() {
+		record, err := ocf.Read()
+		if err != nil {
+			return nil, wrapErr(err)
+		}
+		records = append(records, record)
+	}
+	if err := ocf.Err(); err != nil {
+		return nil, wrapErr(err)
+	}
+	return records, nil
+}
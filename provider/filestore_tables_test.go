@@ -0,0 +1,425 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/featureform/filestore"
+)
+
+type tableTestRow struct {
+	Entity string `parquet:"entity"`
+	Value  int64  `parquet:"value"`
+}
+
+// tableTestStore returns a local file store rooted at a temp dir and the table directory in it.
+func tableTestStore(t *testing.T) (FileStore, string, filestore.Filepath) {
+	root := t.TempDir()
+	store, err := NewLocalFileStore([]byte(fmt.Sprintf(`{"DirPath": "file://%s/"}`, root)))
+	if err != nil {
+		t.Fatalf("Failed to create local file store: %v", err)
+	}
+	dir, err := store.CreateFilePath("featureform/Transformation/tbl/v1", true)
+	if err != nil {
+		t.Fatalf("Failed to create table path: %v", err)
+	}
+	return store, filepath.Join(root, "featureform/Transformation/tbl/v1"), dir
+}
+
+func writeTableTestFile(t *testing.T, dir, rel string, data []byte) {
+	full := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(full, data, 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", rel, err)
+	}
+}
+
+func writeTableTestParquet(t *testing.T, dir, rel string, entities ...string) {
+	rows := make([]tableTestRow, len(entities))
+	for i, e := range entities {
+		rows[i] = tableTestRow{Entity: e, Value: int64(i)}
+	}
+	buf := new(bytes.Buffer)
+	if err := parquet.Write(buf, rows); err != nil {
+		t.Fatalf("Failed to write parquet: %v", err)
+	}
+	writeTableTestFile(t, dir, rel, buf.Bytes())
+}
+
+func tableTestFileNames(t *testing.T, files []filestore.Filepath) []string {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = filepath.Base(f.Key())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func tableTestEntities(t *testing.T, iter Iterator) []string {
+	entities := []string{}
+	for {
+		row, err := iter.Next()
+		if err != nil {
+			t.Fatalf("Failed to read row: %v", err)
+		}
+		if row == nil {
+			break
+		}
+		entities = append(entities, fmt.Sprintf("%v", row["entity"]))
+	}
+	sort.Strings(entities)
+	return entities
+}
+
+func deltaCommitName(version int) string {
+	return fmt.Sprintf("_delta_log/%020d.json", version)
+}
+
+// deltaCheckpointTestRow mimics a Spark-written checkpoint, which has more actions and fields
+// than the reader decodes.
+type deltaCheckpointTestRow struct {
+	Txn *struct {
+		AppID   string `parquet:"appId"`
+		Version int64  `parquet:"version"`
+	} `parquet:"txn,optional"`
+	Add *struct {
+		Path             string            `parquet:"path"`
+		PartitionValues  map[string]string `parquet:"partitionValues"`
+		Size             int64             `parquet:"size"`
+		ModificationTime int64             `parquet:"modificationTime"`
+		DataChange       bool              `parquet:"dataChange"`
+	} `parquet:"add,optional"`
+	Remove *struct {
+		Path              string `parquet:"path"`
+		DeletionTimestamp int64  `parquet:"deletionTimestamp"`
+	} `parquet:"remove,optional"`
+	MetaData *struct {
+		ID               string   `parquet:"id"`
+		PartitionColumns []string `parquet:"partitionColumns,list"`
+	} `parquet:"metaData,optional"`
+}
+
+func writeDeltaTestTable(t *testing.T, root string) {
+	writeTableTestParquet(t, root, "part-0000-a.parquet", "a1", "a2")
+	writeTableTestParquet(t, root, "part-0000-b.parquet", "b1")
+	writeTableTestParquet(t, root, "part 0000-c.parquet", "c1")
+	writeTableTestParquet(t, root, "part-0000-d.parquet", "d1", "d2")
+	commits := []string{
+		`{"protocol":{"minReaderVersion":1,"minWriterVersion":2}}
+{"metaData":{"id":"x","format":{"provider":"parquet"},"partitionColumns":[]}}
+{"add":{"path":"part-0000-a.parquet","size":1,"dataChange":true}}`,
+		// An overwrite: a is replaced by b.
+		`{"commitInfo":{"operation":"WRITE"}}
+{"remove":{"path":"part-0000-a.parquet","dataChange":true}}
+{"add":{"path":"part-0000-b.parquet","size":1,"dataChange":true}}`,
+		// Paths in the log are URL-encoded.
+		`{"add":{"path":"part%200000-c.parquet","size":1,"dataChange":true}}`,
+		`{"add":{"path":"part-0000-d.parquet","size":1,"dataChange":true}}`,
+	}
+	for i, c := range commits {
+		writeTableTestFile(t, root, deltaCommitName(i), []byte(c))
+	}
+}
+
+func TestDeltaTableReader(t *testing.T) {
+	store, root, dir := tableTestStore(t)
+	writeDeltaTestTable(t, root)
+
+	format, err := DetectFileStoreTableFormat(store, dir)
+	if err != nil {
+		t.Fatalf("Failed to detect table format: %v", err)
+	}
+	if format != filestore.Delta {
+		t.Fatalf("Expected delta table, got %q", format)
+	}
+	reader, err := NewFileStoreTableReader(store, dir, format)
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+	latest, err := reader.LatestVersion()
+	if err != nil {
+		t.Fatalf("Failed to get latest version: %v", err)
+	}
+	if latest != 3 {
+		t.Fatalf("Expected latest version 3, got %d", latest)
+	}
+	tests := map[int64][]string{
+		0:                  {"part-0000-a.parquet"},
+		1:                  {"part-0000-b.parquet"},
+		2:                  {"part 0000-c.parquet", "part-0000-b.parquet"},
+		LatestTableVersion: {"part 0000-c.parquet", "part-0000-b.parquet", "part-0000-d.parquet"},
+	}
+	for version, expected := range tests {
+		files, err := reader.DataFiles(version)
+		if err != nil {
+			t.Fatalf("Failed to get data files at version %d: %v", version, err)
+		}
+		if got := tableTestFileNames(t, files); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("Version %d: expected %v, got %v", version, expected, got)
+		}
+	}
+
+	iter, err := ServeFileStoreTable(store, dir, filestore.Delta, 1)
+	if err != nil {
+		t.Fatalf("Failed to serve table: %v", err)
+	}
+	if got := tableTestEntities(t, iter); !reflect.DeepEqual(got, []string{"b1"}) {
+		t.Fatalf("Expected [b1] at version 1, got %v", got)
+	}
+	files, err := fileStoreNewestFiles(store, dir)
+	if err != nil {
+		t.Fatalf("Failed to get newest files: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 newest files, got %v", tableTestFileNames(t, files))
+	}
+}
+
+func TestDeltaTableReaderCheckpoint(t *testing.T) {
+	store, root, dir := tableTestStore(t)
+	writeDeltaTestTable(t, root)
+	// Checkpoint version 2 and drop the commits before it, as log cleanup would.
+	rows := []deltaCheckpointTestRow{{}, {}, {}}
+	rows[0].MetaData = &struct {
+		ID               string   `parquet:"id"`
+		PartitionColumns []string `parquet:"partitionColumns,list"`
+	}{ID: "x", PartitionColumns: []string{}}
+	for i, p := range []string{"part-0000-b.parquet", "part%200000-c.parquet"} {
+		rows[i+1].Add = &struct {
+			Path             string            `parquet:"path"`
+			PartitionValues  map[string]string `parquet:"partitionValues"`
+			Size             int64             `parquet:"size"`
+			ModificationTime int64             `parquet:"modificationTime"`
+			DataChange       bool              `parquet:"dataChange"`
+		}{Path: p, PartitionValues: map[string]string{}, Size: 1}
+	}
+	buf := new(bytes.Buffer)
+	if err := parquet.Write(buf, rows); err != nil {
+		t.Fatalf("Failed to write checkpoint: %v", err)
+	}
+	writeTableTestFile(t, root, fmt.Sprintf("_delta_log/%020d.checkpoint.parquet", 2), buf.Bytes())
+	for i := 0; i <= 2; i++ {
+		if err := os.Remove(filepath.Join(root, deltaCommitName(i))); err != nil {
+			t.Fatalf("Failed to remove commit: %v", err)
+		}
+	}
+
+	reader, err := NewFileStoreTableReader(store, dir, filestore.Delta)
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+	files, err := reader.DataFiles(LatestTableVersion)
+	if err != nil {
+		t.Fatalf("Failed to get data files: %v", err)
+	}
+	expected := []string{"part 0000-c.parquet", "part-0000-b.parquet", "part-0000-d.parquet"}
+	if got := tableTestFileNames(t, files); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	if _, err := reader.DataFiles(1); err == nil {
+		t.Fatalf("Expected error reading a version whose commits were cleaned up")
+	}
+}
+
+func TestDeltaTableReaderUnsupported(t *testing.T) {
+	tests := map[string]string{
+		"Partitioned": `{"metaData":{"id":"x","partitionColumns":["day"]}}
+{"add":{"path":"day=1/part-0000-a.parquet","partitionValues":{"day":"1"}}}`,
+		"DeletionVectors": `{"add":{"path":"part-0000-a.parquet","deletionVector":{"storageType":"u","pathOrInlineDv":"x","sizeInBytes":1,"cardinality":1}}}`,
+	}
+	for name, commit := range tests {
+		t.Run(name, func(t *testing.T) {
+			store, root, dir := tableTestStore(t)
+			writeTableTestFile(t, root, deltaCommitName(0), []byte(commit))
+			reader, err := NewFileStoreTableReader(store, dir, filestore.Delta)
+			if err != nil {
+				t.Fatalf("Failed to create reader: %v", err)
+			}
+			if _, err := reader.DataFiles(LatestTableVersion); err == nil {
+				t.Fatalf("Expected error")
+			}
+		})
+	}
+}
+
+const (
+	icebergTestLocation       = "s3a://bucket/warehouse/tbl"
+	icebergTestManifestList   = `{"type":"record","name":"manifest_file","fields":[{"name":"manifest_path","type":"string"},{"name":"content","type":"int"}]}`
+	icebergTestManifestSchema = `{"type":"record","name":"manifest_entry","fields":[
+		{"name":"status","type":"int"},
+		{"name":"snapshot_id","type":["null","long"]},
+		{"name":"data_file","type":{"type":"record","name":"r2","fields":[
+			{"name":"content","type":"int"},
+			{"name":"file_path","type":"string"},
+			{"name":"file_format","type":"string"},
+			{"name":"record_count","type":"long"}
+		]}}
+	]}`
+)
+
+func writeIcebergTestAvro(t *testing.T, root, rel, schemaJSON string, records []interface{}) {
+	buf := new(bytes.Buffer)
+	writer, err := goavro.NewOCFWriter(goavro.OCFConfig{W: buf, Schema: schemaJSON, CompressionName: goavro.CompressionDeflateLabel})
+	if err != nil {
+		t.Fatalf("Failed to create avro writer: %v", err)
+	}
+	if err := writer.Append(records); err != nil {
+		t.Fatalf("Failed to write avro: %v", err)
+	}
+	writeTableTestFile(t, root, rel, buf.Bytes())
+}
+
+func icebergTestEntry(status int32, content int32, file string) interface{} {
+	return map[string]interface{}{
+		"status":      status,
+		"snapshot_id": goavro.Union("long", int64(1)),
+		"data_file": map[string]interface{}{
+			"content":      content,
+			"file_path":    icebergTestLocation + "/data/" + file,
+			"file_format":  "PARQUET",
+			"record_count": int64(1),
+		},
+	}
+}
+
+func icebergTestMetadata(current int64, snapshots ...int64) string {
+	snaps := make([]string, len(snapshots))
+	for i, s := range snapshots {
+		snaps[i] = fmt.Sprintf(`{"snapshot-id":%d,"timestamp-ms":%d,"manifest-list":"%s/metadata/snap-%d.avro"}`, s, 1000*s, icebergTestLocation, s)
+	}
+	return fmt.Sprintf(`{"format-version":2,"location":"%s","current-snapshot-id":%d,"snapshots":[%s]}`,
+		icebergTestLocation, current, strings.Join(snaps, ","))
+}
+
+func TestIcebergTableReader(t *testing.T) {
+	store, root, dir := tableTestStore(t)
+	writeTableTestParquet(t, root, "data/a.parquet", "a1")
+	writeTableTestParquet(t, root, "data/b.parquet", "b1", "b2")
+	writeTableTestParquet(t, root, "data/c.parquet", "c1")
+
+	// Snapshot 1 adds a and b. Snapshot 2 deletes a and adds c, keeping b in a new manifest.
+	writeIcebergTestAvro(t, root, "metadata/m1.avro", icebergTestManifestSchema, []interface{}{
+		icebergTestEntry(1, 0, "a.parquet"),
+		icebergTestEntry(1, 0, "b.parquet"),
+	})
+	writeIcebergTestAvro(t, root, "metadata/m2.avro", icebergTestManifestSchema, []interface{}{
+		icebergTestEntry(2, 0, "a.parquet"),
+		icebergTestEntry(0, 0, "b.parquet"),
+		icebergTestEntry(1, 0, "c.parquet"),
+	})
+	writeIcebergTestAvro(t, root, "metadata/snap-1.avro", icebergTestManifestList, []interface{}{
+		map[string]interface{}{"manifest_path": icebergTestLocation + "/metadata/m1.avro", "content": int32(0)},
+	})
+	writeIcebergTestAvro(t, root, "metadata/snap-2.avro", icebergTestManifestList, []interface{}{
+		map[string]interface{}{"manifest_path": icebergTestLocation + "/metadata/m2.avro", "content": int32(0)},
+	})
+	writeTableTestFile(t, root, "metadata/00001-aaa.metadata.json", []byte(icebergTestMetadata(1, 1)))
+	writeTableTestFile(t, root, "metadata/00002-bbb.metadata.json", []byte(icebergTestMetadata(2, 1, 2)))
+
+	format, err := DetectFileStoreTableFormat(store, dir)
+	if err != nil {
+		t.Fatalf("Failed to detect table format: %v", err)
+	}
+	if format != filestore.Iceberg {
+		t.Fatalf("Expected iceberg table, got %q", format)
+	}
+	reader, err := NewFileStoreTableReader(store, dir, format)
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+	latest, err := reader.LatestVersion()
+	if err != nil {
+		t.Fatalf("Failed to get latest version: %v", err)
+	}
+	if latest != 2 {
+		t.Fatalf("Expected current snapshot 2, got %d", latest)
+	}
+	tests := map[int64][]string{
+		1:                  {"a.parquet", "b.parquet"},
+		2:                  {"b.parquet", "c.parquet"},
+		LatestTableVersion: {"b.parquet", "c.parquet"},
+	}
+	for version, expected := range tests {
+		files, err := reader.DataFiles(version)
+		if err != nil {
+			t.Fatalf("Failed to get data files at snapshot %d: %v", version, err)
+		}
+		if got := tableTestFileNames(t, files); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("Snapshot %d: expected %v, got %v", version, expected, got)
+		}
+	}
+	if _, err := reader.DataFiles(3); err == nil {
+		t.Fatalf("Expected error for unknown snapshot")
+	}
+
+	iter, err := ServeFileStoreTable(store, dir, filestore.Iceberg, 1)
+	if err != nil {
+		t.Fatalf("Failed to serve table: %v", err)
+	}
+	if got := tableTestEntities(t, iter); !reflect.DeepEqual(got, []string{"a1", "b1", "b2"}) {
+		t.Fatalf("Expected [a1 b1 b2] at snapshot 1, got %v", got)
+	}
+
+	// A version hint takes precedence over file names.
+	writeTableTestFile(t, root, "metadata/version-hint.text", []byte("1\n"))
+	writeTableTestFile(t, root, "metadata/v1.metadata.json", []byte(icebergTestMetadata(1, 1)))
+	if latest, err := reader.LatestVersion(); err != nil || latest != 1 {
+		t.Fatalf("Expected snapshot 1 from version hint, got %d: %v", latest, err)
+	}
+}
+
+func TestIcebergTableReaderDeleteFiles(t *testing.T) {
+	store, root, dir := tableTestStore(t)
+	writeIcebergTestAvro(t, root, "metadata/m1.avro", icebergTestManifestSchema, []interface{}{
+		icebergTestEntry(1, 1, "a-deletes.parquet"),
+	})
+	writeIcebergTestAvro(t, root, "metadata/snap-1.avro", icebergTestManifestList, []interface{}{
+		map[string]interface{}{"manifest_path": icebergTestLocation + "/metadata/m1.avro", "content": int32(0)},
+	})
+	writeTableTestFile(t, root, "metadata/v1.metadata.json", []byte(icebergTestMetadata(1, 1)))
+	reader, err := NewFileStoreTableReader(store, dir, filestore.Iceberg)
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+	if _, err := reader.DataFiles(LatestTableVersion); err == nil {
+		t.Fatalf("Expected error for delete files")
+	}
+}
+
+func TestDetectFileStoreTableFormatPlainFiles(t *testing.T) {
+	store, root, dir := tableTestStore(t)
+	writeTableTestParquet(t, root, "2024-01-01-00-00-00-000000/part-0000.parquet", "old")
+	writeTableTestParquet(t, root, "2024-06-01-00-00-00-000000/part-0000.parquet", "new")
+	format, err := DetectFileStoreTableFormat(store, dir)
+	if err != nil {
+		t.Fatalf("Failed to detect table format: %v", err)
+	}
+	if format != "" {
+		t.Fatalf("Expected plain files, got %q", format)
+	}
+	files, err := fileStoreNewestFiles(store, dir)
+	if err != nil {
+		t.Fatalf("Failed to get newest files: %v", err)
+	}
+	if len(files) != 1 || !strings.Contains(files[0].Key(), "2024-06-01") {
+		t.Fatalf("Expected the newest run's file, got %v", files)
+	}
+}
@@ -343,11 +343,15 @@ func (store *firestoreOnlineStore) DeleteTable(feature, variant string) error {
 }
 
 func (store *firestoreOnlineStore) CheckHealth() (bool, error) {
+	return store.CheckHealthContext(context.Background())
+}
+
+func (store *firestoreOnlineStore) CheckHealthContext(ctx context.Context) (bool, error) {
 	// We need to check whether we can reach Firestore. The simplest way of doing this is
 	// by getting a random collection (by calling `Collections().Next()`). However, this
 	// is still able to throw an iterator.Done error if there are no collections,
 	// so we just check for that.
-	_, err := store.client.Collections(context.TODO()).Next()
+	_, err := store.client.Collections(ctx).Next()
 	if err != nil && !errors.Is(err, iterator.Done) {
 		store.logger.Error("Health check failed, unable to connect to firestore")
 		return false, fferr.NewExecutionError(pt.FirestoreOnline.String(), err)
@@ -0,0 +1,169 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/featureform/fferr"
+	pt "github.com/featureform/provider/provider_type"
+	sf "github.com/snowflakedb/gosnowflake"
+)
+
+// IncrementalConfig describes how the rows of an incremental transformation run are written. The
+// query only returns rows that are new since the last run, so they're merged into the existing
+// table rather than replacing it.
+type IncrementalConfig struct {
+	// MergeKeys are the columns that identify a row. A new row replaces the existing rows with
+	// the same keys; without keys, new rows are appended.
+	MergeKeys []string
+	// TimestampColumn is the transformation's event time column, used to clear a backfilled range.
+	TimestampColumn string
+	// BackfillStart and BackfillEnd are set when rebuilding [BackfillStart, BackfillEnd); the
+	// existing rows in that range are deleted before the new ones are written.
+	BackfillStart time.Time
+	BackfillEnd   time.Time
+	// StreamSources and StreamTemplate are only set when Snowflake streams the incremental
+	// sources' new rows into the transformation. StreamTemplate is the query with the stream
+	// sources' placeholders left in, so they can be filled in with either the tables or streams.
+	StreamSources  []StreamSource
+	StreamTemplate string
+}
+
+// StreamSource is an incremental source that Snowflake streams into a transformation.
+type StreamSource struct {
+	// Key is the source's name.variant, as written in the placeholder of the stream template.
+	Key string
+	// Table is the source's table.
+	Table string
+}
+
+func (c IncrementalConfig) IsBackfill() bool {
+	return !c.BackfillEnd.IsZero()
+}
+
+func (c IncrementalConfig) check() error {
+	if !c.IsBackfill() {
+		return nil
+	}
+	if c.TimestampColumn == "" {
+		return fferr.NewInvalidArgumentErrorf("backfilling an incremental transformation requires a timestamp column")
+	}
+	if !c.BackfillStart.Before(c.BackfillEnd) {
+		return fferr.NewInvalidArgumentErrorf("backfill start %s must be before end %s", c.BackfillStart, c.BackfillEnd)
+	}
+	return nil
+}
+
+// SanitizeIncrementalColumn quotes a timestamp column for the SQL dialect of stores of type t,
+// the same way the store quotes it when it reads the column's watermark.
+func SanitizeIncrementalColumn(t pt.Type, column string) string {
+	switch t {
+	case pt.BigQueryOffline, pt.SparkOffline:
+		return "`" + strings.ReplaceAll(column, "`", "") + "`"
+	case pt.ClickHouseOffline:
+		return SanitizeClickHouseIdentifier(column)
+	default:
+		return sanitize(column)
+	}
+}
+
+// SourceWatermarker is implemented by offline stores that can find the latest timestamp in a
+// source. Incremental transformations use it to record how far each source has been read.
+type SourceWatermarker interface {
+	// SourceWatermark returns the latest value of timestampColumn in table, or the zero time if
+	// the table is empty. table is written the way it's referenced in the store's queries.
+	SourceWatermark(table, timestampColumn string) (time.Time, error)
+}
+
+// IncrementalTimestampFormat is the format of the timestamp literals in incremental queries. It's
+// a bare literal since MySQL can't CAST to TIMESTAMP; every dialect coerces it to the column's type.
+const IncrementalTimestampFormat = "2006-01-02 15:04:05.999999"
+
+func incrementalTimestamp(t time.Time) string {
+	return fmt.Sprintf("'%s'", t.UTC().Format(IncrementalTimestampFormat))
+}
+
+// incrementalBackfillDelete deletes the rows of tableName in the config's backfill range.
+func incrementalBackfillDelete(tableName string, config IncrementalConfig) string {
+	ts := sanitize(config.TimestampColumn)
+	return fmt.Sprintf(
+		"DELETE FROM %s WHERE %s >= %s AND %s < %s",
+		tableName, ts, incrementalTimestamp(config.BackfillStart), ts, incrementalTimestamp(config.BackfillEnd),
+	)
+}
+
+// incrementalMergeKeyMatch matches the rows of two tables, or aliases, with the same merge keys.
+func incrementalMergeKeyMatch(target, source string, keys []string) string {
+	conditions := make([]string, len(keys))
+	for i, key := range keys {
+		k := sanitize(key)
+		conditions[i] = fmt.Sprintf("%s.%s = %s.%s", target, k, source, k)
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// transformationIncrementalUpdate writes the rows of query into tableName in a single transaction.
+// Merge keys are handled by deleting the matching rows, rather than with MERGE, so it works on
+// every dialect built on defaultOfflineSQLQueries.
+func (q defaultOfflineSQLQueries) transformationIncrementalUpdate(db *sql.DB, tableName string, query string, config IncrementalConfig) error {
+	statements := q.incrementalUpdateStatements(tableName, query, config)
+	transaction := fmt.Sprintf("BEGIN TRANSACTION;%s;COMMIT;", strings.Join(statements, ";"))
+	// Gets around the fact that the go redshift driver doesn't support multi statement trx queries
+	stmt, _ := sf.WithMultiStatement(context.TODO(), len(statements)+2)
+	if _, err := db.QueryContext(stmt, transaction); err != nil {
+		wrapped := fferr.NewExecutionError("SQL", err)
+		wrapped.AddDetail("table_name", tableName)
+		return wrapped
+	}
+	return nil
+}
+
+func (q defaultOfflineSQLQueries) incrementalUpdateStatements(tableName string, query string, config IncrementalConfig) []string {
+	sanitizedTable := sanitize(tableName)
+	statements := make([]string, 0)
+	if config.IsBackfill() {
+		statements = append(statements, incrementalBackfillDelete(sanitizedTable, config))
+	}
+	if len(config.MergeKeys) == 0 {
+		return append(statements, fmt.Sprintf("INSERT INTO %s SELECT * FROM ( %s ) incremental", sanitizedTable, query))
+	}
+	tempName := sanitize(fmt.Sprintf("tmp_%s", tableName))
+	return append(statements,
+		fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM ( %s ) incremental", tempName, query),
+		fmt.Sprintf(
+			"DELETE FROM %s WHERE EXISTS (SELECT 1 FROM %s WHERE %s)",
+			sanitizedTable, tempName, incrementalMergeKeyMatch(tempName, sanitizedTable, config.MergeKeys),
+		),
+		fmt.Sprintf("INSERT INTO %s SELECT * FROM %s", sanitizedTable, tempName),
+		fmt.Sprintf("DROP TABLE %s", tempName),
+	)
+}
+
+// SourceWatermark satisfies SourceWatermarker.
+func (store *sqlOfflineStore) SourceWatermark(table, timestampColumn string) (time.Time, error) {
+	query := fmt.Sprintf("SELECT MAX(%s) FROM %s", sanitize(timestampColumn), table)
+	return scanSourceWatermark(store.db.QueryRow(query), store.Type().String(), table)
+}
+
+func scanSourceWatermark(row *sql.Row, providerType, table string) (time.Time, error) {
+	var watermark sql.NullTime
+	if err := row.Scan(&watermark); err != nil {
+		wrapped := fferr.NewExecutionError(providerType, err)
+		wrapped.AddDetail("table_name", table)
+		return time.Time{}, wrapped
+	}
+	if !watermark.Valid {
+		return time.Time{}, nil
+	}
+	return watermark.Time.UTC(), nil
+}
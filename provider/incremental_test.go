@@ -0,0 +1,162 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	incrementalTestStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	incrementalTestEnd   = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+)
+
+func TestIncrementalConfigCheck(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  IncrementalConfig
+		isValid bool
+	}{
+		{"append", IncrementalConfig{}, true},
+		{"merge", IncrementalConfig{MergeKeys: []string{"id"}}, true},
+		{"backfill", IncrementalConfig{TimestampColumn: "ts", BackfillStart: incrementalTestStart, BackfillEnd: incrementalTestEnd}, true},
+		{"backfill without timestamp column", IncrementalConfig{BackfillStart: incrementalTestStart, BackfillEnd: incrementalTestEnd}, false},
+		{"backfill ending before it starts", IncrementalConfig{TimestampColumn: "ts", BackfillStart: incrementalTestEnd, BackfillEnd: incrementalTestStart}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.check()
+			if tc.isValid && err != nil {
+				t.Fatalf("Expected config to be valid: %v", err)
+			}
+			if !tc.isValid && err == nil {
+				t.Fatalf("Expected config to be invalid")
+			}
+		})
+	}
+}
+
+func TestTransformationConfigIncrementalJSON(t *testing.T) {
+	config := TransformationConfig{
+		Type:  SQLTransformation,
+		Query: "SELECT * FROM events",
+		Incremental: &IncrementalConfig{
+			MergeKeys:       []string{"id"},
+			TimestampColumn: "ts",
+			BackfillStart:   incrementalTestStart,
+			BackfillEnd:     incrementalTestEnd,
+		},
+	}
+	serialized, err := json.Marshal(&config)
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+	var deserialized TransformationConfig
+	if err := json.Unmarshal(serialized, &deserialized); err != nil {
+		t.Fatalf("Failed to unmarshal config: %v", err)
+	}
+	if !reflect.DeepEqual(config.Incremental, deserialized.Incremental) {
+		t.Fatalf("Expected %+v, got %+v", config.Incremental, deserialized.Incremental)
+	}
+}
+
+func TestIncrementalUpdateStatements(t *testing.T) {
+	backfill := IncrementalConfig{TimestampColumn: "ts", BackfillStart: incrementalTestStart, BackfillEnd: incrementalTestEnd}
+	testCases := []struct {
+		name     string
+		config   IncrementalConfig
+		expected []string
+	}{
+		{
+			name:   "append",
+			config: IncrementalConfig{},
+			expected: []string{
+				`INSERT INTO "tbl" SELECT * FROM ( SELECT * FROM src ) incremental`,
+			},
+		},
+		{
+			name:   "merge",
+			config: IncrementalConfig{MergeKeys: []string{"id", "day"}},
+			expected: []string{
+				`CREATE TABLE "tmp_tbl" AS SELECT * FROM ( SELECT * FROM src ) incremental`,
+				`DELETE FROM "tbl" WHERE EXISTS (SELECT 1 FROM "tmp_tbl" WHERE "tmp_tbl"."id" = "tbl"."id" AND "tmp_tbl"."day" = "tbl"."day")`,
+				`INSERT INTO "tbl" SELECT * FROM "tmp_tbl"`,
+				`DROP TABLE "tmp_tbl"`,
+			},
+		},
+		{
+			name:   "backfill",
+			config: backfill,
+			expected: []string{
+				`DELETE FROM "tbl" WHERE "ts" >= '2024-01-01 00:00:00' AND "ts" < '2024-01-02 00:00:00'`,
+				`INSERT INTO "tbl" SELECT * FROM ( SELECT * FROM src ) incremental`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			statements := defaultOfflineSQLQueries{}.incrementalUpdateStatements("tbl", "SELECT * FROM src", tc.config)
+			if !reflect.DeepEqual(statements, tc.expected) {
+				t.Fatalf("Expected:\n%v\ngot:\n%v", tc.expected, statements)
+			}
+		})
+	}
+}
+
+func TestClickHouseIncrementalUpdateStatements(t *testing.T) {
+	config := IncrementalConfig{
+		MergeKeys:       []string{"id"},
+		TimestampColumn: "ts",
+		BackfillStart:   incrementalTestStart,
+		BackfillEnd:     incrementalTestEnd,
+	}
+	expected := []string{
+		"ALTER TABLE `tbl` DELETE WHERE `ts` >= '2024-01-01 00:00:00' AND `ts` < '2024-01-02 00:00:00' SETTINGS mutations_sync = 2",
+		"CREATE TABLE `tmp_tbl` ENGINE = MergeTree ORDER BY tuple() EMPTY AS SELECT * FROM src",
+		"INSERT INTO `tmp_tbl` SELECT * FROM src",
+		"ALTER TABLE `tbl` DELETE WHERE (`id`) IN (SELECT `id` FROM `tmp_tbl`) SETTINGS mutations_sync = 2",
+		"INSERT INTO `tbl` SELECT * FROM `tmp_tbl`",
+		"DROP TABLE `tmp_tbl`",
+	}
+	statements := clickhouseSQLQueries{}.incrementalUpdateStatements("tbl", "SELECT * FROM src", config)
+	if !reflect.DeepEqual(statements, expected) {
+		t.Fatalf("Expected:\n%v\ngot:\n%v", expected, statements)
+	}
+}
+
+func TestSnowflakeIncrementalMerge(t *testing.T) {
+	columns := []TableColumn{{Name: "id"}, {Name: "value"}}
+	expected := `MERGE INTO "tbl" target USING ( SELECT * FROM src ) incremental ON target."id" = incremental."id" ` +
+		`WHEN MATCHED THEN UPDATE SET target."id" = incremental."id", target."value" = incremental."value" ` +
+		`WHEN NOT MATCHED THEN INSERT ("id", "value") VALUES (incremental."id", incremental."value")`
+	merge := snowflakeSQLQueries{}.incrementalMerge("tbl", "SELECT * FROM src", columns, []string{"id"})
+	if merge != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, merge)
+	}
+}
+
+func TestBigQueryIncrementalUpdateScript(t *testing.T) {
+	q := defaultBQQueries{ProjectId: "project", DatasetId: "dataset"}
+	columns := []TableColumn{{Name: "id"}, {Name: "value"}}
+	config := IncrementalConfig{
+		MergeKeys:       []string{"id"},
+		TimestampColumn: "ts",
+		BackfillStart:   incrementalTestStart,
+		BackfillEnd:     incrementalTestEnd,
+	}
+	expected := "DELETE FROM `project.dataset.tbl` WHERE `ts` >= '2024-01-01 00:00:00' AND `ts` < '2024-01-02 00:00:00';" +
+		"MERGE `project.dataset.tbl` target USING ( SELECT * FROM src ) incremental ON target.`id` = incremental.`id` " +
+		"WHEN MATCHED THEN UPDATE SET `id` = incremental.`id`, `value` = incremental.`value` WHEN NOT MATCHED THEN INSERT ROW;"
+	script := q.incrementalUpdateScript("tbl", "SELECT * FROM src", columns, config)
+	if script != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, script)
+	}
+}
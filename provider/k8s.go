@@ -18,7 +18,6 @@ import (
 	"time"
 
 	"github.com/parquet-go/parquet-go"
-	"github.com/pkg/errors"
 
 	"github.com/featureform/metadata"
 	"github.com/featureform/provider/dataset"
@@ -31,6 +30,7 @@ import (
 
 	cfg "github.com/featureform/config"
 	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
 	"github.com/featureform/filestore"
 	"github.com/featureform/kubernetes"
 	"github.com/featureform/logging"
@@ -108,6 +108,8 @@ type K8sOfflineStore struct {
 	store    FileStore
 	logger   *zap.SugaredLogger
 	query    *pandasOfflineQueries
+	// tableFormat is the format resources are written in; empty for plain Parquet files.
+	tableFormat pc.TableFormat
 	BaseProvider
 }
 
@@ -233,10 +235,11 @@ func k8sOfflineStoreFactory(config pc.SerializedConfig) (Provider, error) {
 	logger.Debugf("Store type: %s", k8.StoreType)
 	queries := pandasOfflineQueries{}
 	k8sOfflineStore := K8sOfflineStore{
-		executor: executor,
-		store:    store,
-		logger:   logger.SugaredLogger,
-		query:    &queries,
+		executor:    executor,
+		store:       store,
+		logger:      logger.SugaredLogger,
+		query:       &queries,
+		tableFormat: k8.FileStoreTableFormat,
 		BaseProvider: BaseProvider{
 			ProviderType:   "K8S_OFFLINE",
 			ProviderConfig: config,
@@ -627,6 +630,9 @@ func (k8s *K8sOfflineStore) pandasRunnerArgs(outputURI string, updatedQuery stri
 		"TRANSFORMATION_TYPE": "sql",
 		"TRANSFORMATION":      updatedQuery,
 	}
+	if k8s.tableFormat != "" {
+		envVars["OUTPUT_TABLE_FORMAT"] = string(k8s.tableFormat)
+	}
 	envVars = k8s.store.AddEnvVars(envVars)
 	return envVars
 }
@@ -639,6 +645,9 @@ func (k8s K8sOfflineStore) getDFArgs(outputURI string, code string, mapping []So
 		"TRANSFORMATION_TYPE": "df",
 		"TRANSFORMATION":      code,
 	}
+	if k8s.tableFormat != "" {
+		envVars["OUTPUT_TABLE_FORMAT"] = string(k8s.tableFormat)
+	}
 	envVars = k8s.store.AddEnvVars(envVars)
 	return envVars
 }
@@ -804,7 +813,7 @@ func (k8s *K8sOfflineStore) getSourcePath(path string) (string, error) {
 		}
 		k8s.logger.Debugw("Retrieved transformation source", "ResourceId", fileResourceId, "fileResourcePath", fileResourcePath)
 		// get file type of source
-		exactFileResourcePath, err := k8s.store.NewestFileOfType(fileResourcePath, fileResourcePath.Ext())
+		exactFileResourcePath, err := k8s.newestSourcePath(fileResourcePath)
 		k8s.logger.Debugw("Retrieved latest file path", "exactFileResourcePath", exactFileResourcePath)
 		if err != nil {
 			k8s.logger.Errorw("Could not get newest blob", "location", fileResourcePath, "error", err)
@@ -825,6 +834,23 @@ func (k8s *K8sOfflineStore) getSourcePath(path string) (string, error) {
 	}
 }
 
+// newestSourcePath returns the path the Pandas runner should read a source from: the table
+// directory for Delta tables, which the runner reads through the table log, or else the newest file.
+func (k8s *K8sOfflineStore) newestSourcePath(path filestore.Filepath) (filestore.Filepath, error) {
+	format, err := DetectFileStoreTableFormat(k8s.store, path)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "":
+		return k8s.store.NewestFileOfType(path, path.Ext())
+	case filestore.Delta:
+		return path, nil
+	default:
+		return nil, fferr.NewInvalidArgumentErrorf("%s sources can't be read by %s", format, k8s.Type())
+	}
+}
+
 func (k8s *K8sOfflineStore) getResourceInformationFromFilePath(path string) (string, string, string) {
 	var fileType string
 	var fileName string
@@ -998,21 +1024,7 @@ func (k8s *K8sOfflineStore) GetMaterialization(id MaterializationID) (dataset.Ma
 }
 
 func (k8s *K8sOfflineStore) ResourceLocation(id ResourceID, resource any) (pl.Location, error) {
-	path, err := k8s.store.CreateFilePath(id.ToFilestorePath(), true)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not create dir path")
-	}
-
-	newestFile, err := k8s.store.NewestFileOfType(path, filestore.Parquet)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not get newest file")
-	}
-
-	newestDirPathDateTime, err := k8s.store.CreateFilePath(newestFile.KeyPrefix(), true)
-	if err != nil {
-		return nil, fmt.Errorf("could not create directory path for k8s newest file: %v", err)
-	}
-	return pl.NewFileLocation(newestDirPathDateTime), nil
+	return fileStoreResourceLocation(k8s.store, id)
 }
 
 func fileStoreGetMaterialization(id MaterializationID, store FileStore, logger *zap.SugaredLogger) (Materialization, error) {
@@ -1037,35 +1049,34 @@ func (mat FileStoreMaterialization) ID() MaterializationID {
 }
 
 func (mat FileStoreMaterialization) NumRows() (int64, error) {
-	resourceKey := ps.ResourceToDirectoryPath(mat.id.Type.String(), mat.id.Name, mat.id.Variant)
-	materializationFilepath, err := mat.store.CreateFilePath(resourceKey, false)
+	newestFiles, err := mat.newestFiles()
 	if err != nil {
 		return 0, err
 	}
-	// TODO(simba) THIS IS INCORRECT!
-	latestMaterializationPath, err := mat.store.NewestFileOfType(materializationFilepath, filestore.Parquet)
-	if err != nil {
-		return 0, err
+	total := int64(0)
+	for _, file := range newestFiles {
+		rows, err := mat.store.NumRows(file)
+		if err != nil {
+			return 0, err
+		}
+		total += rows
 	}
-	// TODO: convert NumRows to accept a list of files to get a total count
-	return mat.store.NumRows(latestMaterializationPath)
+	return total, nil
 }
 
-func (mat FileStoreMaterialization) IterateSegment(begin, end int64) (FeatureIterator, error) {
+// newestFiles returns the files of the latest materialization run, or of the latest version of
+// the materialization's table when it's written as Delta or Iceberg.
+func (mat FileStoreMaterialization) newestFiles() ([]filestore.Filepath, error) {
 	resourceKey := ps.ResourceToDirectoryPath(mat.id.Type.String(), mat.id.Name, mat.id.Variant)
 	searchPath, err := mat.store.CreateFilePath(resourceKey, false)
 	if err != nil {
 		return nil, err
 	}
-	files, err := mat.store.List(searchPath, filestore.Parquet)
-	if err != nil {
-		return nil, err
-	}
-	groups, err := filestore.NewFilePathGroup(files, filestore.DateTimeDirectoryGrouping)
-	if err != nil {
-		return nil, err
-	}
-	newestFiles, err := groups.GetFirst()
+	return fileStoreNewestFiles(mat.store, searchPath)
+}
+
+func (mat FileStoreMaterialization) IterateSegment(begin, end int64) (FeatureIterator, error) {
+	newestFiles, err := mat.newestFiles()
 	if err != nil {
 		return nil, err
 	}
@@ -1086,19 +1097,7 @@ func (mat FileStoreMaterialization) IterateSegment(begin, end int64) (FeatureIte
 }
 
 func (mat FileStoreMaterialization) NumChunks() (int, error) {
-	searchPath, err := mat.store.CreateFilePath(ps.ResourceToDirectoryPath(mat.id.Type.String(), mat.id.Name, mat.id.Variant), false)
-	if err != nil {
-		return -1, err
-	}
-	files, err := mat.store.List(searchPath, filestore.Parquet)
-	if err != nil {
-		return -1, err
-	}
-	groups, err := filestore.NewFilePathGroup(files, filestore.DateTimeDirectoryGrouping)
-	if err != nil {
-		return -1, err
-	}
-	newestFiles, err := groups.GetFirst()
+	newestFiles, err := mat.newestFiles()
 	if err != nil {
 		return -1, err
 	}
@@ -1106,19 +1105,7 @@ func (mat FileStoreMaterialization) NumChunks() (int, error) {
 }
 
 func (mat FileStoreMaterialization) IterateChunk(idx int) (FeatureIterator, error) {
-	searchPath, err := mat.store.CreateFilePath(ps.ResourceToDirectoryPath(mat.id.Type.String(), mat.id.Name, mat.id.Variant), false)
-	if err != nil {
-		return nil, err
-	}
-	files, err := mat.store.List(searchPath, filestore.Parquet)
-	if err != nil {
-		return nil, err
-	}
-	groups, err := filestore.NewFilePathGroup(files, filestore.DateTimeDirectoryGrouping)
-	if err != nil {
-		return nil, err
-	}
-	newestFiles, err := groups.GetFirst()
+	newestFiles, err := mat.newestFiles()
 	if err != nil {
 		return nil, err
 	}
@@ -1266,7 +1253,7 @@ func (k8s *K8sOfflineStore) materialization(id ResourceID, isUpdate bool) (Mater
 		return nil, fferr.NewInternalErrorf("source table is not a filestore location")
 	}
 	// get source path file type; note, it's possible it doesn't have it
-	newestSourcePath, err := k8s.store.NewestFileOfType(sourcePath.Filepath(), sourcePath.Filepath().Ext())
+	newestSourcePath, err := k8s.newestSourcePath(sourcePath.Filepath())
 	k8s.logger.Debugw("Retrieved newest source path", "sourcePath", sourcePath, "newestSourcePath", newestSourcePath)
 	if err != nil {
 		k8s.logger.Errorw("Could not determine newest source file for materialization", "sourcePath", sourcePath, "error", err)
@@ -1337,6 +1324,12 @@ func (k8s *K8sOfflineStore) trainingSet(def TrainingSetDef, isUpdate bool) error
 		k8s.logger.Errorw("Training set definition not valid", def, err)
 		return err
 	}
+	if def.Spine != nil {
+		return fferr.NewInvalidArgumentErrorf("%s does not support training sets with a spine", k8s.Type())
+	}
+	if len(def.AdditionalLabels) > 0 {
+		return fferr.NewInvalidArgumentErrorf("%s does not support training sets with multiple labels", k8s.Type())
+	}
 	sourcePaths := make([]string, 0)
 	featureSchemas := make([]ResourceSchema, 0)
 	resourceKey := ps.ResourceToDirectoryPath(def.ID.Type.String(), def.ID.Name, def.ID.Variant)
@@ -1379,7 +1372,7 @@ func (k8s *K8sOfflineStore) trainingSet(def TrainingSetDef, isUpdate bool) error
 		k8s.logger.Errorw("Could not get latest label file", "error", err)
 		return err
 	}
-	sourcePaths = append(sourcePaths, labelPath.Filepath().ToURI())
+	sourcePaths = append(sourcePaths, pinnedSourcePath(def, labelPath))
 	for _, feature := range def.Features {
 		featureSchema, err := k8s.registeredResourceSchema(feature)
 		if err != nil {
@@ -1397,7 +1390,7 @@ func (k8s *K8sOfflineStore) trainingSet(def TrainingSetDef, isUpdate bool) error
 			k8s.logger.Errorw("Could not get latest feature file", "error", err)
 			return err
 		}
-		sourcePaths = append(sourcePaths, featurePath.Filepath().ToURI())
+		sourcePaths = append(sourcePaths, pinnedSourcePath(def, featurePath))
 		featureSchemas = append(featureSchemas, featureSchema)
 	}
 	trainingSetQuery := k8s.query.trainingSetCreate(def, featureSchemas, labelSchema)
@@ -1415,6 +1408,59 @@ func (k8s *K8sOfflineStore) trainingSet(def TrainingSetDef, isUpdate bool) error
 	return nil
 }
 
+// pinnedSourcePath returns the path the pandas runner reads a training set source from: the copy
+// of it or the Delta version of it that def pins, if any, or the source itself.
+func pinnedSourcePath(def TrainingSetDef, loc *pl.FileStoreLocation) string {
+	snapshot, ok := def.inputSnapshot(snapshotLocation(loc))
+	if !ok {
+		return loc.Filepath().ToURI()
+	}
+	switch snapshot.Kind {
+	case fftypes.FileCopySnapshot:
+		return snapshot.Version
+	case fftypes.TableVersionSnapshot:
+		return fmt.Sprintf("%s@v%s", loc.Filepath().ToURI(), snapshot.Version)
+	default:
+		return loc.Filepath().ToURI()
+	}
+}
+
+// PinTrainingSetInputs pins the current version of each Delta table a training set reads and
+// copies its other files. The pandas runner can't read Iceberg snapshots, so Iceberg inputs can't
+// be pinned.
+func (k8s *K8sOfflineStore) PinTrainingSetInputs(def TrainingSetDef, policy SnapshotPolicy) ([]fftypes.DataSnapshot, error) {
+	inputs := make([]trainingSetInput, 0, 1+len(def.Features))
+	for _, id := range append([]ResourceID{def.Label}, def.Features...) {
+		schema, err := k8s.registeredResourceSchema(id)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, trainingSetInput{resource: snapshotResourceName(id), location: schema.SourceTable})
+	}
+	snapshots, err := pinFileStoreInputs(k8s.store, def, inputs)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Kind == fftypes.TableVersionSnapshot && snapshot.TableFormat != string(filestore.Delta) {
+			return nil, fferr.NewInvalidArgumentErrorf("%s can't pin %s, which is a %s table", k8s.Type(), snapshot.Resource, snapshot.TableFormat)
+		}
+	}
+	return snapshots, nil
+}
+
+func (k8s *K8sOfflineStore) PinTrainingSet(def TrainingSetDef, policy SnapshotPolicy) (fftypes.DataSnapshot, error) {
+	return pinFileStoreTrainingSet(k8s.store, def, time.Now().UTC())
+}
+
+func (k8s *K8sOfflineStore) GetTrainingSetSnapshot(id ResourceID, snapshot fftypes.DataSnapshot) (dataset.TrainingSetIterator, error) {
+	return fileStoreGetTrainingSetSnapshot(id, k8s.store, snapshot)
+}
+
+func (k8s *K8sOfflineStore) ReleaseSnapshots(snapshots []fftypes.DataSnapshot) error {
+	return releaseFileStoreSnapshots(k8s.store, snapshots)
+}
+
 func (k8s *K8sOfflineStore) GetTrainingSet(id ResourceID) (dataset.TrainingSetIterator, error) {
 	legacyIter, err := fileStoreGetTrainingSet(id, k8s.store, k8s.logger)
 	if err != nil {
@@ -1458,15 +1504,7 @@ func fileStoreGetTrainingSet(id ResourceID, store FileStore, logger *zap.Sugared
 	if err != nil {
 		return nil, err
 	}
-	files, err := store.List(filepath, filestore.Parquet)
-	if err != nil {
-		return nil, err
-	}
-	groups, err := filestore.NewFilePathGroup(files, filestore.DateTimeDirectoryGrouping)
-	if err != nil {
-		return nil, err
-	}
-	newestFiles, err := groups.GetFirst()
+	newestFiles, err := fileStoreNewestFiles(store, filepath)
 	if err != nil {
 		return nil, err
 	}
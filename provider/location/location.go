@@ -225,6 +225,12 @@ func NewFileLocation(path filestore.Filepath) Location {
 	return &FileStoreLocation{path: path}
 }
 
+// NewFileTableLocation returns the location of a Delta Lake or Iceberg table stored in a file
+// store directory.
+func NewFileTableLocation(path filestore.Filepath, tableFormat string) Location {
+	return &FileStoreLocation{path: path, tableFormat: tableFormat}
+}
+
 func NewFileLocationFromURI(uri string) (Location, error) {
 	fp := filestore.FilePath{}
 	if err := fp.ParseFilePath(uri); err != nil {
@@ -235,6 +241,8 @@ func NewFileLocationFromURI(uri string) (Location, error) {
 
 type FileStoreLocation struct {
 	path filestore.Filepath
+	// tableFormat is set when the path is a table's directory rather than plain files.
+	tableFormat string
 }
 
 func (l FileStoreLocation) Location() string {
@@ -258,11 +266,19 @@ func (l FileStoreLocation) Filepath() filestore.Filepath {
 	return l.path
 }
 
+func (l FileStoreLocation) TableFormat() string {
+	return l.tableFormat
+}
+
 func (l FileStoreLocation) MarshalJSON() ([]byte, error) {
-	return json.Marshal(JSONLocation{
+	jsonLoc := JSONLocation{
 		OutputLocation: l.Location(),
 		LocationType:   "filestore",
-	})
+	}
+	if l.tableFormat != "" {
+		jsonLoc.TableFormat = &l.tableFormat
+	}
+	return json.Marshal(jsonLoc)
 }
 
 func (l *FileStoreLocation) Deserialize(config []byte) error {
@@ -278,6 +294,10 @@ func (l *FileStoreLocation) Deserialize(config []byte) error {
 		return err
 	}
 	l.path = &fp
+	l.tableFormat = ""
+	if jsonLoc.TableFormat != nil {
+		l.tableFormat = *jsonLoc.TableFormat
+	}
 	return nil
 }
 
@@ -285,7 +305,8 @@ func (l *FileStoreLocation) Proto() *pb.Location {
 	return &pb.Location{
 		Location: &pb.Location_Filestore{
 			Filestore: &pb.FileStoreTable{
-				Path: l.path.ToURI(),
+				Path:        l.path.ToURI(),
+				TableFormat: l.tableFormat,
 			},
 		},
 	}
@@ -438,6 +459,9 @@ func FromProto(pbLocation *pb.Location) (Location, error) {
 		if err != nil {
 			return nil, fferr.NewInternalErrorf("invalid filestore path: %v", err)
 		}
+		if loc.Filestore.TableFormat != "" {
+			return NewFileTableLocation(&fp, loc.Filestore.TableFormat), nil
+		}
 		return NewFileLocation(&fp), nil
 
 	case *pb.Location_Catalog:
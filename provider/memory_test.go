@@ -25,3 +25,11 @@ func TestOfflineStoreMemory(t *testing.T) {
 	}
 	test.Run()
 }
+
+func TestOfflineStoreMemoryTrainTestSplit(t *testing.T) {
+	store, err := GetOfflineStore(pt.MemoryOffline, []byte{})
+	if err != nil {
+		t.Fatalf("could not initialize store: %s\n", err)
+	}
+	testTrainTestSplit(t, store)
+}
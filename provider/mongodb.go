@@ -202,7 +202,16 @@ func (store *mongoDBOnlineStore) DeleteTable(feature, variant string) error {
 }
 
 func (store *mongoDBOnlineStore) CheckHealth() (bool, error) {
-	return false, fferr.NewInternalError(fmt.Errorf("not implemented"))
+	return store.CheckHealthContext(context.Background())
+}
+
+func (store *mongoDBOnlineStore) CheckHealthContext(ctx context.Context) (bool, error) {
+	if err := store.client.Ping(ctx, nil); err != nil {
+		wrapped := fferr.NewConnectionError(pt.MongoDBOnline.String(), err)
+		wrapped.AddDetail("action", "ping")
+		return false, wrapped
+	}
+	return true, nil
 }
 
 func (store mongoDBOnlineStore) Delete(location pl.Location) error {
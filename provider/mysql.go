@@ -148,6 +148,11 @@ func (q mySQLQueries) newSQLOfflineTable(name string, columnType string) string
 	)
 }
 
+// trainTestSplitHash uses CONCAT_WS since MySQL doesn't cast to VARCHAR and || is a logical OR.
+func (q mySQLQueries) trainTestSplitHash(exprs []string, seed int) string {
+	return fmt.Sprintf("MD5(CONCAT_WS('|', %s, '%d'))", strings.Join(exprs, ", "), seed)
+}
+
 func (q mySQLQueries) createValuePlaceholderString(columns []TableColumn) string {
 	placeholders := make([]string, 0)
 	for i := range columns {
@@ -164,9 +169,21 @@ func (q mySQLQueries) trainingSetUpdate(store *sqlOfflineStore, def TrainingSetD
 	return q.trainingSetQuery(store, def, tableName, labelName, true)
 }
 
+func (q mySQLQueries) spineLabelQuery(def TrainingSetDef) (string, error) {
+	return spineLabelQuery(def, "CHAR", "CAST('9999-12-31 23:59:59' AS DATETIME)")
+}
+
 func (q mySQLQueries) trainingSetQuery(store *sqlOfflineStore, def TrainingSetDef, tableName string, labelName string, isUpdate bool) error {
 	columns := make([]string, 0)
-	query := fmt.Sprintf("(SELECT entity, value , ts from %s ) l", sanitize(labelName))
+	labelTable := sanitize(labelName)
+	if def.Spine != nil {
+		spineQuery, err := q.spineLabelQuery(def)
+		if err != nil {
+			return err
+		}
+		labelTable = fmt.Sprintf("(%s) AS spine", spineQuery)
+	}
+	query := fmt.Sprintf("(SELECT entity, value , ts from %s ) l", labelTable)
 	for i, feature := range def.Features {
 		tableName, err := store.getResourceTableName(feature)
 		if err != nil {
@@ -183,16 +200,20 @@ func (q mySQLQueries) trainingSetQuery(store *sqlOfflineStore, def TrainingSetDe
 		}
 	}
 	columnStr := strings.Join(columns, ", ")
+	// Spine training sets only contain feature columns.
+	if def.Spine == nil {
+		columnStr = fmt.Sprintf("%s, l.value as label", columnStr)
+	}
 
 	if isUpdate {
 		tempName := sanitize(fmt.Sprintf("tmp_%s", tableName))
-		fullQuery := fmt.Sprintf("CREATE TABLE %s AS (SELECT %s, l.value as label FROM %s ", tempName, columnStr, query)
+		fullQuery := fmt.Sprintf("CREATE TABLE %s AS (SELECT %s FROM %s ", tempName, columnStr, query)
 		err := q.atomicUpdate(store.db, tableName, tempName, fullQuery)
 		if err != nil {
 			return err
 		}
 	} else {
-		fullQuery := fmt.Sprintf("CREATE TABLE %s AS (SELECT %s, l.value as label FROM %s ", sanitize(tableName), columnStr, query)
+		fullQuery := fmt.Sprintf("CREATE TABLE %s AS (SELECT %s FROM %s ", sanitize(tableName), columnStr, query)
 		if _, err := store.db.Exec(fullQuery); err != nil {
 			wrapped := fferr.NewExecutionError(pt.MySqlOffline.String(), err)
 			wrapped.AddDetail("table_name", tableName)
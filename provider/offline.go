@@ -169,10 +169,19 @@ type LagFeatureDef struct {
 }
 
 type TrainingSetDef struct {
-	ID                 ResourceID
-	Label              ResourceID
+	ID    ResourceID
+	Label ResourceID
+	// Spine is set instead of Label for training sets that only contain features, e.g. for
+	// batch scoring. The spine is a primary or transformation whose rows are described by
+	// LabelSourceMapping; its EntityMappings have no ValueColumn.
+	Spine              *ResourceID
 	LabelSourceMapping SourceMapping
-	Features           []ResourceID
+	// AdditionalLabels are joined to the label or spine the same way as features and are
+	// only supported by stores that build training sets with tsquery. Like features, each
+	// source mapping has Columns and exactly one entity mapping.
+	AdditionalLabels              []ResourceID
+	AdditionalLabelSourceMappings []SourceMapping
+	Features                      []ResourceID
 	// **NOTE** The ProviderType and ProviderConfig fields in FeatureSourceMappings correspond
 	// the feature's source provider as the feature's provider will be the inference store.
 	// See getFeatureSourceMapping in coordinator/tasks/trainingset.go for more details.
@@ -180,12 +189,17 @@ type TrainingSetDef struct {
 	LagFeatures             []LagFeatureDef
 	ResourceSnowflakeConfig *metadata.ResourceSnowflakeConfig
 	Type                    metadata.TrainingSetType
+	// Snapshots, if set, are pinned snapshots of the inputs that the training set is built from
+	// instead of their current data. Only stores that implement SnapshotOfflineStore read them.
+	Snapshots []fftype.DataSnapshot
 }
 
 type TrainingSetDefJSON struct {
 	ID                      ResourceID                        `json:"ID"`
 	Label                   ResourceID                        `json:"Label"`
+	Spine                   *ResourceID                       `json:"Spine,omitempty"`
 	LabelSourceMapping      SourceMappingJSON                 `json:"LabelSourceMapping"`
+	AdditionalLabels        []ResourceID                      `json:"AdditionalLabels,omitempty"`
 	Features                []ResourceID                      `json:"Features"`
 	FeatureSourceMappings   []SourceMappingJSON               `json:"FeatureSourceMappings"`
 	LagFeatures             []LagFeatureDef                   `json:"LagFeatures"`
@@ -196,9 +210,25 @@ func (def *TrainingSetDef) check() error {
 	if err := def.ID.check(TrainingSet); err != nil {
 		return err
 	}
-	if err := def.Label.check(Label); err != nil {
+	if def.Spine != nil {
+		if err := def.Spine.check(Primary, Transformation); err != nil {
+			return err
+		}
+		mappings := def.LabelSourceMapping.EntityMappings
+		if mappings == nil || len(mappings.Mappings) == 0 {
+			return fferr.NewInvalidArgumentError(errors.New("training set spine must map at least one entity"))
+		}
+	} else if err := def.Label.check(Label); err != nil {
 		return err
 	}
+	if len(def.AdditionalLabels) != len(def.AdditionalLabelSourceMappings) {
+		return fferr.NewInvalidArgumentError(errors.New("training set must have a source mapping for every additional label"))
+	}
+	for i := range def.AdditionalLabels {
+		if err := def.AdditionalLabels[i].check(Label); err != nil {
+			return err
+		}
+	}
 	if len(def.Features) == 0 {
 		return fferr.NewInvalidArgumentError(errors.New("training set must have at least one feature"))
 	}
@@ -257,6 +287,9 @@ type TransformationConfig struct {
 	OutputLocationType      pl.LocationType
 	TableFormat             string
 	ResourceSnowflakeConfig *metadata.ResourceSnowflakeConfig
+	// Incremental is set when only the new rows of the sources are queried, and the result
+	// is merged into the existing table rather than replacing it.
+	Incremental *IncrementalConfig
 }
 
 func (m *TransformationConfig) MarshalJSON() ([]byte, error) {
@@ -291,6 +324,7 @@ func (m *TransformationConfig) UnmarshalJSON(data []byte) error {
 		LastRunTimestamp time.Time
 		IsUpdate         bool
 		SparkFlags       pc.SparkFlags
+		Incremental      *IncrementalConfig
 	}
 
 	var temp tempConfig
@@ -308,6 +342,7 @@ func (m *TransformationConfig) UnmarshalJSON(data []byte) error {
 	m.LastRunTimestamp = temp.LastRunTimestamp
 	m.IsUpdate = temp.IsUpdate
 	m.SparkFlags = temp.SparkFlags
+	m.Incremental = temp.Incremental
 
 	err = m.decodeArgs(temp.ArgType, temp.Args)
 	if err != nil {
@@ -341,7 +376,25 @@ type TrainTestSplitDef struct {
 	TrainingSetVariant string
 	TestSize           float32
 	Shuffle            bool
-	RandomState        int
+	// RandomState seeds the shuffle. If it's 0 a random seed is picked, so the split isn't repeatable.
+	RandomState int
+	// Type defaults to RandomSplit.
+	Type TrainTestSplitType
+	// SplitTime is the TimeSplit boundary: rows before it are train, the rest are test. If it's
+	// zero, the latest TestSize of the rows are test.
+	SplitTime time.Time
+	// TimestampColumn and EntityColumn are the training set columns TimeSplit and EntitySplit
+	// use. The memory store ignores them and uses the label's timestamp and entity.
+	TimestampColumn string
+	EntityColumn    string
+	// If Folds is greater than 1, rows (or entities for EntitySplit) are dealt into Folds
+	// folds and Fold, counting from 0, is the test set. TestSize is ignored.
+	Folds int
+	Fold  int
+	// RequestID identifies the request the split is made for. Stores that keep the split
+	// around include it in its name, so concurrent requests for the same split don't read,
+	// or drop, each other's.
+	RequestID string
 }
 
 type MaterializationOptions struct {
@@ -381,6 +434,9 @@ const (
 	// ResumableTransformation makes transformations run async and returns a parameter that can be used
 	// to resume it in the future.
 	ResumableTransformation TransformationOptionType = "ResumableTransformation"
+	// RunLogTransformation forwards logs from the job running the transformation, like a Spark
+	// driver's output, to the caller.
+	RunLogTransformation TransformationOptionType = "RunLogTransformation"
 )
 
 type TransformationOptions []TransformationOption
@@ -411,10 +467,52 @@ func (opts TransformationOptions) GetResumeOption(logger logging.Logger) (*Resum
 	return casted, true
 }
 
+func (opts TransformationOptions) GetRunLogOption(logger logging.Logger) (*RunLogOption, bool) {
+	opt := opts.GetByType(RunLogTransformation)
+	if opt == nil {
+		logger.Debugw("RunLogOption not found")
+		return nil, false
+	}
+	casted, ok := opt.(*RunLogOption)
+	if !ok {
+		logger.DPanicw(
+			"Unknown transformation option with RunLogTransformation type",
+			"option", opt,
+		)
+		return nil, false
+	}
+	return casted, true
+}
+
 type TransformationOption interface {
 	Type() TransformationOptionType
 }
 
+// RunLogOption forwards job logs to addLog, which typically appends them to the task run's logs.
+type RunLogOption struct {
+	addLog func(msg string) error
+}
+
+func (opt *RunLogOption) Type() TransformationOptionType {
+	return RunLogTransformation
+}
+
+func (opt *RunLogOption) Log(msg string) error {
+	return opt.addLog(msg)
+}
+
+func WithRunLogs(addLog func(msg string) error) *RunLogOption {
+	return &RunLogOption{
+		addLog: addLog,
+	}
+}
+
+// TransformationCanceller is implemented by offline stores that can stop a transformation that
+// was started with a ResumeOption, given its resume ID.
+type TransformationCanceller interface {
+	CancelTransformation(resumeID types.ResumeID) error
+}
+
 type ResumeOption struct {
 	// resumeID is used to resume a running transformation. It may have been set by the user in
 	// which case this should become a resume operation. Must use mutex when checking.
@@ -860,10 +958,36 @@ func (adapter *PrimaryTableToDatasetAdapter) Iterator(ctx context.Context, limit
 	return &OldIteratorToNewIteratorAdapter{iterator}, nil
 }
 
+// Schema returns the adapted table's column names, or an empty schema if the table can't be read.
+// Use ReadSchema to find out why a read failed.
 func (adapter *PrimaryTableToDatasetAdapter) Schema() fftype.Schema {
-	iterator, err := adapter.pt.IterateSegment(1)
+	schema, err := adapter.ReadSchema()
+	if err != nil {
+		return fftype.Schema{}
+	}
+	return schema
+}
+
+// ReadSchema returns the adapted table's columns. Tables that can describe themselves from the
+// store's metadata report each column's native and value type; the rest only report names.
+func (adapter *PrimaryTableToDatasetAdapter) ReadSchema() (fftype.Schema, error) {
+	if reader, ok := adapter.pt.(typedSchemaReader); ok {
+		return reader.readSchema()
+	}
+	return readColumnNames(adapter.pt)
+}
+
+// typedSchemaReader is implemented by primary tables that can read their column types from the
+// underlying store.
+type typedSchemaReader interface {
+	readSchema() (fftype.Schema, error)
+}
+
+// readColumnNames builds an untyped schema from the columns of the table's first row segment.
+func readColumnNames(table PrimaryTable) (fftype.Schema, error) {
+	iterator, err := table.IterateSegment(1)
 	if err != nil {
-		panic(err)
+		return fftype.Schema{}, err
 	}
 	defer iterator.Close()
 
@@ -877,7 +1001,21 @@ func (adapter *PrimaryTableToDatasetAdapter) Schema() fftype.Schema {
 
 	return fftype.Schema{
 		Fields: fields,
+	}, nil
+}
+
+// SchemaReader is implemented by datasets whose schema is read from the underlying table and can
+// fail to load.
+type SchemaReader interface {
+	ReadSchema() (fftype.Schema, error)
+}
+
+// ReadSchema returns ds's schema, surfacing the read error for datasets that implement SchemaReader.
+func ReadSchema(ds dataset.Dataset) (fftype.Schema, error) {
+	if reader, ok := ds.(SchemaReader); ok {
+		return reader.ReadSchema()
 	}
+	return ds.Schema(), nil
 }
 
 func (adapter *PrimaryTableToDatasetAdapter) WriteBatch(ctx context.Context, records []fftype.Row) error {
@@ -1195,6 +1333,7 @@ type memoryOfflineStore struct {
 	tables           syncmap.Map
 	materializations syncmap.Map
 	trainingSets     syncmap.Map
+	trainTestSplits  syncmap.Map
 	BaseProvider
 }
 
@@ -1215,6 +1354,7 @@ func NewMemoryOfflineStore() *memoryOfflineStore {
 		tables:           syncmap.Map{},
 		materializations: syncmap.Map{},
 		trainingSets:     syncmap.Map{},
+		trainTestSplits:  syncmap.Map{},
 		BaseProvider: BaseProvider{
 			ProviderType:   pt.MemoryOffline,
 			ProviderConfig: []byte{},
@@ -1433,6 +1573,12 @@ func (store *memoryOfflineStore) CreateTrainingSet(def TrainingSetDef) error {
 	if err := def.check(); err != nil {
 		return err
 	}
+	if def.Spine != nil {
+		return fferr.NewInvalidArgumentErrorf("%s does not support training sets with a spine", store.Type())
+	}
+	if len(def.AdditionalLabels) > 0 {
+		return fferr.NewInvalidArgumentErrorf("%s does not support training sets with multiple labels", store.Type())
+	}
 	label, err := store.getMemoryResourceTable(def.Label)
 	if err != nil {
 		return err
@@ -1456,6 +1602,8 @@ func (store *memoryOfflineStore) CreateTrainingSet(def TrainingSetDef) error {
 		trainingData[i] = trainingRow{
 			Features: featureVals,
 			Label:    labelVal,
+			Entity:   rec.Entity,
+			TS:       rec.TS,
 		}
 	}
 	store.trainingSets.Store(def.ID, trainingData)
@@ -1479,25 +1627,45 @@ func (store *memoryOfflineStore) GetTrainingSet(id ResourceID) (dataset.Training
 }
 
 func (store *memoryOfflineStore) CreateTrainTestSplit(def TrainTestSplitDef) (func() error, error) {
-	// TODO properly implement this
+	train, test, err := store.splitTrainingSet(def)
+	if err != nil {
+		return nil, err
+	}
+	store.trainTestSplits.Store(def, [2]trainingRows{train, test})
 	dropFunc := func() error {
+		store.trainTestSplits.Delete(def)
 		return nil
 	}
 	return dropFunc, nil
 }
 
+// GetTrainTestSplit returns the split made by CreateTrainTestSplit, or makes it again if this store
+// didn't create it. Only a shuffle without a RandomState comes out differently the second time.
 func (store *memoryOfflineStore) GetTrainTestSplit(def TrainTestSplitDef) (dataset.TrainingSetIterator, dataset.TrainingSetIterator, error) {
-	// TODO properly implement this
-	trainingSetResourceId := ResourceID{
-		Name:    def.TrainingSetName,
-		Variant: def.TrainingSetVariant,
+	var train, test trainingRows
+	if data, has := store.trainTestSplits.Load(def); has {
+		split := data.([2]trainingRows)
+		train, test = split[0], split[1]
+	} else {
+		var err error
+		if train, test, err = store.splitTrainingSet(def); err != nil {
+			return nil, nil, err
+		}
 	}
-	trainingSet, err := store.GetTrainingSet(trainingSetResourceId)
-	if err != nil {
+	return NewLegacyTrainingSetIteratorAdapter(train.Iterator()), NewLegacyTrainingSetIteratorAdapter(test.Iterator()), nil
+}
+
+func (store *memoryOfflineStore) splitTrainingSet(def TrainTestSplitDef) (trainingRows, trainingRows, error) {
+	if err := def.check(); err != nil {
 		return nil, nil, err
 	}
-	return trainingSet, trainingSet, nil
-
+	id := ResourceID{Name: def.TrainingSetName, Variant: def.TrainingSetVariant, Type: TrainingSet}
+	data, has := store.trainingSets.Load(id)
+	if !has {
+		return nil, nil, fferr.NewDatasetNotFoundError(id.Name, id.Variant, nil)
+	}
+	train, test := data.(trainingRows).split(def, def.seed())
+	return train, test, nil
 }
 
 func (store *memoryOfflineStore) Close() error {
@@ -1521,6 +1689,9 @@ func (rows trainingRows) Iterator() TrainingSetIterator {
 type trainingRow struct {
 	Features []interface{}
 	Label    interface{}
+	// Entity and TS come from the label row and are only used to split the training set.
+	Entity string
+	TS     time.Time
 }
 
 type memoryTrainingRowsIterator struct {
@@ -1866,13 +2037,40 @@ func (def *TrainingSetDef) ToBuilderParams(logger logging.Logger, sanitizeTableN
 		logger.Debugw("Feature entity mapping", "entity_mappings", ft.EntityMappings.Mappings)
 		ftEntityNames = append(ftEntityNames, ft.EntityMappings.Mappings[0].Name)
 	}
-	logger.Debugw("Label entity mapping", "entity_mappings", def.LabelSourceMapping.EntityMappings)
+	lblCols := make([]metadata.ResourceVariantColumns, len(def.AdditionalLabelSourceMappings))
+	lblTableNames := make([]string, len(def.AdditionalLabelSourceMappings))
+	lblNameVariants := make([]metadata.ResourceID, len(def.AdditionalLabelSourceMappings))
+	lblEntityNames := make([]string, len(def.AdditionalLabelSourceMappings))
+	for i, lbl := range def.AdditionalLabelSourceMappings {
+		if lbl.Columns == nil || lbl.EntityMappings == nil || len(lbl.EntityMappings.Mappings) != 1 {
+			logger.Errorw("Expected each additional label source mapping to have columns and exactly one entity mapping", "mapping", lbl)
+			return tsq.BuilderParams{}, fferr.NewInternalErrorf("expected each additional label source mapping to have columns and exactly one entity mapping: mappings = %v", lbl.EntityMappings)
+		}
+		lblCols[i] = *lbl.Columns
+		lblTableNames[i], err = sanitizeTableNameFn(lbl.Location)
+		if err != nil {
+			return tsq.BuilderParams{}, err
+		}
+		id := def.AdditionalLabels[i]
+		lblNameVariants[i] = metadata.ResourceID{
+			Name:    id.Name,
+			Variant: id.Variant,
+			Type:    metadata.LABEL_VARIANT,
+		}
+		lblEntityNames[i] = lbl.EntityMappings.Mappings[0].Name
+	}
+	logger.Debugw("Label entity mapping", "entity_mappings", def.LabelSourceMapping.EntityMappings, "is_spine", def.Spine != nil, "additional_labels", def.AdditionalLabels)
 	return tsq.BuilderParams{
-		LabelEntityMappings:    def.LabelSourceMapping.EntityMappings,
-		SanitizedLabelTable:    lblTableName,
-		FeatureColumns:         ftCols,
-		SanitizedFeatureTables: ftTableNames,
-		FeatureNameVariants:    ftNameVariants,
-		FeatureEntityNames:     ftEntityNames,
+		LabelEntityMappings:            def.LabelSourceMapping.EntityMappings,
+		SanitizedLabelTable:            lblTableName,
+		FeatureColumns:                 ftCols,
+		SanitizedFeatureTables:         ftTableNames,
+		FeatureNameVariants:            ftNameVariants,
+		FeatureEntityNames:             ftEntityNames,
+		IsSpine:                        def.Spine != nil,
+		AdditionalLabelColumns:         lblCols,
+		SanitizedAdditionalLabelTables: lblTableNames,
+		AdditionalLabelNameVariants:    lblNameVariants,
+		AdditionalLabelEntityNames:     lblEntityNames,
 	}, nil
 }
@@ -16,10 +16,12 @@ import (
 	"math/rand"
 	"os"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/parquet-go/parquet-go"
 	"gotest.tools/v3/assert"
@@ -5036,9 +5038,6 @@ func testTrainTestSplit(t *testing.T, store OfflineStore) {
 		nameConst := name
 		testConst := test
 		t.Run(nameConst, func(t *testing.T) {
-			if store.Type() != pt.ClickHouseOffline {
-				t.Skip()
-			}
 			testConst.TestFunction(t, store, testConst.TestParameters)
 		})
 	}
@@ -5296,3 +5295,229 @@ func GenericRecordsToRows(records []GenericRecord) []fftypes.Row {
 	}
 	return rows
 }
+
+func TestTrainingSetDefSpineCheck(t *testing.T) {
+	spineMappings := &metadata.EntityMappings{
+		Mappings:        []metadata.EntityMapping{{Name: "user", EntityColumn: "user_id"}},
+		TimestampColumn: "scored_at",
+	}
+	tests := []struct {
+		name      string
+		def       TrainingSetDef
+		expectErr bool
+	}{
+		{
+			name: "label",
+			def: TrainingSetDef{
+				ID:       ResourceID{Name: "ts", Variant: "v"},
+				Label:    ResourceID{Name: "label", Variant: "v"},
+				Features: []ResourceID{{Name: "feature", Variant: "v"}},
+			},
+		},
+		{
+			name: "spine without label",
+			def: TrainingSetDef{
+				ID:                 ResourceID{Name: "ts", Variant: "v"},
+				Spine:              &ResourceID{Name: "users_to_score", Variant: "v", Type: Primary},
+				LabelSourceMapping: SourceMapping{EntityMappings: spineMappings},
+				Features:           []ResourceID{{Name: "feature", Variant: "v"}},
+			},
+		},
+		{
+			name: "spine without entity mappings",
+			def: TrainingSetDef{
+				ID:       ResourceID{Name: "ts", Variant: "v"},
+				Spine:    &ResourceID{Name: "users_to_score", Variant: "v", Type: Transformation},
+				Features: []ResourceID{{Name: "feature", Variant: "v"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "spine that isn't a source",
+			def: TrainingSetDef{
+				ID:                 ResourceID{Name: "ts", Variant: "v"},
+				Spine:              &ResourceID{Name: "users_to_score", Variant: "v", Type: Feature},
+				LabelSourceMapping: SourceMapping{EntityMappings: spineMappings},
+				Features:           []ResourceID{{Name: "feature", Variant: "v"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "neither label nor spine",
+			def: TrainingSetDef{
+				ID:       ResourceID{Name: "ts", Variant: "v"},
+				Features: []ResourceID{{Name: "feature", Variant: "v"}},
+			},
+			expectErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.def.check()
+			if (err != nil) != tc.expectErr {
+				t.Errorf("check() error = %v, expectErr %v", err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func TestTrainingSetDefSpineBuilderParams(t *testing.T) {
+	def := TrainingSetDef{
+		ID:    ResourceID{Name: "ts", Variant: "v", Type: TrainingSet},
+		Spine: &ResourceID{Name: "users_to_score", Variant: "v", Type: Primary},
+		LabelSourceMapping: SourceMapping{
+			Location: pl.NewSQLLocation("users_to_score"),
+			EntityMappings: &metadata.EntityMappings{
+				Mappings: []metadata.EntityMapping{{Name: "user", EntityColumn: "user_id"}},
+			},
+		},
+		Features: []ResourceID{{Name: "avg_spend", Variant: "v", Type: Feature}},
+		FeatureSourceMappings: []SourceMapping{
+			{
+				Location: pl.NewSQLLocation("transactions"),
+				Columns:  &metadata.ResourceVariantColumns{Entity: "user_id", Value: "amount"},
+				EntityMappings: &metadata.EntityMappings{
+					Mappings: []metadata.EntityMapping{{Name: "user", EntityColumn: "user_id"}},
+				},
+			},
+		},
+	}
+	params, err := def.ToBuilderParams(logging.NewTestLogger(t), func(loc pl.Location) (string, error) {
+		return loc.Location(), nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to build params: %v", err)
+	}
+	if !params.IsSpine {
+		t.Fatalf("Expected builder params to be for a spine")
+	}
+	if params.SanitizedLabelTable != "users_to_score" {
+		t.Fatalf("Expected spine table users_to_score, got %s", params.SanitizedLabelTable)
+	}
+}
+
+func TestSQLSpineTrainingSets(t *testing.T) {
+	def := TrainingSetDef{
+		ID:    ResourceID{Name: "ts", Variant: "v", Type: TrainingSet},
+		Spine: &ResourceID{Name: "users_to_score", Variant: "v", Type: Primary},
+		LabelSourceMapping: SourceMapping{
+			Location: pl.NewSQLLocation("users_to_score"),
+			EntityMappings: &metadata.EntityMappings{
+				Mappings: []metadata.EntityMapping{{Name: "user", EntityColumn: "user_id"}},
+			},
+		},
+		Features: []ResourceID{{Name: "avg_spend", Variant: "v", Type: Feature}},
+	}
+	cases := []struct {
+		name      string
+		query     OfflineTableQueries
+		spineSQL  string
+		supported bool
+	}{
+		{
+			name:      "MySQL",
+			query:     &mySQLQueries{},
+			spineSQL:  `from (SELECT "user_id" AS entity, CAST(NULL AS CHAR) AS value, CAST('9999-12-31 23:59:59' AS DATETIME) AS ts FROM "users_to_score") AS spine ) l`,
+			supported: true,
+		},
+		{
+			name:      "Redshift",
+			query:     &redshiftSQLQueries{},
+			spineSQL:  `FROM (SELECT "user_id" AS entity, CAST(NULL AS VARCHAR) AS value, CAST('9999-12-31 23:59:59' AS TIMESTAMP) AS ts FROM "users_to_score") AS t0`,
+			supported: true,
+		},
+		{
+			name:  "Default",
+			query: &defaultOfflineSQLQueries{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create mock db: %v", err)
+			}
+			defer db.Close()
+			store := &sqlOfflineStore{db: db, query: c.query, logger: logging.NewTestLogger(t)}
+			if !c.supported {
+				if err := store.CreateTrainingSet(def); err == nil {
+					t.Fatalf("Expected spine training set to be rejected")
+				}
+				return
+			}
+			// Spine training sets only select the feature column, without a label.
+			selectFeatures := `^CREATE TABLE "[^"]+" AS \(SELECT "[^"]+" FROM .*`
+			mock.ExpectExec(selectFeatures + regexp.QuoteMeta(c.spineSQL)).WillReturnResult(sqlmock.NewResult(0, 0))
+			if err := store.CreateTrainingSet(def); err != nil {
+				t.Fatalf("Failed to create spine training set: %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatalf("Expected the training set to read the spine: %v", err)
+			}
+
+			multiLabelDef := def
+			multiLabelDef.AdditionalLabels = []ResourceID{{Name: "disputed", Variant: "v", Type: Label}}
+			multiLabelDef.AdditionalLabelSourceMappings = []SourceMapping{{Location: pl.NewSQLLocation("disputes")}}
+			if err := store.CreateTrainingSet(multiLabelDef); err == nil || !strings.Contains(err.Error(), "multiple labels") {
+				t.Fatalf("Expected spine training set with additional labels to be rejected: %v", err)
+			}
+			if err := store.UpdateTrainingSet(multiLabelDef); err == nil || !strings.Contains(err.Error(), "multiple labels") {
+				t.Fatalf("Expected spine training set update with additional labels to be rejected: %v", err)
+			}
+		})
+	}
+}
+
+func TestTrainingSetDefAdditionalLabelsBuilderParams(t *testing.T) {
+	def := TrainingSetDef{
+		ID:    ResourceID{Name: "ts", Variant: "v", Type: TrainingSet},
+		Label: ResourceID{Name: "is_fraud", Variant: "v", Type: Label},
+		LabelSourceMapping: SourceMapping{
+			Location: pl.NewSQLLocation("transactions"),
+			EntityMappings: &metadata.EntityMappings{
+				Mappings:    []metadata.EntityMapping{{Name: "user", EntityColumn: "user_id"}, {Name: "merchant", EntityColumn: "merchant_id"}},
+				ValueColumn: "is_fraud",
+			},
+		},
+		AdditionalLabels: []ResourceID{{Name: "disputed", Variant: "v", Type: Label}},
+		AdditionalLabelSourceMappings: []SourceMapping{
+			{
+				Location: pl.NewSQLLocation("disputes"),
+				Columns:  &metadata.ResourceVariantColumns{Entity: "merchant_id", Value: "disputed"},
+				EntityMappings: &metadata.EntityMappings{
+					Mappings: []metadata.EntityMapping{{Name: "merchant", EntityColumn: "merchant_id"}},
+				},
+			},
+		},
+		Features: []ResourceID{{Name: "avg_spend", Variant: "v", Type: Feature}},
+		FeatureSourceMappings: []SourceMapping{
+			{
+				Location: pl.NewSQLLocation("user_spend"),
+				Columns:  &metadata.ResourceVariantColumns{Entity: "user_id", Value: "amount"},
+				EntityMappings: &metadata.EntityMappings{
+					Mappings: []metadata.EntityMapping{{Name: "user", EntityColumn: "user_id"}},
+				},
+			},
+		},
+	}
+	if err := def.check(); err != nil {
+		t.Fatalf("Expected training set with additional labels to be valid: %v", err)
+	}
+	params, err := def.ToBuilderParams(logging.NewTestLogger(t), func(loc pl.Location) (string, error) {
+		return loc.Location(), nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to build params: %v", err)
+	}
+	if !reflect.DeepEqual(params.SanitizedAdditionalLabelTables, []string{"disputes"}) {
+		t.Errorf("Expected additional label table disputes, got %v", params.SanitizedAdditionalLabelTables)
+	}
+	if !reflect.DeepEqual(params.AdditionalLabelEntityNames, []string{"merchant"}) {
+		t.Errorf("Expected additional label entity merchant, got %v", params.AdditionalLabelEntityNames)
+	}
+
+	def.AdditionalLabelSourceMappings = nil
+	if err := def.check(); err == nil {
+		t.Errorf("Expected additional label without a source mapping to fail")
+	}
+}
@@ -70,6 +70,12 @@ type BatchOnlineTable interface {
 	MaxBatchSize() (int, error)
 }
 
+// WriteBudgetedStore is implemented by online stores that can be configured
+// with a write budget for materializations.
+type WriteBudgetedStore interface {
+	WriteBudget() pc.WriteBudget
+}
+
 type SetItem struct {
 	Entity string
 	Value  interface{}
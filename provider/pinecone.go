@@ -96,7 +96,12 @@ func (store *pineconeOnlineStore) DeleteTable(feature, variant string) error {
 }
 
 func (store *pineconeOnlineStore) CheckHealth() (bool, error) {
-	return false, fferr.NewInternalError(fmt.Errorf("not implemented"))
+	if err := store.client.listIndexes(); err != nil {
+		wrapped := fferr.NewConnectionError(pt.PineconeOnline.String(), err)
+		wrapped.AddDetail("action", "list indexes")
+		return false, wrapped
+	}
+	return true, nil
 }
 
 func (store *pineconeOnlineStore) Delete(location pl.Location) error {
@@ -257,6 +262,13 @@ func (api pineconeAPI) createIndex(name string, dimension int32) error {
 	return nil
 }
 
+// https://docs.pinecone.io/reference/list_indexes
+func (api pineconeAPI) listIndexes() error {
+	base := api.getIndexOperationURL("databases")
+	_, err := api.request(http.MethodGet, base, nil, http.StatusOK)
+	return err
+}
+
 // https://docs.pinecone.io/reference/describe_index
 func (api pineconeAPI) describeIndex(name string) (dimension int32, state PineconeIndexState, err error) {
 	base := api.getIndexOperationURL(fmt.Sprintf("databases/%s", name))
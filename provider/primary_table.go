@@ -15,6 +15,7 @@ import (
 	"github.com/parquet-go/parquet-go"
 
 	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
 	"github.com/featureform/filestore"
 	pl "github.com/featureform/provider/location"
 	"github.com/featureform/provider/types"
@@ -110,30 +111,26 @@ func (tbl *FileStorePrimaryTable) GetLocation() pl.Location {
 	return pl.NewFileLocation(tbl.source)
 }
 
+// files returns the files that hold the table's current data.
+func (tbl *FileStorePrimaryTable) files() ([]filestore.Filepath, error) {
+	if !tbl.source.IsDir() {
+		return []filestore.Filepath{tbl.source}, nil
+	}
+	// The key should only be a directory in the case of transformations.
+	if !tbl.isTransformation {
+		return nil, fferr.NewInternalErrorf("expected a file but got a directory: %s", tbl.source.Key())
+	}
+	// The file structure in cloud storage for transformations is /featureform/Transformation/<NAME>/<VARIANT>
+	// but there is an additional directory that's named using a timestamp that contains the transformation file
+	// we need to access, unless the transformation is written as a Delta or Iceberg table, in which case the
+	// table's log says which files hold the latest version.
+	return fileStoreNewestFiles(tbl.store, tbl.source)
+}
+
 func (tbl *FileStorePrimaryTable) IterateSegment(n int64) (GenericTableIterator, error) {
-	sources := []filestore.Filepath{tbl.source}
-	if tbl.source.IsDir() {
-		// The key should only be a directory in the case of transformations.
-		if !tbl.isTransformation {
-			return nil, fferr.NewInternalErrorf("expected a file but got a directory: %s", tbl.source.Key())
-		}
-		// The file structure in cloud storage for transformations is /featureform/Transformation/<NAME>/<VARIANT>
-		// but there is an additional directory that's named using a timestamp that contains the transformation file
-		// we need to access. NewestFileOfType will recursively search for the newest file of the given type (i.e.
-		// parquet) given a path (i.e. `key`).
-		transformations, err := tbl.store.List(tbl.source, filestore.Parquet)
-		if err != nil {
-			return nil, err
-		}
-		groups, err := filestore.NewFilePathGroup(transformations, filestore.DateTimeDirectoryGrouping)
-		if err != nil {
-			return nil, err
-		}
-		newestFiles, err := groups.GetFirst()
-		if err != nil {
-			return nil, err
-		}
-		sources = newestFiles
+	sources, err := tbl.files()
+	if err != nil {
+		return nil, err
 	}
 	fmt.Printf("Sources: %d found\n", len(sources))
 	fmt.Printf("Source %s extension %s\n", sources[0].ToURI(), string(sources[0].Ext()))
@@ -156,6 +153,65 @@ func (tbl *FileStorePrimaryTable) IterateSegment(n int64) (GenericTableIterator,
 	}
 }
 
+// readSchema reads column types from the parquet schema of the table's first file. CSV files don't
+// carry types, so only their column names are returned.
+func (tbl *FileStorePrimaryTable) readSchema() (fftypes.Schema, error) {
+	sources, err := tbl.files()
+	if err != nil {
+		return fftypes.Schema{}, err
+	}
+	if len(sources) == 0 || sources[0].Ext() != filestore.Parquet {
+		return readColumnNames(tbl)
+	}
+	src, err := tbl.store.ReaderAt(sources[0])
+	if err != nil {
+		return fftypes.Schema{}, err
+	}
+	reader := parquet.NewReader(src)
+	defer reader.Close()
+	fields := reader.Schema().Fields()
+	columns := make([]fftypes.ColumnSchema, len(fields))
+	for i, field := range fields {
+		columns[i] = parquetColumnSchema(field)
+	}
+	return fftypes.Schema{Fields: columns}, nil
+}
+
+// parquetColumnSchema maps a parquet field to the value type its values are read as. Nested fields,
+// such as vector lists, are left untyped so they are only checked for presence.
+func parquetColumnSchema(field parquet.Field) fftypes.ColumnSchema {
+	column := fftypes.ColumnSchema{Name: fftypes.ColumnName(field.Name())}
+	if !field.Leaf() {
+		return column
+	}
+	fieldType := field.Type()
+	column.NativeType = fftypes.NativeTypeLiteral(fieldType.String())
+	logical := fieldType.LogicalType()
+	switch fieldType.Kind() {
+	case parquet.Boolean:
+		column.Type = fftypes.Bool
+	case parquet.Int32:
+		column.Type = fftypes.Int32
+	case parquet.Int64:
+		if logical != nil && logical.Timestamp != nil {
+			column.Type = fftypes.Timestamp
+		} else {
+			column.Type = fftypes.Int64
+		}
+	case parquet.Int96:
+		column.Type = fftypes.Timestamp
+	case parquet.Float:
+		column.Type = fftypes.Float32
+	case parquet.Double:
+		column.Type = fftypes.Float64
+	case parquet.ByteArray:
+		if logical != nil && (logical.UTF8 != nil || logical.Enum != nil || logical.Json != nil) {
+			column.Type = fftypes.String
+		}
+	}
+	return column
+}
+
 func (tbl *FileStorePrimaryTable) NumRows() (int64, error) {
 	src := tbl.source
 	return tbl.store.NumRows(src)
@@ -8,17 +8,22 @@
 package provider
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"github.com/parquet-go/parquet-go"
 
+	fftypes "github.com/featureform/fftypes"
 	pc "github.com/featureform/provider/provider_config"
 	ps "github.com/featureform/provider/provider_schema"
 	"github.com/featureform/provider/types"
+	"github.com/featureform/scheduling"
 )
 
 func TestFileStorePrimaryTable(t *testing.T) {
@@ -222,3 +227,70 @@ func getRecords() []GenericRecord {
 		[]interface{}{"e", 5, 1.5, "fifth string", true, []float32{1.0, 5.0, 1.0}, time.UnixMilli(0)},
 	}
 }
+
+func TestFileStorePrimaryTableSchemaDetectsRetypedColumn(t *testing.T) {
+	config := pc.LocalFileStoreConfig{DirPath: fmt.Sprintf("file:///%s", t.TempDir())}
+	serialized, err := config.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize local store config: %v", err)
+	}
+	store, err := NewLocalFileStore(serialized)
+	if err != nil {
+		t.Fatalf("Failed to create local store: %v", err)
+	}
+	source, err := store.CreateFilePath("primary/transactions.parquet", false)
+	if err != nil {
+		t.Fatalf("Failed to create source path: %v", err)
+	}
+	writeTable := func(amountType types.ValueType, amount any) {
+		schema := TableSchema{Columns: []TableColumn{
+			{Name: "id", ValueType: types.String},
+			{Name: "amount", ValueType: amountType},
+			{Name: "ts", ValueType: types.Timestamp},
+		}}
+		records, err := schema.ToParquetRecords([]GenericRecord{{"a", amount, time.UnixMilli(0).UTC()}})
+		if err != nil {
+			t.Fatalf("Failed to convert records: %v", err)
+		}
+		buf := new(bytes.Buffer)
+		if err := parquet.Write[any](buf, records, schema.AsParquetSchema()); err != nil {
+			t.Fatalf("Failed to write parquet: %v", err)
+		}
+		if err := store.Write(source, buf.Bytes()); err != nil {
+			t.Fatalf("Failed to write source: %v", err)
+		}
+	}
+	table := &PrimaryTableToDatasetAdapter{pt: &FileStorePrimaryTable{store: store, source: source}}
+
+	writeTable(types.Int, 1)
+	registered, err := ReadSchema(table)
+	if err != nil {
+		t.Fatalf("Failed to read registered schema: %v", err)
+	}
+	columnTypes := make(map[fftypes.ColumnName]fftypes.ValueType)
+	for _, field := range registered.Fields {
+		columnTypes[field.Name] = field.Type
+	}
+	expectedTypes := map[fftypes.ColumnName]fftypes.ValueType{
+		"id":     fftypes.String,
+		"amount": fftypes.Int64,
+		"ts":     fftypes.Timestamp,
+	}
+	if !reflect.DeepEqual(expectedTypes, columnTypes) {
+		t.Fatalf("Expected column types %v, got %v", expectedTypes, columnTypes)
+	}
+	contract, err := scheduling.NewSchemaContract(registered).Schema()
+	if err != nil {
+		t.Fatalf("Failed to round trip schema contract: %v", err)
+	}
+
+	writeTable(types.String, "1")
+	live, err := ReadSchema(table)
+	if err != nil {
+		t.Fatalf("Failed to read live schema: %v", err)
+	}
+	drift := contract.Drift(live)
+	if len(drift.Retyped) != 1 || drift.Retyped[0].Name != "amount" || len(drift.Added) != 0 || len(drift.Removed) != 0 {
+		t.Fatalf("Expected only amount to be retyped, got %s", drift.String())
+	}
+}
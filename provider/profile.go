@@ -0,0 +1,623 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"container/heap"
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
+	"github.com/featureform/provider/dataset"
+	pl "github.com/featureform/provider/location"
+	pt "github.com/featureform/provider/provider_type"
+)
+
+const (
+	// profileTopValues is how many of the most frequent values are kept for string columns.
+	profileTopValues = 10
+	// profileDistinctSketchSize is how many distinct values are counted exactly before the
+	// distinct count becomes an estimate.
+	profileDistinctSketchSize = 4096
+	// profileMaxTrackedValues bounds how many string values are counted when finding the most
+	// frequent ones. Values first seen after the limit is hit aren't counted.
+	profileMaxTrackedValues = 10000
+	// profileHistogramBuckets is how many equal-width buckets numeric histograms have.
+	profileHistogramBuckets = 10
+	// profileReservoirSize is how many numeric values are sampled to build a histogram when a
+	// dataset is read row by row. Histograms are exact for columns up to this size.
+	profileReservoirSize = 10000
+)
+
+// DatasetProfiler is implemented by offline stores that can profile a dataset in the database
+// rather than reading it.
+type DatasetProfiler interface {
+	ProfileDataset(ctx context.Context, ds dataset.Dataset, columns []fftypes.ColumnSchema) (*fftypes.DatasetProfile, error)
+}
+
+// TrainingSetProfiler is implemented by offline stores that can profile a training set's table in
+// the database rather than reading it.
+type TrainingSetProfiler interface {
+	ProfileTrainingSet(ctx context.Context, id ResourceID, columns []fftypes.ColumnSchema) (*fftypes.DatasetProfile, error)
+}
+
+// MaterializationColumns names and types the columns of a materialization's rows, which are
+// always an entity, a value and a timestamp.
+func MaterializationColumns(valueType fftypes.ValueType) []fftypes.ColumnSchema {
+	return []fftypes.ColumnSchema{
+		{Name: "entity", Type: fftypes.String},
+		{Name: "value", Type: valueType},
+		{Name: "ts", Type: fftypes.Timestamp},
+	}
+}
+
+// ProfileDataset computes per-column statistics of ds. columns names and types each column of a
+// row in order; if it's nil, ds.Schema() is used. Stores that implement DatasetProfiler do the
+// work; otherwise ds is read once, row by row.
+func ProfileDataset(ctx context.Context, store OfflineStore, ds dataset.Dataset, columns []fftypes.ColumnSchema) (*fftypes.DatasetProfile, error) {
+	if columns == nil {
+		columns = ds.Schema().Fields
+	}
+	if profiler, ok := store.(DatasetProfiler); ok {
+		return profiler.ProfileDataset(ctx, ds, columns)
+	}
+	return profileDatasetFromIterator(ctx, ds, columns)
+}
+
+func profileDatasetFromIterator(ctx context.Context, ds dataset.Dataset, columns []fftypes.ColumnSchema) (*fftypes.DatasetProfile, error) {
+	iter, err := ds.Iterator(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	return ProfileIterator(iter, columns)
+}
+
+// ProfileIterator computes per-column statistics by reading the rest of iter. columns is used
+// like in ProfileDataset, falling back to iter.Schema(). It doesn't close iter.
+func ProfileIterator(iter dataset.Iterator, columns []fftypes.ColumnSchema) (*fftypes.DatasetProfile, error) {
+	if columns == nil {
+		columns = iter.Schema().Fields
+	}
+	return profileRows(iter, iter.Values, columns)
+}
+
+// ProfileTrainingSet profiles the features and, if hasLabel is set, the label of a training set by
+// reading the rest of iter. columns names the features in order, followed by the label. It
+// doesn't close iter.
+func ProfileTrainingSet(iter dataset.TrainingSetIterator, columns []fftypes.ColumnSchema, hasLabel bool) (*fftypes.DatasetProfile, error) {
+	return profileRows(iter, trainingSetValues(iter, hasLabel), columns)
+}
+
+// ProfileStoredTrainingSet profiles the training set id. columns and hasLabel are used like in
+// ProfileTrainingSet. Stores that implement TrainingSetProfiler do the work; otherwise the training
+// set is read once, row by row.
+func ProfileStoredTrainingSet(ctx context.Context, store OfflineStore, id ResourceID, columns []fftypes.ColumnSchema, hasLabel bool) (*fftypes.DatasetProfile, error) {
+	if profiler, ok := store.(TrainingSetProfiler); ok {
+		return profiler.ProfileTrainingSet(ctx, id, columns)
+	}
+	iter, err := store.GetTrainingSet(id)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	return ProfileTrainingSet(iter, columns, hasLabel)
+}
+
+func profileRows(src rowSource, values func() fftypes.Row, columns []fftypes.ColumnSchema) (*fftypes.DatasetProfile, error) {
+	var profilers []*columnProfiler
+	var rows int64
+	for src.Next() {
+		row := values()
+		if profilers == nil {
+			profilers = newColumnProfilers(columns, len(row))
+		}
+		if len(row) != len(profilers) {
+			return nil, fferr.NewInternalErrorf("row %d has %d columns, expected %d", rows, len(row), len(profilers))
+		}
+		rows++
+		for i, val := range row {
+			profilers[i].add(val.Value)
+		}
+	}
+	if err := src.Err(); err != nil {
+		return nil, err
+	}
+	if profilers == nil {
+		profilers = newColumnProfilers(columns, len(columns))
+	}
+	profile := &fftypes.DatasetProfile{RowCount: rows, Columns: make([]fftypes.ColumnProfile, len(profilers))}
+	for i, p := range profilers {
+		profile.Columns[i] = p.finish()
+	}
+	return profile, nil
+}
+
+func newColumnProfilers(columns []fftypes.ColumnSchema, width int) []*columnProfiler {
+	profilers := make([]*columnProfiler, width)
+	for i := range profilers {
+		col := fftypes.ColumnSchema{}
+		if i < len(columns) {
+			col = columns[i]
+		}
+		if col.Name == "" {
+			col.Name = fftypes.ColumnName(fmt.Sprintf("column_%d", i))
+		}
+		profilers[i] = &columnProfiler{
+			profile:  fftypes.ColumnProfile{Name: string(col.Name), Type: profileTypeName(col.Type)},
+			numeric:  true,
+			strings:  true,
+			distinct: newDistinctSketch(profileDistinctSketchSize),
+			values:   make(map[string]int64),
+			// A fixed seed keeps profiles of the same data the same.
+			rand: rand.New(rand.NewSource(int64(i))),
+		}
+	}
+	return profilers
+}
+
+func profileTypeName(t fftypes.ValueType) string {
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
+
+func isNumericType(t fftypes.ValueType) bool {
+	if t == nil || t.IsVector() {
+		return false
+	}
+	switch t.Scalar() {
+	case fftypes.Int, fftypes.Int8, fftypes.Int16, fftypes.Int32, fftypes.Int64,
+		fftypes.UInt8, fftypes.UInt16, fftypes.UInt32, fftypes.UInt64,
+		fftypes.Float32, fftypes.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// columnProfiler accumulates the statistics of one column. A column is treated as numeric or as
+// a string column only if every non-null value is a number or a string respectively.
+type columnProfiler struct {
+	profile  fftypes.ColumnProfile
+	numeric  bool
+	strings  bool
+	mean     float64
+	m2       float64
+	distinct *distinctSketch
+	values   map[string]int64
+	// reservoir is a uniform sample of the column's numbers.
+	reservoir []float64
+	rand      *rand.Rand
+}
+
+func (p *columnProfiler) add(val any) {
+	if val == nil {
+		p.profile.NullCount++
+		return
+	}
+	p.profile.Count++
+	p.distinct.add(fmt.Sprintf("%v", val))
+	if f, ok := profileNumber(val); ok && p.numeric {
+		if p.profile.Count == 1 || f < p.profile.Min {
+			p.profile.Min = f
+		}
+		if p.profile.Count == 1 || f > p.profile.Max {
+			p.profile.Max = f
+		}
+		// Welford's algorithm keeps the variance stable over large columns.
+		delta := f - p.mean
+		p.mean += delta / float64(p.profile.Count)
+		p.m2 += delta * (f - p.mean)
+		if len(p.reservoir) < profileReservoirSize {
+			p.reservoir = append(p.reservoir, f)
+		} else if i := p.rand.Int63n(p.profile.Count); i < profileReservoirSize {
+			p.reservoir[i] = f
+		}
+	} else {
+		p.numeric = false
+	}
+	if s, ok := val.(string); ok && p.strings {
+		if _, has := p.values[s]; has || len(p.values) < profileMaxTrackedValues {
+			p.values[s]++
+		}
+	} else {
+		p.strings = false
+	}
+}
+
+func (p *columnProfiler) finish() fftypes.ColumnProfile {
+	profile := p.profile
+	profile.DistinctCount = p.distinct.estimate()
+	if profile.Count == 0 {
+		return profile
+	}
+	if p.numeric {
+		profile.Numeric = true
+		profile.Mean = p.mean
+		profile.StdDev = math.Sqrt(p.m2 / float64(profile.Count))
+		profile.Histogram = reservoirHistogram(profile, p.reservoir)
+	} else {
+		profile.Min, profile.Max = 0, 0
+	}
+	if p.strings {
+		profile.TopValues = topValues(p.values, profileTopValues)
+	}
+	return profile
+}
+
+// reservoirHistogram buckets a sample of a column's values, scaling the counts up to the whole
+// column.
+func reservoirHistogram(profile fftypes.ColumnProfile, sample []float64) []fftypes.HistogramBucket {
+	profile.Histogram = fftypes.EqualWidthBuckets(profile.Min, profile.Max, profileHistogramBuckets)
+	counts := make([]int64, len(profile.Histogram))
+	for _, f := range sample {
+		counts[profile.HistogramBucket(f)]++
+	}
+	scale := float64(profile.Count) / float64(len(sample))
+	for i, count := range counts {
+		profile.Histogram[i].Count = int64(math.Round(float64(count) * scale))
+	}
+	return profile.Histogram
+}
+
+func profileNumber(val any) (float64, bool) {
+	switch casted := val.(type) {
+	case int:
+		return float64(casted), true
+	case int8:
+		return float64(casted), true
+	case int16:
+		return float64(casted), true
+	case int32:
+		return float64(casted), true
+	case int64:
+		return float64(casted), true
+	case uint8:
+		return float64(casted), true
+	case uint16:
+		return float64(casted), true
+	case uint32:
+		return float64(casted), true
+	case uint64:
+		return float64(casted), true
+	case float32:
+		return float64(casted), true
+	case float64:
+		return casted, true
+	default:
+		return 0, false
+	}
+}
+
+// topValues returns the k most frequent values, breaking ties by value.
+func topValues(counts map[string]int64, k int) []fftypes.ValueCount {
+	values := make([]fftypes.ValueCount, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, fftypes.ValueCount{Value: value, Count: count})
+	}
+	sortValueCounts(values)
+	if len(values) > k {
+		values = values[:k]
+	}
+	return values
+}
+
+// sortValueCounts orders values from most to least frequent, breaking ties by value.
+func sortValueCounts(values []fftypes.ValueCount) {
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+}
+
+// distinctSketch estimates the number of distinct values from the k smallest hashes it has seen
+// (a k-minimum values sketch). The count is exact until more than k distinct values are added.
+type distinctSketch struct {
+	k      int
+	hashes hashHeap
+	seen   map[uint64]struct{}
+}
+
+func newDistinctSketch(k int) *distinctSketch {
+	return &distinctSketch{k: k, seen: make(map[uint64]struct{})}
+}
+
+func (s *distinctSketch) add(value string) {
+	h := fnv.New64a()
+	h.Write([]byte(value))
+	hash := mixHash(h.Sum64())
+	if _, has := s.seen[hash]; has {
+		return
+	}
+	if len(s.hashes) == s.k {
+		if hash >= s.hashes[0] {
+			return
+		}
+		delete(s.seen, heap.Pop(&s.hashes).(uint64))
+	}
+	heap.Push(&s.hashes, hash)
+	s.seen[hash] = struct{}{}
+}
+
+func (s *distinctSketch) estimate() int64 {
+	if len(s.hashes) < s.k {
+		return int64(len(s.hashes))
+	}
+	fraction := float64(s.hashes[0]) / float64(math.MaxUint64)
+	return int64(float64(s.k-1) / fraction)
+}
+
+// mixHash spreads FNV's output over the whole hash space, which the estimate relies on.
+func mixHash(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// hashHeap is a max-heap, so the largest of the k smallest hashes is at the top.
+type hashHeap []uint64
+
+func (h hashHeap) Len() int           { return len(h) }
+func (h hashHeap) Less(i, j int) bool { return h[i] > h[j] }
+func (h hashHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *hashHeap) Push(x any)        { *h = append(*h, x.(uint64)) }
+func (h *hashHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+func (store *sqlOfflineStore) ProfileDataset(ctx context.Context, ds dataset.Dataset, columns []fftypes.ColumnSchema) (*fftypes.DatasetProfile, error) {
+	sqlLocation, ok := ds.Location().(*pl.SQLLocation)
+	if !ok {
+		return profileDatasetFromIterator(ctx, ds, columns)
+	}
+	tableColumns := make([]string, len(columns))
+	for i, col := range columns {
+		if col.Name == "" {
+			return nil, fferr.NewInvalidArgumentErrorf("column %d of %s has no name", i, sqlLocation.Location())
+		}
+		tableColumns[i] = string(col.Name)
+	}
+	dbConn, err := store.getDb(sqlLocation.GetDatabase(), sqlLocation.GetSchema())
+	if err != nil {
+		return nil, fferr.NewConnectionError(store.Type().String(), err)
+	}
+	return store.profileTable(ctx, dbConn, sqlLocation, columns, tableColumns)
+}
+
+// ProfileTrainingSet profiles a training set's table in the database. Like the training set's
+// iterator, it reads the table's columns in order, so the first len(columns) of them are profiled
+// under the names in columns. Untyped columns take their type from the table when the store has a
+// value converter.
+func (store *sqlOfflineStore) ProfileTrainingSet(ctx context.Context, id ResourceID, columns []fftypes.ColumnSchema) (*fftypes.DatasetProfile, error) {
+	tableName, err := store.getTrainingSetName(id)
+	if err != nil {
+		return nil, err
+	}
+	sqlLocation := pl.NewSQLLocation(tableName)
+	schema, err := store.trainingSetSchema(sqlLocation)
+	if err != nil {
+		return nil, err
+	}
+	if len(schema.Fields) < len(columns) {
+		return nil, fferr.NewInternalErrorf("training set %s has %d columns, expected at least %d", tableName, len(schema.Fields), len(columns))
+	}
+	typed := make([]fftypes.ColumnSchema, len(columns))
+	tableColumns := make([]string, len(columns))
+	for i, col := range columns {
+		if col.Type == nil {
+			col.Type = schema.Fields[i].Type
+		}
+		typed[i] = col
+		tableColumns[i] = string(schema.Fields[i].Name)
+	}
+	return store.profileTable(ctx, store.db, sqlLocation, typed, tableColumns)
+}
+
+// trainingSetSchema reads a training set table's columns from the catalog, with their types if the
+// store's converter understands them.
+func (store *sqlOfflineStore) trainingSetSchema(sqlLocation *pl.SQLLocation) (fftypes.Schema, error) {
+	if converter, err := pt.GetConverter(store.Type()); err == nil {
+		schema, err := store.query.getSchema(store.db, converter, *sqlLocation)
+		if err == nil {
+			return schema, nil
+		}
+		store.logger.Debugw("Failed to read typed training set schema; profiling untyped columns", "table", sqlLocation.Location(), "error", err)
+	}
+	columns, err := store.query.getColumns(store.db, sqlLocation.GetTable())
+	if err != nil {
+		return fftypes.Schema{}, err
+	}
+	fields := make([]fftypes.ColumnSchema, len(columns))
+	for i, col := range columns {
+		fields[i] = fftypes.ColumnSchema{Name: fftypes.ColumnName(col.Name)}
+	}
+	return fftypes.Schema{Fields: fields}, nil
+}
+
+// profileTable profiles a table with at most three scans, whatever its width: one for the row
+// count and each column's stats, one for the histograms of numeric columns and one for the most
+// frequent values of string columns. tableColumns[i] is the table's column that columns[i]
+// profiles.
+func (store *sqlOfflineStore) profileTable(ctx context.Context, dbConn *sql.DB, sqlLocation *pl.SQLLocation, columns []fftypes.ColumnSchema, tableColumns []string) (*fftypes.DatasetProfile, error) {
+	table := pl.SanitizeFullyQualifiedObject(sqlLocation.TableLocation())
+	queryErr := func(err error) error {
+		wrapped := fferr.NewExecutionError(store.Type().String(), err)
+		wrapped.AddDetail("table_name", sqlLocation.Location())
+		return wrapped
+	}
+
+	profile := &fftypes.DatasetProfile{Columns: make([]fftypes.ColumnProfile, len(columns))}
+	type numericStats struct {
+		min, max, mean, stddev sql.NullFloat64
+	}
+	stats := make([]numericStats, len(columns))
+	dest := []any{&profile.RowCount}
+	for i, col := range columns {
+		column := &profile.Columns[i]
+		*column = fftypes.ColumnProfile{Name: string(col.Name), Type: profileTypeName(col.Type), Numeric: isNumericType(col.Type)}
+		dest = append(dest, &column.Count, &column.DistinctCount)
+		if column.Numeric {
+			dest = append(dest, &stats[i].min, &stats[i].max, &stats[i].mean, &stats[i].stddev)
+		}
+	}
+	query := tableProfileQuery(columns, tableColumns, table)
+	store.logger.Debugw("Profiling table", "table", sqlLocation.Location(), "query", query)
+	if err := dbConn.QueryRowContext(ctx, query).Scan(dest...); err != nil {
+		return nil, queryErr(err)
+	}
+	for i := range profile.Columns {
+		column := &profile.Columns[i]
+		column.NullCount = profile.RowCount - column.Count
+		column.Min, column.Max, column.Mean, column.StdDev = stats[i].min.Float64, stats[i].max.Float64, stats[i].mean.Float64, stats[i].stddev.Float64
+	}
+	if err := store.queryHistograms(ctx, dbConn, tableColumns, table, profile.Columns); err != nil {
+		return nil, queryErr(err)
+	}
+	if err := store.queryTopValues(ctx, dbConn, columns, tableColumns, table, profile.Columns); err != nil {
+		return nil, queryErr(err)
+	}
+	return profile, nil
+}
+
+// queryHistograms fills in the histograms of the profiled numeric columns with one query.
+func (store *sqlOfflineStore) queryHistograms(ctx context.Context, dbConn *sql.DB, tableColumns []string, table string, profiles []fftypes.ColumnProfile) error {
+	var buckets []histogramBuckets
+	for i := range profiles {
+		column := &profiles[i]
+		if !column.Numeric || column.Count == 0 {
+			continue
+		}
+		column.Histogram = fftypes.EqualWidthBuckets(column.Min, column.Max, profileHistogramBuckets)
+		if len(column.Histogram) == 1 {
+			column.Histogram[0].Count = column.Count
+			continue
+		}
+		buckets = append(buckets, histogramBuckets{column: tableColumns[i], min: column.Min, max: column.Max, counts: column.Histogram})
+	}
+	if len(buckets) == 0 {
+		return nil
+	}
+	var dest []any
+	for _, b := range buckets {
+		for i := range b.counts {
+			dest = append(dest, &b.counts[i].Count)
+		}
+	}
+	return dbConn.QueryRowContext(ctx, histogramQuery(buckets, table)).Scan(dest...)
+}
+
+// queryTopValues fills in the most frequent values of the profiled string columns with one query.
+func (store *sqlOfflineStore) queryTopValues(ctx context.Context, dbConn *sql.DB, columns []fftypes.ColumnSchema, tableColumns []string, table string, profiles []fftypes.ColumnProfile) error {
+	var indexes []int
+	for i, col := range columns {
+		if col.Type != nil && !col.Type.IsVector() && col.Type.Scalar() == fftypes.String && profiles[i].Count > 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+	rows, err := dbConn.QueryContext(ctx, topValuesQuery(indexes, tableColumns, table, profileTopValues))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var idx int
+		var value fftypes.ValueCount
+		if err := rows.Scan(&idx, &value.Value, &value.Count); err != nil {
+			return err
+		}
+		if idx >= 0 && idx < len(profiles) {
+			profiles[idx].TopValues = append(profiles[idx].TopValues, value)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	// UNION ALL doesn't keep each column's ordering.
+	for _, idx := range indexes {
+		sortValueCounts(profiles[idx].TopValues)
+	}
+	return nil
+}
+
+// tableProfileQuery selects the table's row count followed by, for each column, its non-null
+// count and distinct count, and its min, max, mean and standard deviation if it's numeric.
+func tableProfileQuery(columns []fftypes.ColumnSchema, tableColumns []string, table string) string {
+	selects := []string{"COUNT(*)"}
+	for i, col := range columns {
+		column := sanitize(tableColumns[i])
+		selects = append(selects, fmt.Sprintf("COUNT(%s)", column), fmt.Sprintf("COUNT(DISTINCT %s)", column))
+		if isNumericType(col.Type) {
+			selects = append(selects,
+				fmt.Sprintf("MIN(%s)", column), fmt.Sprintf("MAX(%s)", column),
+				fmt.Sprintf("AVG(%s)", column), fmt.Sprintf("STDDEV_POP(%s)", column),
+			)
+		}
+	}
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(selects, ", "), table)
+}
+
+// histogramBuckets is a numeric column's equal-width buckets over [min, max].
+type histogramBuckets struct {
+	column   string
+	min, max float64
+	counts   []fftypes.HistogramBucket
+}
+
+// histogramQuery selects the count of each bucket of each column, in order. A value's bucket is
+// the same as in ColumnProfile.HistogramBucket, with max going in the last bucket.
+func histogramQuery(buckets []histogramBuckets, table string) string {
+	var selects []string
+	for _, b := range buckets {
+		column := sanitize(b.column)
+		n := len(b.counts)
+		bucket := fmt.Sprintf(
+			"CASE WHEN %s >= %s THEN %d ELSE FLOOR((%s - (%s)) / %s) END",
+			column, strconv.FormatFloat(b.max, 'g', -1, 64), n-1, column,
+			strconv.FormatFloat(b.min, 'g', -1, 64), strconv.FormatFloat((b.max-b.min)/float64(n), 'g', -1, 64),
+		)
+		for i := 0; i < n; i++ {
+			selects = append(selects, fmt.Sprintf("COUNT(CASE WHEN %s = %d THEN 1 END)", bucket, i))
+		}
+	}
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(selects, ", "), table)
+}
+
+// topValuesQuery selects the k most frequent non-null values of each of the columns at indexes,
+// as rows of the column's index, the value and its count.
+func topValuesQuery(indexes []int, tableColumns []string, table string, k int) string {
+	selects := make([]string, len(indexes))
+	for i, idx := range indexes {
+		column := sanitize(tableColumns[idx])
+		selects[i] = fmt.Sprintf(
+			"SELECT %d, top_value, top_count FROM (SELECT %s AS top_value, COUNT(*) AS top_count FROM %s WHERE %s IS NOT NULL GROUP BY %s ORDER BY COUNT(*) DESC, %s LIMIT %d) AS top_%d",
+			idx, column, table, column, column, column, k, idx,
+		)
+	}
+	return strings.Join(selects, " UNION ALL ")
+}
@@ -0,0 +1,325 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+
+	fftypes "github.com/featureform/fftypes"
+	"github.com/featureform/logging"
+	"github.com/featureform/provider/dataset"
+	pl "github.com/featureform/provider/location"
+	ps "github.com/featureform/provider/provider_schema"
+)
+
+func TestProfileDataset(t *testing.T) {
+	profile, err := ProfileDataset(context.Background(), NewMemoryOfflineStore(), expectationTestDataset(), nil)
+	if err != nil {
+		t.Fatalf("Failed to profile dataset: %v", err)
+	}
+	if profile.RowCount != 5 {
+		t.Fatalf("Expected 5 rows, got %d", profile.RowCount)
+	}
+	entity := fftypes.ColumnProfile{
+		Name:          "entity",
+		Type:          "string",
+		Count:         5,
+		DistinctCount: 3,
+		TopValues:     []fftypes.ValueCount{{Value: "a", Count: 2}, {Value: "b", Count: 2}, {Value: "c", Count: 1}},
+	}
+	if !reflect.DeepEqual(profile.Columns[0], entity) {
+		t.Fatalf("Wrong entity profile\nexpected: %+v\n     got: %+v", entity, profile.Columns[0])
+	}
+	amount := profile.Columns[1]
+	if amount.Count != 4 || amount.NullCount != 1 || amount.DistinctCount != 4 || !amount.Numeric {
+		t.Fatalf("Wrong amount counts: %+v", amount)
+	}
+	// Values are 1.5, -2, 30 and 4.
+	if amount.Min != -2 || amount.Max != 30 || amount.Mean != 8.375 || len(amount.TopValues) != 0 {
+		t.Fatalf("Wrong amount stats: %+v", amount)
+	}
+	if expected := math.Sqrt(160.421875); math.Abs(amount.StdDev-expected) > 1e-9 {
+		t.Fatalf("Expected stddev %f, got %f", expected, amount.StdDev)
+	}
+	counts := make([]int64, len(amount.Histogram))
+	for i, bucket := range amount.Histogram {
+		counts[i] = bucket.Count
+	}
+	if expected := []int64{1, 2, 0, 0, 0, 0, 0, 0, 0, 1}; !reflect.DeepEqual(counts, expected) {
+		t.Fatalf("Wrong amount histogram\nexpected: %v\n     got: %v", expected, counts)
+	}
+	if ts := profile.Columns[2]; ts.Min != 1 || ts.Max != 2 || ts.DistinctCount != 2 {
+		t.Fatalf("Wrong ts stats: %+v", ts)
+	}
+}
+
+func TestProfileIteratorColumns(t *testing.T) {
+	rows := []fftypes.Row{
+		{{Value: "a"}, {Value: true}, {Value: int64(1)}},
+		{{Value: "b"}, {Value: nil}, {Value: "two"}},
+	}
+	ds := dataset.NewInMemoryDataset(rows, fftypes.Schema{}, pl.NewSQLLocation("materialization"))
+	iter, err := ds.Iterator(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("Failed to get iterator: %v", err)
+	}
+	defer iter.Close()
+	profile, err := ProfileIterator(iter, MaterializationColumns(fftypes.Bool)[:2])
+	if err != nil {
+		t.Fatalf("Failed to profile iterator: %v", err)
+	}
+	names := make([]string, len(profile.Columns))
+	for i, col := range profile.Columns {
+		names[i] = col.Name
+	}
+	if !reflect.DeepEqual(names, []string{"entity", "value", "column_2"}) {
+		t.Fatalf("Wrong column names: %v", names)
+	}
+	if value := profile.Columns[1]; value.Type != "bool" || value.Numeric || value.NullCount != 1 || value.TopValues != nil {
+		t.Fatalf("Wrong value profile: %+v", value)
+	}
+	if mixed := profile.Columns[2]; mixed.Numeric || mixed.Min != 0 || mixed.TopValues != nil {
+		t.Fatalf("Columns with mixed values shouldn't get numeric or string stats: %+v", mixed)
+	}
+}
+
+func TestDistinctSketch(t *testing.T) {
+	small := newDistinctSketch(64)
+	for i := 0; i < 100; i++ {
+		small.add(fmt.Sprintf("%d", i%50))
+	}
+	if small.estimate() != 50 {
+		t.Fatalf("Expected an exact count of 50, got %d", small.estimate())
+	}
+	large := newDistinctSketch(1024)
+	for i := 0; i < 100000; i++ {
+		large.add(fmt.Sprintf("value-%d", i))
+	}
+	if estimate := large.estimate(); estimate < 90000 || estimate > 110000 {
+		t.Fatalf("Expected an estimate within 10%% of 100000, got %d", estimate)
+	}
+}
+
+func TestProfileQueries(t *testing.T) {
+	table := `"materialization"`
+	tests := map[string]struct {
+		query    string
+		expected string
+	}{
+		"Table": {
+			tableProfileQuery(
+				[]fftypes.ColumnSchema{
+					{Name: "entity", Type: fftypes.String},
+					{Name: "value", Type: fftypes.Float64},
+					{Name: "embedding", Type: fftypes.VectorType{ScalarType: fftypes.Float32, Dimension: 3}},
+				},
+				[]string{"entity", "value", "embedding"},
+				table,
+			),
+			`SELECT COUNT(*), COUNT("entity"), COUNT(DISTINCT "entity"), COUNT("value"), COUNT(DISTINCT "value"), MIN("value"), MAX("value"), AVG("value"), STDDEV_POP("value"), COUNT("embedding"), COUNT(DISTINCT "embedding") FROM "materialization"`,
+		},
+		"TopValues": {
+			topValuesQuery([]int{0, 2}, []string{"entity", "value", "merchant"}, table, 10),
+			`SELECT 0, top_value, top_count FROM (SELECT "entity" AS top_value, COUNT(*) AS top_count FROM "materialization" WHERE "entity" IS NOT NULL GROUP BY "entity" ORDER BY COUNT(*) DESC, "entity" LIMIT 10) AS top_0` +
+				` UNION ALL SELECT 2, top_value, top_count FROM (SELECT "merchant" AS top_value, COUNT(*) AS top_count FROM "materialization" WHERE "merchant" IS NOT NULL GROUP BY "merchant" ORDER BY COUNT(*) DESC, "merchant" LIMIT 10) AS top_2`,
+		},
+		"Histogram": {
+			histogramQuery([]histogramBuckets{{column: "value", min: -2, max: 30, counts: make([]fftypes.HistogramBucket, 2)}}, table),
+			`SELECT COUNT(CASE WHEN CASE WHEN "value" >= 30 THEN 1 ELSE FLOOR(("value" - (-2)) / 16) END = 0 THEN 1 END),` +
+				` COUNT(CASE WHEN CASE WHEN "value" >= 30 THEN 1 ELSE FLOOR(("value" - (-2)) / 16) END = 1 THEN 1 END) FROM "materialization"`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if test.query != test.expected {
+				t.Fatalf("Unexpected query\nexpected: %s\n     got: %s", test.expected, test.query)
+			}
+		})
+	}
+}
+
+var registerProfileTestDriver sync.Once
+
+// openProfileTestDB opens an in-memory SQLite database. SQLite doesn't have FLOOR or STDDEV_POP,
+// so they're added to run the queries the warehouses would.
+func openProfileTestDB(t *testing.T) *sql.DB {
+	registerProfileTestDriver.Do(func() {
+		sql.Register("sqlite3_profile", &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				floor := func(value any) any {
+					if f, ok := profileNumber(value); ok {
+						return math.Floor(f)
+					}
+					return nil
+				}
+				if err := conn.RegisterFunc("floor", floor, true); err != nil {
+					return err
+				}
+				return conn.RegisterAggregator("stddev_pop", newStddevPop, true)
+			},
+		})
+	})
+	db, err := sql.Open("sqlite3_profile", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	// Every connection gets its own in-memory database.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+type stddevPop struct {
+	values []float64
+}
+
+func newStddevPop() *stddevPop {
+	return &stddevPop{}
+}
+
+func (s *stddevPop) Step(value any) {
+	if f, ok := profileNumber(value); ok {
+		s.values = append(s.values, f)
+	}
+}
+
+func (s *stddevPop) Done() any {
+	if len(s.values) == 0 {
+		return nil
+	}
+	var sum, squares float64
+	for _, f := range s.values {
+		sum += f
+	}
+	mean := sum / float64(len(s.values))
+	for _, f := range s.values {
+		squares += (f - mean) * (f - mean)
+	}
+	return math.Sqrt(squares / float64(len(s.values)))
+}
+
+// sqliteProfileQueries reads column names from SQLite's catalog, which has no information_schema.
+type sqliteProfileQueries struct {
+	defaultOfflineSQLQueries
+}
+
+func (q *sqliteProfileQueries) getColumns(db *sql.DB, name string) ([]TableColumn, error) {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?) ORDER BY cid", name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var columns []TableColumn
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		columns = append(columns, TableColumn{Name: column})
+	}
+	return columns, rows.Err()
+}
+
+func newProfileTestStore(t *testing.T, db *sql.DB) *sqlOfflineStore {
+	return &sqlOfflineStore{
+		db:     db,
+		query:  &sqliteProfileQueries{},
+		getDb:  func(database, schema string) (*sql.DB, error) { return db, nil },
+		logger: logging.NewTestLogger(t),
+	}
+}
+
+// TestSQLProfileDatasetMatchesIterator runs the pushed down queries and checks that they profile a
+// table the same way reading it row by row does.
+func TestSQLProfileDatasetMatchesIterator(t *testing.T) {
+	ctx := context.Background()
+	db := openProfileTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE "transactions" ("entity" TEXT, "amount" REAL, "ts" INTEGER)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	ds := expectationTestDataset()
+	iter, err := ds.Iterator(ctx, 0)
+	if err != nil {
+		t.Fatalf("Failed to get iterator: %v", err)
+	}
+	for iter.Next() {
+		row := iter.Values()
+		if _, err := db.Exec(`INSERT INTO "transactions" VALUES (?, ?, ?)`, row[0].Value, row[1].Value, row[2].Value); err != nil {
+			t.Fatalf("Failed to insert row: %v", err)
+		}
+	}
+	iter.Close()
+
+	expected, err := ProfileDataset(ctx, NewMemoryOfflineStore(), ds, nil)
+	if err != nil {
+		t.Fatalf("Failed to profile dataset by reading it: %v", err)
+	}
+	actual, err := ProfileDataset(ctx, newProfileTestStore(t, db), ds, nil)
+	if err != nil {
+		t.Fatalf("Failed to profile dataset in SQL: %v", err)
+	}
+	for i := range actual.Columns {
+		if math.Abs(actual.Columns[i].Mean-expected.Columns[i].Mean) > 1e-9 || math.Abs(actual.Columns[i].StdDev-expected.Columns[i].StdDev) > 1e-9 {
+			t.Fatalf("Wrong mean or stddev of %s\nexpected: %+v\n     got: %+v", actual.Columns[i].Name, expected.Columns[i], actual.Columns[i])
+		}
+		actual.Columns[i].Mean, actual.Columns[i].StdDev = expected.Columns[i].Mean, expected.Columns[i].StdDev
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("SQL profile differs from the iterator's\nexpected: %+v\n     got: %+v", expected, actual)
+	}
+}
+
+func TestSQLProfileTrainingSet(t *testing.T) {
+	db := openProfileTestDB(t)
+	id := ResourceID{Name: "fraud", Variant: "v1", Type: TrainingSet}
+	tableName, err := ps.ResourceToTableName(id.Type.String(), id.Name, id.Variant)
+	if err != nil {
+		t.Fatalf("Failed to get table name: %v", err)
+	}
+	if _, err := db.Exec(fmt.Sprintf(`CREATE TABLE "%s" ("amount" REAL, "merchant" TEXT, "label" INTEGER)`, tableName)); err != nil {
+		t.Fatalf("Failed to create training set: %v", err)
+	}
+	rows := [][]any{{1.0, "m1", 0}, {3.0, "m1", 1}, {nil, "m2", 1}}
+	for _, row := range rows {
+		if _, err := db.Exec(fmt.Sprintf(`INSERT INTO "%s" VALUES (?, ?, ?)`, tableName), row...); err != nil {
+			t.Fatalf("Failed to insert row: %v", err)
+		}
+	}
+	// Like the training set task's columns, these are named differently than the table's and the
+	// label is untyped.
+	columns := []fftypes.ColumnSchema{
+		{Name: "feature__amount__v1", Type: fftypes.Float64},
+		{Name: "feature__merchant__v1", Type: fftypes.String},
+		{Name: "label__is_fraud__v1"},
+	}
+	profile, err := ProfileStoredTrainingSet(context.Background(), newProfileTestStore(t, db), id, columns, true)
+	if err != nil {
+		t.Fatalf("Failed to profile training set: %v", err)
+	}
+	if profile.RowCount != 3 || len(profile.Columns) != 3 {
+		t.Fatalf("Wrong profile shape: %+v", profile)
+	}
+	if amount := profile.Columns[0]; amount.Name != "feature__amount__v1" || amount.Count != 2 || amount.NullCount != 1 || amount.Mean != 2 || amount.Min != 1 || amount.Max != 3 {
+		t.Fatalf("Wrong amount profile: %+v", amount)
+	}
+	merchant := profile.Columns[1]
+	if expected := []fftypes.ValueCount{{Value: "m1", Count: 2}, {Value: "m2", Count: 1}}; !reflect.DeepEqual(merchant.TopValues, expected) {
+		t.Fatalf("Wrong merchant top values\nexpected: %v\n     got: %v", expected, merchant.TopValues)
+	}
+	if label := profile.Columns[2]; label.Name != "label__is_fraud__v1" || label.Count != 3 || label.DistinctCount != 2 || label.Numeric {
+		t.Fatalf("Wrong label profile: %+v", label)
+	}
+}
@@ -12,6 +12,7 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	pl "github.com/featureform/provider/location"
 
@@ -58,6 +59,19 @@ type Provider interface {
 	Delete(location pl.Location) error
 }
 
+// ContextHealthChecker is implemented by providers whose health check stops when ctx is done.
+type ContextHealthChecker interface {
+	CheckHealthContext(ctx context.Context) (bool, error)
+}
+
+// CheckHealth runs the health check of p, cancelling it with ctx if p supports that.
+func CheckHealth(ctx context.Context, p Provider) (bool, error) {
+	if checker, ok := p.(ContextHealthChecker); ok {
+		return checker.CheckHealthContext(ctx)
+	}
+	return p.CheckHealth()
+}
+
 type BaseProvider struct {
 	ProviderType   pt.Type
 	ProviderConfig pc.SerializedConfig
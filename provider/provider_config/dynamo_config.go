@@ -22,6 +22,7 @@ type DynamodbConfig struct {
 	Endpoint           string
 	StronglyConsistent bool
 	Tags               map[string]string
+	WriteBudget        WriteBudget
 }
 
 type dynamodbConfigTemp struct {
@@ -31,6 +32,7 @@ type dynamodbConfigTemp struct {
 	Endpoint           string
 	StronglyConsistent bool
 	Tags               map[string]string
+	WriteBudget        WriteBudget
 }
 
 func (d DynamodbConfig) Serialized() SerializedConfig {
@@ -51,6 +53,10 @@ func (d *DynamodbConfig) Deserialize(config []byte) error {
 	d.Region = temp.Region
 	d.StronglyConsistent = temp.StronglyConsistent
 	d.Tags = temp.Tags
+	if err := temp.WriteBudget.Validate(); err != nil {
+		return err
+	}
+	d.WriteBudget = temp.WriteBudget
 
 	creds, err := UnmarshalAWSCredentials(temp.Credentials)
 	if err != nil {
@@ -65,6 +71,7 @@ func (d DynamodbConfig) MutableFields() ss.StringSet {
 	return ss.StringSet{
 		"Credentials": true,
 		"Tags":        true,
+		"WriteBudget": true,
 	}
 }
 
@@ -18,6 +18,7 @@ func TestDynamoConfigMutableFields(t *testing.T) {
 	expected := ss.StringSet{
 		"Credentials": true,
 		"Tags":        true,
+		"WriteBudget": true,
 	}
 
 	config := DynamodbConfig{
@@ -115,6 +116,20 @@ func TestDynamodbConfig_SerializationDeserialization(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "write budget",
+			config: DynamodbConfig{
+				Prefix:      "budgetedTablePrefix",
+				Region:      "us-east-1",
+				Credentials: AWSAssumeRoleCredentials{},
+				WriteBudget: WriteBudget{
+					RowsPerSecond:   1000,
+					MaxConcurrency:  8,
+					TargetLatencyMs: 50,
+				},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
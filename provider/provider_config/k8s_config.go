@@ -23,6 +23,10 @@ type K8sConfig struct {
 	ExecutorConfig interface{}
 	StoreType      filestore.FileStoreType
 	StoreConfig    FileStoreConfig
+	// FileStoreTableFormat writes resources as Delta tables instead of plain Parquet directories.
+	// Iceberg isn't supported because the Pandas runner has no way to commit an Iceberg table
+	// without a catalog.
+	FileStoreTableFormat TableFormat
 }
 
 func (k8s *K8sConfig) Serialize() ([]byte, error) {
@@ -47,6 +51,8 @@ func (k8s *K8sConfig) UnmarshalJSON(data []byte) error {
 		ExecutorConfig interface{}
 		StoreType      filestore.FileStoreType
 		StoreConfig    map[string]interface{}
+
+		FileStoreTableFormat TableFormat
 	}
 
 	var temp tempConfig
@@ -58,6 +64,13 @@ func (k8s *K8sConfig) UnmarshalJSON(data []byte) error {
 	k8s.ExecutorType = temp.ExecutorType
 	k8s.StoreType = temp.StoreType
 
+	switch temp.FileStoreTableFormat {
+	case "", DeltaLake:
+		k8s.FileStoreTableFormat = temp.FileStoreTableFormat
+	default:
+		return fferr.NewProviderConfigError("Kubernetes", fmt.Errorf("the file store table format '%s' is not supported for k8s", temp.FileStoreTableFormat))
+	}
+
 	if temp.ExecutorConfig == "" {
 		k8s.ExecutorConfig = ExecutorConfig{}
 	} else {
@@ -163,6 +176,10 @@ func (a K8sConfig) DifferingFields(b K8sConfig) (ss.StringSet, error) {
 		result["Store."+field] = val
 	}
 
+	if a.FileStoreTableFormat != b.FileStoreTableFormat {
+		result["FileStoreTableFormat"] = true
+	}
+
 	return result, err
 }
 
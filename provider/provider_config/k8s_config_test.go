@@ -129,3 +129,48 @@ func TestK8sConfigDifferingFields(t *testing.T) {
 		})
 	}
 }
+
+func TestK8sConfigFileStoreTableFormat(t *testing.T) {
+	config := K8sConfig{
+		ExecutorType:   K8s,
+		ExecutorConfig: ExecutorConfig{DockerImage: "container"},
+		StoreType:      filestore.Azure,
+		StoreConfig: &AzureFileStoreConfig{
+			AccountName:   "account name",
+			AccountKey:    "account key",
+			ContainerName: "container name",
+			Path:          "container path",
+		},
+		FileStoreTableFormat: DeltaLake,
+	}
+	serialized, err := config.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize config: %v", err)
+	}
+	deserialized := K8sConfig{}
+	if err := deserialized.Deserialize(serialized); err != nil {
+		t.Fatalf("Failed to deserialize config: %v", err)
+	}
+	if deserialized.FileStoreTableFormat != DeltaLake {
+		t.Fatalf("Expected table format %s, got %q", DeltaLake, deserialized.FileStoreTableFormat)
+	}
+
+	changed := config
+	changed.FileStoreTableFormat = ""
+	differing, err := config.DifferingFields(changed)
+	if err != nil {
+		t.Fatalf("Failed to get differing fields: %v", err)
+	}
+	if !differing["FileStoreTableFormat"] || config.MutableFields()["FileStoreTableFormat"] {
+		t.Fatalf("Expected FileStoreTableFormat to differ and be immutable, got %v", differing)
+	}
+
+	config.FileStoreTableFormat = Iceberg
+	serialized, err = config.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize config: %v", err)
+	}
+	if err := (&K8sConfig{}).Deserialize(serialized); err == nil {
+		t.Fatalf("Expected error for iceberg table format on k8s")
+	}
+}
@@ -0,0 +1,80 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package provider_config
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/featureform/logging/redacted"
+)
+
+// sensitiveConfigFields are substrings of normalized field names (lower case, without
+// separators) whose values are secrets.
+var sensitiveConfigFields = []string{
+	"password",
+	"passphrase",
+	"secret",
+	"token",
+	"apikey",
+	"accountkey",
+	"privatekey",
+	"connectionstring",
+	"keytab",
+}
+
+func isSensitiveConfigField(name string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+	for _, field := range sensitiveConfigFields {
+		if strings.Contains(normalized, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactSerializedConfig replaces the secrets in a serialized provider config with
+// redacted.String, keeping everything else so clients can still parse it. Configs that aren't
+// JSON objects are redacted entirely since there's no way to tell what they contain.
+func RedactSerializedConfig(config SerializedConfig) SerializedConfig {
+	if len(config) == 0 {
+		return config
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(config, &fields); err != nil {
+		return SerializedConfig(redacted.String)
+	}
+	redactedConfig, err := json.Marshal(redactConfigValue(fields))
+	if err != nil {
+		return SerializedConfig(redacted.String)
+	}
+	return redactedConfig
+}
+
+func redactConfigValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		// Objects under a sensitive name, like a secret reference {"type": "vault", ...}, are
+		// redacted field by field instead.
+		for key, child := range v {
+			if _, isObject := child.(map[string]interface{}); isSensitiveConfigField(key) && !isObject && child != nil {
+				v[key] = redacted.String
+			} else {
+				v[key] = redactConfigValue(child)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactConfigValue(child)
+		}
+		return v
+	default:
+		return v
+	}
+}
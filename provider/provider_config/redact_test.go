@@ -0,0 +1,57 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package provider_config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/featureform/logging/redacted"
+)
+
+func TestRedactSerializedConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			"Postgres",
+			`{"Host": "localhost", "Port": "5432", "Password": "hunter2"}`,
+			`{"Host": "localhost", "Port": "5432", "Password": "` + redacted.String + `"}`,
+		},
+		{
+			"Nested credentials",
+			`{"Region": "us-east-1", "Credentials": {"Type": "AWS_STATIC_CREDENTIALS", "AccessKeyId": "AKID", "SecretKey": "shh"}}`,
+			`{"Region": "us-east-1", "Credentials": {"Type": "AWS_STATIC_CREDENTIALS", "AccessKeyId": "AKID", "SecretKey": "` + redacted.String + `"}}`,
+		},
+		{
+			"Service account",
+			`{"ProjectID": "ff", "Credentials": {"client_email": "ff@example.com", "private_key": "-----BEGIN"}}`,
+			`{"ProjectID": "ff", "Credentials": {"client_email": "ff@example.com", "private_key": "` + redacted.String + `"}}`,
+		},
+		{
+			"Secret reference",
+			`{"Password": {"type": "vault", "path": "featureform/postgres", "field": "password"}}`,
+			`{"Password": {"type": "vault", "path": "featureform/postgres", "field": "password"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := RedactSerializedConfig(SerializedConfig(tt.config))
+			var expected, parsed interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.expected), &expected))
+			assert.NoError(t, json.Unmarshal(actual, &parsed))
+			assert.Equal(t, expected, parsed)
+		})
+	}
+	assert.Equal(t, redacted.String, string(RedactSerializedConfig(SerializedConfig("host=localhost password=hunter2"))))
+	assert.Empty(t, RedactSerializedConfig(nil))
+}
@@ -16,10 +16,11 @@ import (
 )
 
 type RedisConfig struct {
-	Prefix   string
-	Addr     string
-	Password string
-	DB       int
+	Prefix      string
+	Addr        string
+	Password    string
+	DB          int
+	WriteBudget WriteBudget
 }
 
 func (r RedisConfig) Serialized() SerializedConfig {
@@ -35,12 +36,13 @@ func (r *RedisConfig) Deserialize(config SerializedConfig) error {
 	if err != nil {
 		return fferr.NewInternalError(err)
 	}
-	return nil
+	return r.WriteBudget.Validate()
 }
 
 func (r RedisConfig) MutableFields() ss.StringSet {
 	return ss.StringSet{
-		"Password": true,
+		"Password":    true,
+		"WriteBudget": true,
 	}
 }
 
@@ -16,7 +16,8 @@ import (
 
 func TestRedisConfigMutableFields(t *testing.T) {
 	expected := ss.StringSet{
-		"Password": true,
+		"Password":    true,
+		"WriteBudget": true,
 	}
 
 	config := RedisConfig{
@@ -22,13 +22,19 @@ type SparkExecutorType string
 type TableFormat string
 
 const (
-	EMR          SparkExecutorType = "EMR"
-	Databricks   SparkExecutorType = "DATABRICKS"
-	SparkGeneric SparkExecutorType = "SPARK"
-	Iceberg      TableFormat       = "iceberg"
-	DeltaLake    TableFormat       = "delta"
+	EMR             SparkExecutorType = "EMR"
+	Databricks      SparkExecutorType = "DATABRICKS"
+	SparkGeneric    SparkExecutorType = "SPARK"
+	SparkKubernetes SparkExecutorType = "SPARK_KUBERNETES"
+	Iceberg         TableFormat       = "iceberg"
+	DeltaLake       TableFormat       = "delta"
 )
 
+// IsValid reports whether t is a known table format or empty.
+func (t TableFormat) IsValid() bool {
+	return t == "" || t == Iceberg || t == DeltaLake
+}
+
 type GCPCredentials struct {
 	ProjectId string
 	JSON      map[string]interface{}
@@ -66,6 +72,9 @@ type SparkConfig struct {
 	StoreType      fs.FileStoreType
 	StoreConfig    SparkFileStoreConfig
 	GlueConfig     *GlueConfig // GlueConfig is optional
+	// FileStoreTableFormat writes resources to the file store as Delta or Iceberg tables instead
+	// of plain Parquet directories. It's ignored when a catalog is used.
+	FileStoreTableFormat TableFormat
 }
 
 type sparkConfigTemp struct {
@@ -74,6 +83,8 @@ type sparkConfigTemp struct {
 	StoreType      fs.FileStoreType
 	StoreConfig    json.RawMessage
 	GlueConfig     *GlueConfig
+
+	FileStoreTableFormat TableFormat
 }
 
 func (s *SparkConfig) Deserialize(config SerializedConfig) error {
@@ -86,6 +97,10 @@ func (s *SparkConfig) Deserialize(config SerializedConfig) error {
 	s.ExecutorType = temp.ExecutorType
 	s.StoreType = temp.StoreType
 	s.GlueConfig = temp.GlueConfig
+	s.FileStoreTableFormat = temp.FileStoreTableFormat
+	if !s.FileStoreTableFormat.IsValid() {
+		return fferr.NewProviderConfigError("Spark", fmt.Errorf("unknown file store table format '%s'", s.FileStoreTableFormat))
+	}
 
 	execData, err := json.Marshal(temp.ExecutorConfig)
 	if err != nil {
@@ -99,6 +114,8 @@ func (s *SparkConfig) Deserialize(config SerializedConfig) error {
 		s.ExecutorConfig = &DatabricksConfig{}
 	case SparkGeneric:
 		s.ExecutorConfig = &SparkGenericConfig{}
+	case SparkKubernetes:
+		s.ExecutorConfig = &SparkKubernetesConfig{}
 	default:
 	}
 
@@ -145,6 +162,8 @@ func (s *SparkConfig) UnmarshalJSON(data []byte) error {
 		StoreType      fs.FileStoreType
 		StoreConfig    map[string]interface{}
 		GlueConfig     *GlueConfig
+
+		FileStoreTableFormat TableFormat
 	}
 
 	var temp tempConfig
@@ -156,6 +175,10 @@ func (s *SparkConfig) UnmarshalJSON(data []byte) error {
 	s.ExecutorType = temp.ExecutorType
 	s.StoreType = temp.StoreType
 	s.GlueConfig = temp.GlueConfig
+	s.FileStoreTableFormat = temp.FileStoreTableFormat
+	if !s.FileStoreTableFormat.IsValid() {
+		return fferr.NewProviderConfigError("Spark", fmt.Errorf("unknown file store table format '%s'", s.FileStoreTableFormat))
+	}
 
 	err = s.decodeExecutor(temp.ExecutorType, temp.ExecutorConfig)
 	if err != nil {
@@ -189,6 +212,8 @@ func (s SparkConfig) MutableFields() ss.StringSet {
 		executorFields = s.ExecutorConfig.(*DatabricksConfig).MutableFields()
 	case SparkGeneric:
 		executorFields = s.ExecutorConfig.(*SparkGenericConfig).MutableFields()
+	case SparkKubernetes:
+		executorFields = s.ExecutorConfig.(*SparkKubernetesConfig).MutableFields()
 	default:
 		executorFields = ss.StringSet{}
 	}
@@ -250,6 +275,8 @@ func (a SparkConfig) DifferingFields(b SparkConfig) (ss.StringSet, error) {
 		executorFields, err = a.ExecutorConfig.(*DatabricksConfig).DifferingFields(*b.ExecutorConfig.(*DatabricksConfig))
 	case SparkGeneric:
 		executorFields, err = a.ExecutorConfig.(*SparkGenericConfig).DifferingFields(*b.ExecutorConfig.(*SparkGenericConfig))
+	case SparkKubernetes:
+		executorFields, err = a.ExecutorConfig.(*SparkKubernetesConfig).DifferingFields(*b.ExecutorConfig.(*SparkKubernetesConfig))
 	default:
 		return nil, fferr.NewProviderConfigError("Spark", fmt.Errorf("unknown executor type: %v", a.ExecutorType))
 	}
@@ -283,6 +310,11 @@ func (a SparkConfig) DifferingFields(b SparkConfig) (ss.StringSet, error) {
 		result["Store."+field] = val
 	}
 
+	// Existing resources can't be read in another format, so this is never mutable.
+	if a.FileStoreTableFormat != b.FileStoreTableFormat {
+		result["FileStoreTableFormat"] = true
+	}
+
 	return result, err
 }
 
@@ -299,6 +331,8 @@ func (s *SparkConfig) decodeExecutor(executorType SparkExecutorType, configMap m
 		executorConfig = &DatabricksConfig{}
 	case SparkGeneric:
 		executorConfig = &SparkGenericConfig{}
+	case SparkKubernetes:
+		executorConfig = &SparkKubernetesConfig{}
 	default:
 		return fferr.NewProviderConfigError(
 			"Spark",
@@ -0,0 +1,74 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package provider_config
+
+import (
+	"encoding/json"
+
+	"github.com/featureform/fferr"
+
+	ss "github.com/featureform/helpers/stringset"
+)
+
+// SparkKubernetesConfig configures Spark jobs that are submitted as SparkApplication
+// resources to the Kubernetes Spark Operator.
+type SparkKubernetesConfig struct {
+	// Namespace the SparkApplications are created in. Defaults to Featureform's namespace.
+	Namespace string
+	// Image must contain Spark, Python and the connectors for the file store.
+	Image           string
+	ImagePullPolicy string
+	// ServiceAccount is used by the driver to create executor pods.
+	ServiceAccount    string
+	SparkVersion      string
+	PythonVersion     string
+	DriverCores       int32
+	DriverMemory      string
+	ExecutorCores     int32
+	ExecutorInstances int32
+	ExecutorMemory    string
+}
+
+func (sk *SparkKubernetesConfig) Deserialize(config SerializedConfig) error {
+	if err := json.Unmarshal(config, sk); err != nil {
+		return fferr.NewInternalError(err)
+	}
+	return nil
+}
+
+func (sk *SparkKubernetesConfig) Serialize() ([]byte, error) {
+	conf, err := json.Marshal(sk)
+	if err != nil {
+		return nil, fferr.NewInternalError(err)
+	}
+	return conf, nil
+}
+
+func (sk *SparkKubernetesConfig) IsExecutorConfig() bool {
+	return true
+}
+
+func (sk SparkKubernetesConfig) MutableFields() ss.StringSet {
+	// Namespace can't change since running jobs are resumed by looking them up in it.
+	return ss.StringSet{
+		"Image":             true,
+		"ImagePullPolicy":   true,
+		"ServiceAccount":    true,
+		"SparkVersion":      true,
+		"PythonVersion":     true,
+		"DriverCores":       true,
+		"DriverMemory":      true,
+		"ExecutorCores":     true,
+		"ExecutorInstances": true,
+		"ExecutorMemory":    true,
+	}
+}
+
+func (a SparkKubernetesConfig) DifferingFields(b SparkKubernetesConfig) (ss.StringSet, error) {
+	return differingFields(a, b)
+}
@@ -0,0 +1,96 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package provider_config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/featureform/filestore"
+	ss "github.com/featureform/helpers/stringset"
+)
+
+func TestSparkKubernetesConfigDifferingFields(t *testing.T) {
+	a := SparkKubernetesConfig{
+		Namespace:         "featureform",
+		Image:             "featureform/spark:3.5.1",
+		ServiceAccount:    "spark",
+		SparkVersion:      "3.5.1",
+		ExecutorInstances: 2,
+	}
+	b := a
+	b.Image = "featureform/spark:3.5.2"
+	b.ExecutorInstances = 4
+
+	actual, err := a.DifferingFields(b)
+	if err != nil {
+		t.Fatalf("Failed to get differing fields due to error: %v", err)
+	}
+	expected := ss.StringSet{
+		"Image":             true,
+		"ExecutorInstances": true,
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected %v, but instead found %v", expected, actual)
+	}
+	if !a.MutableFields().Contains(actual) {
+		t.Errorf("Expected %v to be mutable", actual)
+	}
+	if a.MutableFields()["Namespace"] {
+		t.Errorf("Expected Namespace to be immutable")
+	}
+}
+
+func TestSparkKubernetesConfig_Serde(t *testing.T) {
+	sparkConfig := SparkConfig{
+		ExecutorType: SparkKubernetes,
+		ExecutorConfig: &SparkKubernetesConfig{
+			Namespace:         "featureform",
+			Image:             "featureform/spark:3.5.1",
+			ImagePullPolicy:   "IfNotPresent",
+			ServiceAccount:    "spark",
+			SparkVersion:      "3.5.1",
+			PythonVersion:     "3",
+			DriverCores:       1,
+			DriverMemory:      "2g",
+			ExecutorCores:     2,
+			ExecutorInstances: 4,
+			ExecutorMemory:    "4g",
+		},
+		StoreType: filestore.S3,
+		StoreConfig: &S3FileStoreConfig{
+			Credentials:  AWSStaticCredentials{AccessKeyId: "aws-key", SecretKey: "aws-secret"},
+			BucketRegion: "us-east-1",
+			BucketPath:   "bucket",
+			Path:         "path",
+		},
+	}
+
+	serialized, err := sparkConfig.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize SparkConfig: %v", err)
+	}
+	deserialized := SparkConfig{}
+	if err := deserialized.Deserialize(serialized); err != nil {
+		t.Fatalf("Failed to deserialize SparkConfig: %v", err)
+	}
+	if !reflect.DeepEqual(sparkConfig, deserialized) {
+		t.Errorf("Expected %v, but found %v", sparkConfig, deserialized)
+	}
+
+	expectedMutable := ss.StringSet{}
+	for field := range (SparkKubernetesConfig{}).MutableFields() {
+		expectedMutable["Executor."+field] = true
+	}
+	for field := range (S3FileStoreConfig{}).MutableFields() {
+		expectedMutable["Store."+field] = true
+	}
+	if actual := deserialized.MutableFields(); !reflect.DeepEqual(expectedMutable, actual) {
+		t.Errorf("Expected %v, but found %v", expectedMutable, actual)
+	}
+}
@@ -0,0 +1,50 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package provider_config
+
+import (
+	"time"
+
+	"github.com/featureform/fferr"
+)
+
+// WriteBudget limits how hard materialization jobs write to an online store.
+// The budget is shared by every chunk runner in a process that targets the
+// same provider. Zero values mean "unlimited", so a config without a budget
+// behaves exactly as it did before budgets existed.
+type WriteBudget struct {
+	// RowsPerSecond is the maximum number of rows written per second.
+	RowsPerSecond float64
+	// MaxConcurrency is the maximum number of in-flight write requests. The
+	// actual limit adapts between 1 and MaxConcurrency based on backpressure.
+	MaxConcurrency int
+	// TargetLatencyMs is the write latency above which the store is treated
+	// as overloaded and the budget backs off.
+	TargetLatencyMs int
+}
+
+func (b WriteBudget) IsUnlimited() bool {
+	return b.RowsPerSecond == 0 && b.MaxConcurrency == 0 && b.TargetLatencyMs == 0
+}
+
+func (b WriteBudget) TargetLatency() time.Duration {
+	return time.Duration(b.TargetLatencyMs) * time.Millisecond
+}
+
+func (b WriteBudget) Validate() error {
+	if b.RowsPerSecond < 0 {
+		return fferr.NewInvalidArgumentErrorf("write budget rows per second cannot be negative: %f", b.RowsPerSecond)
+	}
+	if b.MaxConcurrency < 0 {
+		return fferr.NewInvalidArgumentErrorf("write budget max concurrency cannot be negative: %d", b.MaxConcurrency)
+	}
+	if b.TargetLatencyMs < 0 {
+		return fferr.NewInvalidArgumentErrorf("write budget target latency cannot be negative: %d", b.TargetLatencyMs)
+	}
+	return nil
+}
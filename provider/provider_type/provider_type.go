@@ -88,6 +88,18 @@ func GetFileTypes() []Type {
 	return []Type{S3, GCS, HDFS, AZURE}
 }
 
+// SupportsIncrementalTransformation reports whether stores of type t merge into, or overwrite
+// the partitions of, the table of a transformation. Other stores ignore
+// TransformationConfig.Incremental and rebuild the table, so they have to be given the full query.
+func SupportsIncrementalTransformation(t Type) bool {
+	switch t {
+	case PostgresOffline, RedshiftOffline, SnowflakeOffline, BigQueryOffline, ClickHouseOffline, SparkOffline:
+		return true
+	default:
+		return false
+	}
+}
+
 var (
 	converterRegistry = make(map[Type]types.ValueConverter[any])
 	registryLock      = sync.RWMutex{}
@@ -34,8 +34,9 @@ func (t redisTableKey) String() string {
 }
 
 type redisOnlineStore struct {
-	client rueidis.Client
-	prefix string
+	client      rueidis.Client
+	prefix      string
+	writeBudget pc.WriteBudget
 	BaseProvider
 }
 
@@ -76,7 +77,7 @@ func NewRedisOnlineStore(options *pc.RedisConfig) (*redisOnlineStore, error) {
 		wrapped.AddDetail("addr", options.Addr)
 		return nil, wrapped
 	}
-	return &redisOnlineStore{redisClient, options.Prefix, BaseProvider{
+	return &redisOnlineStore{redisClient, options.Prefix, options.WriteBudget, BaseProvider{
 		ProviderType:   pt.RedisOnline,
 		ProviderConfig: options.Serialized(),
 	},
@@ -87,6 +88,10 @@ func (store *redisOnlineStore) AsOnlineStore() (OnlineStore, error) {
 	return store, nil
 }
 
+func (store *redisOnlineStore) WriteBudget() pc.WriteBudget {
+	return store.writeBudget
+}
+
 func (store *redisOnlineStore) Close() error {
 	store.client.Close()
 	return nil
@@ -204,8 +209,12 @@ func (store *redisOnlineStore) DeleteTable(feature, variant string) error {
 }
 
 func (store *redisOnlineStore) CheckHealth() (bool, error) {
+	return store.CheckHealthContext(context.Background())
+}
+
+func (store *redisOnlineStore) CheckHealthContext(ctx context.Context) (bool, error) {
 	cmd := store.client.B().Ping().Build()
-	resp, err := store.client.Do(context.Background(), cmd).ToString()
+	resp, err := store.client.Do(ctx, cmd).ToString()
 	if err != nil {
 		wrapped := fferr.NewConnectionError(pt.RedisOnline.String(), err)
 		wrapped.AddDetail("action", "ping")
@@ -252,6 +252,7 @@ func TestGetTableBackwardsCompatibility(t *testing.T) {
 	redisOnlineStore := redisOnlineStore{
 		redisClient,
 		prefix,
+		redisConfig.WriteBudget,
 		BaseProvider{ProviderType: pt.RedisOnline, ProviderConfig: redisConfig.Serialized()},
 	}
 	if err != nil {
@@ -309,6 +310,7 @@ func TestCreateGetTable(t *testing.T) {
 	redisOnlineStore := redisOnlineStore{
 		redisClient,
 		prefix,
+		redisConfig.WriteBudget,
 		BaseProvider{ProviderType: pt.RedisOnline, ProviderConfig: redisConfig.Serialized()},
 	}
 	if err != nil {
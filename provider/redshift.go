@@ -183,7 +183,19 @@ func (q redshiftSQLQueries) trainingSetUpdate(store *sqlOfflineStore, def Traini
 	return q.trainingSetQuery(store, def, tableName, labelName, true)
 }
 
+func (q redshiftSQLQueries) spineLabelQuery(def TrainingSetDef) (string, error) {
+	return spineLabelQuery(def, "VARCHAR", "CAST('9999-12-31 23:59:59' AS TIMESTAMP)")
+}
+
 func (q redshiftSQLQueries) trainingSetQuery(store *sqlOfflineStore, def TrainingSetDef, tableName string, labelName string, isUpdate bool) error {
+	labelTable := sanitize(labelName)
+	if def.Spine != nil {
+		spineQuery, err := q.spineLabelQuery(def)
+		if err != nil {
+			return err
+		}
+		labelTable = fmt.Sprintf("(%s)", spineQuery)
+	}
 	columns := make([]string, 0)
 	selectColumns := make([]string, 0)
 	query := ""
@@ -204,13 +216,18 @@ func (q redshiftSQLQueries) trainingSetQuery(store *sqlOfflineStore, def Trainin
 	}
 	columnStr := strings.Join(columns, ", ")
 	selectColumnStr := strings.Join(selectColumns, ", ")
+	// Spine training sets only contain feature columns.
+	outputColumnStr := columnStr
+	if def.Spine == nil {
+		outputColumnStr = fmt.Sprintf("%s, label", columnStr)
+	}
 
 	if !isUpdate {
 		fullQuery := fmt.Sprintf(
-			"CREATE TABLE %s AS (SELECT %s, label FROM ("+
+			"CREATE TABLE %s AS (SELECT %s FROM ("+
 				"SELECT *, row_number() over(PARTITION BY e, label, time ORDER BY \"time\", %s DESC) AS rn FROM ( "+
 				"SELECT t0.entity AS e, t0.value AS label, t0.ts AS time, %s, %s FROM %s AS t0 %s )",
-			sanitize(tableName), columnStr, selectColumnStr, columnStr, selectColumnStr, sanitize(labelName), query)
+			sanitize(tableName), outputColumnStr, selectColumnStr, columnStr, selectColumnStr, labelTable, query)
 		if _, err := store.db.Exec(fullQuery); err != nil {
 			wrapped := fferr.NewResourceExecutionError(pt.RedshiftOffline.String(), def.ID.Name, def.ID.Variant, fferr.ResourceType(def.ID.Type.String()), err)
 			wrapped.AddDetail("table_name", tableName)
@@ -220,10 +237,10 @@ func (q redshiftSQLQueries) trainingSetQuery(store *sqlOfflineStore, def Trainin
 	} else {
 		tempTable := sanitize(fmt.Sprintf("tmp_%s", tableName))
 		fullQuery := fmt.Sprintf(
-			"CREATE TABLE %s AS (SELECT %s, label FROM ("+
+			"CREATE TABLE %s AS (SELECT %s FROM ("+
 				"SELECT *, row_number() over(PARTITION BY e, label, time ORDER BY \"time\", %s desc) AS rn FROM ( "+
 				"SELECT t0.entity AS e, t0.value AS label, t0.ts AS time, %s, %s FROM %s AS t0 %s )",
-			tempTable, columnStr, selectColumnStr, columnStr, selectColumnStr, sanitize(labelName), query)
+			tempTable, outputColumnStr, selectColumnStr, columnStr, selectColumnStr, labelTable, query)
 
 		if err := q.atomicUpdate(store.db, tableName, tempTable, fullQuery); err != nil {
 			return err
@@ -0,0 +1,161 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package retriever
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsv2config "github.com/aws/aws-sdk-go-v2/config"
+
+	"github.com/featureform/fferr"
+)
+
+const (
+	ValueProviderTypeAWSSecretsManager = "aws_secrets_manager"
+)
+
+// AWSSecretsManagerValueProvider reads the current version of secrets from AWS Secrets Manager
+// using the default AWS credential chain (env, shared config, IRSA, instance role). Keys are
+// secret names or ARNs.
+type AWSSecretsManagerValueProvider struct {
+	Region string `json:"region"`
+	// Endpoint overrides the regional endpoint, e.g. for VPC endpoints.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+func (a *AWSSecretsManagerValueProvider) GetValue(secretID string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretHTTPClient.Timeout)
+	defer cancel()
+	cfg, err := awsv2config.LoadDefaultConfig(ctx, awsv2config.WithRegion(a.Region))
+	if err != nil {
+		return "", fferr.NewInternalErrorf("failed to load AWS config: %w", err)
+	}
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fferr.NewInternalErrorf("failed to retrieve AWS credentials: %w", err)
+	}
+	payload, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", fferr.NewInternalError(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint(cfg.Region), bytes.NewReader(payload))
+	if err != nil {
+		return "", fferr.NewInternalError(err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	hash := sha256.Sum256(payload)
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "secretsmanager", cfg.Region, time.Now()); err != nil {
+		return "", fferr.NewInternalErrorf("failed to sign AWS request: %w", err)
+	}
+	resp, err := secretHTTPClient.Do(req)
+	if err != nil {
+		return "", fferr.NewInternalErrorf("failed to reach AWS Secrets Manager: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fferr.NewInternalError(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fferr.NewInternalErrorf("AWS Secrets Manager returned %d reading %s: %s", resp.StatusCode, secretID, strings.TrimSpace(string(body)))
+	}
+	var secret struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fferr.NewInternalErrorf("failed to parse AWS Secrets Manager response: %w", err)
+	}
+	if secret.SecretString == nil {
+		return "", fferr.NewInternalErrorf("AWS secret %s has no string value", secretID)
+	}
+	return *secret.SecretString, nil
+}
+
+func (a *AWSSecretsManagerValueProvider) endpoint(region string) string {
+	if a.Endpoint != "" {
+		return a.Endpoint
+	}
+	return fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", region)
+}
+
+func (a *AWSSecretsManagerValueProvider) Serialize() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (a *AWSSecretsManagerValueProvider) Deserialize(data []byte) error {
+	type alias AWSSecretsManagerValueProvider
+	return json.Unmarshal(data, (*alias)(a))
+}
+
+func (a *AWSSecretsManagerValueProvider) MarshalJSON() ([]byte, error) {
+	type alias AWSSecretsManagerValueProvider
+	return marshalWithType(ValueProviderTypeAWSSecretsManager, (*alias)(a))
+}
+
+// AWSSecretValue is an AWS Secrets Manager secret, or a field of one stored as JSON.
+type AWSSecretValue[T SupportedTypes] struct {
+	Region   string        `json:"region"`
+	Endpoint string        `json:"endpoint"`
+	SecretID string        `json:"secret_id"`
+	Field    string        `json:"field"`
+	Provider ValueProvider `json:"-"`
+}
+
+func (a *AWSSecretValue[T]) Get() (T, error) {
+	return getSecret[T](a.Provider, a.SecretID, a.Field)
+}
+
+func (a *AWSSecretValue[T]) Serialize() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (a *AWSSecretValue[T]) Deserialize(data []byte) error {
+	return json.Unmarshal(data, a)
+}
+
+func (a *AWSSecretValue[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"type":      ValueRetrieverTypeAWSSecretsManager,
+		"region":    a.Region,
+		"endpoint":  a.Endpoint,
+		"secret_id": a.SecretID,
+		"field":     a.Field,
+	})
+}
+
+func (a *AWSSecretValue[T]) UnmarshalJSON(data []byte) error {
+	type alias AWSSecretValue[T]
+	temp := struct {
+		Type string `json:"type"`
+		*alias
+	}{alias: (*alias)(a)}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return fferr.NewInternalErrorf("failed to unmarshal into temporary struct: %w", err)
+	}
+	if temp.Type != ValueRetrieverTypeAWSSecretsManager {
+		return fferr.NewInternalErrorf("expected type %s, got %s", ValueRetrieverTypeAWSSecretsManager, temp.Type)
+	}
+	if a.SecretID == "" {
+		return fferr.NewInternalErrorf("AWS secret value requires a secret_id")
+	}
+	provider := &AWSSecretsManagerValueProvider{Region: a.Region, Endpoint: a.Endpoint}
+	id := fmt.Sprintf("aws|%s|%s", a.Region, a.Endpoint)
+	a.Provider = sharedSecretCache(id, func() ValueProvider { return provider })
+	return nil
+}
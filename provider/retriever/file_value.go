@@ -0,0 +1,94 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package retriever
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/featureform/fferr"
+)
+
+const (
+	ValueProviderTypeFile = "file"
+)
+
+// FileValueProvider reads secrets mounted as files, such as Kubernetes secret volumes. Files are
+// read on every call, which is cheap and picks up the kubelet's atomic updates on rotation.
+type FileValueProvider struct{}
+
+func (f *FileValueProvider) GetValue(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fferr.NewInternalErrorf("failed to read secret file %s: %w", path, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func (f *FileValueProvider) Serialize() ([]byte, error) {
+	return json.Marshal(f)
+}
+
+func (f *FileValueProvider) Deserialize(data []byte) error {
+	return nil
+}
+
+func (f *FileValueProvider) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"type": ValueProviderTypeFile,
+	})
+}
+
+// FileValue is a value read from a file on the pod, optionally a field of a JSON document.
+type FileValue[T SupportedTypes] struct {
+	Path     string        `json:"path"`
+	Field    string        `json:"field,omitempty"`
+	Provider ValueProvider `json:"-"`
+}
+
+func (f *FileValue[T]) Get() (T, error) {
+	return getSecret[T](f.Provider, f.Path, f.Field)
+}
+
+func (f *FileValue[T]) Serialize() ([]byte, error) {
+	return json.Marshal(f)
+}
+
+func (f *FileValue[T]) Deserialize(data []byte) error {
+	return json.Unmarshal(data, f)
+}
+
+func (f *FileValue[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"type":  ValueRetrieverTypeFile,
+		"path":  f.Path,
+		"field": f.Field,
+	})
+}
+
+func (f *FileValue[T]) UnmarshalJSON(data []byte) error {
+	var temp struct {
+		Type  string `json:"type"`
+		Path  string `json:"path"`
+		Field string `json:"field"`
+	}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return fferr.NewInternalErrorf("failed to unmarshal into temporary struct: %w", err)
+	}
+	if temp.Type != ValueRetrieverTypeFile {
+		return fferr.NewInternalErrorf("expected type %s, got %s", ValueRetrieverTypeFile, temp.Type)
+	}
+	if temp.Path == "" {
+		return fferr.NewInternalErrorf("file value requires a path")
+	}
+	f.Path = temp.Path
+	f.Field = temp.Field
+	f.Provider = &FileValueProvider{}
+	return nil
+}
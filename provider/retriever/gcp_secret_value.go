@@ -0,0 +1,167 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package retriever
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/featureform/fferr"
+)
+
+const (
+	ValueProviderTypeGCPSecretManager = "gcp_secret_manager"
+
+	defaultGCPSecretManagerEndpoint = "https://secretmanager.googleapis.com"
+	gcpCloudPlatformScope           = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// GCPSecretManagerValueProvider reads secrets from GCP Secret Manager using application default
+// credentials (workload identity, GOOGLE_APPLICATION_CREDENTIALS). Keys are secret version
+// resource names, projects/<project>/secrets/<secret>/versions/<version>.
+type GCPSecretManagerValueProvider struct {
+	// Endpoint overrides the public API endpoint, e.g. for Private Service Connect.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	tokenSource oauth2.TokenSource
+}
+
+func (g *GCPSecretManagerValueProvider) GetValue(name string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretHTTPClient.Timeout)
+	defer cancel()
+	tokens := g.tokenSource
+	if tokens == nil {
+		var err error
+		if tokens, err = google.DefaultTokenSource(ctx, gcpCloudPlatformScope); err != nil {
+			return "", fferr.NewInternalErrorf("failed to load GCP credentials: %w", err)
+		}
+	}
+	token, err := tokens.Token()
+	if err != nil {
+		return "", fferr.NewInternalErrorf("failed to retrieve GCP token: %w", err)
+	}
+	endpoint := g.Endpoint
+	if endpoint == "" {
+		endpoint = defaultGCPSecretManagerEndpoint
+	}
+	url := fmt.Sprintf("%s/v1/%s:access", strings.TrimRight(endpoint, "/"), name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fferr.NewInternalError(err)
+	}
+	token.SetAuthHeader(req)
+	resp, err := secretHTTPClient.Do(req)
+	if err != nil {
+		return "", fferr.NewInternalErrorf("failed to reach GCP Secret Manager: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fferr.NewInternalError(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fferr.NewInternalErrorf("GCP Secret Manager returned %d reading %s: %s", resp.StatusCode, name, strings.TrimSpace(string(body)))
+	}
+	var secret struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fferr.NewInternalErrorf("failed to parse GCP Secret Manager response: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(secret.Payload.Data)
+	if err != nil {
+		return "", fferr.NewInternalErrorf("failed to decode GCP secret payload: %w", err)
+	}
+	return string(data), nil
+}
+
+func (g *GCPSecretManagerValueProvider) Serialize() ([]byte, error) {
+	return json.Marshal(g)
+}
+
+func (g *GCPSecretManagerValueProvider) Deserialize(data []byte) error {
+	type alias GCPSecretManagerValueProvider
+	return json.Unmarshal(data, (*alias)(g))
+}
+
+func (g *GCPSecretManagerValueProvider) MarshalJSON() ([]byte, error) {
+	type alias GCPSecretManagerValueProvider
+	return marshalWithType(ValueProviderTypeGCPSecretManager, (*alias)(g))
+}
+
+// GCPSecretValue is a GCP Secret Manager secret version, or a field of one stored as JSON.
+type GCPSecretValue[T SupportedTypes] struct {
+	Project string `json:"project"`
+	Secret  string `json:"secret"`
+	// Version defaults to "latest", so new versions are picked up once the cache expires.
+	Version  string        `json:"version"`
+	Field    string        `json:"field"`
+	Endpoint string        `json:"endpoint"`
+	Provider ValueProvider `json:"-"`
+}
+
+func (g *GCPSecretValue[T]) Get() (T, error) {
+	return getSecret[T](g.Provider, g.resourceName(), g.Field)
+}
+
+func (g *GCPSecretValue[T]) resourceName() string {
+	version := g.Version
+	if version == "" {
+		version = "latest"
+	}
+	return fmt.Sprintf("projects/%s/secrets/%s/versions/%s", g.Project, g.Secret, version)
+}
+
+func (g *GCPSecretValue[T]) Serialize() ([]byte, error) {
+	return json.Marshal(g)
+}
+
+func (g *GCPSecretValue[T]) Deserialize(data []byte) error {
+	return json.Unmarshal(data, g)
+}
+
+func (g *GCPSecretValue[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"type":     ValueRetrieverTypeGCPSecretManager,
+		"project":  g.Project,
+		"secret":   g.Secret,
+		"version":  g.Version,
+		"field":    g.Field,
+		"endpoint": g.Endpoint,
+	})
+}
+
+func (g *GCPSecretValue[T]) UnmarshalJSON(data []byte) error {
+	type alias GCPSecretValue[T]
+	temp := struct {
+		Type string `json:"type"`
+		*alias
+	}{alias: (*alias)(g)}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return fferr.NewInternalErrorf("failed to unmarshal into temporary struct: %w", err)
+	}
+	if temp.Type != ValueRetrieverTypeGCPSecretManager {
+		return fferr.NewInternalErrorf("expected type %s, got %s", ValueRetrieverTypeGCPSecretManager, temp.Type)
+	}
+	if g.Project == "" || g.Secret == "" {
+		return fferr.NewInternalErrorf("GCP secret value requires a project and secret")
+	}
+	provider := &GCPSecretManagerValueProvider{Endpoint: g.Endpoint}
+	g.Provider = sharedSecretCache("gcp|"+g.Endpoint, func() ValueProvider { return provider })
+	return nil
+}
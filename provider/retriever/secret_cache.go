@@ -0,0 +1,178 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package retriever
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/featureform/fferr"
+)
+
+const (
+	// SecretCacheTTLEnv overrides how long values fetched from a remote secret backend are cached.
+	SecretCacheTTLEnv = "FEATUREFORM_SECRET_CACHE_TTL"
+
+	defaultSecretCacheTTL = 5 * time.Minute
+	// A value that can't be refreshed is served for at most this long past its TTL.
+	defaultSecretMaxStale = time.Hour
+)
+
+// leasedValueProvider is implemented by backends that know how long a value stays valid,
+// such as Vault secrets with a lease. A zero duration falls back to the cache TTL.
+type leasedValueProvider interface {
+	GetValueWithLease(key string) (string, time.Duration, error)
+}
+
+type cachedSecret struct {
+	value     string
+	expiresAt time.Time
+}
+
+// CachedValueProvider caches the values of a remote ValueProvider. A value is refetched once its
+// TTL passes so rotated secrets are picked up without a restart. If the refetch fails the last
+// known value is served until MaxStale passes, so a secret backend outage doesn't immediately
+// break every provider connection.
+type CachedValueProvider struct {
+	Provider ValueProvider
+	TTL      time.Duration
+	MaxStale time.Duration
+
+	now     func() time.Time
+	mtx     sync.Mutex
+	entries map[string]cachedSecret
+	// fetches makes concurrent misses for the same key share one fetch.
+	fetches singleflight.Group
+}
+
+func NewCachedValueProvider(provider ValueProvider, ttl time.Duration) *CachedValueProvider {
+	return &CachedValueProvider{
+		Provider: provider,
+		TTL:      ttl,
+		MaxStale: defaultSecretMaxStale,
+		now:      time.Now,
+		entries:  make(map[string]cachedSecret),
+	}
+}
+
+func (c *CachedValueProvider) GetValue(key string) (string, error) {
+	c.mtx.Lock()
+	now := c.now()
+	cached, has := c.entries[key]
+	c.mtx.Unlock()
+	if has && now.Before(cached.expiresAt) {
+		return cached.value, nil
+	}
+	// The cache isn't locked during the fetch so a slow backend doesn't block other keys.
+	value, err, _ := c.fetches.Do(key, func() (interface{}, error) {
+		value, lease, err := c.fetch(key)
+		if err != nil {
+			return "", err
+		}
+		ttl := c.TTL
+		if lease > 0 && lease < ttl {
+			ttl = lease
+		}
+		c.mtx.Lock()
+		c.entries[key] = cachedSecret{value: value, expiresAt: now.Add(ttl)}
+		c.mtx.Unlock()
+		return value, nil
+	})
+	if err != nil {
+		if has && now.Before(cached.expiresAt.Add(c.MaxStale)) {
+			return cached.value, nil
+		}
+		return "", err
+	}
+	return value.(string), nil
+}
+
+func (c *CachedValueProvider) fetch(key string) (string, time.Duration, error) {
+	if leased, ok := c.Provider.(leasedValueProvider); ok {
+		return leased.GetValueWithLease(key)
+	}
+	value, err := c.Provider.GetValue(key)
+	return value, 0, err
+}
+
+func (c *CachedValueProvider) Serialize() ([]byte, error) {
+	return c.Provider.Serialize()
+}
+
+func (c *CachedValueProvider) Deserialize(data []byte) error {
+	return c.Provider.Deserialize(data)
+}
+
+func (c *CachedValueProvider) MarshalJSON() ([]byte, error) {
+	return c.Provider.Serialize()
+}
+
+func secretCacheTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv(SecretCacheTTLEnv)); err == nil && ttl >= 0 {
+		return ttl
+	}
+	return defaultSecretCacheTTL
+}
+
+// secretCaches shares one cache per backend across every deserialized config, since provider
+// configs are deserialized on every request.
+var secretCaches = struct {
+	sync.Mutex
+	caches map[string]*CachedValueProvider
+}{caches: make(map[string]*CachedValueProvider)}
+
+func sharedSecretCache(id string, create func() ValueProvider) *CachedValueProvider {
+	secretCaches.Lock()
+	defer secretCaches.Unlock()
+	if cache, has := secretCaches.caches[id]; has {
+		return cache
+	}
+	cache := NewCachedValueProvider(create(), secretCacheTTL())
+	secretCaches.caches[id] = cache
+	return cache
+}
+
+// extractSecretField returns a field of a secret stored as a JSON object, or the secret itself if
+// field is empty. AWS and GCP secrets commonly hold several credentials in one JSON document.
+func extractSecretField(secret, field string) (string, error) {
+	if field == "" {
+		return secret, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fferr.NewInternalErrorf("secret is not a JSON object, cannot read field %s", field)
+	}
+	value, has := fields[field]
+	if !has {
+		return "", fferr.NewInternalErrorf("secret has no field %s", field)
+	}
+	if str, ok := value.(string); ok {
+		return str, nil
+	}
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		return "", fferr.NewInternalError(err)
+	}
+	return string(serialized), nil
+}
+
+func getSecret[T SupportedTypes](provider ValueProvider, key, field string) (T, error) {
+	secret, err := provider.GetValue(key)
+	if err != nil {
+		return *new(T), err
+	}
+	value, err := extractSecretField(secret, field)
+	if err != nil {
+		return *new(T), err
+	}
+	return convertStringToType[T](value)
+}
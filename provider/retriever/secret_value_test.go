@@ -0,0 +1,285 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package retriever
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestFileValue(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	jsonFile := filepath.Join(dir, "creds.json")
+	assert.NoError(t, os.WriteFile(passwordFile, []byte("hunter2\n"), 0600))
+	assert.NoError(t, os.WriteFile(jsonFile, []byte(`{"user": "admin", "port": 5432}`), 0600))
+
+	password, err := DeserializeValue[string]([]byte(fmt.Sprintf(`{"type": "file", "path": %q}`, passwordFile)))
+	assert.NoError(t, err)
+	value, err := password.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+
+	// Rotated files are picked up on the next Get.
+	assert.NoError(t, os.WriteFile(passwordFile, []byte("hunter3"), 0600))
+	value, err = password.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter3", value)
+
+	port, err := DeserializeValue[int]([]byte(fmt.Sprintf(`{"type": "file", "path": %q, "field": "port"}`, jsonFile)))
+	assert.NoError(t, err)
+	portValue, err := port.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, 5432, portValue)
+
+	serialized, err := password.Serialize()
+	assert.NoError(t, err)
+	deserialized, err := DeserializeValue[string](serialized)
+	assert.NoError(t, err)
+	assert.Equal(t, password, deserialized)
+
+	missing := &FileValue[string]{Path: filepath.Join(dir, "missing"), Provider: &FileValueProvider{}}
+	_, err = missing.Get()
+	assert.Error(t, err)
+}
+
+func TestVaultValue(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/data/featureform/postgres":
+			fmt.Fprint(w, `{"lease_duration": 0, "data": {"data": {"password": "hunter2", "port": "5432"}, "metadata": {"version": 3}}}`)
+		case "/v1/legacy/featureform/postgres":
+			fmt.Fprint(w, `{"lease_duration": 60, "data": {"password": "legacy"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	t.Setenv(VaultTokenEnv, "root")
+
+	config := fmt.Sprintf(`{"type": "vault", "address": %q, "mount": "kv", "path": "featureform/postgres", "field": "password"}`, server.URL)
+	password, err := DeserializeValue[string]([]byte(config))
+	assert.NoError(t, err)
+	value, err := password.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+
+	// A second config for the same secret shares the cache.
+	portConfig := strings.Replace(config, `"password"`, `"port"`, 1)
+	port, err := DeserializeValue[int]([]byte(portConfig))
+	assert.NoError(t, err)
+	portValue, err := port.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, 5432, portValue)
+	assert.Equal(t, int32(1), requests.Load())
+
+	legacy := &VaultValue[string]{Path: "featureform/postgres", Field: "password", Provider: &VaultValueProvider{Address: server.URL, Mount: "legacy", KVVersion: 1}}
+	value, err = legacy.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "legacy", value)
+
+	t.Setenv(VaultTokenEnv, "wrong")
+	unauthorized := &VaultValue[string]{Path: "featureform/postgres", Field: "password", Provider: &VaultValueProvider{Address: server.URL, Mount: "kv"}}
+	_, err = unauthorized.Get()
+	assert.Error(t, err)
+}
+
+func TestAWSSecretValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req struct {
+			SecretId string
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &req); err != nil || req.SecretId != "prod/snowflake" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"__type": "ResourceNotFoundException"}`)
+			return
+		}
+		fmt.Fprint(w, `{"Name": "prod/snowflake", "SecretString": "{\"password\": \"hunter2\"}"}`)
+	}))
+	defer server.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	config := fmt.Sprintf(`{"type": "aws_secrets_manager", "region": "us-east-1", "endpoint": %q, "secret_id": "prod/snowflake", "field": "password"}`, server.URL)
+	password, err := DeserializeValue[string]([]byte(config))
+	assert.NoError(t, err)
+	value, err := password.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", value)
+
+	missing := &AWSSecretValue[string]{SecretID: "missing", Provider: &AWSSecretsManagerValueProvider{Region: "us-east-1", Endpoint: server.URL}}
+	_, err = missing.Get()
+	assert.Error(t, err)
+}
+
+func TestGCPSecretValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gcp-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v1/projects/ff/secrets/firestore/versions/latest:access" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data := base64.StdEncoding.EncodeToString([]byte(`{"type": "service_account"}`))
+		fmt.Fprintf(w, `{"name": "projects/ff/secrets/firestore/versions/2", "payload": {"data": %q}}`, data)
+	}))
+	defer server.Close()
+
+	provider := &GCPSecretManagerValueProvider{
+		Endpoint:    server.URL,
+		tokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "gcp-token"}),
+	}
+	credentials := &GCPSecretValue[string]{Project: "ff", Secret: "firestore", Provider: provider}
+	value, err := credentials.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type": "service_account"}`, value)
+
+	missing := &GCPSecretValue[string]{Project: "ff", Secret: "missing", Provider: provider}
+	_, err = missing.Get()
+	assert.Error(t, err)
+
+	serialized, err := credentials.Serialize()
+	assert.NoError(t, err)
+	deserialized, err := DeserializeValue[string](serialized)
+	assert.NoError(t, err)
+	assert.Equal(t, credentials.resourceName(), deserialized.(*GCPSecretValue[string]).resourceName())
+}
+
+type countingValueProvider struct {
+	EnvironmentValueProvider
+	values map[string]string
+	lease  time.Duration
+	calls  int
+	err    error
+}
+
+func (c *countingValueProvider) GetValueWithLease(key string) (string, time.Duration, error) {
+	c.calls++
+	if c.err != nil {
+		return "", 0, c.err
+	}
+	return c.values[key], c.lease, nil
+}
+
+func TestCachedValueProvider(t *testing.T) {
+	backend := &countingValueProvider{values: map[string]string{"key": "v1"}}
+	cache := NewCachedValueProvider(backend, time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	value, err := cache.GetValue("key")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	// Served from the cache until the TTL passes, then the rotated value is fetched.
+	backend.values["key"] = "v2"
+	value, _ = cache.GetValue("key")
+	assert.Equal(t, "v1", value)
+	now = now.Add(2 * time.Minute)
+	value, _ = cache.GetValue("key")
+	assert.Equal(t, "v2", value)
+	assert.Equal(t, 2, backend.calls)
+
+	// A failed refresh serves the stale value until MaxStale passes.
+	backend.err = fmt.Errorf("backend down")
+	now = now.Add(2 * time.Minute)
+	value, err = cache.GetValue("key")
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", value)
+	now = now.Add(defaultSecretMaxStale)
+	_, err = cache.GetValue("key")
+	assert.Error(t, err)
+
+	// Leases shorter than the TTL win. The value already expired so this fetches it again.
+	backend.err = nil
+	backend.lease = 10 * time.Second
+	_, _ = cache.GetValue("key")
+	calls := backend.calls
+	now = now.Add(20 * time.Second)
+	_, _ = cache.GetValue("key")
+	assert.Equal(t, calls+1, backend.calls)
+}
+
+type blockingValueProvider struct {
+	EnvironmentValueProvider
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (b *blockingValueProvider) GetValueWithLease(key string) (string, time.Duration, error) {
+	b.calls.Add(1)
+	if key == "slow" {
+		<-b.release
+	}
+	return key, 0, nil
+}
+
+func TestCachedValueProviderConcurrentFetches(t *testing.T) {
+	backend := &blockingValueProvider{release: make(chan struct{})}
+	cache := NewCachedValueProvider(backend, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.GetValue("slow")
+			assert.NoError(t, err)
+			assert.Equal(t, "slow", value)
+		}()
+	}
+	assert.Eventually(t, func() bool { return backend.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	// Other keys are fetched while the slow one is in flight.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		value, err := cache.GetValue("fast")
+		assert.NoError(t, err)
+		assert.Equal(t, "fast", value)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Fetch of another key blocked on the slow fetch")
+	}
+
+	close(backend.release)
+	wg.Wait()
+	// Both callers of the slow key shared one fetch, only the fast key was fetched besides it.
+	assert.Equal(t, int32(2), backend.calls.Load())
+}
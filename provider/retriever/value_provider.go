@@ -59,7 +59,32 @@ func DeserializeValueProvider(data []byte) (ValueProvider, error) {
 	switch typeHolder.Type {
 	case ValueProviderTypeEnvironment:
 		return &EnvironmentValueProvider{}, nil
+	case ValueProviderTypeFile:
+		return &FileValueProvider{}, nil
+	case ValueProviderTypeVault:
+		provider := &VaultValueProvider{}
+		return provider, provider.Deserialize(data)
+	case ValueProviderTypeAWSSecretsManager:
+		provider := &AWSSecretsManagerValueProvider{}
+		return provider, provider.Deserialize(data)
+	case ValueProviderTypeGCPSecretManager:
+		provider := &GCPSecretManagerValueProvider{}
+		return provider, provider.Deserialize(data)
 	default:
 		return nil, fmt.Errorf("unknown type %s", typeHolder.Type)
 	}
 }
+
+// marshalWithType serializes a provider's configuration alongside its type discriminator.
+func marshalWithType(providerType string, config interface{}) ([]byte, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	fields["type"] = providerType
+	return json.Marshal(fields)
+}
@@ -24,6 +24,26 @@ func TestDeserializeValueProvider(t *testing.T) {
 			valueProvider: &EnvironmentValueProvider{},
 			wantErr:       false,
 		},
+		{
+			name:          "FileValueProvider",
+			valueProvider: &FileValueProvider{},
+			wantErr:       false,
+		},
+		{
+			name:          "VaultValueProvider",
+			valueProvider: &VaultValueProvider{Address: "http://vault:8200", Mount: "kv", KVVersion: 1},
+			wantErr:       false,
+		},
+		{
+			name:          "AWSSecretsManagerValueProvider",
+			valueProvider: &AWSSecretsManagerValueProvider{Region: "us-east-1"},
+			wantErr:       false,
+		},
+		{
+			name:          "GCPSecretManagerValueProvider",
+			valueProvider: &GCPSecretManagerValueProvider{Endpoint: "https://secretmanager.example.com"},
+			wantErr:       false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
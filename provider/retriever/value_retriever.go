@@ -14,8 +14,12 @@ import (
 )
 
 const (
-	ValueRetrieverTypeStatic      = "static"
-	ValueRetrieverTypeEnvironment = "environment"
+	ValueRetrieverTypeStatic            = "static"
+	ValueRetrieverTypeEnvironment       = "environment"
+	ValueRetrieverTypeFile              = "file"
+	ValueRetrieverTypeVault             = "vault"
+	ValueRetrieverTypeAWSSecretsManager = "aws_secrets_manager"
+	ValueRetrieverTypeGCPSecretManager  = "gcp_secret_manager"
 )
 
 type Value[T SupportedTypes] interface {
@@ -133,6 +137,30 @@ func DeserializeValue[T SupportedTypes](data []byte) (Value[T], error) {
 			return nil, err
 		}
 		return &retriever, nil
+	case ValueRetrieverTypeFile:
+		var retriever FileValue[T]
+		if err := retriever.Deserialize(data); err != nil {
+			return nil, err
+		}
+		return &retriever, nil
+	case ValueRetrieverTypeVault:
+		var retriever VaultValue[T]
+		if err := retriever.Deserialize(data); err != nil {
+			return nil, err
+		}
+		return &retriever, nil
+	case ValueRetrieverTypeAWSSecretsManager:
+		var retriever AWSSecretValue[T]
+		if err := retriever.Deserialize(data); err != nil {
+			return nil, err
+		}
+		return &retriever, nil
+	case ValueRetrieverTypeGCPSecretManager:
+		var retriever GCPSecretValue[T]
+		if err := retriever.Deserialize(data); err != nil {
+			return nil, err
+		}
+		return &retriever, nil
 	default:
 		return nil, fferr.NewInternalErrorf("unknown type: %s", typeHolder.Type)
 	}
@@ -0,0 +1,211 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package retriever
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/featureform/fferr"
+)
+
+const (
+	ValueProviderTypeVault = "vault"
+
+	// VaultAddressEnv and VaultTokenEnv are the standard Vault CLI variables. VaultTokenFileEnv
+	// points at a token written by a Vault agent sink and is re-read on every fetch, so renewed
+	// tokens are picked up.
+	VaultAddressEnv   = "VAULT_ADDR"
+	VaultTokenEnv     = "VAULT_TOKEN"
+	VaultTokenFileEnv = "FEATUREFORM_VAULT_TOKEN_FILE"
+
+	defaultVaultMount = "secret"
+)
+
+var secretHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// VaultValueProvider reads secrets from a Vault KV secrets engine. Keys are secret paths relative
+// to the mount and values are the secret's data as a JSON object.
+type VaultValueProvider struct {
+	Address   string `json:"address"`
+	Mount     string `json:"mount"`
+	Namespace string `json:"namespace,omitempty"`
+	// KVVersion is 2 unless set to 1.
+	KVVersion int `json:"kv_version,omitempty"`
+}
+
+func (v *VaultValueProvider) GetValue(path string) (string, error) {
+	value, _, err := v.GetValueWithLease(path)
+	return value, err
+}
+
+func (v *VaultValueProvider) GetValueWithLease(path string) (string, time.Duration, error) {
+	address := v.Address
+	if address == "" {
+		address = os.Getenv(VaultAddressEnv)
+	}
+	if address == "" {
+		return "", 0, fferr.NewInternalErrorf("vault address not set, set address or %s", VaultAddressEnv)
+	}
+	token, err := VaultToken()
+	if err != nil {
+		return "", 0, err
+	}
+	url := fmt.Sprintf("%s/v1/%s", strings.TrimRight(address, "/"), v.secretPath(path))
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", 0, fferr.NewInternalError(err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+	resp, err := secretHTTPClient.Do(req)
+	if err != nil {
+		return "", 0, fferr.NewInternalErrorf("failed to reach vault: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, fferr.NewInternalError(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fferr.NewInternalErrorf("vault returned %d reading %s: %s", resp.StatusCode, path, strings.TrimSpace(string(body)))
+	}
+	var secret struct {
+		LeaseDuration int             `json:"lease_duration"`
+		Data          json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", 0, fferr.NewInternalErrorf("failed to parse vault response: %w", err)
+	}
+	data := secret.Data
+	if v.kvVersion() == 2 {
+		var versioned struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(secret.Data, &versioned); err != nil {
+			return "", 0, fferr.NewInternalErrorf("failed to parse vault response: %w", err)
+		}
+		data = versioned.Data
+	}
+	if len(data) == 0 || string(data) == "null" {
+		return "", 0, fferr.NewInternalErrorf("vault secret %s has no data", path)
+	}
+	return string(data), time.Duration(secret.LeaseDuration) * time.Second, nil
+}
+
+func (v *VaultValueProvider) kvVersion() int {
+	if v.KVVersion == 1 {
+		return 1
+	}
+	return 2
+}
+
+func (v *VaultValueProvider) secretPath(path string) string {
+	mount := strings.Trim(v.Mount, "/")
+	if mount == "" {
+		mount = defaultVaultMount
+	}
+	path = strings.TrimLeft(path, "/")
+	if v.kvVersion() == 2 {
+		return fmt.Sprintf("%s/data/%s", mount, path)
+	}
+	return fmt.Sprintf("%s/%s", mount, path)
+}
+
+// VaultToken returns the token used to authenticate with Vault, read from the file named by
+// FEATUREFORM_VAULT_TOKEN_FILE if it's set so that rotated tokens are picked up, or VAULT_TOKEN.
+func VaultToken() (string, error) {
+	if file := os.Getenv(VaultTokenFileEnv); file != "" {
+		token, err := os.ReadFile(file)
+		if err != nil {
+			return "", fferr.NewInternalErrorf("failed to read vault token file: %w", err)
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+	if token := os.Getenv(VaultTokenEnv); token != "" {
+		return token, nil
+	}
+	return "", fferr.NewInternalErrorf("vault token not set, set %s or %s", VaultTokenEnv, VaultTokenFileEnv)
+}
+
+func (v *VaultValueProvider) Serialize() ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (v *VaultValueProvider) Deserialize(data []byte) error {
+	type alias VaultValueProvider
+	return json.Unmarshal(data, (*alias)(v))
+}
+
+func (v *VaultValueProvider) MarshalJSON() ([]byte, error) {
+	type alias VaultValueProvider
+	return marshalWithType(ValueProviderTypeVault, (*alias)(v))
+}
+
+// VaultValue is a field of a Vault KV secret.
+type VaultValue[T SupportedTypes] struct {
+	Address   string        `json:"address"`
+	Mount     string        `json:"mount"`
+	Namespace string        `json:"namespace"`
+	KVVersion int           `json:"kv_version"`
+	Path      string        `json:"path"`
+	Field     string        `json:"field"`
+	Provider  ValueProvider `json:"-"`
+}
+
+func (v *VaultValue[T]) Get() (T, error) {
+	return getSecret[T](v.Provider, v.Path, v.Field)
+}
+
+func (v *VaultValue[T]) Serialize() ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (v *VaultValue[T]) Deserialize(data []byte) error {
+	return json.Unmarshal(data, v)
+}
+
+func (v *VaultValue[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"type":       ValueRetrieverTypeVault,
+		"address":    v.Address,
+		"mount":      v.Mount,
+		"namespace":  v.Namespace,
+		"kv_version": v.KVVersion,
+		"path":       v.Path,
+		"field":      v.Field,
+	})
+}
+
+func (v *VaultValue[T]) UnmarshalJSON(data []byte) error {
+	type alias VaultValue[T]
+	temp := struct {
+		Type string `json:"type"`
+		*alias
+	}{alias: (*alias)(v)}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return fferr.NewInternalErrorf("failed to unmarshal into temporary struct: %w", err)
+	}
+	if temp.Type != ValueRetrieverTypeVault {
+		return fferr.NewInternalErrorf("expected type %s, got %s", ValueRetrieverTypeVault, temp.Type)
+	}
+	if v.Path == "" || v.Field == "" {
+		return fferr.NewInternalErrorf("vault value requires a path and field")
+	}
+	provider := &VaultValueProvider{Address: v.Address, Mount: v.Mount, Namespace: v.Namespace, KVVersion: v.KVVersion}
+	id := fmt.Sprintf("vault|%s|%s|%s|%d", v.Address, v.Mount, v.Namespace, v.KVVersion)
+	v.Provider = sharedSecretCache(id, func() ValueProvider { return provider })
+	return nil
+}
@@ -18,6 +18,7 @@ import boto3
 import pandas as pd
 from pandasql import sqldf
 from azure.storage.blob import BlobServiceClient
+from deltalake import DeltaTable, write_deltalake
 
 LOCAL_MODE = "local"
 K8S_MODE = "k8s"
@@ -28,6 +29,9 @@ AZURE = "azure"
 GCS = "gcs"
 S3 = "s3"
 
+# Output Table Formats
+DELTA = "delta"
+
 real_path = os.path.realpath(__file__)
 dir_path = os.path.dirname(real_path)
 
@@ -46,6 +50,9 @@ class BlobStore:
     def get_client(self):
         return self._client
 
+    def get_credentials(self):
+        return self._credentials
+
     def upload(self, file_path, blob_path):
         if os.path.isfile(file_path):
             response = self.upload_file(file_path, blob_path)
@@ -235,6 +242,7 @@ def main(args):
             args.transformation,
             args.sources,
             blob_store,
+            args.output_table_format,
         )
     elif args.transformation_type == "df":
         print(f"starting execution for DF Transformation in {args.mode} mode")
@@ -244,26 +252,33 @@ def main(args):
             args.transformation,
             args.sources,
             blob_store,
+            args.output_table_format,
         )
     return output_location
 
 
-def execute_sql_job(mode, output_uri, transformation, source_list, blob_store):
+def execute_sql_job(
+    mode, output_uri, transformation, source_list, blob_store, output_table_format=""
+):
     """
     Executes the SQL Queries:
 
     Parameters:
-        mode:           string ("local", "k8s")
-        output_uri:     string (path to blob store)
-        transformation: string (eg. "SELECT * FROM source_0)
-        source_list:    List(string) (a list of input sources)
-        blob_store:     BlobStore (blob store object)
+        mode:                string ("local", "k8s")
+        output_uri:          string (path to blob store)
+        transformation:      string (eg. "SELECT * FROM source_0)
+        source_list:         List(string) (a list of input sources)
+        blob_store:          BlobStore (blob store object)
+        output_table_format: string ("" for a Parquet file, or "delta")
 
     Returns:
         output_uri_with_timestamp: string (output path of blob storage)
     """
     try:
         for i, source in enumerate(source_list):
+            if is_delta_table(source, blob_store):
+                globals()[f"source_{i}"] = read_delta_table(source, blob_store)
+                continue
             if blob_store.type == LOCAL:
                 output_path = source
             else:
@@ -282,6 +297,9 @@ def execute_sql_job(mode, output_uri, transformation, source_list, blob_store):
         transformation_df = pysqldf(transformation)
         output_dataframe = set_bool_columns(transformation_df)
 
+        if output_table_format == DELTA:
+            return write_delta_table(output_dataframe, output_uri, blob_store)
+
         dt = datetime.now()
         output_uri_with_timestamp = f"{output_uri}/{dt}.parquet"
 
@@ -300,16 +318,17 @@ def execute_sql_job(mode, output_uri, transformation, source_list, blob_store):
         raise e
 
 
-def execute_df_job(mode, output_uri, code, sources, blob_store):
+def execute_df_job(mode, output_uri, code, sources, blob_store, output_table_format=""):
     """
     Executes the DF transformation:
 
     Parameters:
-        mode:             string ("local", "k8s")
-        output_uri:       string (blob store path)
-        code:             code (python code)
-        sources:          List(string) (a list of input sources)
-        blob_store:       BlobStore (blob store object)
+        mode:                string ("local", "k8s")
+        output_uri:          string (blob store path)
+        code:                code (python code)
+        sources:             List(string) (a list of input sources)
+        blob_store:          BlobStore (blob store object)
+        output_table_format: string ("" for a Parquet file, or "delta")
 
     Returns:
         output_uri_with_timestamp: string (output s3 path)
@@ -318,6 +337,10 @@ def execute_df_job(mode, output_uri, code, sources, blob_store):
     func_parameters = []
     print(f"reading '{len(sources)}' source files")
     for i, source in enumerate(sources):
+        if is_delta_table(source, blob_store):
+            print(f"reading '{source}' delta table into dataframe")
+            func_parameters.append(read_delta_table(source, blob_store))
+            continue
         if blob_store.type == LOCAL:
             source_path = source
         else:
@@ -355,6 +378,9 @@ def execute_df_job(mode, output_uri, code, sources, blob_store):
                 f"the transformation function returned a {type(output_df)} instead of a pandas dataframe."
             )
 
+        if output_table_format == DELTA:
+            return write_delta_table(output_df, output_uri, blob_store)
+
         dt = datetime.now()
         output_uri_with_timestamp = f"{output_uri}/{dt}.parquet"
 
@@ -375,6 +401,99 @@ def execute_df_job(mode, output_uri, code, sources, blob_store):
         raise e
 
 
+def delta_table_uri(path, blob_store):
+    """
+    Returns the URI deltalake uses for a table path. Paths are blob keys except in local mode.
+    """
+    if "://" in path:
+        return path.replace("s3a://", "s3://", 1)
+    credentials = blob_store.get_credentials()
+    if blob_store.type == S3:
+        return f"s3://{credentials.bucket_name}/{path}"
+    elif blob_store.type == AZURE:
+        return f"az://{credentials.container}/{path}"
+    return path
+
+
+def delta_storage_options(blob_store):
+    """
+    Returns the deltalake storage options holding the blob store's credentials.
+    """
+    credentials = blob_store.get_credentials()
+    if blob_store.type == S3:
+        return {
+            "AWS_ACCESS_KEY_ID": credentials.aws_access_key_id,
+            "AWS_SECRET_ACCESS_KEY": credentials.aws_secret_key,
+            "AWS_REGION": credentials.bucket_region,
+            # Each resource has a single writer, so we don't need a locking provider.
+            "AWS_S3_ALLOW_UNSAFE_RENAME": "true",
+        }
+    elif blob_store.type == AZURE:
+        parts = dict(
+            part.split("=", 1)
+            for part in credentials.connection_string.split(";")
+            if "=" in part
+        )
+        return {
+            "account_name": parts.get("AccountName", ""),
+            "account_key": parts.get("AccountKey", ""),
+        }
+    return {}
+
+
+def split_delta_version(source):
+    """
+    Splits a pinned Delta table source, written like Delta's own "<path>@v<version>", into its
+    path and version. The version is None for unpinned sources.
+    """
+    path, sep, version = source.rpartition("@v")
+    if sep and version.isdigit():
+        return path, int(version)
+    return source, None
+
+
+def is_delta_table(source, blob_store):
+    """
+    Sources that are Delta tables are passed as the table's directory, optionally followed by
+    @v<version> to read a pinned version.
+    """
+    if source.endswith(".csv") or source.endswith(".parquet"):
+        return False
+    path, _ = split_delta_version(source)
+    return DeltaTable.is_deltatable(
+        delta_table_uri(path, blob_store), delta_storage_options(blob_store)
+    )
+
+
+def read_delta_table(source, blob_store):
+    path, version = split_delta_version(source)
+    return DeltaTable(
+        delta_table_uri(path, blob_store),
+        version=version,
+        storage_options=delta_storage_options(blob_store),
+    ).to_pandas()
+
+
+def write_delta_table(df, output_uri, blob_store):
+    """
+    Overwrites the Delta table at output_uri with df in a single commit, keeping earlier
+    versions for time travel.
+
+    Returns:
+        output_uri: string (the table's location)
+    """
+    table_uri = delta_table_uri(output_uri, blob_store)
+    print(f"writing output dataframe to delta table {table_uri}")
+    write_deltalake(
+        table_uri,
+        df,
+        mode="overwrite",
+        schema_mode="overwrite",
+        storage_options=delta_storage_options(blob_store),
+    )
+    return output_uri
+
+
 def get_code_from_file(mode, file_path):
     """
     Reads the code from a pkl file into a python code object.
@@ -452,6 +571,7 @@ def get_args():
     sources = os.getenv("SOURCES", "").split(",")
     transformation_type = os.getenv("TRANSFORMATION_TYPE")
     transformation = os.getenv("TRANSFORMATION")
+    output_table_format = os.getenv("OUTPUT_TABLE_FORMAT", "")
 
     blob_credentials = get_blob_credentials(mode, blob_store_type)
 
@@ -460,6 +580,7 @@ def get_args():
         transformation_type=transformation_type,
         transformation=transformation,
         output_uri=output_uri,
+        output_table_format=output_table_format,
         sources=sources,
         blob_credentials=blob_credentials,
     )
@@ -495,6 +616,11 @@ def validate_args(args):
             f"the {args.transformation_type} transformation type is not supported. supported types are 'sql', and 'df'."
         )
 
+    if args.output_table_format not in ("", DELTA):
+        raise ValueError(
+            f"the {args.output_table_format} output table format is not supported. supported formats are '{DELTA}'."
+        )
+
     if not (args.output_uri and args.sources != [""] and args.transformation != ""):
         raise Exception(
             "the environment variables are not set properly; output_uri, sources, and transformation are not set correctly."
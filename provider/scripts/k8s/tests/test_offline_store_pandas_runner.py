@@ -14,9 +14,18 @@ sys.path.insert(0, "provider/scripts/k8s")
 
 import pandas
 import pytest
+from deltalake import DeltaTable
 from dotenv import load_dotenv
 
-from offline_store_pandas_runner import K8S_MODE, LOCAL, AZURE, S3, GCS, LOCAL_DATA_PATH
+from offline_store_pandas_runner import (
+    K8S_MODE,
+    LOCAL,
+    AZURE,
+    S3,
+    GCS,
+    DELTA,
+    LOCAL_DATA_PATH,
+)
 from offline_store_pandas_runner import (
     main,
     get_args,
@@ -233,6 +242,44 @@ def test_blob_stores(variables, request):
         assert os.path.isdir(f"{LOCAL_DATA_PATH}/{download_directory}")
 
 
+@pytest.mark.skipif(sys.platform.startswith("win"), reason="should not run on windows")
+def test_sql_job_delta_output(local_variables_success, tmp_path):
+    set_environment_variables(local_variables_success)
+    args = get_args()
+    set_environment_variables(local_variables_success, delete=True)
+    blob_store = get_blob_store(args.blob_credentials)
+    table = str(tmp_path / "delta_output")
+
+    output = execute_sql_job(
+        args.mode, table, args.transformation, args.sources, blob_store, DELTA
+    )
+    assert output == table
+    assert os.path.isdir(f"{table}/_delta_log")
+
+    # A second run overwrites the table, and the table can be read back as a source.
+    execute_sql_job(
+        args.mode,
+        table,
+        "SELECT * FROM source_0 LIMIT 1",
+        [table],
+        blob_store,
+        DELTA,
+    )
+    expected = pandas.read_csv(args.sources[0]).head(1)
+    copy = str(tmp_path / "delta_copy")
+    execute_sql_job(args.mode, copy, "SELECT * FROM source_0", [table], blob_store, DELTA)
+    actual = DeltaTable(copy).to_pandas()
+    assert len(actual) == len(expected)
+    assert DeltaTable(table).version() == 1
+
+    # A pinned source reads the version it names rather than the latest one.
+    pinned = str(tmp_path / "delta_pinned")
+    execute_sql_job(
+        args.mode, pinned, "SELECT * FROM source_0", [f"{table}@v0"], blob_store, DELTA
+    )
+    assert len(DeltaTable(pinned).to_pandas()) == len(pandas.read_csv(args.sources[0]))
+
+
 def set_environment_variables(variables, delete=False):
     for key, value in variables.items():
         if delete:
@@ -110,6 +110,7 @@ def main(args):
                 headers=args.headers,
                 credentials=args.credential,
                 is_update=args.is_update,
+                incremental=args.incremental,
             )
         elif args.transformation_type == "df":
             output_location = execute_df_job(
@@ -858,6 +859,7 @@ def execute_sql_query(
     headers,
     credentials,
     is_update=False,
+    incremental=None,
 ):
     # Executes the SQL Queries:
     # Parameters:
@@ -866,6 +868,8 @@ def execute_sql_query(
     #     sql_query: string (eg. "SELECT * FROM source_0)
     #     spark_configs: dict (eg. {"fs.azure.account.key.account_name.dfs.core.windows.net": "aksdfkai=="})
     #     sources: List(dict) containing the location of sources, their provider type and possible information about the file/directory
+    #     incremental: dict (eg. {"mergeKeys": ["id"], "previous": "s3://bucket/path/2024-01-01-00-00-00-000000"}); if set,
+    #         the query's rows are merged into the existing output rather than replacing it
     # Return:
     #     output_uri_with_timestamp: string (output s3 path)
     try:
@@ -892,13 +896,30 @@ def execute_sql_query(
         # implemented for DF transformations
         output_uri_with_timestamp = ""
         print(f"Writing output to {output_location} of type {output_location_type}")
-        if output_location_type == "filestore":
+        if output_location_type == "filestore" and output.get("tableFormat"):
+            write_filestore_table(
+                spark,
+                output_dataframe,
+                output_location,
+                output.get("tableFormat"),
+                incremental,
+            )
+            output_uri_with_timestamp = output_location
+        elif output_location_type == "filestore":
             dt = datetime.datetime.now()
             safe_datetime = dt.strftime("%Y-%m-%d-%H-%M-%S-%f")
 
             # remove the '/' at the end of output_uri in order to avoid double slashes in the output file path.
             output_uri_with_timestamp = f"{output_location.rstrip('/')}/{safe_datetime}"
 
+            if incremental is not None and incremental.get("previous"):
+                previous_df = read_previous_output(
+                    spark, incremental["previous"], output_format
+                )
+                output_dataframe = merge_incremental(
+                    previous_df, output_dataframe, incremental
+                )
+
             if output_format == OutputFormat.PARQUET:
                 if headers == Headers.EXCLUDE:
                     raise Exception(
@@ -920,7 +941,17 @@ def execute_sql_query(
         elif output_location_type == "catalog":
             table_format = output.get("tableFormat")
 
-            if table_format == "iceberg":
+            if incremental is not None:
+                table = output_location
+                if table_format == "iceberg":
+                    table = "ff_catalog." + output_location
+                elif table_format == "delta":
+                    table = output_location.replace("-", "_")
+                print(f"Merging incremental rows into {table_format} table: ", table)
+                write_incremental_catalog_table(
+                    spark, output_dataframe, table, incremental
+                )
+            elif table_format == "iceberg":
                 glue_table = "ff_catalog." + output_location
                 print("Writing to iceberg table: ", glue_table)
                 final_write_obj = output_dataframe.writeTo(glue_table)
@@ -958,6 +989,96 @@ def execute_sql_query(
         raise e
 
 
+def filestore_table_identifier(location, table_format):
+    # Tables in the file store are addressed by path rather than by name in a catalog.
+    if table_format == "delta":
+        return f"delta.`{location}`"
+    elif table_format == "iceberg":
+        return f"ff_files.`{location}`"
+    raise Exception(
+        f"the table format '{table_format}' is not supported. Supported types: Apache Iceberg and Delta Lake"
+    )
+
+
+def filestore_table_exists(spark, table):
+    try:
+        spark.table(table).schema
+        return True
+    except Exception:
+        return False
+
+
+def write_filestore_table(spark, df, location, table_format, incremental):
+    # Each write is a single commit, so readers never see a partial output and earlier
+    # versions stay available for time travel until they are expired.
+    table = filestore_table_identifier(location, table_format)
+    if incremental is not None and filestore_table_exists(spark, table):
+        print(f"Merging incremental rows into {table_format} table: ", location)
+        write_incremental_catalog_table(spark, df, table, incremental)
+    elif table_format == "delta":
+        print("Writing to delta table: ", location)
+        df.write.format("delta").mode("overwrite").option(
+            "overwriteSchema", "true"
+        ).save(location)
+    else:
+        print("Writing to iceberg table: ", location)
+        df.writeTo(table).createOrReplace()
+    print(f"Successfully wrote {table_format} table {location}")
+
+
+def read_previous_output(spark, location, output_format):
+    if output_format == OutputFormat.CSV:
+        return spark.read.option("header", "true").csv(location)
+    return spark.read.parquet(location)
+
+
+def incremental_backfill_condition(incremental):
+    # Matches the rows a backfill replaces, or None if this isn't a backfill.
+    end = incremental.get("backfillEnd")
+    if not end:
+        return None
+    column = F.col(incremental["timestampColumn"])
+    return (column >= F.lit(incremental["backfillStart"]).cast("timestamp")) & (
+        column < F.lit(end).cast("timestamp")
+    )
+
+
+def merge_incremental(previous_df, new_df, incremental):
+    # Combines the rows already written with the new ones. Rows in a backfilled range, and rows
+    # with the same merge keys as a new row, are replaced; everything else is kept.
+    backfill = incremental_backfill_condition(incremental)
+    if backfill is not None:
+        previous_df = previous_df.filter(
+            F.col(incremental["timestampColumn"]).isNull() | ~backfill
+        )
+    merge_keys = incremental.get("mergeKeys") or []
+    if merge_keys:
+        previous_df = previous_df.join(
+            new_df.select(*merge_keys).distinct(), on=merge_keys, how="left_anti"
+        )
+    return previous_df.unionByName(new_df)
+
+
+def write_incremental_catalog_table(spark, new_df, table, incremental):
+    backfill = incremental_backfill_condition(incremental)
+    if backfill is not None:
+        column = incremental["timestampColumn"]
+        spark.sql(
+            f"DELETE FROM {table} WHERE {column} >= TIMESTAMP '{incremental['backfillStart']}' "
+            f"AND {column} < TIMESTAMP '{incremental['backfillEnd']}'"
+        )
+    merge_keys = incremental.get("mergeKeys") or []
+    if not merge_keys:
+        new_df.writeTo(table).append()
+        return
+    new_df.createOrReplaceTempView("ff_incremental")
+    on = " AND ".join(f"target.{key} = source.{key}" for key in merge_keys)
+    spark.sql(
+        f"MERGE INTO {table} target USING ff_incremental source ON {on} "
+        "WHEN MATCHED THEN UPDATE SET * WHEN NOT MATCHED THEN INSERT *"
+    )
+
+
 def get_source_df(source, credentials, is_update, spark):
     location = source.get("location")
     location_type = source.get("locationType")
@@ -968,6 +1089,10 @@ def get_source_df(source, credentials, is_update, spark):
             table = "ff_catalog." + location
             role_arn = source.get("awsAssumeRoleArn")
             spark_reader = spark.read.format("org.apache.iceberg.spark.source.IcebergSource")
+            if source.get("asOf"):
+                spark_reader = spark_reader.option(
+                    "as-of-timestamp", str(as_of_millis(source.get("asOf")))
+                )
             has_new_data = True
             if role_arn is not None:
                 spark.conf.set("spark.hadoop.fs.s3a.assumed.role.arn", role_arn)
@@ -982,6 +1107,12 @@ def get_source_df(source, credentials, is_update, spark):
             print(f"Reading Delta table: {location}")
             if source.get("isIncremental") and is_update:
                 source_df = get_incremental_delta_records(spark, location)
+            elif source.get("asOf"):
+                source_df = (
+                    spark.read.format("delta")
+                    .option("timestampAsOf", source.get("asOf"))
+                    .table(location)
+                )
             else:
                 source_df = spark.read.format("delta").table(location)
             return source_df
@@ -1029,6 +1160,15 @@ def get_source_df(source, credentials, is_update, spark):
             raise Exception(f"Failed to read from BigQuery table: {str(e)}")
         timestamp_column = source.get("timestampColumnName")
         return source_df
+    elif location_type == "filestore" and source.get("tableFormat"):
+        table_format = source.get("tableFormat")
+        print(f"Reading {table_format} table: {location}")
+        reader = spark.read.format(table_format)
+        version = source.get("version")
+        if version:
+            print(f"Reading version {version} of {location}")
+            reader = reader.option(table_version_option(table_format), version)
+        return reader.load(location)
     elif location_type == "filestore":
         file_extension = Path(location).suffix
         is_directory = file_extension == ""
@@ -1061,6 +1201,23 @@ def get_source_df(source, credentials, is_update, spark):
         )
 
 
+def table_version_option(table_format):
+    """
+    Returns the read option that pins a Delta version or an Iceberg snapshot ID.
+    """
+    if table_format == "iceberg":
+        return "snapshot-id"
+    return "versionAsOf"
+
+
+def as_of_millis(as_of):
+    """
+    Converts a pinned UTC timestamp (e.g. "2025-01-02 03:04:05.000") to epoch milliseconds.
+    """
+    dt = datetime.datetime.strptime(as_of, "%Y-%m-%d %H:%M:%S.%f")
+    return int(dt.replace(tzinfo=datetime.timezone.utc).timestamp() * 1000)
+
+
 def partition_delta_by_timestamp(df, output_location, column):
     df = df.withColumn("date", F.date_format(F.col(column), "yyyy-MM-dd"))
 
@@ -1181,7 +1338,16 @@ def execute_df_job(
         # remove the '/' at the end of output_uri in order to avoid double slashes in the output file path.
         output_uri_with_timestamp = f"{output_location.rstrip('/')}/{safe_datetime}"
 
-        if output_location_type == "filestore":
+        if output_location_type == "filestore" and output.get("tableFormat"):
+            write_filestore_table(
+                spark,
+                output_dataframe,
+                output_location,
+                output.get("tableFormat"),
+                None,
+            )
+            output_uri_with_timestamp = output_location
+        elif output_location_type == "filestore":
             dt = datetime.datetime.now()
             safe_datetime = dt.strftime("%Y-%m-%d-%H-%M-%S-%f")
 
@@ -1555,6 +1721,8 @@ def setup_common_parser(parser):
     parser.add_argument("--submit_params_uri", help="Path to the submit params file.")
     parser.add_argument("--is_update", default=False, action=BoolAction,
                         help="Specifies if this transform has been run successfully before, and that this is an update.")
+    parser.add_argument("--incremental", default=None, action=JsonAction,
+                        help="Merge the output into the existing table rather than replacing it; e.g., {\"mergeKeys\": [\"id\"]}")
     parser.add_argument("--direct_copy_use_iceberg", default=False, action=BoolAction, help="Specifies that we should use the new implementation of materialization that uses iceberg tables")
     parser.add_argument("--direct_copy_target", help="The type of provider we're doing a direct copy to. ex. dynamo")
     parser.add_argument("--direct_copy_table_name", help="If doing a direct copy, this is the table it'll be copied to")
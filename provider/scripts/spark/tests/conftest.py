@@ -69,6 +69,7 @@ def sql_all_arguments():
         direct_copy_entity_column=None,
         direct_copy_value_column=None,
         direct_copy_timestamp_column=None,
+        incremental=None,
     )
     return (input_args, expected_args)
 
@@ -133,6 +134,7 @@ def sql_partial_arguments():
         direct_copy_entity_column=None,
         direct_copy_value_column=None,
         direct_copy_timestamp_column=None,
+        incremental=None,
     )
     return (input_args, expected_args)
 
@@ -236,6 +238,7 @@ def df_all_arguments():
         direct_copy_entity_column=None,
         direct_copy_value_column=None,
         direct_copy_timestamp_column=None,
+        incremental=None,
     )
     return (input_args, expected_args)
 
@@ -327,6 +330,7 @@ def sql_databricks_all_arguments():
         direct_copy_entity_column=None,
         direct_copy_value_column=None,
         direct_copy_timestamp_column=None,
+        incremental=None,
     )
     return input_args, expected_args
 
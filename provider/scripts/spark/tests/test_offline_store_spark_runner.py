@@ -26,6 +26,9 @@ from offline_store_spark_runner import (
     delete_file,
     check_dill_exception,
     get_s3_object,
+    filestore_table_identifier,
+    table_version_option,
+    as_of_millis,
 )
 
 
@@ -177,6 +180,20 @@ def test_delete_file(tmp_path):
     assert not os.path.isfile(file_path)
 
 
+def test_filestore_table_identifier():
+    location = "s3a://bucket/featureform/Transformation/t/v"
+    assert filestore_table_identifier(location, "delta") == f"delta.`{location}`"
+    assert filestore_table_identifier(location, "iceberg") == f"ff_files.`{location}`"
+    with pytest.raises(Exception):
+        filestore_table_identifier(location, "hudi")
+
+
+def test_pinned_table_reads():
+    assert table_version_option("delta") == "versionAsOf"
+    assert table_version_option("iceberg") == "snapshot-id"
+    assert as_of_millis("2025-01-02 03:04:05.678") == 1735787045678
+
+
 def test_split_key_value():
     key_values = ["a=b", "b=c", "c=b", "d=e=="]
     expected_output = {"a": "b", "b": "c", "c": "b", "d": "e=="}
@@ -0,0 +1,386 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
+	"github.com/featureform/filestore"
+	"github.com/featureform/provider/dataset"
+	pl "github.com/featureform/provider/location"
+	tsq "github.com/featureform/provider/tsquery"
+)
+
+// SnapshotPolicy controls whether a training set pins snapshots of the data it reads and writes,
+// and how long they're kept.
+type SnapshotPolicy struct {
+	Enabled bool
+	// Retention is how long a run's snapshots are kept. Stores that expire old versions on their
+	// own, like Snowflake, extend their retention to cover it. Zero keeps snapshots until
+	// KeepLast releases them.
+	Retention time.Duration
+	// KeepLast is how many of the newest pinned runs keep their snapshots regardless of
+	// Retention. Zero doesn't keep any runs past Retention.
+	KeepLast int
+}
+
+// Expired reports whether the snapshots of a run pinned at taken should be released, given the
+// number of newer runs that pinned snapshots. With neither Retention nor KeepLast set,
+// snapshots are kept forever.
+func (p SnapshotPolicy) Expired(taken time.Time, newer int, now time.Time) bool {
+	if p.KeepLast > 0 && newer < p.KeepLast {
+		return false
+	}
+	if p.Retention > 0 {
+		return now.Sub(taken) > p.Retention
+	}
+	return p.KeepLast > 0
+}
+
+// SnapshotOfflineStore is implemented by offline stores that can pin the data a training set
+// reads and writes, so that it can be re-created and served exactly as it was.
+type SnapshotOfflineStore interface {
+	// PinTrainingSetInputs snapshots the label or spine, features and additional labels of def.
+	// Setting the result as def.Snapshots makes CreateTrainingSet read the snapshots.
+	PinTrainingSetInputs(def TrainingSetDef, policy SnapshotPolicy) ([]fftypes.DataSnapshot, error)
+	// PinTrainingSet snapshots the training set that def created.
+	PinTrainingSet(def TrainingSetDef, policy SnapshotPolicy) (fftypes.DataSnapshot, error)
+	// GetTrainingSetSnapshot returns the training set as it was when snapshot was pinned.
+	GetTrainingSetSnapshot(id ResourceID, snapshot fftypes.DataSnapshot) (dataset.TrainingSetIterator, error)
+	// ReleaseSnapshots lets the store reclaim the data that snapshots pinned.
+	ReleaseSnapshots(snapshots []fftypes.DataSnapshot) error
+}
+
+// trainingSetInput is a table that a training set reads.
+type trainingSetInput struct {
+	resource string
+	location pl.Location
+}
+
+// snapshotLocation identifies the table at loc among a training set's inputs. SQL locations are
+// fully qualified, since their Location is only the table's name.
+func snapshotLocation(loc pl.Location) string {
+	if sqlLoc, ok := loc.(*pl.SQLLocation); ok {
+		return sqlLoc.TableLocation().String()
+	}
+	return loc.Location()
+}
+
+func snapshotResourceName(id ResourceID) string {
+	return fmt.Sprintf("%s %s (%s)", id.Type, id.Name, id.Variant)
+}
+
+// inputs returns the tables def reads, as located by its source mappings. Tables read by more
+// than one resource are only returned once.
+func (def *TrainingSetDef) inputs() []trainingSetInput {
+	inputs := make([]trainingSetInput, 0, 1+len(def.FeatureSourceMappings)+len(def.AdditionalLabelSourceMappings))
+	seen := make(map[string]bool)
+	add := func(id ResourceID, mapping SourceMapping) {
+		if mapping.Location == nil || seen[snapshotLocation(mapping.Location)] {
+			return
+		}
+		seen[snapshotLocation(mapping.Location)] = true
+		inputs = append(inputs, trainingSetInput{resource: snapshotResourceName(id), location: mapping.Location})
+	}
+	if def.Spine != nil {
+		add(*def.Spine, def.LabelSourceMapping)
+	} else {
+		add(def.Label, def.LabelSourceMapping)
+	}
+	for i, mapping := range def.FeatureSourceMappings {
+		add(def.Features[i], mapping)
+	}
+	for i, mapping := range def.AdditionalLabelSourceMappings {
+		add(def.AdditionalLabels[i], mapping)
+	}
+	return inputs
+}
+
+// inputSnapshot returns the pinned snapshot of the input at location, as returned by
+// snapshotLocation, if def has one.
+func (def *TrainingSetDef) inputSnapshot(location string) (fftypes.DataSnapshot, bool) {
+	for _, snapshot := range def.Snapshots {
+		if !snapshot.Output && snapshot.Location == location {
+			return snapshot, true
+		}
+	}
+	return fftypes.DataSnapshot{}, false
+}
+
+// setSnapshotTables points params at the pinned snapshots of def's inputs. tableFn returns the
+// table expression that reads a snapshot of table, which is written the way params has it.
+func (def *TrainingSetDef) setSnapshotTables(params *tsq.BuilderParams, tableFn func(table string, snapshot fftypes.DataSnapshot) string) {
+	if len(def.Snapshots) == 0 {
+		return
+	}
+	snapshotTable := func(table string, loc pl.Location) string {
+		if loc == nil {
+			return ""
+		}
+		snapshot, ok := def.inputSnapshot(snapshotLocation(loc))
+		if !ok {
+			return ""
+		}
+		return tableFn(table, snapshot)
+	}
+	params.LabelTableSnapshot = snapshotTable(params.SanitizedLabelTable, def.LabelSourceMapping.Location)
+	params.FeatureTableSnapshots = make([]string, len(def.FeatureSourceMappings))
+	for i, mapping := range def.FeatureSourceMappings {
+		params.FeatureTableSnapshots[i] = snapshotTable(params.SanitizedFeatureTables[i], mapping.Location)
+	}
+	params.AdditionalLabelTableSnapshots = make([]string, len(def.AdditionalLabelSourceMappings))
+	for i, mapping := range def.AdditionalLabelSourceMappings {
+		params.AdditionalLabelTableSnapshots[i] = snapshotTable(params.SanitizedAdditionalLabelTables[i], mapping.Location)
+	}
+}
+
+func checkSnapshotNotReleased(id ResourceID, snapshot fftypes.DataSnapshot) error {
+	if snapshot.Released {
+		return fferr.NewDatasetNotFoundError(id.Name, id.Variant, fmt.Errorf("snapshot of %s taken at %s has been released", snapshot.Location, snapshot.Timestamp))
+	}
+	return nil
+}
+
+// fileStoreSnapshotsDir holds the copies of plain files pinned by training sets on file stores.
+const fileStoreSnapshotsDir = "featureform/Snapshots"
+
+// fileStoreSnapshotDir returns the directory that a training set run copies its pinned files to.
+func fileStoreSnapshotDir(store FileStore, id ResourceID, taken time.Time) (filestore.Filepath, error) {
+	return store.CreateFilePath(fmt.Sprintf("%s/%s/%s/%d", fileStoreSnapshotsDir, id.Name, id.Variant, taken.UnixMilli()), true)
+}
+
+// pinFileStoreLocation pins the data at a file store location: the current version of a Delta or
+// Iceberg table, or otherwise a copy of its files, which is written to copyDir.
+func pinFileStoreLocation(store FileStore, loc *pl.FileStoreLocation, copyDir filestore.Filepath, taken time.Time) (fftypes.DataSnapshot, error) {
+	snapshot := fftypes.DataSnapshot{Location: loc.Location(), Timestamp: taken}
+	src := loc.Filepath()
+	if src.Ext() != "" {
+		dest, err := tableChildPath(store, copyDir, path.Base(src.Key()), false)
+		if err != nil {
+			return fftypes.DataSnapshot{}, err
+		}
+		if err := copyFileStoreFile(store, src, dest); err != nil {
+			return fftypes.DataSnapshot{}, err
+		}
+		snapshot.Kind = fftypes.FileCopySnapshot
+		snapshot.Version = dest.ToURI()
+		return snapshot, nil
+	}
+	dir, err := store.CreateFilePath(src.Key(), true)
+	if err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	format := filestore.FileType(loc.TableFormat())
+	if format == "" {
+		if format, err = DetectFileStoreTableFormat(store, dir); err != nil {
+			return fftypes.DataSnapshot{}, err
+		}
+	}
+	if format != "" {
+		reader, err := NewFileStoreTableReader(store, dir, format)
+		if err != nil {
+			return fftypes.DataSnapshot{}, err
+		}
+		version, err := reader.LatestVersion()
+		if err != nil {
+			return fftypes.DataSnapshot{}, err
+		}
+		snapshot.Kind = fftypes.TableVersionSnapshot
+		snapshot.TableFormat = string(format)
+		snapshot.Version = strconv.FormatInt(version, 10)
+		return snapshot, nil
+	}
+	files, err := store.List(dir, filestore.Parquet)
+	if err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	if len(files) == 0 {
+		return fftypes.DataSnapshot{}, fferr.NewDatasetLocationNotFoundError(dir.ToURI(), fmt.Errorf("no files to pin"))
+	}
+	if err := copyFileStoreFiles(store, files, dir, copyDir); err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	snapshot.Kind = fftypes.FileCopySnapshot
+	snapshot.Version = copyDir.ToURI()
+	return snapshot, nil
+}
+
+// pinFileStoreTrainingSet pins the current contents of a training set's directory: the current
+// version of a table, or otherwise a copy of the newest run's files.
+func pinFileStoreTrainingSet(store FileStore, def TrainingSetDef, taken time.Time) (fftypes.DataSnapshot, error) {
+	dir, err := store.CreateFilePath(def.ID.ToFilestorePath(), true)
+	if err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	snapshot := fftypes.DataSnapshot{Resource: snapshotResourceName(def.ID), Location: dir.ToURI(), Timestamp: taken, Output: true}
+	format, err := DetectFileStoreTableFormat(store, dir)
+	if err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	if format != "" {
+		pinned, err := pinFileStoreLocation(store, pl.NewFileTableLocation(dir, string(format)).(*pl.FileStoreLocation), nil, taken)
+		if err != nil {
+			return fftypes.DataSnapshot{}, err
+		}
+		snapshot.Kind, snapshot.TableFormat, snapshot.Version = pinned.Kind, pinned.TableFormat, pinned.Version
+		return snapshot, nil
+	}
+	files, err := fileStoreNewestFiles(store, dir)
+	if err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	snapshotDir, err := fileStoreSnapshotDir(store, def.ID, taken)
+	if err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	copyDir, err := tableChildPath(store, snapshotDir, "output", true)
+	if err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	if err := copyFileStoreFiles(store, files, dir, copyDir); err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	snapshot.Kind = fftypes.FileCopySnapshot
+	snapshot.Version = copyDir.ToURI()
+	return snapshot, nil
+}
+
+// copyFileStoreFiles copies files from under fromDir to the same relative paths under toDir, so
+// that readers group the copies the way they group the originals.
+func copyFileStoreFiles(store FileStore, files []filestore.Filepath, fromDir, toDir filestore.Filepath) error {
+	prefix := strings.TrimSuffix(fromDir.Key(), "/") + "/"
+	for _, file := range files {
+		rel := strings.TrimPrefix(file.Key(), prefix)
+		dest, err := tableChildPath(store, toDir, rel, false)
+		if err != nil {
+			return err
+		}
+		if err := copyFileStoreFile(store, file, dest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFileStoreFile(store FileStore, src, dest filestore.Filepath) error {
+	data, err := store.Read(src)
+	if err != nil {
+		return err
+	}
+	return store.Write(dest, data)
+}
+
+// serveFileStoreSnapshot returns an iterator over the data a file store snapshot pinned.
+func serveFileStoreSnapshot(store FileStore, snapshot fftypes.DataSnapshot) (Iterator, error) {
+	switch snapshot.Kind {
+	case fftypes.TableVersionSnapshot:
+		dir, err := store.ParseFilePath(snapshot.Location)
+		if err != nil {
+			return nil, err
+		}
+		version, err := strconv.ParseInt(snapshot.Version, 10, 64)
+		if err != nil {
+			return nil, fferr.NewInternalErrorf("invalid %s table version %q: %v", snapshot.TableFormat, snapshot.Version, err)
+		}
+		return ServeFileStoreTable(store, dir, filestore.FileType(snapshot.TableFormat), version)
+	case fftypes.FileCopySnapshot:
+		copyPath, err := store.ParseFilePath(snapshot.Version)
+		if err != nil {
+			return nil, err
+		}
+		if copyPath.Ext() != "" {
+			return store.Serve([]filestore.Filepath{copyPath})
+		}
+		copyDir, err := store.CreateFilePath(copyPath.Key(), true)
+		if err != nil {
+			return nil, err
+		}
+		files, err := fileStoreNewestFiles(store, copyDir)
+		if err != nil {
+			return nil, err
+		}
+		return store.Serve(files)
+	default:
+		return nil, fferr.NewInvalidArgumentErrorf("file stores can't serve %s snapshots", snapshot.Kind)
+	}
+}
+
+// releaseFileStoreSnapshots deletes the copies made for FileCopySnapshots. Table versions are left
+// for the table's own retention, e.g. Delta's VACUUM or Iceberg's snapshot expiration, to remove.
+func releaseFileStoreSnapshots(store FileStore, snapshots []fftypes.DataSnapshot) error {
+	for _, snapshot := range snapshots {
+		if snapshot.Kind != fftypes.FileCopySnapshot || snapshot.Released {
+			continue
+		}
+		copyPath, err := store.ParseFilePath(snapshot.Version)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(copyPath.Key(), fileStoreSnapshotsDir+"/") {
+			return fferr.NewInternalErrorf("refusing to release %s, which isn't a pinned copy", copyPath.ToURI())
+		}
+		if copyPath.Ext() != "" {
+			err = store.Delete(copyPath)
+		} else {
+			err = store.DeleteAll(copyPath)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pinFileStoreInputs pins the file store and catalog tables that a Spark or Kubernetes training
+// set reads, copying plain files under the training set's snapshot directory.
+func pinFileStoreInputs(store FileStore, def TrainingSetDef, inputs []trainingSetInput) ([]fftypes.DataSnapshot, error) {
+	taken := time.Now().UTC()
+	snapshotDir, err := fileStoreSnapshotDir(store, def.ID, taken)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]fftypes.DataSnapshot, 0, len(inputs))
+	for i, input := range inputs {
+		var snapshot fftypes.DataSnapshot
+		switch loc := input.location.(type) {
+		case *pl.FileStoreLocation:
+			copyDir, err := tableChildPath(store, snapshotDir, fmt.Sprintf("input_%d", i), true)
+			if err != nil {
+				return nil, err
+			}
+			if snapshot, err = pinFileStoreLocation(store, loc, copyDir, taken); err != nil {
+				return nil, err
+			}
+		case *pl.CatalogLocation:
+			snapshot = fftypes.DataSnapshot{Location: loc.Location(), Kind: fftypes.TimeTravelSnapshot, TableFormat: loc.TableFormat(), Timestamp: taken}
+		default:
+			return nil, fferr.NewInvalidArgumentErrorf("can't pin %s, which is read from a %T", input.resource, input.location)
+		}
+		snapshot.Resource = input.resource
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// fileStoreGetTrainingSetSnapshot serves a training set on a file store as of an output snapshot.
+func fileStoreGetTrainingSetSnapshot(id ResourceID, store FileStore, snapshot fftypes.DataSnapshot) (dataset.TrainingSetIterator, error) {
+	if err := checkSnapshotNotReleased(id, snapshot); err != nil {
+		return nil, err
+	}
+	iter, err := serveFileStoreSnapshot(store, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return NewLegacyTrainingSetIteratorAdapter(&FileStoreTrainingSet{id: id, store: store, iter: iter}), nil
+}
@@ -0,0 +1,155 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	fftypes "github.com/featureform/fftypes"
+	pl "github.com/featureform/provider/location"
+)
+
+func TestSnapshotPolicyExpired(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+	recent := now.Add(-time.Hour)
+	cases := []struct {
+		name    string
+		policy  SnapshotPolicy
+		taken   time.Time
+		newer   int
+		expired bool
+	}{
+		{"no limits", SnapshotPolicy{Enabled: true}, old, 10, false},
+		{"within retention", SnapshotPolicy{Enabled: true, Retention: 24 * time.Hour}, recent, 10, false},
+		{"past retention", SnapshotPolicy{Enabled: true, Retention: 24 * time.Hour}, old, 10, true},
+		{"kept by keep last", SnapshotPolicy{Enabled: true, Retention: 24 * time.Hour, KeepLast: 2}, old, 1, false},
+		{"past keep last", SnapshotPolicy{Enabled: true, KeepLast: 2}, recent, 2, true},
+		{"past keep last within retention", SnapshotPolicy{Enabled: true, Retention: 24 * time.Hour, KeepLast: 2}, recent, 2, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.policy.Expired(c.taken, c.newer, now); got != c.expired {
+				t.Fatalf("Expected expired %v, got %v", c.expired, got)
+			}
+		})
+	}
+}
+
+func TestFileStoreSnapshots(t *testing.T) {
+	store, labelRoot, labelDir := tableTestStore(t)
+	root := strings.TrimSuffix(labelRoot, "featureform/Transformation/tbl/v1")
+	writeTableTestParquet(t, labelRoot, "2024-01-01-00-00-00-000000/part-0000.parquet", "old")
+	featureDir, err := store.CreateFilePath("featureform/Transformation/delta/v1", true)
+	if err != nil {
+		t.Fatalf("Failed to create feature path: %v", err)
+	}
+	writeDeltaTestTable(t, filepath.Join(root, "featureform/Transformation/delta/v1"))
+
+	def := TrainingSetDef{
+		ID:                    ResourceID{Name: "ts", Variant: "v1", Type: TrainingSet},
+		Label:                 ResourceID{Name: "label", Variant: "v1", Type: Label},
+		LabelSourceMapping:    SourceMapping{Location: pl.NewFileLocation(labelDir)},
+		Features:              []ResourceID{{Name: "f1", Variant: "v1", Type: Feature}, {Name: "f2", Variant: "v1", Type: Feature}},
+		FeatureSourceMappings: []SourceMapping{{Location: pl.NewFileLocation(featureDir)}, {Location: pl.NewFileLocation(featureDir)}},
+	}
+	inputs := def.inputs()
+	if len(inputs) != 2 {
+		t.Fatalf("Expected the shared feature table to be pinned once, got %d inputs", len(inputs))
+	}
+	snapshots, err := pinFileStoreInputs(store, def, inputs)
+	if err != nil {
+		t.Fatalf("Failed to pin inputs: %v", err)
+	}
+	kinds := []fftypes.SnapshotKind{snapshots[0].Kind, snapshots[1].Kind}
+	if !reflect.DeepEqual(kinds, []fftypes.SnapshotKind{fftypes.FileCopySnapshot, fftypes.TableVersionSnapshot}) {
+		t.Fatalf("Expected a copy of the label and a version of the feature table, got %v", kinds)
+	}
+	if snapshots[1].Version != "3" || snapshots[1].TableFormat != "delta" {
+		t.Fatalf("Expected delta version 3, got %s version %s", snapshots[1].TableFormat, snapshots[1].Version)
+	}
+	def.Snapshots = snapshots
+	if _, ok := def.inputSnapshot(snapshotLocation(def.FeatureSourceMappings[1].Location)); !ok {
+		t.Fatalf("Expected the second feature to read the pinned feature table")
+	}
+
+	// A newer run of the label's source doesn't change what the snapshot serves.
+	writeTableTestParquet(t, labelRoot, "2024-06-01-00-00-00-000000/part-0000.parquet", "new")
+	iter, err := serveFileStoreSnapshot(store, snapshots[0])
+	if err != nil {
+		t.Fatalf("Failed to serve label snapshot: %v", err)
+	}
+	if entities := tableTestEntities(t, iter); !reflect.DeepEqual(entities, []string{"old"}) {
+		t.Fatalf("Expected the pinned label, got %v", entities)
+	}
+	iter, err = serveFileStoreSnapshot(store, snapshots[1])
+	if err != nil {
+		t.Fatalf("Failed to serve feature snapshot: %v", err)
+	}
+	if entities := tableTestEntities(t, iter); !reflect.DeepEqual(entities, []string{"b1", "c1", "d1", "d2"}) {
+		t.Fatalf("Expected the feature table at version 3, got %v", entities)
+	}
+
+	tsRoot := filepath.Join(root, def.ID.ToFilestorePath())
+	writeTableTestParquet(t, tsRoot, "2024-01-01-00-00-00-000000/part-0000.parquet", "ts-old")
+	writeTableTestParquet(t, tsRoot, "2024-06-01-00-00-00-000000/part-0000.parquet", "ts-new")
+	output, err := pinFileStoreTrainingSet(store, def, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("Failed to pin training set: %v", err)
+	}
+	if !output.Output || output.Kind != fftypes.FileCopySnapshot {
+		t.Fatalf("Expected an output copy, got %+v", output)
+	}
+	iter, err = serveFileStoreSnapshot(store, output)
+	if err != nil {
+		t.Fatalf("Failed to serve training set snapshot: %v", err)
+	}
+	if entities := tableTestEntities(t, iter); !reflect.DeepEqual(entities, []string{"ts-new"}) {
+		t.Fatalf("Expected the newest training set run, got %v", entities)
+	}
+
+	if err := releaseFileStoreSnapshots(store, append(snapshots, output)); err != nil {
+		t.Fatalf("Failed to release snapshots: %v", err)
+	}
+	for _, snapshot := range []fftypes.DataSnapshot{snapshots[0], output} {
+		copyPath, err := store.ParseFilePath(snapshot.Version)
+		if err != nil {
+			t.Fatalf("Failed to parse copy path: %v", err)
+		}
+		// Local stores leave empty directories behind, so only look for files.
+		err = filepath.Walk(filepath.Join(root, copyPath.Key()), func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				t.Fatalf("Expected %s to be deleted", path)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to walk %s: %v", copyPath.Key(), err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "featureform/Transformation/delta/v1", deltaCommitName(3))); err != nil {
+		t.Fatalf("Expected the feature table to be left alone: %v", err)
+	}
+	output.Released = true
+	if _, err := fileStoreGetTrainingSetSnapshot(def.ID, store, output); err == nil {
+		t.Fatalf("Expected serving a released snapshot to fail")
+	}
+}
+
+func TestFileStoreSnapshotsReleaseOnlyCopies(t *testing.T) {
+	store, _, dir := tableTestStore(t)
+	snapshot := fftypes.DataSnapshot{Kind: fftypes.FileCopySnapshot, Location: dir.ToURI(), Version: dir.ToURI()}
+	if err := releaseFileStoreSnapshots(store, []fftypes.DataSnapshot{snapshot}); err == nil {
+		t.Fatalf("Expected releasing a path outside the snapshots directory to fail")
+	}
+}
@@ -9,10 +9,14 @@ package provider
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
 	"github.com/featureform/helpers/stringset"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
@@ -90,6 +94,15 @@ func (sf *snowflakeOfflineStore) CreateTransformation(config TransformationConfi
 		logger.Errorw("Failed to get transformation table name", "error", err)
 		return err
 	}
+	if config.ResourceSnowflakeConfig.RefreshedBySnowflake() {
+		return sf.createRefreshedTransformation(tableName, config, logger)
+	}
+	// Dynamic tables are refreshed by Snowflake, so incremental transformations, which we merge
+	// into, are plain tables.
+	if config.Incremental != nil {
+		logger.Info("Creating incremental transformation table")
+		return sf.sqlOfflineStore.CreateTransformation(config)
+	}
 	var snowflakeConfig pc.SnowflakeConfig
 	if err := snowflakeConfig.Deserialize(sf.sqlOfflineStore.Config()); err != nil {
 		logger.Errorw("Failed to deserialize snowflake config", "error", err)
@@ -125,10 +138,203 @@ func (sf *snowflakeOfflineStore) CreateTransformation(config TransformationConfi
 }
 
 func (sf *snowflakeOfflineStore) UpdateTransformation(config TransformationConfig, opts ...TransformationOption) error {
+	if config.ResourceSnowflakeConfig.RefreshedBySnowflake() {
+		tableName, err := sf.sqlOfflineStore.getTransformationTableName(config.TargetTableID)
+		if err != nil {
+			return err
+		}
+		return sf.checkLatestRefresh(config.TargetTableID, tableName, config.ResourceSnowflakeConfig.RefreshStrategy)
+	}
+	if config.Incremental != nil {
+		return sf.sqlOfflineStore.UpdateTransformation(config, opts...)
+	}
 	sf.logger.Errorw("Snowflake Offline Store does not currently support updating transformations", "config", config, "opts", opts)
 	return fferr.NewInternalErrorf("Snowflake Offline Store does not currently support updating transformations")
 }
 
+// createRefreshedTransformation creates a transformation that Snowflake keeps up to date, either as
+// a dynamic table or, for incremental transformations, as a table that a task merges the rows
+// streamed from its sources into.
+func (sf *snowflakeOfflineStore) createRefreshedTransformation(tableName string, config TransformationConfig, logger logging.Logger) error {
+	resConfig, err := sf.refreshedConfig(config.ResourceSnowflakeConfig)
+	if err != nil {
+		logger.Errorw("Failed to merge refresh config", "error", err)
+		return err
+	}
+	switch resConfig.RefreshStrategy {
+	case metadata.DynamicTableRefresh:
+		query := sf.sfQueries.dynamicTableCreate(tableName, config.Query, *resConfig)
+		return sf.execRefreshed(config.TargetTableID, []string{query}, logger)
+	case metadata.StreamRefresh:
+		if config.Incremental == nil || len(config.Incremental.StreamSources) == 0 || config.Incremental.StreamTemplate == "" {
+			return fferr.NewInvalidArgumentErrorf("Snowflake stream refreshes require an incremental SQL transformation")
+		}
+		return sf.createStreamedTransformation(tableName, config, *resConfig, logger)
+	default:
+		return fferr.NewInternalErrorf("unsupported Snowflake refresh strategy %s", resConfig.RefreshStrategy)
+	}
+}
+
+// createStreamedTransformation loads the table with the full query, then schedules a task that
+// merges the rows streamed from the transformation's incremental sources into it. The load reads
+// the sources as of a single time, and the streams start at that time, so that each row is either
+// loaded or streamed, never both.
+func (sf *snowflakeOfflineStore) createStreamedTransformation(tableName string, config TransformationConfig, resConfig metadata.ResourceSnowflakeConfig, logger logging.Logger) error {
+	schedule, err := streamTaskSchedule(resConfig.DynamicTableConfig.TargetLag)
+	if err != nil {
+		return err
+	}
+	at, err := sf.currentTimestamp()
+	if err != nil {
+		logger.Errorw("Failed to get current timestamp", "error", err)
+		return err
+	}
+	sources := config.Incremental.StreamSources
+	streams := make([]string, len(sources))
+	loadTables := make(map[string]string, len(sources))
+	streamTables := make(map[string]string, len(sources))
+	for i, source := range sources {
+		streams[i] = streamName(tableName, i)
+		loadTables[source.Key] = sf.sfQueries.timeTravelTable(source.Table, at)
+		streamTables[source.Key] = sanitize(streams[i])
+	}
+	loadQuery, err := fillStreamTemplate(config.Incremental.StreamTemplate, loadTables)
+	if err != nil {
+		return err
+	}
+	streamQuery, err := fillStreamTemplate(config.Incremental.StreamTemplate, streamTables)
+	if err != nil {
+		return err
+	}
+	queries := make([]string, 0, len(streams)+1)
+	queries = append(queries, sf.sfQueries.tableCreateAs(tableName, loadQuery))
+	for i, source := range sources {
+		queries = append(queries, sf.sfQueries.streamCreate(streams[i], source.Table, at))
+	}
+	if err := sf.execRefreshed(config.TargetTableID, queries, logger); err != nil {
+		return err
+	}
+	columns, err := sf.sfQueries.getColumns(sf.db, tableName)
+	if err != nil {
+		logger.Errorw("Failed to get transformation columns", "error", err)
+		return err
+	}
+	task := streamTaskName(tableName)
+	queries = []string{
+		sf.sfQueries.streamTaskCreate(task, tableName, streamQuery, streams, columns, config.Incremental.MergeKeys, resConfig, schedule),
+		sf.sfQueries.taskResume(task),
+	}
+	return sf.execRefreshed(config.TargetTableID, queries, logger)
+}
+
+// fillStreamTemplate replaces the stream sources' placeholders in a stream template with their
+// tables, which must cover every placeholder left in the template.
+func fillStreamTemplate(template string, tables map[string]string) (string, error) {
+	var sb strings.Builder
+	for {
+		split := strings.SplitN(template, "{{", 2)
+		sb.WriteString(split[0])
+		if len(split) == 1 {
+			return sb.String(), nil
+		}
+		afterSplit := strings.SplitN(split[1], "}}", 2)
+		if len(afterSplit) != 2 {
+			return "", fferr.NewInvalidArgumentErrorf("unterminated {{ in stream template")
+		}
+		key := strings.TrimSpace(afterSplit[0])
+		table, has := tables[key]
+		if !has {
+			return "", fferr.NewInternalErrorf("stream template placeholder %s isn't a stream source", key)
+		}
+		sb.WriteString(table)
+		template = afterSplit[1]
+	}
+}
+
+func (sf *snowflakeOfflineStore) execRefreshed(id ResourceID, queries []string, logger logging.Logger) error {
+	for _, query := range queries {
+		logger.Debugw("Creating table refreshed by Snowflake", "query", query)
+		if _, err := sf.sqlOfflineStore.db.Exec(query); err != nil {
+			logger.Errorw("Failed to create table refreshed by Snowflake", "query", query, "error", err)
+			wrapped := fferr.NewResourceExecutionError(pt.SnowflakeOffline.String(), id.Name, id.Variant, fferr.ResourceType(id.Type.String()), err)
+			return sf.handleErr(wrapped, err)
+		}
+	}
+	logger.Info("Successfully created table refreshed by Snowflake")
+	return nil
+}
+
+// refreshedConfig fills in a copy of a resource's config from the provider's.
+func (sf *snowflakeOfflineStore) refreshedConfig(config *metadata.ResourceSnowflakeConfig) (*metadata.ResourceSnowflakeConfig, error) {
+	var snowflakeConfig pc.SnowflakeConfig
+	if err := snowflakeConfig.Deserialize(sf.sqlOfflineStore.Config()); err != nil {
+		return nil, err
+	}
+	merged := *config
+	if config.DynamicTableConfig != nil {
+		dynamicTableConfig := *config.DynamicTableConfig
+		merged.DynamicTableConfig = &dynamicTableConfig
+	}
+	if err := merged.MergeRefreshed(&snowflakeConfig); err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
+
+// snowflakeRefresh is the latest refresh Snowflake ran of a table it keeps up to date.
+type snowflakeRefresh struct {
+	State       string
+	Message     string
+	CompletedAt time.Time
+}
+
+func (r snowflakeRefresh) Failed() bool {
+	switch r.State {
+	case "FAILED", "UPSTREAM_FAILED", "FAILED_AND_AUTO_SUSPENDED":
+		return true
+	default:
+		return false
+	}
+}
+
+// latestRefresh returns the latest refresh of a table refreshed by Snowflake, or nil if Snowflake
+// hasn't refreshed it yet.
+func (sf *snowflakeOfflineStore) latestRefresh(tableName string, strategy metadata.SnowflakeRefreshStrategy) (*snowflakeRefresh, error) {
+	var refresh snowflakeRefresh
+	var completedAt sql.NullTime
+	row := sf.db.QueryRow(sf.sfQueries.latestRefreshQuery(tableName, strategy))
+	if err := row.Scan(&refresh.State, &refresh.Message, &completedAt); err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		wrapped := fferr.NewExecutionError(pt.SnowflakeOffline.String(), err)
+		wrapped.AddDetail("table_name", tableName)
+		return nil, wrapped
+	}
+	refresh.CompletedAt = completedAt.Time
+	return &refresh, nil
+}
+
+// checkLatestRefresh stands in for re-running the query of a resource refreshed by Snowflake,
+// failing if Snowflake's latest refresh of it failed.
+func (sf *snowflakeOfflineStore) checkLatestRefresh(id ResourceID, tableName string, strategy metadata.SnowflakeRefreshStrategy) error {
+	logger := sf.logger.WithResource(logging.ResourceType(id.Type.String()), id.Name, id.Variant).With("refresh_strategy", strategy)
+	refresh, err := sf.latestRefresh(tableName, strategy)
+	if err != nil {
+		logger.Errorw("Failed to get latest Snowflake refresh", "error", err)
+		return err
+	}
+	if refresh == nil {
+		logger.Info("Snowflake hasn't refreshed the table yet")
+		return nil
+	}
+	logger.Infow("Latest Snowflake refresh", "state", refresh.State, "completed_at", refresh.CompletedAt)
+	if refresh.Failed() {
+		err := fmt.Errorf("Snowflake refresh %s: %s", refresh.State, refresh.Message)
+		return fferr.NewResourceExecutionError(pt.SnowflakeOffline.String(), id.Name, id.Variant, fferr.ResourceType(id.Type.String()), err)
+	}
+	return nil
+}
+
 // Snowflake breaks with the pattern of other offline store that create resource tables for labels and features. (Resource tables are intermediate tables
 // between the sources on which labels and features are registered and materializations and training sets; they duplicate the data from the source tables
 // for the 2 columns provided, that is, entity, value, and timestamp.)
@@ -243,6 +449,9 @@ func (sf *snowflakeOfflineStore) CreateMaterialization(id ResourceID, opts Mater
 		logger.Errorw("Failed to validate resource ID", "error", err)
 		return dataset.Materialization{}, err
 	}
+	if opts.ResourceSnowflakeConfig.RefreshedBySnowflake() {
+		return sf.createRefreshedMaterialization(id, opts, logger)
+	}
 	var snowflakeConfig pc.SnowflakeConfig
 	if err := snowflakeConfig.Deserialize(sf.sqlOfflineStore.Config()); err != nil {
 		logger.Errorw("Failed to deserialize snowflake config", "error", err)
@@ -287,6 +496,10 @@ func (sf *snowflakeOfflineStore) CreateMaterialization(id ResourceID, opts Mater
 		return dataset.Materialization{}, sf.handleErr(wrapped, err)
 	}
 	logger.Info("Successfully created materialization")
+	return sf.materialization(id, tableName), nil
+}
+
+func (sf *snowflakeOfflineStore) materialization(id ResourceID, tableName string) dataset.Materialization {
 	mat := &sqlMaterialization{
 		id:           MaterializationID(fmt.Sprintf("%s__%s", id.Name, id.Variant)),
 		db:           sf.sqlOfflineStore.db,
@@ -295,10 +508,75 @@ func (sf *snowflakeOfflineStore) CreateMaterialization(id ResourceID, opts Mater
 		query:        sf.sfQueries,
 		providerType: pt.SnowflakeOffline,
 	}
-	return NewLegacyMaterializationAdapterWithEmptySchema(mat), nil
+	return NewLegacyMaterializationAdapterWithEmptySchema(mat)
+}
+
+// createRefreshedMaterialization creates a materialization as a dynamic table. Materializations
+// keep each entity's latest value, which can't be streamed, so they can't use stream refreshes.
+func (sf *snowflakeOfflineStore) createRefreshedMaterialization(id ResourceID, opts MaterializationOptions, logger logging.Logger) (dataset.Materialization, error) {
+	if opts.ResourceSnowflakeConfig.RefreshStrategy != metadata.DynamicTableRefresh {
+		return dataset.Materialization{}, fferr.NewInvalidArgumentErrorf("Snowflake %s refreshes aren't supported for features", opts.ResourceSnowflakeConfig.RefreshStrategy)
+	}
+	resConfig, err := sf.refreshedConfig(opts.ResourceSnowflakeConfig)
+	if err != nil {
+		logger.Errorw("Failed to merge refresh config", "error", err)
+		return dataset.Materialization{}, err
+	}
+	tableName, err := ps.ResourceToTableName(FeatureMaterialization.String(), id.Name, id.Variant)
+	if err != nil {
+		return dataset.Materialization{}, err
+	}
+	if err := opts.Schema.Validate(); err != nil {
+		return dataset.Materialization{}, err
+	}
+	sqlLoc, isSqlLoc := opts.Schema.SourceTable.(*pl.SQLLocation)
+	if !isSqlLoc {
+		return dataset.Materialization{}, fferr.NewInvalidArgumentErrorf("source table is not an SQL location")
+	}
+	materializationAsQuery := sf.sfQueries.materializationCreateAsQuery(opts.Schema.Entity, opts.Schema.Value, opts.Schema.TS, SanitizeSnowflakeIdentifier(sqlLoc.TableLocation()))
+	query := sf.sfQueries.dynamicTableCreate(tableName, materializationAsQuery, *resConfig)
+	if err := sf.execRefreshed(id, []string{query}, logger); err != nil {
+		return dataset.Materialization{}, err
+	}
+	return sf.materialization(id, tableName), nil
+}
+
+// DeleteMaterialization drops a materialization's table, which is a dynamic table if Snowflake
+// refreshes it.
+func (sf *snowflakeOfflineStore) DeleteMaterialization(id MaterializationID) error {
+	name, variant, err := ps.MaterializationIDToResource(string(id))
+	if err != nil {
+		return err
+	}
+	tableName, err := sf.sqlOfflineStore.getMaterializationTableName(ResourceID{name, variant, Feature})
+	if err != nil {
+		return err
+	}
+	if exists, err := sf.sqlOfflineStore.materializationExists(id); err != nil {
+		return err
+	} else if !exists {
+		return fferr.NewDatasetNotFoundError(string(id), "", nil)
+	}
+	if _, err := sf.db.Exec(sf.sfQueries.materializationDrop(tableName)); err == nil {
+		return nil
+	}
+	if _, err := sf.db.Exec(sf.sfQueries.dynamicMaterializationDrop(tableName)); err != nil {
+		return fferr.NewDatasetNotFoundError(string(id), "", nil)
+	}
+	return nil
 }
 
 func (sf *snowflakeOfflineStore) UpdateMaterialization(id ResourceID, opts MaterializationOptions) (dataset.Materialization, error) {
+	if opts.ResourceSnowflakeConfig.RefreshedBySnowflake() {
+		tableName, err := ps.ResourceToTableName(FeatureMaterialization.String(), id.Name, id.Variant)
+		if err != nil {
+			return dataset.Materialization{}, err
+		}
+		if err := sf.checkLatestRefresh(id, tableName, opts.ResourceSnowflakeConfig.RefreshStrategy); err != nil {
+			return dataset.Materialization{}, err
+		}
+		return sf.materialization(id, tableName), nil
+	}
 	sf.logger.Errorw("Snowflake Offline Store does not currently support updating materializations", "id", id, "opts", opts)
 	return dataset.Materialization{}, fferr.NewInternalErrorf("Snowflake Offline Store does not currently support updating materializations")
 }
@@ -397,8 +675,15 @@ func (sf snowflakeOfflineStore) Delete(location pl.Location) error {
 		return fferr.NewDatasetLocationNotFoundError(location.Location(), nil)
 	}
 
+	// There's no record here of which transformations Snowflake refreshes with a task, so any
+	// table's task and streams are dropped.
+	if err := sf.dropStreamTask(*sqlLoc, logger); err != nil {
+		return err
+	}
+
 	queries := []string{
 		sf.sfQueries.dropTableQuery(*sqlLoc),
+		sf.sfQueries.dropDynamicTableQuery(*sqlLoc),
 		sf.sfQueries.dropViewQuery(*sqlLoc),
 	}
 
@@ -421,7 +706,7 @@ func (sf snowflakeOfflineStore) Delete(location pl.Location) error {
 		}
 	}
 
-	if dropSuccessful && len(errs) < 2 {
+	if dropSuccessful {
 		logger.Infow("Successfully dropped table", "table", sqlLoc)
 		return nil
 	}
@@ -435,6 +720,72 @@ func (sf snowflakeOfflineStore) Delete(location pl.Location) error {
 	return fferr.NewExecutionError(pt.SnowflakeOffline.String(), fmt.Errorf("failed to drop table due to errors"))
 }
 
+// dropStreamTask drops the task and streams that Snowflake refreshes a transformation with, if it
+// has them. The task goes first, as it reads the streams.
+func (sf snowflakeOfflineStore) dropStreamTask(loc pl.SQLLocation, logger logging.Logger) error {
+	query := sf.sfQueries.dropTaskQuery(loc)
+	logger.Debugw("Dropping stream task", "query", query)
+	if _, err := sf.db.Exec(query); err != nil {
+		logger.Errorw("Failed to drop stream task", "query", query, "error", err)
+		return sf.handleErr(fferr.NewExecutionError(pt.SnowflakeOffline.String(), err), err)
+	}
+	streams, err := sf.listStreams(loc)
+	if err != nil {
+		logger.Errorw("Failed to list streams", "error", err)
+		return err
+	}
+	for _, stream := range streams {
+		query := sf.sfQueries.dropStreamQuery(loc, stream)
+		logger.Debugw("Dropping stream", "query", query)
+		if _, err := sf.db.Exec(query); err != nil {
+			logger.Errorw("Failed to drop stream", "query", query, "error", err)
+			return sf.handleErr(fferr.NewExecutionError(pt.SnowflakeOffline.String(), err), err)
+		}
+	}
+	return nil
+}
+
+// listStreams returns the names of a transformation's streams.
+func (sf snowflakeOfflineStore) listStreams(loc pl.SQLLocation) ([]string, error) {
+	rows, err := sf.db.Query(sf.sfQueries.showStreamsQuery(loc))
+	if err != nil {
+		return nil, sf.handleErr(fferr.NewExecutionError(pt.SnowflakeOffline.String(), err), err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fferr.NewExecutionError(pt.SnowflakeOffline.String(), err)
+	}
+	nameIdx := -1
+	for i, column := range columns {
+		if strings.EqualFold(column, "name") {
+			nameIdx = i
+		}
+	}
+	if nameIdx == -1 {
+		return nil, fferr.NewInternalErrorf("SHOW STREAMS didn't return the streams' names")
+	}
+	tableName := loc.TableLocation().Table
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	var streams []string
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fferr.NewExecutionError(pt.SnowflakeOffline.String(), err)
+		}
+		if name := string(values[nameIdx]); isStreamName(tableName, name) {
+			streams = append(streams, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fferr.NewExecutionError(pt.SnowflakeOffline.String(), err)
+	}
+	return streams, nil
+}
+
 // handleErr attempts to add the Snowflake query and session IDs to a wrapped error to aid
 // in further debugging in the Snowflake UI; note that handleErr will not fail even if the
 // returned error isn't an instance of gosnowflake.SnowflakeError or if the query to get
@@ -480,5 +831,145 @@ func (sf snowflakeOfflineStore) adaptTsDefToBuilderParams(def TrainingSetDef) (t
 		return SanitizeSnowflakeIdentifier(lblLoc.TableLocation()), nil
 	}
 
-	return def.ToBuilderParams(sf.logger, sanitizeTableNameFn)
+	params, err := def.ToBuilderParams(sf.logger, sanitizeTableNameFn)
+	if err != nil {
+		return tsq.BuilderParams{}, err
+	}
+	def.setSnapshotTables(&params, func(table string, snapshot fftypes.DataSnapshot) string {
+		return sf.sfQueries.timeTravelTable(table, snapshot.Timestamp)
+	})
+	return params, nil
+}
+
+// snowflakeMaxRetentionDays is the most days of Time Travel Snowflake keeps for a table.
+const snowflakeMaxRetentionDays = 90
+
+// PinTrainingSetInputs pins the tables a training set reads with Time Travel, extending their
+// retention to cover the policy's. Dynamic training sets can't read Time Travel, so their inputs
+// aren't pinned.
+func (sf *snowflakeOfflineStore) PinTrainingSetInputs(def TrainingSetDef, policy SnapshotPolicy) ([]fftypes.DataSnapshot, error) {
+	logger := sf.logger.WithResource(logging.TrainingSetVariant, def.ID.Name, def.ID.Variant)
+	if def.Type == metadata.DynamicTrainingSet {
+		logger.Info("Dynamic training sets can't read Time Travel; not pinning inputs")
+		return nil, nil
+	}
+	at, err := sf.currentTimestamp()
+	if err != nil {
+		return nil, err
+	}
+	inputs := def.inputs()
+	snapshots := make([]fftypes.DataSnapshot, 0, len(inputs))
+	for _, input := range inputs {
+		tblLoc, err := sf.getValidTableLocation(input.location)
+		if err != nil {
+			return nil, err
+		}
+		if err := sf.extendRetention(tblLoc, policy); err != nil {
+			logger.Errorw("Failed to extend retention of training set input", "table", tblLoc.String(), "error", err)
+			return nil, err
+		}
+		snapshots = append(snapshots, fftypes.DataSnapshot{
+			Resource:  input.resource,
+			Location:  snapshotLocation(input.location),
+			Kind:      fftypes.TimeTravelSnapshot,
+			Timestamp: at,
+		})
+	}
+	logger.Infow("Pinned training set inputs", "timestamp", at)
+	return snapshots, nil
+}
+
+// PinTrainingSet pins a training set's table with Time Travel. A view training set's query reads
+// its pinned inputs, so the view itself is the snapshot.
+func (sf *snowflakeOfflineStore) PinTrainingSet(def TrainingSetDef, policy SnapshotPolicy) (fftypes.DataSnapshot, error) {
+	tableName, err := sf.sqlOfflineStore.getTrainingSetName(def.ID)
+	if err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	at, err := sf.currentTimestamp()
+	if err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	snapshot := fftypes.DataSnapshot{Resource: snapshotResourceName(def.ID), Location: tableName, Timestamp: at, Output: true}
+	if def.Type == metadata.ViewTrainingSet {
+		snapshot.Kind = fftypes.PinnedQuerySnapshot
+		return snapshot, nil
+	}
+	tblLoc, err := sf.getValidTableLocation(pl.NewSQLLocation(tableName))
+	if err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	if err := sf.extendRetention(tblLoc, policy); err != nil {
+		return fftypes.DataSnapshot{}, err
+	}
+	snapshot.Kind = fftypes.TimeTravelSnapshot
+	return snapshot, nil
+}
+
+func (sf *snowflakeOfflineStore) GetTrainingSetSnapshot(id ResourceID, snapshot fftypes.DataSnapshot) (dataset.TrainingSetIterator, error) {
+	if err := checkSnapshotNotReleased(id, snapshot); err != nil {
+		return nil, err
+	}
+	switch snapshot.Kind {
+	case fftypes.PinnedQuerySnapshot:
+		return sf.sqlOfflineStore.GetTrainingSet(id)
+	case fftypes.TimeTravelSnapshot:
+		return sf.sqlOfflineStore.getTrainingSet(id, func(columns string, trainingSetName string) string {
+			return fmt.Sprintf("SELECT %s FROM %s", columns, sf.sfQueries.timeTravelTable(sanitize(trainingSetName), snapshot.Timestamp))
+		})
+	default:
+		return nil, fferr.NewInvalidArgumentErrorf("Snowflake can't serve %s snapshots", snapshot.Kind)
+	}
+}
+
+// ReleaseSnapshots doesn't need to do anything, since Snowflake drops Time Travel data once a
+// table's retention has passed.
+func (sf *snowflakeOfflineStore) ReleaseSnapshots(snapshots []fftypes.DataSnapshot) error {
+	return nil
+}
+
+func (sf *snowflakeOfflineStore) currentTimestamp() (time.Time, error) {
+	var at time.Time
+	if err := sf.db.QueryRow(sf.sfQueries.currentTimestampQuery()).Scan(&at); err != nil {
+		return time.Time{}, fferr.NewExecutionError(pt.SnowflakeOffline.String(), err)
+	}
+	return at, nil
+}
+
+// extendRetention raises a table's Time Travel retention to cover the policy's retention. It never
+// lowers it. Views don't have Time Travel, so they can't be pinned.
+func (sf *snowflakeOfflineStore) extendRetention(tblLoc pl.FullyQualifiedObject, policy SnapshotPolicy) error {
+	days := int(math.Ceil(policy.Retention.Hours() / 24))
+	if days > snowflakeMaxRetentionDays {
+		return fferr.NewInvalidArgumentErrorf("Snowflake keeps at most %d days of Time Travel, but the snapshot retention is %s", snowflakeMaxRetentionDays, policy.Retention)
+	}
+	var tableType, isDynamic, isIceberg string
+	var retention int
+	row := sf.db.QueryRow(sf.sfQueries.tableRetentionQuery(tblLoc))
+	if err := row.Scan(&tableType, &isDynamic, &isIceberg, &retention); err == sql.ErrNoRows {
+		return fferr.NewDatasetLocationNotFoundError(tblLoc.String(), err)
+	} else if err != nil {
+		wrapped := fferr.NewExecutionError(pt.SnowflakeOffline.String(), err)
+		wrapped.AddDetail("table_name", tblLoc.String())
+		return wrapped
+	}
+	if tableType == "VIEW" {
+		return fferr.NewInvalidArgumentErrorf("can't pin %s, since Snowflake views don't have Time Travel", tblLoc.String())
+	}
+	if days <= retention {
+		return nil
+	}
+	kind := "TABLE"
+	if isDynamic == "YES" {
+		kind = "DYNAMIC TABLE"
+	} else if isIceberg == "YES" {
+		kind = "ICEBERG TABLE"
+	}
+	if _, err := sf.db.Exec(sf.sfQueries.retentionAlter(kind, SanitizeSnowflakeIdentifier(tblLoc), days)); err != nil {
+		wrapped := fferr.NewExecutionError(pt.SnowflakeOffline.String(), err)
+		wrapped.AddDetail("table_name", tblLoc.String())
+		return wrapped
+	}
+	sf.logger.Infow("Extended Time Travel retention", "table", tblLoc.String(), "from_days", retention, "to_days", days)
+	return nil
 }
@@ -8,8 +8,10 @@
 package provider
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,6 +23,7 @@ import (
 	"github.com/featureform/metadata"
 	pl "github.com/featureform/provider/location"
 	"github.com/featureform/provider/snowflake"
+	sf "github.com/snowflakedb/gosnowflake"
 )
 
 const CATALOG_CLAUSE = "CATALOG = 'SNOWFLAKE' "
@@ -57,17 +60,17 @@ func (q snowflakeSQLQueries) materializationDrop(tableName string) string {
 	return fmt.Sprintf("DROP TABLE %s", sanitize(tableName))
 }
 
+// dynamicMaterializationDrop drops a materialization that Snowflake refreshes, which DROP TABLE
+// doesn't.
+func (q snowflakeSQLQueries) dynamicMaterializationDrop(tableName string) string {
+	return fmt.Sprintf("DROP DYNAMIC TABLE %s", sanitize(tableName))
+}
+
 func (q snowflakeSQLQueries) dynamicIcebergTableCreate(tableName, query string, config metadata.ResourceSnowflakeConfig) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("CREATE OR REPLACE DYNAMIC ICEBERG TABLE %s ", sanitize(tableName)))
-
-	if config.DynamicTableConfig.TargetLag != "DOWNSTREAM" {
-		sb.WriteString(fmt.Sprintf("TARGET_LAG = '%s' ", config.DynamicTableConfig.TargetLag))
-	} else {
-		sb.WriteString("TARGET_LAG = DOWNSTREAM ")
-	}
-
+	sb.WriteString(targetLagClause(config.DynamicTableConfig.TargetLag))
 	sb.WriteString(fmt.Sprintf("WAREHOUSE = '%s' ", config.Warehouse))
 	sb.WriteString(fmt.Sprintf("EXTERNAL_VOLUME = '%s' ", config.DynamicTableConfig.ExternalVolume))
 	sb.WriteString(CATALOG_CLAUSE)
@@ -79,6 +82,159 @@ func (q snowflakeSQLQueries) dynamicIcebergTableCreate(tableName, query string,
 	return sb.String()
 }
 
+// dynamicTableCreate creates a (non-Iceberg) dynamic table, which doesn't need a catalog.
+func (q snowflakeSQLQueries) dynamicTableCreate(tableName, query string, config metadata.ResourceSnowflakeConfig) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("CREATE OR REPLACE DYNAMIC TABLE %s ", sanitize(tableName)))
+	sb.WriteString(targetLagClause(config.DynamicTableConfig.TargetLag))
+	sb.WriteString(fmt.Sprintf("WAREHOUSE = '%s' ", config.Warehouse))
+	sb.WriteString(fmt.Sprintf("REFRESH_MODE = %s ", config.DynamicTableConfig.RefreshMode))
+	sb.WriteString(fmt.Sprintf("INITIALIZE = %s ", config.DynamicTableConfig.Initialize))
+	sb.WriteString(fmt.Sprintf("AS %s", query))
+
+	return sb.String()
+}
+
+func targetLagClause(targetLag string) string {
+	if targetLag != "DOWNSTREAM" {
+		return fmt.Sprintf("TARGET_LAG = '%s' ", targetLag)
+	}
+	return "TARGET_LAG = DOWNSTREAM "
+}
+
+func (q snowflakeSQLQueries) tableCreateAs(tableName, query string) string {
+	return fmt.Sprintf("CREATE OR REPLACE TABLE %s AS %s", sanitize(tableName), query)
+}
+
+// streamName names the stream of a transformation's ith incremental source.
+func streamName(tableName string, i int) string {
+	return fmt.Sprintf("%s%d", streamNamePrefix(tableName), i)
+}
+
+func streamNamePrefix(tableName string) string {
+	return tableName + "__stream_"
+}
+
+// isStreamName reports whether stream is named like one of a transformation's streams.
+func isStreamName(tableName, stream string) bool {
+	suffix, has := strings.CutPrefix(stream, streamNamePrefix(tableName))
+	if !has {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// streamTaskName names the task that merges a transformation's streams into its table.
+func streamTaskName(tableName string) string {
+	return fmt.Sprintf("%s__task", tableName)
+}
+
+// streamCreate creates an append only stream of the rows added to a source since at, as
+// incremental transformations only read the rows added to their sources.
+func (q snowflakeSQLQueries) streamCreate(stream, sourceTable string, at time.Time) string {
+	return fmt.Sprintf("CREATE OR REPLACE STREAM %s ON TABLE %s APPEND_ONLY = TRUE", sanitize(stream), q.timeTravelTable(sourceTable, at))
+}
+
+// streamTaskCreate creates a task that runs every schedule while any of streams has data, and
+// merges the result of query into the table. query must read the streams rather than the sources,
+// so that running it consumes them.
+func (q snowflakeSQLQueries) streamTaskCreate(task, tableName, query string, streams []string, columns []TableColumn, mergeKeys []string, config metadata.ResourceSnowflakeConfig, schedule string) string {
+	hasData := make([]string, len(streams))
+	for i, stream := range streams {
+		hasData[i] = fmt.Sprintf("SYSTEM$STREAM_HAS_DATA('%s')", sanitize(stream))
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("CREATE OR REPLACE TASK %s ", sanitize(task)))
+	sb.WriteString(fmt.Sprintf("WAREHOUSE = %s ", config.Warehouse))
+	sb.WriteString(fmt.Sprintf("SCHEDULE = '%s' ", schedule))
+	sb.WriteString(fmt.Sprintf("WHEN %s ", strings.Join(hasData, " OR ")))
+	if len(mergeKeys) > 0 {
+		sb.WriteString(fmt.Sprintf("AS %s", q.incrementalMerge(tableName, query, columns, mergeKeys)))
+	} else {
+		names := make([]string, len(columns))
+		for i, column := range columns {
+			names[i] = sanitize(column.Name)
+		}
+		cols := strings.Join(names, ", ")
+		sb.WriteString(fmt.Sprintf("AS INSERT INTO %s (%s) SELECT %s FROM ( %s ) incremental", sanitize(tableName), cols, cols, query))
+	}
+	return sb.String()
+}
+
+func (q snowflakeSQLQueries) taskResume(task string) string {
+	return fmt.Sprintf("ALTER TASK %s RESUME", sanitize(task))
+}
+
+// streamTaskSchedule converts a target lag, such as "90 seconds" or "2 hours", to a task
+// schedule in minutes. Tasks can't be scheduled more often than once a minute.
+func streamTaskSchedule(targetLag string) (string, error) {
+	parts := strings.Fields(targetLag)
+	if len(parts) != 2 {
+		return "", fferr.NewInvalidArgumentErrorf("can't schedule a Snowflake task with target lag %q", targetLag)
+	}
+	value, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", fferr.NewInvalidArgumentErrorf("can't schedule a Snowflake task with target lag %q", targetLag)
+	}
+	var minutes int
+	switch strings.ToLower(parts[1]) {
+	case "seconds":
+		minutes = value / 60
+	case "minutes":
+		minutes = value
+	case "hours":
+		minutes = value * 60
+	case "days":
+		minutes = value * 60 * 24
+	default:
+		return "", fferr.NewInvalidArgumentErrorf("can't schedule a Snowflake task with target lag %q", targetLag)
+	}
+	return fmt.Sprintf("%d MINUTE", max(minutes, 1)), nil
+}
+
+// latestRefreshQuery selects the state, message and completion time of the latest refresh of a
+// table Snowflake keeps up to date, using the dynamic table's or task's refresh history.
+func (q snowflakeSQLQueries) latestRefreshQuery(tableName string, strategy metadata.SnowflakeRefreshStrategy) string {
+	if strategy == metadata.StreamRefresh {
+		return fmt.Sprintf(
+			"SELECT STATE, COALESCE(ERROR_MESSAGE, ''), COMPLETED_TIME FROM TABLE(INFORMATION_SCHEMA.TASK_HISTORY(TASK_NAME => '%s')) ORDER BY SCHEDULED_TIME DESC LIMIT 1",
+			strings.ReplaceAll(streamTaskName(tableName), "'", "''"),
+		)
+	}
+	return fmt.Sprintf(
+		"SELECT STATE, COALESCE(STATE_MESSAGE, ''), REFRESH_END_TIME FROM TABLE(INFORMATION_SCHEMA.DYNAMIC_TABLE_REFRESH_HISTORY(NAME => '%s')) ORDER BY DATA_TIMESTAMP DESC LIMIT 1",
+		strings.ReplaceAll(sanitize(tableName), "'", "''"),
+	)
+}
+
+// timeTravelTable reads a table as of a time with Snowflake's Time Travel.
+func (q snowflakeSQLQueries) timeTravelTable(table string, at time.Time) string {
+	return fmt.Sprintf("%s AT(TIMESTAMP => '%s'::TIMESTAMP_TZ)", table, at.Format("2006-01-02 15:04:05.999999999 -07:00"))
+}
+
+func (q snowflakeSQLQueries) currentTimestampQuery() string {
+	return "SELECT CURRENT_TIMESTAMP()"
+}
+
+// tableRetentionQuery selects the type of a table, whether it's dynamic or Iceberg, and the number
+// of days of Time Travel that Snowflake keeps for it.
+func (q snowflakeSQLQueries) tableRetentionQuery(obj pl.FullyQualifiedObject) string {
+	return fmt.Sprintf(
+		"SELECT TABLE_TYPE, IS_DYNAMIC, IS_ICEBERG, RETENTION_TIME FROM %s.INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = '%s' AND TABLE_NAME = '%s'",
+		db.Identifier{obj.Database}.Sanitize(),
+		strings.ReplaceAll(obj.Schema, "'", "''"),
+		strings.ReplaceAll(obj.Table, "'", "''"),
+	)
+}
+
+// retentionAlter sets the number of days of Time Travel that Snowflake keeps for a table. kind is
+// the kind of table, e.g. "DYNAMIC TABLE", as ALTER needs it.
+func (q snowflakeSQLQueries) retentionAlter(kind, table string, days int) string {
+	return fmt.Sprintf("ALTER %s %s SET DATA_RETENTION_TIME_IN_DAYS = %d", kind, table, days)
+}
+
 func (q snowflakeSQLQueries) staticIcebergTableCreate(tableName, query string, config metadata.ResourceSnowflakeConfig) string {
 	var sb strings.Builder
 
@@ -104,6 +260,49 @@ func (q snowflakeSQLQueries) viewCreate(tableName, query string) string {
 	return sb.String()
 }
 
+// transformationIncrementalUpdate uses MERGE when there are merge keys, so the matching rows are
+// updated in place rather than deleted and reinserted.
+func (q snowflakeSQLQueries) transformationIncrementalUpdate(db *sql.DB, tableName string, query string, config IncrementalConfig) error {
+	if len(config.MergeKeys) == 0 {
+		return q.defaultOfflineSQLQueries.transformationIncrementalUpdate(db, tableName, query, config)
+	}
+	columns, err := q.getColumns(db, tableName)
+	if err != nil {
+		return err
+	}
+	statements := make([]string, 0)
+	if config.IsBackfill() {
+		statements = append(statements, incrementalBackfillDelete(sanitize(tableName), config))
+	}
+	statements = append(statements, q.incrementalMerge(tableName, query, columns, config.MergeKeys))
+	transaction := fmt.Sprintf("BEGIN TRANSACTION;%s;COMMIT;", strings.Join(statements, ";"))
+	stmt, _ := sf.WithMultiStatement(context.TODO(), len(statements)+2)
+	if _, err := db.QueryContext(stmt, transaction); err != nil {
+		wrapped := fferr.NewExecutionError("Snowflake", err)
+		wrapped.AddDetail("table_name", tableName)
+		return wrapped
+	}
+	return nil
+}
+
+func (q snowflakeSQLQueries) incrementalMerge(tableName, query string, columns []TableColumn, mergeKeys []string) string {
+	updates := make([]string, len(columns))
+	names := make([]string, len(columns))
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		name := sanitize(column.Name)
+		updates[i] = fmt.Sprintf("target.%s = incremental.%s", name, name)
+		names[i] = name
+		values[i] = fmt.Sprintf("incremental.%s", name)
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("MERGE INTO %s target USING ( %s ) incremental ", sanitize(tableName), query))
+	sb.WriteString(fmt.Sprintf("ON %s ", incrementalMergeKeyMatch("target", "incremental", mergeKeys)))
+	sb.WriteString(fmt.Sprintf("WHEN MATCHED THEN UPDATE SET %s ", strings.Join(updates, ", ")))
+	sb.WriteString(fmt.Sprintf("WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", strings.Join(names, ", "), strings.Join(values, ", ")))
+	return sb.String()
+}
+
 func (q snowflakeSQLQueries) materializationCreateAsQuery(entity, value, ts, tableName string) string {
 	var sb strings.Builder
 
@@ -130,6 +329,38 @@ func (q snowflakeSQLQueries) dropViewQuery(loc pl.SQLLocation) string {
 	return fmt.Sprintf("DROP VIEW %s", SanitizeSnowflakeIdentifier(loc.TableLocation()))
 }
 
+func (q snowflakeSQLQueries) dropDynamicTableQuery(loc pl.SQLLocation) string {
+	return fmt.Sprintf("DROP DYNAMIC TABLE %s", SanitizeSnowflakeIdentifier(loc.TableLocation()))
+}
+
+// dropTaskQuery drops the task that merges a transformation's streams into its table, if it has one.
+func (q snowflakeSQLQueries) dropTaskQuery(loc pl.SQLLocation) string {
+	obj := loc.TableLocation()
+	obj.Table = streamTaskName(obj.Table)
+	return fmt.Sprintf("DROP TASK IF EXISTS %s", SanitizeSnowflakeIdentifier(obj))
+}
+
+func (q snowflakeSQLQueries) dropStreamQuery(loc pl.SQLLocation, stream string) string {
+	obj := loc.TableLocation()
+	obj.Table = stream
+	return fmt.Sprintf("DROP STREAM IF EXISTS %s", SanitizeSnowflakeIdentifier(obj))
+}
+
+// showStreamsQuery lists the streams whose names start like those of a transformation's streams.
+// Underscores are wildcards in the pattern, so the names still need to be checked.
+func (q snowflakeSQLQueries) showStreamsQuery(loc pl.SQLLocation) string {
+	obj := loc.TableLocation()
+	query := fmt.Sprintf("SHOW STREAMS LIKE '%s%%'", strings.ReplaceAll(streamNamePrefix(obj.Table), "'", "''"))
+	if obj.Schema == "" {
+		return query
+	}
+	schema := db.Identifier{obj.Schema}
+	if obj.Database != "" {
+		schema = db.Identifier{obj.Database, obj.Schema}
+	}
+	return fmt.Sprintf("%s IN SCHEMA %s", query, schema.Sanitize())
+}
+
 func SanitizeSnowflakeIdentifier(obj pl.FullyQualifiedObject) string {
 	ident := db.Identifier{}
 
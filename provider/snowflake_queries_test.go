@@ -9,6 +9,7 @@ package provider
 
 import (
 	"testing"
+	"time"
 
 	"github.com/featureform/metadata"
 )
@@ -78,3 +79,110 @@ func TestSnowflakeDynamicIcebergTableQuery(t *testing.T) {
 	}
 
 }
+
+func TestSnowflakeDynamicTableQuery(t *testing.T) {
+	config := metadata.ResourceSnowflakeConfig{
+		DynamicTableConfig: &metadata.SnowflakeDynamicTableConfig{
+			TargetLag:   "DOWNSTREAM",
+			RefreshMode: metadata.IncrementalRefresh,
+			Initialize:  metadata.InitializeOnSchedule,
+		},
+		Warehouse:       "my_warehouse",
+		RefreshStrategy: metadata.DynamicTableRefresh,
+	}
+	expected := "CREATE OR REPLACE DYNAMIC TABLE \"test_table\" TARGET_LAG = DOWNSTREAM WAREHOUSE = 'my_warehouse' REFRESH_MODE = INCREMENTAL INITIALIZE = ON_SCHEDULE AS SELECT * FROM raw_table"
+	actual := snowflakeSQLQueries{}.dynamicTableCreate("test_table", "SELECT * FROM raw_table", config)
+	if actual != expected {
+		t.Errorf("Expected %v, but instead found %v", expected, actual)
+	}
+}
+
+func TestSnowflakeStreamTaskQuery(t *testing.T) {
+	config := metadata.ResourceSnowflakeConfig{Warehouse: "my_warehouse"}
+	columns := []TableColumn{{Name: "id"}, {Name: "amount"}}
+	streams := []string{streamName("test_table", 0), streamName("test_table", 1)}
+	tests := []struct {
+		name      string
+		mergeKeys []string
+		expected  string
+	}{
+		{
+			name:      "Insert",
+			mergeKeys: nil,
+			expected:  `CREATE OR REPLACE TASK "test_table__task" WAREHOUSE = my_warehouse SCHEDULE = '5 MINUTE' WHEN SYSTEM$STREAM_HAS_DATA('"test_table__stream_0"') OR SYSTEM$STREAM_HAS_DATA('"test_table__stream_1"') AS INSERT INTO "test_table" ("id", "amount") SELECT "id", "amount" FROM ( SELECT * FROM s ) incremental`,
+		},
+		{
+			name:      "Merge",
+			mergeKeys: []string{"id"},
+			expected:  `CREATE OR REPLACE TASK "test_table__task" WAREHOUSE = my_warehouse SCHEDULE = '5 MINUTE' WHEN SYSTEM$STREAM_HAS_DATA('"test_table__stream_0"') OR SYSTEM$STREAM_HAS_DATA('"test_table__stream_1"') AS ` + snowflakeSQLQueries{}.incrementalMerge("test_table", "SELECT * FROM s", columns, []string{"id"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := snowflakeSQLQueries{}.streamTaskCreate(streamTaskName("test_table"), "test_table", "SELECT * FROM s", streams, columns, tt.mergeKeys, config, "5 MINUTE")
+			if actual != tt.expected {
+				t.Errorf("Expected %v, but instead found %v", tt.expected, actual)
+			}
+		})
+	}
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	expectedStream := `CREATE OR REPLACE STREAM "test_table__stream_0" ON TABLE "raw" AT(TIMESTAMP => '2025-03-01 12:00:00 +00:00'::TIMESTAMP_TZ) APPEND_ONLY = TRUE`
+	if actual := (snowflakeSQLQueries{}).streamCreate(streams[0], `"raw"`, at); actual != expectedStream {
+		t.Errorf("Expected %v, but instead found %v", expectedStream, actual)
+	}
+}
+
+func TestSnowflakeStreamTaskSchedule(t *testing.T) {
+	tests := []struct {
+		targetLag string
+		expected  string
+		err       bool
+	}{
+		{"30 seconds", "1 MINUTE", false},
+		{"150 seconds", "2 MINUTE", false},
+		{"15 minutes", "15 MINUTE", false},
+		{"2 hours", "120 MINUTE", false},
+		{"1 days", "1440 MINUTE", false},
+		{"DOWNSTREAM", "", true},
+		{"five minutes", "", true},
+		{"5 weeks", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.targetLag, func(t *testing.T) {
+			actual, err := streamTaskSchedule(tt.targetLag)
+			if (err != nil) != tt.err {
+				t.Fatalf("Expected error %v, but instead found %v", tt.err, err)
+			}
+			if actual != tt.expected {
+				t.Errorf("Expected %v, but instead found %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSnowflakeLatestRefreshQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy metadata.SnowflakeRefreshStrategy
+		expected string
+	}{
+		{
+			name:     "Dynamic Table",
+			strategy: metadata.DynamicTableRefresh,
+			expected: `SELECT STATE, COALESCE(STATE_MESSAGE, ''), REFRESH_END_TIME FROM TABLE(INFORMATION_SCHEMA.DYNAMIC_TABLE_REFRESH_HISTORY(NAME => '"test_table"')) ORDER BY DATA_TIMESTAMP DESC LIMIT 1`,
+		},
+		{
+			name:     "Stream",
+			strategy: metadata.StreamRefresh,
+			expected: `SELECT STATE, COALESCE(ERROR_MESSAGE, ''), COMPLETED_TIME FROM TABLE(INFORMATION_SCHEMA.TASK_HISTORY(TASK_NAME => 'test_table__task')) ORDER BY SCHEDULED_TIME DESC LIMIT 1`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := snowflakeSQLQueries{}.latestRefreshQuery("test_table", tt.strategy)
+			if actual != tt.expected {
+				t.Errorf("Expected %v, but instead found %v", tt.expected, actual)
+			}
+		})
+	}
+}
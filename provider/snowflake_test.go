@@ -14,15 +14,18 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 
 	"github.com/featureform/fferr"
 	types "github.com/featureform/fftypes"
+	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
 	"github.com/featureform/provider/dataset"
 	"github.com/featureform/provider/location"
@@ -855,3 +858,113 @@ func getConfiguredSnowflakeTester(t *testing.T) OfflineSqlTest {
 		},
 	}
 }
+
+func newSnowflakeMockStore(t *testing.T) (*snowflakeOfflineStore, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	queries := &snowflakeSQLQueries{}
+	logger := logging.NewTestLogger(t)
+	store := &sqlOfflineStore{
+		db:     db,
+		query:  queries,
+		getDb:  func(database, schema string) (*sql.DB, error) { return db, nil },
+		logger: logger,
+	}
+	return &snowflakeOfflineStore{sqlOfflineStore: store, logger: logger, sfQueries: queries}, mock
+}
+
+func TestSnowflakeStreamedTransformationStreamsAfterLoad(t *testing.T) {
+	sf, mock := newSnowflakeMockStore(t)
+	q := sf.sfQueries
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	config := TransformationConfig{
+		TargetTableID: ResourceID{Name: "totals", Variant: "v1", Type: Transformation},
+		Incremental: &IncrementalConfig{
+			StreamSources:  []StreamSource{{Key: "events.v1", Table: `"events"`}},
+			StreamTemplate: `SELECT e.id, 'events' AS kind FROM {{ events.v1 }} e JOIN "users" u ON e.id = u.id`,
+		},
+	}
+	resConfig := metadata.ResourceSnowflakeConfig{
+		Warehouse:          "wh",
+		DynamicTableConfig: &metadata.SnowflakeDynamicTableConfig{TargetLag: "5 minutes"},
+	}
+	stream := streamName("totals_table", 0)
+	loadQuery := fmt.Sprintf(`SELECT e.id, 'events' AS kind FROM %s e JOIN "users" u ON e.id = u.id`, q.timeTravelTable(`"events"`, at))
+	streamQuery := `SELECT e.id, 'events' AS kind FROM "totals_table__stream_0" e JOIN "users" u ON e.id = u.id`
+	columns := []TableColumn{{Name: "id"}, {Name: "kind"}}
+
+	// The load and the streams read the sources as of the same time, and the streams come second.
+	mock.ExpectQuery(regexp.QuoteMeta(q.currentTimestampQuery())).WillReturnRows(sqlmock.NewRows([]string{"ts"}).AddRow(at))
+	mock.ExpectExec(regexp.QuoteMeta(q.tableCreateAs("totals_table", loadQuery))).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(q.streamCreate(stream, `"events"`, at))).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT column_name FROM information_schema.columns").WithArgs("totals_table").
+		WillReturnRows(sqlmock.NewRows([]string{"column_name"}).AddRow("id").AddRow("kind"))
+	mock.ExpectExec(regexp.QuoteMeta(q.streamTaskCreate(streamTaskName("totals_table"), "totals_table", streamQuery, []string{stream}, columns, nil, resConfig, "5 MINUTE"))).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(q.taskResume(streamTaskName("totals_table")))).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := sf.createStreamedTransformation("totals_table", config, resConfig, sf.logger); err != nil {
+		t.Fatalf("Failed to create streamed transformation: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+
+	if _, err := fillStreamTemplate("SELECT * FROM {{ users.v1 }}", map[string]string{"events.v1": `"events"`}); err == nil {
+		t.Fatalf("Expected an error filling in a placeholder that isn't a stream source")
+	}
+}
+
+func TestSnowflakeDeleteDropsStreamTaskAndDynamicTable(t *testing.T) {
+	sf, mock := newSnowflakeMockStore(t)
+	q := sf.sfQueries
+	loc := pl.NewSQLLocationFromParts("db", "schema", "totals_table")
+	createdOn := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(q.viewExists())).WithArgs("totals_table").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(q.tableExists())).WithArgs("totals_table").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`DROP TASK IF EXISTS "db"."schema"."totals_table__task"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	// Underscores are wildcards in SHOW's pattern, so it can return other tables' streams.
+	mock.ExpectQuery(regexp.QuoteMeta(`SHOW STREAMS LIKE 'totals_table__stream_%' IN SCHEMA "db"."schema"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"created_on", "name", "database_name"}).
+			AddRow(createdOn, "totals_table__stream_0", "db").
+			AddRow(createdOn, "totals_tableX__stream_0", "db").
+			AddRow(createdOn, "totals_table__stream_0_backup", "db").
+			AddRow(createdOn, "totals_table__stream_1", "db"))
+	mock.ExpectExec(regexp.QuoteMeta(`DROP STREAM IF EXISTS "db"."schema"."totals_table__stream_0"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DROP STREAM IF EXISTS "db"."schema"."totals_table__stream_1"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	// DROP TABLE fails on a dynamic table.
+	mock.ExpectExec(regexp.QuoteMeta(`DROP TABLE "db"."schema"."totals_table"`)).WillReturnError(fmt.Errorf("object is a dynamic table"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT CURRENT_SESSION()")).WillReturnRows(sqlmock.NewRows([]string{"session"}).AddRow("1"))
+	mock.ExpectExec(regexp.QuoteMeta(`DROP DYNAMIC TABLE "db"."schema"."totals_table"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := sf.Delete(loc); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
+
+func TestSnowflakeDeleteDynamicMaterialization(t *testing.T) {
+	sf, mock := newSnowflakeMockStore(t)
+	q := sf.sfQueries
+	id := MaterializationID("amount__v1")
+	tableName, err := ps.ResourceToTableName(FeatureMaterialization.String(), "amount", "v1")
+	if err != nil {
+		t.Fatalf("Failed to get table name: %v", err)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(q.materializationExists())).WithArgs(tableName).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow(tableName))
+	mock.ExpectExec(regexp.QuoteMeta(q.materializationDrop(tableName))).WillReturnError(fmt.Errorf("object is a dynamic table"))
+	mock.ExpectExec(regexp.QuoteMeta(q.dynamicMaterializationDrop(tableName))).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := sf.DeleteMaterialization(id); err != nil {
+		t.Fatalf("Failed to delete materialization: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Unmet expectations: %v", err)
+	}
+}
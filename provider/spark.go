@@ -18,11 +18,11 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/glue"
 	"github.com/google/uuid"
-	"github.com/pkg/errors"
 	"golang.org/x/exp/slices"
 
 	"github.com/featureform/config"
 	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
 	"github.com/featureform/filestore"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
@@ -207,6 +207,9 @@ func (q defaultPythonOfflineQueries) trainingSetCreate(
 	}
 	columnStr := strings.Join(columns, ", ")
 	joinQueryString := strings.Join(joinQueries, " ")
+	if def.Spine != nil {
+		return q.spineTrainingSetCreate(labelSchema, columnStr, joinQueryString, feature_timestamps)
+	}
 	var labelWindowQuery string
 	if labelSchema.EntityMappings.TimestampColumn == "" {
 		labelWindowQuery = fmt.Sprintf(
@@ -247,12 +250,49 @@ func (q defaultPythonOfflineQueries) trainingSetCreate(
 	return finalQuery
 }
 
+// spineTrainingSetCreate joins the features onto the spine's entity and timestamp columns. Unlike
+// a labeled training set, only the feature columns are selected.
+func (q defaultPythonOfflineQueries) spineTrainingSetCreate(
+	spineSchema ResourceSchema,
+	columnStr string,
+	joinQueryString string,
+	featureTimestamps []string,
+) string {
+	spineTS := "CAST(0 AS TIMESTAMP)"
+	if spineSchema.EntityMappings.TimestampColumn != "" {
+		spineTS = spineSchema.EntityMappings.TimestampColumn
+	}
+	// The row ID keeps duplicate spine rows, which are expected when scoring the same entity more than once.
+	spineWindowQuery := fmt.Sprintf(
+		"SELECT %s AS entity, %s AS label_ts, monotonically_increasing_id() AS spine_row_id FROM source_0",
+		spineSchema.EntityMappings.Mappings[0].EntityColumn,
+		spineTS,
+	)
+	spineJoinQuery := fmt.Sprintf("(%s) t0 %s", spineWindowQuery, joinQueryString)
+	timeStamps := strings.Join(featureTimestamps, ", ")
+	timeStampsDesc := strings.Join(featureTimestamps, " DESC,")
+	fullQuery := fmt.Sprintf(
+		"SELECT %s, entity, label_ts, spine_row_id, %s, ROW_NUMBER() over (PARTITION BY spine_row_id ORDER BY %s DESC) as row_number FROM %s",
+		columnStr,
+		timeStamps,
+		timeStampsDesc,
+		spineJoinQuery,
+	)
+	return fmt.Sprintf(
+		"SELECT %s FROM (SELECT * FROM (%s) WHERE row_number=1) ORDER BY label_ts",
+		columnStr,
+		fullQuery,
+	)
+}
+
 type SparkOfflineStore struct {
 	Executor   SparkExecutor
 	Store      SparkFileStore
 	GlueConfig *pc.GlueConfig
 	Logger     logging.Logger
 	query      *defaultPythonOfflineQueries
+	// tableFormat is set when resources are written to the file store as Delta or Iceberg tables.
+	tableFormat pc.TableFormat
 	BaseProvider
 }
 
@@ -284,14 +324,12 @@ func (store *SparkOfflineStore) GetBatchFeatures(ids []ResourceID) (BatchFeature
 		}
 	}
 	// Convert materialization ID to file paths
-	materializationPaths, err := store.createFilePathsFromIDs(legacyStore, materializationIDs)
+	sources, err := store.createFilePathsFromIDs(legacyStore, materializationIDs)
 	if err != nil {
 		logger.Errorw("Failed to create file path for IDs", "err", err)
 		return nil, err
 	}
 
-	sources := sparklib.WrapLegacySourceInfos(materializationPaths)
-
 	// Create a query that selects all features from the table
 	query := createJoinQuery(len(ids))
 
@@ -352,13 +390,26 @@ func (store *SparkOfflineStore) GetBatchFeatures(ids []ResourceID) (BatchFeature
 	return &FileStoreBatchServing{store: legacyStore, iter: iterator, numFeatures: len(ids)}, nil
 }
 
-func (store *SparkOfflineStore) createFilePathsFromIDs(legacyStore SparkFileStore, materializationIDs []ResourceID) ([]string, error) {
-	materializationPaths := make([]string, len(materializationIDs))
+func (store *SparkOfflineStore) createFilePathsFromIDs(legacyStore SparkFileStore, materializationIDs []ResourceID) ([]sparklib.SourceInfo, error) {
+	materializationSources := make([]sparklib.SourceInfo, len(materializationIDs))
 	for i, id := range materializationIDs {
 		path, err := store.Store.CreateFilePath(id.ToFilestorePath(), true)
 		if err != nil {
 			return nil, err
 		}
+		tableFormat, err := DetectFileStoreTableFormat(legacyStore, path)
+		if err != nil {
+			return nil, err
+		}
+		if tableFormat.IsTableFormat() {
+			materializationSources[i] = sparklib.SourceInfo{
+				Location:     path.ToURI(),
+				LocationType: string(pl.FileStoreLocationType),
+				Provider:     pt.Type(store.Store.Type()),
+				TableFormat:  string(tableFormat),
+			}
+			continue
+		}
 		sourceFiles, err := legacyStore.List(path, filestore.Parquet)
 		if err != nil {
 			return nil, err
@@ -375,18 +426,13 @@ func (store *SparkOfflineStore) createFilePathsFromIDs(legacyStore SparkFileStor
 		if err != nil {
 			return nil, err
 		}
-		source := sparklib.SourceInfo{
+		materializationSources[i] = sparklib.SourceInfo{
 			Location:     matDir.ToURI(),
 			LocationType: string(pl.FileStoreLocationType),
 			Provider:     pt.Type(store.Store.Type()),
 		}
-		jsonSource, err := source.Serialize()
-		if err != nil {
-			return nil, err
-		}
-		materializationPaths[i] = jsonSource
 	}
-	return materializationPaths, nil
+	return materializationSources, nil
 }
 
 func createJoinQuery(numFeatures int) string {
@@ -597,6 +643,9 @@ func sparkOfflineStoreFactory(config pc.SerializedConfig) (Provider, error) {
 			ProviderConfig: config,
 		},
 	}
+	if !sc.UsesCatalog() {
+		sparkOfflineStore.tableFormat = sc.FileStoreTableFormat
+	}
 	return &sparkOfflineStore, nil
 }
 
@@ -651,6 +700,12 @@ func NewSparkExecutor(
 			return nil, fferr.NewInternalError(fmt.Errorf("cannot convert config into 'SparkGenericConfig'"))
 		}
 		return NewSparkGenericExecutor(*sparkGenericConfig, logger)
+	case pc.SparkKubernetes:
+		sparkKubernetesConfig, ok := config.(*pc.SparkKubernetesConfig)
+		if !ok {
+			return nil, fferr.NewInternalError(fmt.Errorf("cannot convert config into 'SparkKubernetesConfig'"))
+		}
+		return NewSparkKubernetesExecutor(*sparkKubernetesConfig, logger)
 	default:
 		return nil, fferr.NewInvalidArgumentErrorf("the executor type ('%s') is not supported", execType)
 	}
@@ -682,6 +737,20 @@ func (spark *SparkOfflineStore) RegisterResourceFromSourceTable(id ResourceID, s
 	return blobRegisterResourceFromSourceTable(id, schema, spark.Logger.SugaredLogger, spark.Store)
 }
 
+// sparkJobCanceller is implemented by Spark executors that can stop a job by its resume ID.
+type sparkJobCanceller interface {
+	Cancel(resumeID types.ResumeID) error
+}
+
+// CancelTransformation stops the Spark job behind resumeID, if the executor can cancel jobs.
+func (spark *SparkOfflineStore) CancelTransformation(resumeID types.ResumeID) error {
+	canceller, ok := spark.Executor.(sparkJobCanceller)
+	if !ok {
+		return fferr.NewUnimplementedErrorf("%T can't cancel Spark jobs", spark.Executor)
+	}
+	return canceller.Cancel(resumeID)
+}
+
 func (spark *SparkOfflineStore) SupportsTransformationOption(opt TransformationOptionType) (bool, error) {
 	spark.Logger.Debugw("Checking if Spark supports option", "type", opt)
 	if supports, err := spark.Executor.SupportsTransformationOption(opt); err != nil {
@@ -760,6 +829,14 @@ func (spark *SparkOfflineStore) sqlTransformation(config TransformationConfig, i
 		return fferr.NewDatasetNotFoundError(config.TargetTableID.Name, config.TargetTableID.Variant, fmt.Errorf(outputLocation.Location()))
 	}
 
+	var incremental *sparklib.IncrementalFlag
+	if config.Incremental != nil && isUpdate {
+		if incremental, err = spark.incrementalFlag(config); err != nil {
+			logger.Errorw("Could not create incremental flag for spark transformation", "error", err)
+			return err
+		}
+	}
+
 	logger.Debugw("Running SQL transformation")
 	sparkArgs, err := sparkScriptCommandDef{
 		DeployMode:     getSparkDeployModeFromEnv(),
@@ -770,6 +847,7 @@ func (spark *SparkOfflineStore) sqlTransformation(config TransformationConfig, i
 		JobType:        types.Transform,
 		Store:          spark.Store,
 		Mappings:       config.SourceMapping,
+		Incremental:    incremental,
 	}.PrepareCommand(logger)
 	logger = logger.With("args", sparkArgs.Redacted())
 	if err != nil {
@@ -794,6 +872,28 @@ func (spark *SparkOfflineStore) sqlTransformation(config TransformationConfig, i
 	return nil
 }
 
+// incrementalFlag points the script at the last output, which it merges the new rows into. Catalog
+// tables are merged into in place, so they don't need it.
+func (spark *SparkOfflineStore) incrementalFlag(config TransformationConfig) (*sparklib.IncrementalFlag, error) {
+	if err := config.Incremental.check(); err != nil {
+		return nil, err
+	}
+	flag := &sparklib.IncrementalFlag{
+		MergeKeys:       config.Incremental.MergeKeys,
+		TimestampColumn: config.Incremental.TimestampColumn,
+		BackfillStart:   config.Incremental.BackfillStart,
+		BackfillEnd:     config.Incremental.BackfillEnd,
+	}
+	if !spark.UsesCatalog() {
+		previous, err := spark.ResourceLocation(config.TargetTableID, nil)
+		if err != nil {
+			return nil, err
+		}
+		flag.Previous = previous.Location()
+	}
+	return flag, nil
+}
+
 func (spark *SparkOfflineStore) dfTransformation(config TransformationConfig, isUpdate bool, tfOpts TransformationOptions) error {
 	logger := spark.Logger.With(
 		"type",
@@ -914,7 +1014,7 @@ func (spark *SparkOfflineStore) outputLocation(targetTableID ResourceID) (pl.Loc
 		if err != nil {
 			return nil, err
 		}
-		return pl.NewFileLocation(fp), nil
+		return spark.fileOutputLocation(fp), nil
 	}
 	tableName, err := ps.ResourceToCatalogTableName(targetTableID.Type.String(), targetTableID.Name, targetTableID.Variant)
 	if err != nil {
@@ -923,6 +1023,26 @@ func (spark *SparkOfflineStore) outputLocation(targetTableID ResourceID) (pl.Loc
 	return pl.NewCatalogLocation(spark.GlueConfig.Database, tableName, string(spark.GlueConfig.TableFormat)), nil
 }
 
+// fileOutputLocation is the location a job writes to under dir; a table when the store has a table format.
+func (spark *SparkOfflineStore) fileOutputLocation(dir filestore.Filepath) pl.Location {
+	if spark.tableFormat != "" {
+		return pl.NewFileTableLocation(dir, string(spark.tableFormat))
+	}
+	return pl.NewFileLocation(dir)
+}
+
+// locationTableFormat returns the table format of catalog tables and file store tables, or an
+// empty string for plain files.
+func locationTableFormat(location pl.Location) string {
+	switch lt := location.(type) {
+	case *pl.CatalogLocation:
+		return lt.TableFormat()
+	case *pl.FileStoreLocation:
+		return lt.TableFormat()
+	}
+	return ""
+}
+
 func createSourceInfo(mapping []SourceMapping, logger logging.Logger) ([]sparklib.SourceInfo, error) {
 	sources := make([]sparklib.SourceInfo, 0)
 
@@ -944,6 +1064,7 @@ func createSourceInfo(mapping []SourceMapping, logger logging.Logger) ([]sparkli
 				source = sparklib.SourceInfo{
 					Location:     lt.Location(),
 					LocationType: string(lt.Type()),
+					TableFormat:  lt.TableFormat(),
 				}
 			case *pl.CatalogLocation:
 				source = sparklib.SourceInfo{
@@ -1035,6 +1156,7 @@ func (spark *SparkOfflineStore) prepareQueryForSpark(query string, mapping []Sou
 				source = sparklib.SourceInfo{
 					Location:     lt.Location(),
 					LocationType: string(lt.Type()),
+					TableFormat:  lt.TableFormat(),
 				}
 			case *pl.CatalogLocation:
 				source = sparklib.SourceInfo{
@@ -1133,21 +1255,7 @@ func (spark *SparkOfflineStore) ResourceLocation(id ResourceID, resource any) (p
 		return pl.NewCatalogLocation(spark.GlueConfig.Database, table, string(spark.GlueConfig.TableFormat)), nil
 	}
 
-	path, err := spark.Store.CreateFilePath(id.ToFilestorePath(), true)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not create dir path")
-	}
-
-	newestFile, err := spark.Store.NewestFileOfType(path, filestore.Parquet)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not get newest file")
-	}
-
-	newestFileDirPathDateTime, err := spark.Store.CreateFilePath(newestFile.KeyPrefix(), true)
-	if err != nil {
-		return nil, fmt.Errorf("could not create directory path for spark newestFile: %v", err)
-	}
-	return pl.NewFileLocation(newestFileDirPathDateTime), nil
+	return fileStoreResourceLocation(spark.Store, id)
 }
 
 // TODO: Currently, GetTransformationTable is only used in the context of serving source data as an iterator,
@@ -1299,10 +1407,7 @@ func blobSparkMaterialization(
 		spark.Logger.Errorw("Could not convert resource table to blob offline table", "id", id)
 		return nil, fferr.NewInternalErrorf("could not convert offline table with id %v to sparkResourceTable", id)
 	}
-	var tableFormat string
-	if sparkResourceTable.schema.SourceTable.Type() == pl.CatalogLocationType {
-		tableFormat = string(sparkResourceTable.schema.SourceTable.(*pl.CatalogLocation).TableFormat())
-	}
+	tableFormat := locationTableFormat(sparkResourceTable.schema.SourceTable)
 	// get destination path for the materialization
 	materializationID := ResourceID{Name: id.Name, Variant: id.Variant, Type: FeatureMaterialization}
 	destinationPath, err := spark.Store.CreateFilePath(materializationID.ToFilestorePath(), true)
@@ -1333,7 +1438,7 @@ func blobSparkMaterialization(
 	sparkArgs, err := sparkScriptCommandDef{
 		DeployMode:     getSparkDeployModeFromEnv(),
 		TFType:         SQLTransformation,
-		OutputLocation: pl.NewFileLocation(destinationPath),
+		OutputLocation: spark.fileOutputLocation(destinationPath),
 		Code:           materializationQuery,
 		SourceList:     []sparklib.SourceInfo{sourcePySpark},
 		JobType:        types.Materialize,
@@ -1425,10 +1530,7 @@ func (spark *SparkOfflineStore) directCopyMaterialize(id ResourceID, opts Materi
 	}
 	logger.Debug("Got resource schema", "ResourceSchema", schema)
 	sourceTable := schema.SourceTable
-	tableFormat := ""
-	if sourceTable.Type() == pl.CatalogLocationType {
-		tableFormat = string(sourceTable.(*pl.CatalogLocation).TableFormat())
-	}
+	tableFormat := locationTableFormat(sourceTable)
 	sourceList := []sparklib.SourceInfo{
 		sparklib.SourceInfo{
 			Location:     sourceTable.Location(),
@@ -1541,6 +1643,9 @@ func sparkTrainingSet(def TrainingSetDef, spark *SparkOfflineStore, isUpdate boo
 		spark.Logger.Errorw("Training set definition not valid", "definition", def, "error", err)
 		return err
 	}
+	if len(def.AdditionalLabels) > 0 {
+		return fferr.NewInvalidArgumentErrorf("%s does not support training sets with multiple labels", spark.Type())
+	}
 	logger := spark.Logger.With("id", def.ID)
 	sourcePaths := make([]sparklib.SourceInfo, 0)
 	featureSchemas := make([]ResourceSchema, 0)
@@ -1568,21 +1673,30 @@ func sparkTrainingSet(def TrainingSetDef, spark *SparkOfflineStore, isUpdate boo
 	logger.Debugw("Label provider", "provider", def.LabelSourceMapping.ProviderType)
 	switch def.LabelSourceMapping.ProviderType {
 	case pt.SparkOffline:
+		if def.Spine != nil {
+			// Spines are read straight from their source rather than from a registered resource table.
+			labelPySparkSource, err = spineSourceInfo(def.LabelSourceMapping)
+			if err != nil {
+				logger.Errorw("Could not get spine source", "spine", def.Spine, "error", err)
+				return err
+			}
+			labelPySparkSource = pinnedSourceInfo(def, def.LabelSourceMapping.Location, labelPySparkSource)
+			labelSchema = ResourceSchema{
+				EntityMappings: *def.LabelSourceMapping.EntityMappings,
+			}
+			break
+		}
 		labelSchema, err = spark.getResourceSchema(def.Label)
 		if err != nil {
 			logger.Errorw("Could not get schema of label in spark store", "label", def.Label, "error", err)
 			return err
 		}
-		var tableFormat string
-		if labelSchema.SourceTable.Type() == pl.CatalogLocationType {
-			tableFormat = labelSchema.SourceTable.(*pl.CatalogLocation).TableFormat()
-		}
-		labelPySparkSource = sparklib.SourceInfo{
+		labelPySparkSource = pinnedSourceInfo(def, labelSchema.SourceTable, sparklib.SourceInfo{
 			Location:     labelSchema.SourceTable.Location(),
 			LocationType: string(labelSchema.SourceTable.Type()),
 			Provider:     def.LabelSourceMapping.ProviderType,
-			TableFormat:  tableFormat,
-		}
+			TableFormat:  locationTableFormat(labelSchema.SourceTable),
+		})
 	case pt.SnowflakeOffline:
 		config := pc.SnowflakeConfig{}
 		if err := config.Deserialize(def.LabelSourceMapping.ProviderConfig); err != nil {
@@ -1617,6 +1731,11 @@ func sparkTrainingSet(def TrainingSetDef, spark *SparkOfflineStore, isUpdate boo
 			Value:  "value",
 			TS:     "ts",
 		}
+		if def.Spine != nil {
+			labelSchema = ResourceSchema{
+				EntityMappings: *def.LabelSourceMapping.EntityMappings,
+			}
+		}
 	default:
 		logger.Errorw("Unsupported label provider", "provider", def.LabelSourceMapping.ProviderType)
 		return fferr.NewInternalErrorf("unsupported label provider: %s", def.LabelSourceMapping.ProviderType.String())
@@ -1667,16 +1786,12 @@ func sparkTrainingSet(def TrainingSetDef, spark *SparkOfflineStore, isUpdate boo
 			spark.Logger.Errorw("Feature entity mappings must be of length 1", "mappings", featureSchema.EntityMappings.Mappings)
 			return fferr.NewInternalErrorf("feature entity mappings must be of length 1; received length %d", len(featureSchema.EntityMappings.Mappings))
 		}
-		var tableFormat string
-		if featureSourceLocation.Type() == pl.CatalogLocationType {
-			tableFormat = featureSourceLocation.(*pl.CatalogLocation).TableFormat()
-		}
-		featurePySparkSource := sparklib.SourceInfo{
+		featurePySparkSource := pinnedSourceInfo(def, featureSourceLocation, sparklib.SourceInfo{
 			Location:     featureSourceLocation.Location(),
 			LocationType: string(featureSourceLocation.Type()),
 			Provider:     spark.Type(),
-			TableFormat:  tableFormat,
-		}
+			TableFormat:  locationTableFormat(featureSourceLocation),
+		})
 		sourcePaths = append(sourcePaths, featurePySparkSource)
 		featureSchemas = append(featureSchemas, featureSchema)
 	}
@@ -1685,7 +1800,7 @@ func sparkTrainingSet(def TrainingSetDef, spark *SparkOfflineStore, isUpdate boo
 	sparkArgs, err := sparkScriptCommandDef{
 		DeployMode:     getSparkDeployModeFromEnv(),
 		TFType:         SQLTransformation,
-		OutputLocation: pl.NewFileLocation(destinationPath),
+		OutputLocation: spark.fileOutputLocation(destinationPath),
 		Code:           trainingSetQuery,
 		SourceList:     sourcePaths,
 		JobType:        types.CreateTrainingSet,
@@ -1724,6 +1839,102 @@ func sparkTrainingSet(def TrainingSetDef, spark *SparkOfflineStore, isUpdate boo
 	return nil
 }
 
+// spineSourceInfo returns the source info for a spine whose source is stored in Spark's file store or catalog.
+func spineSourceInfo(mapping SourceMapping) (sparklib.SourceInfo, error) {
+	if mapping.Location == nil {
+		return sparklib.SourceInfo{}, fferr.NewInvalidArgumentErrorf("training set spine source %s has no location", mapping.Source)
+	}
+	return sparklib.SourceInfo{
+		Location:     mapping.Location.Location(),
+		LocationType: string(mapping.Location.Type()),
+		Provider:     mapping.ProviderType,
+		TableFormat:  locationTableFormat(mapping.Location),
+	}, nil
+}
+
+// pinnedSourceInfo points a training set source at the snapshot of it that def pins, if any.
+func pinnedSourceInfo(def TrainingSetDef, loc pl.Location, source sparklib.SourceInfo) sparklib.SourceInfo {
+	if loc == nil {
+		return source
+	}
+	snapshot, ok := def.inputSnapshot(snapshotLocation(loc))
+	if !ok {
+		return source
+	}
+	switch snapshot.Kind {
+	case fftypes.FileCopySnapshot:
+		source.Location = snapshot.Version
+		source.TableFormat = ""
+	case fftypes.TableVersionSnapshot:
+		source.Version = snapshot.Version
+	case fftypes.TimeTravelSnapshot:
+		// The runner reads this as UTC with millisecond precision.
+		source.AsOf = snapshot.Timestamp.UTC().Format("2006-01-02 15:04:05.000")
+	}
+	return source
+}
+
+// trainingSetInputs returns the tables a training set reads, located the way sparkTrainingSet
+// locates them. Only tables in Spark's file store or catalog can be pinned.
+func (spark *SparkOfflineStore) trainingSetInputs(def TrainingSetDef) ([]trainingSetInput, error) {
+	inputs := make([]trainingSetInput, 0, 1+len(def.Features))
+	add := func(id ResourceID, mapping SourceMapping) error {
+		if mapping.ProviderType != pt.SparkOffline {
+			return fferr.NewInvalidArgumentErrorf("can't pin %s, which is read from %s", snapshotResourceName(id), mapping.ProviderType)
+		}
+		if def.Spine != nil && id == *def.Spine {
+			// Spines are read straight from their source.
+			if mapping.Location == nil {
+				return fferr.NewInvalidArgumentErrorf("training set spine source %s has no location", mapping.Source)
+			}
+			inputs = append(inputs, trainingSetInput{resource: snapshotResourceName(id), location: mapping.Location})
+			return nil
+		}
+		schema, err := spark.getResourceSchema(id)
+		if err != nil {
+			return err
+		}
+		inputs = append(inputs, trainingSetInput{resource: snapshotResourceName(id), location: schema.SourceTable})
+		return nil
+	}
+	label := def.Label
+	if def.Spine != nil {
+		label = *def.Spine
+	}
+	if err := add(label, def.LabelSourceMapping); err != nil {
+		return nil, err
+	}
+	for i, feature := range def.Features {
+		if err := add(feature, def.FeatureSourceMappings[i]); err != nil {
+			return nil, err
+		}
+	}
+	return inputs, nil
+}
+
+// PinTrainingSetInputs pins the current version of each Delta or Iceberg table a training set
+// reads and copies its other files. The tables' own retention, e.g. Delta's VACUUM, must keep
+// their versions for as long as the policy does.
+func (spark *SparkOfflineStore) PinTrainingSetInputs(def TrainingSetDef, policy SnapshotPolicy) ([]fftypes.DataSnapshot, error) {
+	inputs, err := spark.trainingSetInputs(def)
+	if err != nil {
+		return nil, err
+	}
+	return pinFileStoreInputs(spark.Store, def, inputs)
+}
+
+func (spark *SparkOfflineStore) PinTrainingSet(def TrainingSetDef, policy SnapshotPolicy) (fftypes.DataSnapshot, error) {
+	return pinFileStoreTrainingSet(spark.Store, def, time.Now().UTC())
+}
+
+func (spark *SparkOfflineStore) GetTrainingSetSnapshot(id ResourceID, snapshot fftypes.DataSnapshot) (dataset.TrainingSetIterator, error) {
+	return fileStoreGetTrainingSetSnapshot(id, spark.Store, snapshot)
+}
+
+func (spark *SparkOfflineStore) ReleaseSnapshots(snapshots []fftypes.DataSnapshot) error {
+	return releaseFileStoreSnapshots(spark.Store, snapshots)
+}
+
 func (spark *SparkOfflineStore) CreateTrainingSet(def TrainingSetDef) error {
 	return sparkTrainingSet(def, spark, false)
 }
@@ -12,6 +12,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/featureform/config"
 	"github.com/featureform/filestore"
@@ -91,6 +92,37 @@ func (cmd *Command) CompileDatabricks() dbjobs.Task {
 	return task
 }
 
+// SparkApplicationSpec is the part of a Spark Operator SparkApplication's spec that's derived
+// from the command. The JSON tags follow the sparkoperator.k8s.io/v1beta2 schema.
+type SparkApplicationSpec struct {
+	Type                string               `json:"type"`
+	Mode                string               `json:"mode"`
+	MainApplicationFile string               `json:"mainApplicationFile"`
+	Arguments           []string             `json:"arguments,omitempty"`
+	SparkConf           map[string]string    `json:"sparkConf,omitempty"`
+	Deps                SparkApplicationDeps `json:"deps,omitempty"`
+}
+
+type SparkApplicationDeps struct {
+	PyFiles  []string `json:"pyFiles,omitempty"`
+	Packages []string `json:"packages,omitempty"`
+}
+
+func (cmd *Command) CompileSparkApplication() SparkApplicationSpec {
+	list := cmd.Configs.ToSparkFlagsList()
+	nativeFlags, scriptFlags := list.SeparateNativeFlags()
+	scriptArgs := append(cmd.ScriptArgs, scriptFlags.SparkStringFlags()...)
+	spec := SparkApplicationSpec{
+		Type: "Python",
+		// The operator always runs the driver in its own pod.
+		Mode:                "cluster",
+		MainApplicationFile: cmd.Script.ToURI(),
+		Arguments:           scriptArgs,
+	}
+	nativeFlags.ApplyToSparkApplication(&spec)
+	return spec
+}
+
 // CompileScriptOnly returns the script location as a string followed by
 // all the arguments in the script for use in providers like Databricks.
 func (cmd *Command) CompileScriptOnly() (string, []string) {
@@ -180,6 +212,12 @@ func (flags NativeFlags) ApplyToDatabricks(job *dbjobs.Task) {
 	}
 }
 
+func (flags NativeFlags) ApplyToSparkApplication(spec *SparkApplicationSpec) {
+	for _, flag := range flags {
+		flag.ApplyToSparkApplication(spec)
+	}
+}
+
 type ScriptFlags Flags
 
 func (flags ScriptFlags) SparkStringFlags() []string {
@@ -245,6 +283,8 @@ type NativeFlagStringer interface {
 	ApplyToDataprocServerless(*dataprocpb.Batch)
 	// Apply a flag to Databricks
 	ApplyToDatabricks(*dbjobs.Task)
+	// Apply a flag to a Spark Operator SparkApplication
+	ApplyToSparkApplication(*SparkApplicationSpec)
 	FlagStringer
 }
 
@@ -309,6 +349,13 @@ func (flag NativeConfigFlag) ApplyToDatabricks(settings *dbjobs.Task) {
 	logging.GlobalLogger.Warnw("Ignoring native conf flags to databricks", "key", flag.Key)
 }
 
+func (flag NativeConfigFlag) ApplyToSparkApplication(spec *SparkApplicationSpec) {
+	if spec.SparkConf == nil {
+		spec.SparkConf = map[string]string{}
+	}
+	spec.SparkConf[flag.Key] = flag.Value
+}
+
 // ConfigFlag should be set in Spark via spark.config in
 // our PySpark scripts.
 type ConfigFlag struct {
@@ -382,6 +429,10 @@ func (flag PackagesFlag) ApplyToDatabricks(settings *dbjobs.Task) {
 	logging.GlobalLogger.Warnw("Ignoring packages in databricks", "packages", flag.Packages)
 }
 
+func (flag PackagesFlag) ApplyToSparkApplication(spec *SparkApplicationSpec) {
+	spec.Deps.Packages = append(spec.Deps.Packages, flag.Packages...)
+}
+
 func (flag PackagesFlag) IsSparkSubmitNative() bool {
 	return true
 }
@@ -486,6 +537,10 @@ func (flag IncludePyScript) ApplyToDatabricks(settings *dbjobs.Task) {
 	logging.GlobalLogger.Warnw("Unable to include pyscript to databricks", "script", flag.Path.ToURI())
 }
 
+func (flag IncludePyScript) ApplyToSparkApplication(spec *SparkApplicationSpec) {
+	spec.Deps.PyFiles = append(spec.Deps.PyFiles, flag.Path.ToURI())
+}
+
 func (flag IncludePyScript) SparkFlags() Flags {
 	return Flags{flag}
 }
@@ -555,6 +610,46 @@ func (flag DataframeQueryFlag) Redacted() Config {
 	return flag
 }
 
+// IncrementalFlag tells the script to merge the output into the existing table rather than
+// replacing it. Previous is the location of the last output when writing to a filestore.
+type IncrementalFlag struct {
+	MergeKeys       []string
+	TimestampColumn string
+	BackfillStart   time.Time
+	BackfillEnd     time.Time
+	Previous        string
+}
+
+func (flag IncrementalFlag) SparkFlags() Flags {
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format("2006-01-02 15:04:05.999999")
+	}
+	value, err := json.Marshal(map[string]any{
+		"mergeKeys":       flag.MergeKeys,
+		"timestampColumn": flag.TimestampColumn,
+		"backfillStart":   formatTime(flag.BackfillStart),
+		"backfillEnd":     formatTime(flag.BackfillEnd),
+		"previous":        flag.Previous,
+	})
+	if err != nil {
+		logging.GlobalLogger.Errorw("Failed to serialize incremental flag for spark. Skipping flags.", "flag", flag, "error", err)
+		return Flags{}
+	}
+	return Flags{
+		ScriptFlag{
+			Key:   "incremental",
+			Value: string(value),
+		},
+	}
+}
+
+func (flag IncrementalFlag) Redacted() Config {
+	return flag
+}
+
 type DeployFlag struct {
 	Mode types.SparkDeployMode
 }
@@ -590,6 +685,13 @@ func (flag DeployFlag) ApplyToDatabricks(settings *dbjobs.Task) {
 	logging.GlobalLogger.Warnw("Unable to include deploy flag in databricks", "deploy-mode", flag.Mode.SparkArg())
 }
 
+func (flag DeployFlag) ApplyToSparkApplication(spec *SparkApplicationSpec) {
+	logging.GlobalLogger.Debugw(
+		"Ignoring spark deploy mode for the Spark Operator",
+		"deploy-mode", flag.Mode.SparkArg(),
+	)
+}
+
 type BigQueryFlags struct {
 	Config *pc.BigQueryConfig
 }
@@ -942,6 +1044,62 @@ func (args DeltaFlags) Redacted() Config {
 	return args
 }
 
+// FileStoreTableFlags configure Spark to read and write Delta and Iceberg tables by path when
+// a job's output or sources are tables in the file store rather than in a catalog.
+type FileStoreTableFlags struct {
+	TableFormats []types.TableFormatType
+	// Warehouse is required by Iceberg's Hadoop catalog, though tables are only ever
+	// addressed by path through it.
+	Warehouse string
+}
+
+func (args FileStoreTableFlags) SparkFlags() Flags {
+	var extensions []string
+	var flags Flags
+	for _, format := range args.TableFormats {
+		switch format {
+		case types.DeltaType:
+			extensions = append(extensions, "io.delta.sql.DeltaSparkSessionExtension")
+			flags = append(flags, ConfigFlag{
+				Key:   "spark.sql.catalog.spark_catalog",
+				Value: "org.apache.spark.sql.delta.catalog.DeltaCatalog",
+			})
+		case types.IcebergType:
+			extensions = append(extensions, "org.apache.iceberg.spark.extensions.IcebergSparkSessionExtensions")
+			flags = append(flags,
+				PackagesFlag{
+					Packages: []string{"org.apache.iceberg:iceberg-spark-runtime-3.5_2.12:1.6.1"},
+				},
+				ConfigFlag{
+					Key:   "spark.sql.catalog.ff_files",
+					Value: "org.apache.iceberg.spark.SparkCatalog",
+				},
+				ConfigFlag{
+					Key:   "spark.sql.catalog.ff_files.type",
+					Value: "hadoop",
+				},
+				ConfigFlag{
+					Key:   "spark.sql.catalog.ff_files.warehouse",
+					Value: args.Warehouse,
+				},
+			)
+		}
+	}
+	if len(extensions) == 0 {
+		return Flags{}
+	}
+	return append(Flags{
+		ConfigFlag{
+			Key:   "spark.sql.extensions",
+			Value: strings.Join(extensions, ","),
+		},
+	}, flags...)
+}
+
+func (args FileStoreTableFlags) Redacted() Config {
+	return args
+}
+
 type KafkaFlags struct{}
 
 func (args KafkaFlags) SparkFlags() Flags {
@@ -1214,6 +1372,30 @@ func (flag MasterFlag) ApplyToDatabricks(settings *dbjobs.Task) {
 	logging.GlobalLogger.Warnw("Unable to include master flag in databricks", "master-flag", flag.Master)
 }
 
+func (flag MasterFlag) ApplyToSparkApplication(spec *SparkApplicationSpec) {
+	logging.GlobalLogger.Debugw(
+		"Ignoring spark master flag for the Spark Operator",
+		"master-flag", flag.Master,
+	)
+}
+
+// YarnDetachedFlags makes spark-submit return once a cluster mode application is accepted by
+// YARN, rather than waiting for it to finish.
+type YarnDetachedFlags struct{}
+
+func (args YarnDetachedFlags) SparkFlags() Flags {
+	return Flags{
+		NativeConfigFlag{
+			Key:   "spark.yarn.submit.waitAppCompletion",
+			Value: "false",
+		},
+	}
+}
+
+func (args YarnDetachedFlags) Redacted() Config {
+	return args
+}
+
 type HighMemoryFlags struct{}
 
 func (args HighMemoryFlags) SparkFlags() Flags {
@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/featureform/filestore"
+	"github.com/featureform/provider/types"
 )
 
 func TestSparkConfig(t *testing.T) {
@@ -36,6 +37,32 @@ func TestSparkConfig(t *testing.T) {
 				"\"spark.sql.extensions=org.apache.iceberg.spark.extensions.IcebergSparkSessionExtensions\"",
 			},
 		},
+		"NoFileStoreTables": testCase{
+			Configs:  Configs{FileStoreTableFlags{}},
+			Expected: []string{"spark-submit", "/"},
+		},
+		"FileStoreDeltaAndIceberg": testCase{
+			Configs: Configs{FileStoreTableFlags{
+				TableFormats: []types.TableFormatType{types.DeltaType, types.IcebergType},
+				Warehouse:    "s3a://bucket/featureform",
+			}},
+			Expected: []string{
+				"spark-submit",
+				"--packages",
+				"org.apache.iceberg:iceberg-spark-runtime-3.5_2.12:1.6.1",
+				"/",
+				"--spark_config",
+				"\"spark.sql.extensions=io.delta.sql.DeltaSparkSessionExtension,org.apache.iceberg.spark.extensions.IcebergSparkSessionExtensions\"",
+				"--spark_config",
+				"\"spark.sql.catalog.spark_catalog=org.apache.spark.sql.delta.catalog.DeltaCatalog\"",
+				"--spark_config",
+				"\"spark.sql.catalog.ff_files=org.apache.iceberg.spark.SparkCatalog\"",
+				"--spark_config",
+				"\"spark.sql.catalog.ff_files.type=hadoop\"",
+				"--spark_config",
+				"\"spark.sql.catalog.ff_files.warehouse=s3a://bucket/featureform\"",
+			},
+		},
 	}
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -46,3 +73,40 @@ func TestSparkConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestCompileSparkApplication(t *testing.T) {
+	path, err := filestore.NewEmptyFilepath(filestore.S3)
+	if err != nil {
+		t.Fatalf("Failed to create empty file path: %s", err)
+	}
+	cmd := &Command{
+		Script:     path,
+		ScriptArgs: []string{"sql"},
+		Configs: Configs{
+			IcebergFlags{},
+			MasterFlag{Master: "local"},
+			YarnDetachedFlags{},
+			IncludePyScript{Path: path},
+		},
+	}
+	expected := SparkApplicationSpec{
+		Type:                "Python",
+		Mode:                "cluster",
+		MainApplicationFile: "/",
+		Arguments: []string{
+			"sql",
+			"--spark_config",
+			"\"spark.sql.extensions=org.apache.iceberg.spark.extensions.IcebergSparkSessionExtensions\"",
+		},
+		SparkConf: map[string]string{
+			"spark.yarn.submit.waitAppCompletion": "false",
+		},
+		Deps: SparkApplicationDeps{
+			PyFiles:  []string{"/"},
+			Packages: []string{"org.apache.iceberg:iceberg-spark-runtime-3.5_2.12:1.6.1"},
+		},
+	}
+	if spec := cmd.CompileSparkApplication(); !reflect.DeepEqual(spec, expected) {
+		t.Fatalf("Spec not equal.\n%+v\n%+v\n", spec, expected)
+	}
+}
@@ -32,6 +32,11 @@ type SourceInfo struct {
 	AwsAssumeRoleArn    string `json:"awsAssumeRoleArn"`
 	TimestampColumnName string `json:"timestampColumnName"`
 
+	// Version and AsOf pin the data a table is read at: a Delta version or Iceberg snapshot
+	// ID for file store tables, or a timestamp for catalog tables.
+	Version string `json:"version,omitempty"`
+	AsOf    string `json:"asOf,omitempty"`
+
 	// Deprecated
 	// TODO remove
 	// Old version of our pyspark job actually passed in strings
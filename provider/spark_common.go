@@ -78,6 +78,8 @@ type sparkScriptCommandDef struct {
 	Store SparkFileStoreV2
 	// Mappings provides SourceMappings for use alongside SourceList
 	Mappings []SourceMapping
+	// Incremental is set when the output should be merged into the existing table.
+	Incremental *spark.IncrementalFlag
 }
 
 func (def sparkScriptCommandDef) Redacted() map[string]any {
@@ -103,6 +105,7 @@ func (def sparkScriptCommandDef) Redacted() map[string]any {
 		"Mappings":       redactedMapping,
 		"FileStoreType":  def.Store.FilestoreType(),
 		"SparkStoreType": def.Store.Type(),
+		"Incremental":    def.Incremental,
 	}
 }
 
@@ -152,6 +155,7 @@ func (def sparkScriptCommandDef) PrepareCommand(logger logging.Logger) (*spark.C
 				SnowflakeConfig: snowflakeConfig,
 				BigQueryConfig:  bqConfig,
 				Store:           def.Store,
+				FileTables:      def.fileStoreTableFlags(),
 			},
 		),
 	}
@@ -180,6 +184,9 @@ func (def sparkScriptCommandDef) PrepareCommand(logger logging.Logger) (*spark.C
 			Sources: def.SourceList,
 		})
 	}
+	if def.Incremental != nil {
+		cmd.AddConfigs(*def.Incremental)
+	}
 	// EMR's API enforces a 10K-character (i.e. bytes) limit on string values passed to HadoopJarStep, so to avoid a 400, we need
 	// to check to ensure the args are below this limit. If they exceed this limit, it's most likely due to the query and/or the list
 	// of sources, so we write these as a JSON file and read them from the PySpark runner script to side-step this constraint
@@ -204,6 +211,32 @@ type sparkCoreConfigsArgs struct {
 	SnowflakeConfig *pc.SnowflakeConfig
 	BigQueryConfig  *pc.BigQueryConfig
 	Store           SparkFileStoreV2
+	FileTables      spark.FileStoreTableFlags
+}
+
+// fileStoreTableFlags collects the table formats of the file store tables the job writes or reads.
+func (def sparkScriptCommandDef) fileStoreTableFlags() spark.FileStoreTableFlags {
+	flags := spark.FileStoreTableFlags{}
+	seen := map[string]bool{}
+	add := func(format, location string) {
+		if format == "" || seen[format] {
+			return
+		}
+		seen[format] = true
+		flags.TableFormats = append(flags.TableFormats, types.TableFormatType(format))
+		if flags.Warehouse == "" {
+			flags.Warehouse = location
+		}
+	}
+	if output, ok := def.OutputLocation.(*pl.FileStoreLocation); ok {
+		add(output.TableFormat(), output.Location())
+	}
+	for _, source := range def.SourceList {
+		if source.LocationType == string(pl.FileStoreLocationType) {
+			add(source.TableFormat, source.Location)
+		}
+	}
+	return flags
 }
 
 func sparkCoreConfigs(args sparkCoreConfigsArgs) spark.Configs {
@@ -223,6 +256,7 @@ func sparkCoreConfigs(args sparkCoreConfigsArgs) spark.Configs {
 		spark.DeployFlag{
 			Mode: args.DeployMode,
 		},
+		args.FileTables,
 	}
 	return append(configs, args.Store.SparkConfigs()...)
 }
@@ -9,11 +9,14 @@ package provider
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/featureform/fferr"
 	"github.com/featureform/filestore"
@@ -22,6 +25,7 @@ import (
 	pc "github.com/featureform/provider/provider_config"
 	pt "github.com/featureform/provider/provider_type"
 	"github.com/featureform/provider/spark"
+	"github.com/featureform/provider/types"
 )
 
 func NewSparkGenericExecutor(sparkGenericConfig pc.SparkGenericConfig, logger logging.Logger) (SparkExecutor, error) {
@@ -48,9 +52,27 @@ type SparkGenericExecutor struct {
 	coreSite      string
 	yarnSite      string
 	logger        logging.Logger
+	// runCommand runs a bash command and returns its stdout and stderr. It defaults to
+	// runBashCommand, and pollInterval to sparkGenericStatusPollInterval.
+	runCommand   func(command string) (string, string, error)
+	pollInterval time.Duration
 	baseExecutor
 }
 
+func (s *SparkGenericExecutor) run(command string) (string, string, error) {
+	if s.runCommand == nil {
+		return runBashCommand(command)
+	}
+	return s.runCommand(command)
+}
+
+func (s *SparkGenericExecutor) statusPollInterval() time.Duration {
+	if s.pollInterval == 0 {
+		return sparkGenericStatusPollInterval
+	}
+	return s.pollInterval
+}
+
 func (s *SparkGenericExecutor) InitializeExecutor(store SparkFileStoreV2) error {
 	s.logger.Info("Uploading PySpark script to filestore")
 	// We can't use CreateFilePath here because it calls Validate under the hood,
@@ -76,7 +98,7 @@ func (s *SparkGenericExecutor) InitializeExecutor(store SparkFileStoreV2) error
 	return nil
 }
 
-func (s *SparkGenericExecutor) getYarnCommand(args string) (string, error) {
+func (s *SparkGenericExecutor) writeHadoopConf() (string, error) {
 	configDir, err := os.MkdirTemp("", "hadoop-conf")
 	if err != nil {
 		return "", fferr.NewInternalError(fmt.Errorf("could not create temp dir: %v", err))
@@ -91,6 +113,14 @@ func (s *SparkGenericExecutor) getYarnCommand(args string) (string, error) {
 	if err != nil {
 		return "", fferr.NewInternalError(fmt.Errorf("could not write core-site.xml: %v", err))
 	}
+	return configDir, nil
+}
+
+func (s *SparkGenericExecutor) getYarnCommand(args string) (string, error) {
+	configDir, err := s.writeHadoopConf()
+	if err != nil {
+		return "", err
+	}
 	return fmt.Sprintf(""+
 		"pyenv global %s && "+
 		"export HADOOP_CONF_DIR=%s &&  "+
@@ -102,53 +132,323 @@ func (s *SparkGenericExecutor) getGenericCommand(args string) string {
 	return fmt.Sprintf("pyenv global %s && pyenv exec %s", s.pythonVersion, args)
 }
 
+// getStatusCommand returns a command that prints the status of a submitted driver.
+func (s *SparkGenericExecutor) getStatusCommand(submissionID string) (string, error) {
+	if s.isYarn() {
+		configDir, err := s.writeHadoopConf()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(""+
+			"export HADOOP_CONF_DIR=%s && "+
+			"yarn application -status %s; "+
+			"rm -r %s", configDir, submissionID, configDir), nil
+	}
+	return s.getGenericCommand(fmt.Sprintf("spark-submit --master %s --status %s", s.master, submissionID)), nil
+}
+
+func (s *SparkGenericExecutor) isYarn() bool {
+	return s.master == "yarn"
+}
+
+// supportsResume returns true if the master lets us look up a driver by its submission ID.
+func (s *SparkGenericExecutor) supportsResume() bool {
+	return s.isYarn() || strings.HasPrefix(s.master, "spark://")
+}
+
 func (s *SparkGenericExecutor) SupportsTransformationOption(opt TransformationOptionType) (bool, error) {
+	if opt == ResumableTransformation {
+		return s.supportsResume(), nil
+	}
 	return false, nil
 }
 
 func (s *SparkGenericExecutor) RunSparkJob(sparkCmd *spark.Command, store SparkFileStoreV2, opts SparkJobOptions, tfOpts TransformationOptions) error {
+	logger := s.logger.With("master", s.master, "job_name", opts.JobName)
+	resumeOpt, hasResumeOpt := tfOpts.GetResumeOption(logger)
+	// In client mode the driver runs inside of spark-submit, so it can't outlive us and there's
+	// nothing to re-attach to.
+	resumable := hasResumeOpt && s.supportsResume() && commandDeployMode(sparkCmd) == types.SparkClusterDeployMode
+	logger = logger.With("resume_opt_set", hasResumeOpt, "resumable", resumable)
+
+	if hasResumeOpt && !resumable {
+		if resumeOpt.IsResumeIDSet() {
+			logger.Warnw("Unable to resume spark job in client mode, re-running it", "resume_id", resumeOpt.ResumeID())
+		}
+		go func() {
+			var jobErr error = fferr.NewInternalErrorf("Waiter panicked")
+			defer func() {
+				if err := resumeOpt.finishWithError(jobErr); err != nil {
+					logger.Errorw("Unable to set error in resume option", "error", err)
+				}
+			}()
+			_, jobErr = s.submit(sparkCmd, store, false, logger)
+		}()
+		return nil
+	}
+	if !resumable {
+		_, err := s.submit(sparkCmd, store, false, logger)
+		return err
+	}
+
+	var submissionID string
+	if resumeOpt.IsResumeIDSet() {
+		resumeID, err := deserializeSparkGenericResumeID(resumeOpt.ResumeID())
+		if err != nil {
+			logger.Errorw("Failed to deserialize resume ID", "error", err)
+			return err
+		}
+		if resumeID.Master != s.master {
+			logger.Warnw("Resuming a spark job submitted to a different master", "resuming_on_master", resumeID.Master)
+		}
+		submissionID = resumeID.SubmissionID
+		logger.Infow("Resuming spark job", "submission_id", submissionID)
+	} else {
+		output, err := s.submit(sparkCmd, store, true, logger)
+		if err != nil {
+			return err
+		}
+		submissionID, err = parseSparkSubmissionID(output)
+		if err != nil {
+			logger.Errorw("Unable to find submission ID in spark-submit output", "error", err)
+			return err
+		}
+		resumeID, err := (&sparkGenericResumeID{Master: s.master, SubmissionID: submissionID}).Marshal()
+		if err != nil {
+			return err
+		}
+		if err := resumeOpt.setResumeID(resumeID); err != nil {
+			return err
+		}
+	}
+	logger = logger.With("submission_id", submissionID)
+	go func() {
+		// Finish ResumeOption after the driver finishes.
+		var jobErr error = fferr.NewInternalErrorf("Waiter panicked")
+		defer func() {
+			if err := resumeOpt.finishWithError(jobErr); err != nil {
+				logger.Errorw("Unable to set error in resume option", "error", err)
+			}
+		}()
+		jobErr = s.waitForDriver(submissionID, store, opts.MaxJobDuration, logger)
+		logger.Debugw("Resume option finished", "job_err", jobErr)
+	}()
+	return nil
+}
+
+// submit runs spark-submit and returns its combined output. If detach is set, spark-submit
+// returns as soon as the driver is submitted rather than waiting for it to finish.
+func (s *SparkGenericExecutor) submit(sparkCmd *spark.Command, store SparkFileStoreV2, detach bool, logger logging.Logger) (string, error) {
 	sparkCmd.AddConfigs(spark.MasterFlag{s.master})
+	if detach && s.isYarn() {
+		sparkCmd.AddConfigs(spark.YarnDetachedFlags{})
+	}
 	args := sparkCmd.Compile()
-	bashCommand := "bash"
 	sparkArgsString := strings.Join(args, " ")
 	var commandString string
 
-	if s.master == "yarn" {
-		s.logger.Info("Running spark job on yarn")
+	if s.isYarn() {
+		logger.Info("Running spark job on yarn")
 		var err error
 		commandString, err = s.getYarnCommand(sparkArgsString)
 		if err != nil {
-			return err
+			return "", err
 		}
 	} else {
 		commandString = s.getGenericCommand(sparkArgsString)
 	}
 
-	bashCommandArgs := []string{"-c", commandString}
+	logger.Info("Executing spark-submit")
+	stdout, stderr, err := s.run(commandString)
+	if err != nil {
+		wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("spark job failed: %v", err))
+		wrapped.AddDetails("executor_type", "Spark Generic", "store_type", store.Type(), "stdout", stdout, "stderr", stderr)
+		wrapped.AddFixSuggestion("Check the cluster logs for more information")
+		return "", wrapped
+	}
+	// spark-submit logs to stderr, so look at both streams.
+	return stdout + "\n" + stderr, nil
+}
+
+func (s *SparkGenericExecutor) waitForDriver(submissionID string, store SparkFileStoreV2, maxWait time.Duration, logger logging.Logger) error {
+	logger.Infow("Waiting for spark job to complete", "wait_duration", maxWait.String())
+	deadline := time.Now().Add(maxWait)
+	for {
+		statusCmd, err := s.getStatusCommand(submissionID)
+		if err != nil {
+			return err
+		}
+		stdout, stderr, err := s.run(statusCmd)
+		if err != nil {
+			wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("could not get spark job status: %v", err))
+			wrapped.AddDetails("executor_type", "Spark Generic", "store_type", store.Type(), "submission_id", submissionID, "stdout", stdout, "stderr", stderr)
+			return wrapped
+		}
+		state, err := parseSparkDriverState(stdout + "\n" + stderr)
+		if err != nil {
+			logger.Warnw("Unable to parse spark job status, retrying", "error", err)
+		}
+		switch state {
+		case sparkDriverSucceeded:
+			logger.Info("Spark job completed successfully")
+			return nil
+		case sparkDriverFailed:
+			wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("spark job failed"))
+			wrapped.AddDetails("executor_type", "Spark Generic", "store_type", store.Type(), "submission_id", submissionID, "status", stdout)
+			wrapped.AddFixSuggestion("Check the cluster logs for more information")
+			return wrapped
+		}
+		if time.Now().After(deadline) {
+			wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("spark job did not finish within %s", maxWait))
+			wrapped.AddDetails("executor_type", "Spark Generic", "store_type", store.Type(), "submission_id", submissionID)
+			return wrapped
+		}
+		time.Sleep(s.statusPollInterval())
+	}
+}
 
-	s.logger.Info("Executing spark-submit")
-	cmd := exec.Command(bashCommand, bashCommandArgs...)
+func runBashCommand(command string) (string, string, error) {
+	cmd := exec.Command("bash", "-c", command)
 	cmd.Env = append(os.Environ(), "FEATUREFORM_LOCAL_MODE=true")
 
 	var outb, errb bytes.Buffer
 	cmd.Stdout = &outb
 	cmd.Stderr = &errb
 
-	err := cmd.Start()
-	if err != nil {
-		wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("could not run spark job: %v", err))
-		wrapped.AddDetails("executor_type", "Spark Generic", "store_type", store.Type())
-		wrapped.AddFixSuggestion("Check the cluster logs for more information")
-		return wrapped
+	if err := cmd.Start(); err != nil {
+		return "", "", fmt.Errorf("could not run command: %v", err)
+	}
+	err := cmd.Wait()
+	return outb.String(), errb.String(), err
+}
+
+// commandDeployMode returns the deploy mode set on cmd, spark defaults to client mode.
+func commandDeployMode(cmd *spark.Command) types.SparkDeployMode {
+	for _, cfg := range cmd.Configs {
+		if flag, ok := cfg.(spark.DeployFlag); ok {
+			return flag.Mode
+		}
 	}
+	return types.SparkClientDeployMode
+}
 
-	err = cmd.Wait()
-	if err != nil {
-		wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("spark job failed: %v", err))
-		wrapped.AddDetails("executor_type", "Spark Generic", "store_type", store.Type(), "stdout", outb, "stderr", errb)
-		wrapped.AddFixSuggestion("Check the cluster logs for more information")
-		return wrapped
+const sparkGenericStatusPollInterval = 10 * time.Second
+
+var (
+	yarnApplicationIDRegex  = regexp.MustCompile(`application_\d+_\d+`)
+	standaloneDriverIDRegex = regexp.MustCompile(`driver-\d+-\d+`)
+	yarnFinalStateRegex     = regexp.MustCompile(`Final-State\s*:\s*(\w+)`)
+	yarnStateRegex          = regexp.MustCompile(`(?m)^\s*State\s*:\s*(\w+)`)
+	standaloneStateRegex    = regexp.MustCompile(`"driverState"\s*:\s*"(\w+)"`)
+)
+
+// parseSparkSubmissionID finds the YARN application ID or standalone driver ID in spark-submit's output.
+func parseSparkSubmissionID(output string) (string, error) {
+	if id := yarnApplicationIDRegex.FindString(output); id != "" {
+		return id, nil
+	}
+	if id := standaloneDriverIDRegex.FindString(output); id != "" {
+		return id, nil
+	}
+	return "", fferr.NewInternalErrorf("no submission ID found in spark-submit output")
+}
+
+type sparkDriverState int
+
+const (
+	sparkDriverRunning sparkDriverState = iota
+	sparkDriverSucceeded
+	sparkDriverFailed
+)
+
+// parseSparkDriverState reads the output of `yarn application -status` or `spark-submit --status`.
+func parseSparkDriverState(output string) (sparkDriverState, error) {
+	if match := yarnFinalStateRegex.FindStringSubmatch(output); match != nil {
+		switch match[1] {
+		case "SUCCEEDED":
+			return sparkDriverSucceeded, nil
+		case "FAILED", "KILLED":
+			return sparkDriverFailed, nil
+		}
+		// The final state is UNDEFINED until the application finishes, fall back to State.
+		if state := yarnStateRegex.FindStringSubmatch(output); state != nil {
+			switch state[1] {
+			case "FAILED", "KILLED":
+				return sparkDriverFailed, nil
+			}
+		}
+		return sparkDriverRunning, nil
+	}
+	if match := standaloneStateRegex.FindStringSubmatch(output); match != nil {
+		switch match[1] {
+		case "FINISHED":
+			return sparkDriverSucceeded, nil
+		case "FAILED", "ERROR", "KILLED":
+			return sparkDriverFailed, nil
+		}
+		return sparkDriverRunning, nil
+	}
+	return sparkDriverRunning, fferr.NewInternalErrorf("no driver state found in status output")
+}
+
+// sparkGenericResumeID serializes into a ResumeID to be used via ResumeOption (a type of TransformationOption)
+type sparkGenericResumeID struct {
+	Master       string
+	SubmissionID string
+}
+
+// sparkGenericResumeIDRecordV0 becomes the actual JSON format of the ResumeID in the database.
+type sparkGenericResumeIDRecordV0 struct {
+	// SchemaVersion will make it easier to retain backwards compatibility in the future and do schema
+	// migration.
+	SchemaVersion int
+	Master        string
+	SubmissionID  string
+}
+
+func (rec sparkGenericResumeIDRecordV0) ToSparkGenericResumeID() *sparkGenericResumeID {
+	return &sparkGenericResumeID{
+		Master:       rec.Master,
+		SubmissionID: rec.SubmissionID,
 	}
+}
 
+func (resID *sparkGenericResumeID) Validate() error {
+	if resID.Master == "" {
+		return fferr.NewInternalErrorf("Spark Resume ID must have Master set: %v", resID)
+	}
+	if resID.SubmissionID == "" {
+		return fferr.NewInternalErrorf("Spark Resume ID must have SubmissionID set: %v", resID)
+	}
 	return nil
 }
+
+func (resID *sparkGenericResumeID) Marshal() (types.ResumeID, error) {
+	if err := resID.Validate(); err != nil {
+		return types.NilResumeID, err
+	}
+	record := sparkGenericResumeIDRecordV0{
+		// If you're changing the schema of the record, you should change the schema version and handle it in
+		// the deserialize method.
+		SchemaVersion: 0,
+		Master:        resID.Master,
+		SubmissionID:  resID.SubmissionID,
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fferr.NewInternalErrorf("Unable to serialize Spark resume ID: %s", err)
+	}
+	return types.ResumeID(data), nil
+}
+
+func deserializeSparkGenericResumeID(id types.ResumeID) (*sparkGenericResumeID, error) {
+	var record sparkGenericResumeIDRecordV0
+	if err := json.Unmarshal([]byte(id), &record); err != nil {
+		return nil, fferr.NewInternalErrorf("Unable to deserialize Spark resume ID: %s", err)
+	}
+	resID := record.ToSparkGenericResumeID()
+	if err := resID.Validate(); err != nil {
+		return nil, err
+	}
+	return resID, nil
+}
@@ -0,0 +1,468 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/filestore"
+	"github.com/featureform/kubernetes"
+	"github.com/featureform/logging"
+	pl "github.com/featureform/provider/location"
+	pc "github.com/featureform/provider/provider_config"
+	pt "github.com/featureform/provider/provider_type"
+	"github.com/featureform/provider/spark"
+	"github.com/featureform/provider/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	sparkKubernetesPollInterval = 10 * time.Second
+	// Driver logs are forwarded to the run logs in batches to limit the number of log entries.
+	sparkKubernetesLogBatchSize     = 100
+	sparkKubernetesLogFlushInterval = 5 * time.Second
+	// How long to wait for the remaining driver logs once the application has finished.
+	sparkKubernetesLogDrainTimeout = 30 * time.Second
+)
+
+func NewSparkKubernetesExecutor(config pc.SparkKubernetesConfig, logger logging.Logger) (SparkExecutor, error) {
+	client, err := kubernetes.NewSparkApplicationClient(config.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return newSparkKubernetesExecutor(config, client, logger)
+}
+
+func newSparkKubernetesExecutor(config pc.SparkKubernetesConfig, client *kubernetes.SparkApplicationClient, logger logging.Logger) (*SparkKubernetesExecutor, error) {
+	if config.Image == "" {
+		return nil, fferr.NewProviderConfigError("Spark", fmt.Errorf("an image is required to run spark on kubernetes"))
+	}
+	if config.SparkVersion == "" {
+		return nil, fferr.NewProviderConfigError("Spark", fmt.Errorf("a spark version is required to run spark on kubernetes"))
+	}
+	base, err := newBaseExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return &SparkKubernetesExecutor{
+		config:       config,
+		client:       client,
+		logger:       logger.With("executor_type", pc.SparkKubernetes, "namespace", client.Namespace),
+		pollInterval: sparkKubernetesPollInterval,
+		baseExecutor: base,
+	}, nil
+}
+
+// SparkKubernetesExecutor runs spark jobs as SparkApplication resources via the Kubernetes Spark Operator.
+type SparkKubernetesExecutor struct {
+	config       pc.SparkKubernetesConfig
+	client       *kubernetes.SparkApplicationClient
+	logger       logging.Logger
+	pollInterval time.Duration
+	baseExecutor
+}
+
+func (e *SparkKubernetesExecutor) InitializeExecutor(store SparkFileStoreV2) error {
+	e.logger.Info("Uploading PySpark script to filestore")
+	// We can't use CreateFilePath here because it calls Validate under the hood,
+	// which will always fail given it's a local file without a valid scheme or bucket, for example.
+	sparkLocalScriptPath := &filestore.LocalFilepath{}
+	if err := sparkLocalScriptPath.SetKey(e.files.LocalScriptPath); err != nil {
+		return err
+	}
+
+	sparkRemoteScriptPath, err := store.CreateFilePath(e.files.RemoteScriptPath, false)
+	if err != nil {
+		return err
+	}
+
+	err = readAndUploadFile(sparkLocalScriptPath, sparkRemoteScriptPath, store)
+	if err != nil {
+		return err
+	}
+	scriptExists, err := store.Exists(pl.NewFileLocation(sparkRemoteScriptPath))
+	if err != nil || !scriptExists {
+		return fferr.NewInternalError(fmt.Errorf("could not upload spark script: Path: %s, Error: %v", sparkRemoteScriptPath.ToURI(), err))
+	}
+	return nil
+}
+
+func (e *SparkKubernetesExecutor) SupportsTransformationOption(opt TransformationOptionType) (bool, error) {
+	switch opt {
+	case ResumableTransformation, RunLogTransformation:
+		return true, nil
+	}
+	return false, nil
+}
+
+func (e *SparkKubernetesExecutor) RunSparkJob(cmd *spark.Command, store SparkFileStoreV2, opts SparkJobOptions, tfOpts TransformationOptions) error {
+	logger := e.logger.With("job_name", opts.JobName)
+	resumeOpt, hasResumeOpt := tfOpts.GetResumeOption(logger)
+	runLogOpt, _ := tfOpts.GetRunLogOption(logger)
+	logger = logger.With("resume_opt_set", hasResumeOpt)
+
+	client := e.client
+	var name string
+	// Only forward logs written after we re-attach, the rest were already forwarded.
+	var logsSince *metav1.Time
+	if hasResumeOpt && resumeOpt.IsResumeIDSet() {
+		resumeID, err := deserializeSparkKubernetesResumeID(resumeOpt.ResumeID())
+		if err != nil {
+			logger.Errorw("Failed to deserialize resume ID", "error", err)
+			return err
+		}
+		if resumeID.Namespace != client.Namespace {
+			logger.Warnw("Resuming a spark application in a different namespace", "resuming_in_namespace", resumeID.Namespace)
+			client = e.clientForNamespace(resumeID.Namespace)
+		}
+		name = resumeID.Name
+		now := metav1.Now()
+		logsSince = &now
+		logger.Infow("Resuming spark application", "application_name", name)
+	} else {
+		name = kubernetes.CreateSparkApplicationName(opts.JobName)
+		if err := e.submit(cmd, name, logger); err != nil {
+			return err
+		}
+		if hasResumeOpt {
+			resumeID, err := (&sparkKubernetesResumeID{Namespace: client.Namespace, Name: name}).Marshal()
+			if err != nil {
+				return err
+			}
+			if err := resumeOpt.setResumeID(resumeID); err != nil {
+				return err
+			}
+		}
+	}
+	logger = logger.With("application_name", name)
+	waiter := sparkApplicationWaiter{
+		client:       client,
+		name:         name,
+		maxWait:      opts.MaxJobDuration,
+		pollInterval: e.pollInterval,
+		runLogs:      runLogOpt,
+		logsSince:    logsSince,
+		logger:       logger,
+	}
+	if !hasResumeOpt {
+		return waiter.Wait()
+	}
+	go func() {
+		// Finish ResumeOption after the application finishes.
+		var jobErr error = fferr.NewInternalErrorf("Waiter panicked")
+		defer func() {
+			if err := resumeOpt.finishWithError(jobErr); err != nil {
+				logger.Errorw("Unable to set error in resume option", "error", err)
+			}
+		}()
+		jobErr = waiter.Wait()
+		logger.Debugw("Resume option finished", "job_err", jobErr)
+	}()
+	return nil
+}
+
+// Cancel deletes the SparkApplication referred to by resumeID, which stops its driver and executors.
+func (e *SparkKubernetesExecutor) Cancel(resumeID types.ResumeID) error {
+	id, err := deserializeSparkKubernetesResumeID(resumeID)
+	if err != nil {
+		return err
+	}
+	e.logger.Infow("Cancelling spark application", "application_name", id.Name)
+	return e.clientForNamespace(id.Namespace).Delete(context.Background(), id.Name)
+}
+
+func (e *SparkKubernetesExecutor) clientForNamespace(namespace string) *kubernetes.SparkApplicationClient {
+	client := *e.client
+	client.Namespace = namespace
+	return &client
+}
+
+func (e *SparkKubernetesExecutor) submit(cmd *spark.Command, name string, logger logging.Logger) error {
+	spec, err := e.applicationSpec(cmd)
+	if err != nil {
+		return err
+	}
+	logger.Infow("Creating spark application", "application_name", name)
+	if err := e.client.Create(context.Background(), name, spec); err != nil {
+		logger.Errorw("Failed to create spark application", "application_name", name, "error", err)
+		wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("could not create spark application: %w", err))
+		wrapped.AddDetails("executor_type", "Spark Kubernetes", "namespace", e.client.Namespace, "application_name", name)
+		wrapped.AddFixSuggestion("Check that the Spark Operator is installed and Featureform is allowed to create SparkApplications in the namespace")
+		return wrapped
+	}
+	return nil
+}
+
+// sparkKubernetesSpec is the full SparkApplication spec, the command's part plus the
+// resources set in the executor config.
+type sparkKubernetesSpec struct {
+	spark.SparkApplicationSpec
+	Image           string                 `json:"image"`
+	ImagePullPolicy string                 `json:"imagePullPolicy,omitempty"`
+	SparkVersion    string                 `json:"sparkVersion"`
+	PythonVersion   string                 `json:"pythonVersion,omitempty"`
+	RestartPolicy   sparkKubernetesRestart `json:"restartPolicy"`
+	Driver          sparkKubernetesPodSpec `json:"driver"`
+	Executor        sparkKubernetesPodSpec `json:"executor"`
+}
+
+type sparkKubernetesRestart struct {
+	Type string `json:"type"`
+}
+
+type sparkKubernetesPodSpec struct {
+	Cores          int32  `json:"cores,omitempty"`
+	Instances      int32  `json:"instances,omitempty"`
+	Memory         string `json:"memory,omitempty"`
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
+func (e *SparkKubernetesExecutor) applicationSpec(cmd *spark.Command) (map[string]interface{}, error) {
+	spec := sparkKubernetesSpec{
+		SparkApplicationSpec: cmd.CompileSparkApplication(),
+		Image:                e.config.Image,
+		ImagePullPolicy:      e.config.ImagePullPolicy,
+		SparkVersion:         e.config.SparkVersion,
+		PythonVersion:        e.config.PythonVersion,
+		// Failed runs are retried by the coordinator, not the operator.
+		RestartPolicy: sparkKubernetesRestart{Type: "Never"},
+		Driver: sparkKubernetesPodSpec{
+			Cores:          e.config.DriverCores,
+			Memory:         e.config.DriverMemory,
+			ServiceAccount: e.config.ServiceAccount,
+		},
+		Executor: sparkKubernetesPodSpec{
+			Cores:     e.config.ExecutorCores,
+			Instances: e.config.ExecutorInstances,
+			Memory:    e.config.ExecutorMemory,
+		},
+	}
+	// The dynamic client only accepts JSON types, so round trip the typed spec through JSON.
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fferr.NewInternalError(err)
+	}
+	var unstructuredSpec map[string]interface{}
+	if err := json.Unmarshal(data, &unstructuredSpec); err != nil {
+		return nil, fferr.NewInternalError(err)
+	}
+	return unstructuredSpec, nil
+}
+
+// sparkApplicationWaiter polls a SparkApplication until it finishes and forwards its driver logs.
+type sparkApplicationWaiter struct {
+	client       *kubernetes.SparkApplicationClient
+	name         string
+	maxWait      time.Duration
+	pollInterval time.Duration
+	runLogs      *RunLogOption
+	logsSince    *metav1.Time
+	logger       logging.Logger
+}
+
+func (w sparkApplicationWaiter) Wait() error {
+	w.logger.Infow("Waiting for spark application to complete", "wait_duration", w.maxWait.String())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var logsDone chan struct{}
+	deadline := time.Now().Add(w.maxWait)
+	for {
+		status, err := w.client.GetStatus(ctx, w.name)
+		if err != nil {
+			var notFound *fferr.JobDoesNotExistError
+			if errors.As(err, &notFound) {
+				w.logger.Errorw("Spark application no longer exists", "error", err)
+				wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("spark application was deleted before it finished"))
+				w.addDetails(wrapped)
+				return wrapped
+			}
+			wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("could not get spark application status: %w", err))
+			w.addDetails(wrapped)
+			return wrapped
+		}
+		if logsDone == nil && w.runLogs != nil && hasDriverLogs(status) {
+			logsDone = w.forwardDriverLogs(ctx, status.DriverPodName)
+		}
+		if status.State.IsTerminal() {
+			w.drainLogs(logsDone)
+			if status.State == kubernetes.SparkApplicationCompleted {
+				w.logger.Info("Spark application completed successfully")
+				return nil
+			}
+			return w.applicationError(status)
+		}
+		if time.Now().After(deadline) {
+			return w.cancel()
+		}
+		time.Sleep(w.pollInterval)
+	}
+}
+
+// hasDriverLogs returns true once the driver container has started.
+func hasDriverLogs(status kubernetes.SparkApplicationStatus) bool {
+	if status.DriverPodName == "" {
+		return false
+	}
+	switch status.State {
+	case kubernetes.SparkApplicationNew, kubernetes.SparkApplicationSubmitted, kubernetes.SparkApplicationSubmissionFailed:
+		return false
+	}
+	return true
+}
+
+// forwardDriverLogs streams the driver's logs into the run logs in the background and returns a
+// channel that's closed once the stream ends.
+func (w sparkApplicationWaiter) forwardDriverLogs(ctx context.Context, podName string) chan struct{} {
+	logger := w.logger.With("driver_pod", podName)
+	logger.Debugw("Forwarding driver logs to run logs")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		batch := make([]string, 0, sparkKubernetesLogBatchSize)
+		lastFlush := time.Now()
+		flush := func() {
+			if len(batch) == 0 {
+				return
+			}
+			if err := w.runLogs.Log(strings.Join(batch, "\n")); err != nil {
+				// We can continue without the run log
+				logger.Errorw("Unable to add run log", "error", err)
+			}
+			batch = batch[:0]
+			lastFlush = time.Now()
+		}
+		err := w.client.StreamPodLogs(ctx, podName, w.logsSince, func(line string) {
+			batch = append(batch, line)
+			if len(batch) >= sparkKubernetesLogBatchSize || time.Since(lastFlush) > sparkKubernetesLogFlushInterval {
+				flush()
+			}
+		})
+		flush()
+		if err != nil {
+			logger.Warnw("Stopped forwarding driver logs", "error", err)
+		}
+	}()
+	return done
+}
+
+func (w sparkApplicationWaiter) drainLogs(logsDone chan struct{}) {
+	if logsDone == nil {
+		return
+	}
+	select {
+	case <-logsDone:
+	case <-time.After(sparkKubernetesLogDrainTimeout):
+		w.logger.Warnw("Timed out waiting for driver logs", "timeout", sparkKubernetesLogDrainTimeout.String())
+	}
+}
+
+func (w sparkApplicationWaiter) applicationError(status kubernetes.SparkApplicationStatus) error {
+	w.logger.Errorw("Spark application failed", "state", status.State, "error_message", status.ErrorMessage)
+	var wrapped *fferr.ExecutionError
+	if status.State == kubernetes.SparkApplicationSubmissionFailed {
+		wrapped = fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("spark application could not be submitted: %s", status.ErrorMessage))
+		wrapped.AddFixSuggestion("Check that the image, service account and resources in the Spark Kubernetes config are valid")
+	} else {
+		wrapped = fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("spark application failed: %s", status.ErrorMessage))
+		wrapped.AddFixSuggestion("Check the driver and executor pod logs for more information")
+	}
+	w.addDetails(wrapped)
+	wrapped.AddDetails("state", string(status.State), "driver_pod", status.DriverPodName)
+	if failed := status.FailedExecutors(); len(failed) > 0 {
+		wrapped.AddDetail("failed_executors", strings.Join(failed, ","))
+	}
+	return wrapped
+}
+
+// cancel deletes an application that exceeded the max wait duration to avoid having a long
+// running job that won't result in usable output to Featureform.
+func (w sparkApplicationWaiter) cancel() error {
+	if err := w.client.Delete(context.Background(), w.name); err != nil {
+		w.logger.Errorw("Could not cancel spark application", "error", err)
+		wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("could not cancel spark application that exceeded max wait duration: %w", err))
+		w.addDetails(wrapped)
+		return wrapped
+	}
+	w.logger.Errorw("Spark application exceeded max wait duration and was cancelled", "wait_duration", w.maxWait)
+	wrapped := fferr.NewExecutionError(pt.SparkOffline.String(), fmt.Errorf("spark application exceeded max wait duration and was cancelled"))
+	w.addDetails(wrapped)
+	wrapped.AddDetail("wait_duration", w.maxWait.String())
+	return wrapped
+}
+
+func (w sparkApplicationWaiter) addDetails(err *fferr.ExecutionError) {
+	err.AddDetails("executor_type", "Spark Kubernetes", "namespace", w.client.Namespace, "application_name", w.name)
+}
+
+// sparkKubernetesResumeID serializes into a ResumeID to be used via ResumeOption (a type of TransformationOption)
+type sparkKubernetesResumeID struct {
+	Namespace string
+	Name      string
+}
+
+// sparkKubernetesResumeIDRecordV0 becomes the actual JSON format of the ResumeID in the database.
+type sparkKubernetesResumeIDRecordV0 struct {
+	// SchemaVersion will make it easier to retain backwards compatibility in the future and do schema
+	// migration.
+	SchemaVersion int
+	Namespace     string
+	Name          string
+}
+
+func (rec sparkKubernetesResumeIDRecordV0) ToSparkKubernetesResumeID() *sparkKubernetesResumeID {
+	return &sparkKubernetesResumeID{
+		Namespace: rec.Namespace,
+		Name:      rec.Name,
+	}
+}
+
+func (resID *sparkKubernetesResumeID) Validate() error {
+	if resID.Namespace == "" {
+		return fferr.NewInternalErrorf("Spark Kubernetes Resume ID must have Namespace set: %v", resID)
+	}
+	if resID.Name == "" {
+		return fferr.NewInternalErrorf("Spark Kubernetes Resume ID must have Name set: %v", resID)
+	}
+	return nil
+}
+
+func (resID *sparkKubernetesResumeID) Marshal() (types.ResumeID, error) {
+	if err := resID.Validate(); err != nil {
+		return types.NilResumeID, err
+	}
+	record := sparkKubernetesResumeIDRecordV0{
+		// If you're changing the schema of the record, you should change the schema version and handle it in
+		// the deserialize method.
+		SchemaVersion: 0,
+		Namespace:     resID.Namespace,
+		Name:          resID.Name,
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fferr.NewInternalErrorf("Unable to serialize Spark Kubernetes resume ID: %s", err)
+	}
+	return types.ResumeID(data), nil
+}
+
+func deserializeSparkKubernetesResumeID(id types.ResumeID) (*sparkKubernetesResumeID, error) {
+	var record sparkKubernetesResumeIDRecordV0
+	if err := json.Unmarshal([]byte(id), &record); err != nil {
+		return nil, fferr.NewInternalErrorf("Unable to deserialize Spark Kubernetes resume ID: %s", err)
+	}
+	resID := record.ToSparkKubernetesResumeID()
+	if err := resID.Validate(); err != nil {
+		return nil, err
+	}
+	return resID, nil
+}
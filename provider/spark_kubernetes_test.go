@@ -0,0 +1,345 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/featureform/fferr"
+	fs "github.com/featureform/filestore"
+	"github.com/featureform/kubernetes"
+	"github.com/featureform/logging"
+	pc "github.com/featureform/provider/provider_config"
+	"github.com/featureform/provider/spark"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const sparkKubernetesTestNamespace = "featureform"
+
+// fakeSparkOperator plays the part of the Spark Operator. Each get of a SparkApplication returns
+// the next status in statuses, the last status is repeated.
+type fakeSparkOperator struct {
+	dynamic  *dynamicfake.FakeDynamicClient
+	mu       sync.Mutex
+	statuses []map[string]interface{}
+}
+
+func newFakeSparkOperator(statuses ...map[string]interface{}) *fakeSparkOperator {
+	operator := &fakeSparkOperator{
+		dynamic: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+			runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{kubernetes.SparkApplicationGVR: "SparkApplicationList"},
+		),
+		statuses: statuses,
+	}
+	operator.dynamic.PrependReactor("get", "sparkapplications", operator.get)
+	return operator
+}
+
+func (op *fakeSparkOperator) get(action k8stesting.Action) (bool, runtime.Object, error) {
+	name := action.(k8stesting.GetAction).GetName()
+	obj, err := op.dynamic.Tracker().Get(kubernetes.SparkApplicationGVR, action.GetNamespace(), name)
+	if err != nil {
+		return true, nil, err
+	}
+	app := obj.(*unstructured.Unstructured).DeepCopy()
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if len(op.statuses) > 0 {
+		app.Object["status"] = op.statuses[0]
+		if len(op.statuses) > 1 {
+			op.statuses = op.statuses[1:]
+		}
+	}
+	return true, app, nil
+}
+
+func (op *fakeSparkOperator) applications(t *testing.T) []unstructured.Unstructured {
+	list, err := op.dynamic.Tracker().List(kubernetes.SparkApplicationGVR, kubernetes.SparkApplicationGVR.GroupVersion().WithKind("SparkApplication"), sparkKubernetesTestNamespace)
+	if err != nil {
+		t.Fatalf("Failed to list spark applications: %s", err)
+	}
+	return list.(*unstructured.UnstructuredList).Items
+}
+
+func (op *fakeSparkOperator) executor(t *testing.T) *SparkKubernetesExecutor {
+	return &SparkKubernetesExecutor{
+		config: pc.SparkKubernetesConfig{
+			Image:             "featureform/spark:latest",
+			ServiceAccount:    "spark",
+			SparkVersion:      "3.5.1",
+			PythonVersion:     "3",
+			DriverMemory:      "1g",
+			ExecutorInstances: 2,
+		},
+		client: &kubernetes.SparkApplicationClient{
+			Dynamic:   op.dynamic,
+			Clientset: k8sfake.NewSimpleClientset(),
+			Namespace: sparkKubernetesTestNamespace,
+		},
+		logger:       logging.NewTestLogger(t),
+		pollInterval: time.Millisecond,
+	}
+}
+
+func sparkApplicationStatus(state kubernetes.SparkApplicationState, errMsg string, executors map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"applicationState": map[string]interface{}{"state": string(state), "errorMessage": errMsg},
+		"driverInfo":       map[string]interface{}{"podName": "driver-pod"},
+		"executorState":    executors,
+	}
+}
+
+type collectedRunLogs struct {
+	mu   sync.Mutex
+	logs []string
+}
+
+func (c *collectedRunLogs) add(msg string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logs = append(c.logs, msg)
+	return nil
+}
+
+func (c *collectedRunLogs) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return strings.Join(c.logs, "\n")
+}
+
+func newSparkKubernetesCmd() *spark.Command {
+	return &spark.Command{
+		Script:     &fs.LocalFilepath{},
+		ScriptArgs: []string{"sql"},
+		Configs:    spark.Configs{spark.HighMemoryFlags{}},
+	}
+}
+
+func TestSparkKubernetesExecutorResume(t *testing.T) {
+	jobOpts := SparkJobOptions{MaxJobDuration: time.Minute, JobName: "featureform-sql-transformation--name--variant"}
+	operator := newFakeSparkOperator(
+		sparkApplicationStatus(kubernetes.SparkApplicationSubmitted, "", nil),
+		sparkApplicationStatus(kubernetes.SparkApplicationRunning, "", nil),
+		sparkApplicationStatus(kubernetes.SparkApplicationCompleted, "", nil),
+	)
+	executor := operator.executor(t)
+	runLogs := &collectedRunLogs{}
+	asyncOpt := RunAsyncWithResume(time.Minute)
+	tfOpts := []TransformationOption{asyncOpt, WithRunLogs(runLogs.add)}
+	if err := executor.RunSparkJob(newSparkKubernetesCmd(), nil, jobOpts, tfOpts); err != nil {
+		t.Fatalf("Failed to run job: %s", err)
+	}
+	if err := asyncOpt.Wait(); err != nil {
+		t.Fatalf("Job failed: %s", err)
+	}
+	if !strings.Contains(runLogs.String(), "fake logs") {
+		t.Fatalf("Expected driver logs in run logs, got %q", runLogs.String())
+	}
+
+	apps := operator.applications(t)
+	if len(apps) != 1 {
+		t.Fatalf("Expected 1 spark application, found %d", len(apps))
+	}
+	app := apps[0]
+	if !strings.HasPrefix(app.GetName(), "featureform-sql-transformation--name--var-") {
+		t.Fatalf("Unexpected application name %s", app.GetName())
+	}
+	expectedFields := []struct {
+		path     []string
+		expected string
+	}{
+		{[]string{"apiVersion"}, "sparkoperator.k8s.io/v1beta2"},
+		{[]string{"spec", "image"}, "featureform/spark:latest"},
+		{[]string{"spec", "sparkVersion"}, "3.5.1"},
+		{[]string{"spec", "restartPolicy", "type"}, "Never"},
+		{[]string{"spec", "driver", "serviceAccount"}, "spark"},
+		{[]string{"spec", "driver", "memory"}, "1g"},
+		{[]string{"spec", "sparkConf", "spark.executor.memoryOverhead"}, "1g"},
+	}
+	for _, field := range expectedFields {
+		if actual, _, _ := unstructured.NestedString(app.Object, field.path...); actual != field.expected {
+			t.Fatalf("Expected %v to be %s, got %s", field.path, field.expected, actual)
+		}
+	}
+	if instances, _, _ := unstructured.NestedFloat64(app.Object, "spec", "executor", "instances"); instances != 2 {
+		t.Fatalf("Expected 2 executor instances, got %v", instances)
+	}
+	if args, _, _ := unstructured.NestedStringSlice(app.Object, "spec", "arguments"); len(args) != 1 || args[0] != "sql" {
+		t.Fatalf("Unexpected arguments %v", args)
+	}
+
+	resumeID, err := deserializeSparkKubernetesResumeID(asyncOpt.ResumeID())
+	if err != nil {
+		t.Fatalf("Failed to deserialize resume ID: %s", err)
+	}
+	expectedID := sparkKubernetesResumeID{Namespace: sparkKubernetesTestNamespace, Name: app.GetName()}
+	if *resumeID != expectedID {
+		t.Fatalf("Resume IDs don't match %v %v", expectedID, *resumeID)
+	}
+
+	resumeOpt, err := ResumeOptionWithID(asyncOpt.ResumeID(), time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to create resume option: %s", err)
+	}
+	if err := executor.RunSparkJob(newSparkKubernetesCmd(), nil, jobOpts, []TransformationOption{resumeOpt}); err != nil {
+		t.Fatalf("Failed to resume job: %s", err)
+	}
+	if err := resumeOpt.Wait(); err != nil {
+		t.Fatalf("Resumed job failed: %s", err)
+	}
+	if apps := operator.applications(t); len(apps) != 1 {
+		t.Fatalf("Expected resume to re-attach rather than create an application, found %d", len(apps))
+	}
+}
+
+func TestSparkKubernetesExecutorFailures(t *testing.T) {
+	jobOpts := SparkJobOptions{MaxJobDuration: time.Minute, JobName: "job"}
+	tests := []struct {
+		name            string
+		status          map[string]interface{}
+		expectedDetails map[string]string
+	}{
+		{
+			"Executor failure",
+			sparkApplicationStatus(kubernetes.SparkApplicationFailed, "driver container failed", map[string]interface{}{
+				"job-exec-1": "FAILED",
+				"job-exec-2": "COMPLETED",
+			}),
+			map[string]string{
+				"state":            "FAILED",
+				"driver_pod":       "driver-pod",
+				"failed_executors": "job-exec-1",
+			},
+		},
+		{
+			"Submission failure",
+			sparkApplicationStatus(kubernetes.SparkApplicationSubmissionFailed, "image pull failed", nil),
+			map[string]string{
+				"state": "SUBMISSION_FAILED",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := newFakeSparkOperator(tt.status)
+			err := operator.executor(t).RunSparkJob(newSparkKubernetesCmd(), nil, jobOpts, nil)
+			var execErr *fferr.ExecutionError
+			if !errors.As(err, &execErr) {
+				t.Fatalf("Expected execution error, got %T: %v", err, err)
+			}
+			details := execErr.Details()
+			for key, expected := range tt.expectedDetails {
+				if details[key] != expected {
+					t.Fatalf("Expected detail %s to be %s, got %s", key, expected, details[key])
+				}
+			}
+		})
+	}
+}
+
+func TestSparkKubernetesExecutorCancel(t *testing.T) {
+	t.Run("Max wait exceeded", func(t *testing.T) {
+		operator := newFakeSparkOperator(sparkApplicationStatus(kubernetes.SparkApplicationRunning, "", nil))
+		jobOpts := SparkJobOptions{MaxJobDuration: 10 * time.Millisecond, JobName: "job"}
+		err := operator.executor(t).RunSparkJob(newSparkKubernetesCmd(), nil, jobOpts, nil)
+		var execErr *fferr.ExecutionError
+		if !errors.As(err, &execErr) {
+			t.Fatalf("Expected execution error, got %T: %v", err, err)
+		}
+		if apps := operator.applications(t); len(apps) != 0 {
+			t.Fatalf("Expected application to be deleted, found %d", len(apps))
+		}
+	})
+
+	t.Run("Cancel by resume ID", func(t *testing.T) {
+		operator := newFakeSparkOperator(sparkApplicationStatus(kubernetes.SparkApplicationRunning, "", nil))
+		executor := operator.executor(t)
+		asyncOpt := RunAsyncWithResume(time.Minute)
+		jobOpts := SparkJobOptions{MaxJobDuration: time.Minute, JobName: "job"}
+		if err := executor.RunSparkJob(newSparkKubernetesCmd(), nil, jobOpts, []TransformationOption{asyncOpt}); err != nil {
+			t.Fatalf("Failed to run job: %s", err)
+		}
+		if err := executor.Cancel(asyncOpt.ResumeID()); err != nil {
+			t.Fatalf("Failed to cancel job: %s", err)
+		}
+		if err := asyncOpt.Wait(); err == nil {
+			t.Fatalf("Expected cancelled job to fail")
+		}
+		// Cancelling an application that's already gone is a no-op.
+		if err := executor.Cancel(asyncOpt.ResumeID()); err != nil {
+			t.Fatalf("Failed to cancel deleted job: %s", err)
+		}
+	})
+
+	t.Run("Cancel transformation from the offline store", func(t *testing.T) {
+		operator := newFakeSparkOperator(sparkApplicationStatus(kubernetes.SparkApplicationRunning, "", nil))
+		executor := operator.executor(t)
+		asyncOpt := RunAsyncWithResume(time.Minute)
+		jobOpts := SparkJobOptions{MaxJobDuration: time.Minute, JobName: "job"}
+		if err := executor.RunSparkJob(newSparkKubernetesCmd(), nil, jobOpts, []TransformationOption{asyncOpt}); err != nil {
+			t.Fatalf("Failed to run job: %s", err)
+		}
+		var store TransformationCanceller = &SparkOfflineStore{Executor: executor}
+		if err := store.CancelTransformation(asyncOpt.ResumeID()); err != nil {
+			t.Fatalf("Failed to cancel transformation: %s", err)
+		}
+		if apps := operator.applications(t); len(apps) != 0 {
+			t.Fatalf("Expected application to be deleted, found %d", len(apps))
+		}
+	})
+}
+
+func TestSparkKubernetesResumeID(t *testing.T) {
+	t.Run("Invalid IDs", func(t *testing.T) {
+		tests := []sparkKubernetesResumeID{
+			{},
+			{Namespace: "featureform"},
+			{Name: "job"},
+		}
+		for _, test := range tests {
+			id, err := test.Marshal()
+			if err == nil {
+				t.Fatalf("Succeeded to marshal %v into %s", test, id)
+			}
+		}
+	})
+
+	validID := sparkKubernetesResumeID{Namespace: "featureform", Name: "job-abc"}
+	resumeID, err := validID.Marshal()
+	if err != nil {
+		t.Fatalf("Failed to marshal %v: %s", validID, err)
+	}
+	parsedID, err := deserializeSparkKubernetesResumeID(resumeID)
+	if err != nil {
+		t.Fatalf("Failed to deserialize %s: %s", resumeID, err)
+	}
+	if validID != *parsedID {
+		t.Fatalf("IDs don't match %v %v", validID, *parsedID)
+	}
+}
+
+func TestSparkApplicationNotFound(t *testing.T) {
+	operator := newFakeSparkOperator()
+	client := operator.executor(t).client
+	_, err := client.GetStatus(context.Background(), "missing")
+	var notFound *fferr.JobDoesNotExistError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("Expected job does not exist error, got %T: %v", err, err)
+	}
+}
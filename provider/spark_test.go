@@ -1825,6 +1825,39 @@ func TestTrainingSetCreate(t *testing.T) {
 	}
 }
 
+func TestSpineTrainingSetCreate(t *testing.T) {
+	testTrainingSetDef := TrainingSetDef{
+		ID: ResourceID{"test_training_set", "default", TrainingSet},
+		Features: []ResourceID{
+			{"test_feature_1", "default", Feature},
+		},
+		Spine: &ResourceID{"users_to_score", "default", Primary},
+	}
+	testFeatureSchemas := []ResourceSchema{
+		{
+			Entity:         "entity",
+			Value:          "feature_value_1",
+			TS:             "ts",
+			EntityMappings: metadata.EntityMappings{Mappings: []metadata.EntityMapping{{Name: "user", EntityColumn: "entity"}}},
+		},
+	}
+	testSpineSchema := ResourceSchema{
+		EntityMappings: metadata.EntityMappings{Mappings: []metadata.EntityMapping{{Name: "user", EntityColumn: "user_id"}}, TimestampColumn: "scored_at"},
+	}
+	queries := defaultPythonOfflineQueries{}
+	trainingSetQuery := queries.trainingSetCreate(testTrainingSetDef, testFeatureSchemas, testSpineSchema)
+
+	correctQuery := "SELECT `Feature__test_feature_1__default` FROM (SELECT * FROM (SELECT `Feature__test_feature_1__default`, entity, label_ts, " +
+		"spine_row_id, t1_ts, ROW_NUMBER() over (PARTITION BY spine_row_id ORDER BY t1_ts DESC) as row_number FROM (SELECT user_id AS entity, " +
+		"scored_at AS label_ts, monotonically_increasing_id() AS spine_row_id FROM source_0) t0 LEFT OUTER JOIN (SELECT * FROM (SELECT entity as " +
+		"t1_entity, feature_value_1 as `Feature__test_feature_1__default`, ts as t1_ts FROM source_1) ORDER BY t1_ts ASC) t1 ON (t1_entity = entity " +
+		"AND t1_ts <= label_ts)) WHERE row_number=1) ORDER BY label_ts"
+
+	if trainingSetQuery != correctQuery {
+		t.Fatalf("training set query not correct, got %s, expected %s", trainingSetQuery, correctQuery)
+	}
+}
+
 // func TestCompareStructsFail(t *testing.T) {
 // 	t.Parallel()
 // 	type testStruct struct {
@@ -3732,6 +3765,238 @@ func TestEmrResumeID(t *testing.T) {
 	}
 }
 
+func TestDatabricksResumeID(t *testing.T) {
+	t.Run("Invalid IDs", func(t *testing.T) {
+		tests := []databricksResumeID{
+			{},
+			{JobID: 1},
+			{RunID: 1},
+		}
+		for _, test := range tests {
+			id, err := test.Marshal()
+			if err == nil {
+				t.Fatalf("Succeeded to marshal %v into %s", test, id)
+			}
+		}
+	})
+
+	validID := databricksResumeID{JobID: 1, RunID: 2}
+	resumeID, err := validID.Marshal()
+	if err != nil {
+		t.Fatalf("Failed to marshal %v: %s", validID, err)
+	}
+	parsedID, err := deserializeDatabricksResumeID(resumeID)
+	if err != nil {
+		t.Fatalf("Failed to deserialize %v: %s", validID, err)
+	}
+	if validID != *parsedID {
+		t.Fatalf("IDs don't match %v %v", validID, *parsedID)
+	}
+	if _, err := deserializeDatabricksResumeID(types.ResumeID("not-json")); err == nil {
+		t.Fatalf("Succeeded to deserialize invalid resume ID")
+	}
+}
+
+func TestDataprocResumeID(t *testing.T) {
+	t.Run("Invalid IDs", func(t *testing.T) {
+		tests := []dataprocResumeID{
+			{},
+			{RequestID: "abc"},
+			{OperationName: "abc"},
+		}
+		for _, test := range tests {
+			id, err := test.Marshal()
+			if err == nil {
+				t.Fatalf("Succeeded to marshal %v into %s", test, id)
+			}
+		}
+	})
+
+	validID := dataprocResumeID{RequestID: "featureform-job-abc", OperationName: "projects/p/regions/r/operations/o"}
+	resumeID, err := validID.Marshal()
+	if err != nil {
+		t.Fatalf("Failed to marshal %v: %s", validID, err)
+	}
+	if parsedID := deserializeDataprocResumeID(resumeID); validID != *parsedID {
+		t.Fatalf("IDs don't match %v %v", validID, *parsedID)
+	}
+
+	// Resume IDs written before the versioned record were the bare request ID.
+	legacy := deserializeDataprocResumeID(types.ResumeID("featureform-job-abc"))
+	expectedLegacy := dataprocResumeID{RequestID: "featureform-job-abc"}
+	if *legacy != expectedLegacy {
+		t.Fatalf("Legacy IDs don't match %v %v", expectedLegacy, *legacy)
+	}
+}
+
+func TestSparkGenericResumeID(t *testing.T) {
+	t.Run("Invalid IDs", func(t *testing.T) {
+		tests := []sparkGenericResumeID{
+			{},
+			{Master: "yarn"},
+			{SubmissionID: "application_1_1"},
+		}
+		for _, test := range tests {
+			id, err := test.Marshal()
+			if err == nil {
+				t.Fatalf("Succeeded to marshal %v into %s", test, id)
+			}
+		}
+	})
+
+	validID := sparkGenericResumeID{Master: "yarn", SubmissionID: "application_1700000000000_0001"}
+	resumeID, err := validID.Marshal()
+	if err != nil {
+		t.Fatalf("Failed to marshal %v: %s", validID, err)
+	}
+	parsedID, err := deserializeSparkGenericResumeID(resumeID)
+	if err != nil {
+		t.Fatalf("Failed to deserialize %v: %s", validID, err)
+	}
+	if validID != *parsedID {
+		t.Fatalf("IDs don't match %v %v", validID, *parsedID)
+	}
+}
+
+func TestParseSparkSubmissionID(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+		wantErr  bool
+	}{
+		{"yarn", "INFO Client: Submitted application application_1700000000000_0042\n", "application_1700000000000_0042", false},
+		{"standalone", `"submissionId" : "driver-20240101120000-0003",`, "driver-20240101120000-0003", false},
+		{"missing", "Exception in thread main", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := parseSparkSubmissionID(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSparkSubmissionID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if id != tt.expected {
+				t.Fatalf("Expected %s, got %s", tt.expected, id)
+			}
+		})
+	}
+}
+
+func TestParseSparkDriverState(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected sparkDriverState
+		wantErr  bool
+	}{
+		{"yarn running", "State : RUNNING\nFinal-State : UNDEFINED\n", sparkDriverRunning, false},
+		{"yarn succeeded", "State : FINISHED\nFinal-State : SUCCEEDED\n", sparkDriverSucceeded, false},
+		{"yarn failed", "State : FINISHED\nFinal-State : FAILED\n", sparkDriverFailed, false},
+		{"yarn killed before final state", "\tFinal-State : UNDEFINED\n\tState : KILLED\n", sparkDriverFailed, false},
+		{"standalone running", `"driverState" : "RUNNING",`, sparkDriverRunning, false},
+		{"standalone finished", `"driverState" : "FINISHED",`, sparkDriverSucceeded, false},
+		{"standalone error", `"driverState" : "ERROR",`, sparkDriverFailed, false},
+		{"unknown", "connection refused", sparkDriverRunning, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := parseSparkDriverState(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSparkDriverState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if state != tt.expected {
+				t.Fatalf("Expected %v, got %v", tt.expected, state)
+			}
+		})
+	}
+}
+
+// fakeSparkCommands records the commands the generic executor runs and answers
+// spark-submit and status commands in order.
+type fakeSparkCommands struct {
+	commands []string
+	statuses []string
+}
+
+func (f *fakeSparkCommands) run(command string) (string, string, error) {
+	f.commands = append(f.commands, command)
+	if strings.Contains(command, "--status") || strings.Contains(command, "yarn application -status") {
+		status := f.statuses[0]
+		if len(f.statuses) > 1 {
+			f.statuses = f.statuses[1:]
+		}
+		return status, "", nil
+	}
+	return "", "INFO Client: Submitted application application_1700000000000_0001", nil
+}
+
+func TestSparkGenericExecutorResume(t *testing.T) {
+	newExecutor := func(fake *fakeSparkCommands) *SparkGenericExecutor {
+		return &SparkGenericExecutor{
+			master:       "yarn",
+			logger:       logging.NewTestLogger(t),
+			runCommand:   fake.run,
+			pollInterval: time.Millisecond,
+		}
+	}
+	newCmd := func(mode types.SparkDeployMode) *spark.Command {
+		return &spark.Command{
+			Script:  &fs.LocalFilepath{},
+			Configs: spark.Configs{spark.DeployFlag{Mode: mode}},
+		}
+	}
+	jobOpts := SparkJobOptions{MaxJobDuration: time.Minute, JobName: "testResume"}
+
+	t.Run("Submit and resume", func(t *testing.T) {
+		fake := &fakeSparkCommands{statuses: []string{"Final-State : UNDEFINED", "Final-State : SUCCEEDED"}}
+		executor := newExecutor(fake)
+		asyncOpt := RunAsyncWithResume(time.Minute)
+		if err := executor.RunSparkJob(newCmd(types.SparkClusterDeployMode), nil, jobOpts, []TransformationOption{asyncOpt}); err != nil {
+			t.Fatalf("Failed to run job: %s", err)
+		}
+		if err := asyncOpt.Wait(); err != nil {
+			t.Fatalf("Job failed: %s", err)
+		}
+		if !strings.Contains(fake.commands[0], "spark.yarn.submit.waitAppCompletion=false") {
+			t.Fatalf("Expected detached submit, got %s", fake.commands[0])
+		}
+
+		resumeFake := &fakeSparkCommands{statuses: []string{"Final-State : SUCCEEDED"}}
+		resumeOpt, err := ResumeOptionWithID(asyncOpt.ResumeID(), time.Minute)
+		if err != nil {
+			t.Fatalf("Failed to create resume option: %s", err)
+		}
+		if err := newExecutor(resumeFake).RunSparkJob(newCmd(types.SparkClusterDeployMode), nil, jobOpts, []TransformationOption{resumeOpt}); err != nil {
+			t.Fatalf("Failed to resume job: %s", err)
+		}
+		if err := resumeOpt.Wait(); err != nil {
+			t.Fatalf("Resumed job failed: %s", err)
+		}
+		for _, cmd := range resumeFake.commands {
+			if strings.Contains(cmd, "spark-submit") {
+				t.Fatalf("Resume re-submitted the job: %s", cmd)
+			}
+		}
+		if !strings.Contains(resumeFake.commands[0], "application_1700000000000_0001") {
+			t.Fatalf("Resume didn't poll the original application: %s", resumeFake.commands[0])
+		}
+	})
+
+	t.Run("Client mode isn't resumable", func(t *testing.T) {
+		fake := &fakeSparkCommands{}
+		asyncOpt := RunAsyncWithResume(time.Minute)
+		if err := newExecutor(fake).RunSparkJob(newCmd(types.SparkClientDeployMode), nil, jobOpts, []TransformationOption{asyncOpt}); err != nil {
+			t.Fatalf("Failed to run job: %s", err)
+		}
+		if err := asyncOpt.Wait(); err != nil {
+			t.Fatalf("Job failed: %s", err)
+		}
+		if asyncOpt.IsResumeIDSet() {
+			t.Fatalf("Client mode job set resume ID %s", asyncOpt.ResumeID())
+		}
+	})
+}
+
 func randomStringNBytes(size int, t *testing.T) string {
 	randomBytes := make([]byte, size)
 	_, err := rand.Read(randomBytes)
@@ -77,11 +77,13 @@ type OfflineTableQueries interface {
 	trainingSetUpdate(store *sqlOfflineStore, def TrainingSetDef, tableName string, labelName string) error
 	trainingRowSelect(columns string, trainingSetName string) string
 	trainingRowSplitSelect(columns string, trainingSetSplitName string) (string, string)
+	trainTestSplitHash(exprs []string, seed int) string
 	castTableItemType(v interface{}, t interface{}) interface{}
 	getValueColumnType(t *sql.ColumnType) interface{}
 	numRows(n interface{}) (int64, error)
 	transformationCreate(name string, query string) []string
 	transformationUpdate(db *sql.DB, tableName string, query string) error
+	transformationIncrementalUpdate(db *sql.DB, tableName string, query string, config IncrementalConfig) error
 	transformationExists() string // this isn't used anywhere should I still keep it
 	resourceTableColumns(obj pl.FullyQualifiedObject) (string, error)
 }
@@ -259,7 +261,11 @@ func (store *sqlOfflineStore) Close() error {
 }
 
 func (store *sqlOfflineStore) CheckHealth() (bool, error) {
-	err := store.db.Ping()
+	return store.CheckHealthContext(context.Background())
+}
+
+func (store *sqlOfflineStore) CheckHealthContext(ctx context.Context) (bool, error) {
+	err := store.db.PingContext(ctx)
 	if err != nil {
 		wrapped := fferr.NewConnectionError(store.Type().String(), err)
 		wrapped.AddDetail("action", "ping")
@@ -1068,6 +1074,17 @@ func (store *sqlOfflineStore) CreateTrainingSet(def TrainingSetDef) error {
 	if postgresQueries, ok := store.query.(*postgresSQLQueries); ok {
 		return postgresQueries.trainingSetCreate(store, def, tableName, "")
 	}
+	if len(def.AdditionalLabels) > 0 {
+		return fferr.NewInvalidArgumentErrorf("%s does not support training sets with multiple labels", store.Type())
+	}
+	// The remaining SQL providers join against the label's resource table, so dialects that
+	// support spines read the spine's table in the same shape instead.
+	if def.Spine != nil {
+		if _, ok := store.query.(spineTrainingSetQueries); !ok {
+			return fferr.NewInvalidArgumentErrorf("%s does not support training sets with a spine", store.Type())
+		}
+		return store.query.trainingSetCreate(store, def, tableName, "")
+	}
 
 	label, err := store.getsqlResourceTable(def.Label)
 	if err != nil {
@@ -1084,6 +1101,26 @@ func (store *sqlOfflineStore) UpdateTrainingSet(def TrainingSetDef) error {
 	if err := def.check(); err != nil {
 		return err
 	}
+	if _, ok := store.query.(*postgresSQLQueries); !ok && len(def.AdditionalLabels) > 0 {
+		return fferr.NewInvalidArgumentErrorf("%s does not support training sets with multiple labels", store.Type())
+	}
+	if def.Spine != nil {
+		if postgresQueries, ok := store.query.(*postgresSQLQueries); ok {
+			tableName, err := store.getTrainingSetName(def.ID)
+			if err != nil {
+				return err
+			}
+			return postgresQueries.trainingSetUpdate(store, def, tableName, "")
+		}
+		if _, ok := store.query.(spineTrainingSetQueries); !ok {
+			return fferr.NewInvalidArgumentErrorf("%s does not support training sets with a spine", store.Type())
+		}
+		tableName, err := store.getTrainingSetName(def.ID)
+		if err != nil {
+			return err
+		}
+		return store.query.trainingSetUpdate(store, def, tableName, "")
+	}
 	label, err := store.getsqlResourceTable(def.Label)
 	if err != nil {
 		return err
@@ -1100,6 +1137,12 @@ func (store *sqlOfflineStore) UpdateTrainingSet(def TrainingSetDef) error {
 }
 
 func (store *sqlOfflineStore) GetTrainingSet(id ResourceID) (dataset.TrainingSetIterator, error) {
+	return store.getTrainingSet(id, store.query.trainingRowSelect)
+}
+
+// getTrainingSet serves a training set's rows using selectQuery, which selects the columns of the
+// training set's table. Stores use it to read a training set as of a snapshot.
+func (store *sqlOfflineStore) getTrainingSet(id ResourceID, selectQuery func(columns string, trainingSetName string) string) (dataset.TrainingSetIterator, error) {
 	logger := store.logger.WithResource(logging.TrainingSetVariant, id.Name, id.Variant)
 	logger.Debugw("Getting training set")
 	if err := id.check(TrainingSet); err != nil {
@@ -1128,7 +1171,7 @@ func (store *sqlOfflineStore) GetTrainingSet(id ResourceID) (dataset.TrainingSet
 		features = append(features, sanitize(name.Name))
 	}
 	columns := strings.Join(features[:], ", ")
-	trainingSetQry := store.query.trainingRowSelect(columns, trainingSetName)
+	trainingSetQry := selectQuery(columns, trainingSetName)
 	store.logger.Debugw("Training Set Query", "query", trainingSetQry)
 	rows, err := store.db.Query(trainingSetQry)
 	if err != nil {
@@ -1145,12 +1188,98 @@ func (store *sqlOfflineStore) GetTrainingSet(id ResourceID) (dataset.TrainingSet
 	return NewLegacyTrainingSetIteratorAdapter(iter), nil
 }
 
+// trainTestSplitColumns returns the name of the training set's table and its columns.
+func (store *sqlOfflineStore) trainTestSplitColumns(def TrainTestSplitDef) (string, []string, error) {
+	id := ResourceID{Name: def.TrainingSetName, Variant: def.TrainingSetVariant, Type: TrainingSet}
+	if exists, err := store.tableExistsForResourceId(id); err != nil {
+		return "", nil, err
+	} else if !exists {
+		return "", nil, fferr.NewDatasetNotFoundError(id.Name, id.Variant, nil)
+	}
+	trainingSetName, err := store.getTrainingSetName(id)
+	if err != nil {
+		return "", nil, err
+	}
+	tableColumns, err := store.query.getColumns(store.db, trainingSetName)
+	if err != nil {
+		return "", nil, err
+	}
+	columns := make([]string, len(tableColumns))
+	for i, column := range tableColumns {
+		columns[i] = column.Name
+	}
+	return trainingSetName, columns, nil
+}
+
 func (store *sqlOfflineStore) CreateTrainTestSplit(def TrainTestSplitDef) (func() error, error) {
-	return nil, fmt.Errorf("not Implemented")
+	logger := store.logger.WithResource(logging.TrainingSetVariant, def.TrainingSetName, def.TrainingSetVariant)
+	if err := def.check(); err != nil {
+		return nil, err
+	}
+	trainingSetName, columns, err := store.trainTestSplitColumns(def)
+	if err != nil {
+		logger.Errorw("Error getting training set columns", "error", err)
+		return nil, err
+	}
+	keyColumn, err := trainTestSplitKeyColumn(def, columns)
+	if err != nil {
+		return nil, err
+	}
+	var total int
+	if err := store.db.QueryRow(trainTestSplitCountQuery(def, trainingSetName, keyColumn)).Scan(&total); err != nil {
+		wrapped := fferr.NewResourceExecutionError(store.Type().String(), def.TrainingSetName, def.TrainingSetVariant, fferr.TRAINING_SET_VARIANT, err)
+		wrapped.AddDetail("table_name", trainingSetName)
+		return nil, wrapped
+	}
+	splitName := trainTestSplitName(trainingSetName, def)
+	query := trainTestSplitViewQuery(store.query, def, trainingSetName, splitName, columns, keyColumn, total, def.seed())
+	logger.Debugw("Creating train test split", "query", query)
+	if _, err := store.db.Exec(query); err != nil {
+		wrapped := fferr.NewResourceExecutionError(store.Type().String(), def.TrainingSetName, def.TrainingSetVariant, fferr.TRAINING_SET_VARIANT, err)
+		wrapped.AddDetail("table_name", splitName)
+		return nil, wrapped
+	}
+	dropFunc := func() error {
+		if _, err := store.db.Exec(fmt.Sprintf("DROP VIEW IF EXISTS %s", sanitize(splitName))); err != nil {
+			wrapped := fferr.NewExecutionError(store.Type().String(), err)
+			wrapped.AddDetail("table_name", splitName)
+			return wrapped
+		}
+		return nil
+	}
+	return dropFunc, nil
 }
 
 func (store *sqlOfflineStore) GetTrainTestSplit(def TrainTestSplitDef) (dataset.TrainingSetIterator, dataset.TrainingSetIterator, error) {
-	return nil, nil, fmt.Errorf("not Implemented")
+	logger := store.logger.WithResource(logging.TrainingSetVariant, def.TrainingSetName, def.TrainingSetVariant)
+	trainingSetName, columns, err := store.trainTestSplitColumns(def)
+	if err != nil {
+		logger.Errorw("Error getting training set columns", "error", err)
+		return nil, nil, err
+	}
+	sanitized := make([]string, len(columns))
+	for i, column := range columns {
+		sanitized[i] = sanitize(column)
+	}
+	splitName := trainTestSplitName(trainingSetName, def)
+	train, test := store.query.trainingRowSplitSelect(strings.Join(sanitized, ", "), splitName)
+	colTypes, err := store.getValueColumnTypes(trainingSetName)
+	if err != nil {
+		logger.Errorw("Error getting column types", "error", err, "training_set_name", trainingSetName)
+		return nil, nil, err
+	}
+	trainRows, err := store.db.Query(train)
+	if err != nil {
+		return nil, nil, fferr.NewResourceExecutionError(store.Type().String(), def.TrainingSetName, def.TrainingSetVariant, fferr.TRAINING_SET_VARIANT, err)
+	}
+	testRows, err := store.db.Query(test)
+	if err != nil {
+		trainRows.Close()
+		return nil, nil, fferr.NewResourceExecutionError(store.Type().String(), def.TrainingSetName, def.TrainingSetVariant, fferr.TRAINING_SET_VARIANT, err)
+	}
+	trainIter := store.newsqlTrainingSetIterator(trainRows, colTypes)
+	testIter := store.newsqlTrainingSetIterator(testRows, colTypes)
+	return NewLegacyTrainingSetIteratorAdapter(trainIter), NewLegacyTrainingSetIteratorAdapter(testIter), nil
 }
 
 // getValueColumnTypes returns a list of column types. Columns consist of feature and label values
@@ -1227,7 +1356,10 @@ func (it *sqlTrainingRowsIterator) Next() bool {
 		return false
 	}
 	var label interface{}
-	numFeatures := len(columnNames) - 1
+	numFeatures := len(columnNames)
+	if trainingSetHasLabel(columnNames) {
+		numFeatures--
+	}
 	featureVals := make([]interface{}, numFeatures)
 	for i, value := range values {
 		if value == nil {
@@ -1299,6 +1431,16 @@ func (table *SqlPrimaryTable) GetLocation() pl.Location {
 	return table.sqlLocation
 }
 
+// readSchema reads the table's column types from the database's catalog. Providers without a
+// value converter fall back to column names only.
+func (table *SqlPrimaryTable) readSchema() (fftypes.Schema, error) {
+	converter, err := pt.GetConverter(table.providerType)
+	if err != nil {
+		return readColumnNames(table)
+	}
+	return table.query.getSchema(table.db, converter, *table.sqlLocation)
+}
+
 func (table *SqlPrimaryTable) Write(rec GenericRecord) error {
 	tb := sanitize(table.name)
 	columns := table.getColumnNameString()
@@ -1533,6 +1675,12 @@ func (store *sqlOfflineStore) UpdateTransformation(config TransformationConfig,
 	if err != nil {
 		return err
 	}
+	if config.Incremental != nil {
+		if err := config.Incremental.check(); err != nil {
+			return err
+		}
+		return store.query.transformationIncrementalUpdate(store.db, name, config.Query, *config.Incremental)
+	}
 	err = store.query.transformationUpdate(store.db, name, config.Query)
 	if err != nil {
 		return err
@@ -1889,8 +2037,18 @@ func (q defaultOfflineSQLQueries) trainingRowSelect(columns string, trainingSetN
 }
 
 func (q defaultOfflineSQLQueries) trainingRowSplitSelect(columns string, trainingSetSplitName string) (string, string) {
-	// throw unimiplemented error
-	return "", ""
+	train := fmt.Sprintf("SELECT %s FROM %s WHERE %s = 0", columns, sanitize(trainingSetSplitName), trainTestSplitTestColumn)
+	test := fmt.Sprintf("SELECT %s FROM %s WHERE %s = 1", columns, sanitize(trainingSetSplitName), trainTestSplitTestColumn)
+	return train, test
+}
+
+// trainTestSplitHash returns an expression hashing the values of exprs together with the seed.
+func (q defaultOfflineSQLQueries) trainTestSplitHash(exprs []string, seed int) string {
+	parts := make([]string, len(exprs))
+	for i, expr := range exprs {
+		parts[i] = fmt.Sprintf("COALESCE(CAST(%s AS VARCHAR), '')", expr)
+	}
+	return fmt.Sprintf("MD5(%s || '|%d')", strings.Join(parts, " || '|' || "), seed)
 }
 func (q defaultOfflineSQLQueries) getValueColumnTypes(tableName string) string {
 	return fmt.Sprintf("SELECT * FROM %s", sanitize(tableName))
@@ -2008,6 +2166,40 @@ func (q defaultOfflineSQLQueries) trainingSetQuery(store *sqlOfflineStore, def T
 	return nil
 }
 
+// trainingSetHasLabel reports whether a training set's columns end with its label. Training sets
+// built from a spine only have feature columns, none of which are named label.
+func trainingSetHasLabel(columnNames []string) bool {
+	return len(columnNames) > 0 && strings.EqualFold(columnNames[len(columnNames)-1], "label")
+}
+
+// spineTrainingSetQueries is implemented by SQL dialects whose training set queries can read a
+// spine in place of the label's resource table.
+type spineTrainingSetQueries interface {
+	spineLabelQuery(def TrainingSetDef) (string, error)
+}
+
+// spineLabelQuery selects a training set's spine in the shape of a label resource table, with an
+// empty value column cast to nullValueType. Spines without a timestamp column use maxTimestamp so
+// that they're joined with the latest feature values.
+func spineLabelQuery(def TrainingSetDef, nullValueType, maxTimestamp string) (string, error) {
+	loc, ok := def.LabelSourceMapping.Location.(*pl.SQLLocation)
+	if !ok {
+		return "", fferr.NewInternalErrorf("spine location is not an SQL location, actual %T", def.LabelSourceMapping.Location)
+	}
+	mappings := def.LabelSourceMapping.EntityMappings
+	if len(mappings.Mappings) != 1 {
+		return "", fferr.NewInvalidArgumentErrorf("training set spine must map exactly one entity, got %d", len(mappings.Mappings))
+	}
+	ts := maxTimestamp
+	if mappings.TimestampColumn != "" {
+		ts = sanitize(mappings.TimestampColumn)
+	}
+	return fmt.Sprintf(
+		"SELECT %s AS entity, CAST(NULL AS %s) AS value, %s AS ts FROM %s",
+		sanitize(mappings.Mappings[0].EntityColumn), nullValueType, ts, SanitizeFullyQualifiedObject(loc.TableLocation()),
+	), nil
+}
+
 func (q defaultOfflineSQLQueries) atomicUpdate(db *sql.DB, tableName string, tempName string, query string) error {
 	sanitizedTable := sanitize(tableName)
 	transaction := fmt.Sprintf(
@@ -4,7 +4,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+
+	fftypes "github.com/featureform/fftypes"
+	pl "github.com/featureform/provider/location"
+	pt "github.com/featureform/provider/provider_type"
+	"github.com/featureform/scheduling"
 )
 
 func TestDefaultCastTableItemType(t *testing.T) {
@@ -121,3 +127,49 @@ func TestDefaultCastTableItemType(t *testing.T) {
 		})
 	}
 }
+
+func TestSqlPrimaryTableSchemaDetectsRetypedColumn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock db: %v", err)
+	}
+	defer db.Close()
+	table := &PrimaryTableToDatasetAdapter{pt: &SqlPrimaryTable{
+		db:           db,
+		name:         "transactions",
+		sqlLocation:  pl.NewSQLLocationFromParts("ff", "public", "transactions"),
+		query:        &defaultOfflineSQLQueries{},
+		providerType: pt.PostgresOffline,
+	}}
+	expectColumns := func(amountType string) {
+		mock.ExpectQuery("FROM information_schema.columns WHERE table_name = 'transactions' AND table_schema = 'public'").
+			WillReturnRows(sqlmock.NewRows([]string{"column_name", "data_type"}).
+				AddRow("id", "character varying").
+				AddRow("amount", amountType))
+	}
+
+	expectColumns("integer")
+	registered, err := ReadSchema(table)
+	if err != nil {
+		t.Fatalf("Failed to read registered schema: %v", err)
+	}
+	assert.Equal(t, fftypes.Int32, registered.Fields[1].Type)
+	assert.Equal(t, "integer", registered.Fields[1].NativeType.TypeName())
+	contract, err := scheduling.NewSchemaContract(registered).Schema()
+	if err != nil {
+		t.Fatalf("Failed to round trip schema contract: %v", err)
+	}
+
+	expectColumns("character varying")
+	live, err := ReadSchema(table)
+	if err != nil {
+		t.Fatalf("Failed to read live schema: %v", err)
+	}
+	drift := contract.Drift(live)
+	if len(drift.Retyped) != 1 || drift.Retyped[0].Name != "amount" || len(drift.Added) != 0 || len(drift.Removed) != 0 {
+		t.Fatalf("Expected only amount to be retyped, got %s", drift.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("Expected the schema to be read from the catalog: %v", err)
+	}
+}
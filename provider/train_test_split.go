@@ -0,0 +1,251 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/featureform/fferr"
+)
+
+// TrainTestSplitType chooses how the rows of a training set are dealt into train and test.
+type TrainTestSplitType int
+
+const (
+	// RandomSplit puts TestSize of the rows, chosen by a shuffle seeded with RandomState, in test.
+	RandomSplit TrainTestSplitType = iota
+	// TimeSplit puts rows at or after SplitTime in test.
+	TimeSplit
+	// EntitySplit deals whole entities into train or test, so no entity appears in both.
+	EntitySplit
+)
+
+func (t TrainTestSplitType) String() string {
+	switch t {
+	case RandomSplit:
+		return "random"
+	case TimeSplit:
+		return "time"
+	case EntitySplit:
+		return "entity"
+	default:
+		return fmt.Sprintf("TrainTestSplitType(%d)", int(t))
+	}
+}
+
+const trainTestSplitTestColumn = "is_test"
+
+func (def TrainTestSplitDef) check() error {
+	if def.TrainingSetName == "" {
+		return fferr.NewInvalidArgumentErrorf("train test split must have a training set name")
+	}
+	if def.TestSize < 0 || def.TestSize > 1 {
+		return fferr.NewInvalidArgumentErrorf("train test split test size must be between 0 and 1, got %v", def.TestSize)
+	}
+	if def.Folds < 0 {
+		return fferr.NewInvalidArgumentErrorf("train test split folds must not be negative, got %d", def.Folds)
+	}
+	if def.Folds > 1 && (def.Fold < 0 || def.Fold >= def.Folds) {
+		return fferr.NewInvalidArgumentErrorf("train test split fold %d is not in [0, %d)", def.Fold, def.Folds)
+	}
+	switch def.Type {
+	case RandomSplit, EntitySplit:
+	case TimeSplit:
+		if def.Folds > 1 {
+			return fferr.NewInvalidArgumentErrorf("time based train test splits don't support folds")
+		}
+	default:
+		return fferr.NewInvalidArgumentErrorf("unknown train test split type %s", def.Type)
+	}
+	return nil
+}
+
+// seed returns the seed used to shuffle the split. Like scikit-learn, a RandomState of 0
+// picks a new split every time.
+func (def TrainTestSplitDef) seed() int {
+	if def.Shuffle && def.RandomState == 0 {
+		return rand.Int()
+	}
+	return def.RandomState
+}
+
+func (def TrainTestSplitDef) kFold() bool {
+	return def.Folds > 1
+}
+
+// testCount is the number of rows, or entities, that go in test out of total.
+func (def TrainTestSplitDef) testCount(total int) int {
+	return int(float32(total) * def.TestSize)
+}
+
+// isTest reports whether the row or entity at position pos, counting from 0, of total is in test.
+func (def TrainTestSplitDef) isTest(pos, total int) bool {
+	if def.kFold() {
+		return pos%def.Folds == def.Fold
+	}
+	return pos < def.testCount(total)
+}
+
+// split deals the rows into train and test. Rows are first put in entity then timestamp order,
+// so the same def and seed always give the same split, and keep the order they were dealt in.
+func (rows trainingRows) split(def TrainTestSplitDef, seed int) (trainingRows, trainingRows) {
+	sorted := make(trainingRows, len(rows))
+	copy(sorted, rows)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Entity != sorted[j].Entity {
+			return sorted[i].Entity < sorted[j].Entity
+		}
+		return sorted[i].TS.Before(sorted[j].TS)
+	})
+	shuffle := func(n int, swap func(i, j int)) {
+		if def.Shuffle {
+			rand.New(rand.NewSource(int64(seed))).Shuffle(n, swap)
+		}
+	}
+
+	train, test := make(trainingRows, 0), make(trainingRows, 0)
+	deal := func(row trainingRow, isTest bool) {
+		if isTest {
+			test = append(test, row)
+		} else {
+			train = append(train, row)
+		}
+	}
+	switch def.Type {
+	case RandomSplit:
+		shuffle(len(sorted), func(i, j int) { sorted[i], sorted[j] = sorted[j], sorted[i] })
+		for pos, row := range sorted {
+			deal(row, def.isTest(pos, len(sorted)))
+		}
+	case EntitySplit:
+		entities := make([]string, 0)
+		rowsByEntity := make(map[string]trainingRows)
+		for _, row := range sorted {
+			if _, ok := rowsByEntity[row.Entity]; !ok {
+				entities = append(entities, row.Entity)
+			}
+			rowsByEntity[row.Entity] = append(rowsByEntity[row.Entity], row)
+		}
+		shuffle(len(entities), func(i, j int) { entities[i], entities[j] = entities[j], entities[i] })
+		for pos, entity := range entities {
+			isTest := def.isTest(pos, len(entities))
+			for _, row := range rowsByEntity[entity] {
+				deal(row, isTest)
+			}
+		}
+	case TimeSplit:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].TS.Before(sorted[j].TS)
+		})
+		for pos, row := range sorted {
+			if def.SplitTime.IsZero() {
+				// The latest rows are at the end, so count positions from there.
+				deal(row, def.isTest(len(sorted)-1-pos, len(sorted)))
+			} else {
+				deal(row, !row.TS.Before(def.SplitTime))
+			}
+		}
+	}
+	return train, test
+}
+
+// trainTestSplitName returns the name of the view holding the split. Every field of the def is
+// part of the name, so different splits of the same training set don't collide, and the
+// request ID is added as a suffix so the same split requested twice at once doesn't either.
+func trainTestSplitName(trainingSetName string, def TrainTestSplitDef) string {
+	h := fnv.New32a()
+	h.Write([]byte(fmt.Sprintf("%+v", def)))
+	name := fmt.Sprintf("%s_split_%08x", trainingSetName, h.Sum32())
+	if def.RequestID != "" {
+		name = fmt.Sprintf("%s_%s", name, strings.ReplaceAll(def.RequestID, "-", ""))
+	}
+	return name
+}
+
+// trainTestSplitKeyColumn returns the training set column the split is keyed on, if any.
+func trainTestSplitKeyColumn(def TrainTestSplitDef, columns []string) (string, error) {
+	var column string
+	switch def.Type {
+	case TimeSplit:
+		if def.TimestampColumn == "" {
+			return "", fferr.NewInvalidArgumentErrorf("time based train test splits need a timestamp column")
+		}
+		column = def.TimestampColumn
+	case EntitySplit:
+		if def.EntityColumn == "" {
+			return "", fferr.NewInvalidArgumentErrorf("entity based train test splits need an entity column")
+		}
+		column = def.EntityColumn
+	default:
+		return "", nil
+	}
+	for _, c := range columns {
+		if c == column {
+			return column, nil
+		}
+	}
+	return "", fferr.NewInvalidArgumentErrorf("training set %s (%s) has no column %s", def.TrainingSetName, def.TrainingSetVariant, column)
+}
+
+// trainTestSplitCountQuery counts what the split deals out: rows, or entities for EntitySplit.
+func trainTestSplitCountQuery(def TrainTestSplitDef, trainingSetName, keyColumn string) string {
+	if def.Type == EntitySplit {
+		return fmt.Sprintf("SELECT COUNT(DISTINCT %s) FROM %s", sanitize(keyColumn), sanitize(trainingSetName))
+	}
+	return fmt.Sprintf("SELECT COUNT(*) FROM %s", sanitize(trainingSetName))
+}
+
+// trainTestSplitViewQuery creates a view of the training set with an is_test column. Rows are
+// ranked, by a seeded hash when shuffling, and the rank decides which side they land on, the
+// same way as trainingRows.split.
+func trainTestSplitViewQuery(q OfflineTableQueries, def TrainTestSplitDef, trainingSetName, splitName string, columns []string, keyColumn string, total, seed int) string {
+	sanitized := make([]string, len(columns))
+	for i, c := range columns {
+		sanitized[i] = sanitize(c)
+	}
+	columnStr := strings.Join(sanitized, ", ")
+
+	var rank string
+	switch def.Type {
+	case RandomSplit:
+		if def.Shuffle {
+			rank = fmt.Sprintf("ROW_NUMBER() OVER (ORDER BY %s, %s)", q.trainTestSplitHash(sanitized, seed), columnStr)
+		} else {
+			rank = fmt.Sprintf("ROW_NUMBER() OVER (ORDER BY %s)", columnStr)
+		}
+	case EntitySplit:
+		key := sanitize(keyColumn)
+		if def.Shuffle {
+			rank = fmt.Sprintf("DENSE_RANK() OVER (ORDER BY %s, %s)", q.trainTestSplitHash([]string{key}, seed), key)
+		} else {
+			rank = fmt.Sprintf("DENSE_RANK() OVER (ORDER BY %s)", key)
+		}
+	case TimeSplit:
+		rank = fmt.Sprintf("ROW_NUMBER() OVER (ORDER BY %s DESC, %s)", sanitize(keyColumn), columnStr)
+	}
+
+	var isTest string
+	switch {
+	case def.Type == TimeSplit && !def.SplitTime.IsZero():
+		// A bare literal since MySQL can't CAST to TIMESTAMP; every dialect coerces it to the column's type.
+		isTest = fmt.Sprintf("%s >= '%s'", sanitize(keyColumn), def.SplitTime.UTC().Format("2006-01-02 15:04:05.999999"))
+	case def.kFold():
+		isTest = fmt.Sprintf("MOD(ff_split_rank - 1, %d) = %d", def.Folds, def.Fold)
+	default:
+		isTest = fmt.Sprintf("ff_split_rank <= %d", def.testCount(total))
+	}
+
+	return fmt.Sprintf(
+		"CREATE VIEW %s AS SELECT %s, CASE WHEN %s THEN 1 ELSE 0 END AS %s FROM (SELECT %s, %s AS ff_split_rank FROM %s) ranked",
+		sanitize(splitName), columnStr, isTest, trainTestSplitTestColumn, columnStr, rank, sanitize(trainingSetName),
+	)
+}
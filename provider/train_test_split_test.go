@@ -0,0 +1,510 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package provider
+
+import (
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/provider/dataset"
+	"github.com/featureform/provider/types"
+)
+
+// splitTestTrainingSet creates a training set with 4 rows for each of 5 entities, a day apart.
+func splitTestTrainingSet(t *testing.T, store OfflineStore) ResourceID {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	features := make([]ResourceRecord, 0)
+	labels := make([]ResourceRecord, 0)
+	for _, entity := range []string{"a", "b", "c", "d", "e"} {
+		for day := 0; day < 4; day++ {
+			ts := start.AddDate(0, 0, day)
+			features = append(features, ResourceRecord{Entity: entity, Value: day, TS: ts})
+			labels = append(labels, ResourceRecord{Entity: entity, Value: day%2 == 0, TS: ts})
+		}
+	}
+	featureID := randomID(Feature)
+	featureTable, err := store.CreateResourceTable(featureID, TableSchema{Columns: []TableColumn{
+		{Name: "entity", ValueType: types.String},
+		{Name: "value", ValueType: types.Int},
+		{Name: "ts", ValueType: types.Timestamp},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create table: %s", err)
+	}
+	if err := featureTable.WriteBatch(features); err != nil {
+		t.Fatalf("Failed to write batch: %s", err)
+	}
+	labelID := randomID(Label)
+	labelTable, err := store.CreateResourceTable(labelID, TableSchema{Columns: []TableColumn{
+		{Name: "entity", ValueType: types.String},
+		{Name: "value", ValueType: types.Bool},
+		{Name: "ts", ValueType: types.Timestamp},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to create table: %s", err)
+	}
+	if err := labelTable.WriteBatch(labels); err != nil {
+		t.Fatalf("Failed to write batch: %s", err)
+	}
+	def := TrainingSetDef{ID: randomID(TrainingSet), Label: labelID, Features: []ResourceID{featureID}}
+	if err := store.CreateTrainingSet(def); err != nil {
+		t.Fatalf("Failed to create training set: %s", err)
+	}
+	return def.ID
+}
+
+// splitTestRows creates and reads the split, returning the feature values of each side.
+func splitTestRows(t *testing.T, store OfflineStore, def TrainTestSplitDef) ([]interface{}, []interface{}) {
+	cleanup, err := store.CreateTrainTestSplit(def)
+	if err != nil {
+		t.Fatalf("Failed to create train test split: %s", err)
+	}
+	defer cleanup()
+	train, test, err := store.GetTrainTestSplit(def)
+	if err != nil {
+		t.Fatalf("Failed to get train test split: %s", err)
+	}
+	values := func(iter dataset.TrainingSetIterator) []interface{} {
+		vals := make([]interface{}, 0)
+		for iter.Next() {
+			vals = append(vals, iter.Features().GetRawValues()[0])
+		}
+		return vals
+	}
+	return values(train), values(test)
+}
+
+func TestMemoryTrainTestSplitTypes(t *testing.T) {
+	store := NewMemoryOfflineStore()
+	id := splitTestTrainingSet(t, store)
+	base := TrainTestSplitDef{TrainingSetName: id.Name, TrainingSetVariant: id.Variant}
+
+	t.Run("Time", func(t *testing.T) {
+		def := base
+		def.Type = TimeSplit
+		def.SplitTime = time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+		train, test := splitTestRows(t, store, def)
+		if len(train) != 10 || len(test) != 10 {
+			t.Fatalf("Expected 10 train and 10 test rows, got %d and %d", len(train), len(test))
+		}
+		for _, day := range train {
+			if day.(int) >= 2 {
+				t.Fatalf("Row from day %d is after the split time but in train", day)
+			}
+		}
+		for _, day := range test {
+			if day.(int) < 2 {
+				t.Fatalf("Row from day %d is before the split time but in test", day)
+			}
+		}
+	})
+
+	t.Run("TimeLatestRows", func(t *testing.T) {
+		def := base
+		def.Type = TimeSplit
+		def.TestSize = 0.25
+		_, test := splitTestRows(t, store, def)
+		if len(test) != 5 {
+			t.Fatalf("Expected 5 test rows, got %d", len(test))
+		}
+		for _, day := range test {
+			if day.(int) != 3 {
+				t.Fatalf("Expected only the last day in test, got day %d", day)
+			}
+		}
+	})
+
+	entitySplit := func(t *testing.T, def TrainTestSplitDef) (map[string]bool, map[string]bool) {
+		cleanup, err := store.CreateTrainTestSplit(def)
+		if err != nil {
+			t.Fatalf("Failed to create train test split: %s", err)
+		}
+		defer cleanup()
+		data, _ := store.trainTestSplits.Load(def)
+		split := data.([2]trainingRows)
+		entities := func(rows trainingRows) map[string]bool {
+			set := make(map[string]bool)
+			for _, row := range rows {
+				set[row.Entity] = true
+			}
+			return set
+		}
+		return entities(split[0]), entities(split[1])
+	}
+
+	t.Run("Entity", func(t *testing.T) {
+		def := base
+		def.Type = EntitySplit
+		def.TestSize = 0.4
+		def.Shuffle = true
+		def.RandomState = 7
+		train, test := entitySplit(t, def)
+		if len(train) != 3 || len(test) != 2 {
+			t.Fatalf("Expected 3 train and 2 test entities, got %v and %v", train, test)
+		}
+		for entity := range test {
+			if train[entity] {
+				t.Fatalf("Entity %s is in both train and test", entity)
+			}
+		}
+		train2, test2 := entitySplit(t, def)
+		if len(train2) != len(train) || len(test2) != len(test) {
+			t.Fatalf("Same random state gave different splits")
+		}
+		for entity := range test {
+			if !test2[entity] {
+				t.Fatalf("Same random state gave different splits: %v and %v", test, test2)
+			}
+		}
+	})
+
+	t.Run("KFold", func(t *testing.T) {
+		seen := make(map[string]int)
+		for fold := 0; fold < 5; fold++ {
+			def := base
+			def.Type = EntitySplit
+			def.Shuffle = true
+			def.RandomState = 3
+			def.Folds = 5
+			def.Fold = fold
+			train, test := entitySplit(t, def)
+			if len(test) != 1 || len(train) != 4 {
+				t.Fatalf("Expected 1 test and 4 train entities in fold %d, got %v and %v", fold, test, train)
+			}
+			for entity := range test {
+				seen[entity]++
+			}
+		}
+		if len(seen) != 5 {
+			t.Fatalf("Expected every entity to be tested once, got %v", seen)
+		}
+	})
+
+	t.Run("RandomKFold", func(t *testing.T) {
+		total := 0
+		for fold := 0; fold < 3; fold++ {
+			def := base
+			def.Shuffle = true
+			def.RandomState = 11
+			def.Folds = 3
+			def.Fold = fold
+			train, test := splitTestRows(t, store, def)
+			if len(train)+len(test) != 20 {
+				t.Fatalf("Expected 20 rows in fold %d, got %d", fold, len(train)+len(test))
+			}
+			total += len(test)
+		}
+		if total != 20 {
+			t.Fatalf("Expected every row to be tested once, got %d test rows", total)
+		}
+	})
+}
+
+func TestMemoryTrainTestSplitRecreated(t *testing.T) {
+	store := NewMemoryOfflineStore()
+	id := splitTestTrainingSet(t, store)
+	def := TrainTestSplitDef{TrainingSetName: id.Name, TrainingSetVariant: id.Variant, TestSize: 0.5, Shuffle: true, RandomState: 5}
+	cleanup, err := store.CreateTrainTestSplit(def)
+	if err != nil {
+		t.Fatalf("Failed to create train test split: %s", err)
+	}
+	created, _ := store.trainTestSplits.Load(def)
+	if err := cleanup(); err != nil {
+		t.Fatalf("Failed to clean up train test split: %s", err)
+	}
+	// Another store, or the same one after cleanup, makes the same split again.
+	train, test, err := store.GetTrainTestSplit(def)
+	if err != nil {
+		t.Fatalf("Failed to get train test split: %s", err)
+	}
+	for i, iter := range []dataset.TrainingSetIterator{train, test} {
+		rows := created.([2]trainingRows)[i]
+		n := 0
+		for iter.Next() {
+			if iter.Label().Value != rows[n].Label {
+				t.Fatalf("Row %d of split %d differs from the created split", n, i)
+			}
+			n++
+		}
+		if n != len(rows) {
+			t.Fatalf("Expected %d rows in split %d, got %d", len(rows), i, n)
+		}
+	}
+	def.TrainingSetName = "missing"
+	if _, err := store.CreateTrainTestSplit(def); err == nil {
+		t.Fatalf("Expected an error splitting a missing training set")
+	}
+}
+
+func TestTrainTestSplitDefCheck(t *testing.T) {
+	valid := TrainTestSplitDef{TrainingSetName: "ts", TestSize: 0.2}
+	invalid := map[string]TrainTestSplitDef{
+		"NoName":           {TestSize: 0.2},
+		"TestSizeTooBig":   {TrainingSetName: "ts", TestSize: 1.5},
+		"NegativeTestSize": {TrainingSetName: "ts", TestSize: -0.1},
+		"NegativeFolds":    {TrainingSetName: "ts", Folds: -1},
+		"FoldOutOfRange":   {TrainingSetName: "ts", Folds: 3, Fold: 3},
+		"TimeFolds":        {TrainingSetName: "ts", Type: TimeSplit, Folds: 3},
+		"UnknownType":      {TrainingSetName: "ts", Type: TrainTestSplitType(9)},
+	}
+	if err := valid.check(); err != nil {
+		t.Fatalf("Expected valid def, got %s", err)
+	}
+	for name, def := range invalid {
+		t.Run(name, func(t *testing.T) {
+			err := def.check()
+			var invalidArg *fferr.InvalidArgumentError
+			if !errors.As(err, &invalidArg) {
+				t.Fatalf("Expected InvalidArgumentError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTrainTestSplitViewQuery(t *testing.T) {
+	columns := []string{"feature__avg__v1", "entity_id", "ts", "label"}
+	def := TrainTestSplitDef{TrainingSetName: "ts", TestSize: 0.25, Shuffle: true, RandomState: 42}
+
+	query := trainTestSplitViewQuery(&postgresSQLQueries{}, def, "training_set", "split", columns, "", 100, def.seed())
+	expected := `CREATE VIEW "split" AS SELECT "feature__avg__v1", "entity_id", "ts", "label", CASE WHEN ff_split_rank <= 25 THEN 1 ELSE 0 END AS is_test FROM ` +
+		`(SELECT "feature__avg__v1", "entity_id", "ts", "label", ROW_NUMBER() OVER (ORDER BY MD5(COALESCE(CAST("feature__avg__v1" AS VARCHAR), '') || '|' || ` +
+		`COALESCE(CAST("entity_id" AS VARCHAR), '') || '|' || COALESCE(CAST("ts" AS VARCHAR), '') || '|' || COALESCE(CAST("label" AS VARCHAR), '') || '|42'), ` +
+		`"feature__avg__v1", "entity_id", "ts", "label") AS ff_split_rank FROM "training_set") ranked`
+	if query != expected {
+		t.Fatalf("Unexpected query\nexpected: %s\n     got: %s", expected, query)
+	}
+
+	entityDef := def
+	entityDef.Type = EntitySplit
+	entityDef.EntityColumn = "entity_id"
+	entityDef.Folds = 4
+	entityDef.Fold = 1
+	query = trainTestSplitViewQuery(&mySQLQueries{}, entityDef, "training_set", "split", columns, "entity_id", 10, entityDef.seed())
+	for _, part := range []string{
+		`DENSE_RANK() OVER (ORDER BY MD5(CONCAT_WS('|', "entity_id", '42')), "entity_id")`,
+		"CASE WHEN MOD(ff_split_rank - 1, 4) = 1 THEN 1 ELSE 0 END",
+	} {
+		if !strings.Contains(query, part) {
+			t.Fatalf("Expected query to contain %s, got %s", part, query)
+		}
+	}
+
+	timeDef := TrainTestSplitDef{TrainingSetName: "ts", Type: TimeSplit, TimestampColumn: "ts", SplitTime: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+	query = trainTestSplitViewQuery(&postgresSQLQueries{}, timeDef, "training_set", "split", columns, "ts", 10, timeDef.seed())
+	if !strings.Contains(query, `CASE WHEN "ts" >= '2025-03-01 12:00:00' THEN 1 ELSE 0 END`) {
+		t.Fatalf("Expected the split time in the query, got %s", query)
+	}
+}
+
+func TestTrainTestSplitKeyColumn(t *testing.T) {
+	columns := []string{"feature__avg__v1", "entity_id", "label"}
+	if _, err := trainTestSplitKeyColumn(TrainTestSplitDef{Type: EntitySplit, EntityColumn: "missing"}, columns); err == nil {
+		t.Fatalf("Expected an error for a missing entity column")
+	}
+	if _, err := trainTestSplitKeyColumn(TrainTestSplitDef{Type: TimeSplit}, columns); err == nil {
+		t.Fatalf("Expected an error for an unset timestamp column")
+	}
+	column, err := trainTestSplitKeyColumn(TrainTestSplitDef{Type: EntitySplit, EntityColumn: "entity_id"}, columns)
+	if err != nil || column != "entity_id" {
+		t.Fatalf("Expected entity_id, got %s, %v", column, err)
+	}
+	if name := trainTestSplitName("training_set", TrainTestSplitDef{TestSize: 0.2}); name == trainTestSplitName("training_set", TrainTestSplitDef{TestSize: 0.3}) {
+		t.Fatalf("Expected different splits to get different view names, both got %s", name)
+	}
+	first := TrainTestSplitDef{TestSize: 0.2, RequestID: "5b8e2c1e-1f0a-4f5e-9a43-1b2c3d4e5f60"}
+	second := TrainTestSplitDef{TestSize: 0.2, RequestID: "0f6d9a7b-2c3e-4d5f-8a9b-c0d1e2f3a4b5"}
+	if name := trainTestSplitName("training_set", first); !strings.HasSuffix(name, "_5b8e2c1e1f0a4f5e9a431b2c3d4e5f60") {
+		t.Fatalf("Expected the request ID as a suffix, got %s", name)
+	}
+	if name := trainTestSplitName("training_set", first); name == trainTestSplitName("training_set", second) {
+		t.Fatalf("Expected concurrent requests for the same split to get different view names, both got %s", name)
+	}
+}
+
+var registerSplitTestDriver sync.Once
+
+// openSplitTestDB opens an in-memory SQLite database with a 20 row training set: 4 rows, a day
+// apart, for each of 5 entities. SQLite doesn't have MD5 or MOD, so they're added to run the
+// queries the warehouses would.
+func openSplitTestDB(t *testing.T) *sql.DB {
+	registerSplitTestDriver.Do(func() {
+		sql.Register("sqlite3_train_test_split", &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				md5Hex := func(s string) string {
+					sum := md5.Sum([]byte(s))
+					return hex.EncodeToString(sum[:])
+				}
+				if err := conn.RegisterFunc("md5", md5Hex, true); err != nil {
+					return err
+				}
+				return conn.RegisterFunc("mod", func(a, b int64) int64 { return a % b }, true)
+			},
+		})
+	})
+	db, err := sql.Open("sqlite3_train_test_split", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	// Every connection gets its own in-memory database.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`CREATE TABLE "training_set" ("feature__avg__v1" INTEGER, "entity_id" TEXT, "ts" TEXT, "label" INTEGER)`); err != nil {
+		t.Fatalf("Failed to create training set: %v", err)
+	}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, entity := range []string{"a", "b", "c", "d", "e"} {
+		for day := 0; day < 4; day++ {
+			ts := start.AddDate(0, 0, day).Format("2006-01-02 15:04:05")
+			if _, err := db.Exec(`INSERT INTO "training_set" VALUES (?, ?, ?, ?)`, day, entity, ts, day%2); err != nil {
+				t.Fatalf("Failed to insert row: %v", err)
+			}
+		}
+	}
+	return db
+}
+
+// runSQLSplit creates the split view the way sqlOfflineStore does and reads both sides of it
+// back as entity@timestamp keys.
+func runSQLSplit(t *testing.T, db *sql.DB, def TrainTestSplitDef) ([]string, []string) {
+	columns := []string{"feature__avg__v1", "entity_id", "ts", "label"}
+	keyColumn, err := trainTestSplitKeyColumn(def, columns)
+	if err != nil {
+		t.Fatalf("Failed to get key column: %v", err)
+	}
+	var total int
+	if err := db.QueryRow(trainTestSplitCountQuery(def, "training_set", keyColumn)).Scan(&total); err != nil {
+		t.Fatalf("Failed to count: %v", err)
+	}
+	splitName := trainTestSplitName("training_set", def)
+	queries := &postgresSQLQueries{}
+	if _, err := db.Exec(trainTestSplitViewQuery(queries, def, "training_set", splitName, columns, keyColumn, total, def.seed())); err != nil {
+		t.Fatalf("Failed to create split: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(fmt.Sprintf("DROP VIEW IF EXISTS %s", sanitize(splitName))) })
+
+	read := func(query string) []string {
+		rows, err := db.Query(query)
+		if err != nil {
+			t.Fatalf("Failed to read split: %v", err)
+		}
+		defer rows.Close()
+		keys := make([]string, 0)
+		for rows.Next() {
+			var entity, ts string
+			if err := rows.Scan(&entity, &ts); err != nil {
+				t.Fatalf("Failed to scan row: %v", err)
+			}
+			keys = append(keys, entity+"@"+ts)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatalf("Failed to read split: %v", err)
+		}
+		return keys
+	}
+	train, test := queries.trainingRowSplitSelect(`"entity_id", "ts"`, splitName)
+	return read(train), read(test)
+}
+
+func TestSQLTrainTestSplitRuns(t *testing.T) {
+	checkPartition := func(t *testing.T, train, test []string, testSize int) {
+		if len(test) != testSize || len(train)+len(test) != 20 {
+			t.Fatalf("Expected %d test rows out of 20, got %d train and %d test", testSize, len(train), len(test))
+		}
+		seen := make(map[string]bool)
+		for _, key := range append(append([]string{}, train...), test...) {
+			if seen[key] {
+				t.Fatalf("Expected %s on one side of the split only", key)
+			}
+			seen[key] = true
+		}
+	}
+	entity := func(key string) string {
+		return strings.Split(key, "@")[0]
+	}
+
+	t.Run("random", func(t *testing.T) {
+		db := openSplitTestDB(t)
+		def := TrainTestSplitDef{TrainingSetName: "ts", TestSize: 0.25, Shuffle: true, RandomState: 42, RequestID: "first"}
+		train, test := runSQLSplit(t, db, def)
+		checkPartition(t, train, test, 5)
+		def.RequestID = "second"
+		_, again := runSQLSplit(t, db, def)
+		if strings.Join(test, ",") != strings.Join(again, ",") {
+			t.Fatalf("Expected the same seed to give the same split, got %v and %v", test, again)
+		}
+	})
+	t.Run("entity", func(t *testing.T) {
+		db := openSplitTestDB(t)
+		def := TrainTestSplitDef{TrainingSetName: "ts", Type: EntitySplit, EntityColumn: "entity_id", TestSize: 0.4, Shuffle: true, RandomState: 7}
+		train, test := runSQLSplit(t, db, def)
+		checkPartition(t, train, test, 8)
+		testEntities := make(map[string]bool)
+		for _, key := range test {
+			testEntities[entity(key)] = true
+		}
+		for _, key := range train {
+			if testEntities[entity(key)] {
+				t.Fatalf("Expected entity %s on one side of the split only", entity(key))
+			}
+		}
+	})
+	t.Run("time", func(t *testing.T) {
+		db := openSplitTestDB(t)
+		def := TrainTestSplitDef{TrainingSetName: "ts", Type: TimeSplit, TimestampColumn: "ts", SplitTime: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)}
+		train, test := runSQLSplit(t, db, def)
+		checkPartition(t, train, test, 10)
+		for _, key := range test {
+			if !strings.HasSuffix(key, "2025-01-03 00:00:00") && !strings.HasSuffix(key, "2025-01-04 00:00:00") {
+				t.Fatalf("Expected only rows from the split time on in test, got %s", key)
+			}
+		}
+	})
+	t.Run("k-fold", func(t *testing.T) {
+		db := openSplitTestDB(t)
+		seen := make(map[string]int)
+		for fold := 0; fold < 4; fold++ {
+			def := TrainTestSplitDef{TrainingSetName: "ts", Shuffle: true, RandomState: 3, Folds: 4, Fold: fold}
+			train, test := runSQLSplit(t, db, def)
+			checkPartition(t, train, test, 5)
+			for _, key := range test {
+				seen[key]++
+			}
+		}
+		if len(seen) != 20 {
+			t.Fatalf("Expected every row to be in exactly one test fold, got %v", seen)
+		}
+	})
+	t.Run("concurrent requests", func(t *testing.T) {
+		db := openSplitTestDB(t)
+		def := TrainTestSplitDef{TrainingSetName: "ts", TestSize: 0.25, RequestID: "first"}
+		runSQLSplit(t, db, def)
+		other := def
+		other.RequestID = "second"
+		// Without the request ID the second view would have the same name and fail to create.
+		runSQLSplit(t, db, other)
+		if _, err := db.Exec(fmt.Sprintf("DROP VIEW %s", sanitize(trainTestSplitName("training_set", def)))); err != nil {
+			t.Fatalf("Failed to drop the first split: %v", err)
+		}
+		train, test := (&postgresSQLQueries{}).trainingRowSplitSelect(`"entity_id"`, trainTestSplitName("training_set", other))
+		for _, query := range []string{train, test} {
+			if _, err := db.Exec(query); err != nil {
+				t.Fatalf("Expected the second split to outlive the first: %v", err)
+			}
+		}
+	})
+}
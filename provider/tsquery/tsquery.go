@@ -34,6 +34,21 @@ type BuilderParams struct {
 	SanitizedFeatureTables []string
 	FeatureNameVariants    []metadata.ResourceID
 	FeatureEntityNames     []string
+	// IsSpine is true when the label table is an entity spine without a value column,
+	// in which case the training set only contains feature columns.
+	IsSpine bool
+	// Additional labels are joined like features but their columns are aliased as labels
+	// and come after all feature columns.
+	AdditionalLabelColumns         []metadata.ResourceVariantColumns
+	SanitizedAdditionalLabelTables []string
+	AdditionalLabelNameVariants    []metadata.ResourceID
+	AdditionalLabelEntityNames     []string
+	// The snapshot fields hold table expressions that read a pinned snapshot of a table, e.g.
+	// `"LABELS" AT(TIMESTAMP => ...)`; when set, they're used in place of the table's name. The
+	// slices are either empty or have an entry, possibly empty, for every table.
+	LabelTableSnapshot            string
+	FeatureTableSnapshots         []string
+	AdditionalLabelTableSnapshots []string
 }
 
 // NewTrainingSet creates a new training set query builder based on the label and feature columns provided.
@@ -41,6 +56,8 @@ func NewTrainingSet(config QueryConfig, params BuilderParams) *TrainingSet {
 	lbtTable := labelTable{
 		SanitizedTableName: params.SanitizedLabelTable,
 		EntityMappings:     params.LabelEntityMappings,
+		IsSpine:            params.IsSpine,
+		Snapshot:           params.LabelTableSnapshot,
 	}
 
 	featureTables := make([]featureTable, len(params.FeatureColumns))
@@ -52,8 +69,21 @@ func NewTrainingSet(config QueryConfig, params BuilderParams) *TrainingSet {
 			SanitizedTableName: params.SanitizedFeatureTables[i],
 			ColumnAliases:      []string{fmt.Sprintf("feature__%s__%s", params.FeatureNameVariants[i].Name, params.FeatureNameVariants[i].Variant)},
 			EntityName:         params.FeatureEntityNames[i],
+			Snapshot:           snapshotAt(params.FeatureTableSnapshots, i),
 		}
 	}
+	for i, cols := range params.AdditionalLabelColumns {
+		featureTables = append(featureTables, featureTable{
+			Entity:             cols.Entity,
+			Values:             []string{cols.Value},
+			TS:                 cols.TS,
+			SanitizedTableName: params.SanitizedAdditionalLabelTables[i],
+			ColumnAliases:      []string{fmt.Sprintf("label__%s__%s", params.AdditionalLabelNameVariants[i].Name, params.AdditionalLabelNameVariants[i].Variant)},
+			EntityName:         params.AdditionalLabelEntityNames[i],
+			IsLabel:            true,
+			Snapshot:           snapshotAt(params.AdditionalLabelTableSnapshots, i),
+		})
+	}
 
 	return &TrainingSet{
 		labelTable:    lbtTable,
@@ -62,6 +92,13 @@ func NewTrainingSet(config QueryConfig, params BuilderParams) *TrainingSet {
 	}
 }
 
+func snapshotAt(snapshots []string, i int) string {
+	if i >= len(snapshots) {
+		return ""
+	}
+	return snapshots[i]
+}
+
 // TrainingSet represents a training set query builder.
 type TrainingSet struct {
 	labelTable    labelTable
@@ -76,15 +113,59 @@ type featureTable struct {
 	SanitizedTableName string
 	ColumnAliases      []string
 	EntityName         string
+	// IsLabel is true for additional labels, which are joined the same way as features.
+	IsLabel bool
+	// Snapshot, if set, is the table expression that reads the pinned snapshot of the table.
+	Snapshot string
+}
+
+// TableSQL returns how the table is referenced in FROM and JOIN clauses.
+func (ft featureTable) TableSQL() string {
+	if ft.Snapshot != "" {
+		return ft.Snapshot
+	}
+	return ft.SanitizedTableName
 }
 
 type labelTable struct {
 	SanitizedTableName string
 	EntityMappings     *metadata.EntityMappings
+	IsSpine            bool
+	// Snapshot, if set, is the table expression that reads the pinned snapshot of the table.
+	Snapshot string
+}
+
+// TableSQL returns how the table is referenced in FROM clauses.
+func (l labelTable) TableSQL(config QueryConfig) string {
+	if l.Snapshot != "" {
+		return l.Snapshot
+	}
+	if config.QuoteTable {
+		return config.QuoteChar + l.SanitizedTableName + config.QuoteChar
+	}
+	return l.SanitizedTableName
+}
+
+// ValueColumn returns the label's value column, or an empty string if the table is a spine.
+func (l labelTable) ValueColumn() string {
+	if l.IsSpine {
+		return ""
+	}
+	return l.EntityMappings.ValueColumn
+}
+
+// LabelSelectSQL returns the label column for the SELECT clause, if there is one.
+func (l labelTable) LabelSelectSQL() string {
+	if l.IsSpine {
+		return ""
+	}
+	return fmt.Sprintf(", l.%s AS label", l.EntityMappings.ValueColumn)
 }
 
 type featureTableMap map[string]*featureTable
 
+// Keys returns the sorted keys of the feature tables followed by the sorted keys of
+// the additional label tables, so that label columns always come after feature columns.
 func (ftm featureTableMap) Keys() []string {
 	keys := make([]string, len(ftm))
 	i := 0
@@ -92,10 +173,27 @@ func (ftm featureTableMap) Keys() []string {
 		keys[i] = k
 		i++
 	}
-	sort.Strings(keys)
+	sort.Slice(keys, func(i, j int) bool {
+		iLabel, jLabel := ftm[keys[i]].IsLabel, ftm[keys[j]].IsLabel
+		if iLabel != jLabel {
+			return jLabel
+		}
+		return keys[i] < keys[j]
+	})
 	return keys
 }
 
+// entityColumn returns the label or spine column that the table is joined on.
+func (l labelTable) entityColumn(ft *featureTable) (string, error) {
+	for _, m := range l.EntityMappings.Mappings {
+		if m.Name == ft.EntityName {
+			return m.EntityColumn, nil
+		}
+	}
+	logging.GlobalLogger.Errorw("no label entity mapping for table", "entity", ft.EntityName, "feature_table", ft)
+	return "", fferr.NewInvalidArgumentErrorf("entity %s of %s is not mapped to a column of the label or spine", ft.EntityName, strings.Join(ft.ColumnAliases, ", "))
+}
+
 // CompileSQL compiles the label and feature tables into a single SQL query
 // that uses LEFT JOINs, ASOF JOINs and/or CTEs to create a training set
 // based on the presence or absence of timestamps in the label and feature tables.
@@ -189,6 +287,8 @@ func (j asOfJoins) ToSQL(config QueryConfig) string {
 // join doesn't exist. Instead, we emulate it by manually filtering for entries
 // based on their timestamp, and grab the most recent one.
 type windowJoin struct {
+	// index matches the alias of the table's columns in the SELECT clause.
+	index      int
 	entity     string
 	ts         string
 	label      string
@@ -212,13 +312,15 @@ func (j windowJoin) FeatureSQL(index int) string {
   SELECT
     %s as ts,
     l.%s,
-    l.label,
-
 `,
 		index,
 		j.ft.TS,
 		j.entity,
 	))
+	if j.label != "" {
+		sb.WriteString("    l.label,\n")
+	}
+	sb.WriteString("\n")
 
 	for i := range j.ft.Values {
 		sb.WriteString(fmt.Sprintf("    %s,\n", j.ft.Values[i]))
@@ -237,7 +339,7 @@ func (j windowJoin) FeatureSQL(index int) string {
 		j.entity,
 		index,
 		j.ft.TS,
-		j.ft.SanitizedTableName,
+		j.ft.TableSQL(),
 		index,
 		j.entity,
 		index,
@@ -251,12 +353,14 @@ feature_%d_filtered AS (
   SELECT
     ts,
     %s,
-    label,
-
 `,
 		index,
 		j.entity,
 	))
+	if j.label != "" {
+		sb.WriteString("    label,\n")
+	}
+	sb.WriteString("\n")
 
 	joins := make([]string, len(j.ft.Values))
 	for i := range j.ft.Values {
@@ -286,38 +390,40 @@ func (j windowJoins) HeaderSQL(config QueryConfig) string {
 		return ""
 	}
 
-	quoteChar := ""
-	if config.QuoteTable {
-		quoteChar = config.QuoteChar
-	}
-
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf(`WITH labels AS (
   SELECT
     %s as ts,
-    %s AS label,
 `,
 		j.ts,
-		j.label,
 	))
+	if j.label != "" {
+		sb.WriteString(fmt.Sprintf("    %s AS label,\n", j.label))
+	}
 
-	joins := make([]string, len(j.windows))
-	for i, join := range j.windows {
-		joins[i] = "    " + join.EntitySQL(config)
+	// Several windows can join on the same entity column, but it's only selected once.
+	entities := make([]string, 0, len(j.windows))
+	seen := make(map[string]bool)
+	for _, join := range j.windows {
+		entity := join.EntitySQL(config)
+		if seen[entity] {
+			continue
+		}
+		seen[entity] = true
+		entities = append(entities, "    "+entity)
 	}
-	sb.WriteString(strings.Join(joins, ",\n"))
+	sb.WriteString(strings.Join(entities, ",\n"))
 
 	sb.WriteString(fmt.Sprintf(`
-  FROM %s%s%s
+  FROM %s
 ),
 `,
-		quoteChar,
 		j.labelTable,
-		quoteChar,
 	))
 
+	joins := make([]string, len(j.windows))
 	for i, j := range j.windows {
-		joins[i] = j.FeatureSQL(i + 1)
+		joins[i] = j.FeatureSQL(j.index)
 	}
 	sb.WriteString(strings.Join(joins, ",\n"))
 	sb.WriteString("\n")
@@ -331,16 +437,16 @@ func (j windowJoins) SelectSQL(config QueryConfig) string {
 
 	var sb strings.Builder
 
-	for i, window := range j.windows {
+	for _, window := range j.windows {
 		sb.WriteString(fmt.Sprintf(`
 LEFT JOIN feature_%d_filtered f%d
   ON f%d.ts = l.%s
   AND f%d.%s = l.%s`,
-			i+1,
-			i+1,
-			i+1,
+			window.index,
+			window.index,
+			window.index,
 			window.ts,
-			i+1,
+			window.index,
 			window.entity,
 			window.entity,
 		))
@@ -361,7 +467,7 @@ type cte struct {
 func (c cte) ToSQL() string {
 	selectClause := fmt.Sprintf("SELECT %s, %s, %s", c.ft.Entity, strings.Join(c.ft.Values, ", "), c.ft.TS)
 	partitionStmt := fmt.Sprintf(",ROW_NUMBER() OVER (PARTITION BY %s ORDER BY %s DESC) AS rn ", c.ft.Entity, c.ft.TS)
-	fromStmt := fmt.Sprintf("FROM %s ", c.ft.SanitizedTableName)
+	fromStmt := fmt.Sprintf("FROM %s ", c.ft.TableSQL())
 	cte := fmt.Sprintf("%s AS (%s %s %s)", c.alias, selectClause, partitionStmt, fromStmt)
 	return cte
 }
@@ -379,7 +485,7 @@ func (j leftJoin) ToSQL() string {
 	if j.ft.TS != "" {
 		return fmt.Sprintf("LEFT JOIN %s ON l.%s = %s.%s AND %s.rn = 1", j.alias, j.lblEntity, j.alias, j.ft.Entity, j.alias)
 	}
-	return fmt.Sprintf("LEFT JOIN %s %s ON l.%s = %s.%s", j.ft.SanitizedTableName, j.alias, j.lblEntity, j.alias, j.ft.Entity)
+	return fmt.Sprintf("LEFT JOIN %s %s ON l.%s = %s.%s", j.ft.TableSQL(), j.alias, j.lblEntity, j.alias, j.ft.Entity)
 }
 
 // asOfJoin represents an ASOF JOIN between the label and feature tables.
@@ -392,7 +498,7 @@ type asOfJoin struct {
 
 // ToSQL creates an ASOF JOIN between the label and feature tables.
 func (j asOfJoin) ToSQL(config QueryConfig) string {
-	joinClause := fmt.Sprintf("%s %s %s", joinAsOf, j.ft.SanitizedTableName, j.alias)
+	joinClause := fmt.Sprintf("%s %s %s", joinAsOf, j.ft.TableSQL(), j.alias)
 	var matchClause string
 	if config.AsOfJoinUseNormalJoinSyntax {
 		matchClause += fmt.Sprintf("ON l.%s = %s.%s", j.lblEntity, j.alias, j.ft.Entity)
@@ -431,7 +537,7 @@ type pitTrainingSetQueryBuilder struct {
 // the same table, entity, and timestamp column as another feature, then the values and column aliases
 // are combined into a single feature table so that the query uses a single join for all.
 func (b *pitTrainingSetQueryBuilder) AddFeature(tbl featureTable) {
-	key := createTableKey(tbl.SanitizedTableName, tbl.EntityName, tbl.Entity, tbl.TS)
+	key := createTableKey(tbl)
 	existing, exists := b.featureTableMap[key]
 	if exists {
 		existing.Values = append(existing.Values, tbl.Values...)
@@ -449,8 +555,8 @@ func (b *pitTrainingSetQueryBuilder) Compile() error {
 
 	b.windowJoins = windowJoins{
 		ts:         b.labelTable.EntityMappings.TimestampColumn,
-		label:      b.labelTable.EntityMappings.ValueColumn,
-		labelTable: b.labelTable.SanitizedTableName,
+		label:      b.labelTable.ValueColumn(),
+		labelTable: b.labelTable.TableSQL(b.config),
 	}
 
 	for i, k := range b.featureTableMap.Keys() {
@@ -465,25 +571,25 @@ func (b *pitTrainingSetQueryBuilder) Compile() error {
 			b.columns = append(b.columns, col{tableAlias: ftAlias, val: val, colAlias: ft.ColumnAliases[i]})
 		}
 		// JOINS
-		for _, m := range b.labelTable.EntityMappings.Mappings {
-			if m.Name == ft.EntityName {
-				if ft.TS != "" {
-					if b.config.UseAsOfJoin {
-						b.asOfJoins = append(b.asOfJoins, asOfJoin{alias: ftAlias, ft: ft, lblEntity: m.EntityColumn, lblTS: b.labelTable.EntityMappings.TimestampColumn})
-					} else {
-						b.windowJoins.windows = append(b.windowJoins.windows, windowJoin{
-							entity:     m.EntityColumn,
-							ft:         ft,
-							ts:         b.labelTable.EntityMappings.TimestampColumn,
-							label:      b.labelTable.EntityMappings.ValueColumn,
-							labelTable: b.labelTable.SanitizedTableName,
-						})
-					}
-				} else {
-					b.leftJoins = append(b.leftJoins, leftJoin{alias: ftAlias, ft: ft, lblEntity: m.EntityColumn})
-				}
-				break
+		lblEntity, err := b.labelTable.entityColumn(ft)
+		if err != nil {
+			return err
+		}
+		if ft.TS != "" {
+			if b.config.UseAsOfJoin {
+				b.asOfJoins = append(b.asOfJoins, asOfJoin{alias: ftAlias, ft: ft, lblEntity: lblEntity, lblTS: b.labelTable.EntityMappings.TimestampColumn})
+			} else {
+				b.windowJoins.windows = append(b.windowJoins.windows, windowJoin{
+					index:      i + 1,
+					entity:     lblEntity,
+					ft:         ft,
+					ts:         b.labelTable.EntityMappings.TimestampColumn,
+					label:      b.labelTable.ValueColumn(),
+					labelTable: b.labelTable.SanitizedTableName,
+				})
 			}
+		} else {
+			b.leftJoins = append(b.leftJoins, leftJoin{alias: ftAlias, ft: ft, lblEntity: lblEntity})
 		}
 	}
 	return nil
@@ -491,18 +597,13 @@ func (b *pitTrainingSetQueryBuilder) Compile() error {
 
 // ToSQL returns the SQL representation of the point-in-time training set query builder.
 func (b *pitTrainingSetQueryBuilder) ToSQL() string {
-	quoteChar := ""
-	if b.config.QuoteTable {
-		quoteChar = b.config.QuoteChar
-	}
-
 	var sb strings.Builder
 	// WINDOW (Alternative to ASOF on platforms that don't support it)
 	sb.WriteString(b.windowJoins.HeaderSQL(b.config))
 	// SELECT
-	sb.WriteString(fmt.Sprintf("SELECT %s, l.%s AS label", b.columns.ToSQL(b.config), b.labelTable.EntityMappings.ValueColumn))
+	sb.WriteString(fmt.Sprintf("SELECT %s%s", b.columns.ToSQL(b.config), b.labelTable.LabelSelectSQL()))
 	// FROM
-	sb.WriteString(fmt.Sprintf(" FROM %s%s%s l ", quoteChar, b.labelTable.SanitizedTableName, quoteChar))
+	sb.WriteString(fmt.Sprintf(" FROM %s l ", b.labelTable.TableSQL(b.config)))
 	// JOIN(s)
 	sb.WriteString(b.leftJoins.ToSQL(b.config))
 	sb.WriteString(" ")
@@ -530,7 +631,7 @@ type trainingSetQueryBuilder struct {
 // entity, and timestamp column as another feature, then the values and column aliases are combined into
 // a single feature table so that the query uses a single join and/or CTE for all.
 func (b *trainingSetQueryBuilder) AddFeature(tbl featureTable) {
-	key := createTableKey(tbl.SanitizedTableName, tbl.EntityName, tbl.Entity, tbl.TS)
+	key := createTableKey(tbl)
 	existing, exists := b.featureTableMap[key]
 	if exists {
 		existing.Values = append(existing.Values, tbl.Values...)
@@ -558,12 +659,11 @@ func (b *trainingSetQueryBuilder) Compile() error {
 			b.ctes = append(b.ctes, cte{alias: ftAlias, ft: ft})
 		}
 		// JOIN
-		for _, m := range b.labelTable.EntityMappings.Mappings {
-			if m.Name == ft.EntityName {
-				b.joins = append(b.joins, leftJoin{alias: ftAlias, ft: ft, lblEntity: m.EntityColumn})
-				break
-			}
+		lblEntity, err := b.labelTable.entityColumn(ft)
+		if err != nil {
+			return err
 		}
+		b.joins = append(b.joins, leftJoin{alias: ftAlias, ft: ft, lblEntity: lblEntity})
 		// COLUMNS
 		for i, val := range ft.Values {
 			b.columns = append(b.columns, col{tableAlias: ftAlias, val: val, colAlias: ft.ColumnAliases[i]})
@@ -574,18 +674,13 @@ func (b *trainingSetQueryBuilder) Compile() error {
 
 // ToSQL returns the SQL representation of the training set query builder.
 func (b *trainingSetQueryBuilder) ToSQL() string {
-	quoteChar := ""
-	if b.config.QuoteTable {
-		quoteChar = b.config.QuoteChar
-	}
-
 	var sb strings.Builder
 	// CTE(s)
 	sb.WriteString(b.ctes.ToSQL(b.config))
 	// SELECT
-	sb.WriteString(fmt.Sprintf("SELECT %s, l.%s AS label ", b.columns.ToSQL(b.config), b.labelTable.EntityMappings.ValueColumn))
+	sb.WriteString(fmt.Sprintf("SELECT %s%s ", b.columns.ToSQL(b.config), b.labelTable.LabelSelectSQL()))
 	// FROM
-	sb.WriteString(fmt.Sprintf("FROM %s%s%s l ", quoteChar, b.labelTable.SanitizedTableName, quoteChar))
+	sb.WriteString(fmt.Sprintf("FROM %s l ", b.labelTable.TableSQL(b.config)))
 	// JOIN(s)
 	sb.WriteString(b.joins.ToSQL(b.config))
 	sb.WriteString(";")
@@ -623,7 +718,7 @@ func validateLabelTable(lbl labelTable) error {
 		logging.GlobalLogger.Errorw("entity mappings cannot be empty", "label_table", lbl)
 		return fferr.NewInternalErrorf("entity mappings cannot be empty")
 	}
-	if lbl.EntityMappings.ValueColumn == "" {
+	if lbl.EntityMappings.ValueColumn == "" && !lbl.IsSpine {
 		logging.GlobalLogger.Errorw("value column cannot be empty", "label_table", lbl)
 		return fferr.NewInternalErrorf("value column cannot be empty")
 	}
@@ -644,6 +739,10 @@ func validateLabelTable(lbl labelTable) error {
 	return nil
 }
 
-func createTableKey(tableName, entityName, entityCol, ts string) string {
-	return fmt.Sprintf("%s_%s_%s_%s", tableName, entityName, entityCol, ts)
+func createTableKey(tbl featureTable) string {
+	key := fmt.Sprintf("%s_%s_%s_%s", tbl.SanitizedTableName, tbl.EntityName, tbl.Entity, tbl.TS)
+	if tbl.IsLabel {
+		return "label_" + key
+	}
+	return key
 }
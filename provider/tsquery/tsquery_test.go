@@ -1,6 +1,7 @@
 package tsquery
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/featureform/metadata"
@@ -224,6 +225,45 @@ func TestNewTrainingSetQueryBuilder(t *testing.T) {
 			expectedErr: false,
 			expectedSQL: `SELECT f1.swell_direction AS "feature__swell_direction__variant", f1.wave_power_kj AS "feature__wave_power_kj__variant", f2.avg_success_rate_perc AS "feature__avg_success_rate_perc__variant", l.successful_rides AS label FROM "DEMO2"."CORRECTNESS"."surfer_location_labels_ts" l LEFT JOIN "DEMO2"."CORRECTNESS"."surfer_success_rates_features_no_ts" f2 ON l.surfer_id = f2.surfer_id ASOF JOIN "DEMO2"."CORRECTNESS"."surf_conditions_features_ts" f1 MATCH_CONDITION(l.observed_on >= f1.measured_on) ON(l.location_id = f1.location_id);`,
 		},
+		{
+			name: "Spine with timestamps",
+			lbl: labelTable{
+				SanitizedTableName: "\"DEMO2\".\"CORRECTNESS\".\"locations_to_score\"",
+				EntityMappings:     &metadata.EntityMappings{Mappings: []metadata.EntityMapping{{Name: "location", EntityColumn: "location_id"}}, TimestampColumn: "scored_on"},
+				IsSpine:            true,
+			},
+			fts: []featureTable{
+				{
+					Entity:             "location_id",
+					Values:             []string{"swell_direction"},
+					TS:                 "measured_on",
+					SanitizedTableName: "\"DEMO2\".\"CORRECTNESS\".\"surf_conditions_features_ts\"",
+					ColumnAliases:      []string{"feature__swell_direction__variant"},
+					EntityName:         "location",
+				},
+			},
+			expectedErr: false,
+			expectedSQL: `SELECT f1.swell_direction AS "feature__swell_direction__variant" FROM "DEMO2"."CORRECTNESS"."locations_to_score" l  ASOF JOIN "DEMO2"."CORRECTNESS"."surf_conditions_features_ts" f1 MATCH_CONDITION(l.scored_on >= f1.measured_on) ON(l.location_id = f1.location_id);`,
+		},
+		{
+			name: "Spine without timestamps",
+			lbl: labelTable{
+				SanitizedTableName: "\"DEMO2\".\"CORRECTNESS\".\"surfers_to_score\"",
+				EntityMappings:     &metadata.EntityMappings{Mappings: []metadata.EntityMapping{{Name: "surfer", EntityColumn: "surfer_id"}}},
+				IsSpine:            true,
+			},
+			fts: []featureTable{
+				{
+					Entity:             "surfer_id",
+					Values:             []string{"favorite_spot"},
+					SanitizedTableName: "\"DEMO2\".\"CORRECTNESS\".\"surfer_success_rates_features_no_ts\"",
+					ColumnAliases:      []string{"feature__favorite_spot__variant"},
+					EntityName:         "surfer",
+				},
+			},
+			expectedErr: false,
+			expectedSQL: `SELECT f1.favorite_spot AS "feature__favorite_spot__variant" FROM "DEMO2"."CORRECTNESS"."surfers_to_score" l LEFT JOIN "DEMO2"."CORRECTNESS"."surfer_success_rates_features_no_ts" f1 ON l.surfer_id = f1.surfer_id;`,
+		},
 	}
 
 	for _, c := range cases {
@@ -264,3 +304,131 @@ func TestNewTrainingSetQueryBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestSpineWindowJoinsOmitLabel(t *testing.T) {
+	params := BuilderParams{
+		LabelEntityMappings: &metadata.EntityMappings{
+			Mappings:        []metadata.EntityMapping{{Name: "location", EntityColumn: "location_id"}},
+			TimestampColumn: "scored_on",
+		},
+		SanitizedLabelTable:    "locations_to_score",
+		FeatureColumns:         []metadata.ResourceVariantColumns{{Entity: "location_id", Value: "swell_direction", TS: "measured_on"}},
+		SanitizedFeatureTables: []string{"surf_conditions_features_ts"},
+		FeatureNameVariants:    []metadata.ResourceID{{Name: "swell_direction", Variant: "variant"}},
+		FeatureEntityNames:     []string{"location"},
+		IsSpine:                true,
+	}
+	sql, err := NewTrainingSet(QueryConfig{QuoteChar: "`", QuoteTable: true}, params).CompileSQL()
+	if err != nil {
+		t.Fatalf("Failed to compile spine training set: %v", err)
+	}
+	for _, unexpected := range []string{"AS label", "l.label", "    label,"} {
+		if strings.Contains(sql, unexpected) {
+			t.Errorf("Expected spine training set query to not contain %q:\n%s", unexpected, sql)
+		}
+	}
+	if !strings.Contains(sql, "SELECT f1.swell_direction AS `feature__swell_direction__variant` FROM `locations_to_score` l") {
+		t.Errorf("Expected spine training set query to only select features:\n%s", sql)
+	}
+
+	params.IsSpine = false
+	if _, err := NewTrainingSet(QueryConfig{}, params).CompileSQL(); err == nil {
+		t.Errorf("Expected training set without a label value column to fail")
+	}
+}
+
+func TestMultiEntityAdditionalLabels(t *testing.T) {
+	params := BuilderParams{
+		LabelEntityMappings: &metadata.EntityMappings{
+			Mappings: []metadata.EntityMapping{
+				{Name: "user", EntityColumn: "user_id"},
+				{Name: "merchant", EntityColumn: "merchant_id"},
+			},
+			ValueColumn:     "is_fraud",
+			TimestampColumn: "ts",
+		},
+		SanitizedLabelTable:            "transactions",
+		FeatureColumns:                 []metadata.ResourceVariantColumns{{Entity: "user_id", Value: "avg_spend", TS: "ts"}, {Entity: "merchant_id", Value: "chargebacks"}},
+		SanitizedFeatureTables:         []string{"user_spend", "merchant_stats"},
+		FeatureNameVariants:            []metadata.ResourceID{{Name: "avg_spend", Variant: "v"}, {Name: "chargebacks", Variant: "v"}},
+		FeatureEntityNames:             []string{"user", "merchant"},
+		AdditionalLabelColumns:         []metadata.ResourceVariantColumns{{Entity: "merchant_id", Value: "disputed", TS: "ts"}},
+		SanitizedAdditionalLabelTables: []string{"disputes"},
+		AdditionalLabelNameVariants:    []metadata.ResourceID{{Name: "disputed", Variant: "v"}},
+		AdditionalLabelEntityNames:     []string{"merchant"},
+	}
+	sql, err := NewTrainingSet(QueryConfig{UseAsOfJoin: true, QuoteChar: "\""}, params).CompileSQL()
+	if err != nil {
+		t.Fatalf("Failed to compile multi-label training set: %v", err)
+	}
+	expectedSQL := `SELECT f1.chargebacks AS "feature__chargebacks__v", f2.avg_spend AS "feature__avg_spend__v", f3.disputed AS "label__disputed__v", l.is_fraud AS label FROM transactions l LEFT JOIN merchant_stats f1 ON l.merchant_id = f1.merchant_id ASOF JOIN user_spend f2 MATCH_CONDITION(l.ts >= f2.ts) ON(l.user_id = f2.user_id) ASOF JOIN disputes f3 MATCH_CONDITION(l.ts >= f3.ts) ON(l.merchant_id = f3.merchant_id);`
+	if sql != expectedSQL {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expectedSQL, sql)
+	}
+
+	params.AdditionalLabelEntityNames = []string{"device"}
+	if _, err := NewTrainingSet(QueryConfig{UseAsOfJoin: true}, params).CompileSQL(); err == nil {
+		t.Errorf("Expected label keyed on an unmapped entity to fail")
+	}
+}
+
+func TestWindowJoinsUseColumnAliases(t *testing.T) {
+	params := BuilderParams{
+		LabelEntityMappings: &metadata.EntityMappings{
+			Mappings:        []metadata.EntityMapping{{Name: "user", EntityColumn: "user_id"}},
+			ValueColumn:     "churned",
+			TimestampColumn: "ts",
+		},
+		SanitizedLabelTable:    "churn",
+		FeatureColumns:         []metadata.ResourceVariantColumns{{Entity: "user_id", Value: "country"}, {Entity: "user_id", Value: "avg_spend", TS: "ts"}},
+		SanitizedFeatureTables: []string{"a_users", "b_spend"},
+		FeatureNameVariants:    []metadata.ResourceID{{Name: "country", Variant: "v"}, {Name: "avg_spend", Variant: "v"}},
+		FeatureEntityNames:     []string{"user", "user"},
+	}
+	sql, err := NewTrainingSet(QueryConfig{QuoteChar: "\""}, params).CompileSQL()
+	if err != nil {
+		t.Fatalf("Failed to compile training set: %v", err)
+	}
+	for _, expected := range []string{"f2.avg_spend AS", "feature_2 AS (", "LEFT JOIN feature_2_filtered f2"} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expected query to contain %q:\n%s", expected, sql)
+		}
+	}
+}
+
+func TestSnapshotTablesReplaceTableNames(t *testing.T) {
+	params := BuilderParams{
+		LabelEntityMappings: &metadata.EntityMappings{
+			Mappings:        []metadata.EntityMapping{{Name: "user", EntityColumn: "user_id"}},
+			ValueColumn:     "churned",
+			TimestampColumn: "ts",
+		},
+		SanitizedLabelTable:    "churn",
+		FeatureColumns:         []metadata.ResourceVariantColumns{{Entity: "user_id", Value: "country"}, {Entity: "user_id", Value: "avg_spend", TS: "ts"}},
+		SanitizedFeatureTables: []string{"users", "spend"},
+		FeatureNameVariants:    []metadata.ResourceID{{Name: "country", Variant: "v"}, {Name: "avg_spend", Variant: "v"}},
+		FeatureEntityNames:     []string{"user", "user"},
+		LabelTableSnapshot:     `"churn" AT(TIMESTAMP => '2025-01-02 03:04:05'::TIMESTAMP_LTZ)`,
+		FeatureTableSnapshots:  []string{"", `"spend" AT(TIMESTAMP => '2025-01-02 03:04:05'::TIMESTAMP_LTZ)`},
+	}
+	sql, err := NewTrainingSet(QueryConfig{UseAsOfJoin: true, QuoteChar: "\""}, params).CompileSQL()
+	if err != nil {
+		t.Fatalf("Failed to compile training set: %v", err)
+	}
+	expectedSQL := `SELECT f1.avg_spend AS "feature__avg_spend__v", f2.country AS "feature__country__v", l.churned AS label FROM "churn" AT(TIMESTAMP => '2025-01-02 03:04:05'::TIMESTAMP_LTZ) l LEFT JOIN users f2 ON l.user_id = f2.user_id ASOF JOIN "spend" AT(TIMESTAMP => '2025-01-02 03:04:05'::TIMESTAMP_LTZ) f1 MATCH_CONDITION(l.ts >= f1.ts) ON(l.user_id = f1.user_id);`
+	if sql != expectedSQL {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expectedSQL, sql)
+	}
+
+	snapshot := "(SELECT * FROM `churn` FOR SYSTEM_TIME AS OF TIMESTAMP_MILLIS(1735787045000))"
+	params.LabelTableSnapshot = snapshot
+	params.FeatureTableSnapshots = nil
+	sql, err = NewTrainingSet(QueryConfig{QuoteChar: "`", QuoteTable: true}, params).CompileSQL()
+	if err != nil {
+		t.Fatalf("Failed to compile training set: %v", err)
+	}
+	// Window joins read the label table in the labels CTE as well as in the FROM clause.
+	if strings.Count(sql, snapshot) != 2 || strings.Contains(sql, "`churn`\n") {
+		t.Errorf("Expected the label table to be read from its snapshot:\n%s", sql)
+	}
+}
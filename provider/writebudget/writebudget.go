@@ -0,0 +1,279 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+// Package writebudget enforces a pc.WriteBudget on writes to an online store.
+//
+// A Limiter combines a token bucket (rows/sec) with an adaptive concurrency
+// limit. Both back off multiplicatively when the store signals backpressure
+// (throttling errors or latency above the target) and recover additively on
+// success. Limiters are shared per provider via ForProvider so that every
+// chunk runner in the process draws from the same budget.
+package writebudget
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"golang.org/x/time/rate"
+
+	pc "github.com/featureform/provider/provider_config"
+)
+
+const (
+	// decreaseFactor is applied to the concurrency limit and rate on backpressure.
+	decreaseFactor = 0.5
+	// rateIncreaseFraction of the configured rate is added back on each success.
+	rateIncreaseFraction = 0.01
+	// minRateFraction of the configured rate is the floor we'll back off to.
+	minRateFraction = 0.01
+	// decreaseCooldown prevents a burst of concurrent throttles from collapsing
+	// the limits more than once per interval.
+	decreaseCooldown = 500 * time.Millisecond
+)
+
+// throttleErrorCodes are the API error codes that signal the store is
+// rejecting writes due to capacity.
+var throttleErrorCodes = map[string]bool{
+	"ProvisionedThroughputExceededException": true,
+	"ThrottlingException":                    true,
+	"RequestLimitExceeded":                   true,
+}
+
+// IsThrottle returns true if err is a throttling error returned by the store.
+func IsThrottle(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return throttleErrorCodes[apiErr.ErrorCode()]
+	}
+	return false
+}
+
+// Limiter enforces a WriteBudget. A nil *Limiter is valid and never limits.
+type Limiter struct {
+	budget pc.WriteBudget
+	rate   *rate.Limiter
+
+	mu           sync.Mutex
+	limit        float64
+	inFlight     int
+	lastDecrease time.Time
+	// changed is closed and replaced whenever a slot frees up or the limit
+	// changes, waking every goroutine blocked in Acquire.
+	changed chan struct{}
+}
+
+// NewLimiter returns a Limiter for budget, or nil if the budget is unlimited.
+func NewLimiter(budget pc.WriteBudget) *Limiter {
+	if budget.IsUnlimited() {
+		return nil
+	}
+	l := &Limiter{
+		budget:  budget,
+		limit:   float64(budget.MaxConcurrency),
+		changed: make(chan struct{}),
+	}
+	if budget.RowsPerSecond > 0 {
+		l.rate = rate.NewLimiter(rate.Limit(budget.RowsPerSecond), burstSize(budget.RowsPerSecond))
+	}
+	return l
+}
+
+func burstSize(rowsPerSecond float64) int {
+	return int(math.Max(1, math.Ceil(rowsPerSecond)))
+}
+
+// Acquire blocks until a write of rows rows is allowed by the budget. Every
+// successful Acquire must be paired with a Release.
+func (l *Limiter) Acquire(ctx context.Context, rows int) error {
+	if l == nil {
+		return nil
+	}
+	if err := l.acquireSlot(ctx); err != nil {
+		return err
+	}
+	if err := l.waitRows(ctx, rows); err != nil {
+		l.releaseSlot()
+		return err
+	}
+	return nil
+}
+
+func (l *Limiter) acquireSlot(ctx context.Context) error {
+	if l.budget.MaxConcurrency == 0 {
+		return nil
+	}
+	for {
+		l.mu.Lock()
+		if l.inFlight < int(l.limit) {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (l *Limiter) waitRows(ctx context.Context, rows int) error {
+	if l.rate == nil {
+		return nil
+	}
+	// WaitN fails if n exceeds the burst, so large batches wait in pieces.
+	burst := l.rate.Burst()
+	for rows > 0 {
+		n := rows
+		if n > burst {
+			n = burst
+		}
+		if err := l.rate.WaitN(ctx, n); err != nil {
+			return err
+		}
+		rows -= n
+	}
+	return nil
+}
+
+func (l *Limiter) releaseSlot() {
+	if l.budget.MaxConcurrency == 0 {
+		return
+	}
+	l.mu.Lock()
+	l.inFlight--
+	l.broadcastLocked()
+	l.mu.Unlock()
+}
+
+// Release returns the slot taken by Acquire and feeds the outcome of the
+// write back into the limiter. Throttling errors and latency above the
+// budget's target shrink the limits, successes grow them back.
+func (l *Limiter) Release(latency time.Duration, err error) {
+	if l == nil {
+		return
+	}
+	l.releaseSlot()
+	target := l.budget.TargetLatency()
+	switch {
+	case IsThrottle(err), target > 0 && latency > target:
+		l.ReportBackpressure()
+	case err == nil:
+		l.increase()
+	}
+}
+
+// ReportBackpressure shrinks the limits. Stores that retry throttled writes
+// internally call it via ReportThrottle so the budget still adapts.
+func (l *Limiter) ReportBackpressure() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Sub(l.lastDecrease) < decreaseCooldown {
+		return
+	}
+	l.lastDecrease = now
+	if l.budget.MaxConcurrency > 0 {
+		l.limit = math.Max(1, l.limit*decreaseFactor)
+	}
+	if l.rate != nil {
+		floor := l.budget.RowsPerSecond * minRateFraction
+		l.rate.SetLimit(rate.Limit(math.Max(floor, float64(l.rate.Limit())*decreaseFactor)))
+	}
+	l.broadcastLocked()
+}
+
+func (l *Limiter) increase() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.budget.MaxConcurrency > 0 {
+		// Additive increase of roughly one slot per window of successful writes.
+		prev := int(l.limit)
+		l.limit = math.Min(float64(l.budget.MaxConcurrency), l.limit+1/l.limit)
+		if int(l.limit) > prev {
+			l.broadcastLocked()
+		}
+	}
+	if l.rate != nil {
+		next := float64(l.rate.Limit()) + l.budget.RowsPerSecond*rateIncreaseFraction
+		l.rate.SetLimit(rate.Limit(math.Min(l.budget.RowsPerSecond, next)))
+	}
+}
+
+func (l *Limiter) broadcastLocked() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// ConcurrencyLimit returns the current adaptive concurrency limit, or 0 if
+// concurrency isn't limited.
+func (l *Limiter) ConcurrencyLimit() int {
+	if l == nil || l.budget.MaxConcurrency == 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// RowsPerSecond returns the current allowed write rate, or 0 if the rate
+// isn't limited.
+func (l *Limiter) RowsPerSecond() float64 {
+	if l == nil || l.rate == nil {
+		return 0
+	}
+	return float64(l.rate.Limit())
+}
+
+var limiters = &sync.Map{}
+
+// ForProvider returns the Limiter shared by every writer to the provider
+// identified by key. Callers should use a key that changes whenever the
+// budget does, like the provider's serialized config.
+func ForProvider(key string, budget pc.WriteBudget) *Limiter {
+	if budget.IsUnlimited() {
+		return nil
+	}
+	if cached, ok := limiters.Load(key); ok {
+		return cached.(*Limiter)
+	}
+	actual, _ := limiters.LoadOrStore(key, NewLimiter(budget))
+	return actual.(*Limiter)
+}
+
+type limiterContextKey struct{}
+
+// WithLimiter returns a context that carries l to the online store.
+func WithLimiter(ctx context.Context, l *Limiter) context.Context {
+	if l == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, limiterContextKey{}, l)
+}
+
+// FromContext returns the Limiter stored in ctx, or nil.
+func FromContext(ctx context.Context) *Limiter {
+	l, _ := ctx.Value(limiterContextKey{}).(*Limiter)
+	return l
+}
+
+// ReportThrottle signals backpressure to the Limiter in ctx, if any.
+func ReportThrottle(ctx context.Context) {
+	FromContext(ctx).ReportBackpressure()
+}
@@ -0,0 +1,196 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package writebudget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+
+	pc "github.com/featureform/provider/provider_config"
+)
+
+func TestUnlimitedBudgetIsNil(t *testing.T) {
+	l := NewLimiter(pc.WriteBudget{})
+	if l != nil {
+		t.Fatalf("Expected nil limiter for unlimited budget, got %v", l)
+	}
+	// A nil limiter must be usable without checks.
+	if err := l.Acquire(context.Background(), 100); err != nil {
+		t.Fatalf("Acquire on nil limiter failed: %v", err)
+	}
+	l.Release(time.Second, fmt.Errorf("ignored"))
+	ReportThrottle(context.Background())
+}
+
+func TestIsThrottle(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"plain error", fmt.Errorf("connection reset"), false},
+		{"provisioned throughput", &types.ProvisionedThroughputExceededException{}, true},
+		{"wrapped throttling", fmt.Errorf("write failed: %w", &smithy.GenericAPIError{Code: "ThrottlingException"}), true},
+		{"other api error", &smithy.GenericAPIError{Code: "ValidationException"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := IsThrottle(tt.err); actual != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	l := NewLimiter(pc.WriteBudget{MaxConcurrency: 2})
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := l.Acquire(ctx, 1); err != nil {
+			t.Fatalf("Acquire %d failed: %v", i, err)
+		}
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := l.Acquire(timeoutCtx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected third Acquire to block until deadline, got %v", err)
+	}
+	acquired := make(chan error)
+	go func() {
+		acquired <- l.Acquire(ctx, 1)
+	}()
+	l.Release(time.Millisecond, nil)
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("Acquire after Release failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Acquire did not unblock after Release")
+	}
+}
+
+func TestBackpressureAndRecovery(t *testing.T) {
+	budget := pc.WriteBudget{RowsPerSecond: 1000, MaxConcurrency: 8, TargetLatencyMs: 10}
+	l := NewLimiter(budget)
+	ctx := context.Background()
+
+	if err := l.Acquire(ctx, 1); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	l.Release(time.Millisecond, &types.ProvisionedThroughputExceededException{})
+	if limit := l.ConcurrencyLimit(); limit != 4 {
+		t.Fatalf("Expected concurrency to halve to 4 on throttle, got %d", limit)
+	}
+	if rate := l.RowsPerSecond(); rate != 500 {
+		t.Fatalf("Expected rate to halve to 500 on throttle, got %f", rate)
+	}
+
+	// A second signal inside the cooldown must not shrink the limits again.
+	if err := l.Acquire(ctx, 1); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	l.Release(time.Second, nil)
+	if limit := l.ConcurrencyLimit(); limit != 4 {
+		t.Fatalf("Expected concurrency to stay at 4 during cooldown, got %d", limit)
+	}
+
+	for i := 0; i < 100; i++ {
+		if err := l.Acquire(ctx, 1); err != nil {
+			t.Fatalf("Acquire failed: %v", err)
+		}
+		l.Release(time.Millisecond, nil)
+	}
+	if limit := l.ConcurrencyLimit(); limit != budget.MaxConcurrency {
+		t.Fatalf("Expected concurrency to recover to %d, got %d", budget.MaxConcurrency, limit)
+	}
+	if rate := l.RowsPerSecond(); rate != budget.RowsPerSecond {
+		t.Fatalf("Expected rate to recover to %f, got %f", budget.RowsPerSecond, rate)
+	}
+}
+
+func TestLatencyBackpressure(t *testing.T) {
+	l := NewLimiter(pc.WriteBudget{MaxConcurrency: 16, TargetLatencyMs: 5})
+	if err := l.Acquire(context.Background(), 1); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	l.Release(50*time.Millisecond, nil)
+	if limit := l.ConcurrencyLimit(); limit != 8 {
+		t.Fatalf("Expected slow write to halve concurrency to 8, got %d", limit)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	l := NewLimiter(pc.WriteBudget{RowsPerSecond: 100})
+	ctx := context.Background()
+	start := time.Now()
+	// The first 100 rows are covered by the burst, the next 20 must wait ~200ms.
+	for i := 0; i < 120; i += 10 {
+		if err := l.Acquire(ctx, 10); err != nil {
+			t.Fatalf("Acquire failed: %v", err)
+		}
+		l.Release(0, nil)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("Expected rate limit to delay writes, took %s", elapsed)
+	}
+}
+
+func TestRateLimitLargerThanBurst(t *testing.T) {
+	l := NewLimiter(pc.WriteBudget{RowsPerSecond: 10})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := l.Acquire(ctx, 15); err != nil {
+		t.Fatalf("Expected Acquire larger than burst to succeed, got %v", err)
+	}
+}
+
+func TestForProviderSharesLimiter(t *testing.T) {
+	budget := pc.WriteBudget{MaxConcurrency: 4}
+	var wg sync.WaitGroup
+	results := make([]*Limiter, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = ForProvider(t.Name(), budget)
+		}(i)
+	}
+	wg.Wait()
+	for i, l := range results {
+		if l != results[0] {
+			t.Fatalf("Limiter %d differs from first limiter", i)
+		}
+	}
+	if ForProvider(t.Name()+"-other", budget) == results[0] {
+		t.Fatalf("Expected different providers to get different limiters")
+	}
+	if ForProvider(t.Name()+"-unlimited", pc.WriteBudget{}) != nil {
+		t.Fatalf("Expected unlimited budget to return nil limiter")
+	}
+}
+
+func TestContext(t *testing.T) {
+	l := NewLimiter(pc.WriteBudget{MaxConcurrency: 4})
+	ctx := WithLimiter(context.Background(), l)
+	if FromContext(ctx) != l {
+		t.Fatalf("Expected limiter from context")
+	}
+	ReportThrottle(ctx)
+	if limit := l.ConcurrencyLimit(); limit != 2 {
+		t.Fatalf("Expected ReportThrottle to halve concurrency to 2, got %d", limit)
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -19,11 +21,14 @@ import (
 	"github.com/featureform/logging"
 
 	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
 	"github.com/featureform/metadata"
+	"github.com/featureform/metrics"
 	"github.com/featureform/provider"
 	"github.com/featureform/provider/dataset"
 	pc "github.com/featureform/provider/provider_config"
 	pt "github.com/featureform/provider/provider_type"
+	"github.com/featureform/provider/writebudget"
 	"github.com/featureform/types"
 )
 
@@ -39,8 +44,32 @@ const providerCachingEnabled = false
 const (
 	entityColIdx = 0
 	valueColIdx  = 1
+	tsColIdx     = 2
 )
 
+// BackfillWindow limits a materialization to the entities a backfill of the feature's source
+// over [Start, End) may have changed.
+type BackfillWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// mayHaveChanged reports whether row, an entity's latest value, should be copied. Only values at
+// or after End are known to be what's already online. A value before Start may be stale online,
+// since the backfill may have removed the newer value the online store was set from, so it's
+// copied again to re-derive it. Rows without a timestamp are always copied, since there's no
+// telling whether the backfill changed them.
+func (w *BackfillWindow) mayHaveChanged(row fftypes.Row) bool {
+	if w == nil || len(row) <= tsColIdx {
+		return true
+	}
+	ts, ok := row[tsColIdx].Value.(time.Time)
+	if !ok {
+		return true
+	}
+	return ts.Before(w.End)
+}
+
 type IndexRunner interface {
 	types.Runner
 	SetIndex(index int) error
@@ -51,6 +80,8 @@ type MaterializedChunkRunner struct {
 	Table        provider.OnlineStoreTable
 	Store        provider.OnlineStore
 	ChunkIdx     int
+	// Backfill, if set, skips the rows outside of the window.
+	Backfill *BackfillWindow
 }
 
 type ResultSync struct {
@@ -94,8 +125,22 @@ func (m *MaterializedChunkRunner) Run() (types.CompletionWatcher, error) {
 	// offer the best results
 	workerPoolSize := config.GetMaterializationWorkerPoolSize()
 	logger.Debugw("worker pool size", "worker_pool_size", workerPoolSize)
+	// The workers share a write budget with every other chunk runner writing to
+	// the same provider; workers beyond its concurrency limit block until a
+	// slot frees up.
+	limiter := m.writeLimiter()
+	if limiter != nil {
+		logger.Debugw("using write budget", "concurrency_limit", limiter.ConcurrencyLimit(), "rows_per_second", limiter.RowsPerSecond())
+		ctx = writebudget.WithLimiter(ctx, limiter)
+	}
+	providerType := m.Store.Type().String()
 	go func() {
 		logger.Debugw("starting materialized chunk runner", "chunk_idx", m.ChunkIdx)
+		var written atomic.Int64
+		chunkStart := time.Now()
+		defer func() {
+			metrics.Instrument().ObserveMaterializedChunk(providerType, int(written.Load()), time.Since(chunkStart))
+		}()
 		it, err := m.Materialized.ChunkIterator(ctx, m.ChunkIdx)
 		if err != nil {
 			logger.Errorw("error getting iterator", "error", err)
@@ -149,7 +194,7 @@ func (m *MaterializedChunkRunner) Run() (types.CompletionWatcher, error) {
 					buffer = append(buffer, provider.SetItem{record.Entity, record.Value})
 					if len(buffer) == maxBatch {
 						logger.Debugw("setting batch", "batch_size", len(buffer))
-						if err := batchTable.BatchSet(ctx, buffer); err != nil {
+						if err := budgetedWrite(ctx, limiter, providerType, "BatchSet", len(buffer), &written, func() error { return batchTable.BatchSet(ctx, buffer) }); err != nil {
 							logger.Errorf("error setting batch: %v", err)
 							select {
 							case errCh <- err:
@@ -162,7 +207,7 @@ func (m *MaterializedChunkRunner) Run() (types.CompletionWatcher, error) {
 				// Clear the buffer
 				if len(buffer) != 0 {
 					logger.Debugw("setting batch", "batch_size", len(buffer))
-					if err := batchTable.BatchSet(ctx, buffer); err != nil {
+					if err := budgetedWrite(ctx, limiter, providerType, "BatchSet", len(buffer), &written, func() error { return batchTable.BatchSet(ctx, buffer) }); err != nil {
 						logger.Errorf("error setting batch: %v", err)
 						select {
 						case errCh <- err:
@@ -177,7 +222,7 @@ func (m *MaterializedChunkRunner) Run() (types.CompletionWatcher, error) {
 			setterFn = func() {
 				defer wg.Done()
 				for record := range ch {
-					if err := m.Table.Set(record.Entity, record.Value); err != nil {
+					if err := budgetedWrite(ctx, limiter, providerType, "Set", 1, &written, func() error { return m.Table.Set(record.Entity, record.Value) }); err != nil {
 						select {
 						case errCh <- err:
 						default:
@@ -193,13 +238,17 @@ func (m *MaterializedChunkRunner) Run() (types.CompletionWatcher, error) {
 		var chanErr error
 		for it.Next() {
 			values := it.Values()
+			if !m.Backfill.mayHaveChanged(values) {
+				continue
+			}
 			entity := values[entityColIdx].Value.(string) // Using entityColIdx constant instead of hardcoded 0
 			val := values[valueColIdx].Value              // Using valueColIdx constant instead of hardcoded 1
+			// Block rather than drop records when the workers fall behind, which
+			// is expected when the write budget is throttling them.
 			select {
 			case chanErr = <-errCh:
 				logger.Errorf("error setting value: %v", chanErr)
 			case ch <- provider.ResourceRecord{Entity: entity, Value: val}:
-			default:
 			}
 			if chanErr != nil {
 				break
@@ -243,6 +292,34 @@ func (m *MaterializedChunkRunner) Run() (types.CompletionWatcher, error) {
 	return jobWatcher, nil
 }
 
+// writeLimiter returns the write budget limiter shared by all chunk runners
+// targeting the same online store, or nil if the store has no budget.
+func (m *MaterializedChunkRunner) writeLimiter() *writebudget.Limiter {
+	budgeted, ok := m.Store.(provider.WriteBudgetedStore)
+	if !ok {
+		return nil
+	}
+	key := fmt.Sprintf("%s:%s", m.Store.Type(), m.Store.Config())
+	return writebudget.ForProvider(key, budgeted.WriteBudget())
+}
+
+// budgetedWrite runs write within the limiter's budget and feeds the write's
+// latency and error back into it. Successful writes add their rows to written.
+func budgetedWrite(ctx context.Context, limiter *writebudget.Limiter, providerType, operation string, rows int, written *atomic.Int64, write func() error) error {
+	if err := limiter.Acquire(ctx, rows); err != nil {
+		return fferr.NewInternalError(err)
+	}
+	start := time.Now()
+	err := write()
+	latency := time.Since(start)
+	limiter.Release(latency, err)
+	metrics.Instrument().ObserveProviderOperation(providerType, operation, latency, err)
+	if err == nil {
+		written.Add(int64(rows))
+	}
+	return err
+}
+
 func (m *MaterializedChunkRunner) SetIndex(index int) error {
 	m.ChunkIdx = index
 	return nil
@@ -313,6 +390,7 @@ type MaterializedChunkRunnerConfig struct {
 	IsUpdate       bool
 	Logger         *zap.SugaredLogger
 	SkipCache      bool
+	Backfill       *BackfillWindow
 }
 
 func (m *MaterializedChunkRunnerConfig) Serialize() (Config, error) {
@@ -398,5 +476,6 @@ func MaterializedChunkRunnerFactory(config Config) (types.Runner, error) {
 		Table:        table,
 		Store:        onlineStore,
 		ChunkIdx:     runnerConfig.ChunkIdx,
+		Backfill:     runnerConfig.Backfill,
 	}, nil
 }
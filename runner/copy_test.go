@@ -14,6 +14,7 @@ import (
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -981,3 +982,45 @@ func TestRunnerConfigDeserializeFails(t *testing.T) {
 		t.Fatalf("Failed to report error deserializing config")
 	}
 }
+
+func TestChunkRunnerBackfillWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	materialization := provider.MemoryMaterialization{
+		Id: provider.MaterializationID(uuid.NewString()),
+		Data: []provider.ResourceRecord{
+			{Entity: "before", Value: 1, TS: start.Add(-time.Second)},
+			{Entity: "start", Value: 2, TS: start},
+			{Entity: "inside", Value: 3, TS: start.Add(time.Hour)},
+			{Entity: "end", Value: 4, TS: end},
+			{Entity: "no_timestamp", Value: 5},
+		},
+		RowsPerChunk: 5,
+	}
+	table := &MockOnlineTable{DataTable: sync.Map{}}
+	job := &MaterializedChunkRunner{
+		Materialized: provider.NewLegacyMaterializationAdapterWithEmptySchema(&materialization),
+		Table:        table,
+		Store:        NewMockOnlineStore(),
+		Backfill:     &BackfillWindow{Start: start, End: end},
+	}
+	watcher, err := job.Run()
+	if err != nil {
+		t.Fatalf("Failed to start job: %v", err)
+	}
+	if err := watcher.Wait(); err != nil {
+		t.Fatalf("Job failed: %v", err)
+	}
+	// Entities whose latest value is before the window are re-derived, since the backfill may
+	// have removed the value they were last set from.
+	expected := map[string]bool{"before": true, "start": true, "inside": true, "end": false, "no_timestamp": true}
+	for entity, isCopied := range expected {
+		_, err := table.Get(entity)
+		if isCopied && err != nil {
+			t.Errorf("Expected %s to be copied: %v", entity, err)
+		}
+		if !isCopied && err == nil {
+			t.Errorf("Expected %s to be skipped", entity)
+		}
+	}
+}
@@ -47,6 +47,8 @@ type MaterializeRunner struct {
 	Cloud    JobCloud
 	Logger   *zap.SugaredLogger
 	Options  provider.MaterializationOptions
+	// Backfill, if set, limits the copy to the online store to the entities in the window.
+	Backfill *BackfillWindow
 }
 
 func (m MaterializeRunner) Resource() metadata.ResourceID {
@@ -166,6 +168,7 @@ func (m MaterializeRunner) MaterializeToOnline(materialization dataset.Materiali
 		MaterializedID: provider.MaterializationID(materialization.ID()),
 		ResourceID:     m.ID,
 		Logger:         m.Logger,
+		Backfill:       m.Backfill,
 	}
 	var cloudWatcher types.CompletionWatcher
 	switch m.Cloud {
@@ -253,6 +256,7 @@ type MaterializedRunnerConfig struct {
 	Cloud         JobCloud
 	IsUpdate      bool
 	Options       provider.MaterializationOptions
+	Backfill      *BackfillWindow
 }
 
 type MaterializedRunnerConfigJSON struct {
@@ -265,6 +269,7 @@ type MaterializedRunnerConfigJSON struct {
 	Cloud         JobCloud                   `json:"Cloud"`
 	IsUpdate      bool                       `json:"IsUpdate"`
 	Options       MaterializationOptionsJSON `json:"Options"`
+	Backfill      *BackfillWindow            `json:"Backfill,omitempty"`
 }
 
 type MaterializationOptionsJSON struct {
@@ -299,6 +304,7 @@ func (m *MaterializedRunnerConfig) Serialize() (Config, error) {
 			ResourceSnowflakeConfig: m.Options.ResourceSnowflakeConfig,
 			Schema:                  json.RawMessage(schemaBytes),
 		},
+		Backfill: m.Backfill,
 	}
 
 	configBytes, err := json.Marshal(data)
@@ -324,6 +330,7 @@ func (config *MaterializedRunnerConfig) Deserialize(data []byte) error {
 	config.VType = intermediate.VType
 	config.Cloud = intermediate.Cloud
 	config.IsUpdate = intermediate.IsUpdate
+	config.Backfill = intermediate.Backfill
 
 	options := provider.MaterializationOptions{}
 	options.Output = intermediate.Options.Output
@@ -377,5 +384,6 @@ func MaterializeRunnerFactory(config Config) (types.Runner, error) {
 		Cloud:    runnerConfig.Cloud,
 		Logger:   logging.NewLogger("materializer").SugaredLogger,
 		Options:  runnerConfig.Options,
+		Backfill: runnerConfig.Backfill,
 	}, nil
 }
@@ -14,10 +14,23 @@ import (
 	"github.com/featureform/ffsync"
 	"github.com/featureform/helpers/notifications"
 	"github.com/featureform/helpers/postgres"
+	"github.com/featureform/helpers/sqlite"
 	"github.com/featureform/logging"
 	ss "github.com/featureform/storage"
 )
 
+// newRunNotifier builds the run notification dispatcher from the notifications config file, if one is set.
+func newRunNotifier(logger logging.Logger) (notifications.RunNotifier, error) {
+	dispatcher, err := notifications.NewDispatcherFromFile(logger, cfg.GetNotificationsConfigPath())
+	if err != nil {
+		return nil, err
+	}
+	if dispatcher == nil {
+		return nil, nil
+	}
+	return dispatcher, nil
+}
+
 func NewMemoryTaskMetadataManager(ctx context.Context) (TaskMetadataManager, error) {
 	logger := logging.GetLoggerFromContext(ctx)
 	logger.Debug("Building in-memory task metadata manager")
@@ -35,7 +48,7 @@ func NewMemoryTaskMetadataManager(ctx context.Context) (TaskMetadataManager, err
 	}
 
 	storage := ss.MetadataStorage{
-		Locker:  &memoryLocker,
+		Locker:  ffsync.NewInstrumentedLocker("memory", &memoryLocker),
 		Storage: &memoryStorage,
 		Logger:  logger,
 	}
@@ -50,12 +63,18 @@ func NewMemoryTaskMetadataManager(ctx context.Context) (TaskMetadataManager, err
 	logger.Debug("Building slack notifier")
 	slackChannel := cfg.GetSlackChannelId()
 	slackNotif := notifications.NewSlackNotifier(slackChannel, logger)
+	runNotifier, err := newRunNotifier(logger)
+	if err != nil {
+		logger.Errorw("Failed to build run notifier", "err", err)
+		return TaskMetadataManager{}, err
+	}
 
 	logger.Info("Successfully created in-memory TaskMetadataManager")
 	return TaskMetadataManager{
 		Storage:     storage,
 		idGenerator: generator,
 		notifier:    slackNotif,
+		runNotifier: runNotifier,
 	}, nil
 }
 
@@ -76,7 +95,7 @@ func NewPSQLTaskMetadataManager(ctx context.Context, pool *postgres.Pool) (TaskM
 	}
 
 	psqlMetadataStorage := ss.MetadataStorage{
-		Locker:          psqlLocker,
+		Locker:          ffsync.NewInstrumentedLocker("psql", psqlLocker),
 		Storage:         psqlStorage,
 		Logger:          logger,
 		SkipListLocking: true,
@@ -92,11 +111,67 @@ func NewPSQLTaskMetadataManager(ctx context.Context, pool *postgres.Pool) (TaskM
 	logger.Debug("Building slack notifier")
 	slackChannel := cfg.GetSlackChannelId()
 	slackNotif := notifications.NewSlackNotifier(slackChannel, logger)
+	runNotifier, err := newRunNotifier(logger)
+	if err != nil {
+		logger.Errorw("Failed to build run notifier", "err", err)
+		return TaskMetadataManager{}, err
+	}
 
 	logger.Info("TaskMetadataManager successfully created.")
 	return TaskMetadataManager{
 		Storage:     psqlMetadataStorage,
 		idGenerator: idGenerator,
 		notifier:    slackNotif,
+		runNotifier: runNotifier,
+	}, nil
+}
+
+// NewSQLiteTaskMetadataManager builds a TaskMetadataManager backed by an embedded SQLite file.
+// The database must already have the SQLite migrations applied.
+func NewSQLiteTaskMetadataManager(ctx context.Context, db *sqlite.DB) (TaskMetadataManager, error) {
+	logger := logging.GetLoggerFromContext(ctx)
+	logger.Debug("Building SQLite task metadata manager")
+	sqliteLocker, err := ffsync.NewSQLiteLocker(ctx, db)
+	if err != nil {
+		logger.Errorw("Failed to initialize SQLite locker", "err", err)
+		return TaskMetadataManager{}, err
+	}
+
+	logger.Debug("Building SQLite storage impl")
+	sqliteStorage, err := ss.NewSQLiteStorageImplementation(ctx, db, "ff_task_metadata")
+	if err != nil {
+		logger.Errorw("Failed to initialize SQLite storage", "err", err)
+		return TaskMetadataManager{}, err
+	}
+
+	sqliteMetadataStorage := ss.MetadataStorage{
+		Locker:          ffsync.NewInstrumentedLocker("sqlite", sqliteLocker),
+		Storage:         sqliteStorage,
+		Logger:          logger,
+		SkipListLocking: true,
+	}
+
+	logger.Debug("Building SQLite ordered ID generator")
+	idGenerator, err := ffsync.NewSQLiteOrderedIdGenerator(ctx, db)
+	if err != nil {
+		logger.Errorw("Failed to initialize SQLite ordered-id-generator", "err", err)
+		return TaskMetadataManager{}, err
+	}
+
+	logger.Debug("Building slack notifier")
+	slackChannel := cfg.GetSlackChannelId()
+	slackNotif := notifications.NewSlackNotifier(slackChannel, logger)
+	runNotifier, err := newRunNotifier(logger)
+	if err != nil {
+		logger.Errorw("Failed to build run notifier", "err", err)
+		return TaskMetadataManager{}, err
+	}
+
+	logger.Info("SQLite TaskMetadataManager successfully created.")
+	return TaskMetadataManager{
+		Storage:     sqliteMetadataStorage,
+		idGenerator: idGenerator,
+		notifier:    slackNotif,
+		runNotifier: runNotifier,
 	}, nil
 }
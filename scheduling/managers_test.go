@@ -0,0 +1,79 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package scheduling
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/featureform/db"
+	"github.com/featureform/ffsync"
+	"github.com/featureform/helpers/sqlite"
+	"github.com/featureform/logging"
+)
+
+func openSQLiteTaskMetadataManager(ctx context.Context, t *testing.T, path string) (TaskMetadataManager, *sqlite.DB) {
+	t.Helper()
+	liteDB, err := sqlite.NewDB(ctx, sqlite.Config{Path: path})
+	if err != nil {
+		t.Fatalf("failed to open sqlite database: %v", err)
+	}
+	if err := db.RunSQLiteMigrations(ctx, liteDB); err != nil {
+		t.Fatalf("failed to run sqlite migrations: %v", err)
+	}
+	manager, err := NewSQLiteTaskMetadataManager(ctx, liteDB)
+	if err != nil {
+		t.Fatalf("failed to create sqlite task metadata manager: %v", err)
+	}
+	return manager, liteDB
+}
+
+func TestSQLiteTaskMetadataManagerSurvivesRestart(t *testing.T) {
+	ctx := logging.NewTestContext(t)
+	path := filepath.Join(t.TempDir(), "featureform.db")
+	target := NameVariant{"name", "variant", "type"}
+
+	manager, liteDB := openSQLiteTaskMetadataManager(ctx, t, path)
+	task, err := manager.CreateTask(ctx, "name", ResourceCreation, target)
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	run, err := manager.CreateTaskRun(ctx, "run", task.ID, OnApplyTrigger{TriggerName: "apply"})
+	if err != nil {
+		t.Fatalf("failed to create task run: %v", err)
+	}
+	liteDB.Close()
+
+	restarted, liteDB := openSQLiteTaskMetadataManager(ctx, t, path)
+	defer liteDB.Close()
+	tasks, err := restarted.GetAllTasks()
+	if err != nil {
+		t.Fatalf("failed to get tasks: %v", err)
+	}
+	if len(tasks) != 1 || !tasks[0].ID.Equals(task.ID) {
+		t.Fatalf("expected task %s to survive restart, got %v", task.ID, tasks)
+	}
+	runs, err := restarted.GetRunsByDate(time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to get runs: %v", err)
+	}
+	if len(runs) != 1 || !runs[0].ID.Equals(run.ID) {
+		t.Fatalf("expected run %s to survive restart, got %v", run.ID, runs)
+	}
+
+	// IDs must keep counting from where the previous process left off.
+	next, err := restarted.CreateTask(ctx, "name2", ResourceCreation, target)
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	if !next.ID.Equals(TaskID(ffsync.Uint64OrderedId(2))) {
+		t.Fatalf("expected next task id to be 2, got %s", next.ID)
+	}
+}
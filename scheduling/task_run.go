@@ -10,6 +10,7 @@ package scheduling
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 
 	"github.com/featureform/fferr"
 	"github.com/featureform/ffsync"
+	fftypes "github.com/featureform/fftypes"
 	ptypes "github.com/featureform/provider/types"
 )
 
@@ -171,6 +173,7 @@ type TriggerType int32
 const (
 	OnApplyTriggerType  TriggerType = TriggerType(sch.TriggerType_ON_APPLY)
 	ScheduleTriggerType TriggerType = TriggerType(sch.TriggerType_SCHEDULE)
+	BackfillTriggerType TriggerType = TriggerType(sch.TriggerType_BACKFILL)
 )
 
 func (tt TriggerType) String() string {
@@ -211,6 +214,22 @@ func (t ScheduleTrigger) Name() string {
 	return t.TriggerName
 }
 
+// BackfillTrigger reruns an incremental transformation over the rows with timestamps in [Start, End),
+// replacing what the table had for that range.
+type BackfillTrigger struct {
+	TriggerName string    `json:"triggerName"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+}
+
+func (t BackfillTrigger) Type() TriggerType {
+	return BackfillTriggerType
+}
+
+func (t BackfillTrigger) Name() string {
+	return t.TriggerName
+}
+
 type TaskRunMetadata struct {
 	ID             TaskRunID       `json:"runId"`
 	TaskId         TaskID          `json:"taskId"`
@@ -232,6 +251,22 @@ type TaskRunMetadata struct {
 	SchedulerID    ct.SchedulerID  `json:"schedulerId"`
 	RunIteration   string          `json:"runIteration"`
 	ErrorProto     *pb.ErrorStatus
+	// Watermarks are the newest timestamps, by source name and variant, that a run of an
+	// incremental transformation has read. The next run reads only rows after them.
+	Watermarks map[string]time.Time `json:"watermarks,omitempty"`
+	// SchemaContract is the schema a primary source had when it was registered. Tasks that read
+	// the source check its live schema against it.
+	SchemaContract *SchemaContract `json:"schemaContract,omitempty"`
+	// ExpectationResults are the outcomes of the data quality expectations checked after the run.
+	ExpectationResults []ExpectationResult `json:"expectationResults,omitempty"`
+	// Profile summarizes the data a feature or training set run produced.
+	Profile *fftypes.DatasetProfile `json:"profile,omitempty"`
+	// Snapshots pin the data a training set run read and wrote, so it can be re-created and
+	// served as it was.
+	Snapshots []fftypes.DataSnapshot `json:"snapshots,omitempty"`
+	// TraceParent is the W3C traceparent of the request that created the run, the span that
+	// executes the run links back to it.
+	TraceParent string `json:"traceParent,omitempty"`
 }
 
 func (t *TaskRunMetadata) Marshal() ([]byte, error) {
@@ -244,24 +279,30 @@ func (t *TaskRunMetadata) Marshal() ([]byte, error) {
 
 func (t *TaskRunMetadata) Unmarshal(data []byte) error {
 	type tempConfig struct {
-		ID             uint64          `json:"runId"`
-		TaskId         uint64          `json:"taskId"`
-		Name           string          `json:"name"`
-		Trigger        json.RawMessage `json:"trigger"`
-		TriggerType    TriggerType     `json:"triggerType"`
-		Target         json.RawMessage `json:"target"`
-		TargetType     TargetType      `json:"targetType"`
-		Status         Status          `json:"status"`
-		StartTime      time.Time       `json:"startTime"`
-		EndTime        time.Time       `json:"endTime"`
-		Logs           []string        `json:"logs"`
-		Error          string          `json:"error"`
-		ResumeID       string          `json:"resumeID"`
-		ErrorProto     *pb.ErrorStatus
-		LastSuccessful uint64         `json:"lastSuccessful"`
-		IsDelete       bool           `json:"isDelete"`
-		SchedulerID    ct.SchedulerID `json:"schedulerId"`
-		RunIteration   string         `json:"runIteration"`
+		ID                 uint64          `json:"runId"`
+		TaskId             uint64          `json:"taskId"`
+		Name               string          `json:"name"`
+		Trigger            json.RawMessage `json:"trigger"`
+		TriggerType        TriggerType     `json:"triggerType"`
+		Target             json.RawMessage `json:"target"`
+		TargetType         TargetType      `json:"targetType"`
+		Status             Status          `json:"status"`
+		StartTime          time.Time       `json:"startTime"`
+		EndTime            time.Time       `json:"endTime"`
+		Logs               []string        `json:"logs"`
+		Error              string          `json:"error"`
+		ResumeID           string          `json:"resumeID"`
+		ErrorProto         *pb.ErrorStatus
+		LastSuccessful     uint64                  `json:"lastSuccessful"`
+		IsDelete           bool                    `json:"isDelete"`
+		SchedulerID        ct.SchedulerID          `json:"schedulerId"`
+		RunIteration       string                  `json:"runIteration"`
+		Watermarks         map[string]time.Time    `json:"watermarks"`
+		SchemaContract     *SchemaContract         `json:"schemaContract"`
+		ExpectationResults []ExpectationResult     `json:"expectationResults"`
+		Profile            *fftypes.DatasetProfile `json:"profile"`
+		Snapshots          []fftypes.DataSnapshot  `json:"snapshots"`
+		TraceParent        string                  `json:"traceParent"`
 	}
 
 	var temp tempConfig
@@ -301,6 +342,12 @@ func (t *TaskRunMetadata) Unmarshal(data []byte) error {
 	t.IsDelete = temp.IsDelete
 	t.RunIteration = temp.RunIteration
 	t.SchedulerID = temp.SchedulerID
+	t.Watermarks = temp.Watermarks
+	t.SchemaContract = temp.SchemaContract
+	t.ExpectationResults = temp.ExpectationResults
+	t.Profile = temp.Profile
+	t.Snapshots = temp.Snapshots
+	t.TraceParent = temp.TraceParent
 
 	triggerMap := make(map[string]interface{})
 	if err := json.Unmarshal(temp.Trigger, &triggerMap); err != nil {
@@ -323,6 +370,13 @@ func (t *TaskRunMetadata) Unmarshal(data []byte) error {
 			return fferr.NewInternalError(errMessage)
 		}
 		t.Trigger = scheduleTrigger
+	case BackfillTriggerType:
+		var backfillTrigger BackfillTrigger
+		if err := json.Unmarshal(temp.Trigger, &backfillTrigger); err != nil {
+			errMessage := fmt.Errorf("failed to deserialize Backfill Trigger data: %w", err)
+			return fferr.NewInternalError(errMessage)
+		}
+		t.Trigger = backfillTrigger
 	default:
 		errMessage := fmt.Errorf("unknown trigger type: %s", temp.TriggerType)
 		return fferr.NewInvalidArgumentError(errMessage)
@@ -378,11 +432,17 @@ func (run *TaskRunMetadata) ToProto() (*sch.TaskRunMetadata, error) {
 			ErrorMessage: run.Error,
 			ErrorStatus:  run.ErrorProto,
 		},
-		ResumeID:       &sch.ResumeID{Id: run.ResumeID.String()},
-		LastSuccessful: lsid,
-		IsDelete:       run.IsDelete,
-		RunIteration:   run.RunIteration,
-		SchedulerID:    string(run.SchedulerID),
+		ResumeID:           &sch.ResumeID{Id: run.ResumeID.String()},
+		LastSuccessful:     lsid,
+		IsDelete:           run.IsDelete,
+		RunIteration:       run.RunIteration,
+		SchedulerID:        string(run.SchedulerID),
+		Watermarks:         WatermarksToProto(run.Watermarks),
+		SchemaContract:     run.SchemaContract.ToProto(),
+		ExpectationResults: ExpectationResultsToProto(run.ExpectationResults),
+		Profile:            DatasetProfileToProto(run.Profile),
+		Snapshots:          DataSnapshotsToProto(run.Snapshots),
+		TraceParent:        run.TraceParent,
 	}
 
 	taskRunMetadata, err := setTriggerProto(taskRunMetadata, run.Trigger)
@@ -404,6 +464,8 @@ func setTriggerProto(proto *sch.TaskRunMetadata, trigger Trigger) (*sch.TaskRunM
 		proto.Trigger = getApplyTrigger(t)
 	case ScheduleTrigger:
 		proto.Trigger = getScheduleTrigger(t)
+	case BackfillTrigger:
+		proto.Trigger = getBackfillTrigger(t)
 	default:
 		return nil, fferr.NewUnimplementedErrorf("could not convert trigger to proto: type: %T", trigger)
 	}
@@ -468,6 +530,270 @@ func getScheduleTrigger(trigger ScheduleTrigger) *sch.TaskRunMetadata_Schedule {
 	}
 }
 
+func getBackfillTrigger(trigger BackfillTrigger) *sch.TaskRunMetadata_Backfill {
+	return &sch.TaskRunMetadata_Backfill{
+		Backfill: &sch.BackfillTrigger{
+			Name:  trigger.Name(),
+			Start: wrapTimestampProto(trigger.Start),
+			End:   wrapTimestampProto(trigger.End),
+		},
+	}
+}
+
+func WatermarksToProto(watermarks map[string]time.Time) []*sch.SourceWatermark {
+	protos := make([]*sch.SourceWatermark, 0, len(watermarks))
+	for source, watermark := range watermarks {
+		protos = append(protos, &sch.SourceWatermark{Source: source, Watermark: wrapTimestampProto(watermark)})
+	}
+	sort.Slice(protos, func(i, j int) bool { return protos[i].Source < protos[j].Source })
+	return protos
+}
+
+func WatermarksFromProto(protos []*sch.SourceWatermark) map[string]time.Time {
+	if len(protos) == 0 {
+		return nil
+	}
+	watermarks := make(map[string]time.Time, len(protos))
+	for _, w := range protos {
+		watermarks[w.GetSource()] = w.GetWatermark().AsTime()
+	}
+	return watermarks
+}
+
+// SchemaContract is the typed schema of a primary source, kept in a form that serializes.
+type SchemaContract struct {
+	Columns []ContractColumn `json:"columns"`
+}
+
+type ContractColumn struct {
+	Name       string `json:"name"`
+	NativeType string `json:"nativeType,omitempty"`
+	// ValueType is serialized with fftypes.SerializeType, or empty if it's unknown.
+	ValueType string `json:"valueType,omitempty"`
+}
+
+func NewSchemaContract(schema fftypes.Schema) *SchemaContract {
+	contract := &SchemaContract{Columns: make([]ContractColumn, len(schema.Fields))}
+	for i, field := range schema.Fields {
+		column := ContractColumn{Name: string(field.Name)}
+		if field.NativeType != nil {
+			column.NativeType = field.NativeType.TypeName()
+		}
+		if field.Type != nil {
+			column.ValueType = fftypes.SerializeType(field.Type)
+		}
+		contract.Columns[i] = column
+	}
+	return contract
+}
+
+func (c *SchemaContract) Schema() (fftypes.Schema, error) {
+	fields := make([]fftypes.ColumnSchema, len(c.Columns))
+	for i, column := range c.Columns {
+		field := fftypes.ColumnSchema{Name: fftypes.ColumnName(column.Name)}
+		if column.NativeType != "" {
+			field.NativeType = fftypes.NativeTypeLiteral(column.NativeType)
+		}
+		if column.ValueType != "" {
+			valueType, err := fftypes.DeserializeType(column.ValueType)
+			if err != nil {
+				return fftypes.Schema{}, fferr.NewInternalErrorf("failed to deserialize type of column %s: %v", column.Name, err)
+			}
+			field.Type = valueType
+		}
+		fields[i] = field
+	}
+	return fftypes.Schema{Fields: fields}, nil
+}
+
+func (c *SchemaContract) ToProto() *sch.SchemaContract {
+	if c == nil {
+		return nil
+	}
+	columns := make([]*sch.ContractColumn, len(c.Columns))
+	for i, column := range c.Columns {
+		columns[i] = &sch.ContractColumn{Name: column.Name, NativeType: column.NativeType}
+		if column.ValueType == "" {
+			continue
+		}
+		valueType, err := fftypes.DeserializeType(column.ValueType)
+		if err != nil {
+			continue
+		}
+		// Types without a proto equivalent are left unset and only checked for presence.
+		if _, err := valueType.Scalar().ToProtoEnum(); err == nil {
+			columns[i].ValueType = valueType.ToProto()
+		}
+	}
+	return &sch.SchemaContract{Columns: columns}
+}
+
+func SchemaContractFromProto(proto *sch.SchemaContract) *SchemaContract {
+	if proto == nil {
+		return nil
+	}
+	contract := &SchemaContract{Columns: make([]ContractColumn, len(proto.GetColumns()))}
+	for i, column := range proto.GetColumns() {
+		contract.Columns[i] = ContractColumn{Name: column.GetName(), NativeType: column.GetNativeType()}
+		if column.GetValueType() == nil {
+			continue
+		}
+		if valueType, err := fftypes.ValueTypeFromProto(column.GetValueType()); err == nil {
+			contract.Columns[i].ValueType = fftypes.SerializeType(valueType)
+		}
+	}
+	return contract
+}
+
+// ExpectationResult is the outcome of a data quality expectation checked after a run.
+type ExpectationResult struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Blocking bool   `json:"blocking"`
+	Passed   bool   `json:"passed"`
+	// Observed is what the check measured: a null fraction, a count of out-of-range or duplicate
+	// rows, or a row count change.
+	Observed float64 `json:"observed"`
+	RowCount int64   `json:"rowCount"`
+	Message  string  `json:"message,omitempty"`
+}
+
+func ExpectationResultsToProto(results []ExpectationResult) []*sch.ExpectationResult {
+	protos := make([]*sch.ExpectationResult, len(results))
+	for i, r := range results {
+		protos[i] = &sch.ExpectationResult{
+			Name:     r.Name,
+			Type:     r.Type,
+			Blocking: r.Blocking,
+			Passed:   r.Passed,
+			Observed: r.Observed,
+			RowCount: r.RowCount,
+			Message:  r.Message,
+		}
+	}
+	return protos
+}
+
+func ExpectationResultsFromProto(protos []*sch.ExpectationResult) []ExpectationResult {
+	if len(protos) == 0 {
+		return nil
+	}
+	results := make([]ExpectationResult, len(protos))
+	for i, r := range protos {
+		results[i] = ExpectationResult{
+			Name:     r.GetName(),
+			Type:     r.GetType(),
+			Blocking: r.GetBlocking(),
+			Passed:   r.GetPassed(),
+			Observed: r.GetObserved(),
+			RowCount: r.GetRowCount(),
+			Message:  r.GetMessage(),
+		}
+	}
+	return results
+}
+
+func DatasetProfileToProto(profile *fftypes.DatasetProfile) *pb.DatasetProfile {
+	if profile == nil {
+		return nil
+	}
+	columns := make([]*pb.ColumnProfile, len(profile.Columns))
+	for i, c := range profile.Columns {
+		topValues := make([]*pb.ValueCount, len(c.TopValues))
+		for j, v := range c.TopValues {
+			topValues[j] = &pb.ValueCount{Value: v.Value, Count: v.Count}
+		}
+		histogram := make([]*pb.HistogramBucket, len(c.Histogram))
+		for j, b := range c.Histogram {
+			histogram[j] = &pb.HistogramBucket{Lower: b.Lower, Upper: b.Upper, Count: b.Count}
+		}
+		columns[i] = &pb.ColumnProfile{
+			Name:          c.Name,
+			Type:          c.Type,
+			Count:         c.Count,
+			NullCount:     c.NullCount,
+			DistinctCount: c.DistinctCount,
+			Numeric:       c.Numeric,
+			Min:           c.Min,
+			Max:           c.Max,
+			Mean:          c.Mean,
+			Stddev:        c.StdDev,
+			TopValues:     topValues,
+			Histogram:     histogram,
+		}
+	}
+	return &pb.DatasetProfile{RowCount: profile.RowCount, Columns: columns}
+}
+
+func DatasetProfileFromProto(proto *pb.DatasetProfile) *fftypes.DatasetProfile {
+	if proto == nil {
+		return nil
+	}
+	profile := &fftypes.DatasetProfile{RowCount: proto.GetRowCount(), Columns: make([]fftypes.ColumnProfile, len(proto.GetColumns()))}
+	for i, c := range proto.GetColumns() {
+		var topValues []fftypes.ValueCount
+		for _, v := range c.GetTopValues() {
+			topValues = append(topValues, fftypes.ValueCount{Value: v.GetValue(), Count: v.GetCount()})
+		}
+		var histogram []fftypes.HistogramBucket
+		for _, b := range c.GetHistogram() {
+			histogram = append(histogram, fftypes.HistogramBucket{Lower: b.GetLower(), Upper: b.GetUpper(), Count: b.GetCount()})
+		}
+		profile.Columns[i] = fftypes.ColumnProfile{
+			Name:          c.GetName(),
+			Type:          c.GetType(),
+			Count:         c.GetCount(),
+			NullCount:     c.GetNullCount(),
+			DistinctCount: c.GetDistinctCount(),
+			Numeric:       c.GetNumeric(),
+			Min:           c.GetMin(),
+			Max:           c.GetMax(),
+			Mean:          c.GetMean(),
+			StdDev:        c.GetStddev(),
+			TopValues:     topValues,
+			Histogram:     histogram,
+		}
+	}
+	return profile
+}
+
+func DataSnapshotsToProto(snapshots []fftypes.DataSnapshot) []*sch.DataSnapshot {
+	protos := make([]*sch.DataSnapshot, len(snapshots))
+	for i, s := range snapshots {
+		protos[i] = &sch.DataSnapshot{
+			Resource:    s.Resource,
+			Location:    s.Location,
+			Kind:        string(s.Kind),
+			Version:     s.Version,
+			TableFormat: s.TableFormat,
+			Timestamp:   wrapTimestampProto(s.Timestamp),
+			Output:      s.Output,
+			Released:    s.Released,
+		}
+	}
+	return protos
+}
+
+func DataSnapshotsFromProto(protos []*sch.DataSnapshot) []fftypes.DataSnapshot {
+	if len(protos) == 0 {
+		return nil
+	}
+	snapshots := make([]fftypes.DataSnapshot, len(protos))
+	for i, s := range protos {
+		snapshots[i] = fftypes.DataSnapshot{
+			Resource:    s.GetResource(),
+			Location:    s.GetLocation(),
+			Kind:        fftypes.SnapshotKind(s.GetKind()),
+			Version:     s.GetVersion(),
+			TableFormat: s.GetTableFormat(),
+			Timestamp:   s.GetTimestamp().AsTime(),
+			Output:      s.GetOutput(),
+			Released:    s.GetReleased(),
+		}
+	}
+	return snapshots
+}
+
 func TaskRunMetadataFromProto(run *sch.TaskRunMetadata) (TaskRunMetadata, error) {
 	rid, err := ParseTaskRunID(run.RunID.Id)
 	if err != nil {
@@ -496,24 +822,30 @@ func TaskRunMetadataFromProto(run *sch.TaskRunMetadata) (TaskRunMetadata, error)
 		return TaskRunMetadata{}, err
 	}
 	return TaskRunMetadata{
-		ID:             rid,
-		TaskId:         tid,
-		Name:           run.Name,
-		Trigger:        t,
-		TriggerType:    TriggerType(run.TriggerType),
-		Target:         target,
-		TargetType:     TargetType(run.TargetType),
-		Status:         Status(run.Status.Status),
-		StartTime:      run.StartTime.AsTime(),
-		EndTime:        run.EndTime.AsTime(),
-		Logs:           run.Logs,
-		Error:          run.Status.ErrorMessage,
-		ErrorProto:     run.Status.ErrorStatus,
-		ResumeID:       ptypes.ResumeID(run.GetResumeID().GetId()),
-		LastSuccessful: lsid,
-		IsDelete:       run.IsDelete,
-		SchedulerID:    ct.SchedulerID(run.SchedulerID),
-		RunIteration:   run.RunIteration,
+		ID:                 rid,
+		TaskId:             tid,
+		Name:               run.Name,
+		Trigger:            t,
+		TriggerType:        TriggerType(run.TriggerType),
+		Target:             target,
+		TargetType:         TargetType(run.TargetType),
+		Status:             Status(run.Status.Status),
+		StartTime:          run.StartTime.AsTime(),
+		EndTime:            run.EndTime.AsTime(),
+		Logs:               run.Logs,
+		Error:              run.Status.ErrorMessage,
+		ErrorProto:         run.Status.ErrorStatus,
+		ResumeID:           ptypes.ResumeID(run.GetResumeID().GetId()),
+		LastSuccessful:     lsid,
+		IsDelete:           run.IsDelete,
+		SchedulerID:        ct.SchedulerID(run.SchedulerID),
+		RunIteration:       run.RunIteration,
+		Watermarks:         WatermarksFromProto(run.GetWatermarks()),
+		SchemaContract:     SchemaContractFromProto(run.GetSchemaContract()),
+		ExpectationResults: ExpectationResultsFromProto(run.GetExpectationResults()),
+		Profile:            DatasetProfileFromProto(run.GetProfile()),
+		Snapshots:          DataSnapshotsFromProto(run.GetSnapshots()),
+		TraceParent:        run.GetTraceParent(),
 	}, nil
 }
 
@@ -523,6 +855,8 @@ func convertProtoTriggerType(trigger interface{}) (Trigger, error) {
 		return OnApplyTrigger{TriggerName: t.Apply.Name}, nil
 	case *sch.TaskRunMetadata_Schedule:
 		return ScheduleTrigger{TriggerName: t.Schedule.Name, Schedule: t.Schedule.Schedule}, nil
+	case *sch.TaskRunMetadata_Backfill:
+		return BackfillTrigger{TriggerName: t.Backfill.Name, Start: t.Backfill.Start.AsTime(), End: t.Backfill.End.AsTime()}, nil
 	default:
 		return nil, fferr.NewUnimplementedErrorf("could not convert trigger type: %T", trigger)
 	}
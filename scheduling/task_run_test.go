@@ -15,6 +15,7 @@ import (
 
 	"github.com/featureform/fferr"
 	"github.com/featureform/ffsync"
+	fftypes "github.com/featureform/fftypes"
 	pb "github.com/featureform/metadata/proto"
 	ptypes "github.com/featureform/provider/types"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -169,6 +170,46 @@ func TestSerializeTaskRunMetadata(t *testing.T) {
 			},
 			triggerType: ScheduleTriggerType,
 		},
+		{
+			name: "WithBackfillTrigger",
+			task: TaskRunMetadata{
+				ID:     TaskRunID(id1),
+				TaskId: TaskID(id1),
+				Name:   "backfill_taskrun",
+				Trigger: BackfillTrigger{
+					TriggerName: "name3",
+					Start:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					End:         time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				},
+				TriggerType: BackfillTriggerType,
+				Target: NameVariant{
+					Name:    "name",
+					Variant: "variant",
+				},
+				TargetType: NameVariantTarget,
+				Status:     READY,
+				StartTime:  time.Now().Truncate(0).UTC(),
+				EndTime:    time.Now().Truncate(0).UTC(),
+				Watermarks: map[string]time.Time{
+					"events.v1": time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+				},
+				ExpectationResults: []ExpectationResult{
+					{Name: "few_nulls", Type: "EXPECT_NULL_FRACTION", Blocking: true, Passed: true, Observed: 0.01, RowCount: 100, Message: "0.01 of 100 rows are null"},
+				},
+				Profile: &fftypes.DatasetProfile{
+					RowCount: 100,
+					Columns: []fftypes.ColumnProfile{
+						{Name: "entity", Type: "string", Count: 100, DistinctCount: 2, TopValues: []fftypes.ValueCount{{Value: "a", Count: 60}, {Value: "b", Count: 40}}},
+						{Name: "value", Type: "float64", Count: 99, NullCount: 1, DistinctCount: 80, Numeric: true, Min: -1, Max: 10, Mean: 2.5, StdDev: 1.5, Histogram: []fftypes.HistogramBucket{{Lower: -1, Upper: 4.5, Count: 70}, {Lower: 4.5, Upper: 10, Count: 29}}},
+					},
+				},
+				Snapshots: []fftypes.DataSnapshot{
+					{Resource: "label churn (v1)", Location: "DB.PUBLIC.EVENTS", Kind: fftypes.TimeTravelSnapshot, Timestamp: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+					{Resource: "training set churn (v1)", Location: "s3://bucket/featureform/TrainingSet/churn/v1", Kind: fftypes.TableVersionSnapshot, Version: "3", TableFormat: "delta", Timestamp: time.Date(2024, 3, 1, 12, 5, 0, 0, time.UTC), Output: true},
+				},
+			},
+			triggerType: BackfillTriggerType,
+		},
 	}
 
 	for _, currTest := range testCases {
@@ -466,6 +507,48 @@ func Test_wrapTaskRunMetadataProto(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"Backfill",
+			TaskRunMetadata{
+				ID:     TaskRunID(id),
+				TaskId: TaskID(id),
+				Trigger: BackfillTrigger{
+					TriggerName: "trigger_name",
+					Start:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					End:         time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				},
+				TriggerType: BackfillTriggerType,
+				Target: NameVariant{
+					Name:         "name",
+					Variant:      "variant",
+					ResourceType: "SOURCE",
+				},
+				TargetType: NameVariantTarget,
+				Status:     READY,
+				StartTime:  time.Now().UTC(),
+				EndTime:    time.Now().AddDate(0, 0, 1).UTC(),
+				Logs:       []string{"log1", "log2"},
+				Watermarks: map[string]time.Time{
+					"events.v1": time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+					"users.v1":  time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC),
+				},
+				ExpectationResults: []ExpectationResult{
+					{Name: "stable_size", Type: "EXPECT_ROW_COUNT_CHANGE", Observed: 0.5, RowCount: 150},
+				},
+				Profile: &fftypes.DatasetProfile{
+					RowCount: 150,
+					Columns: []fftypes.ColumnProfile{
+						{Name: "value", Count: 150, DistinctCount: 3, TopValues: []fftypes.ValueCount{{Value: "x", Count: 100}}},
+						{Name: "amount", Count: 150, Numeric: true, Max: 1, Histogram: []fftypes.HistogramBucket{{Upper: 1, Count: 150}}},
+					},
+				},
+				Snapshots: []fftypes.DataSnapshot{
+					{Resource: "feature amount (v1)", Location: "s3://bucket/featureform/Transformation/txns/v1", Kind: fftypes.FileCopySnapshot, Version: "s3://bucket/featureform/Snapshots/churn/v1/1709294400000/input_0", Timestamp: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+					{Resource: "training set churn (v1)", Location: "s3://bucket/featureform/TrainingSet/churn/v1", Kind: fftypes.FileCopySnapshot, Version: "s3://bucket/featureform/Snapshots/churn/v1/1709294400000/output", Timestamp: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), Output: true, Released: true},
+				},
+			},
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -485,3 +568,53 @@ func Test_wrapTaskRunMetadataProto(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaContract(t *testing.T) {
+	schema := fftypes.Schema{
+		Fields: []fftypes.ColumnSchema{
+			{Name: "entity", Type: fftypes.String, NativeType: fftypes.NativeTypeLiteral("VARCHAR")},
+			{Name: "value", Type: fftypes.Int64, NativeType: fftypes.NativeTypeLiteral("BIGINT")},
+			{Name: "ts"},
+		},
+	}
+	contract := NewSchemaContract(schema)
+	got, err := contract.Schema()
+	if err != nil {
+		t.Fatalf("failed to read contract schema: %v", err)
+	}
+	if drift := schema.Drift(got); !drift.IsEmpty() {
+		t.Fatalf("contract schema drifted from its source: %s", drift)
+	}
+
+	id := ffsync.Uint64OrderedId(1)
+	run := TaskRunMetadata{
+		ID:             TaskRunID(id),
+		TaskId:         TaskID(id),
+		Name:           "primary_taskrun",
+		Trigger:        OnApplyTrigger{TriggerName: "apply"},
+		TriggerType:    OnApplyTriggerType,
+		Target:         NameVariant{Name: "name", Variant: "variant", ResourceType: "SOURCE"},
+		TargetType:     NameVariantTarget,
+		Status:         READY,
+		StartTime:      time.Now().Truncate(0).UTC(),
+		SchemaContract: contract,
+	}
+	serialized, err := run.Marshal()
+	if err != nil {
+		t.Fatalf("failed to serialize task run metadata: %v", err)
+	}
+	deserialized := TaskRunMetadata{}
+	if err := deserialized.Unmarshal(serialized); err != nil {
+		t.Fatalf("failed to deserialize task run metadata: %v", err)
+	}
+	if !reflect.DeepEqual(deserialized.SchemaContract, contract) {
+		t.Fatalf("Wrong contract after JSON round trip, \ngot: %#v\nExpected: %#v", deserialized.SchemaContract, contract)
+	}
+
+	if fromProto := SchemaContractFromProto(contract.ToProto()); !reflect.DeepEqual(fromProto, contract) {
+		t.Fatalf("Wrong contract after proto round trip, \ngot: %#v\nExpected: %#v", fromProto, contract)
+	}
+	if (*SchemaContract)(nil).ToProto() != nil || SchemaContractFromProto(nil) != nil {
+		t.Fatalf("Expected nil contracts to stay nil")
+	}
+}
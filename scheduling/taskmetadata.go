@@ -21,8 +21,10 @@ import (
 
 	"github.com/featureform/fferr"
 	"github.com/featureform/ffsync"
+	fftypes "github.com/featureform/fftypes"
 	ptypes "github.com/featureform/provider/types"
 	ss "github.com/featureform/storage"
+	"github.com/featureform/tracing"
 )
 
 const (
@@ -47,9 +49,16 @@ func (trl TaskRunList) FilterByStatus(statuses ...Status) TaskRunList {
 type TaskManagerType string
 
 type TaskMetadataManager struct {
-	Storage     ss.MetadataStorage
-	idGenerator ffsync.OrderedIdGenerator
-	notifier    notifications.Notifier
+	Storage       ss.MetadataStorage
+	idGenerator   ffsync.OrderedIdGenerator
+	notifier      notifications.Notifier
+	runNotifier   notifications.RunNotifier
+	ownerResolver notifications.OwnerResolver
+}
+
+// SetOwnerResolver lets run notifications be routed by the owner of the run's resource.
+func (m *TaskMetadataManager) SetOwnerResolver(resolver notifications.OwnerResolver) {
+	m.ownerResolver = resolver
 }
 
 func (m *TaskMetadataManager) SyncIncompleteRuns() error {
@@ -139,6 +148,13 @@ func (m *TaskMetadataManager) GetTaskByID(id TaskID) (TaskMetadata, error) {
 	return taskMetadata, nil
 }
 
+// CheckHealth checks that the manager can read its task runs, which is cheaper than listing
+// them since only the incomplete runs are counted.
+func (m *TaskMetadataManager) CheckHealth() error {
+	_, err := m.Storage.Count(taskRunIncompleteKeyPrefix)
+	return err
+}
+
 func (m *TaskMetadataManager) GetAllTasks() (TaskMetadataList, error) {
 	metadata, err := m.Storage.List(TaskMetadataKey{}.String())
 	if err != nil {
@@ -231,6 +247,7 @@ func (m *TaskMetadataManager) CreateTaskRun(ctx context.Context, name string, ta
 		StartTime:      startTime,
 		LastSuccessful: lastSuccess,
 		IsDelete:       isDelete,
+		TraceParent:    tracing.TraceParent(ctx),
 	}
 
 	runs.Runs = append(runs.Runs, TaskRunSimple{RunID: metadata.ID, DateCreated: startTime})
@@ -528,6 +545,9 @@ func (m *TaskMetadataManager) SetRunStatus(ctx context.Context, runID TaskRunID,
 	//fire off notification if status changes
 	if prevStatus != newStatus {
 		m.notifyChange(updatedMetadata, updateErr)
+		if updateErr == nil {
+			m.notifyRun(ctx, prevStatus, updatedMetadata)
+		}
 	} else {
 		m.Storage.Logger.Debugf("status has not changed, do not notify status: %s", prevStatus)
 	}
@@ -573,6 +593,45 @@ func (m *TaskMetadataManager) notifyChange(updatedMetadata TaskRunMetadata, upda
 	}()
 }
 
+// notifyRun sends the transition to the configured run notification sinks in the background.
+func (m *TaskMetadataManager) notifyRun(ctx context.Context, prevStatus Status, run TaskRunMetadata) {
+	if m.runNotifier == nil {
+		return
+	}
+	event := notifications.RunEvent{
+		TaskID:         run.TaskId.String(),
+		RunID:          run.ID.String(),
+		RunName:        run.Name,
+		PreviousStatus: prevStatus.String(),
+		Status:         run.Status.String(),
+		Error:          run.Error,
+		Time:           time.Now().UTC(),
+	}
+	switch target := run.Target.(type) {
+	case NameVariant:
+		event.ResourceType = target.ResourceType
+		event.Name = target.Name
+		event.Variant = target.Variant
+	case Provider:
+		event.ResourceType = "PROVIDER"
+		event.Name = target.Name
+	}
+	// The caller's context may be cancelled as soon as the status is saved.
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if m.ownerResolver != nil && event.Name != "" {
+			owner, err := m.ownerResolver(ctx, event.ResourceType, event.Name, event.Variant)
+			if err != nil {
+				m.Storage.Logger.Warnw("Failed to resolve owner for run notification", "task_id", event.TaskID, "run_id", event.RunID, "err", err)
+			}
+			event.Owner = owner
+		}
+		if err := m.runNotifier.NotifyRun(ctx, event); err != nil {
+			m.Storage.Logger.Errorw("Failed to send run notification", "task_id", event.TaskID, "run_id", event.RunID, "status", event.Status, "err", err)
+		}
+	}()
+}
+
 func (m *TaskMetadataManager) SetResumeID(runID TaskRunID, taskID TaskID, id ptypes.ResumeID) error {
 	metadata, err := m.GetRunByID(taskID, runID)
 	if err != nil {
@@ -596,6 +655,121 @@ func (m *TaskMetadataManager) SetResumeID(runID TaskRunID, taskID TaskID, id pty
 	return err
 }
 
+func (m *TaskMetadataManager) SetRunWatermarks(runID TaskRunID, taskID TaskID, watermarks map[string]time.Time) error {
+	metadata, err := m.GetRunByID(taskID, runID)
+	if err != nil {
+		return err
+	}
+	updateWatermarks := func(runMetadata string) (string, error) {
+		metadata := TaskRunMetadata{}
+		err := metadata.Unmarshal([]byte(runMetadata))
+		if err != nil {
+			return "", err
+		}
+		metadata.Watermarks = watermarks
+		serializedMetadata, err := metadata.Marshal()
+		if err != nil {
+			return "", err
+		}
+		return string(serializedMetadata), nil
+	}
+	taskRunMetadataKey := TaskRunMetadataKey{taskID: taskID, runID: metadata.ID, date: metadata.StartTime}
+	err = m.Storage.Update(taskRunMetadataKey.String(), updateWatermarks)
+	return err
+}
+
+func (m *TaskMetadataManager) SetRunSchemaContract(runID TaskRunID, taskID TaskID, contract *SchemaContract) error {
+	metadata, err := m.GetRunByID(taskID, runID)
+	if err != nil {
+		return err
+	}
+	updateContract := func(runMetadata string) (string, error) {
+		metadata := TaskRunMetadata{}
+		err := metadata.Unmarshal([]byte(runMetadata))
+		if err != nil {
+			return "", err
+		}
+		metadata.SchemaContract = contract
+		serializedMetadata, err := metadata.Marshal()
+		if err != nil {
+			return "", err
+		}
+		return string(serializedMetadata), nil
+	}
+	taskRunMetadataKey := TaskRunMetadataKey{taskID: taskID, runID: metadata.ID, date: metadata.StartTime}
+	err = m.Storage.Update(taskRunMetadataKey.String(), updateContract)
+	return err
+}
+
+func (m *TaskMetadataManager) SetRunExpectationResults(runID TaskRunID, taskID TaskID, results []ExpectationResult) error {
+	metadata, err := m.GetRunByID(taskID, runID)
+	if err != nil {
+		return err
+	}
+	updateResults := func(runMetadata string) (string, error) {
+		metadata := TaskRunMetadata{}
+		err := metadata.Unmarshal([]byte(runMetadata))
+		if err != nil {
+			return "", err
+		}
+		metadata.ExpectationResults = results
+		serializedMetadata, err := metadata.Marshal()
+		if err != nil {
+			return "", err
+		}
+		return string(serializedMetadata), nil
+	}
+	taskRunMetadataKey := TaskRunMetadataKey{taskID: taskID, runID: metadata.ID, date: metadata.StartTime}
+	err = m.Storage.Update(taskRunMetadataKey.String(), updateResults)
+	return err
+}
+
+func (m *TaskMetadataManager) SetRunProfile(runID TaskRunID, taskID TaskID, profile *fftypes.DatasetProfile) error {
+	metadata, err := m.GetRunByID(taskID, runID)
+	if err != nil {
+		return err
+	}
+	updateProfile := func(runMetadata string) (string, error) {
+		metadata := TaskRunMetadata{}
+		err := metadata.Unmarshal([]byte(runMetadata))
+		if err != nil {
+			return "", err
+		}
+		metadata.Profile = profile
+		serializedMetadata, err := metadata.Marshal()
+		if err != nil {
+			return "", err
+		}
+		return string(serializedMetadata), nil
+	}
+	taskRunMetadataKey := TaskRunMetadataKey{taskID: taskID, runID: metadata.ID, date: metadata.StartTime}
+	err = m.Storage.Update(taskRunMetadataKey.String(), updateProfile)
+	return err
+}
+
+func (m *TaskMetadataManager) SetRunSnapshots(runID TaskRunID, taskID TaskID, snapshots []fftypes.DataSnapshot) error {
+	metadata, err := m.GetRunByID(taskID, runID)
+	if err != nil {
+		return err
+	}
+	updateSnapshots := func(runMetadata string) (string, error) {
+		metadata := TaskRunMetadata{}
+		err := metadata.Unmarshal([]byte(runMetadata))
+		if err != nil {
+			return "", err
+		}
+		metadata.Snapshots = snapshots
+		serializedMetadata, err := metadata.Marshal()
+		if err != nil {
+			return "", err
+		}
+		return string(serializedMetadata), nil
+	}
+	taskRunMetadataKey := TaskRunMetadataKey{taskID: taskID, runID: metadata.ID, date: metadata.StartTime}
+	err = m.Storage.Update(taskRunMetadataKey.String(), updateSnapshots)
+	return err
+}
+
 func (m *TaskMetadataManager) SetRunEndTime(runID TaskRunID, taskID TaskID, time time.Time) error {
 	if time.IsZero() {
 		errMessage := fmt.Errorf("end time cannot be zero")
@@ -668,12 +842,29 @@ func (m *TaskMetadataManager) AppendRunLog(runID TaskRunID, taskID TaskID, log s
 	return err
 }
 
-// This will just block until logic is implemented
-func (m *TaskMetadataManager) WatchForCancel(runID TaskRunID, taskID TaskID) error {
-	for {
+// cancelPollInterval is how often WatchForCancel checks a run's status.
+var cancelPollInterval = 5 * time.Second
 
+// WatchForCancel blocks until the run is cancelled. It returns an error if the run finishes
+// without being cancelled, or if ctx is done first.
+func (m *TaskMetadataManager) WatchForCancel(ctx context.Context, runID TaskRunID, taskID TaskID) error {
+	for {
+		run, err := m.GetRunByID(taskID, runID)
+		if err != nil {
+			return err
+		}
+		switch run.Status {
+		case CANCELLED:
+			return nil
+		case READY, FAILED:
+			return fferr.NewInternalErrorf("run %s finished with status %s without being cancelled", runID.String(), run.Status.String())
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cancelPollInterval):
+		}
 	}
-	return nil
 }
 
 func (m *TaskMetadataManager) SetRunSchedulerID(tid TaskID, runID TaskRunID, schedulerID ct.SchedulerID, runIteration string) error {
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/featureform/ffsync"
+	"github.com/featureform/helpers/notifications"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata/proto"
 	ptypes "github.com/featureform/provider/types"
@@ -1654,3 +1655,150 @@ func Test_notifyChange(t *testing.T) {
 		})
 	}
 }
+
+type mockRunNotifier struct {
+	events chan notifications.RunEvent
+}
+
+func (m *mockRunNotifier) NotifyRun(ctx context.Context, event notifications.RunEvent) error {
+	m.events <- event
+	return nil
+}
+
+func Test_SetRunStatus_Invokes_RunNotifier(t *testing.T) {
+	ctx := logging.NewTestContext(t)
+	locker, err := ffsync.NewMemoryLocker()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	memoryStorage, err := ss.NewMemoryStorageImplementation()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	storage := ss.MetadataStorage{
+		Locker:          &locker,
+		Storage:         &memoryStorage,
+		SkipListLocking: true,
+		Logger:          logging.WrapZapLogger(zaptest.NewLogger(t).Sugar()),
+	}
+	runNotifier := &mockRunNotifier{events: make(chan notifications.RunEvent, 2)}
+	taskManager := TaskMetadataManager{
+		Storage:     storage,
+		idGenerator: &MockGenerator{},
+		runNotifier: runNotifier,
+	}
+	taskManager.SetOwnerResolver(func(ctx context.Context, resourceType, name, variant string) (string, error) {
+		return "alice", nil
+	})
+
+	task, err := taskManager.CreateTask(ctx, "mytask", ResourceCreation, NameVariant{"transactions", "v1", "SOURCE_VARIANT"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	run, err := taskManager.CreateTaskRun(ctx, "myrun", task.ID, OnApplyTrigger{"apply"})
+	if err != nil {
+		t.Fatalf("Failed to create run: %v", err)
+	}
+	statuses := []*proto.ResourceStatus{
+		{Status: proto.ResourceStatus_RUNNING},
+		{Status: proto.ResourceStatus_FAILED, ErrorMessage: "transformation failed"},
+	}
+	for _, status := range statuses {
+		if err := taskManager.SetRunStatus(ctx, run.ID, task.ID, status); err != nil {
+			t.Fatalf("Failed to set status: %v", err)
+		}
+	}
+
+	events := make(map[string]notifications.RunEvent)
+	for len(events) < len(statuses) {
+		select {
+		case event := <-runNotifier.events:
+			events[event.Status] = event
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for run notifications")
+		}
+	}
+	assert.Equal(t, PENDING.String(), events[RUNNING.String()].PreviousStatus)
+	event := events[FAILED.String()]
+	assert.Equal(t, task.ID.String(), event.TaskID)
+	assert.Equal(t, run.ID.String(), event.RunID)
+	assert.Equal(t, "SOURCE_VARIANT", event.ResourceType)
+	assert.Equal(t, "transactions", event.Name)
+	assert.Equal(t, "v1", event.Variant)
+	assert.Equal(t, "alice", event.Owner)
+	assert.Equal(t, RUNNING.String(), event.PreviousStatus)
+	assert.Equal(t, "transformation failed", event.Error)
+}
+
+func TestWatchForCancel(t *testing.T) {
+	defer func(interval time.Duration) { cancelPollInterval = interval }(cancelPollInterval)
+	cancelPollInterval = 10 * time.Millisecond
+
+	ctx := logging.NewTestContext(t)
+	manager, err := NewMemoryTaskMetadataManager(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	task, err := manager.CreateTask(ctx, "mytask", ResourceCreation, NameVariant{"transactions", "v1", "SOURCE_VARIANT"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	newRunningRun := func(t *testing.T) TaskRunMetadata {
+		run, err := manager.CreateTaskRun(ctx, "myrun", task.ID, OnApplyTrigger{"apply"})
+		if err != nil {
+			t.Fatalf("Failed to create run: %v", err)
+		}
+		if err := manager.SetRunStatus(ctx, run.ID, task.ID, &proto.ResourceStatus{Status: proto.ResourceStatus_RUNNING}); err != nil {
+			t.Fatalf("Failed to set run status: %v", err)
+		}
+		return run
+	}
+	watch := func(ctx context.Context, run TaskRunMetadata) chan error {
+		errs := make(chan error, 1)
+		go func() {
+			errs <- manager.WatchForCancel(ctx, run.ID, task.ID)
+		}()
+		return errs
+	}
+	waitFor := func(t *testing.T, errs chan error) error {
+		select {
+		case err := <-errs:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatalf("WatchForCancel didn't return")
+			return nil
+		}
+	}
+
+	t.Run("Cancelled", func(t *testing.T) {
+		run := newRunningRun(t)
+		errs := watch(ctx, run)
+		if err := manager.SetRunStatus(ctx, run.ID, task.ID, &proto.ResourceStatus{Status: proto.ResourceStatus_CANCELLED}); err != nil {
+			t.Fatalf("Failed to cancel run: %v", err)
+		}
+		if err := waitFor(t, errs); err != nil {
+			t.Fatalf("Expected cancel, got %v", err)
+		}
+	})
+
+	t.Run("Finished", func(t *testing.T) {
+		run := newRunningRun(t)
+		errs := watch(ctx, run)
+		if err := manager.SetRunStatus(ctx, run.ID, task.ID, &proto.ResourceStatus{Status: proto.ResourceStatus_READY}); err != nil {
+			t.Fatalf("Failed to finish run: %v", err)
+		}
+		if err := waitFor(t, errs); err == nil {
+			t.Fatalf("Expected an error for a run that finished without being cancelled")
+		}
+	})
+
+	t.Run("Stopped", func(t *testing.T) {
+		run := newRunningRun(t)
+		watchCtx, stop := context.WithCancel(ctx)
+		errs := watch(watchCtx, run)
+		stop()
+		if err := waitFor(t, errs); err != context.Canceled {
+			t.Fatalf("Expected the watch to stop with its context, got %v", err)
+		}
+	})
+}
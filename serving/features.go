@@ -11,6 +11,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/featureform/fferr"
 	"github.com/featureform/metadata"
@@ -18,6 +19,7 @@ import (
 	pb "github.com/featureform/proto"
 	"github.com/featureform/provider"
 	pt "github.com/featureform/provider/provider_type"
+	"github.com/featureform/tracing"
 )
 
 type indexedValue struct {
@@ -174,7 +176,7 @@ func (serv *FeatureServer) getPrecomputedValues(ctx context.Context, entityMap m
 		return nil, err
 	}
 
-	featureValues, err := serv.getEntityValues(ctx, entities, featureTable)
+	featureValues, err := serv.getEntityValues(ctx, entities, featureTable, store.Type().String(), meta)
 	if err != nil {
 		return nil, err
 	}
@@ -240,7 +242,7 @@ func (serv *FeatureServer) cacheFeatureTable(ctx context.Context, store provider
 	return featureTable, nil
 }
 
-func (serv *FeatureServer) getEntityValues(ctx context.Context, entities []string, featureTable provider.OnlineStoreTable) ([]indexedValue, error) {
+func (serv *FeatureServer) getEntityValues(ctx context.Context, entities []string, featureTable provider.OnlineStoreTable, providerType string, meta *metadata.FeatureVariant) ([]indexedValue, error) {
 	obs := ctx.Value(observer{}).(metrics.FeatureObserver)
 
 	valCh := make(chan indexedValue, len(entities))
@@ -249,7 +251,15 @@ func (serv *FeatureServer) getEntityValues(ctx context.Context, entities []strin
 	for i, entityVal := range entities {
 		// Start a goroutine for each entity
 		go func(index int, ev string) {
+			_, span := tracing.Start(ctx, "provider.OnlineStoreTable.Get",
+				tracing.ProviderTypeKey.String(providerType),
+				tracing.ResourceNameKey.String(meta.Name()),
+				tracing.ResourceVariantKey.String(meta.Variant()),
+			)
+			start := time.Now()
 			val, err := featureTable.Get(ev)
+			metrics.Instrument().ObserveProviderOperation(providerType, "Get", time.Since(start), err)
+			tracing.End(span, err)
 			if err != nil {
 				// Push error into the error channel
 				errCh <- err
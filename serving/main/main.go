@@ -12,6 +12,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
 	_ "net/http/pprof"
@@ -20,18 +21,34 @@ import (
 	grpc_health "google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 
+	"github.com/featureform/auth"
+	"github.com/featureform/config"
 	"github.com/featureform/health"
 	help "github.com/featureform/helpers"
 	"github.com/featureform/helpers/interceptors"
+	"github.com/featureform/helpers/notifications"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
 	"github.com/featureform/metrics"
 	pb "github.com/featureform/proto"
 	"github.com/featureform/serving"
+	"github.com/featureform/tracing"
 )
 
 func main() {
 	logger := logging.NewLogger("serving")
+	shutdownTracing, err := tracing.Init(context.Background(), "serving")
+	if err != nil {
+		logger.Panicw("Failed to configure tracing", "Err", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Errorw("Failed to flush traces", "Err", err)
+		}
+	}()
+	if err := metrics.EnableInstrumentationFromEnv(); err != nil {
+		logger.Panicw("Failed to register metrics", "Err", err)
+	}
 
 	host := help.GetEnv("SERVING_HOST", "0.0.0.0")
 	logger.Infow("Using serving host", "host", host)
@@ -54,7 +71,7 @@ func main() {
 
 	apiStatusPort := help.GetEnv("API_STATUS_PORT", "8443")
 	logger.Infow("Retrieved API status port from ENV", "port", apiStatusPort)
-	if err = health.StartHttpServer(logger, apiStatusPort); err != nil {
+	if err = health.StartHttpServer(logger, apiStatusPort, health.GRPCPeerCheck("metadata", metadataConn)); err != nil {
 		logger.Errorw("Failed to start health check", "err", err)
 		panic(err)
 	}
@@ -68,7 +85,47 @@ func main() {
 	if err != nil {
 		logger.Panicw("Failed to create training server", "Err", err)
 	}
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(interceptors.UnaryServerErrorInterceptor), grpc.StreamInterceptor(interceptors.StreamServerErrorInterceptor))
+	skewConfig, err := serving.SkewConfigFromEnv()
+	if err != nil {
+		logger.Panicw("Failed to configure skew monitoring", "Err", err)
+	}
+	if skewConfig.SampleRate > 0 {
+		sink, err := serving.NewFileSampleSink(skewConfig.SampleDir)
+		if err != nil {
+			logger.Panicw("Failed to create served sample sink", "Err", err)
+		}
+		logger.Infow("Sampling served features for skew monitoring", "rate", skewConfig.SampleRate, "dir", skewConfig.SampleDir)
+		serv.Sampler = serving.NewSampler(sink, skewConfig.SampleRate, logger)
+		defer serv.Sampler.Close()
+		monitor := serving.NewSkewMonitor(meta, sink, skewConfig, logger)
+		dispatcher, err := notifications.NewDispatcherFromFile(logger, config.GetNotificationsConfigPath())
+		if err != nil {
+			logger.Panicw("Failed to configure skew notifications", "Err", err)
+		}
+		if dispatcher != nil {
+			monitor.Notifier = dispatcher
+		}
+		go monitor.Run(context.Background())
+	}
+	authConfig := auth.ConfigFromEnv()
+	opts, err := authConfig.ServerOptions()
+	if err != nil {
+		logger.Panicw("Failed to configure TLS", "Err", err)
+	}
+	unary := []grpc.UnaryServerInterceptor{interceptors.UnaryServerMetricsInterceptor, interceptors.UnaryServerErrorInterceptor}
+	stream := []grpc.StreamServerInterceptor{interceptors.StreamServerMetricsInterceptor, interceptors.StreamServerErrorInterceptor}
+	guard, err := auth.NewGuardFromEnv(logger, metadata.NewAuthResolver(meta))
+	if err != nil {
+		logger.Panicw("Failed to configure authentication", "Err", err)
+	}
+	if guard != nil {
+		logger.Infow("Authentication enabled")
+		unary = append(unary, guard.UnaryServerInterceptor)
+		stream = append(stream, guard.StreamServerInterceptor)
+	}
+	opts = append(opts, interceptors.TracingServerOptions()...)
+	opts = append(opts, grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+	grpcServer := grpc.NewServer(opts...)
 
 	healthServer := grpc_health.NewServer()
 	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
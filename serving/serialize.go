@@ -66,8 +66,22 @@ func emptySourceRow() *sourceRow {
 	}
 }
 
-func serializedRow(features []interface{}, label interface{}) (*pb.TrainingDataRow, error) {
-	r, err := newRow(features, label)
+// trainingRowShape is how a training set's iterator values map to a row's columns.
+type trainingRowShape struct {
+	// numAdditionalLabels is the number of trailing feature values that are additional labels.
+	numAdditionalLabels int
+	// hasLabel is false for training sets built from a spine, which only have feature columns.
+	hasLabel bool
+}
+
+// serializedRow serializes a training set row. Training set iterators return additional labels
+// as the trailing feature values, so they're split out here.
+func serializedRow(features []interface{}, label interface{}, shape trainingRowShape) (*pb.TrainingDataRow, error) {
+	if shape.numAdditionalLabels > len(features) {
+		return nil, fferr.NewInternalErrorf("training set row has %d values but %d additional labels", len(features), shape.numAdditionalLabels)
+	}
+	split := len(features) - shape.numAdditionalLabels
+	r, err := newRow(features[:split], label, shape.hasLabel, features[split:])
 	if err != nil {
 		return nil, err
 	}
@@ -90,15 +104,22 @@ func SerializedSourceRow(row []interface{}) (*pb.SourceDataRow, error) {
 	return r.Serialized(), nil
 }
 
-func newRow(features []interface{}, label interface{}) (*row, error) {
+func newRow(features []interface{}, label interface{}, hasLabel bool, additionalLabels []interface{}) (*row, error) {
 	r := emptyRow()
 	for _, f := range features {
 		if err := r.AddFeature(f); err != nil {
 			return nil, err
 		}
 	}
-	if err := r.SetLabel(label); err != nil {
-		return nil, err
+	if hasLabel {
+		if err := r.SetLabel(label); err != nil {
+			return nil, err
+		}
+	}
+	for _, l := range additionalLabels {
+		if err := r.AddAdditionalLabel(l); err != nil {
+			return nil, err
+		}
 	}
 	return r, nil
 }
@@ -138,6 +159,15 @@ func (row *row) AddFeature(feature interface{}) error {
 	return nil
 }
 
+func (row *row) AddAdditionalLabel(label interface{}) error {
+	value, err := wrapValue(label)
+	if err != nil {
+		return err
+	}
+	row.serialized.AdditionalLabels = append(row.serialized.AdditionalLabels, value)
+	return nil
+}
+
 func newSourceRow(rows []interface{}) (*sourceRow, error) {
 	r := emptySourceRow()
 	for _, row := range rows {
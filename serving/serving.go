@@ -15,9 +15,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
 	"sync"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
 	"github.com/featureform/logging"
 	"github.com/featureform/metadata"
 	"github.com/featureform/metrics"
@@ -26,6 +31,7 @@ import (
 	"github.com/featureform/provider/dataset"
 	pt "github.com/featureform/provider/provider_type"
 	"github.com/featureform/scheduling"
+	"github.com/featureform/tracing"
 )
 
 const (
@@ -40,6 +46,8 @@ type FeatureServer struct {
 	Providers *sync.Map
 	Tables    *sync.Map
 	Features  *sync.Map
+	// Sampler records served values for skew monitoring. Nothing is sampled if it's nil.
+	Sampler *Sampler
 }
 
 func NewFeatureServer(meta *metadata.Client, promMetrics metrics.MetricsHandler, logger logging.Logger) (*FeatureServer, error) {
@@ -68,7 +76,13 @@ func (serv *FeatureServer) TrainingData(req *pb.TrainingDataRequest, stream pb.F
 			return err
 		}
 	}
-	iter, err := serv.getTrainingSetIterator(name, variant)
+	shape, err := serv.getTrainingRowShape(name, variant)
+	if err != nil {
+		logger.Errorw("Failed to get training set labels", "Error", err)
+		featureObserver.SetError()
+		return err
+	}
+	iter, err := serv.getTrainingSetIterator(name, variant, req.GetRunId())
 	if err != nil {
 		logger.Errorw("Failed to get training set iterator", "Error", err)
 		featureObserver.SetError()
@@ -80,7 +94,7 @@ func (serv *FeatureServer) TrainingData(req *pb.TrainingDataRequest, stream pb.F
 		featRows := iter.Features()
 
 		// TODO we should directly serialize using types
-		sRow, err := serializedRow(featRows.GetRawValues(), iter.Label().Value)
+		sRow, err := serializedRow(featRows.GetRawValues(), iter.Label().Value, shape)
 		if err != nil {
 			logger.Errorw("Failed to serialize row", "Error", err)
 			featureObserver.SetError()
@@ -120,17 +134,30 @@ type splitContext struct {
 	req             *pb.TrainTestSplitRequest
 	trainIterator   *dataset.TrainingSetIterator
 	testIterator    *dataset.TrainingSetIterator
+	shape           *trainingRowShape
 	isTestFinished  *bool
 	isTrainFinished *bool
-	logger          logging.Logger
+	// cleanup drops the split once the stream is done with its iterators.
+	cleanup *func() error
+	logger  logging.Logger
 }
 
 func (serv *FeatureServer) TrainTestSplit(stream pb.Feature_TrainTestSplitServer) error {
 	var (
 		trainIter, testIter dataset.TrainingSetIterator
+		shape               trainingRowShape
 		isTrainFinished     bool
 		isTestFinished      bool
+		cleanup             func() error
 	)
+	defer func() {
+		if cleanup == nil {
+			return
+		}
+		if err := cleanup(); err != nil {
+			serv.Logger.Errorw("Failed to clean up train test split", "error", err)
+		}
+	}()
 
 	for {
 		if isTrainFinished && isTestFinished {
@@ -162,8 +189,10 @@ func (serv *FeatureServer) TrainTestSplit(stream pb.Feature_TrainTestSplitServer
 			req:             req,
 			trainIterator:   &trainIter,
 			testIterator:    &testIter,
+			shape:           &shape,
 			isTestFinished:  &isTestFinished,
 			isTrainFinished: &isTrainFinished,
+			cleanup:         &cleanup,
 			logger:          logger,
 		}
 
@@ -185,27 +214,47 @@ func (serv *FeatureServer) TrainTestSplit(stream pb.Feature_TrainTestSplitServer
 func (serv *FeatureServer) handleSplitInitializeRequest(splitContext *splitContext) error {
 	splitContext.logger.Infow("Initializing dataset", "id", splitContext.req.Id, "shuffle", splitContext.req.Shuffle, "testSize", splitContext.req.TestSize)
 
+	splitType, err := trainTestSplitType(splitContext.req.SplitType)
+	if err != nil {
+		return err
+	}
 	trainTestSplitDef := provider.TrainTestSplitDef{
 		TrainingSetName:    splitContext.req.Id.GetName(),
 		TrainingSetVariant: splitContext.req.Id.GetVersion(),
 		TestSize:           splitContext.req.TestSize,
 		Shuffle:            splitContext.req.Shuffle,
 		RandomState:        int(splitContext.req.RandomState),
+		Type:               splitType,
+		TimestampColumn:    splitContext.req.TimestampColumn,
+		EntityColumn:       splitContext.req.EntityColumn,
+		Folds:              int(splitContext.req.Folds),
+		Fold:               int(splitContext.req.Fold),
+		RequestID:          uuid.NewString(),
+	}
+	if splitContext.req.SplitTime != nil {
+		trainTestSplitDef.SplitTime = splitContext.req.SplitTime.AsTime()
 	}
 
 	cleanupFunc, err := serv.createTrainTestSplit(trainTestSplitDef)
-	defer cleanupFunc()
 	if err != nil {
-		return fferr.NewInternalError(err)
+		return err
 	}
+	// The iterators read from the split until the stream ends, so it's dropped then.
+	*splitContext.cleanup = cleanupFunc
 	train, test, err := serv.getTrainTestSplitIterators(trainTestSplitDef)
 	if err != nil {
 		splitContext.logger.Errorw("Failed to get training set iterator", "Error", err)
 		return err
 	}
+	shape, err := serv.getTrainingRowShape(trainTestSplitDef.TrainingSetName, trainTestSplitDef.TrainingSetVariant)
+	if err != nil {
+		splitContext.logger.Errorw("Failed to get training set labels", "Error", err)
+		return err
+	}
 
 	*splitContext.trainIterator = train
 	*splitContext.testIterator = test
+	*splitContext.shape = shape
 
 	initResponse := &pb.BatchTrainTestSplitResponse{
 		RequestType: pb.RequestType_INITIALIZE,
@@ -236,7 +285,7 @@ func (serv *FeatureServer) handleSplitDataRequest(splitContext *splitContext) er
 
 	for rows < int(splitContext.req.BatchSize) {
 		if thisIter.Next() {
-			sRow, err := serializedRow(thisIter.Features().GetRawValues(), thisIter.Label().Value)
+			sRow, err := serializedRow(thisIter.Features().GetRawValues(), thisIter.Label().Value, *splitContext.shape)
 			if err != nil {
 				return err
 			}
@@ -303,11 +352,20 @@ func (serv *FeatureServer) TrainingDataColumns(ctx context.Context, req *pb.Trai
 	for i, f := range fv {
 		features[i] = fmt.Sprintf("feature__%s__%s", f.Name, f.Variant)
 	}
-	lv := ts.Label()
-	label := fmt.Sprintf("label__%s__%s", lv.Name, lv.Variant)
+	// Training sets built from a spine only have feature columns.
+	label := ""
+	if !ts.HasSpine() {
+		lv := ts.Label()
+		label = fmt.Sprintf("label__%s__%s", lv.Name, lv.Variant)
+	}
+	var additionalLabels []string
+	for _, l := range ts.AdditionalLabels() {
+		additionalLabels = append(additionalLabels, fmt.Sprintf("label__%s__%s", l.Name, l.Variant))
+	}
 	return &pb.TrainingColumns{
-		Features: features,
-		Label:    label,
+		Features:         features,
+		Label:            label,
+		AdditionalLabels: additionalLabels,
 	}, nil
 }
 
@@ -383,7 +441,20 @@ func (serv *FeatureServer) SourceData(req *pb.SourceDataRequest, stream pb.Featu
 	return nil
 }
 
-func (serv *FeatureServer) getTrainingSetIterator(name, variant string) (dataset.TrainingSetIterator, error) {
+// getTrainingRowShape returns how many additional labels the training set has, whose values are
+// the trailing feature values of the training set iterator's rows, and whether it has a label.
+func (serv *FeatureServer) getTrainingRowShape(name, variant string) (trainingRowShape, error) {
+	ctx := context.TODO()
+	ts, err := serv.Metadata.GetTrainingSetVariant(ctx, metadata.NameVariant{Name: name, Variant: variant})
+	if err != nil {
+		return trainingRowShape{}, err
+	}
+	return trainingRowShape{numAdditionalLabels: len(ts.AdditionalLabels()), hasLabel: !ts.HasSpine()}, nil
+}
+
+// getTrainingSetIterator serves a training set from the snapshot pinned by runID, or by its latest
+// pinned run when runID is 0. Training sets without pinned snapshots are served as they are now.
+func (serv *FeatureServer) getTrainingSetIterator(name, variant string, runID int64) (dataset.TrainingSetIterator, error) {
 	ctx := context.TODO()
 	serv.Logger.Infow("Getting Training Set Iterator", "name", name, "variant", variant)
 	ts, err := serv.Metadata.GetTrainingSetVariant(ctx, metadata.NameVariant{Name: name, Variant: variant})
@@ -404,6 +475,19 @@ func (serv *FeatureServer) getTrainingSetIterator(name, variant string) (dataset
 		serv.Logger.Errorw("Training set provider is not an offline store", "Error", err)
 		return nil, err
 	}
+	if snapshotStore, ok := store.(provider.SnapshotOfflineStore); ok && (runID != 0 || ts.SnapshotPolicy().GetEnabled()) {
+		snapshot, found, err := serv.pinnedTrainingSet(ts, runID)
+		if err != nil {
+			serv.Logger.Errorw("Could not get pinned training set snapshot", "name", name, "variant", variant, "run_id", runID, "Error", err)
+			return nil, err
+		}
+		if found {
+			serv.Logger.Debugw("Get Training Set Snapshot From Store", "name", name, "variant", variant, "timestamp", snapshot.Timestamp)
+			return snapshotStore.GetTrainingSetSnapshot(provider.ResourceID{Name: name, Variant: variant, Type: provider.TrainingSet}, snapshot)
+		}
+	} else if runID != 0 {
+		return nil, fferr.NewInvalidArgumentErrorf("training set %s (%s) isn't stored in an offline store that pins snapshots", name, variant)
+	}
 	serv.Logger.Debugw("Get Training Set From Store", "name", name, "variant", variant)
 	tsIter, err := store.GetTrainingSet(provider.ResourceID{Name: name, Variant: variant})
 	if err != nil {
@@ -413,6 +497,63 @@ func (serv *FeatureServer) getTrainingSetIterator(name, variant string) (dataset
 	return tsIter, err
 }
 
+// pinnedTrainingSet returns the snapshot of a training set pinned by runID, or by its latest run
+// that pinned one when runID is 0. found is false if no run pinned one.
+func (serv *FeatureServer) pinnedTrainingSet(ts *metadata.TrainingSetVariant, runID int64) (snapshot fftypes.DataSnapshot, found bool, err error) {
+	taskIDs, err := ts.TaskIDs()
+	if err != nil {
+		return fftypes.DataSnapshot{}, false, err
+	}
+	var latest time.Time
+	for _, tid := range taskIDs {
+		runs, err := serv.Metadata.Tasks.GetRuns(tid)
+		if err != nil {
+			return fftypes.DataSnapshot{}, false, err
+		}
+		for _, run := range runs {
+			output, ok := outputSnapshot(run.Snapshots)
+			if !ok {
+				continue
+			}
+			if runID != 0 {
+				if run.ID.String() == strconv.FormatInt(runID, 10) {
+					return output, true, nil
+				}
+				continue
+			}
+			if !found || run.StartTime.After(latest) {
+				snapshot, found, latest = output, true, run.StartTime
+			}
+		}
+	}
+	if runID != 0 {
+		return fftypes.DataSnapshot{}, false, fferr.NewInvalidArgumentErrorf("run %d of training set %s (%s) didn't pin a snapshot", runID, ts.Name(), ts.Variant())
+	}
+	return snapshot, found, nil
+}
+
+func outputSnapshot(snapshots []fftypes.DataSnapshot) (fftypes.DataSnapshot, bool) {
+	for _, snapshot := range snapshots {
+		if snapshot.Output {
+			return snapshot, true
+		}
+	}
+	return fftypes.DataSnapshot{}, false
+}
+
+func trainTestSplitType(t pb.SplitType) (provider.TrainTestSplitType, error) {
+	switch t {
+	case pb.SplitType_RANDOM_SPLIT:
+		return provider.RandomSplit, nil
+	case pb.SplitType_TIME_SPLIT:
+		return provider.TimeSplit, nil
+	case pb.SplitType_ENTITY_SPLIT:
+		return provider.EntitySplit, nil
+	default:
+		return 0, fferr.NewInvalidArgumentErrorf("unknown split type %s", t)
+	}
+}
+
 func (serv *FeatureServer) createTrainTestSplit(def provider.TrainTestSplitDef) (func() error, error) {
 	ctx := context.TODO()
 	serv.Logger.Infow("Creating Train Test Split", "TrainTestSplitDef", fmt.Sprintf("%+v", def))
@@ -634,6 +775,7 @@ func (serv *FeatureServer) FeatureServe(ctx context.Context, req *pb.FeatureServ
 	if err != nil {
 		return nil, err
 	}
+	serv.Sampler.SampleRows(req.GetModel().GetName(), features, rows)
 
 	return &pb.FeatureRow{
 		ValueLists: rows,
@@ -692,7 +834,15 @@ func (serv *FeatureServer) getFeatureValue(ctx context.Context, name, variant st
 			obs.SetError()
 			return nil, err
 		}
+		_, span := tracing.Start(ctx, "provider.OnlineStoreTable.Get",
+			tracing.ProviderTypeKey.String(providerEntry.Type()),
+			tracing.ResourceNameKey.String(name),
+			tracing.ResourceVariantKey.String(variant),
+		)
+		start := time.Now()
 		val, err = table.Get(entity)
+		metrics.Instrument().ObserveProviderOperation(providerEntry.Type(), "Get", time.Since(start), err)
+		tracing.End(span, err)
 		if err != nil {
 			logger.Errorw("entity not found", "Error", err)
 			obs.SetError()
@@ -734,6 +884,7 @@ func (serv *FeatureServer) BatchFeatureServe(req *pb.BatchFeatureServeRequest, s
 		if err != nil {
 			return err
 		}
+		serv.Sampler.SampleBatchRow(features, iter.Features())
 		rows.Rows = append(rows.Rows, sRow)
 		bufRows++
 		if bufRows == DataBatchSize {
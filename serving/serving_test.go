@@ -1580,3 +1580,32 @@ func TestTrainTestSplit_DataRequest(t *testing.T) {
 	assert.NotEmpty(t, mockTrainTestSplitServer.Responses)
 	assert.Equal(t, pb.RequestType_INITIALIZE, mockTrainTestSplitServer.Responses[0].RequestType)
 }
+
+func TestSerializedRowAdditionalLabels(t *testing.T) {
+	row, err := serializedRow([]interface{}{1.5, "US", true}, 1, trainingRowShape{numAdditionalLabels: 1, hasLabel: true})
+	if err != nil {
+		t.Fatalf("Failed to serialize row: %s", err)
+	}
+	if len(row.Features) != 2 {
+		t.Fatalf("Expected 2 features, got %d", len(row.Features))
+	}
+	if len(row.AdditionalLabels) != 1 || !row.AdditionalLabels[0].GetBoolValue() {
+		t.Fatalf("Expected additional label true, got %v", row.AdditionalLabels)
+	}
+	if _, err := serializedRow([]interface{}{1.5}, 1, trainingRowShape{numAdditionalLabels: 2, hasLabel: true}); err == nil {
+		t.Fatalf("Expected row with fewer values than additional labels to fail")
+	}
+}
+
+func TestSerializedRowSpine(t *testing.T) {
+	row, err := serializedRow([]interface{}{1.5, "US"}, nil, trainingRowShape{})
+	if err != nil {
+		t.Fatalf("Failed to serialize row: %s", err)
+	}
+	if len(row.Features) != 2 {
+		t.Fatalf("Expected 2 features, got %d", len(row.Features))
+	}
+	if row.Label != nil {
+		t.Fatalf("Expected a spine training set row to have no label, got %v", row.Label)
+	}
+}
@@ -0,0 +1,423 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package serving
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/featureform/fferr"
+	help "github.com/featureform/helpers"
+	"github.com/featureform/logging"
+	pb "github.com/featureform/proto"
+)
+
+const (
+	// sampleBufferSize is how many samples can be waiting to be written. Samples taken while the
+	// buffer is full are dropped so that sampling never slows down serving.
+	sampleBufferSize = 4096
+	// sampleFlushSize is how many samples are written to the sink at once.
+	sampleFlushSize     = 512
+	sampleFlushInterval = time.Second
+)
+
+// ServedSample is a feature value returned by the serving API. Numbers are float64s and
+// everything else is a string.
+type ServedSample struct {
+	Feature string    `json:"feature"`
+	Variant string    `json:"variant"`
+	Model   string    `json:"model,omitempty"`
+	Value   any       `json:"value"`
+	Time    time.Time `json:"time"`
+}
+
+// SampleSink stores served samples so they can later be compared with training data.
+type SampleSink interface {
+	Write(samples []ServedSample) error
+	// Read returns the samples served at or after since.
+	Read(since time.Time) ([]ServedSample, error)
+	// Prune deletes samples served before before. Sinks may keep samples that share storage with
+	// newer ones.
+	Prune(before time.Time) error
+}
+
+// FileSampleSink writes samples as JSON lines to one file per day in a local directory.
+type FileSampleSink struct {
+	Dir string
+	mu  sync.Mutex
+}
+
+func NewFileSampleSink(dir string) (*FileSampleSink, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fferr.NewInternalErrorf("failed to create sample directory %s: %v", dir, err)
+	}
+	return &FileSampleSink{Dir: dir}, nil
+}
+
+const sampleFilePrefix = "samples-"
+
+func (sink *FileSampleSink) fileName(t time.Time) string {
+	return filepath.Join(sink.Dir, sampleFilePrefix+t.UTC().Format("20060102")+".jsonl")
+}
+
+func (sink *FileSampleSink) Write(samples []ServedSample) error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	files := make(map[string]*os.File)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	for _, sample := range samples {
+		name := sink.fileName(sample.Time)
+		f, has := files[name]
+		if !has {
+			var err error
+			f, err = os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				return fferr.NewInternalErrorf("failed to open sample file %s: %v", name, err)
+			}
+			files[name] = f
+		}
+		line, err := json.Marshal(sample)
+		if err != nil {
+			return fferr.NewInternalError(err)
+		}
+		if _, err := f.Write(append(line, '\n')); err != nil {
+			return fferr.NewInternalErrorf("failed to write sample file %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func (sink *FileSampleSink) Read(since time.Time) ([]ServedSample, error) {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	names, err := filepath.Glob(filepath.Join(sink.Dir, sampleFilePrefix+"*.jsonl"))
+	if err != nil {
+		return nil, fferr.NewInternalError(err)
+	}
+	sort.Strings(names)
+	first := sink.fileName(since)
+	var samples []ServedSample
+	for _, name := range names {
+		// Files are named by day, so older days can be skipped without reading them.
+		if name < first {
+			continue
+		}
+		fileSamples, err := readSampleFile(name, since)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, fileSamples...)
+	}
+	return samples, nil
+}
+
+// Prune deletes the files of the days before before's day.
+func (sink *FileSampleSink) Prune(before time.Time) error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	return removeFilesBefore(filepath.Join(sink.Dir, sampleFilePrefix+"*.jsonl"), sink.fileName(before))
+}
+
+// removeFilesBefore deletes the files matching pattern that sort before first. Files are named
+// by time, so they sort in the order they were written.
+func removeFilesBefore(pattern, first string) error {
+	names, err := filepath.Glob(pattern)
+	if err != nil {
+		return fferr.NewInternalError(err)
+	}
+	for _, name := range names {
+		if name >= first {
+			continue
+		}
+		if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+			return fferr.NewInternalErrorf("failed to remove %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func readSampleFile(name string, since time.Time) ([]ServedSample, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fferr.NewInternalErrorf("failed to open sample file %s: %v", name, err)
+	}
+	defer f.Close()
+	var samples []ServedSample
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var sample ServedSample
+		// A partly written line is skipped rather than failing the whole read.
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			continue
+		}
+		if !sample.Time.Before(since) {
+			samples = append(samples, sample)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fferr.NewInternalErrorf("failed to read sample file %s: %v", name, err)
+	}
+	return samples, nil
+}
+
+// Sampler records a random fraction of served feature values in a SampleSink. Samples are
+// written in the background; if the sink falls behind, samples are dropped.
+type Sampler struct {
+	rate    float64
+	sink    SampleSink
+	logger  logging.Logger
+	samples chan ServedSample
+	done    chan struct{}
+	now     func() time.Time
+}
+
+// NewSampler starts a sampler that keeps each served value with probability rate.
+func NewSampler(sink SampleSink, rate float64, logger logging.Logger) *Sampler {
+	s := &Sampler{
+		rate:    rate,
+		sink:    sink,
+		logger:  logger,
+		samples: make(chan ServedSample, sampleBufferSize),
+		done:    make(chan struct{}),
+		now:     time.Now,
+	}
+	go s.run()
+	return s
+}
+
+// SampleRows samples the values FeatureServe returned. rows holds one list of values per feature.
+func (s *Sampler) SampleRows(model string, features []*pb.FeatureID, rows []*pb.ValueList) {
+	if s == nil {
+		return
+	}
+	for i, row := range rows {
+		if i >= len(features) {
+			break
+		}
+		for _, val := range row.GetValues() {
+			if rand.Float64() >= s.rate {
+				continue
+			}
+			if native, ok := sampledValue(val); ok {
+				s.add(ServedSample{Feature: features[i].GetName(), Variant: features[i].GetVersion(), Model: model, Value: native})
+			}
+		}
+	}
+}
+
+// SampleBatchRow samples one row BatchFeatureServe returned.
+func (s *Sampler) SampleBatchRow(features []*pb.FeatureID, values []interface{}) {
+	if s == nil || rand.Float64() >= s.rate {
+		return
+	}
+	for i, val := range values {
+		if i >= len(features) {
+			break
+		}
+		wrapped, err := wrapValue(val)
+		if err != nil {
+			continue
+		}
+		if native, ok := sampledValue(wrapped); ok {
+			s.add(ServedSample{Feature: features[i].GetName(), Variant: features[i].GetVersion(), Value: native})
+		}
+	}
+}
+
+func (s *Sampler) add(sample ServedSample) {
+	sample.Time = s.now().UTC()
+	select {
+	case s.samples <- sample:
+	default:
+	}
+}
+
+func (s *Sampler) run() {
+	ticker := time.NewTicker(sampleFlushInterval)
+	defer ticker.Stop()
+	defer close(s.done)
+	batch := make([]ServedSample, 0, sampleFlushSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.sink.Write(batch); err != nil {
+			s.logger.Warnw("Failed to write served samples; dropping them", "count", len(batch), "error", err)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case sample, ok := <-s.samples:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, sample)
+			if len(batch) == sampleFlushSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// Close writes any buffered samples and stops the sampler. Nothing may be sampled after Close.
+func (s *Sampler) Close() {
+	close(s.samples)
+	<-s.done
+}
+
+// sampledValue converts a served value into the form it's compared with training data in.
+// Numbers become float64s. Vectors, functions and nulls aren't sampled.
+func sampledValue(val *pb.Value) (any, bool) {
+	switch typed := val.GetValue().(type) {
+	case *pb.Value_StrValue:
+		return typed.StrValue, true
+	case *pb.Value_BoolValue:
+		return strconv.FormatBool(typed.BoolValue), true
+	case *pb.Value_IntValue:
+		return float64(typed.IntValue), true
+	case *pb.Value_Int32Value:
+		return float64(typed.Int32Value), true
+	case *pb.Value_Int64Value:
+		return float64(typed.Int64Value), true
+	case *pb.Value_Uint32Value:
+		return float64(typed.Uint32Value), true
+	case *pb.Value_Uint64Value:
+		return float64(typed.Uint64Value), true
+	case *pb.Value_FloatValue:
+		return float64(typed.FloatValue), true
+	case *pb.Value_DoubleValue:
+		return typed.DoubleValue, true
+	default:
+		return nil, false
+	}
+}
+
+// SkewThresholds are the limits past which a feature's served values are reported as skewed.
+// A zero field means the limit is inherited from the less specific thresholds.
+type SkewThresholds struct {
+	// PSI and KL limit the drift of numeric features.
+	PSI float64 `json:"psi,omitempty"`
+	KL  float64 `json:"kl,omitempty"`
+	// PValue is the chi-square p-value below which a categorical feature is skewed.
+	PValue float64 `json:"pValue,omitempty"`
+}
+
+// DefaultSkewThresholds are commonly used limits: a PSI over 0.2 is usually taken as a
+// significant shift.
+var DefaultSkewThresholds = SkewThresholds{PSI: 0.2, KL: 0.1, PValue: 0.01}
+
+func (t SkewThresholds) override(other SkewThresholds) SkewThresholds {
+	if other.PSI != 0 {
+		t.PSI = other.PSI
+	}
+	if other.KL != 0 {
+		t.KL = other.KL
+	}
+	if other.PValue != 0 {
+		t.PValue = other.PValue
+	}
+	return t
+}
+
+// SkewThresholdConfig holds default thresholds and overrides per model and per feature.
+// Features are keyed by "name:variant". A feature's overrides win over its model's.
+type SkewThresholdConfig struct {
+	Default  SkewThresholds            `json:"default"`
+	Models   map[string]SkewThresholds `json:"models,omitempty"`
+	Features map[string]SkewThresholds `json:"features,omitempty"`
+}
+
+// For returns the thresholds that apply to a feature served to a model.
+func (c SkewThresholdConfig) For(model, feature, variant string) SkewThresholds {
+	t := DefaultSkewThresholds.override(c.Default)
+	t = t.override(c.Models[model])
+	return t.override(c.Features[fmt.Sprintf("%s:%s", feature, variant)])
+}
+
+// SkewConfig configures serving skew monitoring. Sampling is off when SampleRate is 0.
+type SkewConfig struct {
+	SampleRate    float64
+	SampleDir     string
+	CheckInterval time.Duration
+	// Lookback is how far back samples are compared with training data.
+	Lookback time.Duration
+	// Retention is how long samples and reports are kept. It's never shorter than Lookback.
+	Retention time.Duration
+	// MinSamples is how many samples a feature needs before it's checked.
+	MinSamples int
+	Thresholds SkewThresholdConfig
+}
+
+// retention returns how long samples and reports are kept, defaulting to Lookback.
+func (c SkewConfig) retention() time.Duration {
+	if c.Retention < c.Lookback {
+		return c.Lookback
+	}
+	return c.Retention
+}
+
+// SkewConfigFromEnv reads the skew monitoring config from SKEW_SAMPLE_RATE, SKEW_SAMPLE_DIR,
+// SKEW_CHECK_INTERVAL, SKEW_LOOKBACK, SKEW_RETENTION, SKEW_MIN_SAMPLES and SKEW_THRESHOLDS (JSON).
+func SkewConfigFromEnv() (SkewConfig, error) {
+	config := SkewConfig{
+		SampleDir:     help.GetEnv("SKEW_SAMPLE_DIR", filepath.Join(os.TempDir(), "featureform", "skew")),
+		CheckInterval: time.Hour,
+		Lookback:      24 * time.Hour,
+		MinSamples:    help.GetEnvInt("SKEW_MIN_SAMPLES", 100),
+	}
+	if rate := help.GetEnv("SKEW_SAMPLE_RATE", ""); rate != "" {
+		parsed, err := strconv.ParseFloat(rate, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			return SkewConfig{}, fferr.NewInvalidArgumentErrorf("SKEW_SAMPLE_RATE must be between 0 and 1, got %q", rate)
+		}
+		config.SampleRate = parsed
+	}
+	durations := map[string]*time.Duration{
+		"SKEW_CHECK_INTERVAL": &config.CheckInterval,
+		"SKEW_LOOKBACK":       &config.Lookback,
+		"SKEW_RETENTION":      &config.Retention,
+	}
+	for key, duration := range durations {
+		parsed, err := help.LookupEnvDuration(key)
+		if _, notFound := err.(*help.EnvNotFound); notFound {
+			continue
+		}
+		if err != nil || parsed <= 0 {
+			return SkewConfig{}, fferr.NewInvalidArgumentErrorf("%s must be a positive duration, got %q", key, os.Getenv(key))
+		}
+		*duration = parsed
+	}
+	if config.Retention == 0 {
+		config.Retention = config.Lookback
+	} else if config.Retention < config.Lookback {
+		return SkewConfig{}, fferr.NewInvalidArgumentErrorf("SKEW_RETENTION (%s) must be at least SKEW_LOOKBACK (%s)", config.Retention, config.Lookback)
+	}
+	if thresholds := strings.TrimSpace(help.GetEnv("SKEW_THRESHOLDS", "")); thresholds != "" {
+		if err := json.Unmarshal([]byte(thresholds), &config.Thresholds); err != nil {
+			return SkewConfig{}, fferr.NewInvalidArgumentErrorf("failed to parse SKEW_THRESHOLDS: %v", err)
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,387 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package serving
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
+	"github.com/featureform/helpers/notifications"
+	"github.com/featureform/logging"
+	"github.com/featureform/metadata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// skewMetadata is the part of the metadata client the skew monitor uses.
+type skewMetadata interface {
+	ListModels(ctx context.Context) ([]*metadata.Model, error)
+	GetFeatureVariant(ctx context.Context, id metadata.NameVariant) (*metadata.FeatureVariant, error)
+	GetProfile(ctx context.Context, id metadata.ResourceID, runID string) (*metadata.ResourceProfile, error)
+}
+
+// FeatureSkew is how far a feature's served values have drifted from its training data.
+type FeatureSkew struct {
+	Feature string `json:"feature"`
+	Variant string `json:"variant"`
+	// TrainingSet is the training set whose profile the samples were compared with.
+	TrainingSet metadata.NameVariant `json:"trainingSet"`
+	Samples     int                  `json:"samples"`
+	Numeric     bool                 `json:"numeric"`
+	PSI         float64              `json:"psi,omitempty"`
+	KL          float64              `json:"kl,omitempty"`
+	ChiSquare   float64              `json:"chiSquare,omitempty"`
+	PValue      float64              `json:"pValue,omitempty"`
+	Thresholds  SkewThresholds       `json:"thresholds"`
+	Skewed      bool                 `json:"skewed"`
+	// Skipped says why a feature wasn't checked.
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// ModelSkewReport holds the skew of the features served to a model. Features that aren't linked
+// to a model are reported under an empty model name.
+type ModelSkewReport struct {
+	Model    string        `json:"model"`
+	Features []FeatureSkew `json:"features"`
+}
+
+// SkewReport is the result of one skew check.
+type SkewReport struct {
+	Time   time.Time         `json:"time"`
+	Since  time.Time         `json:"since"`
+	Models []ModelSkewReport `json:"models"`
+}
+
+// Statuses of the notifications sent when a feature becomes skewed.
+const (
+	SkewedStatus    = "SKEWED"
+	NotSkewedStatus = "NOT_SKEWED"
+)
+
+// SkewMonitor periodically compares served samples with training set profiles.
+type SkewMonitor struct {
+	Metadata skewMetadata
+	Sink     SampleSink
+	Config   SkewConfig
+	Logger   logging.Logger
+	// Notifier, if set, is told when a feature served to a model becomes skewed.
+	Notifier notifications.RunNotifier
+	now      func() time.Time
+	mu       sync.Mutex
+	// skewed holds the model and feature pairs that were skewed at their last check, so a
+	// notification is only sent when a feature becomes skewed rather than on every check.
+	skewed map[string]bool
+}
+
+func NewSkewMonitor(meta skewMetadata, sink SampleSink, config SkewConfig, logger logging.Logger) *SkewMonitor {
+	return &SkewMonitor{Metadata: meta, Sink: sink, Config: config, Logger: logger, now: time.Now, skewed: make(map[string]bool)}
+}
+
+// Run checks for skew every interval until ctx is done.
+func (m *SkewMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.Config.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := m.Check(ctx)
+			if err != nil {
+				m.Logger.Errorw("Failed to check for training/serving skew", "error", err)
+				continue
+			}
+			if err := m.writeReport(report); err != nil {
+				m.Logger.Errorw("Failed to write skew report", "error", err)
+			}
+			if err := m.prune(report.Time); err != nil {
+				m.Logger.Errorw("Failed to prune old skew samples and reports", "error", err)
+			}
+		}
+	}
+}
+
+// Check compares the samples served within the lookback window with the profiles of the training
+// sets the features were trained in. Skewed features are logged, and the Notifier is told about
+// the ones that weren't skewed at their last check.
+func (m *SkewMonitor) Check(ctx context.Context) (*SkewReport, error) {
+	now := m.now().UTC()
+	since := now.Add(-m.Config.Lookback)
+	samples, err := m.Sink.Read(since)
+	if err != nil {
+		return nil, err
+	}
+	byFeature := make(map[metadata.NameVariant][]ServedSample)
+	// modelFeatures links models to features, both through the model's metadata and through
+	// the samples served to it.
+	modelFeatures := make(map[string]map[metadata.NameVariant]bool)
+	link := func(model string, feature metadata.NameVariant) {
+		if modelFeatures[model] == nil {
+			modelFeatures[model] = make(map[metadata.NameVariant]bool)
+		}
+		modelFeatures[model][feature] = true
+	}
+	for _, sample := range samples {
+		feature := metadata.NameVariant{Name: sample.Feature, Variant: sample.Variant}
+		byFeature[feature] = append(byFeature[feature], sample)
+		if sample.Model != "" {
+			link(sample.Model, feature)
+		}
+	}
+	models, err := m.Metadata.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	modelTrainingSets := make(map[string]metadata.NameVariants, len(models))
+	for _, model := range models {
+		modelTrainingSets[model.Name()] = model.TrainingSets()
+		for _, feature := range model.Features() {
+			if _, served := byFeature[feature]; served {
+				link(model.Name(), feature)
+			}
+		}
+	}
+	linked := make(map[metadata.NameVariant]bool)
+	for _, features := range modelFeatures {
+		for feature := range features {
+			linked[feature] = true
+		}
+	}
+	for feature := range byFeature {
+		if !linked[feature] {
+			link("", feature)
+		}
+	}
+
+	profiles := make(map[metadata.NameVariant]*fftypes.DatasetProfile)
+	report := &SkewReport{Time: now, Since: since}
+	for _, model := range sortedKeys(modelFeatures) {
+		modelReport := ModelSkewReport{Model: model}
+		for _, feature := range sortedNameVariants(modelFeatures[model]) {
+			skew, err := m.featureSkew(ctx, model, modelTrainingSets[model], feature, byFeature[feature], profiles)
+			if err != nil {
+				return nil, err
+			}
+			if skew.Skewed {
+				m.Logger.Warnw("Training/serving skew detected",
+					"model", model, "feature", feature.Name, "variant", feature.Variant,
+					"training_set", skew.TrainingSet, "psi", skew.PSI, "kl", skew.KL, "p_value", skew.PValue)
+			}
+			if skew.Skipped == "" && m.transition(model, skew) {
+				m.notify(ctx, model, skew, now)
+			}
+			modelReport.Features = append(modelReport.Features, skew)
+		}
+		report.Models = append(report.Models, modelReport)
+	}
+	return report, nil
+}
+
+// transition records whether a checked feature is skewed and returns true if it just became so.
+func (m *SkewMonitor) transition(model string, skew FeatureSkew) bool {
+	key := fmt.Sprintf("%s/%s/%s", model, skew.Feature, skew.Variant)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.skewed == nil {
+		m.skewed = make(map[string]bool)
+	}
+	was := m.skewed[key]
+	m.skewed[key] = skew.Skewed
+	return skew.Skewed && !was
+}
+
+func (m *SkewMonitor) notify(ctx context.Context, model string, skew FeatureSkew, now time.Time) {
+	if m.Notifier == nil {
+		return
+	}
+	event := notifications.RunEvent{
+		TaskID:         fmt.Sprintf("skew/%s/%s/%s", model, skew.Feature, skew.Variant),
+		RunID:          now.Format("20060102T150405Z"),
+		RunName:        model,
+		ResourceType:   metadata.FEATURE_VARIANT.String(),
+		Name:           skew.Feature,
+		Variant:        skew.Variant,
+		PreviousStatus: NotSkewedStatus,
+		Status:         SkewedStatus,
+		Error:          skew.describe(model),
+		Time:           now,
+	}
+	if err := m.Notifier.NotifyRun(ctx, event); err != nil {
+		m.Logger.Errorw("Failed to send skew notification", "model", model, "feature", skew.Feature, "variant", skew.Variant, "error", err)
+	}
+}
+
+// describe explains which of a skewed feature's thresholds were crossed.
+func (skew FeatureSkew) describe(model string) string {
+	servedTo := "served values"
+	if model != "" {
+		servedTo = fmt.Sprintf("values served to model %s", model)
+	}
+	var measure string
+	if skew.Numeric {
+		measure = fmt.Sprintf("PSI %.4f (threshold %.4f), KL %.4f (threshold %.4f)", skew.PSI, skew.Thresholds.PSI, skew.KL, skew.Thresholds.KL)
+	} else {
+		measure = fmt.Sprintf("chi-square p-value %.4g (threshold %.4g)", skew.PValue, skew.Thresholds.PValue)
+	}
+	return fmt.Sprintf("%d %s drifted from training set %s (%s): %s", skew.Samples, servedTo, skew.TrainingSet.Name, skew.TrainingSet.Variant, measure)
+}
+
+func (m *SkewMonitor) featureSkew(ctx context.Context, model string, modelTrainingSets metadata.NameVariants, feature metadata.NameVariant, samples []ServedSample, profiles map[metadata.NameVariant]*fftypes.DatasetProfile) (FeatureSkew, error) {
+	skew := FeatureSkew{
+		Feature:    feature.Name,
+		Variant:    feature.Variant,
+		Samples:    len(samples),
+		Thresholds: m.Config.Thresholds.For(model, feature.Name, feature.Variant),
+	}
+	if len(samples) < m.Config.MinSamples {
+		skew.Skipped = fmt.Sprintf("%d samples is fewer than the minimum of %d", len(samples), m.Config.MinSamples)
+		return skew, nil
+	}
+	trainingSet, column, err := m.baseline(ctx, modelTrainingSets, feature, profiles)
+	if err != nil {
+		return FeatureSkew{}, err
+	}
+	if column == nil {
+		skew.Skipped = "no profiled training set contains the feature"
+		return skew, nil
+	}
+	skew.TrainingSet = trainingSet
+	switch {
+	case column.Numeric && len(column.Histogram) > 0:
+		skew.Numeric = true
+		var served []float64
+		for _, sample := range samples {
+			if f, ok := sample.Value.(float64); ok {
+				served = append(served, f)
+			}
+		}
+		if len(served) == 0 {
+			skew.Skipped = "no numeric values were served"
+			return skew, nil
+		}
+		skew.PSI, skew.KL = numericDivergence(*column, served)
+		skew.Skewed = skew.PSI > skew.Thresholds.PSI || skew.KL > skew.Thresholds.KL
+	case len(column.TopValues) > 0 && column.Count > 0:
+		served := make([]string, 0, len(samples))
+		for _, sample := range samples {
+			served = append(served, fmt.Sprint(sample.Value))
+		}
+		skew.ChiSquare, skew.PValue = categoricalSkew(*column, served)
+		skew.Skewed = skew.PValue < skew.Thresholds.PValue
+	default:
+		skew.Skipped = "the training set profile has no distribution for the feature"
+	}
+	return skew, nil
+}
+
+// baseline finds the training data profile a feature's samples are compared with. Training sets
+// shared by the model and the feature are preferred, then any training set with the feature.
+func (m *SkewMonitor) baseline(ctx context.Context, modelTrainingSets metadata.NameVariants, feature metadata.NameVariant, profiles map[metadata.NameVariant]*fftypes.DatasetProfile) (metadata.NameVariant, *fftypes.ColumnProfile, error) {
+	variant, err := m.Metadata.GetFeatureVariant(ctx, feature)
+	if err != nil {
+		return metadata.NameVariant{}, nil, err
+	}
+	featureTrainingSets := variant.TrainingSets()
+	var candidates metadata.NameVariants
+	for _, ts := range featureTrainingSets {
+		if modelTrainingSets.Contains(ts) {
+			candidates = append(candidates, ts)
+		}
+	}
+	candidates = append(candidates, featureTrainingSets...)
+	columnName := fmt.Sprintf("feature__%s__%s", feature.Name, feature.Variant)
+	for _, ts := range candidates {
+		profile, has := profiles[ts]
+		if !has {
+			resourceProfile, err := m.Metadata.GetProfile(ctx, metadata.ResourceID{Name: ts.Name, Variant: ts.Variant, Type: metadata.TRAINING_SET_VARIANT}, "")
+			if err != nil && !isNotFound(err) {
+				return metadata.NameVariant{}, nil, err
+			}
+			if resourceProfile != nil {
+				profile = resourceProfile.Profile
+			}
+			profiles[ts] = profile
+		}
+		if profile == nil {
+			continue
+		}
+		for i, column := range profile.Columns {
+			if column.Name == columnName {
+				return ts, &profile.Columns[i], nil
+			}
+		}
+	}
+	return metadata.NameVariant{}, nil, nil
+}
+
+func isNotFound(err error) bool {
+	var grpcErr fferr.Error
+	if errors.As(err, &grpcErr) {
+		return grpcErr.GetCode() == codes.NotFound
+	}
+	return status.Code(err) == codes.NotFound
+}
+
+const skewReportPrefix = "skew-report-"
+
+func (m *SkewMonitor) reportName(t time.Time) string {
+	return filepath.Join(m.Config.SampleDir, skewReportPrefix+t.UTC().Format("20060102T150405Z")+".json")
+}
+
+func (m *SkewMonitor) writeReport(report *SkewReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fferr.NewInternalError(err)
+	}
+	name := m.reportName(report.Time)
+	if err := os.WriteFile(name, data, 0644); err != nil {
+		return fferr.NewInternalErrorf("failed to write skew report %s: %v", name, err)
+	}
+	return nil
+}
+
+// prune deletes the samples and reports that are older than the retention period.
+func (m *SkewMonitor) prune(now time.Time) error {
+	before := now.Add(-m.Config.retention())
+	if err := m.Sink.Prune(before); err != nil {
+		return err
+	}
+	return removeFilesBefore(filepath.Join(m.Config.SampleDir, skewReportPrefix+"*.json"), m.reportName(before))
+}
+
+func sortedKeys(m map[string]map[metadata.NameVariant]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedNameVariants(set map[metadata.NameVariant]bool) []metadata.NameVariant {
+	nvs := make([]metadata.NameVariant, 0, len(set))
+	for nv := range set {
+		nvs = append(nvs, nv)
+	}
+	sort.Slice(nvs, func(i, j int) bool {
+		if nvs[i].Name != nvs[j].Name {
+			return nvs[i].Name < nvs[j].Name
+		}
+		return nvs[i].Variant < nvs[j].Variant
+	})
+	return nvs
+}
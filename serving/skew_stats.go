@@ -0,0 +1,139 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package serving
+
+import (
+	"math"
+
+	fftypes "github.com/featureform/fftypes"
+)
+
+// skewEpsilon stands in for empty buckets and categories so that divergences stay finite.
+const skewEpsilon = 1e-4
+
+// otherCategory collects the served values that aren't one of the training data's top values.
+const otherCategory = "__other__"
+
+// numericDivergence compares served numbers with a training column's histogram, returning the
+// population stability index and the KL divergence of the served distribution from the
+// training one. Values outside of the training range count towards the outer buckets.
+func numericDivergence(train fftypes.ColumnProfile, served []float64) (psi, kl float64) {
+	trainDist := make([]float64, len(train.Histogram))
+	var trainTotal int64
+	for i, bucket := range train.Histogram {
+		trainDist[i] = float64(bucket.Count)
+		trainTotal += bucket.Count
+	}
+	servedDist := make([]float64, len(train.Histogram))
+	for _, f := range served {
+		servedDist[train.HistogramBucket(f)]++
+	}
+	normalize(trainDist, float64(trainTotal))
+	normalize(servedDist, float64(len(served)))
+	for i := range trainDist {
+		t, s := trainDist[i], servedDist[i]
+		psi += (s - t) * math.Log(s/t)
+		kl += s * math.Log(s/t)
+	}
+	return psi, kl
+}
+
+// normalize turns counts into proportions, replacing zeros with skewEpsilon.
+func normalize(counts []float64, total float64) {
+	for i, count := range counts {
+		p := 0.0
+		if total > 0 {
+			p = count / total
+		}
+		counts[i] = math.Max(p, skewEpsilon)
+	}
+}
+
+// categoricalSkew runs a chi-square goodness of fit test of served values against a training
+// column's top values, with every other value in one extra category. It returns the statistic and
+// its p-value.
+func categoricalSkew(train fftypes.ColumnProfile, served []string) (stat, pValue float64) {
+	expected := make(map[string]float64, len(train.TopValues)+1)
+	remaining := 1.0
+	for _, v := range train.TopValues {
+		p := float64(v.Count) / float64(train.Count)
+		expected[v.Value] = p
+		remaining -= p
+	}
+	expected[otherCategory] = math.Max(remaining, 0)
+	observed := make(map[string]float64, len(expected))
+	for _, v := range served {
+		if _, has := expected[v]; !has {
+			v = otherCategory
+		}
+		observed[v]++
+	}
+	n := float64(len(served))
+	for category, p := range expected {
+		e := math.Max(p, skewEpsilon) * n
+		diff := observed[category] - e
+		stat += diff * diff / e
+	}
+	return stat, chiSquarePValue(stat, len(expected)-1)
+}
+
+// chiSquarePValue is the probability of a chi-square statistic of at least stat with df degrees
+// of freedom.
+func chiSquarePValue(stat float64, df int) float64 {
+	if df <= 0 || stat <= 0 {
+		return 1
+	}
+	return upperIncompleteGamma(float64(df)/2, stat/2)
+}
+
+// upperIncompleteGamma is the regularized upper incomplete gamma function Q(a, x), using a series
+// for small x and a continued fraction otherwise.
+func upperIncompleteGamma(a, x float64) float64 {
+	const (
+		maxIterations = 500
+		tolerance     = 1e-14
+		tiny          = 1e-300
+	)
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgamma)
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < maxIterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*tolerance {
+				break
+			}
+		}
+		return math.Max(0, 1-sum*prefix)
+	}
+	// Lentz's method for the continued fraction.
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < maxIterations; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < tolerance {
+			break
+		}
+	}
+	return prefix * h
+}
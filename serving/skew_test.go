@@ -0,0 +1,339 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package serving
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/featureform/fferr"
+	fftypes "github.com/featureform/fftypes"
+	"github.com/featureform/helpers/notifications"
+	"github.com/featureform/logging"
+	"github.com/featureform/metadata"
+	mpb "github.com/featureform/metadata/proto"
+	pb "github.com/featureform/proto"
+)
+
+func TestChiSquarePValue(t *testing.T) {
+	tests := []struct {
+		stat     float64
+		df       int
+		expected float64
+	}{
+		{3.841459, 1, 0.05},
+		{11.070498, 5, 0.05},
+		{2, 10, 0.996340},
+		{0, 3, 1},
+	}
+	for _, test := range tests {
+		if p := chiSquarePValue(test.stat, test.df); math.Abs(p-test.expected) > 1e-5 {
+			t.Errorf("Expected p-value %f for %f with %d degrees of freedom, got %f", test.expected, test.stat, test.df, p)
+		}
+	}
+}
+
+func skewTrainingColumn() fftypes.ColumnProfile {
+	column := fftypes.ColumnProfile{Numeric: true, Count: 1000, Min: 0, Max: 10}
+	column.Histogram = fftypes.EqualWidthBuckets(0, 10, 10)
+	for i := range column.Histogram {
+		column.Histogram[i].Count = 100
+	}
+	return column
+}
+
+func TestNumericDivergence(t *testing.T) {
+	column := skewTrainingColumn()
+	uniform := make([]float64, 1000)
+	shifted := make([]float64, 1000)
+	for i := range uniform {
+		uniform[i] = float64(i%100) / 10
+		shifted[i] = 8 + float64(i%100)/10
+	}
+	psi, kl := numericDivergence(column, uniform)
+	assert.InDelta(t, 0, psi, 1e-9)
+	assert.InDelta(t, 0, kl, 1e-9)
+	psi, kl = numericDivergence(column, shifted)
+	assert.Greater(t, psi, DefaultSkewThresholds.PSI)
+	assert.Greater(t, kl, DefaultSkewThresholds.KL)
+}
+
+func TestCategoricalSkew(t *testing.T) {
+	column := fftypes.ColumnProfile{Count: 100, TopValues: []fftypes.ValueCount{{Value: "a", Count: 50}, {Value: "b", Count: 30}}}
+	same := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		switch {
+		case i < 50:
+			same = append(same, "a")
+		case i < 80:
+			same = append(same, "b")
+		default:
+			same = append(same, "c")
+		}
+	}
+	stat, p := categoricalSkew(column, same)
+	assert.InDelta(t, 0, stat, 1e-9)
+	assert.InDelta(t, 1, p, 1e-9)
+
+	different := make([]string, 100)
+	for i := range different {
+		different[i] = "b"
+	}
+	_, p = categoricalSkew(column, different)
+	assert.Less(t, p, DefaultSkewThresholds.PValue)
+}
+
+func TestFileSampleSink(t *testing.T) {
+	sink, err := NewFileSampleSink(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	day := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	samples := []ServedSample{
+		{Feature: "f", Variant: "v", Value: 1.5, Time: day.Add(-24 * time.Hour)},
+		{Feature: "f", Variant: "v", Model: "m", Value: "x", Time: day.Add(-time.Hour)},
+		{Feature: "f", Variant: "v", Value: 2.0, Time: day},
+	}
+	if err := sink.Write(samples); err != nil {
+		t.Fatalf("Failed to write samples: %v", err)
+	}
+	read, err := sink.Read(day.Add(-2 * time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to read samples: %v", err)
+	}
+	assert.Equal(t, samples[1:], read)
+
+	// The previous day's file holds samples from within the hour, so only older days go.
+	if err := sink.Prune(day.Add(-2 * time.Hour)); err != nil {
+		t.Fatalf("Failed to prune samples: %v", err)
+	}
+	read, err = sink.Read(time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to read samples: %v", err)
+	}
+	assert.Equal(t, samples[1:], read)
+}
+
+func TestSampler(t *testing.T) {
+	sink, err := NewFileSampleSink(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	features := []*pb.FeatureID{{Name: "amount", Version: "v"}, {Name: "city", Version: "v"}}
+	rows := []*pb.ValueList{
+		{Values: []*pb.Value{{Value: &pb.Value_Int64Value{Int64Value: 3}}, {Value: &pb.Value_DoubleValue{DoubleValue: 1.5}}}},
+		{Values: []*pb.Value{{Value: &pb.Value_StrValue{StrValue: "nyc"}}, {Value: &pb.Value_Vector32Value{}}}},
+	}
+	sampler := NewSampler(sink, 1, logging.NewTestLogger(t))
+	sampler.SampleRows("model", features, rows)
+	sampler.SampleBatchRow(features, []interface{}{int32(7), true})
+	sampler.Close()
+
+	NewSampler(sink, 0, logging.NewTestLogger(t)).SampleRows("model", features, rows)
+	var nilSampler *Sampler
+	nilSampler.SampleRows("model", features, rows)
+
+	read, err := sink.Read(time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to read samples: %v", err)
+	}
+	values := make([]any, len(read))
+	for i, sample := range read {
+		values[i] = sample.Value
+	}
+	assert.Equal(t, []any{3.0, 1.5, "nyc", 7.0, "true"}, values)
+	assert.Equal(t, "model", read[0].Model)
+	assert.Equal(t, "", read[3].Model)
+}
+
+func TestSkewConfigFromEnv(t *testing.T) {
+	t.Setenv("SKEW_SAMPLE_RATE", "0.25")
+	t.Setenv("SKEW_CHECK_INTERVAL", "10m")
+	t.Setenv("SKEW_THRESHOLDS", `{"default": {"psi": 0.3}, "models": {"churn": {"psi": 0.5, "kl": 0.4}}, "features": {"amount:v": {"psi": 0.1}}}`)
+	config, err := SkewConfigFromEnv()
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	assert.Equal(t, 0.25, config.SampleRate)
+	assert.Equal(t, 10*time.Minute, config.CheckInterval)
+	assert.Equal(t, 24*time.Hour, config.Lookback)
+	assert.Equal(t, 24*time.Hour, config.Retention, "retention should default to the lookback")
+	assert.Equal(t, SkewThresholds{PSI: 0.3, KL: 0.1, PValue: 0.01}, config.Thresholds.For("other", "city", "v"))
+	assert.Equal(t, SkewThresholds{PSI: 0.5, KL: 0.4, PValue: 0.01}, config.Thresholds.For("churn", "city", "v"))
+	assert.Equal(t, SkewThresholds{PSI: 0.1, KL: 0.4, PValue: 0.01}, config.Thresholds.For("churn", "amount", "v"))
+
+	t.Setenv("SKEW_RETENTION", "168h")
+	config, err = SkewConfigFromEnv()
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	assert.Equal(t, 168*time.Hour, config.Retention)
+
+	t.Setenv("SKEW_RETENTION", "1h")
+	_, err = SkewConfigFromEnv()
+	assert.Error(t, err, "samples within the lookback can't be deleted")
+
+	t.Setenv("SKEW_RETENTION", "")
+	t.Setenv("SKEW_SAMPLE_RATE", "2")
+	_, err = SkewConfigFromEnv()
+	assert.Error(t, err)
+}
+
+type recordingNotifier struct {
+	events []notifications.RunEvent
+}
+
+func (n *recordingNotifier) NotifyRun(ctx context.Context, event notifications.RunEvent) error {
+	n.events = append(n.events, event)
+	return nil
+}
+
+type skewMetadataMock struct {
+	models   []*metadata.Model
+	features map[metadata.NameVariant]*metadata.FeatureVariant
+	profiles map[metadata.NameVariant]*fftypes.DatasetProfile
+}
+
+func (m skewMetadataMock) ListModels(ctx context.Context) ([]*metadata.Model, error) {
+	return m.models, nil
+}
+
+func (m skewMetadataMock) GetFeatureVariant(ctx context.Context, id metadata.NameVariant) (*metadata.FeatureVariant, error) {
+	return m.features[id], nil
+}
+
+func (m skewMetadataMock) GetProfile(ctx context.Context, id metadata.ResourceID, runID string) (*metadata.ResourceProfile, error) {
+	profile, has := m.profiles[metadata.NameVariant{Name: id.Name, Variant: id.Variant}]
+	if !has {
+		return nil, fferr.NewDatasetNotFoundError(id.Name, id.Variant, nil)
+	}
+	return &metadata.ResourceProfile{ID: id, Profile: profile}, nil
+}
+
+func TestSkewMonitorCheck(t *testing.T) {
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	amount := metadata.NameVariant{Name: "amount", Variant: "v"}
+	city := metadata.NameVariant{Name: "city", Variant: "v"}
+	nvs := func(nvs ...metadata.NameVariant) []*mpb.NameVariant {
+		return metadata.NameVariants(nvs).Serialize()
+	}
+	oldTS := metadata.NameVariant{Name: "old", Variant: "v"}
+	churnTS := metadata.NameVariant{Name: "churn", Variant: "v"}
+	shifted := skewTrainingColumn()
+	shifted.Name = "feature__amount__v"
+	for i := range shifted.Histogram {
+		shifted.Histogram[i].Count = 0
+	}
+	shifted.Histogram[0].Count = 1000
+	matching := skewTrainingColumn()
+	matching.Name = "feature__amount__v"
+	meta := skewMetadataMock{
+		models: []*metadata.Model{
+			metadata.WrapProtoModel(&mpb.Model{Name: "churn", Features: nvs(amount), Trainingsets: nvs(churnTS)}),
+		},
+		features: map[metadata.NameVariant]*metadata.FeatureVariant{
+			amount: metadata.WrapProtoFeatureVariant(&mpb.FeatureVariant{Name: "amount", Variant: "v", Trainingsets: nvs(oldTS, churnTS)}),
+			city:   metadata.WrapProtoFeatureVariant(&mpb.FeatureVariant{Name: "city", Variant: "v", Trainingsets: nvs(oldTS)}),
+		},
+		profiles: map[metadata.NameVariant]*fftypes.DatasetProfile{
+			// The model's own training set should be picked over the older one.
+			oldTS:   {Columns: []fftypes.ColumnProfile{matching}},
+			churnTS: {Columns: []fftypes.ColumnProfile{shifted}},
+		},
+	}
+	sink, err := NewFileSampleSink(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	var samples []ServedSample
+	for i := 0; i < 100; i++ {
+		samples = append(samples, ServedSample{Feature: "amount", Variant: "v", Model: "churn", Value: float64(i) / 10, Time: now.Add(-time.Minute)})
+	}
+	samples = append(samples,
+		ServedSample{Feature: "city", Variant: "v", Value: "nyc", Time: now.Add(-time.Minute)},
+		ServedSample{Feature: "amount", Variant: "v", Value: 1.0, Time: now.Add(-48 * time.Hour)},
+	)
+	if err := sink.Write(samples); err != nil {
+		t.Fatalf("Failed to write samples: %v", err)
+	}
+	notifier := &recordingNotifier{}
+	monitor := NewSkewMonitor(meta, sink, SkewConfig{Lookback: 24 * time.Hour, MinSamples: 10}, logging.NewTestLogger(t))
+	monitor.Notifier = notifier
+	monitor.now = func() time.Time { return now }
+	report, err := monitor.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to check skew: %v", err)
+	}
+	if len(report.Models) != 2 {
+		t.Fatalf("Expected an unattributed report and a churn report, got %+v", report.Models)
+	}
+	unattributed, churn := report.Models[0], report.Models[1]
+	assert.Equal(t, "", unattributed.Model)
+	assert.Equal(t, "city", unattributed.Features[0].Feature)
+	assert.NotEmpty(t, unattributed.Features[0].Skipped, "a feature with too few samples shouldn't be checked")
+
+	assert.Equal(t, "churn", churn.Model)
+	skew := churn.Features[0]
+	assert.Equal(t, churnTS, skew.TrainingSet)
+	assert.Equal(t, 100, skew.Samples)
+	assert.True(t, skew.Numeric)
+	assert.True(t, skew.Skewed)
+	assert.Greater(t, skew.PSI, DefaultSkewThresholds.PSI)
+
+	if len(notifier.events) != 1 {
+		t.Fatalf("Expected one skew notification, got %+v", notifier.events)
+	}
+	event := notifier.events[0]
+	assert.Equal(t, SkewedStatus, event.Status)
+	assert.Equal(t, "FEATURE_VARIANT", event.ResourceType)
+	assert.Equal(t, "amount", event.Name)
+	assert.Equal(t, "churn", event.RunName)
+	assert.Contains(t, event.Error, "training set churn (v)")
+
+	if _, err := monitor.Check(context.Background()); err != nil {
+		t.Fatalf("Failed to check skew: %v", err)
+	}
+	assert.Len(t, notifier.events, 1, "a feature that stays skewed shouldn't be notified again")
+}
+
+func TestSkewMonitorPrune(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	sink, err := NewFileSampleSink(dir)
+	if err != nil {
+		t.Fatalf("Failed to create sink: %v", err)
+	}
+	monitor := NewSkewMonitor(skewMetadataMock{}, sink, SkewConfig{SampleDir: dir, Lookback: 24 * time.Hour, Retention: 72 * time.Hour}, logging.NewTestLogger(t))
+	for _, age := range []time.Duration{time.Hour, 48 * time.Hour, 96 * time.Hour} {
+		at := now.Add(-age)
+		if err := sink.Write([]ServedSample{{Feature: "f", Variant: "v", Value: 1.0, Time: at}}); err != nil {
+			t.Fatalf("Failed to write samples: %v", err)
+		}
+		if err := monitor.writeReport(&SkewReport{Time: at}); err != nil {
+			t.Fatalf("Failed to write report: %v", err)
+		}
+	}
+	if err := monitor.prune(now); err != nil {
+		t.Fatalf("Failed to prune: %v", err)
+	}
+	samples, err := filepath.Glob(filepath.Join(dir, sampleFilePrefix+"*"))
+	if err != nil {
+		t.Fatalf("Failed to list samples: %v", err)
+	}
+	reports, err := filepath.Glob(filepath.Join(dir, skewReportPrefix+"*"))
+	if err != nil {
+		t.Fatalf("Failed to list reports: %v", err)
+	}
+	assert.Equal(t, []string{sink.fileName(now.Add(-48 * time.Hour)), sink.fileName(now.Add(-time.Hour))}, samples)
+	assert.Equal(t, []string{monitor.reportName(now.Add(-48 * time.Hour)), monitor.reportName(now.Add(-time.Hour))}, reports)
+}
@@ -0,0 +1,211 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/featureform/auth"
+	"github.com/featureform/fferr"
+	"github.com/featureform/logging"
+	"github.com/featureform/storage/query"
+)
+
+// AuditKeyPrefix is the prefix of every audit entry in the state provider.
+// Keys end in a zero padded timestamp so they sort chronologically.
+const AuditKeyPrefix = "/audit/entry="
+
+type AuditOperation string
+
+const (
+	AuditCreate          AuditOperation = "create"
+	AuditUpdate          AuditOperation = "update"
+	AuditMarkForDeletion AuditOperation = "mark_for_deletion"
+	AuditPrune           AuditOperation = "prune"
+)
+
+// AuditResource identifies the resource an entry is about.
+type AuditResource struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Variant string `json:"variant,omitempty"`
+}
+
+// AuditChange is a single changed field, with sensitive values already redacted.
+type AuditChange struct {
+	Path     string `json:"path"`
+	OldValue string `json:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty"`
+}
+
+type AuditEntry struct {
+	ID        string         `json:"id"`
+	Timestamp time.Time      `json:"timestamp"`
+	Actor     string         `json:"actor"`
+	RequestID string         `json:"request_id"`
+	Operation AuditOperation `json:"operation"`
+	Key       string         `json:"key"`
+	Resource  AuditResource  `json:"resource"`
+	Changes   []AuditChange  `json:"changes,omitempty"`
+}
+
+// AuditDiffer turns a write to key into the resource it touches and the
+// redacted changes between the old and new values. Keys it returns false for
+// are not audited.
+type AuditDiffer func(key, oldValue, newValue string) (AuditResource, []AuditChange, bool)
+
+type AuditFilter struct {
+	Resource *AuditResource
+	Actor    string
+	Since    time.Time
+	// Limit keeps only the most recent entries when positive.
+	Limit int
+}
+
+func (filter AuditFilter) matches(entry AuditEntry) bool {
+	if filter.Actor != "" && entry.Actor != filter.Actor {
+		return false
+	}
+	if !filter.Since.IsZero() && entry.Timestamp.Before(filter.Since) {
+		return false
+	}
+	if res := filter.Resource; res != nil {
+		if entry.Resource.Type != res.Type || entry.Resource.Name != res.Name {
+			return false
+		}
+		if res.Variant != "" && entry.Resource.Variant != res.Variant {
+			return false
+		}
+	}
+	return true
+}
+
+// queries pushes the filter down to the storage query. Storage implementations
+// that ignore query options return every entry, so List still checks matches.
+func (filter AuditFilter) queries() []query.Query {
+	var opts []query.Query
+	if filter.Actor != "" {
+		opts = append(opts, query.ValueEquals{Column: auditColumn("actor"), Value: filter.Actor})
+	}
+	if !filter.Since.IsZero() {
+		opts = append(opts, query.KeyRange{Start: fmt.Sprintf("%s%020d", AuditKeyPrefix, filter.Since.UnixNano())})
+	}
+	if res := filter.Resource; res != nil {
+		opts = append(opts,
+			query.ValueEquals{Column: auditColumn("resource", "type"), Value: res.Type},
+			query.ValueEquals{Column: auditColumn("resource", "name"), Value: res.Name},
+		)
+		if res.Variant != "" {
+			opts = append(opts, query.ValueEquals{Column: auditColumn("resource", "variant"), Value: res.Variant})
+		}
+	}
+	if filter.Limit > 0 {
+		opts = append(opts, query.KeySort{Dir: query.Desc}, query.Limit{Limit: filter.Limit})
+	}
+	return opts
+}
+
+func auditColumn(path ...string) query.JSONColumn {
+	steps := make([]query.JSONPathStep, len(path))
+	for i, key := range path {
+		steps[i] = query.JSONPathStep{Key: key}
+	}
+	return query.JSONColumn{Path: steps, Type: query.String}
+}
+
+// AuditLog is an append-only log of metadata mutations kept in the state provider.
+type AuditLog struct {
+	storage metadataStorageImplementation
+	differ  AuditDiffer
+}
+
+func NewAuditLog(storage metadataStorageImplementation, differ AuditDiffer) *AuditLog {
+	return &AuditLog{storage: storage, differ: differ}
+}
+
+// Tracks reports whether writes to key are audited.
+func (log *AuditLog) Tracks(key string) bool {
+	if strings.HasPrefix(key, AuditKeyPrefix) {
+		return false
+	}
+	_, _, ok := log.differ(key, "", "")
+	return ok
+}
+
+// Record appends an entry for a write to key made by the caller in ctx.
+func (log *AuditLog) Record(ctx context.Context, op AuditOperation, key, oldValue, newValue string) error {
+	resource, changes, ok := log.differ(key, oldValue, newValue)
+	// Rewrites that don't change anything, like re-encrypting a value, aren't worth recording.
+	if !ok || (op == AuditUpdate && len(changes) == 0) {
+		return nil
+	}
+	return log.Append(ctx, AuditEntry{
+		Operation: op,
+		Key:       key,
+		Resource:  resource,
+		Changes:   changes,
+	})
+}
+
+// Append stores entry, filling in its ID, timestamp, actor and request ID.
+func (log *AuditLog) Append(ctx context.Context, entry AuditEntry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+	if entry.Actor == "" {
+		if principal, ok := auth.PrincipalFromContext(ctx); ok {
+			entry.Actor = principal.Subject
+		}
+	}
+	if entry.RequestID == "" {
+		entry.RequestID = logging.GetRequestIDFromContext(ctx).String()
+	}
+	serialized, err := json.Marshal(entry)
+	if err != nil {
+		return fferr.NewInternalError(err)
+	}
+	key := fmt.Sprintf("%s%020d/%s", AuditKeyPrefix, entry.Timestamp.UnixNano(), entry.ID)
+	return log.storage.Set(ctx, key, string(serialized))
+}
+
+// List returns the entries matching filter, oldest first.
+func (log *AuditLog) List(filter AuditFilter) ([]AuditEntry, error) {
+	rows, err := log.storage.List(AuditKeyPrefix, filter.queries()...)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(rows))
+	for key := range rows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	entries := make([]AuditEntry, 0, len(keys))
+	for _, key := range keys {
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(rows[key]), &entry); err != nil {
+			return nil, fferr.NewInternalErrorf("could not parse audit entry %s: %v", key, err)
+		}
+		if filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+	return entries, nil
+}
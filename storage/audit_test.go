@@ -0,0 +1,177 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/featureform/auth"
+	"github.com/featureform/ffsync"
+	"github.com/featureform/logging"
+)
+
+// testDiffer audits keys under "resource/" and reports the new value as the only change.
+func testDiffer(key, oldValue, newValue string) (AuditResource, []AuditChange, bool) {
+	name, ok := strings.CutPrefix(key, "resource/")
+	if !ok {
+		return AuditResource{}, nil, false
+	}
+	return AuditResource{Type: "FEATURE_VARIANT", Name: name}, []AuditChange{{Path: "value", OldValue: oldValue, NewValue: newValue}}, true
+}
+
+func newAuditedStorage(t *testing.T) MetadataStorage {
+	locker, err := ffsync.NewMemoryLocker()
+	if err != nil {
+		t.Fatalf("Failed to create locker: %v", err)
+	}
+	impl, err := NewMemoryStorageImplementation()
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	return MetadataStorage{
+		Locker:  &locker,
+		Storage: &impl,
+		Logger:  logging.NewTestLogger(t),
+		Audit:   NewAuditLog(&impl, testDiffer),
+	}
+}
+
+func TestAuditLogRecordsWrites(t *testing.T) {
+	storage := newAuditedStorage(t)
+	_, ctx, _ := logging.InitializeTestRequestID(t)
+	aliceCtx := auth.WithPrincipal(ctx, &auth.Principal{Subject: "alice"})
+
+	if err := storage.Create(aliceCtx, "resource/a", "v1"); err != nil {
+		t.Fatalf("Failed to create: %v", err)
+	}
+	if err := storage.Create(aliceCtx, "resource/a", "v2"); err != nil {
+		t.Fatalf("Failed to overwrite: %v", err)
+	}
+	if err := storage.Update("resource/a", func(string) (string, error) { return "v3", nil }); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	if err := storage.Create(ctx, "untracked", "value"); err != nil {
+		t.Fatalf("Failed to create untracked key: %v", err)
+	}
+
+	entries, err := storage.Audit.List(AuditFilter{})
+	if err != nil {
+		t.Fatalf("Failed to list audit log: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %+v", len(entries), entries)
+	}
+	expected := []struct {
+		op       AuditOperation
+		actor    string
+		oldValue string
+		newValue string
+	}{
+		{AuditCreate, "alice", "", "v1"},
+		{AuditUpdate, "alice", "v1", "v2"},
+		{AuditUpdate, "", "v2", "v3"},
+	}
+	for i, exp := range expected {
+		entry := entries[i]
+		if entry.Operation != exp.op || entry.Actor != exp.actor {
+			t.Fatalf("Entry %d: expected %s by %q, got %s by %q", i, exp.op, exp.actor, entry.Operation, entry.Actor)
+		}
+		if entry.Resource.Name != "a" || entry.Key != "resource/a" {
+			t.Fatalf("Entry %d: unexpected resource %+v", i, entry.Resource)
+		}
+		if len(entry.Changes) != 1 || entry.Changes[0].OldValue != exp.oldValue || entry.Changes[0].NewValue != exp.newValue {
+			t.Fatalf("Entry %d: unexpected changes %+v", i, entry.Changes)
+		}
+	}
+	if entries[0].RequestID != logging.GetRequestIDFromContext(ctx).String() {
+		t.Fatalf("Expected request ID %s, got %s", logging.GetRequestIDFromContext(ctx), entries[0].RequestID)
+	}
+}
+
+func TestAuditLogFilter(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		impl, err := NewMemoryStorageImplementation()
+		if err != nil {
+			t.Fatalf("Failed to create storage: %v", err)
+		}
+		// Memory storage ignores query options, List filters the entries itself.
+		testAuditLogFilter(t, &impl, false)
+	})
+	t.Run("SQLite", func(t *testing.T) {
+		ctx := logging.NewTestContext(t)
+		liteDB := createSQLiteTestDB(ctx, t, filepath.Join(t.TempDir(), "featureform.db"))
+		impl, err := NewSQLiteStorageImplementation(ctx, liteDB, "ff_task_metadata")
+		if err != nil {
+			t.Fatalf("Failed to create SQLite storage: %v", err)
+		}
+		testAuditLogFilter(t, impl, true)
+	})
+}
+
+func testAuditLogFilter(t *testing.T, impl metadataStorageImplementation, pushedDown bool) {
+	log := NewAuditLog(impl, testDiffer)
+	ctx := context.Background()
+	start := time.Now().UTC()
+	entries := []AuditEntry{
+		{Actor: "alice", Operation: AuditCreate, Resource: AuditResource{Type: "FEATURE_VARIANT", Name: "a", Variant: "v1"}, Timestamp: start},
+		{Actor: "bob", Operation: AuditCreate, Resource: AuditResource{Type: "FEATURE_VARIANT", Name: "a", Variant: "v2"}, Timestamp: start.Add(time.Second)},
+		{Actor: "bob", Operation: AuditMarkForDeletion, Resource: AuditResource{Type: "LABEL_VARIANT", Name: "a", Variant: "v1"}, Timestamp: start.Add(2 * time.Second)},
+	}
+	for _, entry := range entries {
+		if err := log.Append(ctx, entry); err != nil {
+			t.Fatalf("Failed to append: %v", err)
+		}
+	}
+	tests := []struct {
+		name     string
+		filter   AuditFilter
+		expected []AuditOperation
+		actors   []string
+	}{
+		{"All", AuditFilter{}, nil, []string{"alice", "bob", "bob"}},
+		{"Actor", AuditFilter{Actor: "bob"}, nil, []string{"bob", "bob"}},
+		{"Since", AuditFilter{Since: start.Add(time.Second)}, nil, []string{"bob", "bob"}},
+		{"Limit keeps newest", AuditFilter{Limit: 1}, []AuditOperation{AuditMarkForDeletion}, []string{"bob"}},
+		{"Resource name", AuditFilter{Resource: &AuditResource{Type: "FEATURE_VARIANT", Name: "a"}}, nil, []string{"alice", "bob"}},
+		{"Resource variant", AuditFilter{Resource: &AuditResource{Type: "FEATURE_VARIANT", Name: "a", Variant: "v2"}}, nil, []string{"bob"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := log.List(tt.filter)
+			if err != nil {
+				t.Fatalf("Failed to list: %v", err)
+			}
+			if pushedDown {
+				rows, err := impl.List(AuditKeyPrefix, tt.filter.queries()...)
+				if err != nil {
+					t.Fatalf("Failed to list with queries: %v", err)
+				}
+				if len(rows) != len(tt.actors) {
+					t.Fatalf("Expected the storage query to return %d entries, got %d", len(tt.actors), len(rows))
+				}
+			}
+			if len(found) != len(tt.actors) {
+				t.Fatalf("Expected %d entries, got %d: %+v", len(tt.actors), len(found), found)
+			}
+			for i, actor := range tt.actors {
+				if found[i].Actor != actor {
+					t.Fatalf("Entry %d: expected actor %s, got %s", i, actor, found[i].Actor)
+				}
+			}
+			for i, op := range tt.expected {
+				if found[i].Operation != op {
+					t.Fatalf("Entry %d: expected %s, got %s", i, op, found[i].Operation)
+				}
+			}
+		})
+	}
+}
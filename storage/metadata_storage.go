@@ -22,6 +22,31 @@ type MetadataStorage struct {
 	Storage         metadataStorageImplementation
 	Logger          logging.Logger
 	SkipListLocking bool
+	// Audit records writes to the keys it tracks; nil disables auditing.
+	Audit *AuditLog
+	// Codec transforms values as they're written and read; nil stores them as is.
+	Codec ValueCodec
+}
+
+// ValueCodec transforms values on their way in and out of the state provider, for example to
+// encrypt part of them. Encoded values must remain valid JSON since queries look inside them.
+type ValueCodec interface {
+	Encode(ctx context.Context, key, value string) (string, error)
+	Decode(ctx context.Context, key, value string) (string, error)
+}
+
+func (s *MetadataStorage) encode(ctx context.Context, key, value string) (string, error) {
+	if s.Codec == nil {
+		return value, nil
+	}
+	return s.Codec.Encode(ctx, key, value)
+}
+
+func (s *MetadataStorage) decode(ctx context.Context, key, value string) (string, error) {
+	if s.Codec == nil {
+		return value, nil
+	}
+	return s.Codec.Decode(ctx, key, value)
 }
 
 func (s *MetadataStorage) unlockWithLogger(ctx context.Context, Locker ffsync.Locker, key ffsync.Key, logger logging.Logger) {
@@ -41,14 +66,41 @@ func (s *MetadataStorage) Create(ctx context.Context, key string, value string)
 	}
 
 	defer s.unlockWithLogger(ctx, s.Locker, lock, logger)
-	if err := s.Storage.Set(ctx, key, value); err != nil {
+	audited := s.Audit != nil && s.Audit.Tracks(key)
+	var oldValue string
+	if audited {
+		// A missing key means this write creates it.
+		if stored, err := s.Storage.Get(key); err == nil {
+			oldValue, _ = s.decode(ctx, key, stored)
+		}
+	}
+	encoded, err := s.encode(ctx, key, value)
+	if err != nil {
+		logger.Errorw("Error encoding value", "error", err)
+		return err
+	}
+	if err := s.Storage.Set(ctx, key, encoded); err != nil {
 		logger.Errorw("Error setting key", "error", err)
 		return err
 	}
 	logger.Debug("Key set successfully")
+	if audited {
+		op := AuditCreate
+		if oldValue != "" {
+			op = AuditUpdate
+		}
+		s.recordAudit(ctx, logger, op, key, oldValue, value)
+	}
 	return nil
 }
 
+// recordAudit doesn't fail the write it describes, since that has already been applied.
+func (s *MetadataStorage) recordAudit(ctx context.Context, logger logging.Logger, op AuditOperation, key, oldValue, newValue string) {
+	if err := s.Audit.Record(ctx, op, key, oldValue, newValue); err != nil {
+		logger.Errorw("Failed to record audit entry", "key", key, "operation", op, "error", err)
+	}
+}
+
 func (s *MetadataStorage) MultiCreate(data map[string]string) error {
 	ctx := context.Background()
 	reqID := uuid.NewString()
@@ -68,10 +120,13 @@ func (s *MetadataStorage) MultiCreate(data map[string]string) error {
 
 	// Set all values
 	for key, value := range data {
-		err := s.Storage.Set(ctx, key, value)
+		encoded, err := s.encode(ctx, key, value)
 		if err != nil {
 			return err
 		}
+		if err := s.Storage.Set(ctx, key, encoded); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -89,7 +144,11 @@ func (s *MetadataStorage) Update(key string, updateFn func(string) (string, erro
 	}
 	defer s.unlockWithLogger(ctx, s.Locker, lock, logger)
 
-	currentValue, err := s.Storage.Get(key)
+	storedValue, err := s.Storage.Get(key)
+	if err != nil {
+		return err
+	}
+	currentValue, err := s.decode(ctx, key, storedValue)
 	if err != nil {
 		return err
 	}
@@ -99,7 +158,17 @@ func (s *MetadataStorage) Update(key string, updateFn func(string) (string, erro
 		return err
 	}
 
-	return s.Storage.Set(ctx, key, newValue)
+	encoded, err := s.encode(ctx, key, newValue)
+	if err != nil {
+		return err
+	}
+	if err := s.Storage.Set(ctx, key, encoded); err != nil {
+		return err
+	}
+	if s.Audit != nil && s.Audit.Tracks(key) {
+		s.recordAudit(ctx, logger, AuditUpdate, key, currentValue, newValue)
+	}
+	return nil
 }
 
 func (s *MetadataStorage) List(prefix string, opts ...query.Query) (map[string]string, error) {
@@ -117,7 +186,16 @@ func (s *MetadataStorage) List(prefix string, opts ...query.Query) (map[string]s
 		defer s.unlockWithLogger(ctx, s.Locker, lock, logger)
 	}
 
-	return s.Storage.List(prefix, opts...)
+	values, err := s.Storage.List(prefix, opts...)
+	if err != nil || s.Codec == nil {
+		return values, err
+	}
+	for key, value := range values {
+		if values[key], err = s.decode(ctx, key, value); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
 }
 
 func (s *MetadataStorage) ListColumn(prefix string, columns []query.Column, opts ...query.Query) ([]map[string]interface{}, error) {
@@ -174,7 +252,22 @@ func (s *MetadataStorage) Get(key string, opts ...query.Query) (string, error) {
 		return "", err
 	}
 	logger.Debug("Retrieved key")
-	return val, nil
+	return s.decode(ctx, key, val)
+}
+
+// Search returns the values whose resources match q. It doesn't lock, matching List with
+// SkipListLocking, since results span arbitrary keys.
+func (s *MetadataStorage) Search(ctx context.Context, q string, opts ...query.Query) (map[string]string, error) {
+	values, err := s.Storage.Search(ctx, q, opts...)
+	if err != nil || s.Codec == nil {
+		return values, err
+	}
+	for key, value := range values {
+		if values[key], err = s.decode(ctx, key, value); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
 }
 
 func (s *MetadataStorage) Delete(key string) (string, error) {
@@ -194,7 +287,7 @@ func (s *MetadataStorage) Delete(key string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return value, nil
+	return s.decode(ctx, key, value)
 }
 
 func (s *MetadataStorage) Close() {
@@ -207,6 +300,7 @@ type MetadataStorageType string
 const (
 	MemoryMetadataStorage MetadataStorageType = "memory"
 	PSQLMetadataStorage   MetadataStorageType = "psql"
+	SQLiteMetadataStorage MetadataStorageType = "sqlite"
 )
 
 type metadataStorageImplementation interface {
@@ -18,6 +18,17 @@ func (qry KeyPrefix) Category() Category {
 	return FilterQuery
 }
 
+// KeyRange keeps the keys that sort at or after Start and before End.
+// Either bound can be left empty.
+type KeyRange struct {
+	Start string
+	End   string
+}
+
+func (qry KeyRange) Category() Category {
+	return FilterQuery
+}
+
 type ValueEquals struct {
 	Not    bool
 	Column Column
@@ -14,9 +14,12 @@ import (
 	"github.com/featureform/storage/query"
 )
 
-func compileColumn(clm query.Column) (string, error) {
+func compileColumn(dialect Dialect, clm query.Column) (string, error) {
 	switch casted := clm.(type) {
 	case query.JSONColumn:
+		if dialect.orDefault() == SQLite {
+			return compileSQLiteJSONColumn(casted)
+		}
 		return compileJSONColumn(casted)
 	case query.SQLColumn:
 		return casted.Column, nil
@@ -67,3 +70,35 @@ func compileJSONColumn(clm query.JSONColumn) (string, error) {
 
 	return qry, nil
 }
+
+// compileSQLiteJSONColumn mirrors compileJSONColumn for SQLite. SQLite's -> and ->> operators
+// accept JSON text directly, so nested JSON strings don't need to be cast before traversing them.
+func compileSQLiteJSONColumn(clm query.JSONColumn) (string, error) {
+	qry := "value"
+
+	for i, step := range clm.Path {
+		operator := "->"
+		isLastStep := i == len(clm.Path)-1
+		// Objects stay as JSON text on the last step, everything else is converted into a scalar.
+		returnsObject := clm.Type == query.Object && isLastStep
+		if !returnsObject && (step.IsJsonString || isLastStep) {
+			operator = "->>"
+		}
+		qry += fmt.Sprintf("%s'%s'", operator, step.Key)
+	}
+
+	switch clm.Type {
+	case query.String:
+		qry = fmt.Sprintf("(%s)", qry)
+	case query.Object:
+		qry = fmt.Sprintf("(%s)", qry)
+	case query.Int:
+		qry = fmt.Sprintf("CAST((%s) AS INTEGER)", qry)
+	case query.Timestamp:
+		qry = fmt.Sprintf("datetime(%s)", qry)
+	default:
+		return "", fferr.NewInternalErrorf("Unsupported JSON value type in SQL storage: %s", clm.Type)
+	}
+
+	return qry, nil
+}
@@ -86,7 +86,7 @@ func TestCompileColumn(t *testing.T) {
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			qry, err := compileColumn(test.Column)
+			qry, err := compileColumn(Postgres, test.Column)
 			if err != nil {
 				t.Fatalf("Failed to compile column: %v\n%s", test.Column, err)
 			}
@@ -0,0 +1,24 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package sqlgen
+
+// Dialect specifies which flavor of SQL a query is compiled into. The zero value
+// compiles to Postgres.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+func (d Dialect) orDefault() Dialect {
+	if d == "" {
+		return Postgres
+	}
+	return d
+}
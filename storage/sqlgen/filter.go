@@ -15,7 +15,7 @@ import (
 	"github.com/featureform/storage/query"
 )
 
-func compileFilters(filters []query.Query) (string, []any, error) {
+func compileFilters(dialect Dialect, filters []query.Query) (string, []any, error) {
 	if len(filters) == 0 {
 		return "", []any{}, nil
 	}
@@ -24,7 +24,7 @@ func compileFilters(filters []query.Query) (string, []any, error) {
 	argNum := 1
 	for i, filter := range filters {
 		// i starts at 0, but args start at 1 in postgres.
-		qry, qryArgs, err := compileFilter(filter, argNum)
+		qry, qryArgs, err := compileFilter(dialect, filter, argNum)
 		if err != nil {
 			return "", nil, err
 		}
@@ -36,29 +36,31 @@ func compileFilters(filters []query.Query) (string, []any, error) {
 	return "WHERE " + predicateStr, args, nil
 }
 
-func compileFilter(filter query.Query, argNum int) (string, []any, error) {
+func compileFilter(dialect Dialect, filter query.Query, argNum int) (string, []any, error) {
 	switch casted := filter.(type) {
 	case query.KeyPrefix:
-		return compileKeyPrefix(casted, argNum)
+		return compileKeyPrefix(dialect, casted, argNum)
+	case query.KeyRange:
+		return compileKeyRange(dialect, casted, argNum)
 	case query.ValueEquals:
-		return compileValueEquals(casted, argNum)
+		return compileValueEquals(dialect, casted, argNum)
 	case query.ValueIn:
-		return compileValueIn(casted, argNum)
+		return compileValueIn(dialect, casted, argNum)
 	case query.ArrayContains:
-		return compileArrayContains(casted, argNum)
+		return compileArrayContains(dialect, casted, argNum)
 	case query.ObjectArrayContains:
-		return compileObjectArrayContains(casted, argNum)
+		return compileObjectArrayContains(dialect, casted, argNum)
 	case query.ValueLike:
-		return compileValueLike(casted, argNum)
+		return compileValueLike(dialect, casted, argNum)
 	case query.ConditionalOR:
-		return compileConditionalOR(casted, argNum)
+		return compileConditionalOR(dialect, casted, argNum)
 	default:
 		return "", nil, fferr.NewInternalErrorf("Unsupported filter type in SQL storage: %T", casted)
 	}
 }
 
-func compileKeyPrefix(filter query.KeyPrefix, argNum int) (string, []any, error) {
-	argStr, err := compileArgNum(argNum)
+func compileKeyPrefix(dialect Dialect, filter query.KeyPrefix, argNum int) (string, []any, error) {
+	argStr, err := compileArgNum(dialect, argNum)
 	if err != nil {
 		return "", nil, err
 	}
@@ -71,15 +73,41 @@ func compileKeyPrefix(filter query.KeyPrefix, argNum int) (string, []any, error)
 	return fmt.Sprintf("key %s %s", operation, argStr), []any{filter.Prefix + "%"}, nil
 }
 
-func compileValueEquals(qry query.ValueEquals, argNum int) (string, []any, error) {
-	argStr, err := compileArgNum(argNum)
+func compileKeyRange(dialect Dialect, filter query.KeyRange, argNum int) (string, []any, error) {
+	var predicates []string
+	var args []any
+	if filter.Start != "" {
+		argStr, err := compileArgNum(dialect, argNum)
+		if err != nil {
+			return "", nil, err
+		}
+		predicates = append(predicates, fmt.Sprintf("key >= %s", argStr))
+		args = append(args, filter.Start)
+		argNum++
+	}
+	if filter.End != "" {
+		argStr, err := compileArgNum(dialect, argNum)
+		if err != nil {
+			return "", nil, err
+		}
+		predicates = append(predicates, fmt.Sprintf("key < %s", argStr))
+		args = append(args, filter.End)
+	}
+	if len(predicates) == 0 {
+		return "", nil, fferr.NewInternalErrorf("KeyRange needs a start or an end")
+	}
+	return strings.Join(predicates, " AND "), args, nil
+}
+
+func compileValueEquals(dialect Dialect, qry query.ValueEquals, argNum int) (string, []any, error) {
+	argStr, err := compileArgNum(dialect, argNum)
 	if err != nil {
 		return "", nil, err
 	}
 	if qry.Column == nil {
 		return "", nil, fferr.NewInternalErrorf("Column not set in ValueEquals")
 	}
-	clmStr, err := compileColumn(qry.Column)
+	clmStr, err := compileColumn(dialect, qry.Column)
 	if err != nil {
 		return "", nil, err
 	}
@@ -100,20 +128,20 @@ func compileValueEquals(qry query.ValueEquals, argNum int) (string, []any, error
 	return fmt.Sprintf("%s %s %s", clmStr, operation, argStr), []any{qry.Value}, nil
 }
 
-func compileValueIn(qry query.ValueIn, argNum int) (string, []any, error) {
+func compileValueIn(dialect Dialect, qry query.ValueIn, argNum int) (string, []any, error) {
 	if len(qry.Values) == 0 {
 		return "", nil, fferr.NewInternalErrorf("Cannot query ValueIn to an empty array in SQL")
 	}
 	if qry.Column == nil {
 		return "", nil, fferr.NewInternalErrorf("Column not set in ValueIn")
 	}
-	clmStr, err := compileColumn(qry.Column)
+	clmStr, err := compileColumn(dialect, qry.Column)
 	if err != nil {
 		return "", nil, err
 	}
 	argStrs := make([]string, len(qry.Values))
 	for i := range qry.Values {
-		argStr, err := compileArgNum(argNum)
+		argStr, err := compileArgNum(dialect, argNum)
 		if err != nil {
 			return "", nil, err
 		}
@@ -124,7 +152,7 @@ func compileValueIn(qry query.ValueIn, argNum int) (string, []any, error) {
 	return fmt.Sprintf("%s IN %s", clmStr, argList), qry.Values, nil
 }
 
-func compileArrayContains(qry query.ArrayContains, argNum int) (string, []any, error) {
+func compileArrayContains(dialect Dialect, qry query.ArrayContains, argNum int) (string, []any, error) {
 	if len(qry.Values) == 0 {
 		return "", nil, fferr.NewInternalErrorf("Cannot compile Array Contains with an empty values array")
 	}
@@ -132,7 +160,7 @@ func compileArrayContains(qry query.ArrayContains, argNum int) (string, []any, e
 		return "", nil, fferr.NewInternalErrorf("Column not set in Array Contains")
 	}
 
-	clmStr, err := compileColumn(qry.Column)
+	clmStr, err := compileColumn(dialect, qry.Column)
 	if err != nil {
 		return "", nil, err
 	}
@@ -141,31 +169,40 @@ func compileArrayContains(qry query.ArrayContains, argNum int) (string, []any, e
 	argPlaceholders := make([]string, len(qry.Values))
 	args := make([]any, len(qry.Values))
 	for i := range qry.Values {
-		argPlaceholders[i] = fmt.Sprintf("$%d", argNum+i)
+		argStr, err := compileArgNum(dialect, argNum+i)
+		if err != nil {
+			return "", nil, err
+		}
+		argPlaceholders[i] = argStr
 		args[i] = qry.Values[i]
 	}
 
 	// group the placeholders together
 	placeholderStr := strings.Join(argPlaceholders, ", ")
 
+	// SQLite has no array operators, so we expand the JSON array into rows instead.
+	if dialect.orDefault() == SQLite {
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM %s AS elem WHERE elem.value IN (%s))", sqliteJSONEach(clmStr), placeholderStr), args, nil
+	}
+
 	// this results in an array check conditional
 	return fmt.Sprintf("(%s)::jsonb ?| array[%s]", clmStr, placeholderStr), args, nil
 }
 
-func compileObjectArrayContains(qry query.ObjectArrayContains, argNum int) (string, []any, error) {
+func compileObjectArrayContains(dialect Dialect, qry query.ObjectArrayContains, argNum int) (string, []any, error) {
 	if len(qry.Values) == 0 {
 		return "", nil, fferr.NewInternalErrorf("Cannot query ValueIn to an empty array in SQL")
 	}
 	if qry.Column == nil {
 		return "", nil, fferr.NewInternalErrorf("Column not set in Object Array Contains")
 	}
-	clmStr, err := compileColumn(qry.Column)
+	clmStr, err := compileColumn(dialect, qry.Column)
 	if err != nil {
 		return "", nil, err
 	}
 	argStrs := make([]string, len(qry.Values))
 	for i := range qry.Values {
-		argStr, err := compileArgNum(argNum)
+		argStr, err := compileArgNum(dialect, argNum)
 		if err != nil {
 			return "", nil, err
 		}
@@ -173,18 +210,21 @@ func compileObjectArrayContains(qry query.ObjectArrayContains, argNum int) (stri
 		argStrs[i] = argStr
 	}
 	argList := fmt.Sprintf("(%s)", strings.Join(argStrs, ","))
+	if dialect.orDefault() == SQLite {
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM %s AS elem WHERE elem.value->>'%s' IN %s)", sqliteJSONEach(clmStr), qry.SearchField, argList), qry.Values, nil
+	}
 	return fmt.Sprintf("EXISTS (SELECT 1 FROM jsonb_array_elements%s AS elem WHERE elem->>'%s' IN %s)", clmStr, qry.SearchField, argList), qry.Values, nil
 }
 
-func compileValueLike(qry query.ValueLike, argNum int) (string, []any, error) {
-	argStr, err := compileArgNum(argNum)
+func compileValueLike(dialect Dialect, qry query.ValueLike, argNum int) (string, []any, error) {
+	argStr, err := compileArgNum(dialect, argNum)
 	if err != nil {
 		return "", nil, err
 	}
 	if qry.Column == nil {
 		return "", nil, fferr.NewInternalErrorf("Column not set in ValueLike")
 	}
-	clmStr, err := compileColumn(qry.Column)
+	clmStr, err := compileColumn(dialect, qry.Column)
 	if err != nil {
 		return "", nil, err
 	}
@@ -192,7 +232,7 @@ func compileValueLike(qry query.ValueLike, argNum int) (string, []any, error) {
 	return fmt.Sprintf("%s like %s", clmStr, argStr), []any{valuePattern}, nil
 }
 
-func compileConditionalOR(conditionalQry query.ConditionalOR, argNum int) (string, []any, error) {
+func compileConditionalOR(dialect Dialect, conditionalQry query.ConditionalOR, argNum int) (string, []any, error) {
 	if len(conditionalQry.Filters) == 0 {
 		return "", nil, fferr.NewInternalErrorf("Cannot compile or with no filters")
 	}
@@ -206,7 +246,7 @@ func compileConditionalOR(conditionalQry query.ConditionalOR, argNum int) (strin
 		if _, ok := filter.(query.ConditionalOR); ok {
 			return "", nil, fferr.NewInternalErrorf("Cannot have a conditional OR inside another conditional OR")
 		}
-		qry, qryArgs, err := compileFilter(filter, argNum)
+		qry, qryArgs, err := compileFilter(dialect, filter, argNum)
 		if err != nil {
 			return "", nil, err
 		}
@@ -218,9 +258,18 @@ func compileConditionalOR(conditionalQry query.ConditionalOR, argNum int) (strin
 	return fmt.Sprintf("(%s)", predicateStr), args, nil
 }
 
-func compileArgNum(argNum int) (string, error) {
+func compileArgNum(dialect Dialect, argNum int) (string, error) {
 	if argNum < 1 {
 		return "", fferr.NewInternalErrorf("ArgNum cannot be less than 1")
 	}
+	if dialect.orDefault() == SQLite {
+		return fmt.Sprintf("?%d", argNum), nil
+	}
 	return fmt.Sprintf("$%d", argNum), nil
 }
+
+// sqliteJSONEach expands a JSON array column into rows. json_each has its own key and value
+// columns which would shadow the table's, so the column is selected in a derived table first.
+func sqliteJSONEach(clmStr string) string {
+	return fmt.Sprintf("(SELECT %s AS arr) AS src, json_each(src.arr)", clmStr)
+}
@@ -107,7 +107,7 @@ func TestCompileFilters(t *testing.T) {
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			qry, args, err := compileFilters(test.Filters)
+			qry, args, err := compileFilters(Postgres, test.Filters)
 			if err != nil {
 				t.Fatalf("%s: Failed to compile sort: %v\n%s", name, test.Filters, err)
 			}
@@ -140,6 +140,16 @@ func TestCompileFilter(t *testing.T) {
 			Expected:    "key NOT LIKE $1",
 			ExpectedArg: []any{"LABEL__%"},
 		},
+		"key range start": {
+			Filter:      query.KeyRange{Start: "/audit/entry=1"},
+			Expected:    "key >= $1",
+			ExpectedArg: []any{"/audit/entry=1"},
+		},
+		"key range start and end": {
+			Filter:      query.KeyRange{Start: "a", End: "b"},
+			Expected:    "key >= $1 AND key < $2",
+			ExpectedArg: []any{"a", "b"},
+		},
 		"JSON value equals": {
 			Filter: query.ValueEquals{
 				Column: query.JSONColumn{
@@ -290,7 +300,7 @@ func TestCompileFilter(t *testing.T) {
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			qry, arg, err := compileFilter(test.Filter, 1)
+			qry, arg, err := compileFilter(Postgres, test.Filter, 1)
 			if err != nil && err.Error() != test.ExpectedErr.Error() {
 				t.Fatalf("Failed to compile filter: %v\n%s", test.Filter, err)
 			}
@@ -22,6 +22,7 @@ type List struct {
 	Sort      query.Sort
 	Limit     query.Limit
 	GroupBy   query.GroupBy
+	Dialect   Dialect
 }
 
 func (list *List) Compile() (string, []any, error) {
@@ -33,6 +34,7 @@ func (list *List) CompileCount() (string, []any, error) {
 }
 
 func (list *List) compile(countOnly bool) (string, []any, error) {
+	dialect := list.Dialect.orDefault()
 	selectClms, err := list.compileColumns(countOnly)
 	if err != nil {
 		return "", nil, err
@@ -42,7 +44,7 @@ func (list *List) compile(countOnly bool) (string, []any, error) {
 	queryParts := []string{
 		base,
 	}
-	where, args, err := compileFilters(list.Filters)
+	where, args, err := compileFilters(dialect, list.Filters)
 	if err != nil {
 		return "", nil, err
 	}
@@ -58,7 +60,7 @@ func (list *List) compile(countOnly bool) (string, []any, error) {
 		queryParts = append(queryParts, groupBy)
 	}
 
-	sort, err := compileSort(list.Sort)
+	sort, err := compileSort(dialect, list.Sort)
 	if err != nil {
 		return "", nil, err
 	}
@@ -117,6 +119,15 @@ func (list *List) compileColumns(countOnly bool) (string, error) {
 }
 
 func NewListQuery(tableName string, opts []query.Query, columns ...query.Column) (*List, error) {
+	return newListQuery(Postgres, tableName, opts, columns...)
+}
+
+// NewSQLiteListQuery is the same as NewListQuery but compiles into SQLite.
+func NewSQLiteListQuery(tableName string, opts []query.Query, columns ...query.Column) (*List, error) {
+	return newListQuery(SQLite, tableName, opts, columns...)
+}
+
+func newListQuery(dialect Dialect, tableName string, opts []query.Query, columns ...query.Column) (*List, error) {
 	if tableName == "" {
 		return nil, fferr.NewInternalErrorf("Table name not set in list query")
 	}
@@ -124,6 +135,7 @@ func NewListQuery(tableName string, opts []query.Query, columns ...query.Column)
 	list := &List{
 		TableName: tableName,
 		Columns:   columns,
+		Dialect:   dialect,
 	}
 
 	var err error
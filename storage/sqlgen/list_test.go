@@ -191,3 +191,96 @@ func TestCompileLists(t *testing.T) {
 		})
 	}
 }
+
+func TestCompileSQLiteLists(t *testing.T) {
+	tests := map[string]ListTest{
+		"status types": {
+			Opts: []query.Query{query.ValueIn{
+				Column: query.JSONColumn{
+					Path: []query.JSONPathStep{{Key: "Message", IsJsonString: true}, {Key: "type"}},
+					Type: query.String,
+				},
+				Values: []any{provider_type.PostgresOffline, provider_type.AZURE}}},
+			CountOnly:    true,
+			Expected:     "SELECT COUNT(*) FROM table WHERE (value->>'Message'->>'type') IN (?1,?2)",
+			ExpectedArgs: []any{provider_type.PostgresOffline, provider_type.AZURE},
+		},
+		"sort by int": {
+			Opts: []query.Query{
+				query.KeyPrefix{Prefix: "FEATURE__"},
+				query.ValueSort{
+					Column: query.JSONColumn{
+						Path: []query.JSONPathStep{{Key: "taskID"}},
+						Type: query.Int,
+					},
+					Dir: query.Desc,
+				},
+			},
+			Expected:     "SELECT key, value FROM table WHERE key LIKE ?1 ORDER BY CAST((value->>'taskID') AS INTEGER) DESC",
+			ExpectedArgs: []any{"FEATURE__%"},
+		},
+		"array contains": {
+			Opts: []query.Query{
+				query.ValueEquals{
+					Column: query.JSONColumn{
+						Path: []query.JSONPathStep{{Key: "SerializedVersion"}},
+						Type: query.String,
+					},
+					Value: "1",
+				},
+				query.ArrayContains{
+					Column: query.JSONColumn{
+						Path: []query.JSONPathStep{{Key: "Message", IsJsonString: true}, {Key: "tags"}, {Key: "tag"}},
+						Type: query.String,
+					},
+					Values: []any{"myTag", "yourTag"},
+				},
+			},
+			Expected: "SELECT key, value FROM table WHERE (value->>'SerializedVersion') = ?1 AND EXISTS (SELECT 1 FROM (SELECT (value->>'Message'->'tags'->>'tag') AS arr) AS src, json_each(src.arr) AS elem WHERE elem.value IN (?2, ?3))",
+			ExpectedArgs: []any{
+				"1",
+				"myTag",
+				"yourTag",
+			},
+		},
+		"object array contains": {
+			Opts: []query.Query{
+				query.ObjectArrayContains{
+					Column: query.JSONColumn{
+						Path: []query.JSONPathStep{{Key: "Message", IsJsonString: true}, {Key: "columns"}},
+						Type: query.Object,
+					},
+					SearchField: "name",
+					Values:      []any{"user_id"},
+				},
+			},
+			Expected:     "SELECT key, value FROM table WHERE EXISTS (SELECT 1 FROM (SELECT (value->>'Message'->'columns') AS arr) AS src, json_each(src.arr) AS elem WHERE elem.value->>'name' IN (?1))",
+			ExpectedArgs: []any{"user_id"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			list, err := NewSQLiteListQuery("table", test.Opts, test.Columns...)
+			if err != nil {
+				t.Fatalf("%s:Failed to compile list: %v\n%s", name, test.Opts, err)
+			}
+			var qry string
+			var args []any
+			var compileErr error
+			if test.CountOnly {
+				qry, args, compileErr = list.CompileCount()
+			} else {
+				qry, args, compileErr = list.Compile()
+			}
+			if compileErr != nil {
+				t.Fatalf("%s:Failed to compile list query: %v\n%s", name, list, compileErr)
+			}
+			if !reflect.DeepEqual(test.ExpectedArgs, args) {
+				t.Errorf("%s:Args not equal\nFound: %v\nExpected: %v", name, args, test.ExpectedArgs)
+			}
+			if qry != test.Expected {
+				t.Errorf("%s:SQL not equal\n%s\n%s", name, qry, test.Expected)
+			}
+		})
+	}
+}
@@ -14,7 +14,7 @@ import (
 	"github.com/featureform/storage/query"
 )
 
-func compileSort(sort query.Sort) (string, error) {
+func compileSort(dialect Dialect, sort query.Sort) (string, error) {
 	if sort == nil {
 		return "", nil
 	}
@@ -22,7 +22,7 @@ func compileSort(sort query.Sort) (string, error) {
 	case query.KeySort:
 		return compileKeySort(casted)
 	case query.ValueSort:
-		return compileValueSort(casted)
+		return compileValueSort(dialect, casted)
 	default:
 		return "", fferr.NewInternalErrorf("Unsupported sort type in SQL storage: %T", casted)
 	}
@@ -36,7 +36,7 @@ func compileKeySort(sort query.KeySort) (string, error) {
 	return fmt.Sprintf("ORDER BY key %s", dirStr), nil
 }
 
-func compileValueSort(sort query.ValueSort) (string, error) {
+func compileValueSort(dialect Dialect, sort query.ValueSort) (string, error) {
 	dirStr, err := compileSortDirection(sort.Direction())
 	if err != nil {
 		return "", err
@@ -45,7 +45,7 @@ func compileValueSort(sort query.ValueSort) (string, error) {
 	if sort.Column == nil {
 		clmStr = "value"
 	} else {
-		compiledClm, err := compileColumn(sort.Column)
+		compiledClm, err := compileColumn(dialect, sort.Column)
 		if err != nil {
 			return "", err
 		}
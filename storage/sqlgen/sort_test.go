@@ -37,7 +37,7 @@ func TestCompileSort(t *testing.T) {
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			qry, err := compileSort(test.Sort)
+			qry, err := compileSort(Postgres, test.Sort)
 			if err != nil {
 				t.Fatalf("Failed to compile sort: %v\n%s", test.Sort, err)
 			}
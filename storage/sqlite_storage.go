@@ -0,0 +1,371 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/helpers/sqlite"
+	"github.com/featureform/logging"
+	"github.com/featureform/storage/query"
+	"github.com/featureform/storage/sqlgen"
+)
+
+const sqliteSearchTable = "search_resources"
+
+func NewSQLiteStorageImplementation(ctx context.Context, db *sqlite.DB, tableName string) (metadataStorageImplementation, error) {
+	logger := logging.GetLoggerFromContext(ctx)
+
+	return &SQLiteStorageImplementation{
+		Db:        db,
+		tableName: tableName,
+		logger:    logger,
+	}, nil
+}
+
+// SQLiteStorageImplementation stores metadata in an embedded SQLite file. It shares its
+// table layout with PSQLStorageImplementation, so queries are compiled with the SQLite
+// dialect of sqlgen.
+type SQLiteStorageImplementation struct {
+	Db        *sqlite.DB
+	tableName string
+	logger    logging.Logger
+}
+
+func (lite *SQLiteStorageImplementation) Set(ctx context.Context, key string, value string) error {
+	logger := logging.GetLoggerFromContext(ctx)
+	if key == "" {
+		logger.Errorw("Cannot set an empty key")
+		return fferr.NewInvalidArgumentError(fmt.Errorf("cannot set an empty key"))
+	}
+	tx, err := lite.Db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.Errorw("Failed to begin transaction", "error", err)
+		return fferr.NewInternalErrorf("failed to set key %s in table %s: %w", key, lite.tableName, err)
+	}
+	defer tx.Rollback()
+
+	insertSQL := lite.setQuery()
+	logger.Debugw("Setting key with query", "query", insertSQL, "key", key, "value", value)
+	if _, err := tx.ExecContext(ctx, insertSQL, key, value); err != nil {
+		logger.Errorw("Failed to set key", "error", err)
+		return fferr.NewInternalErrorf("failed to set key %s in table %s: %w", key, lite.tableName, err)
+	}
+	// Postgres keeps the search index up to date with a trigger, SQLite triggers
+	// can't easily parse keys so we do it alongside the write.
+	if err := lite.indexForSearch(ctx, tx, key, value); err != nil {
+		logger.Errorw("Failed to update search index", "error", err)
+		return fferr.NewInternalErrorf("failed to update search index for key %s: %w", key, err)
+	}
+	if err := tx.Commit(); err != nil {
+		logger.Errorw("Failed to commit set", "error", err)
+		return fferr.NewInternalErrorf("failed to set key %s in table %s: %w", key, lite.tableName, err)
+	}
+	logger.Debugw("Key set successfully")
+	return nil
+}
+
+func (lite *SQLiteStorageImplementation) Get(key string, opts ...query.Query) (string, error) {
+	if key == "" {
+		lite.logger.Errorw("Cannot get an empty key")
+		return "", fferr.NewInvalidArgumentErrorf("cannot get an empty key")
+	}
+
+	opts = append(opts, query.ValueEquals{
+		Column: query.SQLColumn{
+			Column: "key",
+		},
+		Value: key,
+	})
+
+	logger := lite.logger.With("key", key, "options", opts, "table", lite.tableName)
+	logger.Infow("Getting key with options")
+	qry, err := sqlgen.NewSQLiteListQuery(lite.tableName, opts, query.SQLColumn{Column: "value"})
+	if err != nil {
+		logger.Errorw("qry builder failed", "error", err)
+		return "", err
+	}
+
+	qryStr, args, err := qry.Compile()
+	if err != nil {
+		logger.Errorw("Failed to compile get query", "error", err)
+		return "", err
+	}
+	logger.Debugw("get: compiled query", "query", qryStr, "args", args)
+
+	var value string
+	if err := lite.Db.QueryRow(qryStr, args...).Scan(&value); errors.Is(err, sql.ErrNoRows) {
+		return "", fferr.NewKeyNotFoundError(key, nil)
+	} else if err != nil {
+		logger.Errorw("Failed to scan row", "error", err)
+		return "", fferr.NewInternalErrorf("failed to get key %s: %v", key, err)
+	}
+	return value, nil
+}
+
+func (lite *SQLiteStorageImplementation) List(prefix string, opts ...query.Query) (map[string]string, error) {
+	opts = append(opts, query.KeyPrefix{Prefix: prefix})
+	logger := lite.logger.With("prefix", prefix, "options", opts, "table", lite.tableName)
+	logger.Infow("Listing keys with options")
+	qry, err := sqlgen.NewSQLiteListQuery(lite.tableName, opts)
+	if err != nil {
+		logger.Errorw("List failed", "error", err)
+		return nil, err
+	}
+	qryStr, args, err := qry.Compile()
+	if err != nil {
+		logger.Errorw("Failed to compile list query", "error", err)
+		return nil, err
+	}
+	logger.Debugw("List: Compiled query", "query", qryStr, "args", args)
+	rows, err := lite.Db.Query(qryStr, args...)
+	if err != nil {
+		logger.Errorw("List failed", "error", err)
+		return nil, fferr.NewInternalErrorf("failed to list keys with prefix %s: %v", prefix, err)
+	}
+	return scanKeyValues(rows)
+}
+
+func (lite *SQLiteStorageImplementation) Count(prefix string, opts ...query.Query) (int, error) {
+	opts = append(opts, query.KeyPrefix{Prefix: prefix})
+	lite.logger.Infow("Counting keys with options", "options", opts, "table", lite.tableName)
+	qry, err := sqlgen.NewSQLiteListQuery(lite.tableName, opts)
+	if err != nil {
+		lite.logger.Errorw("List failed", "error", err)
+		return 0, err
+	}
+	qryStr, args, err := qry.CompileCount()
+	if err != nil {
+		lite.logger.Errorw("Failed to compile count query", "error", err)
+		return 0, err
+	}
+	var cnt int
+	if err := lite.Db.QueryRow(qryStr, args...).Scan(&cnt); err != nil {
+		return 0, fferr.NewInternalErrorf("failed to count keys with prefix %s: %v", prefix, err)
+	}
+	return cnt, nil
+}
+
+func (lite *SQLiteStorageImplementation) ListColumn(prefix string, columns []query.Column, opts ...query.Query) ([]map[string]interface{}, error) {
+	opts = append(opts, query.KeyPrefix{Prefix: prefix})
+	lite.logger.Infow("Listing computed columns with options", "options", opts, "table", lite.tableName)
+	qry, err := sqlgen.NewSQLiteListQuery(lite.tableName, opts, columns...)
+	if err != nil {
+		lite.logger.Errorw("List failed", "error", err)
+		return nil, err
+	}
+	qryStr, args, err := qry.Compile()
+	if err != nil {
+		lite.logger.Errorw("Failed to compile list query", "error", err)
+		return nil, err
+	}
+	rows, err := lite.Db.Query(qryStr, args...)
+	if err != nil {
+		lite.logger.Errorw("List failed", "error", err)
+		return nil, fferr.NewInternalErrorf("failed to list rows with prefix %s: %w", prefix, err)
+	}
+	defer rows.Close()
+
+	columnNames, err := rows.Columns()
+	if err != nil {
+		return nil, fferr.NewInternalErrorf("failed to get column names: %w", err)
+	}
+
+	var results []map[string]interface{}
+	values := make([]interface{}, len(columnNames))
+	valuePtrs := make([]interface{}, len(columnNames))
+	for rows.Next() {
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, fferr.NewInternalErrorf("failed to scan row: %w", err)
+		}
+		rowMap := make(map[string]interface{})
+		for i, colName := range columnNames {
+			// TEXT can be returned as raw bytes, callers expect strings like they get from Postgres.
+			if b, ok := values[i].([]byte); ok {
+				rowMap[colName] = string(b)
+			} else {
+				rowMap[colName] = values[i]
+			}
+		}
+		results = append(results, rowMap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fferr.NewInternalErrorf("row error occurred: %w", err)
+	}
+	return results, nil
+}
+
+func (lite *SQLiteStorageImplementation) Delete(key string) (string, error) {
+	if key == "" {
+		return "", fferr.NewInvalidArgumentError(fmt.Errorf("cannot delete empty key"))
+	}
+	ctx := context.Background()
+	tx, err := lite.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fferr.NewInternalErrorf("failed to delete key %s: %v", key, err)
+	}
+	defer tx.Rollback()
+
+	var value string
+	if err := tx.QueryRowContext(ctx, lite.deleteQuery(), key).Scan(&value); errors.Is(err, sql.ErrNoRows) {
+		return "", fferr.NewKeyNotFoundError(key, nil)
+	} else if err != nil {
+		return "", fferr.NewInternalErrorf("failed to delete key %s: %v", key, err)
+	}
+	if _, err := tx.ExecContext(ctx, lite.deleteSearchQuery(), key); err != nil {
+		return "", fferr.NewInternalErrorf("failed to remove key %s from search index: %v", key, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fferr.NewInternalErrorf("failed to delete key %s: %v", key, err)
+	}
+	return value, nil
+}
+
+func (lite *SQLiteStorageImplementation) Close() {
+	// No-op, the database is owned by whoever opened it.
+}
+
+func (lite *SQLiteStorageImplementation) Type() MetadataStorageType {
+	return SQLiteMetadataStorage
+}
+
+func (lite *SQLiteStorageImplementation) Search(ctx context.Context, q string, opts ...query.Query) (map[string]string, error) {
+	logger := logging.GetLoggerFromContext(ctx)
+	logger.Infow("Initiating SQLite search", "searchquery", q)
+	if len(opts) > 0 {
+		logger.Warnw("Search with options is not supported in SQLite", "options", opts)
+		return nil, fferr.NewInvalidArgumentError(fmt.Errorf("search with options is not supported in SQLite"))
+	}
+	match := sqliteMatchExpression(q)
+	if match == "" {
+		logger.Debugw("Search query has no searchable terms")
+		return map[string]string{}, nil
+	}
+	searchSQL := lite.searchQuery()
+	logger.Debugw("Search query", "query", searchSQL, "match", match)
+	rows, err := lite.Db.QueryContext(ctx, searchSQL, match)
+	if err != nil {
+		logger.Errorw("Failed to execute search query", "query", searchSQL, "error", err)
+		return nil, fferr.NewInternalErrorf("failed to search for %s: %w", q, err)
+	}
+	return scanKeyValues(rows)
+}
+
+func (lite *SQLiteStorageImplementation) indexForSearch(ctx context.Context, tx *sql.Tx, key, value string) error {
+	if _, err := tx.ExecContext(ctx, lite.deleteSearchQuery(), key); err != nil {
+		return err
+	}
+	doc := newSearchDocument(key, value)
+	if doc.Type == "DELETED" {
+		return nil
+	}
+	_, err := tx.ExecContext(
+		ctx, lite.insertSearchQuery(), key, doc.Name, doc.Type, doc.Variant, strings.Join(doc.Tags, " "),
+	)
+	return err
+}
+
+type searchDocument struct {
+	Name    string
+	Type    string
+	Variant string
+	Tags    []string
+}
+
+// newSearchDocument parses the same fields out of a key and value that the
+// Postgres search trigger does. Keys are formatted as TYPE__NAME__VARIANT.
+func newSearchDocument(key, value string) searchDocument {
+	parts := strings.SplitN(key, "__", 3)
+	doc := searchDocument{Type: parts[0]}
+	if len(parts) > 1 {
+		doc.Name = parts[1]
+	}
+	if len(parts) > 2 {
+		doc.Variant = parts[2]
+	}
+	var wrapper struct {
+		Message string
+	}
+	if err := json.Unmarshal([]byte(value), &wrapper); err != nil || wrapper.Message == "" {
+		return doc
+	}
+	var msg struct {
+		Tags struct {
+			Tag []string `json:"tag"`
+		} `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(wrapper.Message), &msg); err == nil {
+		doc.Tags = msg.Tags.Tag
+	}
+	return doc
+}
+
+// sqliteMatchExpression turns free text into an FTS MATCH expression where every word
+// must prefix match. Splitting on anything that isn't a letter or digit mirrors FTS'
+// default tokenizer, and lowercasing keeps words like OR and NOT from being parsed
+// as query operators.
+func sqliteMatchExpression(q string) string {
+	words := strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = strings.ToLower(word) + "*"
+	}
+	return strings.Join(terms, " ")
+}
+
+func scanKeyValues(rows *sql.Rows) (map[string]string, error) {
+	defer rows.Close()
+	result := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fferr.NewInternalErrorf("failed to scan key-value pair: %w", err)
+		}
+		result[key] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fferr.NewInternalErrorf("row error occurred: %w", err)
+	}
+	return result, nil
+}
+
+// SQL Queries
+func (lite *SQLiteStorageImplementation) setQuery() string {
+	return fmt.Sprintf("INSERT INTO %s (key, value) VALUES (?1, ?2) ON CONFLICT (key) DO UPDATE SET value = excluded.value", sqlite.Sanitize(lite.tableName))
+}
+
+func (lite *SQLiteStorageImplementation) deleteQuery() string {
+	return fmt.Sprintf("DELETE FROM %s WHERE key = ?1 RETURNING value", sqlite.Sanitize(lite.tableName))
+}
+
+func (lite *SQLiteStorageImplementation) insertSearchQuery() string {
+	return fmt.Sprintf("INSERT INTO %s (id, name, type, variant, tags) VALUES (?1, ?2, ?3, ?4, ?5)", sqliteSearchTable)
+}
+
+func (lite *SQLiteStorageImplementation) deleteSearchQuery() string {
+	return fmt.Sprintf("DELETE FROM %s WHERE id = ?1", sqliteSearchTable)
+}
+
+func (lite *SQLiteStorageImplementation) searchQuery() string {
+	return fmt.Sprintf(
+		"SELECT t.key, t.value FROM %[1]s JOIN %[2]s t ON t.key = %[1]s.id WHERE %[1]s MATCH ?1",
+		sqliteSearchTable, sqlite.Sanitize(lite.tableName),
+	)
+}
@@ -0,0 +1,209 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2024 FeatureForm Inc.
+//
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/featureform/db"
+	"github.com/featureform/fferr"
+	"github.com/featureform/helpers/sqlite"
+	"github.com/featureform/logging"
+	"github.com/featureform/storage/query"
+)
+
+func createSQLiteTestDB(ctx context.Context, t *testing.T, path string) *sqlite.DB {
+	t.Helper()
+	liteDB, err := sqlite.NewDB(ctx, sqlite.Config{Path: path})
+	if err != nil {
+		t.Fatalf("Failed to open sqlite database: %v", err)
+	}
+	t.Cleanup(func() { liteDB.Close() })
+	if err := db.RunSQLiteMigrations(ctx, liteDB); err != nil {
+		t.Fatalf("Failed to run sqlite migrations: %v", err)
+	}
+	return liteDB
+}
+
+func TestSQLiteMetadataStorage(t *testing.T) {
+	ctx := logging.NewTestContext(t)
+	liteDB := createSQLiteTestDB(ctx, t, filepath.Join(t.TempDir(), "featureform.db"))
+	liteStorage, err := NewSQLiteStorageImplementation(ctx, liteDB, "ff_task_metadata")
+	if err != nil {
+		t.Fatalf("Failed to create SQLite storage: %v", err)
+	}
+
+	test := MetadataStorageTest{
+		t:       t,
+		storage: liteStorage,
+	}
+	test.Run()
+}
+
+func TestSQLiteMetadataStoragePersists(t *testing.T) {
+	ctx := logging.NewTestContext(t)
+	path := filepath.Join(t.TempDir(), "featureform.db")
+	first := createSQLiteTestDB(ctx, t, path)
+	firstStorage, err := NewSQLiteStorageImplementation(ctx, first, "ff_task_metadata")
+	if err != nil {
+		t.Fatalf("Failed to create SQLite storage: %v", err)
+	}
+	if err := firstStorage.Set(ctx, "FEATURE__avg_txn__v1", "value"); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	first.Close()
+
+	// Migrations must be safe to re-run against an existing file.
+	second := createSQLiteTestDB(ctx, t, path)
+	secondStorage, err := NewSQLiteStorageImplementation(ctx, second, "ff_task_metadata")
+	if err != nil {
+		t.Fatalf("Failed to create SQLite storage: %v", err)
+	}
+	value, err := secondStorage.Get("FEATURE__avg_txn__v1")
+	if err != nil {
+		t.Fatalf("Failed to get key after reopening: %v", err)
+	}
+	if value != "value" {
+		t.Fatalf("Expected value to be persisted, got %s", value)
+	}
+	var keyNotFoundErr *fferr.KeyNotFoundError
+	if _, err := secondStorage.Get("FEATURE__missing__v1"); !errors.As(err, &keyNotFoundErr) {
+		t.Fatalf("Expected key not found error, got %v", err)
+	}
+}
+
+func TestSQLiteMetadataStorageSearch(t *testing.T) {
+	ctx := logging.NewTestContext(t)
+	liteDB := createSQLiteTestDB(ctx, t, filepath.Join(t.TempDir(), "featureform.db"))
+	liteStorage, err := NewSQLiteStorageImplementation(ctx, liteDB, "ff_task_metadata")
+	if err != nil {
+		t.Fatalf("Failed to create SQLite storage: %v", err)
+	}
+	values := map[string]string{
+		"FEATURE_VARIANT__avg_transactions__v1": `{"Message": "{\"tags\": {\"tag\": [\"fraud\"]}}"}`,
+		"FEATURE_VARIANT__user_age__v1":         `{"Message": "{}"}`,
+		"DELETED__avg_transactions__v0":         `{"Message": "{}"}`,
+	}
+	for key, value := range values {
+		if err := liteStorage.Set(ctx, key, value); err != nil {
+			t.Fatalf("Failed to set %s: %v", key, err)
+		}
+	}
+
+	tests := map[string]struct {
+		Query    string
+		Expected []string
+	}{
+		"Name":         {"transactions", []string{"FEATURE_VARIANT__avg_transactions__v1"}},
+		"Name Prefix":  {"avg tran", []string{"FEATURE_VARIANT__avg_transactions__v1"}},
+		"Tag":          {"fraud", []string{"FEATURE_VARIANT__avg_transactions__v1"}},
+		"Variant":      {"v1", []string{"FEATURE_VARIANT__avg_transactions__v1", "FEATURE_VARIANT__user_age__v1"}},
+		"Query Syntax": {`"USER" -age`, []string{"FEATURE_VARIANT__user_age__v1"}},
+		"No Match":     {"missing", nil},
+		"No Terms":     {"!!", nil},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			results, err := liteStorage.Search(ctx, test.Query)
+			if err != nil {
+				t.Fatalf("Failed to search: %v", err)
+			}
+			if len(results) != len(test.Expected) {
+				t.Fatalf("Expected %d results, got %d: %v", len(test.Expected), len(results), results)
+			}
+			for _, key := range test.Expected {
+				if _, has := results[key]; !has {
+					t.Fatalf("Expected %s in results: %v", key, results)
+				}
+			}
+		})
+	}
+
+	if _, err := liteStorage.Delete("FEATURE_VARIANT__user_age__v1"); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	results, err := liteStorage.Search(ctx, "age")
+	if err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("Expected deleted key to be removed from search: %v", results)
+	}
+}
+
+func TestSQLiteMetadataStorageJSONFilters(t *testing.T) {
+	ctx := logging.NewTestContext(t)
+	liteDB := createSQLiteTestDB(ctx, t, filepath.Join(t.TempDir(), "featureform.db"))
+	liteStorage, err := NewSQLiteStorageImplementation(ctx, liteDB, "ff_task_metadata")
+	if err != nil {
+		t.Fatalf("Failed to create SQLite storage: %v", err)
+	}
+	values := map[string]string{
+		"FEATURE__a__v1": `{"taskID": "10", "Message": "{\"tags\": {\"tag\": [\"fraud\"]}, \"columns\": [{\"name\": \"user_id\"}]}"}`,
+		"FEATURE__b__v1": `{"taskID": "9", "Message": "{\"tags\": {\"tag\": [\"churn\"]}, \"columns\": [{\"name\": \"item_id\"}]}"}`,
+	}
+	for key, value := range values {
+		if err := liteStorage.Set(ctx, key, value); err != nil {
+			t.Fatalf("Failed to set %s: %v", key, err)
+		}
+	}
+	message := query.JSONPathStep{Key: "Message", IsJsonString: true}
+	tests := map[string]struct {
+		Opts     []query.Query
+		Expected []string
+	}{
+		"Array Contains": {
+			Opts: []query.Query{query.ArrayContains{
+				Column: query.JSONColumn{Path: []query.JSONPathStep{message, {Key: "tags"}, {Key: "tag"}}, Type: query.String},
+				Values: []any{"fraud"},
+			}},
+			Expected: []string{"FEATURE__a__v1"},
+		},
+		"Object Array Contains": {
+			Opts: []query.Query{query.ObjectArrayContains{
+				Column:      query.JSONColumn{Path: []query.JSONPathStep{message, {Key: "columns"}}, Type: query.Object},
+				SearchField: "name",
+				Values:      []any{"item_id"},
+			}},
+			Expected: []string{"FEATURE__b__v1"},
+		},
+		"Int Sort": {
+			Opts: []query.Query{
+				query.ValueSort{Column: query.JSONColumn{Path: []query.JSONPathStep{{Key: "taskID"}}, Type: query.Int}, Dir: query.Asc},
+				query.Limit{Limit: 1},
+			},
+			Expected: []string{"FEATURE__b__v1"},
+		},
+		"Not Deleted": {
+			Opts: []query.Query{query.ValueEquals{
+				Column: query.SQLColumn{Column: "marked_for_deletion_at"},
+				Value:  nil,
+			}},
+			Expected: []string{"FEATURE__a__v1", "FEATURE__b__v1"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			results, err := liteStorage.List("FEATURE__", test.Opts...)
+			if err != nil {
+				t.Fatalf("Failed to list: %v", err)
+			}
+			if len(results) != len(test.Expected) {
+				t.Fatalf("Expected %d results, got %d: %v", len(test.Expected), len(results), results)
+			}
+			for _, key := range test.Expected {
+				if _, has := results[key]; !has {
+					t.Fatalf("Expected %s in results: %v", key, results)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+// Package tracing sets up OpenTelemetry tracing for Featureform services. Spans are exported
+// through OTLP or to stdout depending on FEATUREFORM_TRACING_EXPORTER, and tracing is a no-op
+// when it isn't set. The standard OTEL_EXPORTER_OTLP_* variables configure the OTLP exporter.
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/featureform/fferr"
+	"github.com/featureform/helpers"
+	"github.com/featureform/logging"
+)
+
+const (
+	// ExporterEnv selects the span exporter: "otlp", "stdout" or "none".
+	ExporterEnv = "FEATUREFORM_TRACING_EXPORTER"
+
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+
+	instrumentationName = "github.com/featureform"
+)
+
+// Attribute keys shared by spans across services.
+const (
+	ProviderTypeKey    = attribute.Key("featureform.provider.type")
+	ResourceNameKey    = attribute.Key("featureform.resource.name")
+	ResourceVariantKey = attribute.Key("featureform.resource.variant")
+	SchedulerIDKey     = attribute.Key("featureform.scheduler_id")
+	TaskIDKey          = attribute.Key("featureform.task_id")
+	RunIDKey           = attribute.Key("featureform.run_id")
+	TargetKey          = attribute.Key("featureform.target")
+)
+
+// Init installs the global tracer provider and propagator for a service. The returned function
+// flushes and stops the exporter and should be called before the service exits.
+func Init(ctx context.Context, service string) (func(context.Context) error, error) {
+	exporterType := strings.ToLower(helpers.GetEnv(ExporterEnv, ExporterNone))
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch exporterType {
+	case ExporterNone, "":
+		otel.SetTextMapPropagator(Propagator())
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = otlptracegrpc.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fferr.NewInvalidArgumentErrorf("unknown %s %q; expected %s, %s or %s", ExporterEnv, exporterType, ExporterOTLP, ExporterStdout, ExporterNone)
+	}
+	if err != nil {
+		return nil, fferr.NewInternalErrorf("failed to create %s trace exporter: %w", exporterType, err)
+	}
+	provider := NewTracerProvider(service, sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(Propagator())
+	return provider.Shutdown, nil
+}
+
+// NewTracerProvider creates a tracer provider whose spans are tagged with the service name.
+// Tests pass a span recorder or in-memory exporter as an option to assert on spans.
+func NewTracerProvider(service string, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	res := resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(service))
+	return sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{sdktrace.WithResource(res)}, opts...)...)
+}
+
+// Propagator is the W3C trace context and baggage propagator used between services.
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// TraceParent returns the W3C traceparent of the span in ctx, or an empty string if ctx isn't
+// traced. It's stored on work that outlives the request so later spans can link back to it.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// LinkTo links span to the span a traceparent from TraceParent refers to. Invalid or empty
+// traceparents are ignored.
+func LinkTo(span trace.Span, traceParent string) {
+	if traceParent == "" {
+		return
+	}
+	carrier := propagation.MapCarrier{"traceparent": traceParent}
+	linked := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+	if linked.IsValid() {
+		span.AddLink(trace.Link{SpanContext: linked})
+	}
+}
+
+// Start starts a span as a child of the span in ctx. The request ID in ctx, if any, is added as
+// an attribute so traces can be found from log lines.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if id, ok := ctx.Value(logging.RequestIDKey).(logging.RequestID); ok && id != "" {
+		attrs = append(attrs, attribute.String(logging.RequestIDSpanAttribute, id.String()))
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,146 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+//
+// Copyright 2025 FeatureForm Inc.
+//
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/featureform/logging"
+)
+
+// useInMemoryExporter installs a tracer provider that records spans in memory for the test.
+func useInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(NewTracerProvider("test", sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+func spanAttribute(span tracetest.SpanStub, key string) (attribute.Value, bool) {
+	for _, attr := range span.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestStartEnd(t *testing.T) {
+	exporter := useInMemoryExporter(t)
+	ctx, _ := logging.NewTestContextAndLogger(t)
+	_, ctx, _ = logging.NewTestLogger(t).InitializeRequestID(ctx)
+	requestID := logging.GetRequestIDFromContext(ctx)
+
+	ctx, parent := Start(ctx, "task_run", TaskIDKey.String("1"))
+	_, child := Start(ctx, "provider.CreateTrainingSet", ProviderTypeKey.String("POSTGRES_OFFLINE"))
+	End(child, errors.New("table not found"))
+	End(parent, nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	childSpan, parentSpan := spans[0], spans[1]
+	if childSpan.Parent.SpanID() != parentSpan.SpanContext.SpanID() {
+		t.Fatalf("Expected %s to be a child of %s", childSpan.Name, parentSpan.Name)
+	}
+	if childSpan.Status.Code != codes.Error || childSpan.Status.Description != "table not found" {
+		t.Fatalf("Expected child span to record the error, got %v", childSpan.Status)
+	}
+	if parentSpan.Status.Code == codes.Error {
+		t.Fatalf("Expected parent span to succeed, got %v", parentSpan.Status)
+	}
+	for _, span := range spans {
+		if value, ok := spanAttribute(span, logging.RequestIDSpanAttribute); !ok || value.AsString() != requestID.String() {
+			t.Fatalf("Expected %s to have request ID %s, got %v", span.Name, requestID, value)
+		}
+	}
+}
+
+func TestRequestIDLinksCurrentSpan(t *testing.T) {
+	exporter := useInMemoryExporter(t)
+	ctx, span := Start(context.Background(), "CreateTrainingSetVariant")
+	logger := logging.NewTestLogger(t)
+	requestID, _, _ := logger.InitializeRequestID(ctx)
+	End(span, nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	if value, ok := spanAttribute(spans[0], logging.RequestIDSpanAttribute); !ok || value.AsString() != requestID.String() {
+		t.Fatalf("Expected span to be linked to request ID %s, got %v", requestID, value)
+	}
+}
+
+func TestLinkToTraceParent(t *testing.T) {
+	exporter := useInMemoryExporter(t)
+	ctx, request := Start(context.Background(), "CreateTrainingSetVariant")
+	traceParent := TraceParent(ctx)
+	End(request, nil)
+	if traceParent == "" {
+		t.Fatalf("Expected a traceparent for a traced context")
+	}
+	if TraceParent(context.Background()) != "" {
+		t.Fatalf("Expected no traceparent for an untraced context")
+	}
+
+	_, run := Start(context.Background(), "task_run")
+	LinkTo(run, traceParent)
+	LinkTo(run, "not-a-traceparent")
+	End(run, nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	requestSpan, runSpan := spans[0], spans[1]
+	if runSpan.SpanContext.TraceID() == requestSpan.SpanContext.TraceID() {
+		t.Fatalf("Expected the run to start its own trace")
+	}
+	if len(runSpan.Links) != 1 || runSpan.Links[0].SpanContext.SpanID() != requestSpan.SpanContext.SpanID() {
+		t.Fatalf("Expected the run to link to the request span, got %v", runSpan.Links)
+	}
+}
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		exporter string
+		wantErr  bool
+	}{
+		{"", false},
+		{ExporterNone, false},
+		{ExporterStdout, false},
+		{"zipkin", true},
+	}
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	for _, tt := range tests {
+		t.Run(tt.exporter, func(t *testing.T) {
+			t.Setenv(ExporterEnv, tt.exporter)
+			shutdown, err := Init(context.Background(), "test")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if err := shutdown(context.Background()); err != nil {
+					t.Fatalf("Failed to shut down tracing: %v", err)
+				}
+			}
+		})
+	}
+}